@@ -12,6 +12,8 @@ import (
 	"strings"
 	"time"
 
+	"powerhour/internal/paths"
+	"powerhour/internal/secrets"
 	"powerhour/pkg/csvplan"
 )
 
@@ -22,7 +24,17 @@ type fetchResult struct {
 	Notes     []string
 }
 
-func (s *Service) fetchURL(ctx context.Context, row csvplan.Row, baseName string, src sourceInfo) (fetchResult, error) {
+// FetchLogPath returns where the yt-dlp log of plan row index in collection
+// is written. The collection is part of the name so the same row number in
+// two collections gets two logs; without one the log is fetch_<index>.log.
+func FetchLogPath(pp paths.ProjectPaths, collection string, index int) string {
+	if name := SanitizeSegment(collection); name != "" {
+		return filepath.Join(pp.LogsDir, fmt.Sprintf("fetch_%s_%03d.log", name, index))
+	}
+	return filepath.Join(pp.LogsDir, fmt.Sprintf("fetch_%03d.log", index))
+}
+
+func (s *Service) fetchURL(ctx context.Context, row csvplan.Row, collection, baseName string, src sourceInfo) (fetchResult, error) {
 	if err := os.MkdirAll(s.Paths.CacheDir, 0o755); err != nil {
 		return fetchResult{}, fmt.Errorf("ensure cache dir: %w", err)
 	}
@@ -30,14 +42,16 @@ func (s *Service) fetchURL(ctx context.Context, row csvplan.Row, baseName string
 		return fetchResult{}, fmt.Errorf("ensure logs dir: %w", err)
 	}
 
-	logPath := filepath.Join(s.Paths.LogsDir, fmt.Sprintf("fetch_%03d.log", row.Index))
+	logPath := FetchLogPath(s.Paths, collection, row.Index)
 	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
 	if err != nil {
 		return fetchResult{}, fmt.Errorf("open fetch log: %w", err)
 	}
 	defer logFile.Close()
 
-	logWriter := s.logWriter(logFile)
+	// The proxy lookup error and yt-dlp's output can echo the expanded
+	// proxy URL, so scrub secrets from everything the log gets.
+	logWriter := secrets.NewRedactingWriter(s.logWriter(logFile))
 
 	writeProxyBanner(ctx, logWriter, s.ytDLPProxy)
 	if s.ytDLPSourceAddr != "" {
@@ -135,7 +149,7 @@ func writeProxyBanner(ctx context.Context, w io.Writer, proxy string) {
 		return
 	}
 
-	fmt.Fprintf(w, "[powerhour] yt-dlp proxy: %s\n", proxy)
+	fmt.Fprintf(w, "[powerhour] yt-dlp proxy: %s\n", secrets.Redact(proxy))
 
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
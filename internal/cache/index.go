@@ -57,6 +57,7 @@ type Entry struct {
 	Channel     string    `json:"channel,omitempty"`
 	UploadDate  string    `json:"upload_date,omitempty"`
 	Description string    `json:"description,omitempty"`
+	License     string    `json:"license,omitempty"`
 	LastUsedAt  time.Time `json:"last_used_at,omitempty"`
 }
 
@@ -68,6 +69,7 @@ type ProbeMetadata struct {
 	Streams         json.RawMessage `json:"streams,omitempty"`
 	FormatRaw       json.RawMessage `json:"format_raw,omitempty"`
 	Raw             json.RawMessage `json:"raw,omitempty"`
+	Crop            *CropSuggestion `json:"crop,omitempty"`
 }
 
 // LoadFromPath reads an index from the given file path, returning an empty
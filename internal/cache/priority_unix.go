@@ -0,0 +1,16 @@
+//go:build !windows
+
+package cache
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// lowerPriority prepares cmd to start at lower priority. Unix can only renice
+// a running process; see applyPriority.
+func lowerPriority(*exec.Cmd, int) {}
+
+func applyPriority(cmd *exec.Cmd, nice int) error {
+	return syscall.Setpriority(syscall.PRIO_PROCESS, cmd.Process.Pid, nice)
+}
@@ -0,0 +1,25 @@
+//go:build windows
+
+package cache
+
+import (
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// lowerPriority starts cmd in the below-normal priority class, or idle for
+// nice 15 and up.
+func lowerPriority(cmd *exec.Cmd, nice int) {
+	class := uint32(windows.BELOW_NORMAL_PRIORITY_CLASS)
+	if nice >= 15 {
+		class = windows.IDLE_PRIORITY_CLASS
+	}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= class
+}
+
+func applyPriority(*exec.Cmd, int) error { return nil }
@@ -6,11 +6,36 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 
 	"powerhour/pkg/csvplan"
 )
 
+// CropSuggestion is the active picture area found by ffmpeg's cropdetect,
+// recorded when a source has baked-in letterbox or pillarbox bars.
+type CropSuggestion struct {
+	Width        int `json:"width"`
+	Height       int `json:"height"`
+	X            int `json:"x"`
+	Y            int `json:"y"`
+	SourceWidth  int `json:"source_width"`
+	SourceHeight int `json:"source_height"`
+}
+
+// Filter returns the ffmpeg crop filter for the suggestion.
+func (c CropSuggestion) Filter() string {
+	return fmt.Sprintf("crop=%d:%d:%d:%d", c.Width, c.Height, c.X, c.Y)
+}
+
+const (
+	// cropDetectWindow is how many seconds of the source cropdetect scans.
+	cropDetectWindow = 20
+	// cropMinBarFraction is the smallest bar (per side pair, as a fraction of
+	// the source dimension) worth cropping; below this it is encoder noise.
+	cropMinBarFraction = 0.02
+)
+
 type ffprobeOutput struct {
 	Format  ffprobeFormat   `json:"format"`
 	Streams json.RawMessage `json:"streams"`
@@ -78,10 +103,157 @@ func (s *Service) probe(ctx context.Context, row csvplan.Row, target string) (*P
 		FormatRaw:       cloneRaw(json.RawMessage(formatRaw)),
 		Raw:             cloneRaw(raw),
 	}
+	meta.Crop = s.detectCrop(ctx, target, parsed.Streams, durationSeconds, logFile)
 
 	return meta, nil
 }
 
+// detectCrop runs cropdetect over a window from the middle of the source and
+// returns a suggestion when it finds bars. The pass decodes cropDetectWindow
+// seconds of video, so it is skipped unless video.auto_crop is on. Detection
+// is best effort: any failure just means no suggestion.
+func (s *Service) detectCrop(ctx context.Context, target string, streams json.RawMessage, duration float64, log *os.File) *CropSuggestion {
+	if !s.autoCrop || s.ffmpeg == "" {
+		return nil
+	}
+	width, height := videoDimensions(streams)
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+
+	start := 0.0
+	if duration > cropDetectWindow*2 {
+		start = duration/2 - cropDetectWindow/2
+	}
+	args := []string{
+		"-hide_banner", "-nostats",
+		"-ss", strconv.FormatFloat(start, 'f', 3, 64),
+		"-i", target,
+		"-t", strconv.Itoa(cropDetectWindow),
+		"-vf", "cropdetect=limit=24:round=2:reset=0",
+		"-an", "-sn",
+		"-f", "null", "-",
+	}
+	s.logf("cropdetect target=%s start=%.1f", target, start)
+	result, err := s.Runner.Run(ctx, s.ffmpeg, args, RunOptions{Stderr: log})
+	if err != nil {
+		s.logf("cropdetect failed: %v", err)
+		return nil
+	}
+	return suggestCrop(string(result.Stderr), width, height)
+}
+
+var cropdetectPattern = regexp.MustCompile(`crop=(\d+):(\d+):(\d+):(\d+)`)
+
+// suggestCrop turns cropdetect output into a suggestion. With reset=0 the last
+// reported box covers every frame scanned, so it is the one to trust.
+func suggestCrop(output string, sourceWidth, sourceHeight int) *CropSuggestion {
+	matches := cropdetectPattern.FindAllStringSubmatch(output, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+	last := matches[len(matches)-1]
+	var v [4]int
+	for i := range v {
+		n, err := strconv.Atoi(last[i+1])
+		if err != nil {
+			return nil
+		}
+		v[i] = n
+	}
+	c := CropSuggestion{Width: v[0], Height: v[1], X: v[2], Y: v[3], SourceWidth: sourceWidth, SourceHeight: sourceHeight}
+	if c.Width <= 0 || c.Height <= 0 || c.X+c.Width > sourceWidth || c.Y+c.Height > sourceHeight {
+		return nil
+	}
+	// An all-black window shrinks to a sliver; don't trust it.
+	if c.Width < sourceWidth/3 || c.Height < sourceHeight/3 {
+		return nil
+	}
+	barX := float64(sourceWidth-c.Width) / float64(sourceWidth)
+	barY := float64(sourceHeight-c.Height) / float64(sourceHeight)
+	if barX < cropMinBarFraction && barY < cropMinBarFraction {
+		return nil
+	}
+	return &c
+}
+
+// ColorInfo is the color metadata ffprobe reports for a source's first video
+// stream, using ffmpeg's names (bt709, smpte170m, smpte2084, tv, pc, …).
+// Empty fields weren't tagged.
+type ColorInfo struct {
+	Space     string `json:"color_space,omitempty"`
+	Transfer  string `json:"color_transfer,omitempty"`
+	Primaries string `json:"color_primaries,omitempty"`
+	Range     string `json:"color_range,omitempty"`
+}
+
+// HDR reports whether the transfer is PQ (HDR10, Dolby Vision) or HLG.
+func (c ColorInfo) HDR() bool {
+	return c.Transfer == "smpte2084" || c.Transfer == "arib-std-b67"
+}
+
+// Color returns the color metadata of the first video stream, read from the
+// stored ffprobe streams, so sources probed before it existed have it too.
+func (p ProbeMetadata) Color() ColorInfo {
+	var parsed []struct {
+		CodecType string `json:"codec_type"`
+		ColorInfo
+	}
+	if err := json.Unmarshal(p.Streams, &parsed); err != nil {
+		return ColorInfo{}
+	}
+	for _, st := range parsed {
+		if st.CodecType == "video" {
+			info := st.ColorInfo
+			for _, v := range []*string{&info.Space, &info.Transfer, &info.Primaries, &info.Range} {
+				if *v == "unknown" || *v == "unspecified" || *v == "reserved" {
+					*v = ""
+				}
+			}
+			return info
+		}
+	}
+	return ColorInfo{}
+}
+
+// AudioLayout returns the channel count and ffmpeg channel layout ("stereo",
+// "5.1(side)", …) of the first audio stream, or zero and "" when there is
+// none. The layout is empty when ffprobe didn't report one.
+func (p ProbeMetadata) AudioLayout() (int, string) {
+	var parsed []struct {
+		CodecType     string `json:"codec_type"`
+		Channels      int    `json:"channels"`
+		ChannelLayout string `json:"channel_layout"`
+	}
+	if err := json.Unmarshal(p.Streams, &parsed); err != nil {
+		return 0, ""
+	}
+	for _, st := range parsed {
+		if st.CodecType == "audio" {
+			return st.Channels, st.ChannelLayout
+		}
+	}
+	return 0, ""
+}
+
+// videoDimensions returns the coded size of the first video stream.
+func videoDimensions(streams json.RawMessage) (int, int) {
+	var parsed []struct {
+		CodecType string `json:"codec_type"`
+		Width     int    `json:"width"`
+		Height    int    `json:"height"`
+	}
+	if err := json.Unmarshal(streams, &parsed); err != nil {
+		return 0, 0
+	}
+	for _, st := range parsed {
+		if st.CodecType == "video" {
+			return st.Width, st.Height
+		}
+	}
+	return 0, 0
+}
+
 func cloneRaw(raw json.RawMessage) json.RawMessage {
 	if raw == nil {
 		return nil
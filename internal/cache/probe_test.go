@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"path/filepath"
+	"testing"
+)
+
+// cropRunner answers ffprobe with a 1920x1080 stream and ffmpeg with a
+// letterboxed cropdetect result, counting the cropdetect passes.
+type cropRunner struct {
+	cropdetectCalls int
+}
+
+func (r *cropRunner) Run(_ context.Context, command string, _ []string, _ RunOptions) (RunResult, error) {
+	if filepath.Base(command) == "ffmpeg" {
+		r.cropdetectCalls++
+		return RunResult{Stderr: []byte("[Parsed_cropdetect_0 @ 0x1] crop=1920:800:0:140\n")}, nil
+	}
+	out := `{"format":{"duration":"200.0"},"streams":[{"codec_type":"video","width":1920,"height":1080}]}`
+	return RunResult{Stdout: []byte(out)}, nil
+}
+
+func TestProbeRunsCropdetectOnlyWithAutoCrop(t *testing.T) {
+	for _, autoCrop := range []bool{false, true} {
+		runner := &cropRunner{}
+		svc := &Service{
+			Paths:    testPaths(t),
+			Logger:   log.New(io.Discard, "", 0),
+			Runner:   runner,
+			ffprobe:  "ffprobe",
+			ffmpeg:   "ffmpeg",
+			autoCrop: autoCrop,
+		}
+		meta, err := svc.ProbeFile(context.Background(), filepath.Join(t.TempDir(), "a.mp4"))
+		if err != nil {
+			t.Fatalf("probe: %v", err)
+		}
+		want := 0
+		if autoCrop {
+			want = 1
+		}
+		if runner.cropdetectCalls != want {
+			t.Errorf("auto_crop=%v: %d cropdetect passes, want %d", autoCrop, runner.cropdetectCalls, want)
+		}
+		if (meta.Crop != nil) != autoCrop {
+			t.Errorf("auto_crop=%v: crop = %+v", autoCrop, meta.Crop)
+		}
+	}
+}
+
+func TestSuggestCrop(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   *CropSuggestion
+	}{
+		{
+			name: "letterboxed uses last box",
+			output: "[Parsed_cropdetect_0 @ 0x1] x1:0 x2:1919 y1:150 y2:929 w:1920 h:784 x:0 y:148 pts:1 t:0.03 crop=1920:784:0:148\n" +
+				"[Parsed_cropdetect_0 @ 0x1] x1:0 x2:1919 y1:140 y2:939 w:1920 h:800 x:0 y:140 pts:2 t:0.07 crop=1920:800:0:140\n",
+			want: &CropSuggestion{Width: 1920, Height: 800, X: 0, Y: 140, SourceWidth: 1920, SourceHeight: 1080},
+		},
+		{
+			name:   "pillarboxed",
+			output: "crop=1440:1080:240:0\n",
+			want:   &CropSuggestion{Width: 1440, Height: 1080, X: 240, Y: 0, SourceWidth: 1920, SourceHeight: 1080},
+		},
+		{name: "full frame", output: "crop=1920:1080:0:0\n"},
+		{name: "negligible bars", output: "crop=1920:1072:0:4\n"},
+		{name: "all black", output: "crop=1920:16:0:532\n"},
+		{name: "no output", output: "frame=  600 fps=0.0\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := suggestCrop(tt.output, 1920, 1080)
+			if (got == nil) != (tt.want == nil) {
+				t.Fatalf("suggestCrop = %+v, want %+v", got, tt.want)
+			}
+			if got != nil && *got != *tt.want {
+				t.Fatalf("suggestCrop = %+v, want %+v", *got, *tt.want)
+			}
+		})
+	}
+}
+
+func TestVideoDimensions(t *testing.T) {
+	streams := json.RawMessage(`[{"codec_type":"audio"},{"codec_type":"video","width":1280,"height":720}]`)
+	if w, h := videoDimensions(streams); w != 1280 || h != 720 {
+		t.Fatalf("videoDimensions = %dx%d, want 1280x720", w, h)
+	}
+	if w, h := videoDimensions(nil); w != 0 || h != 0 {
+		t.Fatalf("videoDimensions(nil) = %dx%d", w, h)
+	}
+}
+
+func TestProbeColor(t *testing.T) {
+	probe := ProbeMetadata{Streams: json.RawMessage(`[
+		{"codec_type":"audio"},
+		{"codec_type":"video","color_space":"smpte170m","color_transfer":"unknown","color_primaries":"smpte170m","color_range":"tv"}
+	]`)}
+	want := ColorInfo{Space: "smpte170m", Primaries: "smpte170m", Range: "tv"}
+	if got := probe.Color(); got != want {
+		t.Fatalf("Color = %+v, want %+v", got, want)
+	}
+	if got := (ProbeMetadata{}).Color(); got != (ColorInfo{}) {
+		t.Fatalf("Color of empty probe = %+v", got)
+	}
+	if !(ColorInfo{Transfer: "arib-std-b67"}).HDR() || (ColorInfo{Transfer: "bt709"}).HDR() {
+		t.Fatal("HDR should be true for HLG only")
+	}
+}
+
+func TestProbeAudioLayout(t *testing.T) {
+	probe := ProbeMetadata{Streams: json.RawMessage(`[
+		{"codec_type":"video"},
+		{"codec_type":"audio","channels":6,"channel_layout":"5.1(side)"}
+	]`)}
+	if channels, layout := probe.AudioLayout(); channels != 6 || layout != "5.1(side)" {
+		t.Fatalf("AudioLayout = %d %q, want 6 5.1(side)", channels, layout)
+	}
+	if channels, layout := (ProbeMetadata{}).AudioLayout(); channels != 0 || layout != "" {
+		t.Fatalf("AudioLayout without streams = %d %q", channels, layout)
+	}
+}
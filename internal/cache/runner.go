@@ -3,9 +3,16 @@ package cache
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"powerhour/internal/telemetry"
 )
 
 type RunOptions struct {
@@ -13,6 +20,7 @@ type RunOptions struct {
 	Env    []string
 	Stdout io.Writer
 	Stderr io.Writer
+	Nice   int // 1-19 runs the command at lower CPU priority
 }
 
 type RunResult struct {
@@ -26,7 +34,19 @@ type Runner interface {
 
 type CmdRunner struct{}
 
-func (CmdRunner) Run(ctx context.Context, command string, args []string, opts RunOptions) (RunResult, error) {
+// Run runs command in its own telemetry span, "exec <tool>", so yt-dlp,
+// ffprobe and ffmpeg time shows up under the fetch or render that ran it.
+func (CmdRunner) Run(ctx context.Context, command string, args []string, opts RunOptions) (result RunResult, err error) {
+	tool := strings.TrimSuffix(filepath.Base(command), ".exe")
+	ctx, span := telemetry.Start(ctx, "exec "+tool, attribute.String("process.executable.name", tool))
+	defer func() {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			span.SetAttributes(attribute.Int("process.exit.code", exitErr.ExitCode()))
+		}
+		span.End(err)
+	}()
+
 	cmd := exec.CommandContext(ctx, command, args...)
 	if opts.Dir != "" {
 		cmd.Dir = opts.Dir
@@ -48,8 +68,18 @@ func (CmdRunner) Run(ctx context.Context, command string, args []string, opts Ru
 
 	cmd.Stdout = stdoutWriter
 	cmd.Stderr = stderrWriter
+	if opts.Nice > 0 {
+		lowerPriority(cmd, opts.Nice)
+	}
 
-	err := cmd.Run()
+	if err := cmd.Start(); err != nil {
+		return RunResult{}, err
+	}
+	if opts.Nice > 0 {
+		// Best effort: the command still runs if its priority can't change.
+		_ = applyPriority(cmd, opts.Nice)
+	}
+	err = cmd.Wait()
 	return RunResult{Stdout: stdoutBuf.Bytes(), Stderr: stderrBuf.Bytes()}, err
 }
 
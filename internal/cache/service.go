@@ -14,10 +14,15 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"powerhour/internal/config"
 	"powerhour/internal/paths"
+	"powerhour/internal/secrets"
+	"powerhour/internal/telemetry"
 	"powerhour/internal/tools"
 	"powerhour/pkg/csvplan"
 )
@@ -43,17 +48,27 @@ type Service struct {
 	Runner           Runner
 	ytDLP            string
 	ffprobe          string
+	ffmpeg           string
 	CookiesPath      string
 	ytDLPProxy       string
 	ytDLPSourceAddr  string
 	logOutput        io.Writer
 	filenameTemplate string
+	// autoCrop runs cropdetect when probing; only video.auto_crop uses it.
+	autoCrop bool
+
+	// remoteInfo memoizes yt-dlp metadata per link for the life of the
+	// service, so a preflight query isn't repeated by Resolve.
+	remoteMu   sync.Mutex
+	remoteInfo map[string]remoteIDInfo
 }
 
 type ResolveOptions struct {
 	Force      bool
 	Reprobe    bool
 	NoDownload bool
+	// Collection names the row's collection in its fetch log path.
+	Collection string
 }
 
 type ResolveStatus string
@@ -91,6 +106,7 @@ type sourceInfo struct {
 	Channel     string
 	UploadDate  string
 	Description string
+	License     string
 }
 
 // LocalSourceMissingError is returned when a local file reference doesn't exist.
@@ -115,6 +131,21 @@ func NewService(ctx context.Context, pp paths.ProjectPaths, logger Logger, runne
 	return NewServiceWithStatus(ctx, pp, logger, runner, nil)
 }
 
+// ResolveYTDLPProxy returns the effective yt-dlp proxy: the project
+// tools.yt-dlp.proxy override, falling back to the global downloads.proxy,
+// with any ${NAME} secret references expanded.
+func ResolveYTDLPProxy(cfg config.Config) (string, error) {
+	proxy := cfg.ToolProxy("yt-dlp")
+	if proxy == "" {
+		proxy = tools.LoadGlobalConfig().Downloads.Proxy
+	}
+	proxy, err := secrets.Expand(proxy)
+	if err != nil {
+		return "", fmt.Errorf("resolve yt-dlp proxy: %w", err)
+	}
+	return proxy, nil
+}
+
 // NewServiceWithStatus is like NewService but accepts a StatusFunc callback
 // to report per-tool progress during tool detection and installation.
 func NewServiceWithStatus(ctx context.Context, pp paths.ProjectPaths, logger Logger, runner Runner, statusFn tools.StatusFunc) (*Service, error) {
@@ -148,9 +179,9 @@ func NewServiceWithStatus(ctx context.Context, pp paths.ProjectPaths, logger Log
 		logger.Printf("using cookies file: %s", cookiesPath)
 	}
 	globalCfg := tools.LoadGlobalConfig()
-	ytProxy := cfg.ToolProxy("yt-dlp")
-	if ytProxy == "" {
-		ytProxy = globalCfg.Downloads.Proxy
+	ytProxy, err := ResolveYTDLPProxy(cfg)
+	if err != nil {
+		return nil, err
 	}
 	ytSourceAddr := cfg.ToolSourceAddress("yt-dlp")
 	if ytSourceAddr == "" {
@@ -180,10 +211,12 @@ func NewServiceWithStatus(ctx context.Context, pp paths.ProjectPaths, logger Log
 		Runner:           runner,
 		ytDLP:            ytPath,
 		ffprobe:          ffprobePath,
+		ffmpeg:           firstNonEmpty(ffStatus.Paths["ffmpeg"], ffStatus.Path),
 		CookiesPath:      cookiesPath,
 		ytDLPProxy:       ytProxy,
 		ytDLPSourceAddr:  ytSourceAddr,
 		filenameTemplate: cfg.DownloadFilenameTemplate(),
+		autoCrop:         cfg.Video.AutoCrop,
 	}
 	return svc, nil
 }
@@ -217,16 +250,33 @@ func (s *Service) templateString() string {
 	return strings.TrimSpace(s.filenameTemplate)
 }
 
+// Resolve finds row's source in the cache, or downloads it, and probes it,
+// in a "resolve source" telemetry span.
 func (s *Service) Resolve(ctx context.Context, idx *Index, row csvplan.Row, opts ResolveOptions) (ResolveResult, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, span := telemetry.Start(ctx, "resolve source",
+		attribute.Int("powerhour.row.index", row.Index),
+		attribute.Bool("powerhour.resolve.force", opts.Force),
+	)
+	result, err := s.resolve(ctx, idx, row, opts)
+	span.SetAttributes(
+		attribute.String("powerhour.resolve.status", string(result.Status)),
+		attribute.String("powerhour.source.type", string(result.Entry.SourceType)),
+		attribute.Bool("powerhour.resolve.probed", result.Probed),
+	)
+	span.End(err)
+	return result, err
+}
+
+func (s *Service) resolve(ctx context.Context, idx *Index, row csvplan.Row, opts ResolveOptions) (ResolveResult, error) {
 	if s == nil {
 		return ResolveResult{}, errors.New("cache service is nil")
 	}
 	if idx == nil {
 		return ResolveResult{}, errors.New("cache index is nil")
 	}
-	if ctx == nil {
-		ctx = context.Background()
-	}
 
 	src, err := s.resolveSource(ctx, idx, row, opts.Force)
 	if err != nil {
@@ -289,6 +339,9 @@ func (s *Service) Resolve(ctx context.Context, idx *Index, row csvplan.Row, opts
 		if src.Description != "" {
 			entry.Description = src.Description
 		}
+		if src.License != "" {
+			entry.License = src.License
+		}
 		normalized := NormalizeMetadata(LoadNormalizationConfig(), NormalizationInput{
 			Title:    entry.Title,
 			Artist:   src.Artist,
@@ -399,7 +452,7 @@ func (s *Service) Resolve(ctx context.Context, idx *Index, row csvplan.Row, opts
 	}
 
 	if !cached {
-		fetchRes, fetchErr := s.fetchURL(ctx, row, names.Remote, src)
+		fetchRes, fetchErr := s.fetchURL(ctx, row, opts.Collection, names.Remote, src)
 		if fetchErr != nil {
 			return ResolveResult{}, fetchErr
 		}
@@ -584,6 +637,7 @@ func (s *Service) resolveRemoteSource(ctx context.Context, idx *Index, link stri
 		Channel:     info.Channel,
 		UploadDate:  info.UploadDate,
 		Description: info.Description,
+		License:     info.License,
 	}, nil
 }
 
@@ -598,12 +652,37 @@ type remoteIDInfo struct {
 	Channel     string
 	UploadDate  string
 	Description string
+	License     string
+	// SizeBytes is yt-dlp's expected download size for the selected
+	// format(s), exact or approximate; 0 when unknown.
+	SizeBytes int64
 }
 
 func (s *Service) queryRemoteID(ctx context.Context, link string) (remoteIDInfo, error) {
 	if s == nil {
 		return remoteIDInfo{}, errors.New("cache service is nil")
 	}
+	s.remoteMu.Lock()
+	info, ok := s.remoteInfo[link]
+	s.remoteMu.Unlock()
+	if ok {
+		return info, nil
+	}
+
+	info, err := s.probeRemoteID(ctx, link)
+	if err != nil {
+		return remoteIDInfo{}, err
+	}
+	s.remoteMu.Lock()
+	if s.remoteInfo == nil {
+		s.remoteInfo = make(map[string]remoteIDInfo)
+	}
+	s.remoteInfo[link] = info
+	s.remoteMu.Unlock()
+	return info, nil
+}
+
+func (s *Service) probeRemoteID(ctx context.Context, link string) (remoteIDInfo, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -650,6 +729,15 @@ func (s *Service) queryRemoteID(ctx context.Context, link string) (remoteIDInfo,
 		Channel      string `json:"channel"`
 		UploadDate   string `json:"upload_date"`
 		Description  string `json:"description"`
+		License      string `json:"license"`
+		// yt-dlp reports sizes as numbers or null, and approximate sizes
+		// may be fractional.
+		Filesize         float64 `json:"filesize"`
+		FilesizeApprox   float64 `json:"filesize_approx"`
+		RequestedFormats []struct {
+			Filesize       float64 `json:"filesize"`
+			FilesizeApprox float64 `json:"filesize_approx"`
+		} `json:"requested_formats"`
 	}
 
 	decoder := json.NewDecoder(bytes.NewReader(raw))
@@ -677,6 +765,13 @@ func (s *Service) queryRemoteID(ctx context.Context, link string) (remoteIDInfo,
 		desc = desc[:500]
 	}
 
+	size := firstPositive(payload.Filesize, payload.FilesizeApprox)
+	if size == 0 {
+		for _, f := range payload.RequestedFormats {
+			size += firstPositive(f.Filesize, f.FilesizeApprox)
+		}
+	}
+
 	return remoteIDInfo{
 		ID:          strings.TrimSpace(payload.ID),
 		Extractor:   extractor,
@@ -688,9 +783,20 @@ func (s *Service) queryRemoteID(ctx context.Context, link string) (remoteIDInfo,
 		Channel:     strings.TrimSpace(payload.Channel),
 		UploadDate:  strings.TrimSpace(payload.UploadDate),
 		Description: desc,
+		License:     strings.TrimSpace(payload.License),
+		SizeBytes:   int64(size),
 	}, nil
 }
 
+func firstPositive(values ...float64) float64 {
+	for _, v := range values {
+		if v > 0 {
+			return v
+		}
+	}
+	return 0
+}
+
 func CanonicalRemoteIdentifier(link, extractor, id string) string {
 	id = strings.TrimSpace(id)
 	extractor = strings.TrimSpace(extractor)
@@ -793,6 +899,9 @@ func hasMetadataChanged(existing, updated Entry) bool {
 	if strings.TrimSpace(existing.Album) != strings.TrimSpace(updated.Album) {
 		return true
 	}
+	if strings.TrimSpace(existing.License) != strings.TrimSpace(updated.License) {
+		return true
+	}
 	if len(existing.Links) != len(updated.Links) {
 		return true
 	}
@@ -1097,14 +1206,18 @@ func cleanupFilename(value string) string {
 	if value == "" {
 		return ""
 	}
-	value = strings.ReplaceAll(value, "/", "_")
-	value = strings.ReplaceAll(value, "\\", "_")
-	value = strings.ReplaceAll(value, ":", "_")
+	// Separators and the characters Windows rejects in file names.
+	value = strings.Map(func(r rune) rune {
+		if r < 0x20 || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, value)
 	for strings.Contains(value, "__") {
 		value = strings.ReplaceAll(value, "__", "_")
 	}
 	value = strings.Trim(value, " .-")
-	return value
+	return paths.PortableName(value)
 }
 
 func SanitizeSegment(value string) string {
@@ -558,3 +558,56 @@ func containsFlagArg(args []string, flag, value string) bool {
 	}
 	return false
 }
+
+type countingIDRunner struct {
+	output string
+	calls  int
+}
+
+func (c *countingIDRunner) Run(_ context.Context, _ string, _ []string, _ RunOptions) (RunResult, error) {
+	c.calls++
+	return RunResult{Stdout: []byte(c.output)}, nil
+}
+
+func TestQueryRemoteIDSizeAndMemo(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   int64
+	}{
+		{"filesize", `{"id":"a","extractor_key":"youtube","filesize":1000,"filesize_approx":2000}`, 1000},
+		{"approx", `{"id":"a","extractor_key":"youtube","filesize":null,"filesize_approx":2500.7}`, 2500},
+		{"requested formats", `{"id":"a","extractor_key":"youtube","requested_formats":[{"filesize":700},{"filesize_approx":300}]}`, 1000},
+		{"unknown", `{"id":"a","extractor_key":"youtube"}`, 0},
+	}
+	for _, tt := range tests {
+		runner := &countingIDRunner{output: tt.output}
+		svc := &Service{Paths: testPaths(t), Logger: log.New(io.Discard, "", 0), Runner: runner, ytDLP: "yt-dlp"}
+		for range 2 {
+			info, err := svc.QueryRemoteID(context.Background(), "https://example.com/v")
+			if err != nil {
+				t.Fatalf("%s: query: %v", tt.name, err)
+			}
+			if info.SizeBytes != tt.want {
+				t.Errorf("%s: SizeBytes = %d, want %d", tt.name, info.SizeBytes, tt.want)
+			}
+		}
+		if runner.calls != 1 {
+			t.Errorf("%s: yt-dlp ran %d times, want 1 (memoized)", tt.name, runner.calls)
+		}
+	}
+}
+
+func TestCleanupFilenamePortable(t *testing.T) {
+	tests := map[string]string{
+		`AC/DC: "Live" <1991>?`: "AC_DC_ _Live_ _1991_",
+		`a\b|c*d`:               "a_b_c_d",
+		"aux":                   "aux_",
+		"  song.  ":             "song",
+	}
+	for in, want := range tests {
+		if got := cleanupFilename(in); got != want {
+			t.Errorf("cleanupFilename(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// Package cast plays media on a TV over the local network: DLNA media
+// renderers through UPnP AVTransport, and Chromecasts through the Cast v2
+// protocol. The module has no dependency for either, so this package
+// carries the small part of each that playback needs: discovery, loading a
+// URL, play/pause/stop and reading the player state.
+package cast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kind names a casting protocol.
+type Kind string
+
+const (
+	KindDLNA       Kind = "dlna"
+	KindChromecast Kind = "chromecast"
+)
+
+// Device is a renderer found on the network.
+type Device struct {
+	Kind  Kind
+	Name  string
+	Model string
+	// Host is the device's address, used to pick the local interface
+	// media is served from.
+	Host string
+
+	// Chromecast: the Cast v2 port on Host.
+	port int
+	// DLNA: the AVTransport control URL and service type.
+	controlURL  string
+	serviceType string
+}
+
+// State is a renderer's player state.
+type State string
+
+const (
+	StateIdle    State = "idle" // nothing loaded, or the media finished
+	StateLoading State = "loading"
+	StatePlaying State = "playing"
+	StatePaused  State = "paused"
+)
+
+// Status is a renderer's player state and position in the current media.
+type Status struct {
+	State    State
+	Position float64 // seconds; 0 when the renderer doesn't say
+}
+
+// Media is one item to play.
+type Media struct {
+	URL         string
+	Title       string
+	Artist      string
+	ContentType string
+	Seconds     float64 // 0 when unknown
+}
+
+// Renderer controls playback on a connected device.
+type Renderer interface {
+	// Load replaces the current media and starts playing it.
+	Load(ctx context.Context, m Media) error
+	Play(ctx context.Context) error
+	Pause(ctx context.Context) error
+	Stop(ctx context.Context) error
+	Status(ctx context.Context) (Status, error)
+	Close() error
+}
+
+// Discover looks for DLNA renderers and Chromecasts for up to timeout and
+// returns them sorted by name. It fails only when both searches fail.
+func Discover(ctx context.Context, timeout time.Duration) ([]Device, error) {
+	var (
+		wg      sync.WaitGroup
+		dlna    []Device
+		cc      []Device
+		dlnaErr error
+		ccErr   error
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		dlna, dlnaErr = DiscoverDLNA(ctx, timeout)
+	}()
+	go func() {
+		defer wg.Done()
+		cc, ccErr = DiscoverChromecasts(ctx, timeout)
+	}()
+	wg.Wait()
+	if dlnaErr != nil && ccErr != nil {
+		return nil, errors.Join(dlnaErr, ccErr)
+	}
+
+	devices := append(dlna, cc...)
+	sort.SliceStable(devices, func(i, j int) bool {
+		return strings.ToLower(devices[i].Name) < strings.ToLower(devices[j].Name)
+	})
+	return devices, nil
+}
+
+// Connect opens a Renderer for d.
+func Connect(ctx context.Context, d Device) (Renderer, error) {
+	switch d.Kind {
+	case KindDLNA:
+		return newDLNARenderer(d), nil
+	case KindChromecast:
+		c, err := dialChromecast(ctx, d)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+	return nil, fmt.Errorf("unknown device kind %q", d.Kind)
+}
+
+// PickDevice returns the device whose name matches query: an exact match
+// (ignoring case) first, then the only one containing it. An empty query
+// picks the only device.
+func PickDevice(devices []Device, query string) (Device, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		switch len(devices) {
+		case 0:
+			return Device{}, errors.New("no renderers found")
+		case 1:
+			return devices[0], nil
+		}
+		return Device{}, fmt.Errorf("found %d renderers (%s); choose one with --device", len(devices), deviceNames(devices))
+	}
+	var matches []Device
+	for _, d := range devices {
+		name := strings.ToLower(d.Name)
+		if name == query {
+			return d, nil
+		}
+		if strings.Contains(name, query) {
+			matches = append(matches, d)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return Device{}, fmt.Errorf("no renderer matches %q (found %s)", query, deviceNames(devices))
+	case 1:
+		return matches[0], nil
+	}
+	return Device{}, fmt.Errorf("%q matches %d renderers (%s)", query, len(matches), deviceNames(matches))
+}
+
+func deviceNames(devices []Device) string {
+	if len(devices) == 0 {
+		return "none"
+	}
+	names := make([]string, len(devices))
+	for i, d := range devices {
+		names[i] = d.Name
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,110 @@
+package cast
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestPickDevice(t *testing.T) {
+	devices := []Device{{Name: "Living Room TV"}, {Name: "Kitchen speaker"}, {Name: "Living Room Chromecast"}}
+	tests := []struct {
+		query   string
+		want    string
+		wantErr bool
+	}{
+		{query: "kitchen", want: "Kitchen speaker"},
+		{query: "living room tv", want: "Living Room TV"},
+		{query: "living", wantErr: true},
+		{query: "garage", wantErr: true},
+		{query: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := PickDevice(devices, tt.query)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("PickDevice(%q) = %q, want error", tt.query, got.Name)
+			}
+			continue
+		}
+		if err != nil || got.Name != tt.want {
+			t.Errorf("PickDevice(%q) = %q, %v; want %q", tt.query, got.Name, err, tt.want)
+		}
+	}
+	if got, err := PickDevice(devices[:1], ""); err != nil || got.Name != "Living Room TV" {
+		t.Errorf("PickDevice(one, \"\") = %q, %v", got.Name, err)
+	}
+}
+
+// fakeRenderer reports states from a script, one per Status call.
+type fakeRenderer struct {
+	loaded []string
+	states []State
+	paused bool
+}
+
+func (f *fakeRenderer) Load(_ context.Context, m Media) error {
+	f.loaded = append(f.loaded, m.URL)
+	return nil
+}
+func (f *fakeRenderer) Play(context.Context) error  { f.paused = false; return nil }
+func (f *fakeRenderer) Pause(context.Context) error { f.paused = true; return nil }
+func (f *fakeRenderer) Stop(context.Context) error  { return nil }
+func (f *fakeRenderer) Close() error                { return nil }
+func (f *fakeRenderer) Status(context.Context) (Status, error) {
+	if len(f.states) == 0 {
+		return Status{State: StateIdle}, nil
+	}
+	st := f.states[0]
+	f.states = f.states[1:]
+	return Status{State: st}, nil
+}
+
+func TestSessionAdvancesWhenItemFinishes(t *testing.T) {
+	ctx := context.Background()
+	r := &fakeRenderer{states: []State{
+		StateIdle, // still loading the first item; not finished
+		StatePlaying,
+		StateIdle, // first item finished
+		StatePlaying,
+		StateIdle, // second item finished: end of queue
+	}}
+	s := NewSession(r, []Media{{URL: "a"}, {URL: "b"}})
+	if err := s.Start(ctx, 0); err != nil {
+		t.Fatal(err)
+	}
+	var err error
+	for range 5 {
+		if _, err = s.Poll(ctx); err != nil {
+			break
+		}
+	}
+	if !errors.Is(err, ErrEndOfQueue) {
+		t.Fatalf("Poll error = %v, want ErrEndOfQueue", err)
+	}
+	if len(r.loaded) != 2 || r.loaded[0] != "a" || r.loaded[1] != "b" {
+		t.Fatalf("loaded %v, want [a b]", r.loaded)
+	}
+}
+
+func TestSessionToggleAndSkip(t *testing.T) {
+	ctx := context.Background()
+	r := &fakeRenderer{}
+	s := NewSession(r, []Media{{URL: "a"}, {URL: "b"}, {URL: "c"}})
+	if err := s.Start(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Toggle(ctx); err != nil || !s.Paused() || !r.paused {
+		t.Fatalf("Toggle: err=%v paused=%v renderer paused=%v", err, s.Paused(), r.paused)
+	}
+	// Paused at idle isn't the end of the item.
+	if _, err := s.Poll(ctx); err != nil || s.Index() != 1 {
+		t.Fatalf("Poll while paused: err=%v index=%d", err, s.Index())
+	}
+	if err := s.Previous(ctx); err != nil || s.Index() != 0 || s.Paused() {
+		t.Fatalf("Previous: err=%v index=%d paused=%v", err, s.Index(), s.Paused())
+	}
+	if err := s.Previous(ctx); err != nil || s.Index() != 0 {
+		t.Fatalf("Previous at first: err=%v index=%d", err, s.Index())
+	}
+}
@@ -0,0 +1,410 @@
+package cast
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	nsConnection = "urn:x-cast:com.google.cast.tp.connection"
+	nsHeartbeat  = "urn:x-cast:com.google.cast.tp.heartbeat"
+	nsReceiver   = "urn:x-cast:com.google.cast.receiver"
+	nsMedia      = "urn:x-cast:com.google.cast.media"
+
+	// defaultMediaReceiver is the Cast app that plays a URL with no
+	// receiver of our own.
+	defaultMediaReceiver = "CC1AD845"
+
+	castSender   = "sender-0"
+	castReceiver = "receiver-0"
+
+	castRequestTimeout = 10 * time.Second
+	castHeartbeat      = 5 * time.Second
+	castMaxMessage     = 64 << 10
+)
+
+// castMessage is the Cast v2 CastMessage protobuf, with string payloads
+// only.
+type castMessage struct {
+	Source      string
+	Destination string
+	Namespace   string
+	Payload     string
+}
+
+// marshal encodes m as protobuf: protocol_version (1) and payload_type
+// (5) are always 0, and the strings are fields 2, 3, 4 and 6.
+func (m castMessage) marshal() []byte {
+	b := []byte{0x08, 0x00}
+	b = appendProtoString(b, 2, m.Source)
+	b = appendProtoString(b, 3, m.Destination)
+	b = appendProtoString(b, 4, m.Namespace)
+	b = append(b, 0x28, 0x00)
+	b = appendProtoString(b, 6, m.Payload)
+	return b
+}
+
+func appendProtoString(b []byte, field int, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(field<<3|2))
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+func unmarshalCastMessage(data []byte) (castMessage, error) {
+	var m castMessage
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return m, errors.New("bad protobuf key")
+		}
+		data = data[n:]
+		field, wire := key>>3, key&7
+		switch wire {
+		case 0:
+			if _, n = binary.Uvarint(data); n <= 0 {
+				return m, errors.New("bad protobuf varint")
+			}
+			data = data[n:]
+		case 2:
+			length, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < length {
+				return m, errors.New("bad protobuf length")
+			}
+			value := string(data[n : n+int(length)])
+			data = data[n+int(length):]
+			switch field {
+			case 2:
+				m.Source = value
+			case 3:
+				m.Destination = value
+			case 4:
+				m.Namespace = value
+			case 6:
+				m.Payload = value
+			}
+		default:
+			return m, fmt.Errorf("unexpected protobuf wire type %d", wire)
+		}
+	}
+	return m, nil
+}
+
+// castPayload holds the fields read from any JSON payload.
+type castPayload struct {
+	Type      string          `json:"type"`
+	RequestID int             `json:"requestId"`
+	Reason    string          `json:"reason"`
+	Status    json.RawMessage `json:"status"`
+}
+
+type receiverStatus struct {
+	Applications []struct {
+		AppID       string `json:"appId"`
+		TransportID string `json:"transportId"`
+	} `json:"applications"`
+}
+
+type mediaStatus struct {
+	MediaSessionID int     `json:"mediaSessionId"`
+	PlayerState    string  `json:"playerState"`
+	IdleReason     string  `json:"idleReason"`
+	CurrentTime    float64 `json:"currentTime"`
+}
+
+// chromecast is a Cast v2 connection running the Default Media Receiver.
+type chromecast struct {
+	conn net.Conn
+
+	writeMu sync.Mutex
+	mu      sync.Mutex
+	nextID  int
+	pending map[int]chan castPayload
+	err     error // set once the connection fails
+
+	transport string
+	session   int // media session of the loaded item
+	done      chan struct{}
+}
+
+func dialChromecast(ctx context.Context, d Device) (*chromecast, error) {
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: castRequestTimeout},
+		// Chromecasts present a certificate signed by Google's device
+		// CA, not one a browser would trust for an IP address.
+		Config: &tls.Config{InsecureSkipVerify: true},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(d.Host, strconv.Itoa(d.port)))
+	if err != nil {
+		return nil, fmt.Errorf("connect to %s: %w", d.Name, err)
+	}
+	c := &chromecast{conn: conn, pending: map[int]chan castPayload{}, done: make(chan struct{})}
+	go c.readLoop()
+	go c.heartbeat()
+
+	if err := c.send(castReceiver, nsConnection, map[string]any{"type": "CONNECT"}); err != nil {
+		c.Close()
+		return nil, err
+	}
+	resp, err := c.request(ctx, castReceiver, nsReceiver, map[string]any{"type": "LAUNCH", "appId": defaultMediaReceiver})
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("launch media receiver: %w", err)
+	}
+	var rs receiverStatus
+	_ = json.Unmarshal(resp.Status, &rs)
+	for _, app := range rs.Applications {
+		if app.AppID == defaultMediaReceiver {
+			c.transport = app.TransportID
+		}
+	}
+	if c.transport == "" {
+		c.Close()
+		return nil, errors.New("launch media receiver: no transport in receiver status")
+	}
+	if err := c.send(c.transport, nsConnection, map[string]any{"type": "CONNECT"}); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *chromecast) Load(ctx context.Context, m Media) error {
+	contentType := m.ContentType
+	if contentType == "" {
+		contentType = "video/mp4"
+	}
+	media := map[string]any{
+		"contentId":   m.URL,
+		"contentType": contentType,
+		"streamType":  "BUFFERED",
+		"metadata": map[string]any{
+			"metadataType": 0,
+			"title":        m.Title,
+			"subtitle":     m.Artist,
+		},
+	}
+	if m.Seconds > 0 {
+		media["duration"] = m.Seconds
+	}
+	resp, err := c.request(ctx, c.transport, nsMedia, map[string]any{
+		"type":        "LOAD",
+		"autoplay":    true,
+		"currentTime": 0,
+		"media":       media,
+	})
+	if err != nil {
+		return fmt.Errorf("load: %w", err)
+	}
+	if st, ok := firstMediaStatus(resp.Status); ok {
+		c.mu.Lock()
+		c.session = st.MediaSessionID
+		c.mu.Unlock()
+	}
+	return nil
+}
+
+func (c *chromecast) Play(ctx context.Context) error  { return c.mediaCommand(ctx, "PLAY") }
+func (c *chromecast) Pause(ctx context.Context) error { return c.mediaCommand(ctx, "PAUSE") }
+func (c *chromecast) Stop(ctx context.Context) error  { return c.mediaCommand(ctx, "STOP") }
+
+func (c *chromecast) mediaCommand(ctx context.Context, command string) error {
+	c.mu.Lock()
+	session := c.session
+	c.mu.Unlock()
+	if session == 0 {
+		return nil
+	}
+	_, err := c.request(ctx, c.transport, nsMedia, map[string]any{"type": command, "mediaSessionId": session})
+	if err != nil {
+		return fmt.Errorf("%s: %w", command, err)
+	}
+	return nil
+}
+
+func (c *chromecast) Status(ctx context.Context) (Status, error) {
+	resp, err := c.request(ctx, c.transport, nsMedia, map[string]any{"type": "GET_STATUS"})
+	if err != nil {
+		return Status{}, fmt.Errorf("status: %w", err)
+	}
+	st, ok := firstMediaStatus(resp.Status)
+	if !ok {
+		return Status{State: StateIdle}, nil
+	}
+	out := Status{Position: st.CurrentTime}
+	switch st.PlayerState {
+	case "PLAYING":
+		out.State = StatePlaying
+	case "PAUSED":
+		out.State = StatePaused
+	case "BUFFERING", "LOADING":
+		out.State = StateLoading
+	default:
+		out.State = StateIdle
+	}
+	return out, nil
+}
+
+func (c *chromecast) Close() error {
+	select {
+	case <-c.done:
+	default:
+		if c.transport != "" {
+			_ = c.send(c.transport, nsConnection, map[string]any{"type": "CLOSE"})
+		}
+	}
+	return c.conn.Close()
+}
+
+func firstMediaStatus(raw json.RawMessage) (mediaStatus, bool) {
+	var statuses []mediaStatus
+	if err := json.Unmarshal(raw, &statuses); err != nil || len(statuses) == 0 {
+		return mediaStatus{}, false
+	}
+	return statuses[0], true
+}
+
+// request sends payload with a new requestId and waits for the reply
+// carrying it. Error replies (LOAD_FAILED, INVALID_REQUEST, ...) become
+// errors.
+func (c *chromecast) request(ctx context.Context, dest, namespace string, payload map[string]any) (castPayload, error) {
+	c.mu.Lock()
+	if c.err != nil {
+		err := c.err
+		c.mu.Unlock()
+		return castPayload{}, err
+	}
+	c.nextID++
+	id := c.nextID
+	ch := make(chan castPayload, 1)
+	c.pending[id] = ch
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	payload["requestId"] = id
+	if err := c.send(dest, namespace, payload); err != nil {
+		return castPayload{}, err
+	}
+	timer := time.NewTimer(castRequestTimeout)
+	defer timer.Stop()
+	select {
+	case resp := <-ch:
+		switch resp.Type {
+		case "LOAD_FAILED", "LOAD_CANCELLED", "INVALID_REQUEST", "LAUNCH_ERROR", "INVALID_PLAYER_STATE":
+			if resp.Reason != "" {
+				return resp, fmt.Errorf("%s (%s)", resp.Type, resp.Reason)
+			}
+			return resp, errors.New(resp.Type)
+		}
+		return resp, nil
+	case <-c.done:
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return castPayload{}, c.err
+	case <-timer.C:
+		return castPayload{}, errors.New("no reply from the device")
+	case <-ctx.Done():
+		return castPayload{}, ctx.Err()
+	}
+}
+
+func (c *chromecast) send(dest, namespace string, payload map[string]any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	msg := castMessage{Source: castSender, Destination: dest, Namespace: namespace, Payload: string(data)}.marshal()
+	frame := binary.BigEndian.AppendUint32(nil, uint32(len(msg)))
+	frame = append(frame, msg...)
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_ = c.conn.SetWriteDeadline(time.Now().Add(castRequestTimeout))
+	if _, err := c.conn.Write(frame); err != nil {
+		return fmt.Errorf("send to device: %w", err)
+	}
+	return nil
+}
+
+// readLoop reads frames until the connection closes, answering pings and
+// handing replies to the requests waiting on them.
+func (c *chromecast) readLoop() {
+	err := c.read()
+	c.mu.Lock()
+	if err == nil || errors.Is(err, net.ErrClosed) {
+		err = errors.New("connection to the device closed")
+	}
+	c.err = err
+	c.mu.Unlock()
+	close(c.done)
+}
+
+func (c *chromecast) read() error {
+	header := make([]byte, 4)
+	for {
+		if _, err := io.ReadFull(c.conn, header); err != nil {
+			return err
+		}
+		n := binary.BigEndian.Uint32(header)
+		if n > castMaxMessage {
+			return fmt.Errorf("cast message too large (%d bytes)", n)
+		}
+		data := make([]byte, n)
+		if _, err := io.ReadFull(c.conn, data); err != nil {
+			return err
+		}
+		msg, err := unmarshalCastMessage(data)
+		if err != nil {
+			return err
+		}
+		var p castPayload
+		if err := json.Unmarshal([]byte(msg.Payload), &p); err != nil {
+			continue
+		}
+		if msg.Namespace == nsHeartbeat && p.Type == "PING" {
+			_ = c.send(msg.Source, nsHeartbeat, map[string]any{"type": "PONG"})
+			continue
+		}
+		if msg.Namespace == nsConnection && p.Type == "CLOSE" && msg.Source == c.transport {
+			return errors.New("the device closed the media session")
+		}
+		if p.RequestID == 0 {
+			continue
+		}
+		c.mu.Lock()
+		ch := c.pending[p.RequestID]
+		c.mu.Unlock()
+		if ch != nil {
+			select {
+			case ch <- p:
+			default:
+			}
+		}
+	}
+}
+
+// heartbeat pings the device, which drops connections that go quiet.
+func (c *chromecast) heartbeat() {
+	ticker := time.NewTicker(castHeartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			_ = c.send(castReceiver, nsHeartbeat, map[string]any{"type": "PING"})
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package cast
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestCastMessageRoundTrip(t *testing.T) {
+	in := castMessage{Source: castSender, Destination: castReceiver, Namespace: nsReceiver, Payload: `{"type":"GET_STATUS","requestId":1}`}
+	out, err := unmarshalCastMessage(in.marshal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != in {
+		t.Fatalf("round trip = %+v, want %+v", out, in)
+	}
+}
+
+func TestParseChromecastResponse(t *testing.T) {
+	// A response with a PTR answer and SRV, TXT and A additionals, the
+	// names after the first compressed against earlier ones.
+	msg := []byte{0, 0, 0x84, 0, 0, 0, 0, 1, 0, 0, 0, 3}
+	service := len(msg)
+	msg = appendDNSName(msg, "_googlecast._tcp.local")
+	msg = appendRR(msg, dnsTypePTR, func(b []byte) []byte {
+		b = append(b, 12)
+		b = append(b, "Chromecast-1"...)
+		return append(b, 0xC0, byte(service))
+	})
+
+	instance := len(msg)
+	msg = append(msg, 12)
+	msg = append(msg, "Chromecast-1"...)
+	msg = append(msg, 0xC0, byte(service))
+	msg = appendRR(msg, dnsTypeSRV, func(b []byte) []byte {
+		b = append(b, 0, 0, 0, 0, 0x1F, 0x49) // priority, weight, port 8009
+		return appendDNSName(b, "abc.local")
+	})
+
+	msg = append(msg, 0xC0, byte(instance))
+	msg = appendRR(msg, dnsTypeTXT, func(b []byte) []byte {
+		for _, kv := range []string{"fn=Den TV", "md=Chromecast Ultra"} {
+			b = append(b, byte(len(kv)))
+			b = append(b, kv...)
+		}
+		return b
+	})
+
+	msg = appendDNSName(msg, "abc.local")
+	msg = appendRR(msg, dnsTypeA, func(b []byte) []byte { return append(b, 192, 168, 1, 30) })
+
+	records, err := parseDNSMessage(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	devices := chromecastsFromRecords(records)
+	if len(devices) != 1 {
+		t.Fatalf("devices = %+v", devices)
+	}
+	d := devices[0]
+	if d.Kind != KindChromecast || d.Name != "Den TV" || d.Model != "Chromecast Ultra" || d.Host != "192.168.1.30" || d.port != 8009 {
+		t.Errorf("device = %+v", d)
+	}
+}
+
+func appendDNSName(b []byte, name string) []byte {
+	q := dnsQuery(name, 0)
+	return append(b, q[12:len(q)-4]...)
+}
+
+func appendRR(b []byte, typ uint16, data func([]byte) []byte) []byte {
+	b = binary.BigEndian.AppendUint16(b, typ)
+	b = append(b, 0, 1, 0, 0, 0, 120) // class IN, ttl
+	lengthAt := len(b)
+	b = append(b, 0, 0)
+	b = data(b)
+	binary.BigEndian.PutUint16(b[lengthAt:], uint16(len(b)-lengthAt-2))
+	return b
+}
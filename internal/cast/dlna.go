@@ -0,0 +1,414 @@
+package cast
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ssdpAddr           = "239.255.255.250:1900"
+	mediaRendererType  = "urn:schemas-upnp-org:device:MediaRenderer:1"
+	avTransportPrefix  = "urn:schemas-upnp-org:service:AVTransport:"
+	dlnaRequestTimeout = 5 * time.Second
+)
+
+// DiscoverDLNA sends an SSDP search for media renderers and collects the
+// ones that answer within timeout and offer an AVTransport service.
+func DiscoverDLNA(ctx context.Context, timeout time.Duration) ([]Device, error) {
+	conn, err := net.ListenPacket("udp4", ":0")
+	if err != nil {
+		return nil, fmt.Errorf("ssdp listen: %w", err)
+	}
+	defer conn.Close()
+	dst, err := net.ResolveUDPAddr("udp4", ssdpAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	mx := max(1, int(timeout/time.Second))
+	search := "M-SEARCH * HTTP/1.1\r\n" +
+		"HOST: " + ssdpAddr + "\r\n" +
+		"MAN: \"ssdp:discover\"\r\n" +
+		"MX: " + strconv.Itoa(mx) + "\r\n" +
+		"ST: " + mediaRendererType + "\r\n\r\n"
+	// UDP can drop the search; a second copy costs nothing.
+	for range 2 {
+		if _, err := conn.WriteTo([]byte(search), dst); err != nil {
+			return nil, fmt.Errorf("ssdp search: %w", err)
+		}
+	}
+
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetReadDeadline(deadline)
+
+	locations := map[string]bool{}
+	buf := make([]byte, 8192)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			break
+		}
+		loc, ok := parseSSDPResponse(buf[:n])
+		if ok {
+			locations[loc] = true
+		}
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		devices []Device
+	)
+	for loc := range locations {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d, err := fetchDLNADevice(ctx, loc)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			devices = append(devices, d)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return devices, nil
+}
+
+// parseSSDPResponse returns the LOCATION of a search response.
+func parseSSDPResponse(data []byte) (string, bool) {
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), nil)
+	if err != nil {
+		return "", false
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", false
+	}
+	loc := strings.TrimSpace(resp.Header.Get("Location"))
+	return loc, loc != ""
+}
+
+type upnpRoot struct {
+	URLBase string     `xml:"URLBase"`
+	Device  upnpDevice `xml:"device"`
+}
+
+type upnpDevice struct {
+	DeviceType   string        `xml:"deviceType"`
+	FriendlyName string        `xml:"friendlyName"`
+	ModelName    string        `xml:"modelName"`
+	Services     []upnpService `xml:"serviceList>service"`
+	Devices      []upnpDevice  `xml:"deviceList>device"`
+}
+
+type upnpService struct {
+	ServiceType string `xml:"serviceType"`
+	ControlURL  string `xml:"controlURL"`
+}
+
+func fetchDLNADevice(ctx context.Context, location string) (Device, error) {
+	ctx, cancel := context.WithTimeout(ctx, dlnaRequestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return Device{}, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return Device{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Device{}, fmt.Errorf("device description: %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return Device{}, err
+	}
+	return parseDLNADescription(location, body)
+}
+
+// parseDLNADescription reads a UPnP device description fetched from
+// location and finds the AVTransport service, which may sit on an embedded
+// device.
+func parseDLNADescription(location string, data []byte) (Device, error) {
+	var root upnpRoot
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return Device{}, fmt.Errorf("parse device description: %w", err)
+	}
+	base, err := url.Parse(location)
+	if err != nil {
+		return Device{}, err
+	}
+	if root.URLBase != "" {
+		if u, err := url.Parse(strings.TrimSpace(root.URLBase)); err == nil {
+			base = u
+		}
+	}
+
+	dev, svc, ok := findAVTransport(root.Device)
+	if !ok {
+		return Device{}, errors.New("no AVTransport service")
+	}
+	control, err := base.Parse(strings.TrimSpace(svc.ControlURL))
+	if err != nil {
+		return Device{}, fmt.Errorf("control url: %w", err)
+	}
+	name := strings.TrimSpace(root.Device.FriendlyName)
+	if name == "" {
+		name = strings.TrimSpace(dev.FriendlyName)
+	}
+	if name == "" {
+		name = base.Hostname()
+	}
+	return Device{
+		Kind:        KindDLNA,
+		Name:        name,
+		Model:       strings.TrimSpace(root.Device.ModelName),
+		Host:        base.Hostname(),
+		controlURL:  control.String(),
+		serviceType: strings.TrimSpace(svc.ServiceType),
+	}, nil
+}
+
+func findAVTransport(d upnpDevice) (upnpDevice, upnpService, bool) {
+	for _, s := range d.Services {
+		if strings.HasPrefix(strings.TrimSpace(s.ServiceType), avTransportPrefix) {
+			return d, s, true
+		}
+	}
+	for _, child := range d.Devices {
+		if dev, svc, ok := findAVTransport(child); ok {
+			return dev, svc, true
+		}
+	}
+	return upnpDevice{}, upnpService{}, false
+}
+
+// dlnaRenderer drives a renderer's AVTransport service with SOAP actions.
+// It holds no connection; every call is its own HTTP request.
+type dlnaRenderer struct {
+	d      Device
+	client *http.Client
+}
+
+func newDLNARenderer(d Device) *dlnaRenderer {
+	return &dlnaRenderer{d: d, client: &http.Client{Timeout: dlnaRequestTimeout}}
+}
+
+func (r *dlnaRenderer) Load(ctx context.Context, m Media) error {
+	// Some renderers refuse a new URI while playing.
+	_, _ = r.call(ctx, "Stop", nil)
+	if _, err := r.call(ctx, "SetAVTransportURI", [][2]string{
+		{"CurrentURI", m.URL},
+		{"CurrentURIMetaData", didlMetadata(m)},
+	}); err != nil {
+		return err
+	}
+	return r.Play(ctx)
+}
+
+func (r *dlnaRenderer) Play(ctx context.Context) error {
+	_, err := r.call(ctx, "Play", [][2]string{{"Speed", "1"}})
+	return err
+}
+
+func (r *dlnaRenderer) Pause(ctx context.Context) error {
+	_, err := r.call(ctx, "Pause", nil)
+	return err
+}
+
+func (r *dlnaRenderer) Stop(ctx context.Context) error {
+	_, err := r.call(ctx, "Stop", nil)
+	return err
+}
+
+func (r *dlnaRenderer) Status(ctx context.Context) (Status, error) {
+	info, err := r.call(ctx, "GetTransportInfo", nil)
+	if err != nil {
+		return Status{}, err
+	}
+	var st Status
+	switch info["CurrentTransportState"] {
+	case "PLAYING":
+		st.State = StatePlaying
+	case "PAUSED_PLAYBACK", "PAUSED_RECORDING":
+		st.State = StatePaused
+	case "TRANSITIONING":
+		st.State = StateLoading
+	default: // STOPPED, NO_MEDIA_PRESENT
+		st.State = StateIdle
+	}
+	if pos, err := r.call(ctx, "GetPositionInfo", nil); err == nil {
+		st.Position = parseUPnPTime(pos["RelTime"])
+	}
+	return st, nil
+}
+
+func (r *dlnaRenderer) Close() error { return nil }
+
+// call invokes an AVTransport action on instance 0 and returns the
+// response arguments by name.
+func (r *dlnaRenderer) call(ctx context.Context, action string, args [][2]string) (map[string]string, error) {
+	body := soapEnvelope(r.d.serviceType, action, args)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.d.controlURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", `text/xml; charset="utf-8"`)
+	req.Header.Set("SOAPAction", fmt.Sprintf(`"%s#%s"`, r.d.serviceType, action))
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", action, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", action, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if msg := parseUPnPError(data); msg != "" {
+			return nil, fmt.Errorf("%s: %s", action, msg)
+		}
+		return nil, fmt.Errorf("%s: %s", action, resp.Status)
+	}
+	return parseSOAPResponse(data)
+}
+
+// soapEnvelope builds the request body for an action. InstanceID always
+// comes first, as the spec orders arguments.
+func soapEnvelope(serviceType, action string, args [][2]string) []byte {
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0" encoding="utf-8"?>`)
+	b.WriteString(`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/"><s:Body>`)
+	fmt.Fprintf(&b, `<u:%s xmlns:u="%s"><InstanceID>0</InstanceID>`, action, xmlEscape(serviceType))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "<%s>%s</%s>", arg[0], xmlEscape(arg[1]), arg[0])
+	}
+	fmt.Fprintf(&b, "</u:%s></s:Body></s:Envelope>", action)
+	return b.Bytes()
+}
+
+// parseSOAPResponse collects the child elements of the action response
+// inside the SOAP body.
+func parseSOAPResponse(data []byte) (map[string]string, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	out := map[string]string{}
+	depth := 0
+	var name string
+	var text strings.Builder
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse soap response: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			// Envelope > Body > actionResponse > argument
+			if depth == 4 {
+				name = t.Name.Local
+				text.Reset()
+			}
+		case xml.CharData:
+			if depth == 4 {
+				text.Write(t)
+			}
+		case xml.EndElement:
+			if depth == 4 {
+				out[name] = strings.TrimSpace(text.String())
+			}
+			depth--
+		}
+	}
+}
+
+// parseUPnPError returns the error description from a SOAP fault.
+func parseUPnPError(data []byte) string {
+	var fault struct {
+		Code        int    `xml:"Body>Fault>detail>UPnPError>errorCode"`
+		Description string `xml:"Body>Fault>detail>UPnPError>errorDescription"`
+	}
+	if err := xml.Unmarshal(data, &fault); err != nil || fault.Code == 0 {
+		return ""
+	}
+	if fault.Description == "" {
+		return fmt.Sprintf("upnp error %d", fault.Code)
+	}
+	return fmt.Sprintf("%s (upnp error %d)", fault.Description, fault.Code)
+}
+
+// didlMetadata describes m as a DIDL-Lite item; many TVs won't play a URI
+// without it.
+func didlMetadata(m Media) string {
+	contentType := m.ContentType
+	if contentType == "" {
+		contentType = "video/mp4"
+	}
+	class := "object.item.videoItem"
+	if strings.HasPrefix(contentType, "audio/") {
+		class = "object.item.audioItem.musicTrack"
+	}
+	var b strings.Builder
+	b.WriteString(`<DIDL-Lite xmlns="urn:schemas-upnp-org:metadata-1-0/DIDL-Lite/" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:upnp="urn:schemas-upnp-org:metadata-1-0/upnp/">`)
+	b.WriteString(`<item id="0" parentID="-1" restricted="1">`)
+	fmt.Fprintf(&b, "<dc:title>%s</dc:title>", xmlEscape(m.Title))
+	if m.Artist != "" {
+		fmt.Fprintf(&b, "<upnp:artist>%s</upnp:artist>", xmlEscape(m.Artist))
+	}
+	fmt.Fprintf(&b, "<upnp:class>%s</upnp:class>", class)
+	b.WriteString(`<res protocolInfo="http-get:*:` + xmlEscape(contentType) + `:` + dlnaContentFeatures + `"`)
+	if m.Seconds > 0 {
+		fmt.Fprintf(&b, ` duration="%s"`, formatUPnPTime(m.Seconds))
+	}
+	fmt.Fprintf(&b, ">%s</res></item></DIDL-Lite>", xmlEscape(m.URL))
+	return b.String()
+}
+
+func xmlEscape(s string) string {
+	var b bytes.Buffer
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// parseUPnPTime reads H:MM:SS[.fff]; anything else is 0.
+func parseUPnPTime(s string) float64 {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) != 3 {
+		return 0
+	}
+	var total float64
+	for _, p := range parts {
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil {
+			return 0
+		}
+		total = total*60 + v
+	}
+	return total
+}
+
+func formatUPnPTime(seconds float64) string {
+	ms := int64(seconds*1000 + 0.5)
+	return fmt.Sprintf("%d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
@@ -0,0 +1,94 @@
+package cast
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseSSDPResponse(t *testing.T) {
+	resp := "HTTP/1.1 200 OK\r\n" +
+		"CACHE-CONTROL: max-age=1800\r\n" +
+		"LOCATION: http://192.168.1.20:49152/description.xml\r\n" +
+		"ST: urn:schemas-upnp-org:device:MediaRenderer:1\r\n\r\n"
+	loc, ok := parseSSDPResponse([]byte(resp))
+	if !ok || loc != "http://192.168.1.20:49152/description.xml" {
+		t.Fatalf("parseSSDPResponse = %q, %v", loc, ok)
+	}
+	if _, ok := parseSSDPResponse([]byte("NOTIFY * HTTP/1.1\r\n\r\n")); ok {
+		t.Fatal("parseSSDPResponse accepted a NOTIFY")
+	}
+}
+
+func TestParseDLNADescription(t *testing.T) {
+	desc := `<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+  <device>
+    <deviceType>urn:schemas-upnp-org:device:MediaRenderer:1</deviceType>
+    <friendlyName>Living Room TV</friendlyName>
+    <modelName>Bravia</modelName>
+    <deviceList>
+      <device>
+        <serviceList>
+          <service>
+            <serviceType>urn:schemas-upnp-org:service:RenderingControl:1</serviceType>
+            <controlURL>/rc</controlURL>
+          </service>
+          <service>
+            <serviceType>urn:schemas-upnp-org:service:AVTransport:1</serviceType>
+            <controlURL>upnp/control/AVTransport</controlURL>
+          </service>
+        </serviceList>
+      </device>
+    </deviceList>
+  </device>
+</root>`
+	d, err := parseDLNADescription("http://192.168.1.20:49152/dmr/description.xml", []byte(desc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Kind != KindDLNA || d.Name != "Living Room TV" || d.Model != "Bravia" || d.Host != "192.168.1.20" {
+		t.Errorf("device = %+v", d)
+	}
+	if d.controlURL != "http://192.168.1.20:49152/dmr/upnp/control/AVTransport" {
+		t.Errorf("controlURL = %q", d.controlURL)
+	}
+	if d.serviceType != "urn:schemas-upnp-org:service:AVTransport:1" {
+		t.Errorf("serviceType = %q", d.serviceType)
+	}
+
+	if _, err := parseDLNADescription("http://h/", []byte(`<root><device><friendlyName>x</friendlyName></device></root>`)); err == nil {
+		t.Error("description without AVTransport accepted")
+	}
+}
+
+func TestSOAPRoundTrip(t *testing.T) {
+	body := string(soapEnvelope("urn:schemas-upnp-org:service:AVTransport:1", "SetAVTransportURI", [][2]string{
+		{"CurrentURI", "http://10.0.0.2:8000/media/0/a&b.mp4"},
+	}))
+	for _, want := range []string{
+		`<u:SetAVTransportURI xmlns:u="urn:schemas-upnp-org:service:AVTransport:1"><InstanceID>0</InstanceID>`,
+		`<CurrentURI>http://10.0.0.2:8000/media/0/a&amp;b.mp4</CurrentURI>`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("envelope missing %s:\n%s", want, body)
+		}
+	}
+
+	resp := `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>
+<u:GetPositionInfoResponse xmlns:u="urn:schemas-upnp-org:service:AVTransport:1">
+<Track>1</Track><RelTime>0:01:02.500</RelTime></u:GetPositionInfoResponse></s:Body></s:Envelope>`
+	out, err := parseSOAPResponse([]byte(resp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := parseUPnPTime(out["RelTime"]); got != 62.5 {
+		t.Errorf("RelTime = %q (%v)", out["RelTime"], got)
+	}
+
+	fault := `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body><s:Fault>
+<detail><UPnPError xmlns="urn:schemas-upnp-org:control-1-0"><errorCode>714</errorCode>
+<errorDescription>Illegal MIME-type</errorDescription></UPnPError></detail></s:Fault></s:Body></s:Envelope>`
+	if got := parseUPnPError([]byte(fault)); got != "Illegal MIME-type (upnp error 714)" {
+		t.Errorf("parseUPnPError = %q", got)
+	}
+}
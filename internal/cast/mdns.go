@@ -0,0 +1,253 @@
+package cast
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	mdnsAddr          = "224.0.0.251:5353"
+	googlecastService = "_googlecast._tcp.local"
+
+	dnsTypeA   = 1
+	dnsTypePTR = 12
+	dnsTypeTXT = 16
+	dnsTypeSRV = 33
+)
+
+// DiscoverChromecasts asks for _googlecast._tcp over multicast DNS and
+// collects the Chromecasts that answer within timeout. The query is sent
+// from an ephemeral port, so responders answer it directly (a "legacy
+// unicast" query) and nothing needs to bind port 5353.
+func DiscoverChromecasts(ctx context.Context, timeout time.Duration) ([]Device, error) {
+	conn, err := net.ListenPacket("udp4", ":0")
+	if err != nil {
+		return nil, fmt.Errorf("mdns listen: %w", err)
+	}
+	defer conn.Close()
+	dst, err := net.ResolveUDPAddr("udp4", mdnsAddr)
+	if err != nil {
+		return nil, err
+	}
+	query := dnsQuery(googlecastService, dnsTypePTR)
+	for range 2 {
+		if _, err := conn.WriteTo(query, dst); err != nil {
+			return nil, fmt.Errorf("mdns query: %w", err)
+		}
+	}
+
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetReadDeadline(deadline)
+
+	seen := map[string]bool{}
+	var devices []Device
+	buf := make([]byte, 9000)
+	for {
+		n, from, err := conn.ReadFrom(buf)
+		if err != nil {
+			break
+		}
+		records, err := parseDNSMessage(buf[:n])
+		if err != nil {
+			continue
+		}
+		for _, d := range chromecastsFromRecords(records) {
+			if d.Host == "" {
+				if udp, ok := from.(*net.UDPAddr); ok {
+					d.Host = udp.IP.String()
+				}
+			}
+			key := net.JoinHostPort(d.Host, fmt.Sprint(d.port))
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			devices = append(devices, d)
+		}
+	}
+	return devices, nil
+}
+
+// dnsRecord is one resource record, with the fields discovery reads.
+type dnsRecord struct {
+	Name   string
+	Type   uint16
+	Target string            // PTR, SRV
+	Port   int               // SRV
+	IP     net.IP            // A
+	TXT    map[string]string // TXT
+}
+
+// chromecastsFromRecords joins the PTR, SRV, TXT and A records of one
+// response into devices. The friendly name comes from TXT "fn" and the
+// model from "md".
+func chromecastsFromRecords(records []dnsRecord) []Device {
+	byName := map[string][]dnsRecord{}
+	for _, r := range records {
+		key := strings.ToLower(r.Name)
+		byName[key] = append(byName[key], r)
+	}
+	var devices []Device
+	for _, ptr := range records {
+		if ptr.Type != dnsTypePTR || !strings.EqualFold(ptr.Name, googlecastService) {
+			continue
+		}
+		instance := strings.ToLower(ptr.Target)
+		d := Device{Kind: KindChromecast, port: 8009}
+		var target string
+		for _, r := range byName[instance] {
+			switch r.Type {
+			case dnsTypeSRV:
+				d.port, target = r.Port, strings.ToLower(r.Target)
+			case dnsTypeTXT:
+				d.Name, d.Model = r.TXT["fn"], r.TXT["md"]
+			}
+		}
+		for _, r := range byName[target] {
+			if r.Type == dnsTypeA {
+				d.Host = r.IP.String()
+				break
+			}
+		}
+		if d.Name == "" {
+			d.Name, _, _ = strings.Cut(ptr.Target, ".")
+		}
+		devices = append(devices, d)
+	}
+	return devices
+}
+
+// dnsQuery builds a one-question DNS query.
+func dnsQuery(name string, qtype uint16) []byte {
+	msg := make([]byte, 12, 64)
+	binary.BigEndian.PutUint16(msg[4:], 1) // QDCOUNT
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		msg = append(msg, byte(len(label)))
+		msg = append(msg, label...)
+	}
+	msg = append(msg, 0)
+	msg = binary.BigEndian.AppendUint16(msg, qtype)
+	msg = binary.BigEndian.AppendUint16(msg, 1) // IN
+	return msg
+}
+
+var errShortDNS = errors.New("short dns message")
+
+// parseDNSMessage returns every answer, authority and additional record
+// of a response.
+func parseDNSMessage(msg []byte) ([]dnsRecord, error) {
+	if len(msg) < 12 {
+		return nil, errShortDNS
+	}
+	if msg[2]&0x80 == 0 {
+		return nil, errors.New("not a dns response")
+	}
+	qd := int(binary.BigEndian.Uint16(msg[4:]))
+	rr := int(binary.BigEndian.Uint16(msg[6:])) + int(binary.BigEndian.Uint16(msg[8:])) + int(binary.BigEndian.Uint16(msg[10:]))
+	off := 12
+	for range qd {
+		_, next, err := readDNSName(msg, off)
+		if err != nil {
+			return nil, err
+		}
+		off = next + 4
+	}
+	var records []dnsRecord
+	for range rr {
+		name, next, err := readDNSName(msg, off)
+		if err != nil {
+			return nil, err
+		}
+		if next+10 > len(msg) {
+			return nil, errShortDNS
+		}
+		r := dnsRecord{Name: name, Type: binary.BigEndian.Uint16(msg[next:])}
+		length := int(binary.BigEndian.Uint16(msg[next+8:]))
+		start := next + 10
+		end := start + length
+		if end > len(msg) {
+			return nil, errShortDNS
+		}
+		data := msg[start:end]
+		switch r.Type {
+		case dnsTypeA:
+			if len(data) == 4 {
+				r.IP = net.IP(append([]byte(nil), data...))
+			}
+		case dnsTypePTR:
+			r.Target, _, err = readDNSName(msg, start)
+		case dnsTypeSRV:
+			if len(data) >= 7 {
+				r.Port = int(binary.BigEndian.Uint16(data[4:]))
+				r.Target, _, err = readDNSName(msg, start+6)
+			}
+		case dnsTypeTXT:
+			r.TXT = parseTXT(data)
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+		off = end
+	}
+	return records, nil
+}
+
+// readDNSName reads a possibly compressed name at off and returns it with
+// the offset just past it.
+func readDNSName(msg []byte, off int) (string, int, error) {
+	var labels []string
+	next := -1
+	for jumps := 0; ; {
+		if off >= len(msg) {
+			return "", 0, errShortDNS
+		}
+		n := int(msg[off])
+		switch {
+		case n == 0:
+			if next < 0 {
+				next = off + 1
+			}
+			return strings.Join(labels, "."), next, nil
+		case n&0xC0 == 0xC0:
+			if off+1 >= len(msg) {
+				return "", 0, errShortDNS
+			}
+			if next < 0 {
+				next = off + 2
+			}
+			if jumps++; jumps > 32 {
+				return "", 0, errors.New("dns name loop")
+			}
+			off = int(binary.BigEndian.Uint16(msg[off:]) & 0x3FFF)
+		default:
+			if off+1+n > len(msg) {
+				return "", 0, errShortDNS
+			}
+			labels = append(labels, string(msg[off+1:off+1+n]))
+			off += 1 + n
+		}
+	}
+}
+
+func parseTXT(data []byte) map[string]string {
+	out := map[string]string{}
+	for len(data) > 0 {
+		n := int(data[0])
+		if 1+n > len(data) {
+			break
+		}
+		k, v, _ := strings.Cut(string(data[1:1+n]), "=")
+		out[strings.ToLower(k)] = v
+		data = data[1+n:]
+	}
+	return out
+}
@@ -0,0 +1,85 @@
+package cast
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dlnaContentFeatures lets DLNA renderers seek: byte ranges are supported,
+// and the file is streamed rather than downloaded first.
+const dlnaContentFeatures = "DLNA.ORG_OP=01;DLNA.ORG_CI=0;DLNA.ORG_FLAGS=01700000000000000000000000000000"
+
+// Server serves a fixed list of files over HTTP for a renderer to fetch.
+// Only those files are reachable, each at /media/<n>/<file name>.
+type Server struct {
+	files []string
+	base  string
+	srv   *http.Server
+}
+
+// Serve starts a server for files on host (an address of this machine the
+// renderer can reach; see LocalAddr), on a free port.
+func Serve(host string, files []string) (*Server, error) {
+	ln, err := net.Listen("tcp", net.JoinHostPort(host, "0"))
+	if err != nil {
+		return nil, fmt.Errorf("listen for the renderer: %w", err)
+	}
+	s := &Server{files: files, base: "http://" + ln.Addr().String()}
+	s.srv = &http.Server{Handler: s, ReadHeaderTimeout: 10 * time.Second}
+	go s.srv.Serve(ln)
+	return s, nil
+}
+
+// URL returns the address of the file at index.
+func (s *Server) URL(index int) string {
+	return fmt.Sprintf("%s/media/%d/%s", s.base, index, url.PathEscape(filepath.Base(s.files[index])))
+}
+
+// Close stops the server.
+func (s *Server) Close() error {
+	return s.srv.Close()
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	rest, ok := strings.CutPrefix(r.URL.Path, "/media/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	n, _, _ := strings.Cut(rest, "/")
+	index, err := strconv.Atoi(n)
+	if err != nil || index < 0 || index >= len(s.files) {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("transferMode.dlna.org", "Streaming")
+	w.Header().Set("contentFeatures.dlna.org", dlnaContentFeatures)
+	http.ServeFile(w, r, s.files[index])
+}
+
+// LocalAddr returns the address of the interface this machine uses to
+// reach host, which is where a renderer on the same network can reach it.
+func LocalAddr(host string) (string, error) {
+	// UDP "connects" without sending anything, but picks the route.
+	conn, err := net.Dial("udp", net.JoinHostPort(host, "9"))
+	if err != nil {
+		return "", fmt.Errorf("find a route to %s: %w", host, err)
+	}
+	defer conn.Close()
+	addr, ok := conn.LocalAddr().(*net.UDPAddr)
+	if !ok || addr.IP.IsUnspecified() {
+		return "", errors.New("no local address for the renderer")
+	}
+	return addr.IP.String(), nil
+}
@@ -0,0 +1,50 @@
+package cast
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestServerServesOnlyListedFiles(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "001 intro.mp4")
+	if err := os.WriteFile(file, []byte("video"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Serve("127.0.0.1", []string{file})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if !strings.HasSuffix(s.URL(0), "/media/0/001%20intro.mp4") {
+		t.Errorf("URL(0) = %s", s.URL(0))
+	}
+	resp, err := http.Get(s.URL(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != "video" {
+		t.Errorf("GET = %s %q", resp.Status, body)
+	}
+	if resp.Header.Get("transferMode.dlna.org") != "Streaming" {
+		t.Errorf("missing DLNA headers: %v", resp.Header)
+	}
+
+	for _, path := range []string{"/media/1/x.mp4", "/media/-1/x", "/" + filepath.Base(file)} {
+		resp, err := http.Get(strings.Replace(s.URL(0), "/media/0/001%20intro.mp4", path, 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("GET %s = %s, want 404", path, resp.Status)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package cast
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrEndOfQueue is returned by Next past the last item.
+var ErrEndOfQueue = errors.New("end of queue")
+
+// Session plays a queue of media on a renderer, one item after another.
+// Renderers only play one item at a time, so Poll notices when an item has
+// finished and loads the next. A Session is not safe for concurrent use.
+type Session struct {
+	r      Renderer
+	items  []Media
+	index  int
+	paused bool
+	// started is set once the current item has been seen playing, so the
+	// idle state a renderer reports while it loads isn't taken as finished.
+	started bool
+}
+
+// NewSession prepares to play items on r.
+func NewSession(r Renderer, items []Media) *Session {
+	return &Session{r: r, items: items, index: -1}
+}
+
+// Index returns the position of the current item, or -1 before Start.
+func (s *Session) Index() int { return s.index }
+
+// Current returns the current item.
+func (s *Session) Current() Media {
+	if s.index < 0 || s.index >= len(s.items) {
+		return Media{}
+	}
+	return s.items[s.index]
+}
+
+// Len returns the number of items in the queue.
+func (s *Session) Len() int { return len(s.items) }
+
+// Paused reports whether the session was paused with Toggle.
+func (s *Session) Paused() bool { return s.paused }
+
+// Start loads the item at index.
+func (s *Session) Start(ctx context.Context, index int) error {
+	if index < 0 || index >= len(s.items) {
+		return ErrEndOfQueue
+	}
+	s.index, s.paused, s.started = index, false, false
+	return s.r.Load(ctx, s.items[index])
+}
+
+// Next skips to the following item.
+func (s *Session) Next(ctx context.Context) error {
+	return s.Start(ctx, s.index+1)
+}
+
+// Previous goes back one item, or restarts the first.
+func (s *Session) Previous(ctx context.Context) error {
+	return s.Start(ctx, max(s.index-1, 0))
+}
+
+// Toggle pauses or resumes the current item.
+func (s *Session) Toggle(ctx context.Context) error {
+	if s.paused {
+		if err := s.r.Play(ctx); err != nil {
+			return err
+		}
+	} else if err := s.r.Pause(ctx); err != nil {
+		return err
+	}
+	s.paused = !s.paused
+	return nil
+}
+
+// Poll reads the renderer's state and loads the next item when the current
+// one has finished. It returns the state read, and ErrEndOfQueue once the
+// last item has finished.
+func (s *Session) Poll(ctx context.Context) (Status, error) {
+	st, err := s.r.Status(ctx)
+	if err != nil {
+		return st, err
+	}
+	switch st.State {
+	case StatePlaying, StatePaused:
+		s.started = true
+	case StateIdle:
+		if s.started && !s.paused {
+			return st, s.Next(ctx)
+		}
+	}
+	return st, nil
+}
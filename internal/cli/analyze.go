@@ -0,0 +1,356 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/config"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+	"powerhour/internal/tools"
+)
+
+var (
+	analyzeCollection string
+	analyzeIndexArg   []string
+	analyzeSnapToBeat bool
+	analyzeRange      float64
+	analyzeChorus     bool
+)
+
+// analyzeChorusCount is how many chorus suggestions --chorus lists per row.
+const analyzeChorusCount = 3
+
+// analyzeMinShift is the smallest snap worth writing; closer starts are
+// already on the beat.
+const analyzeMinShift = 0.05
+
+func newAnalyzeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "analyze",
+		Short: "Snap start times to the beat or suggest chorus starts",
+		Long: `Look for the nearest onset (a sharp rise in loudness, such as a downbeat)
+within --range seconds of each row's start time, so clips can cut in on the
+music instead of mid-phrase.
+
+Onsets come from ffmpeg's ebur128 momentary loudness, read every 100ms over
+the cached source. Without --snap-to-beat the suggested starts are only
+listed. With it, each row's start_time is written to the collection's
+overrides file, leaving the plan alone, and the row's segment is marked
+stale. A collection without an overrides file gets <plan>.overrides.yaml
+next to its plan, and config is updated to point at it.
+
+With --chorus, each row's whole source is measured instead and the three
+likeliest chorus starts are listed with a confidence from 0 to 1: stretches
+that are loud for the song and whose loudness repeats elsewhere in it. Pick
+one and set it as the row's start_time.
+
+Rows with a windows column, and rows whose source isn't cached, are skipped.`,
+		Example: `  powerhour analyze
+  powerhour analyze --snap-to-beat
+  powerhour analyze --index 5-10 --snap-to-beat --range 1
+  powerhour analyze --chorus --index 12`,
+		Args: cobra.NoArgs,
+		RunE: runAnalyze,
+	}
+	cmd.Flags().StringVar(&analyzeCollection, "collection", "", "Collection to analyse (default: the only collection, or songs)")
+	cmd.Flags().StringSliceVar(&analyzeIndexArg, "index", nil, "1-based row index or range like 5-10 (repeat flag for multiple; default: all rows)")
+	cmd.Flags().BoolVar(&analyzeSnapToBeat, "snap-to-beat", false, "Write the snapped start times to the overrides file")
+	cmd.Flags().Float64Var(&analyzeRange, "range", 2, "Seconds either side of the start time to search for an onset")
+	cmd.Flags().BoolVar(&analyzeChorus, "chorus", false, "List likely chorus starts for each row instead")
+	cmd.MarkFlagsMutuallyExclusive("chorus", "snap-to-beat")
+	return cmd
+}
+
+type analyzeResult struct {
+	Index   int     `json:"index"`
+	From    string  `json:"from"`
+	To      string  `json:"to,omitempty"`
+	Shift   float64 `json:"shift_s,omitempty"`
+	Skipped string  `json:"skipped,omitempty"`
+	Segment string  `json:"segment,omitempty"`
+	Stale   bool    `json:"stale,omitempty"`
+	// Chorus lists --chorus suggestions, best first.
+	Chorus []chorusSuggestion `json:"chorus,omitempty"`
+}
+
+type chorusSuggestion struct {
+	Start      string  `json:"start"`
+	Confidence float64 `json:"confidence"`
+}
+
+func runAnalyze(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if analyzeRange <= 0 {
+		return fmt.Errorf("--range must be positive")
+	}
+	indexes, err := parseIndexArgs(analyzeIndexArg)
+	if err != nil {
+		return err
+	}
+
+	glogf, gcloser := logx.StartCommand("analyze")
+	defer gcloser.Close()
+	glogf("analyze started: collection=%s indexes=%v snap=%t chorus=%t range=%.1f", analyzeCollection, indexes, analyzeSnapToBeat, analyzeChorus, analyzeRange)
+
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
+
+	resolver, err := project.NewCollectionResolver(cfg, pp)
+	if err != nil {
+		return err
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		return err
+	}
+	name, err := pickPlanCollection(collections, analyzeCollection)
+	if err != nil {
+		return err
+	}
+	coll := collections[name]
+	if coll.Plan == "" {
+		return fmt.Errorf("collection %q has no plan file", name)
+	}
+
+	idx, err := cache.Load(pp)
+	if err != nil {
+		return err
+	}
+	segments, err := nudgeSegments(pp, cfg, idx, resolver, collections, name)
+	if err != nil {
+		return err
+	}
+	ffmpegPath, err := tools.Lookup("ffmpeg")
+	if err != nil {
+		return fmt.Errorf("locate ffmpeg: %w", err)
+	}
+
+	results, err := analyzeRows(ctx, ffmpegPath, coll, segments, indexes)
+	if err != nil {
+		return err
+	}
+
+	var overridesFile string
+	if analyzeSnapToBeat {
+		overridesFile, err = writeSnappedStarts(cmd, pp, resolver, coll, results)
+		if err != nil {
+			return err
+		}
+		var outputs []string
+		for i := range results {
+			if results[i].To == "" {
+				continue
+			}
+			if seg, ok := segments[results[i].Index]; ok && seg.OutputPath != "" {
+				results[i].Segment = seg.OutputPath
+				outputs = append(outputs, seg.OutputPath)
+			}
+		}
+		stale, err := markSegmentsStale(pp.RenderStateFile, outputs)
+		if err != nil {
+			return err
+		}
+		for i := range results {
+			results[i].Stale = stale[results[i].Segment]
+		}
+		glogf("analyze: wrote %s, marked %d segment(s) stale", overridesFile, len(stale))
+	}
+
+	if outputJSON {
+		data, err := json.MarshalIndent(struct {
+			Collection string          `json:"collection"`
+			Overrides  string          `json:"overrides,omitempty"`
+			Rows       []analyzeResult `json:"rows"`
+		}{name, overridesFile, results}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	snapped := 0
+	for _, r := range results {
+		switch {
+		case r.Skipped != "":
+			cmd.Printf("Row %d start %s: %s\n", r.Index, r.From, r.Skipped)
+		case analyzeChorus:
+			picks := make([]string, len(r.Chorus))
+			for i, c := range r.Chorus {
+				picks[i] = fmt.Sprintf("%s (%.2f)", c.Start, c.Confidence)
+			}
+			cmd.Printf("Row %d start %s: chorus %s\n", r.Index, r.From, strings.Join(picks, ", "))
+		default:
+			snapped++
+			cmd.Printf("Row %d start %s → %s (%+.2fs)", r.Index, r.From, r.To, r.Shift)
+			if r.Stale {
+				cmd.Printf(" (%s marked stale)", relPath(pp.Root, r.Segment))
+			}
+			cmd.Println()
+		}
+	}
+	switch {
+	case overridesFile != "":
+		cmd.Printf("Saved %s\n", relPath(pp.Root, overridesFile))
+	case snapped > 0 && !analyzeSnapToBeat:
+		cmd.Println("Run with --snap-to-beat to write these start times to the overrides file.")
+	}
+	return nil
+}
+
+// analyzeRows measures the onsets around the start of each requested row,
+// or every row when indexes is empty, in plan order. With --chorus it
+// suggests chorus starts from the whole source instead.
+func analyzeRows(ctx context.Context, ffmpegPath string, coll project.Collection, segments map[int]render.Segment, indexes []int) ([]analyzeResult, error) {
+	want := make(map[int]bool, len(indexes))
+	for _, i := range indexes {
+		want[i] = true
+	}
+	var missing []int
+	for _, i := range indexes {
+		found := false
+		for _, row := range coll.Rows {
+			found = found || row.Index == i
+		}
+		if !found {
+			missing = append(missing, i)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("collection %q has no row(s) %s (1-%d)", coll.Name, formatIndexList(missing), len(coll.Rows))
+	}
+
+	var results []analyzeResult
+	for _, row := range coll.Rows {
+		if len(want) > 0 && !want[row.Index] {
+			continue
+		}
+		seg, ok := segments[row.Index]
+		if !ok {
+			continue
+		}
+		start := seg.Clip.Row.Start
+		res := analyzeResult{Index: row.Index, From: formatStartTime(start)}
+		if err := checkRowWindows(row); err != nil {
+			res.Skipped = "plays several windows"
+			results = append(results, res)
+			continue
+		}
+		if seg.SourcePath == "" {
+			res.Skipped = "source not cached"
+			results = append(results, res)
+			continue
+		}
+
+		if analyzeChorus {
+			samples, err := render.MeasureMomentaryLoudness(ctx, ffmpegPath, seg.SourcePath, 0, 0)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: %w", row.Index, err)
+			}
+			for _, c := range render.FindChorusCandidates(samples, float64(seg.Clip.DurationSeconds), analyzeChorusCount) {
+				at := time.Duration(c.Start * float64(time.Second))
+				res.Chorus = append(res.Chorus, chorusSuggestion{Start: formatStartTime(at), Confidence: c.Confidence})
+			}
+			if len(res.Chorus) == 0 {
+				res.Skipped = "source too short to suggest a chorus"
+			}
+			results = append(results, res)
+			continue
+		}
+
+		at := start.Seconds()
+		from := math.Max(0, at-analyzeRange)
+		samples, err := render.MeasureMomentaryLoudness(ctx, ffmpegPath, seg.SourcePath, from, at+analyzeRange-from)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", row.Index, err)
+		}
+		onset, ok := render.PickOnset(samples, at-from)
+		snapped := from + onset
+		switch {
+		case !ok || math.Abs(snapped-at) > analyzeRange:
+			res.Skipped = "no onset nearby"
+		case math.Abs(snapped-at) < analyzeMinShift:
+			res.Skipped = "already on the beat"
+		default:
+			to := time.Duration(snapped * float64(time.Second)).Round(time.Millisecond)
+			res.To = formatStartTime(to)
+			res.Shift = (to - start).Seconds()
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// writeSnappedStarts records each snapped start in the collection's
+// overrides file, keyed by row index, and returns the file's path, or ""
+// when no row moved. A collection without an overrides file gets
+// <plan>.overrides.yaml, saved to config.
+func writeSnappedStarts(cmd *cobra.Command, pp paths.ProjectPaths, resolver *project.CollectionResolver, coll project.Collection, results []analyzeResult) (string, error) {
+	overrides := make(map[string]project.RowOverride, len(coll.Overrides)+len(results))
+	for k, v := range coll.Overrides {
+		overrides[k] = v
+	}
+	changed := 0
+	for _, r := range results {
+		if r.To == "" {
+			continue
+		}
+		key := strconv.Itoa(r.Index)
+		ov := overrides[key]
+		ov.StartTime = r.To
+		overrides[key] = ov
+		changed++
+	}
+	if changed == 0 {
+		return "", nil
+	}
+
+	path := resolver.OverridesPath(coll.Config)
+	if path == "" {
+		path = strings.TrimSuffix(coll.Plan, filepath.Ext(coll.Plan)) + ".overrides.yaml"
+		rel := path
+		if r, err := filepath.Rel(pp.Root, path); err == nil && !strings.HasPrefix(r, "..") {
+			rel = filepath.ToSlash(r)
+		}
+		cfg, err := config.Load(pp.ConfigFile)
+		if err != nil {
+			return "", err
+		}
+		collCfg := cfg.Collections[coll.Name]
+		collCfg.Overrides = rel
+		cfg.Collections[coll.Name] = collCfg
+		if err := config.Save(pp.ConfigFile, cfg); err != nil {
+			return "", fmt.Errorf("save config: %w", err)
+		}
+		if !outputJSON {
+			cmd.Printf("Set collections.%s.overrides to %s\n", coll.Name, rel)
+		}
+	}
+	if err := project.WriteRowOverrides(path, overrides); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -177,7 +177,8 @@ func runCacheDownload(ctx context.Context, videoID, titleFlag, artistFlag string
 
 // runCacheFile registers a local file into the cache.
 func runCacheFile(ctx context.Context, filePath, urlFlag, titleFlag, artistFlag string, dryRun, noProbe bool) error {
-	glogf, closer := logx.StartCommand("cache")
+	glog, closer := logx.Command("cache")
+	glogf := logx.Printf(glog)
 	defer closer.Close()
 
 	absFile, err := filepath.Abs(filePath)
@@ -250,7 +251,7 @@ func runCacheFile(ctx context.Context, filePath, urlFlag, titleFlag, artistFlag
 		title = remoteInfo.Title
 		artist = remoteInfo.Artist
 	} else {
-		glogf("yt-dlp metadata query failed: %v", queryErr)
+		glog.Warn("yt-dlp metadata query failed", "error", queryErr)
 
 		if ytID := cache.ExtractYouTubeID(rawURL); ytID != "" {
 			extractor = "youtube"
@@ -349,7 +350,7 @@ func runCacheFile(ctx context.Context, filePath, urlFlag, titleFlag, artistFlag
 		status.Update("Running ffprobe...")
 		probe, err = svc.ProbeFile(ctx, targetPath)
 		if err != nil {
-			glogf("ffprobe failed: %v", err)
+			glog.Warn("ffprobe failed", "error", err)
 		}
 	}
 
@@ -475,12 +476,12 @@ func resolveFromPlans(filePath string) (*planMatch, error) {
 					StartHeader:     collCfg.StartHeader,
 					DurationHeader:  collCfg.DurationHeader,
 					DefaultDuration: 60,
+					Sheet:           collCfg.Sheet,
 				}
 
 				var rows []csvplan.CollectionRow
-				ext := strings.ToLower(filepath.Ext(planPath))
-				if ext == ".yaml" || ext == ".yml" {
-					result, _ := csvplan.LoadCollectionYAML(planPath, opts)
+				if csvplan.IsStructuredPlan(planPath) {
+					result, _ := csvplan.LoadCollectionStructured(planPath, opts)
 					rows = result.Rows
 				} else {
 					rows, _ = csvplan.LoadCollection(planPath, opts)
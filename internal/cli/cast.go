@@ -0,0 +1,335 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	xterm "github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/cast"
+	"powerhour/internal/config"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/render"
+)
+
+var (
+	castDevice   string
+	castList     bool
+	castFinal    bool
+	castTimeline string
+	castVariant  string
+	castFrom     int
+	castTimeout  time.Duration
+	castHost     string
+)
+
+func newCastCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cast",
+		Short: "Play the hour on a Chromecast or DLNA TV on the local network",
+		Long: `Find Chromecasts and DLNA media renderers (most smart TVs) on the local
+network and play the rendered segments on one, in timeline order, served
+straight from this machine. No HDMI cable needed.
+
+With --final, the concat output is cast as a single video instead.
+
+Keys while casting:
+  space   pause / resume
+  n, →    next segment
+  p, ←    previous segment
+  q       stop and quit
+
+Only one renderer found? It's used without --device. --device matches a
+name exactly or by a unique part of it, ignoring case.`,
+		Example: `  powerhour cast --list
+  powerhour cast --device "living room"
+  powerhour cast --final --timeline short`,
+		Args: cobra.NoArgs,
+		RunE: runCast,
+	}
+	cmd.Flags().StringVar(&castDevice, "device", "", "Renderer name or part of it (default: the only one found)")
+	cmd.Flags().BoolVar(&castList, "list", false, "List the renderers found and exit")
+	cmd.Flags().BoolVar(&castFinal, "final", false, "Cast the concat output instead of the segments")
+	cmd.Flags().StringVar(&castTimeline, "timeline", "", "Use a named timeline from timelines:")
+	cmd.Flags().StringVar(&castVariant, "variant", "", "Follow the timeline as assembled with concat --variant")
+	cmd.Flags().IntVar(&castFrom, "from", 1, "Start at this segment (1-based)")
+	cmd.Flags().DurationVar(&castTimeout, "discover-timeout", 3*time.Second, "How long to look for renderers")
+	cmd.Flags().StringVar(&castHost, "host", "", "Address of this machine the renderer can reach (default: detected)")
+	return cmd
+}
+
+func runCast(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	glogf, gcloser := logx.StartCommand("cast")
+	defer gcloser.Close()
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "Looking for renderers (%s)...\n", castTimeout)
+	devices, err := cast.Discover(ctx, castTimeout)
+	if err != nil {
+		return fmt.Errorf("discover renderers: %w", err)
+	}
+	glogf("cast discovery: %d renderers", len(devices))
+	if castList {
+		return printCastDevices(cmd, devices)
+	}
+	device, err := cast.PickDevice(devices, castDevice)
+	if err != nil {
+		return err
+	}
+
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
+	cfg, pp, err = applyNamedTimeline(cfg, pp, castTimeline)
+	if err != nil {
+		return err
+	}
+	if castVariant != "" {
+		cfg.Timeline, err = cfg.Timeline.WithVariant(castVariant)
+		if err != nil {
+			return err
+		}
+	}
+
+	var entries []render.PlaylistEntry
+	if castFinal {
+		base := concatOutputBase(castTimeline, castVariant)
+		file := findConcatOutput(pp.Root, base)
+		if file == "" {
+			return fmt.Errorf("no %s.mp4/.mkv/.mov in %s; run `powerhour concat` first", base, pp.Root)
+		}
+		entries = []render.PlaylistEntry{{Location: file, Title: filepath.Base(pp.Root)}}
+	} else {
+		var missing []string
+		entries, missing, err = timelinePlaylistEntries(ctx, pp, cfg)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return fmt.Errorf("no rendered segments in the timeline; run `powerhour render` first")
+		}
+		if len(missing) > 0 {
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: %d segments not rendered yet are skipped\n", len(missing))
+		}
+	}
+	if castFrom < 1 || castFrom > len(entries) {
+		return fmt.Errorf("--from %d is out of range (1-%d)", castFrom, len(entries))
+	}
+
+	host := castHost
+	if host == "" {
+		if host, err = cast.LocalAddr(device.Host); err != nil {
+			return err
+		}
+	}
+	files := make([]string, len(entries))
+	for i, e := range entries {
+		files[i] = e.Location
+	}
+	server, err := cast.Serve(host, files)
+	if err != nil {
+		return err
+	}
+	defer server.Close()
+	items := make([]cast.Media, len(entries))
+	for i, e := range entries {
+		items[i] = cast.Media{
+			URL:         server.URL(i),
+			Title:       e.Title,
+			Artist:      e.Artist,
+			ContentType: castContentType(e.Location),
+			Seconds:     e.Seconds,
+		}
+	}
+
+	renderer, err := cast.Connect(ctx, device)
+	if err != nil {
+		return err
+	}
+	defer renderer.Close()
+	glogf("cast started: device=%q kind=%s items=%d", device.Name, device.Kind, len(items))
+
+	session := cast.NewSession(renderer, items)
+	if err := session.Start(ctx, castFrom-1); err != nil {
+		return err
+	}
+	err = castLoop(ctx, cmd.OutOrStdout(), device, session)
+	// The context may be cancelled already; stopping still deserves a try.
+	stopCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if stopErr := renderer.Stop(stopCtx); stopErr != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: %v\n", stopErr)
+	}
+	glogf("cast finished: at %d/%d", session.Index()+1, session.Len())
+	return err
+}
+
+func printCastDevices(cmd *cobra.Command, devices []cast.Device) error {
+	if outputJSON {
+		type device struct {
+			Name  string `json:"name"`
+			Kind  string `json:"kind"`
+			Model string `json:"model,omitempty"`
+			Host  string `json:"host"`
+		}
+		out := make([]device, len(devices))
+		for i, d := range devices {
+			out[i] = device{Name: d.Name, Kind: string(d.Kind), Model: d.Model, Host: d.Host}
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+	if len(devices) == 0 {
+		cmd.Println("No renderers found.")
+		return nil
+	}
+	for _, d := range devices {
+		line := fmt.Sprintf("%-30s  %-10s  %s", d.Name, d.Kind, d.Host)
+		if d.Model != "" {
+			line += "  (" + d.Model + ")"
+		}
+		cmd.Println(line)
+	}
+	return nil
+}
+
+// castKey is a control read from the terminal.
+type castKey int
+
+const (
+	castKeyToggle castKey = iota
+	castKeyNext
+	castKeyPrevious
+	castKeyQuit
+)
+
+// castLoop polls the renderer once a second, advancing through the queue,
+// and applies keys until the queue ends or the user quits.
+func castLoop(ctx context.Context, out io.Writer, device cast.Device, session *cast.Session) error {
+	keys := make(chan castKey, 4)
+	if xterm.IsTerminal(os.Stdin.Fd()) {
+		if state, err := xterm.MakeRaw(os.Stdin.Fd()); err == nil {
+			defer xterm.Restore(os.Stdin.Fd(), state)
+		}
+		go readCastKeys(os.Stdin, keys)
+	}
+
+	fmt.Fprintf(out, "Casting %d item(s) to %s. space pause, n next, p previous, q quit\r\n", session.Len(), device.Name)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	var status cast.Status
+	for {
+		fmt.Fprintf(out, "\r\033[K%s", castStatusLine(session, status))
+		var err error
+		select {
+		case <-ctx.Done():
+			fmt.Fprint(out, "\r\n")
+			return nil
+		case key := <-keys:
+			switch key {
+			case castKeyToggle:
+				err = session.Toggle(ctx)
+			case castKeyNext:
+				err = session.Next(ctx)
+			case castKeyPrevious:
+				err = session.Previous(ctx)
+			case castKeyQuit:
+				fmt.Fprint(out, "\r\n")
+				return nil
+			}
+			status = cast.Status{State: cast.StateLoading}
+			if key == castKeyToggle {
+				status.State = cast.StatePlaying
+				if session.Paused() {
+					status.State = cast.StatePaused
+				}
+			}
+		case <-ticker.C:
+			status, err = session.Poll(ctx)
+		}
+		if errors.Is(err, cast.ErrEndOfQueue) {
+			fmt.Fprint(out, "\r\033[KFinished.\r\n")
+			return nil
+		}
+		if err != nil {
+			fmt.Fprint(out, "\r\n")
+			return err
+		}
+	}
+}
+
+func readCastKeys(r io.Reader, keys chan<- castKey) {
+	buf := make([]byte, 8)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			return
+		}
+		in := string(buf[:n])
+		switch {
+		case in == " ":
+			keys <- castKeyToggle
+		case in == "n" || in == "\x1b[C":
+			keys <- castKeyNext
+		case in == "p" || in == "\x1b[D":
+			keys <- castKeyPrevious
+		case in == "q" || in == "\x03":
+			keys <- castKeyQuit
+			return
+		}
+	}
+}
+
+func castStatusLine(session *cast.Session, status cast.Status) string {
+	m := session.Current()
+	label := m.Title
+	if m.Artist != "" {
+		label = m.Artist + " - " + m.Title
+	}
+	state := string(status.State)
+	if state == "" {
+		state = string(cast.StateLoading)
+	}
+	return fmt.Sprintf("[%d/%d] %s  %s %s", session.Index()+1, session.Len(), label, formatSampleTime(status.Position), state)
+}
+
+// castContentType names the video type renderers expect for a file.
+func castContentType(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".mkv":
+		return "video/x-matroska"
+	case ".mov":
+		return "video/quicktime"
+	case ".webm":
+		return "video/webm"
+	default:
+		return "video/mp4"
+	}
+}
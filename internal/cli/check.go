@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"sort"
 	"strings"
 
@@ -54,18 +55,18 @@ func runCheck(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	logger, closer, err := logx.New(pp)
+	logger, closer, err := logx.Open(pp)
 	if err != nil {
 		return err
 	}
 	defer closer.Close()
-	logger.Printf("powerhour check: project=%s", pp.Root)
+	logger.Info("powerhour check", "project", pp.Root)
 
 	cfg, err := config.Load(pp.ConfigFile)
 	if err != nil {
 		return err
 	}
-	logger.Printf("loaded config version=%d", cfg.Version)
+	logger.Info("loaded config", "version", cfg.Version)
 
 	detectCtx := tools.WithMinimums(cmd.Context(), cfg.ToolMinimums())
 	statuses, err := tools.Detect(detectCtx)
@@ -74,7 +75,11 @@ func runCheck(cmd *cobra.Command, _ []string) error {
 	}
 
 	for _, st := range statuses {
-		logger.Printf("tool %s: source=%s version=%s satisfied=%v error=%s", st.Tool, st.Source, st.Version, st.Satisfied, st.Error)
+		level := slog.LevelInfo
+		if !st.Satisfied {
+			level = slog.LevelWarn
+		}
+		logger.Log(detectCtx, level, "tool", "tool", st.Tool, "source", st.Source, "version", st.Version, "satisfied", st.Satisfied, "error", st.Error)
 	}
 
 	var validations []config.ValidationResult
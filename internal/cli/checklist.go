@@ -0,0 +1,422 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/config"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+	"powerhour/internal/tools"
+)
+
+var (
+	checklistTimeline string
+	checklistDevice   string
+	checklistOut      string
+)
+
+// deviceProfile lists what a playback target can handle. Empty lists
+// accept anything.
+type deviceProfile struct {
+	Name        string
+	Label       string
+	Containers  []string // file extensions without the dot
+	VideoCodecs []string // ffprobe codec_name values
+	AudioCodecs []string
+	PixFmts     []string
+}
+
+var deviceProfiles = []deviceProfile{
+	{Name: "universal", Label: "anything with a screen",
+		Containers: []string{"mp4"}, VideoCodecs: []string{"h264"}, AudioCodecs: []string{"aac"}, PixFmts: []string{"yuv420p"}},
+	{Name: "apple", Label: "Apple TV, iPhone, QuickTime",
+		Containers: []string{"mp4", "mov"}, VideoCodecs: []string{"h264", "hevc"}, AudioCodecs: []string{"aac"}, PixFmts: []string{"yuv420p", "yuv420p10le"}},
+	{Name: "chromecast", Label: "Chromecast / Google TV",
+		Containers: []string{"mp4", "webm", "mkv"}, VideoCodecs: []string{"h264", "hevc", "vp9"}, AudioCodecs: []string{"aac", "mp3", "opus"}, PixFmts: []string{"yuv420p"}},
+	{Name: "browser", Label: "web browsers",
+		Containers: []string{"mp4", "webm"}, VideoCodecs: []string{"h264", "vp9", "av1"}, AudioCodecs: []string{"aac", "mp3", "opus"}, PixFmts: []string{"yuv420p"}},
+	{Name: "vlc", Label: "VLC"},
+}
+
+func deviceProfileNames() []string {
+	names := make([]string, len(deviceProfiles))
+	for i, p := range deviceProfiles {
+		names[i] = p.Name
+	}
+	return names
+}
+
+func lookupDeviceProfile(name string) (deviceProfile, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, p := range deviceProfiles {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return deviceProfile{}, false
+}
+
+// probedMedia is the part of an ffprobe report the checklist looks at.
+type probedMedia struct {
+	VideoCodec      string
+	AudioCodec      string
+	PixFmt          string
+	DurationSeconds float64
+}
+
+// incompatibilities lists why a file with the given extension and streams
+// won't play on the device; nil means it will.
+func (d deviceProfile) incompatibilities(ext string, media probedMedia) []string {
+	var problems []string
+	check := func(kind, value string, allowed []string) {
+		if len(allowed) == 0 || slices.Contains(allowed, value) {
+			return
+		}
+		if value == "" {
+			value = "none"
+		}
+		problems = append(problems, fmt.Sprintf("%s %s (wants %s)", kind, value, strings.Join(allowed, "/")))
+	}
+	check("container", strings.TrimPrefix(strings.ToLower(ext), "."), d.Containers)
+	check("video", media.VideoCodec, d.VideoCodecs)
+	check("audio", media.AudioCodec, d.AudioCodecs)
+	check("pixel format", media.PixFmt, d.PixFmts)
+	return problems
+}
+
+func newChecklistCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "checklist",
+		Short: "Run the pre-party checks and print a go/no-go summary",
+		Long: `Run the final checks before the party: every source cached, every
+timeline segment rendered and readable, the final file assembled after the
+last render and passing a probe, the runtime within timeline.target_duration_s,
+and the file's codecs playable on the target device.
+
+Device profiles: ` + strings.Join(deviceProfileNames(), ", ") + `.
+
+Exits non-zero on a no-go.`,
+		Args: cobra.NoArgs,
+		RunE: runChecklist,
+	}
+	cmd.Flags().StringVar(&checklistTimeline, "timeline", "", "Check a named timeline from timelines: instead of the default timeline")
+	cmd.Flags().StringVar(&checklistDevice, "device", "universal", "Playback device profile ("+strings.Join(deviceProfileNames(), ", ")+")")
+	cmd.Flags().StringVarP(&checklistOut, "output", "o", "", "Final video to check (default: the file concat writes)")
+	_ = cmd.RegisterFlagCompletionFunc("device", cobra.FixedCompletions(deviceProfileNames(), cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+func runChecklist(cmd *cobra.Command, _ []string) error {
+	glogf, gcloser := logx.StartCommand("checklist")
+	defer gcloser.Close()
+	glogf("checklist started: timeline=%s device=%s", checklistTimeline, checklistDevice)
+
+	device, ok := lookupDeviceProfile(checklistDevice)
+	if !ok {
+		return fmt.Errorf("unknown device profile %q (choose from %s)", checklistDevice, strings.Join(deviceProfileNames(), ", "))
+	}
+
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
+	cfg, pp, err = applyNamedTimeline(cfg, pp, checklistTimeline)
+	if err != nil {
+		return err
+	}
+	if len(cfg.Collections) == 0 {
+		return fmt.Errorf("no collections configured")
+	}
+	resolver, err := project.NewCollectionResolver(cfg, pp)
+	if err != nil {
+		return fmt.Errorf("create collection resolver: %w", err)
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		return fmt.Errorf("load collections: %w", err)
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ffprobe := findFFprobe()
+
+	finalPath := checklistOut
+	if finalPath == "" {
+		container := tools.ResolveEncoding(tools.LoadEncodingProfile(), tools.LoadEncodingDefaults(), encodingConfigToDefaults(cfg.Encoding)).Container
+		finalPath = concatOutputBase(checklistTimeline, "") + containerExt(container)
+	}
+	if !filepath.IsAbs(finalPath) {
+		finalPath = filepath.Join(pp.Root, finalPath)
+	}
+
+	var checks []healthCheck
+	checks = append(checks, mustPass(checkSources(pp, collections)))
+
+	segCheck, newestSegment := checkChecklistSegments(ctx, ffprobe, pp, cfg, resolver, collections)
+	checks = append(checks, segCheck)
+
+	finalCheck, media, probed := checkFinalVideo(ctx, ffprobe, finalPath, newestSegment)
+	checks = append(checks, finalCheck)
+	checks = append(checks, checkChecklistRuntime(cfg, collections, media, probed))
+	checks = append(checks, checkDevice(device, finalPath, media, probed))
+
+	for _, c := range checks {
+		glogf("check %s: %s — %s", c.Name, c.Status, c.Summary)
+	}
+	goForParty := true
+	for _, c := range checks {
+		if c.Status == "error" {
+			goForParty = false
+		}
+	}
+
+	if err := writeChecklistResult(cmd, finalPath, device, checks, goForParty); err != nil {
+		return err
+	}
+	if !goForParty {
+		return errors.New("checklist: no-go")
+	}
+	return nil
+}
+
+// mustPass turns a doctor warning into an error: on party night a missing
+// source is a blocker, not a nudge.
+func mustPass(c healthCheck) healthCheck {
+	if c.Status == "warning" {
+		c.Status = "error"
+	}
+	return c
+}
+
+// checkChecklistSegments combines doctor's render-state check with a probe
+// of every timeline segment, and returns the newest segment's mtime so the
+// final file can be checked for staleness.
+func checkChecklistSegments(ctx context.Context, ffprobe string, pp paths.ProjectPaths, cfg config.Config, resolver *project.CollectionResolver, collections map[string]project.Collection) (healthCheck, time.Time) {
+	var newest time.Time
+	rendered := mustPass(checkSegments(pp, cfg, resolver, collections))
+	if rendered.Status != "ok" {
+		return rendered, newest
+	}
+
+	segments, err := render.ResolveTimelineSegments(pp, cfg, collections)
+	if err != nil {
+		return healthCheck{Name: "Segments", Status: "error", Summary: err.Error()}, newest
+	}
+	if missing := listMissingSegments(segments); len(missing) > 0 {
+		return healthCheck{Name: "Segments", Status: "error", Summary: fmt.Sprintf(
+			"%d timeline segments missing; run powerhour render", len(missing))}, newest
+	}
+
+	var bad []string
+	for _, seg := range segments {
+		if info, err := os.Stat(seg.Path); err == nil && info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+		if ffprobe == "" {
+			continue
+		}
+		if err := probeFile(ctx, ffprobe, seg.Path); err != nil {
+			bad = append(bad, filepath.Base(seg.Path))
+		}
+	}
+	if len(bad) > 0 {
+		return healthCheck{Name: "Segments", Status: "error", Summary: fmt.Sprintf(
+			"%d unreadable: %s", len(bad), joinComma(bad))}, newest
+	}
+	if ffprobe == "" {
+		return healthCheck{Name: "Segments", Status: "warning", Summary: fmt.Sprintf(
+			"%s; ffprobe not found, not verified", rendered.Summary)}, newest
+	}
+	return healthCheck{Name: "Segments", Status: "ok", Summary: fmt.Sprintf(
+		"%s, %d timeline segments verified", rendered.Summary, len(segments))}, newest
+}
+
+// checkFinalVideo checks the assembled file exists, is newer than every
+// segment, and probes with a video and an audio stream.
+func checkFinalVideo(ctx context.Context, ffprobe, path string, newestSegment time.Time) (healthCheck, probedMedia, bool) {
+	name := filepath.Base(path)
+	info, err := os.Stat(path)
+	if err != nil {
+		return healthCheck{Name: "Final video", Status: "error", Summary: fmt.Sprintf(
+			"%s not found; run powerhour concat", name)}, probedMedia{}, false
+	}
+	if info.ModTime().Before(newestSegment) {
+		return healthCheck{Name: "Final video", Status: "error", Summary: fmt.Sprintf(
+			"%s is older than the latest render; run powerhour concat", name)}, probedMedia{}, false
+	}
+	if ffprobe == "" {
+		return healthCheck{Name: "Final video", Status: "warning", Summary: fmt.Sprintf(
+			"%s (%s); ffprobe not found, not verified", name, formatBytes(info.Size()))}, probedMedia{}, false
+	}
+
+	media, err := probeMedia(ctx, ffprobe, path)
+	if err != nil {
+		return healthCheck{Name: "Final video", Status: "error", Summary: err.Error()}, probedMedia{}, false
+	}
+	var missing []string
+	if media.VideoCodec == "" {
+		missing = append(missing, "video")
+	}
+	if media.AudioCodec == "" {
+		missing = append(missing, "audio")
+	}
+	if len(missing) > 0 {
+		return healthCheck{Name: "Final video", Status: "error", Summary: fmt.Sprintf(
+			"%s has no %s stream", name, strings.Join(missing, " or "))}, media, true
+	}
+	return healthCheck{Name: "Final video", Status: "ok", Summary: fmt.Sprintf(
+		"%s (%s, %s)", name, formatBytes(info.Size()), formatSampleTime(media.DurationSeconds))}, media, true
+}
+
+// checkChecklistRuntime compares the final file's real length to
+// timeline.target_duration_s, falling back to the projected runtime when
+// the file couldn't be probed.
+func checkChecklistRuntime(cfg config.Config, collections map[string]project.Collection, media probedMedia, probed bool) healthCheck {
+	budget, err := project.TimelineRuntime(cfg.Timeline, collections)
+	if err != nil {
+		return healthCheck{Name: "Runtime", Status: "error", Summary: err.Error()}
+	}
+	label := "projected"
+	if probed && media.DurationSeconds > 0 {
+		budget.ProjectedSeconds = media.DurationSeconds
+		label = "actual"
+	}
+	if budget.TargetSeconds <= 0 {
+		return healthCheck{Name: "Runtime", Status: "ok", Summary: fmt.Sprintf(
+			"%s %s; no target_duration_s set", label, formatSampleTime(budget.ProjectedSeconds))}
+	}
+	summary := fmt.Sprintf("%s %s vs target %s", label,
+		formatSampleTime(budget.ProjectedSeconds), formatSampleTime(budget.TargetSeconds))
+	if budget.OffTarget() {
+		return healthCheck{Name: "Runtime", Status: "error", Summary: fmt.Sprintf(
+			"%s (off by %s)", summary, formatSampleTime(math.Abs(budget.DeltaSeconds())))}
+	}
+	return healthCheck{Name: "Runtime", Status: "ok", Summary: summary}
+}
+
+func checkDevice(device deviceProfile, path string, media probedMedia, probed bool) healthCheck {
+	name := fmt.Sprintf("Device (%s)", device.Name)
+	if !probed {
+		return healthCheck{Name: name, Status: "warning", Summary: "final video not probed; codecs unknown"}
+	}
+	if problems := device.incompatibilities(filepath.Ext(path), media); len(problems) > 0 {
+		return healthCheck{Name: name, Status: "error", Summary: joinComma(problems)}
+	}
+	return healthCheck{Name: name, Status: "ok", Summary: fmt.Sprintf(
+		"%s/%s plays on %s", media.VideoCodec, media.AudioCodec, device.Label)}
+}
+
+// probeMedia reads the first video and audio stream codecs and the
+// container duration from ffprobe.
+func probeMedia(ctx context.Context, ffprobe, path string) (probedMedia, error) {
+	cmd := exec.CommandContext(ctx, ffprobe,
+		"-v", "error",
+		"-print_format", "json",
+		"-show_format",
+		"-show_streams",
+		path,
+	)
+	output, err := cmd.Output()
+	if err != nil {
+		return probedMedia{}, fmt.Errorf("ffprobe %s: %w", filepath.Base(path), err)
+	}
+	var report struct {
+		Streams []struct {
+			CodecType string `json:"codec_type"`
+			CodecName string `json:"codec_name"`
+			PixFmt    string `json:"pix_fmt"`
+		} `json:"streams"`
+		Format struct {
+			Duration string `json:"duration"`
+		} `json:"format"`
+	}
+	if err := json.Unmarshal(output, &report); err != nil {
+		return probedMedia{}, fmt.Errorf("parse ffprobe output: %w", err)
+	}
+	var media probedMedia
+	for _, s := range report.Streams {
+		switch {
+		case s.CodecType == "video" && media.VideoCodec == "":
+			media.VideoCodec = s.CodecName
+			media.PixFmt = s.PixFmt
+		case s.CodecType == "audio" && media.AudioCodec == "":
+			media.AudioCodec = s.CodecName
+		}
+	}
+	media.DurationSeconds, _ = strconv.ParseFloat(report.Format.Duration, 64)
+	return media, nil
+}
+
+func writeChecklistResult(cmd *cobra.Command, finalPath string, device deviceProfile, checks []healthCheck, goForParty bool) error {
+	if outputJSON {
+		payload := struct {
+			Go     bool          `json:"go"`
+			Final  string        `json:"final"`
+			Device string        `json:"device"`
+			Checks []healthCheck `json:"checks"`
+		}{goForParty, finalPath, device.Name, checks}
+		data, err := json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return nil
+	}
+
+	bold := lipgloss.NewStyle().Bold(true).Inline(true)
+	green := lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Inline(true)
+	yellow := lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Inline(true)
+	red := lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Inline(true)
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintln(out, bold.Render("PRE-PARTY CHECKLIST"))
+	warnings := 0
+	for _, c := range checks {
+		var mark string
+		switch c.Status {
+		case "ok":
+			mark = green.Render("✔")
+		case "warning":
+			mark = yellow.Render("?")
+			warnings++
+		default:
+			mark = red.Render("✘")
+		}
+		fmt.Fprintf(out, "  %s %-20s %s\n", mark, c.Name, c.Summary)
+	}
+	fmt.Fprintln(out)
+
+	switch {
+	case !goForParty:
+		fmt.Fprintln(out, red.Bold(true).Render("🚫 NO-GO")+" — fix the ✘ items above before the first shot.")
+	case warnings > 0:
+		fmt.Fprintln(out, green.Bold(true).Render("🍻 GO")+" — with "+strconv.Itoa(warnings)+" item(s) unverified. Cheers!")
+	default:
+		fmt.Fprintln(out, green.Bold(true).Render("🎉 GO")+" — 60 minutes, 60 shots. Let's party!")
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDeviceProfileIncompatibilities(t *testing.T) {
+	h264 := probedMedia{VideoCodec: "h264", AudioCodec: "aac", PixFmt: "yuv420p"}
+	tests := []struct {
+		device string
+		ext    string
+		media  probedMedia
+		want   []string
+	}{
+		{"universal", ".mp4", h264, nil},
+		{"universal", ".MP4", h264, nil},
+		{"universal", ".mkv", h264, []string{"container mkv (wants mp4)"}},
+		{"universal", ".mp4", probedMedia{VideoCodec: "hevc", AudioCodec: "aac", PixFmt: "yuv420p"},
+			[]string{"video hevc (wants h264)"}},
+		{"universal", ".mp4", probedMedia{VideoCodec: "h264", PixFmt: "yuv444p"},
+			[]string{"audio none (wants aac)", "pixel format yuv444p (wants yuv420p)"}},
+		{"apple", ".mov", probedMedia{VideoCodec: "hevc", AudioCodec: "aac", PixFmt: "yuv420p10le"}, nil},
+		{"browser", ".webm", probedMedia{VideoCodec: "vp9", AudioCodec: "opus", PixFmt: "yuv420p"}, nil},
+		{"vlc", ".mkv", probedMedia{VideoCodec: "prores", AudioCodec: "pcm_s16le", PixFmt: "yuv422p10le"}, nil},
+	}
+
+	for _, tt := range tests {
+		device, ok := lookupDeviceProfile(tt.device)
+		if !ok {
+			t.Fatalf("lookupDeviceProfile(%q) not found", tt.device)
+		}
+		got := device.incompatibilities(tt.ext, tt.media)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s %s %+v: got %q, want %q", tt.device, tt.ext, tt.media, got, tt.want)
+		}
+	}
+
+	if _, ok := lookupDeviceProfile("toaster"); ok {
+		t.Error("lookupDeviceProfile(toaster) should fail")
+	}
+}
+
+func TestMustPass(t *testing.T) {
+	for status, want := range map[string]string{"ok": "ok", "warning": "error", "error": "error"} {
+		if got := mustPass(healthCheck{Status: status}).Status; got != want {
+			t.Errorf("mustPass(%s) = %s, want %s", status, got, want)
+		}
+	}
+}
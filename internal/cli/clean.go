@@ -235,7 +235,6 @@ func buildExpectedPaths(pp paths.ProjectPaths, cfg config.Config) (map[string]bo
 	}
 
 	expected := make(map[string]bool, len(clips))
-	tmpl := cfg.SegmentFilenameTemplate()
 
 	for _, collClip := range clips {
 		clip := collClip.Clip
@@ -252,13 +251,7 @@ func buildExpectedPaths(pp paths.ProjectPaths, cfg config.Config) (map[string]bo
 			Overlays: collClip.Overlays,
 		}
 
-		outputDir := collClip.OutputDir
-		if !filepath.IsAbs(outputDir) {
-			outputDir = filepath.Join(pp.SegmentsDir, outputDir)
-		}
-		baseName := render.SegmentBaseName(tmpl, seg)
-		outputPath := filepath.Join(outputDir, baseName+".mp4")
-		expected[outputPath] = true
+		expected[render.CollectionSegmentPath(cfg, pp.SegmentsDir, collClip.CollectionName, collClip.OutputDir, seg)] = true
 	}
 
 	return expected, nil
@@ -3,6 +3,7 @@ package cli
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -10,6 +11,7 @@ import (
 
 	"powerhour/internal/cache"
 	"powerhour/internal/config"
+	"powerhour/internal/hooks"
 	"powerhour/internal/logx"
 	"powerhour/internal/paths"
 	"powerhour/internal/project"
@@ -26,7 +28,8 @@ func addCollectionFetchFlags(cmd *cobra.Command) {
 }
 
 // runCollectionFetch handles fetching for collections-based configuration.
-func runCollectionFetch(ctx context.Context, cmd *cobra.Command, pp paths.ProjectPaths, cfg config.Config, glogf func(string, ...any), status *tui.StatusWriter) error {
+func runCollectionFetch(ctx context.Context, cmd *cobra.Command, pp paths.ProjectPaths, cfg config.Config, glog *slog.Logger, status *tui.StatusWriter) error {
+	glogf := logx.Printf(glog)
 	if cfg.Collections == nil || len(cfg.Collections) == 0 {
 		return fmt.Errorf("no collections configured")
 	}
@@ -81,11 +84,22 @@ func runCollectionFetch(ctx context.Context, cmd *cobra.Command, pp paths.Projec
 		collectionRows = filtered
 	}
 
-	logger, closer, err := logx.New(pp)
+	plog, closer, err := logx.Open(pp)
 	if err != nil {
 		return err
 	}
 	defer closer.Close()
+	logger := logx.PrintLogger(plog)
+
+	if runner := hooks.New(cfg.Hooks, pp.Root, logger.Writer()); runner.Has(hooks.PreFetch) {
+		status.Update("Running pre_fetch hooks...")
+		glogf("running pre_fetch hooks")
+		if err := runner.Run(ctx, hooks.PreFetch, preFetchHookPayload(fetchCollection, collectionRows)); err != nil {
+			return fmt.Errorf("fetch aborted: %w", err)
+		}
+	}
+
+	updateNote := autoUpdateYTDLP(ctx, cfg, glog, status)
 
 	status.Update("Checking tools (yt-dlp, ffmpeg)...")
 	glogf("ensuring tools (yt-dlp, ffmpeg)")
@@ -95,21 +109,35 @@ func runCollectionFetch(ctx context.Context, cmd *cobra.Command, pp paths.Projec
 	}
 	glogf("tools ready, starting fetch")
 
+	var space spaceEstimate
+	if !fetchNoDownload {
+		space = estimateFetchSpace(ctx, svc, pp, idx, collectionRows, fetchForce, status.Update)
+		glogf("fetch space estimate: %d bytes for %d downloads", space.Bytes, space.Items)
+	}
+
 	opts := cache.ResolveOptions{Force: fetchForce, Reprobe: fetchReprobe, NoDownload: fetchNoDownload}
 
 	outWriter := cmd.OutOrStdout()
-	mode := tui.DetectMode(outWriter, fetchNoProgress, outputJSON)
+	mode := tui.DetectMode(outWriter, fetchNoProgress || logx.Echoing(), outputJSON)
 	if mode != tui.ModeTUI {
 		svc.SetLogOutput(cmd.ErrOrStderr())
 	}
 	status.Stop() // Hand off to TUI or plain output
+	if err := checkDiskSpace(cmd.ErrOrStderr(), space, fetchSkipSpace); err != nil {
+		return err
+	}
+	if updateNote != "" && !outputJSON {
+		fmt.Fprintln(cmd.ErrOrStderr(), updateNote)
+	}
 
 	outcomes := make([]fetchRowResult, 0, len(collectionRows))
 	counts := fetchCounts{}
 	dirty := false
+	byLink := fetchByLink{}
 
 	fetchWork := func(send func(tea.Msg)) {
-		for _, collRow := range collectionRows {
+		for i, collRow := range collectionRows {
+			reportPipelineProgress(i, len(collectionRows))
 			row := collRow.Row
 			key := collectionFetchProgressKey(collRow)
 
@@ -120,10 +148,14 @@ func runCollectionFetch(ctx context.Context, cmd *cobra.Command, pp paths.Projec
 				})
 			}
 
-			result, err := svc.Resolve(ctx, idx, row, opts)
+			result, err := byLink.resolve(row.Link, func() (cache.ResolveResult, error) {
+				rowOpts := opts
+				rowOpts.Collection = collRow.CollectionName
+				return svc.Resolve(ctx, idx, row, rowOpts)
+			})
 			if err != nil {
 				counts.Failed++
-				logger.Printf("fetch collection=%s row %03d failed: %v", collRow.CollectionName, row.Index, err)
+				plog.Error("fetch row failed", "collection", collRow.CollectionName, "row", row.Index, "error", err)
 				fmt.Fprintf(cmd.ErrOrStderr(), "fetch collection=%s row %03d failed: %v\n", collRow.CollectionName, row.Index, err)
 				if send != nil {
 					send(tui.RowUpdateMsg{
@@ -186,13 +218,15 @@ func runCollectionFetch(ctx context.Context, cmd *cobra.Command, pp paths.Projec
 				Probed:     result.Probed,
 			})
 		}
+		reportPipelineProgress(len(collectionRows), len(collectionRows))
 	}
 
 	if mode == tui.ModeTUI {
 		glogf("starting TUI (mode=tui)")
 		fmt.Fprintf(outWriter, "Project: %s\n", pp.Root)
 		model := buildCollectionFetchProgressModel(collectionRows)
-		if err := tui.RunWithWork(outWriter, model, fetchWork); err != nil {
+		model.SetDetail(collectionFetchDetail(pp, collectionRows))
+		if err := tui.RunWithWork(outWriter, model, glogf, fetchWork); err != nil {
 			return err
 		}
 		glogf("TUI finished")
@@ -206,6 +240,8 @@ func runCollectionFetch(ctx context.Context, cmd *cobra.Command, pp paths.Projec
 		}
 	}
 
+	notifySummary = fetchSummaryLine(counts)
+
 	if mode == tui.ModeJSON {
 		return writeFetchJSON(cmd, pp.Root, outcomes, counts)
 	}
@@ -221,6 +257,36 @@ func runCollectionFetch(ctx context.Context, cmd *cobra.Command, pp paths.Projec
 	return nil
 }
 
+// fetchByLink shares one fetch among rows with the same link, such as
+// several windows cut from one video.
+type fetchByLink map[string]fetchOutcome
+
+type fetchOutcome struct {
+	result cache.ResolveResult
+	err    error
+}
+
+// resolve runs fetch for the first row with link and hands later rows its
+// outcome, reported as cached. Rows without a link are never shared, so
+// each reports its own error.
+func (b fetchByLink) resolve(link string, fetch func() (cache.ResolveResult, error)) (cache.ResolveResult, error) {
+	link = strings.TrimSpace(link)
+	if link == "" {
+		return fetch()
+	}
+	if prior, ok := b[link]; ok {
+		result := prior.result
+		if result.Status != cache.ResolveStatusMissing {
+			result.Status = cache.ResolveStatusCached
+		}
+		result.Probed, result.Updated = false, false
+		return result, prior.err
+	}
+	result, err := fetch()
+	b[link] = fetchOutcome{result: result, err: err}
+	return result, err
+}
+
 func filterCollectionRowsByIndexArgs(rows []project.CollectionPlanRow, args []string) ([]project.CollectionPlanRow, error) {
 	indexes, err := parseIndexArgs(args)
 	if err != nil {
@@ -276,6 +342,35 @@ func buildCollectionFetchProgressModel(collectionRows []project.CollectionPlanRo
 	return model
 }
 
+// collectionFetchDetail returns the detail pane of each fetch row: its plan
+// fields and, for links yt-dlp downloads, the row's fetch log.
+func collectionFetchDetail(pp paths.ProjectPaths, collectionRows []project.CollectionPlanRow) tui.DetailFunc {
+	byKey := make(map[string]project.CollectionPlanRow, len(collectionRows))
+	for _, entry := range collectionRows {
+		byKey[collectionFetchProgressKey(entry)] = entry
+	}
+	return func(key string) tui.Detail {
+		entry, ok := byKey[key]
+		if !ok {
+			return tui.Detail{}
+		}
+		row := entry.Row
+		detail := tui.Detail{Params: []tui.DetailParam{
+			{Name: "title", Value: row.Title},
+			{Name: "artist", Value: row.Artist},
+			{Name: "link", Value: row.Link},
+			{Name: "start", Value: row.StartRaw},
+		}}
+		if isRemoteLink(strings.TrimSpace(row.Link)) {
+			detail.Params = append(detail.Params, tui.DetailParam{Name: "via", Value: "yt-dlp"})
+			detail.LogPath = cache.FetchLogPath(pp, entry.CollectionName, row.Index)
+		} else {
+			detail.Params = append(detail.Params, tui.DetailParam{Name: "via", Value: "local copy"})
+		}
+		return detail
+	}
+}
+
 func collectionFetchStartStatus(entry project.CollectionPlanRow, force bool) string {
 	link := strings.TrimSpace(entry.Row.Link)
 	if isRemoteLink(link) {
@@ -6,7 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"sort"
@@ -19,6 +19,7 @@ import (
 
 	"powerhour/internal/cache"
 	"powerhour/internal/config"
+	"powerhour/internal/hooks"
 	"powerhour/internal/logx"
 	"powerhour/internal/paths"
 	"powerhour/internal/project"
@@ -38,7 +39,7 @@ func addCollectionRenderFlags(cmd *cobra.Command) {
 }
 
 // runCollectionRender handles rendering for collections-based configuration.
-func runCollectionRender(ctx context.Context, cmd *cobra.Command, pp paths.ProjectPaths, cfg config.Config) error {
+func runCollectionRender(ctx context.Context, cmd *cobra.Command, pp paths.ProjectPaths, cfg config.Config, glog *slog.Logger) error {
 	if cfg.Collections == nil || len(cfg.Collections) == 0 {
 		return fmt.Errorf("no collections configured")
 	}
@@ -66,6 +67,22 @@ func runCollectionRender(ctx context.Context, cmd *cobra.Command, pp paths.Proje
 		return err
 	}
 
+	// Check every collection, not just the selected rows: a partial render
+	// would still overwrite the segment of a row it wasn't asked about.
+	allClips, err := resolver.BuildCollectionClips(collections)
+	if err != nil {
+		return err
+	}
+	applySequenceEntryOverrides(cfg, allClips)
+	if err := render.CheckOutputCollisions(cfg, pp.SegmentsDir, allClips); err != nil {
+		return err
+	}
+	// Track numbers follow the whole timeline, whatever is selected.
+	tracks, err := render.TimelineTracks(pp, cfg, collections)
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: number segment tracks: %v\n", err)
+	}
+
 	if renderCollection != "" {
 		coll, ok := collections[renderCollection]
 		if !ok {
@@ -110,11 +127,11 @@ func runCollectionRender(ctx context.Context, cmd *cobra.Command, pp paths.Proje
 		return fmt.Errorf("no clips to render in collections")
 	}
 
-	// Apply per-sequence-entry fade overrides to the specific clip ranges
-	// consumed by each timeline entry. Uses the same cursor logic as
-	// ResolveTimeline so that a collection appearing twice with different
-	// fades affects only its own portion of clips.
-	applySequenceEntryFades(cfg, collectionClips)
+	// Apply per-sequence-entry duration, fade, and overlay overrides to the
+	// specific clip ranges consumed by each timeline entry. Uses the same
+	// cursor logic as ResolveTimeline so that a collection appearing twice
+	// with different overrides affects only its own portion of clips.
+	applySequenceEntryOverrides(cfg, collectionClips)
 
 	segments := make([]render.Segment, len(collectionClips))
 	renderOrder := make([]int, 0, len(collectionClips))
@@ -122,7 +139,9 @@ func runCollectionRender(ctx context.Context, cmd *cobra.Command, pp paths.Proje
 	shouldRender := make([]bool, len(collectionClips))
 
 	for i, collClip := range collectionClips {
-		segment, err := buildCollectionRenderSegment(pp, cfg, idx, resolver, collClip)
+		segment, err := render.BuildCollectionSegment(pp, cfg, idx, collClip)
+		segment = sampleSegment(pp, collClip.CollectionName, segment)
+		segment.Tags = render.NewSegmentTags(collClip.Clip, filepath.Base(pp.Root), tracks[segment.OutputPath])
 		segments[i] = segment
 
 		if err != nil {
@@ -139,6 +158,17 @@ func runCollectionRender(ctx context.Context, cmd *cobra.Command, pp paths.Proje
 		shouldRender[i] = true
 	}
 
+	if renderEstimateOnly {
+		inputs := make([]renderEstimateInput, len(collectionClips))
+		for i, cc := range collectionClips {
+			inputs[i] = renderEstimateInput{Collection: cc.CollectionName, Segment: segments[i], Cached: shouldRender[i]}
+		}
+		return runRenderEstimate(ctx, cmd, pp, cfg, inputs)
+	}
+	if renderPrintCmd {
+		return printRenderCommands(ctx, cmd, pp, cfg, collectionClips, segments, preflight)
+	}
+
 	// Identify missing sources that can be auto-fetched (URLs only).
 	var missingIndices []int
 	for i, res := range preflight {
@@ -153,30 +183,58 @@ func runCollectionRender(ctx context.Context, cmd *cobra.Command, pp paths.Proje
 	// Create cache service if we need to auto-fetch (before TUI starts, since tool
 	// detection is slow and we don't want it to happen inside the render callback).
 	var cacheSvc *cache.Service
-	var fetchLogger *log.Logger
+	var fetchLog *slog.Logger
 	var fetchLogCloser io.Closer
 	if len(missingIndices) > 0 {
 		var logErr error
-		fetchLogger, fetchLogCloser, logErr = logx.New(pp)
+		fetchLog, fetchLogCloser, logErr = logx.Open(pp)
 		if logErr != nil {
 			return logErr
 		}
 		defer fetchLogCloser.Close()
 
 		var cacheErr error
-		cacheSvc, cacheErr = newCacheServiceWithStatus(ctx, pp, fetchLogger, nil, nil)
+		cacheSvc, cacheErr = newCacheServiceWithStatus(ctx, pp, logx.PrintLogger(fetchLog), nil, nil)
 		if cacheErr != nil {
 			return fmt.Errorf("auto-fetch: %w", cacheErr)
 		}
 	}
 
+	if !renderDryRun {
+		rs, _ := state.Load(pp.RenderStateFile)
+		progress, stopScan := newScanIndicator(cmd.ErrOrStderr())
+		est := estimateRenderSpace(cfg, rs, segments, pp.SegmentsDir, renderForce, progress)
+		stopScan()
+		if err := checkDiskSpace(cmd.ErrOrStderr(), est, renderSkipSpace); err != nil {
+			return err
+		}
+	}
+
 	svc, err := render.NewService(ctx, pp, cfg, nil)
 	if err != nil {
 		return err
 	}
 
+	var segmentHooks *segmentHookReporter
+	// Sample renders aren't the project's segments, so hooks skip them.
+	if len(cfg.Hooks.PostSegment) > 0 && !renderDryRun && renderSampleSecs == 0 {
+		hookLogger, hookLogCloser, err := logx.New(pp)
+		if err != nil {
+			return err
+		}
+		defer hookLogCloser.Close()
+		segmentHooks = newSegmentHookReporter(ctx, hooks.New(cfg.Hooks, pp.Root, hookLogger.Writer()), collectionClips)
+		// Reported once rendering is done; the progress table owns the
+		// terminal until then.
+		defer func() {
+			for _, err := range segmentHooks.failures() {
+				fmt.Fprintf(cmd.ErrOrStderr(), "warning: %v\n", err)
+			}
+		}()
+	}
+
 	outWriter := cmd.OutOrStdout()
-	mode := tui.DetectMode(outWriter, renderNoProgress, outputJSON)
+	mode := tui.DetectMode(outWriter, renderNoProgress || logx.Echoing(), outputJSON)
 
 	// In TUI mode, suppress render service stdout to avoid corrupting the display.
 	if mode != tui.ModeTUI {
@@ -202,9 +260,11 @@ func runCollectionRender(ctx context.Context, cmd *cobra.Command, pp paths.Proje
 					})
 				}
 
-				result, fetchErr := cacheSvc.Resolve(ctx, idx, row, opts)
+				rowOpts := opts
+				rowOpts.Collection = cc.CollectionName
+				result, fetchErr := cacheSvc.Resolve(ctx, idx, row, rowOpts)
 				if fetchErr != nil {
-					fetchLogger.Printf("auto-fetch collection=%s row %03d failed: %v", cc.CollectionName, row.Index, fetchErr)
+					fetchLog.Error("auto-fetch row failed", "collection", cc.CollectionName, "row", row.Index, "error", fetchErr)
 					if send != nil {
 						send(tui.RowUpdateMsg{
 							Key:    key,
@@ -220,7 +280,8 @@ func runCollectionRender(ctx context.Context, cmd *cobra.Command, pp paths.Proje
 				}
 
 				// Re-run preflight for this clip.
-				segment, buildErr := buildCollectionRenderSegment(pp, cfg, idx, resolver, cc)
+				segment, buildErr := render.BuildCollectionSegment(pp, cfg, idx, cc)
+				segment = sampleSegment(pp, cc.CollectionName, segment)
 				segments[i] = segment
 				if buildErr != nil {
 					if errors.Is(buildErr, errMissingCachedSource) {
@@ -273,12 +334,17 @@ func runCollectionRender(ctx context.Context, cmd *cobra.Command, pp paths.Proje
 
 		// Wire stored hashes into segments for service-level change detection.
 		for i := range valid {
-			if prior, ok := rs.Segments[valid[i].OutputPath]; ok {
-				valid[i].StoredHash = prior.InputHash
-			}
+			valid[i].StoredHash = rs.StoredHash(valid[i], cfg)
 		}
 
-		actions := state.DetectChanges(rs, valid, cfg, filenameTemplate, renderForce)
+		// The progress table shows the rows meanwhile; without it, say
+		// something while large projects are scanned.
+		progress, stopScan := state.ScanProgress(nil), func() {}
+		if send == nil {
+			progress, stopScan = newScanIndicator(cmd.ErrOrStderr())
+		}
+		actions := state.DetectChangesProgress(rs, valid, cfg, filenameTemplate, renderForce, progress)
+		stopScan()
 
 		var toRender []render.Segment
 		skip := make(map[string]render.Result)
@@ -308,16 +374,25 @@ func runCollectionRender(ctx context.Context, cmd *cobra.Command, pp paths.Proje
 			return buildErr
 		}
 		filenameTemplate := cfg.SegmentFilenameTemplate()
-		actions := state.DetectChanges(rs, validSegments, cfg, filenameTemplate, renderForce)
+		progress, stopScan := newScanIndicator(cmd.ErrOrStderr())
+		actions := state.DetectChangesProgress(rs, validSegments, cfg, filenameTemplate, renderForce, progress)
+		stopScan()
 		printDryRun(cmd, actions, outputJSON)
 		return nil
 	}
 
 	var fullResults []render.Result
+	var checkpointErr error
+	defer func() {
+		if fullResults != nil {
+			notifySummary = renderSummaryLine(fullResults)
+		}
+	}()
 
 	if mode == tui.ModeTUI {
 		fmt.Fprintf(outWriter, "Project: %s\n", pp.Root)
 		model := buildCollectionRenderProgressModel(pp.Root, collectionClips, segments)
+		model.SetDetail(collectionRenderDetail(pp.Root, cfg, svc, collectionClips, segments))
 
 		// Build set of fetchable indices for quick lookup.
 		fetchableSet := make(map[int]bool, len(missingIndices))
@@ -325,7 +400,14 @@ func runCollectionRender(ctx context.Context, cmd *cobra.Command, pp paths.Proje
 			fetchableSet[i] = true
 		}
 
-		err := tui.RunWithWork(outWriter, model, func(send func(tea.Msg)) {
+		// Quitting the table cancels the work, which the closures above
+		// read through ctx.
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+		workDone := make(chan struct{})
+		err := tui.RunWithWork(outWriter, model, logx.Printf(glog), func(send func(tea.Msg)) {
+			defer close(workDone)
 			// Send non-fetchable preflight errors immediately so they show
 			// as "error" rather than staying "pending" during the fetch phase.
 			for i := range collectionClips {
@@ -410,45 +492,40 @@ func runCollectionRender(ctx context.Context, cmd *cobra.Command, pp paths.Proje
 			)
 
 			var renderResults []render.Result
+			renderStarted := time.Now()
+			checkpoint := state.NewCheckpoint(rs, pp.RenderStateFile, cfg, toRender)
 			if len(toRender) > 0 {
 				renderResults = svc.Render(ctx, toRender, render.Options{
 					Concurrency: renderConcurrency,
 					Force:       renderForce,
-					Reporter:    reporter,
+					Reporter:    checkpoint.Reporter(segmentHooks.wrap(reporter)),
 				})
 			}
 
+			checkpointErr = checkpoint.Err()
 			fullResults = mergeCollectionRenderResultsWithSkips(collectionClips, preflight, shouldRender, renderResults, skipResults)
 
-			// Update render state
-			rs.GlobalConfigHash = state.GlobalConfigHash(cfg)
-			segByPath := make(map[string]render.Segment, len(validSegments))
-			for _, seg := range validSegments {
-				segByPath[seg.OutputPath] = seg
-			}
-			filenameTemplate := cfg.SegmentFilenameTemplate()
-			for _, res := range fullResults {
-				if !res.Skipped && res.Err == nil && res.OutputPath != "" {
-					if seg, ok := segByPath[res.OutputPath]; ok {
-						rs.Segments[res.OutputPath] = state.SegmentState{
-							InputHash:  state.SegmentInputHash(seg, filenameTemplate),
-							RenderedAt: time.Now(),
-							SourcePath: seg.CachedPath,
-							DurationS:  float64(seg.Clip.DurationSeconds),
-						}
-					}
-				}
-			}
+			// Rendered segments were checkpointed as they finished; the
+			// hash still needs setting when nothing rendered.
+			rs.SetGlobalConfig(cfg)
 			currentKeys := make(map[string]bool, len(validSegments))
 			for _, seg := range validSegments {
 				currentKeys[seg.OutputPath] = true
 			}
 			state.Prune(rs, currentKeys)
+			state.RecordRun(rs, state.SummarizeRun(cfg, renderStarted, renderConcurrency, renderResults))
 			_ = rs.Save(pp.RenderStateFile)
 		})
+		// Wait for ffmpeg to stop and the render state to be saved before
+		// going on.
+		cancel()
+		<-workDone
 		if err != nil {
 			return err
 		}
+		if checkpointErr != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: checkpoint render state: %v\n", checkpointErr)
+		}
 
 		printCollectionRenderSummary(outWriter, fullResults)
 	} else {
@@ -459,39 +536,30 @@ func runCollectionRender(ctx context.Context, cmd *cobra.Command, pp paths.Proje
 		}
 
 		var renderResults []render.Result
+		renderStarted := time.Now()
+		checkpoint := state.NewCheckpoint(rs, pp.RenderStateFile, cfg, toRender)
 		if len(toRender) > 0 {
 			renderResults = svc.Render(ctx, toRender, render.Options{
 				Concurrency: renderConcurrency,
 				Force:       renderForce,
+				Reporter:    checkpoint.Reporter(segmentHooks.wrap(newPipelineRenderReporter(len(toRender)))),
 			})
 		}
 
+		if err := checkpoint.Err(); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: checkpoint render state: %v\n", err)
+		}
 		fullResults = mergeCollectionRenderResultsWithSkips(collectionClips, preflight, shouldRender, renderResults, skipResults)
 
-		// Update render state
-		rs.GlobalConfigHash = state.GlobalConfigHash(cfg)
-		segByPath := make(map[string]render.Segment, len(validSegments))
-		for _, seg := range validSegments {
-			segByPath[seg.OutputPath] = seg
-		}
-		filenameTemplate := cfg.SegmentFilenameTemplate()
-		for _, res := range fullResults {
-			if !res.Skipped && res.Err == nil && res.OutputPath != "" {
-				if seg, ok := segByPath[res.OutputPath]; ok {
-					rs.Segments[res.OutputPath] = state.SegmentState{
-						InputHash:  state.SegmentInputHash(seg, filenameTemplate),
-						RenderedAt: time.Now(),
-						SourcePath: seg.CachedPath,
-						DurationS:  float64(seg.Clip.DurationSeconds),
-					}
-				}
-			}
-		}
+		// Rendered segments were checkpointed as they finished; the
+		// hash still needs setting when nothing rendered.
+		rs.SetGlobalConfig(cfg)
 		currentKeys := make(map[string]bool, len(validSegments))
 		for _, seg := range validSegments {
 			currentKeys[seg.OutputPath] = true
 		}
 		state.Prune(rs, currentKeys)
+		state.RecordRun(rs, state.SummarizeRun(cfg, renderStarted, renderConcurrency, renderResults))
 		if saveErr := rs.Save(pp.RenderStateFile); saveErr != nil {
 			return fmt.Errorf("save render state: %w", saveErr)
 		}
@@ -503,7 +571,11 @@ func runCollectionRender(ctx context.Context, cmd *cobra.Command, pp paths.Proje
 		writeCollectionRenderTable(cmd, pp.Root, collectionClips, segments, fullResults)
 	}
 
-	if err := renderInlineFiles(ctx, pp, cfg, svc, renderForce); err != nil {
+	if renderInterrupted(fullResults) {
+		return errors.New("render interrupted; run render again to finish the remaining segments")
+	}
+
+	if err := renderInlineFiles(ctx, pp, cfg, svc, renderForce, float64(renderSampleSecs)); err != nil {
 		return err
 	}
 
@@ -516,11 +588,53 @@ func runCollectionRender(ctx context.Context, cmd *cobra.Command, pp paths.Proje
 // correct timestamps and container compatibility. Uses hash-based change
 // detection: skips segments whose stored hash matches the computed hash and
 // output file exists. Render state is persisted for inline segments.
-func renderInlineFiles(ctx context.Context, pp paths.ProjectPaths, cfg config.Config, svc *render.Service, force bool) error {
+// sampleSeconds renders only the start of each file (see Segment.SampleSeconds).
+func renderInlineFiles(ctx context.Context, pp paths.ProjectPaths, cfg config.Config, svc *render.Service, force bool, sampleSeconds float64) error {
 	rs, _ := state.Load(pp.RenderStateFile)
 	filenameTemplate := cfg.SegmentFilenameTemplate()
-	var segments []render.Segment
+	segments, err := buildInlineSegments(pp, cfg, sampleSeconds)
+	if err != nil {
+		return err
+	}
+	if len(segments) == 0 {
+		return nil
+	}
+	for i := range segments {
+		segments[i].StoredHash = rs.StoredHash(segments[i], cfg)
+	}
 
+	results := svc.Render(ctx, segments, render.Options{Force: force})
+	var errs []string
+	for _, res := range results {
+		if res.Err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", filepath.Base(res.OutputPath), res.Err))
+		}
+		if !res.Skipped && res.Err == nil && res.OutputPath != "" {
+			for _, seg := range segments {
+				if seg.OutputPath == res.OutputPath {
+					rs.Segments[res.OutputPath] = state.SegmentState{
+						InputHash:    state.SegmentInputHash(seg, filenameTemplate),
+						InputParts:   state.SegmentInputParts(seg, filenameTemplate),
+						SettingsHash: state.SettingsHash(seg, cfg),
+						RenderedAt:   time.Now(),
+						SourcePath:   seg.CachedPath,
+					}
+					break
+				}
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("inline file render failed:\n  %s", strings.Join(errs, "\n  "))
+	}
+	_ = rs.Save(pp.RenderStateFile)
+	return nil
+}
+
+// buildInlineSegments returns the segments of the timeline's inline file
+// entries, in sequence order.
+func buildInlineSegments(pp paths.ProjectPaths, cfg config.Config, sampleSeconds float64) ([]render.Segment, error) {
+	var segments []render.Segment
 	for seqIdx, entry := range cfg.Timeline.Sequence {
 		if entry.File == "" {
 			continue
@@ -532,17 +646,13 @@ func renderInlineFiles(ctx context.Context, pp paths.ProjectPaths, cfg config.Co
 		}
 		if _, err := os.Stat(sourcePath); err != nil {
 			if os.IsNotExist(err) {
-				return fmt.Errorf("timeline sequence[%d] file %q: not found", seqIdx, entry.File)
+				return nil, fmt.Errorf("timeline sequence[%d] file %q: not found", seqIdx, entry.File)
 			}
-			return fmt.Errorf("timeline sequence[%d] file %q: %w", seqIdx, entry.File, err)
+			return nil, fmt.Errorf("timeline sequence[%d] file %q: %w", seqIdx, entry.File, err)
 		}
 
 		outPath := render.InlineSegmentPath(pp.SegmentsDir, seqIdx, sourcePath)
 
-		if err := os.MkdirAll(filepath.Dir(outPath), 0o755); err != nil {
-			return fmt.Errorf("create inline segments dir: %w", err)
-		}
-
 		fadeIn, fadeOut := config.ResolveFade(entry.Fade, entry.FadeIn, entry.FadeOut)
 		clip := project.Clip{
 			Sequence:       seqIdx + 1,
@@ -551,130 +661,52 @@ func renderInlineFiles(ctx context.Context, pp paths.ProjectPaths, cfg config.Co
 			SourceKind:     project.SourceKindPlan,
 			FadeInSeconds:  fadeIn,
 			FadeOutSeconds: fadeOut,
+			FadeCurve:      entry.FadeCurve,
+			AudioFadeIn:    entry.AudioFadeIn,
+			AudioFadeOut:   entry.AudioFadeOut,
 			Row: csvplan.Row{
 				Index: seqIdx + 1,
 				Link:  sourcePath,
 			},
 		}
-		seg := render.Segment{
+		segments = append(segments, render.Segment{
 			Clip:       clip,
 			Overlays:   nil,
 			SourcePath: sourcePath,
 			CachedPath: sourcePath,
 			OutputPath: outPath,
-		}
-		if prior, ok := rs.Segments[outPath]; ok {
-			seg.StoredHash = prior.InputHash
-		}
-		segments = append(segments, seg)
-	}
-
-	if len(segments) == 0 {
-		return nil
-	}
-
-	results := svc.Render(ctx, segments, render.Options{Force: force})
-	var errs []string
-	for _, res := range results {
-		if res.Err != nil {
-			errs = append(errs, fmt.Sprintf("%s: %v", filepath.Base(res.OutputPath), res.Err))
-		}
-		if !res.Skipped && res.Err == nil && res.OutputPath != "" {
-			for _, seg := range segments {
-				if seg.OutputPath == res.OutputPath {
-					rs.Segments[res.OutputPath] = state.SegmentState{
-						InputHash:  state.SegmentInputHash(seg, filenameTemplate),
-						RenderedAt: time.Now(),
-						SourcePath: seg.CachedPath,
-					}
-					break
-				}
-			}
-		}
-	}
-	if len(errs) > 0 {
-		return fmt.Errorf("inline file render failed:\n  %s", strings.Join(errs, "\n  "))
+			// Inline files aren't probed; with video.hdr they are mapped up
+			// as BT.709 so bookends match the songs.
+			Color:         render.ResolveColor(cfg, cache.Entry{}),
+			SampleSeconds: sampleSeconds,
+		})
 	}
-	_ = rs.Save(pp.RenderStateFile)
-	return nil
+	return segments, nil
 }
 
-func buildCollectionRenderSegment(pp paths.ProjectPaths, cfg config.Config, idx *cache.Index, resolver *project.CollectionResolver, collClip project.CollectionClip) (render.Segment, error) {
-	clip := collClip.Clip
-
-	clip.Row.DurationSeconds = clip.DurationSeconds
-	if clip.Row.Index <= 0 {
-		clip.Row.Index = clip.TypeIndex
-		if clip.Row.Index <= 0 {
-			clip.Row.Index = clip.Sequence
-		}
-	}
-
-	segment := render.Segment{
-		Clip:     clip,
-		Overlays: collClip.Overlays,
-	}
-
-	outputDir := collClip.OutputDir
-	if !filepath.IsAbs(outputDir) {
-		outputDir = filepath.Join(pp.SegmentsDir, outputDir)
+// sampleSegment turns segment into a sample render when render
+// --sample-duration is set. A collection with an absolute output_dir would
+// put its samples beside the real segments, so they move under the samples
+// directory instead.
+func sampleSegment(pp paths.ProjectPaths, collection string, segment render.Segment) render.Segment {
+	if renderSampleSecs <= 0 {
+		return segment
 	}
-	baseName := render.SegmentBaseName(cfg.SegmentFilenameTemplate(), segment)
-	segment.OutputPath = filepath.Join(outputDir, baseName+".mp4")
-
-	link := clip.Row.Link
-	isURL := strings.HasPrefix(link, "http://") || strings.HasPrefix(link, "https://") || strings.HasPrefix(link, "youtu")
-
-	if !isURL {
-		link = strings.Trim(link, "'\"")
-
-		var sourcePath string
-		if filepath.IsAbs(link) {
-			if _, err := os.Stat(link); err == nil {
-				sourcePath = link
-			} else {
-				sourcePath = filepath.Join(pp.Root, strings.TrimPrefix(link, string(filepath.Separator)))
-			}
-		} else {
-			sourcePath = filepath.Join(pp.Root, link)
-		}
-
-		if _, err := os.Stat(sourcePath); err != nil {
-			if os.IsNotExist(err) {
-				return segment, missingCachedSourceError{
-					msg: fmt.Sprintf("local file not found: %s", sourcePath),
-				}
-			}
-			return segment, fmt.Errorf("collection %q row %03d: stat local file: %w", collClip.CollectionName, clip.Row.Index, err)
-		}
-
-		segment.SourcePath = sourcePath
-		segment.CachedPath = sourcePath
-	} else {
-		entry, ok, err := resolveEntryForRow(pp, idx, clip.Row)
-		if err != nil {
-			return segment, err
-		}
-		if !ok {
-			return segment, missingCachedSourceError{
-				msg: "video not downloaded; may be unavailable or region-locked",
-			}
+	segment.SampleSeconds = float64(renderSampleSecs)
+	if segment.OutputPath != "" {
+		if rel, err := filepath.Rel(pp.SegmentsDir, segment.OutputPath); err != nil || strings.HasPrefix(rel, "..") {
+			segment.OutputPath = filepath.Join(pp.SegmentsDir, collection, filepath.Base(segment.OutputPath))
 		}
-
-		segment.Entry = entry
-		segment.SourcePath = entry.CachedPath
-		segment.CachedPath = entry.CachedPath
 	}
-
-	return segment, nil
+	return segment
 }
 
-// applySequenceEntryFades walks the timeline sequence with a stateful cursor
-// and applies per-entry fade overrides to the corresponding clips. This ensures
-// that a collection appearing twice with different fade values gets different
-// fades for each portion.
-func applySequenceEntryFades(cfg config.Config, clips []project.CollectionClip) {
-	project.ApplySequenceEntryFades(cfg, clips)
+// applySequenceEntryOverrides walks the timeline sequence with a stateful
+// cursor and applies per-entry overrides to the corresponding clips. This
+// ensures that a collection appearing twice with different fade values gets
+// different fades for each portion.
+func applySequenceEntryOverrides(cfg config.Config, clips []project.CollectionClip) {
+	project.ApplySequenceEntryOverrides(cfg, clips)
 }
 
 func writeCollectionRenderJSON(cmd *cobra.Command, projectRoot string, clips []project.CollectionClip, results []render.Result) error {
@@ -783,17 +815,7 @@ func writeCollectionRenderTable(cmd *cobra.Command, projectRoot string, clips []
 }
 
 func printCollectionRenderSummary(w io.Writer, results []render.Result) {
-	var rendered, skipped, failed int
-	for _, res := range results {
-		if res.Err != nil {
-			failed++
-		} else if res.Skipped {
-			skipped++
-		} else {
-			rendered++
-		}
-	}
-	fmt.Fprintf(w, "\nRendered: %d, Skipped: %d, Failed: %d\n", rendered, skipped, failed)
+	fmt.Fprintf(w, "\n%s\n", renderSummaryLine(results))
 }
 
 var collectionRenderColumns = []tui.Column{
@@ -835,6 +857,41 @@ func buildCollectionRenderProgressModel(projectRoot string, clips []project.Coll
 	return model
 }
 
+// collectionRenderDetail returns the detail pane of each render row: the
+// segment's resolved timing, source, output and encoding, and its ffmpeg log.
+func collectionRenderDetail(projectRoot string, cfg config.Config, svc *render.Service, clips []project.CollectionClip, segments []render.Segment) tui.DetailFunc {
+	byKey := make(map[string]int, len(clips))
+	for i, cc := range clips {
+		byKey[collectionRenderKey(cc)] = i
+	}
+	video := fmt.Sprintf("%dx%d@%d %s crf %d %s", cfg.Video.Width, cfg.Video.Height, cfg.Video.FPS, cfg.Video.Codec, cfg.Video.CRF, cfg.Video.Preset)
+	return func(key string) tui.Detail {
+		i, ok := byKey[key]
+		if !ok || i >= len(segments) {
+			return tui.Detail{}
+		}
+		cc, seg := clips[i], segments[i]
+		source := seg.SourcePath
+		if source == "" {
+			source = seg.CachedPath
+		}
+		return tui.Detail{
+			Params: []tui.DetailParam{
+				{Name: "title", Value: cc.Clip.Row.Title},
+				{Name: "artist", Value: cc.Clip.Row.Artist},
+				{Name: "source", Value: relPath(projectRoot, source)},
+				{Name: "start", Value: cc.Clip.Row.StartRaw},
+				{Name: "length", Value: fmt.Sprintf("%.1fs", seg.OutputSeconds())},
+				{Name: "fades", Value: fmt.Sprintf("in %.1fs, out %.1fs", cc.Clip.FadeInSeconds, cc.Clip.FadeOutSeconds)},
+				{Name: "overlays", Value: fmt.Sprintf("%d", len(seg.Overlays))},
+				{Name: "video", Value: video},
+				{Name: "output", Value: relPath(projectRoot, seg.OutputPath)},
+			},
+			LogPath: svc.LogPath(seg),
+		}
+	}
+}
+
 func collectionRenderResultFields(projectRoot string, cc project.CollectionClip, seg render.Segment, res render.Result) map[string]string {
 	fields := make(map[string]string)
 
@@ -957,6 +1014,9 @@ func printDryRun(cmd *cobra.Command, actions []state.SegmentAction, jsonOutput b
 }
 
 func collectionRenderKey(cc project.CollectionClip) string {
+	if cc.Clip.Window > 0 {
+		return fmt.Sprintf("%s:%03d.%d", cc.CollectionName, cc.Clip.Row.Index, cc.Clip.Window)
+	}
 	return fmt.Sprintf("%s:%03d", cc.CollectionName, cc.Clip.Row.Index)
 }
 
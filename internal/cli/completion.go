@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/config"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/tools"
+)
+
+// projectFlagCompletions complete flags from the project's config and plans.
+// They apply to every command that declares a flag with the name.
+var projectFlagCompletions = map[string]cobra.CompletionFunc{
+	"collection": completeCollectionNames,
+	"timeline":   completeTimelineNames,
+	"variant":    completeVariantNames,
+	"index":      completeRowIndexes,
+}
+
+// registerCompletions wires projectFlagCompletions into every command under
+// root, so `render --collection <TAB>` offers the project's collections. The
+// values are looked up when the shell asks, honouring --project.
+func registerCompletions(root *cobra.Command) {
+	root.SetCompletionCommandGroupID("manage")
+	_ = root.RegisterFlagCompletionFunc("project", completeProjectFlag)
+
+	var walk func(*cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		for name, fn := range projectFlagCompletions {
+			if cmd.LocalNonPersistentFlags().Lookup(name) != nil {
+				_ = cmd.RegisterFlagCompletionFunc(name, fn)
+			}
+		}
+		for _, child := range cmd.Commands() {
+			walk(child)
+		}
+	}
+	walk(root)
+}
+
+// loadCompletionConfig loads the project config the command would use.
+// Completion never reports errors; it just offers nothing.
+func loadCompletionConfig() (paths.ProjectPaths, config.Config, bool) {
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return pp, config.Config{}, false
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return pp, config.Config{}, false
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
+	return pp, cfg, true
+}
+
+func completeCollectionNames(_ *cobra.Command, _ []string, _ string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	_, cfg, ok := loadCompletionConfig()
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var out []cobra.Completion
+	for _, name := range slices.Sorted(maps.Keys(cfg.Collections)) {
+		coll := cfg.Collections[name]
+		desc := coll.Plan
+		if desc == "" {
+			desc = coll.File
+		}
+		out = append(out, cobra.CompletionWithDesc(name, desc))
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeTimelineNames(_ *cobra.Command, _ []string, _ string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	_, cfg, ok := loadCompletionConfig()
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return slices.Sorted(maps.Keys(cfg.Timelines)), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeVariantNames offers the variants of the default timeline, or of
+// the one named by --timeline.
+func completeVariantNames(cmd *cobra.Command, _ []string, _ string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	_, cfg, ok := loadCompletionConfig()
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	timeline := cfg.Timeline
+	if name := completionFlagValue(cmd, "timeline"); name != "" {
+		timeline = cfg.Timelines[name]
+	}
+	seen := map[string]bool{}
+	for _, entry := range timeline.Sequence {
+		for name := range entry.Variants {
+			seen[name] = true
+		}
+	}
+	return slices.Sorted(maps.Keys(seen)), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRowIndexes offers the plan indexes of the collection named by
+// --collection, or of every collection, described by title.
+func completeRowIndexes(cmd *cobra.Command, _ []string, _ string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	pp, cfg, ok := loadCompletionConfig()
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	resolver, err := project.NewCollectionResolver(cfg, pp)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	only := completionFlagValue(cmd, "collection")
+	var out []cobra.Completion
+	for _, name := range slices.Sorted(maps.Keys(collections)) {
+		if only != "" && name != only {
+			continue
+		}
+		for _, collRow := range collections[name].Rows {
+			row := collRow.ToRow()
+			desc := row.Title
+			if row.Artist != "" {
+				desc = fmt.Sprintf("%s - %s", row.Title, row.Artist)
+			}
+			if only == "" {
+				desc = name + ": " + desc
+			}
+			out = append(out, cobra.CompletionWithDesc(strconv.Itoa(row.Index), desc))
+		}
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+// completeProjectNames offers the names in the project registry.
+func completeProjectNames(_ *cobra.Command, args []string, _ string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	projects, _ := paths.LoadProjects()
+	out := make([]cobra.Completion, len(projects))
+	for i, p := range projects {
+		out[i] = cobra.CompletionWithDesc(p.Name, p.Root)
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProjectFlag offers registered project names matching what has
+// been typed, and directories otherwise.
+func completeProjectFlag(_ *cobra.Command, _ []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	projects, _ := paths.LoadProjects()
+	var out []cobra.Completion
+	for _, p := range projects {
+		if strings.HasPrefix(p.Name, toComplete) {
+			out = append(out, cobra.CompletionWithDesc(p.Name, p.Root))
+		}
+	}
+	if len(out) == 0 {
+		return nil, cobra.ShellCompDirectiveFilterDirs
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeToolNames completes the tool argument of the tools subcommands.
+func completeToolNames(_ *cobra.Command, args []string, _ string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return tools.KnownTools(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeToolNamesOrAll is completeToolNames plus "all".
+func completeToolNamesOrAll(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	names, directive := completeToolNames(cmd, args, toComplete)
+	if len(args) == 0 {
+		names = append(names, "all")
+	}
+	return names, directive
+}
+
+// completionFlagValue is the value typed so far for the named string flag,
+// or "" when the command has no such flag.
+func completionFlagValue(cmd *cobra.Command, name string) string {
+	value, err := cmd.Flags().GetString(name)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(value)
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProjectCompletions(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("POWERHOUR_LIBRARY", t.TempDir())
+	t.Cleanup(func() { projectDir = "" })
+	writeTestProjectFiles(t, dir)
+	songs := "- title: Teenagers\n  artist: MCR\n  start_time: \"0:10\"\n  duration: \"60\"\n  link: https://youtu.be/abc\n" +
+		"- title: Dammit\n  artist: Blink\n  start_time: \"0:20\"\n  duration: \"60\"\n  link: https://youtu.be/def\n"
+	if err := os.WriteFile(filepath.Join(dir, "songs.yaml"), []byte(songs), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	complete := func(args ...string) []string {
+		t.Helper()
+		root := newRootCmd()
+		var out bytes.Buffer
+		root.SetOut(&out)
+		root.SetErr(&bytes.Buffer{})
+		root.SetArgs(append([]string{"__complete", "--project", dir}, args...))
+		if err := root.Execute(); err != nil {
+			t.Fatalf("complete %v: %v", args, err)
+		}
+		var lines []string
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			if !strings.HasPrefix(line, ":") && !strings.HasPrefix(line, "Completion ended") {
+				lines = append(lines, line)
+			}
+		}
+		return lines
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"collections", []string{"render", "--collection", ""}, []string{"interstitials\tinterstitials.yaml", "songs\tsongs.yaml"}},
+		{"indexes", []string{"render", "--collection", "songs", "--index", ""}, []string{"1\tTeenagers - MCR", "2\tDammit - Blink"}},
+		{"tools", []string{"tools", "pin", ""}, nil},
+		{"devices", []string{"checklist", "--device", ""}, deviceProfileNames()},
+		{"sample overlays", []string{"sample", ""}, sampleOverlayNames},
+	}
+	for _, tt := range tests {
+		got := complete(tt.args...)
+		if tt.name == "tools" {
+			if len(got) == 0 || !strings.Contains(strings.Join(got, " "), "ffmpeg") {
+				t.Errorf("%s: got %q", tt.name, got)
+			}
+			continue
+		}
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -21,41 +21,76 @@ import (
 )
 
 var (
-	concatOut    string
-	concatDryRun bool
-	concatForce  bool
+	concatOut      string
+	concatDryRun   bool
+	concatForce    bool
+	concatVariant  string
+	concatTimeline string
+	concatSnap     bool
 )
 
 func newConcatCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "concat",
-		Short: "Concatenate rendered segments into a final video",
-		RunE:  runConcat,
+		Use:     "concat",
+		Aliases: []string{"assemble"},
+		Short:   "Concatenate rendered segments into a final video",
+		Long: `Concatenate rendered segments into a final video in timeline order.
+
+With --timeline, a named timeline from the timelines: section is assembled
+from its own segments directory into powerhour-<name>.mp4.
+
+With --variant, timeline entries that define that variant are swapped for
+their replacement collection or file before assembly. All other segments are
+shared with the default build and are not re-rendered.
+
+With --snap, every segment is trimmed or padded by a few frames to its
+planned length, so each song starts exactly on its minute however the
+individual encodes rounded. This always re-encodes.`,
+		RunE: withTelemetry("concat", withNotify("concat", runConcat)),
 	}
 
-	cmd.Flags().StringVar(&concatOut, "out", "", "Output file path (default: <project>/powerhour.mp4)")
+	cmd.Flags().StringVar(&concatOut, "out", "", "Output file path (default: <project>/powerhour.mp4, or powerhour-<timeline>-<variant>.mp4)")
 	cmd.Flags().BoolVar(&concatDryRun, "dry-run", false, "Print the resolved segment list without running ffmpeg")
 	cmd.Flags().BoolVar(&concatForce, "force", false, "Re-render inline file segments even if they already exist")
+	cmd.Flags().StringVar(&concatVariant, "variant", "", "Assemble an alternate version using the timeline entries' variants")
+	cmd.Flags().StringVar(&concatTimeline, "timeline", "", "Assemble a named timeline from timelines: instead of the default timeline")
+	cmd.Flags().BoolVar(&concatSnap, "snap", false, "Trim or pad each segment to its planned length so every segment starts on the grid (re-encodes)")
 
 	return cmd
 }
 
 func runConcat(cmd *cobra.Command, _ []string) error {
-	glogf, gcloser := logx.StartCommand("concat")
+	glog, gcloser := logx.Command("concat")
 	defer gcloser.Close()
-	glogf("concat started")
+	glog.Info("concat started")
 
 	pp, err := paths.Resolve(projectDir)
 	if err != nil {
 		return err
 	}
-	glogf("project resolved: %s", pp.Root)
+	glog.Debug("project resolved", "root", pp.Root)
 
 	cfg, err := config.Load(pp.ConfigFile)
 	if err != nil {
 		return err
 	}
-	glogf("config loaded")
+	glog.Debug("config loaded")
+
+	cfg, pp, err = applyNamedTimeline(cfg, pp, concatTimeline)
+	if err != nil {
+		return err
+	}
+	if concatTimeline != "" {
+		glog.Info("timeline selected", "timeline", concatTimeline)
+	}
+
+	if concatVariant != "" {
+		cfg.Timeline, err = cfg.Timeline.WithVariant(concatVariant)
+		if err != nil {
+			return err
+		}
+		glog.Info("variant applied", "variant", concatVariant)
+	}
 
 	outWriter := cmd.OutOrStdout()
 	sw := tui.NewStatusWriter(outWriter)
@@ -97,7 +132,7 @@ func runConcat(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	glogf("resolved %d segments", len(segments))
+	glog.Debug("segments resolved", "count", len(segments))
 
 	if len(segments) == 0 {
 		return fmt.Errorf("no segments found; run `powerhour render` first")
@@ -106,12 +141,12 @@ func runConcat(cmd *cobra.Command, _ []string) error {
 	// Check for missing or stale segments and auto-render if needed.
 	if !concatDryRun && hasMissingSegments(segments) {
 		sw.Update("Rendering missing segments...")
-		glogf("auto-render: missing segments detected, triggering render")
+		glog.Info("auto-render: missing segments detected, triggering render")
 		savedConcurrency := renderConcurrency
 		savedNoProgress := renderNoProgress
 		renderConcurrency = runtime.NumCPU()
 		renderNoProgress = true
-		renderErr := runCollectionRender(ctx, cmd, pp, cfg)
+		renderErr := runCollectionRender(ctx, cmd, pp, cfg, glog)
 		renderConcurrency = savedConcurrency
 		renderNoProgress = savedNoProgress
 		if renderErr != nil {
@@ -124,6 +159,12 @@ func runConcat(cmd *cobra.Command, _ []string) error {
 
 	if concatDryRun {
 		sw.Stop()
+		if concatTimeline != "" {
+			fmt.Fprintf(outWriter, "Timeline: %s\n", concatTimeline)
+		}
+		if concatVariant != "" {
+			fmt.Fprintf(outWriter, "Variant: %s\n", concatVariant)
+		}
 		fmt.Fprintf(outWriter, "Segment order (%d clips):\n", len(segments))
 		for i, seg := range segments {
 			rel, rerr := filepath.Rel(pp.Root, seg.Path)
@@ -147,7 +188,7 @@ func runConcat(cmd *cobra.Command, _ []string) error {
 			if err != nil {
 				return fmt.Errorf("init render service: %w", err)
 			}
-			if err := renderInlineFiles(ctx2, pp, cfg, svc, concatForce); err != nil {
+			if err := renderInlineFiles(ctx2, pp, cfg, svc, concatForce, 0); err != nil {
 				return err
 			}
 			break
@@ -161,14 +202,25 @@ func runConcat(cmd *cobra.Command, _ []string) error {
 
 	// Write the concat list.
 	sw.Update("Writing concat list...")
-	if err := render.WriteConcatList(pp.ConcatListFile, segments); err != nil {
+	if concatSnap {
+		clips, err := resolver.BuildCollectionClips(collections)
+		if err != nil {
+			return err
+		}
+		applySequenceEntryOverrides(cfg, clips)
+		planned := plannedSegmentSeconds(segments, clips)
+		if err := render.WriteGridConcatList(pp.ConcatListFile, segments, planned, cfg.Video.FPS); err != nil {
+			return err
+		}
+		glog.Debug("snapping segments to the grid", "fps", cfg.Video.FPS)
+	} else if err := render.WriteConcatList(pp.ConcatListFile, segments); err != nil {
 		return err
 	}
 
 	// Determine output path.
 	outputPath := concatOut
 	if outputPath == "" {
-		outputPath = filepath.Join(pp.Root, "powerhour"+containerExt(enc.Container))
+		outputPath = filepath.Join(pp.Root, concatOutputBase(concatTimeline, concatVariant)+containerExt(enc.Container))
 	}
 	if !filepath.IsAbs(outputPath) {
 		outputPath = filepath.Join(pp.Root, outputPath)
@@ -176,13 +228,32 @@ func runConcat(cmd *cobra.Command, _ []string) error {
 
 	sw.Update(fmt.Sprintf("Concatenating %d segments → %s", len(segments), filepath.Base(outputPath)))
 
-	result, err := render.RunConcat(ctx, pp.ConcatListFile, outputPath, enc, os.Stdout, os.Stderr)
+	var result render.ConcatResult
+	if concatSnap {
+		result, err = render.RunGridConcat(ctx, pp.ConcatListFile, outputPath, enc, cfg.Video.FPS, cmd.OutOrStdout(), cmd.ErrOrStderr())
+	} else {
+		result, err = render.RunConcat(ctx, pp.ConcatListFile, outputPath, enc, cmd.OutOrStdout(), cmd.ErrOrStderr())
+	}
 	if err != nil {
+		glog.Error("concat failed", "error", err)
 		return err
 	}
 
 	sw.Stop()
-	glogf("concat finished: %s (method=%s)", result.OutputPath, result.Method)
+	glog.Info("concat finished", "output", result.OutputPath, "method", result.Method)
+	notifySummary = fmt.Sprintf("Wrote %s from %d segments", filepath.Base(result.OutputPath), len(segments))
+
+	if len(cfg.Hooks.PostConcat) > 0 {
+		if err := runPostConcatHooks(ctx, pp, cfg, postConcatPayload{
+			Output:   result.OutputPath,
+			Method:   result.Method,
+			Segments: len(segments),
+			Timeline: concatTimeline,
+			Variant:  concatVariant,
+		}); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: %v\n", err)
+		}
+	}
 
 	// Report result.
 	info, statErr := os.Stat(result.OutputPath)
@@ -226,7 +297,13 @@ func resolveEncodingForConcat(ctx context.Context, cfg config.Config) (tools.Res
 	}
 
 	global := tools.LoadEncodingDefaults()
-	return tools.ResolveEncoding(profile, global, encodingConfigToDefaults(cfg.Encoding)), nil
+	enc := tools.ResolveEncoding(profile, global, encodingConfigToDefaults(cfg.Encoding))
+	if cfg.Video.HDRMode() != "" {
+		// Re-encodes keep the HDR segments' encoder, bit depth and tags.
+		enc.VideoCodec = cfg.Video.Codec
+		enc.VideoArgs = render.HDREncodeArgs(cfg)
+	}
+	return enc, nil
 }
 
 // encodingConfigToDefaults converts a project EncodingConfig to the tools
@@ -294,3 +371,39 @@ func formatBytes(n int64) string {
 	}
 	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
 }
+
+// concatOutputBase is the default output filename (without extension);
+// named timelines and variant builds get their own file so they don't
+// overwrite the default.
+func concatOutputBase(timeline, variant string) string {
+	base := "powerhour"
+	for _, part := range []string{timeline, variant} {
+		if part != "" {
+			base += "-" + part
+		}
+	}
+	return base
+}
+
+// clipsBySegment indexes clips by subtitleClipKey, so a timeline segment can
+// be matched to the clip it renders.
+func clipsBySegment(clips []project.CollectionClip) map[string]project.Clip {
+	byKey := make(map[string]project.Clip, len(clips))
+	for _, cc := range clips {
+		byKey[subtitleClipKey(cc.CollectionName, cc.Clip.Row.Index, cc.Clip.Window)] = cc.Clip
+	}
+	return byKey
+}
+
+// plannedSegmentSeconds returns each segment's planned length: its clip's
+// duration plus preroll and postroll. Inline files have no clip and get 0.
+func plannedSegmentSeconds(segments []render.TimelineSegmentPath, clips []project.CollectionClip) []float64 {
+	byKey := clipsBySegment(clips)
+	planned := make([]float64, len(segments))
+	for i, seg := range segments {
+		if clip, ok := byKey[subtitleClipKey(seg.CollectionName, seg.Index, seg.Window)]; ok {
+			planned[i] = clip.OutputSeconds()
+		}
+	}
+	return planned
+}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,7 +10,9 @@ import (
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 
+	"powerhour/internal/config"
 	"powerhour/internal/logx"
+	"powerhour/internal/paths"
 	"powerhour/pkg/csvplan"
 )
 
@@ -19,13 +22,25 @@ func newConvertCmd() *cobra.Command {
 		linkHeader     string
 		startHeader    string
 		durationHeader string
+		collection     string
+		sheet          string
+		strict         bool
 		dryRun         bool
 	)
 
 	cmd := &cobra.Command{
-		Use:   "convert <input.csv>",
-		Short: "Convert a CSV/TSV plan file to YAML format",
-		Args:  cobra.ExactArgs(1),
+		Use:   "convert <input.csv|input.xlsx>",
+		Short: "Convert a CSV/TSV plan or an Excel sheet to YAML format",
+		Long: `Convert a CSV/TSV plan file, or one sheet of an .xlsx workbook, to YAML
+format. --sheet picks the worksheet; the default is the first.
+
+Columns are matched by header name, and the link and start columns are
+re-detected from their contents when the header is missing or wrong. With
+--strict (or --collection pointing at a collection with strict_headers: true)
+no guessing happens: a header row is required and missing link/start columns
+fail with a diagnostic. --collection also uses that collection's
+link_header, start_header, duration_header, duration and sheet.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			glogf, gcloser := logx.StartCommand("convert")
 			defer gcloser.Close()
@@ -37,6 +52,14 @@ func newConvertCmd() *cobra.Command {
 				LinkHeader:     linkHeader,
 				StartHeader:    startHeader,
 				DurationHeader: durationHeader,
+				StrictHeaders:  strict,
+				Sheet:          sheet,
+			}
+			if collection != "" {
+				if err := applyConvertCollection(&opts, collection); err != nil {
+					return err
+				}
+				glogf("convert: using collection %s (strict=%v)", collection, opts.StrictHeaders)
 			}
 
 			rows, err := csvplan.ImportFromCSV(input, opts)
@@ -59,7 +82,7 @@ func newConvertCmd() *cobra.Command {
 				out = filepath.Join(filepath.Dir(input), base+".yaml")
 			}
 
-			if err := writeConvertYAML(out, rows); err != nil {
+			if err := writeConvertPlan(out, rows); err != nil {
 				return err
 			}
 
@@ -68,15 +91,53 @@ func newConvertCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "Output YAML path (default: <input-basename>.yaml)")
+	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "Output path; a .json path writes JSON (default: <input-basename>.yaml)")
 	cmd.Flags().StringVar(&linkHeader, "link", "", "Column name for the URL field (default: auto-detect)")
 	cmd.Flags().StringVar(&startHeader, "start", "", "Column name for the start time field (default: auto-detect)")
 	cmd.Flags().StringVar(&durationHeader, "duration", "", "Column name for the duration field (default: auto-detect)")
+	cmd.Flags().StringVar(&collection, "collection", "", "Use a project collection's header names and strict_headers setting")
+	cmd.Flags().StringVar(&sheet, "sheet", "", "Worksheet to read from an .xlsx input (default: the first)")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Require exact headers; fail instead of guessing link/start columns")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print detected column mapping and sample rows without writing")
 
 	return cmd
 }
 
+// applyConvertCollection fills header names and the sheet not given on the
+// command line from the named collection and turns on strict mode when the
+// collection sets strict_headers.
+func applyConvertCollection(opts *csvplan.ImportOptions, name string) error {
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	collCfg, ok := cfg.Collections[name]
+	if !ok {
+		return fmt.Errorf("collection %q not found in configuration", name)
+	}
+	if opts.LinkHeader == "" {
+		opts.LinkHeader = collCfg.LinkHeader
+	}
+	if opts.StartHeader == "" {
+		opts.StartHeader = collCfg.StartHeader
+	}
+	if opts.DurationHeader == "" {
+		opts.DurationHeader = collCfg.DurationHeader
+	}
+	if opts.Sheet == "" {
+		opts.Sheet = collCfg.Sheet
+	}
+	if collCfg.Duration > 0 {
+		opts.DefaultDuration = collCfg.Duration
+	}
+	opts.StrictHeaders = opts.StrictHeaders || collCfg.StrictHeaders
+	return nil
+}
+
 // printConvertDryRun prints a preview of detected columns and sample rows.
 func printConvertDryRun(cmd *cobra.Command, rows []csvplan.CollectionRow) {
 	if len(rows) == 0 {
@@ -110,8 +171,9 @@ func printConvertDryRun(cmd *cobra.Command, rows []csvplan.CollectionRow) {
 	}
 }
 
-// writeConvertYAML marshals rows as a YAML list to path.
-func writeConvertYAML(path string, rows []csvplan.CollectionRow) error {
+// writeConvertPlan marshals rows as a YAML list to path, or as a JSON list
+// when path ends in .json.
+func writeConvertPlan(path string, rows []csvplan.CollectionRow) error {
 	items := make([]map[string]string, 0, len(rows))
 	for _, row := range rows {
 		m := make(map[string]string, len(row.CustomFields))
@@ -128,9 +190,18 @@ func writeConvertYAML(path string, rows []csvplan.CollectionRow) error {
 		items = append(items, m)
 	}
 
-	data, err := yaml.Marshal(items)
+	var (
+		data []byte
+		err  error
+	)
+	if csvplan.DetectPlanFormat(path) == csvplan.PlanFormatJSON {
+		data, err = json.MarshalIndent(items, "", "  ")
+		data = append(data, '\n')
+	} else {
+		data, err = yaml.Marshal(items)
+	}
 	if err != nil {
-		return fmt.Errorf("marshal YAML: %w", err)
+		return fmt.Errorf("marshal plan: %w", err)
 	}
 
 	if err := os.WriteFile(path, data, 0o644); err != nil {
@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	xterm "github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/diagnostics"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/render"
+	"powerhour/internal/tools"
+)
+
+var (
+	debugBundleOutput string
+	// inDebugBundle is set while debug bundle runs, so its own failure
+	// doesn't replace the one being reported.
+	inDebugBundle bool
+)
+
+func newDebugCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "debug",
+		Short: "Collect diagnostics for bug reports",
+	}
+	cmd.AddCommand(newDebugBundleCmd())
+	return cmd
+}
+
+func newDebugBundleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "Write a diagnostics zip to attach to a bug report",
+		Long: `Writes a zip with the project and global configs (credentials redacted),
+the newest project and global logs, tool versions, OS info and the last
+failed command, including the stack of a crash.
+
+Secret values referenced from the configs are scrubbed from every file.
+Look the zip over before attaching it anywhere public.`,
+		Args: cobra.NoArgs,
+		RunE: runDebugBundle,
+	}
+	cmd.Flags().StringVarP(&debugBundleOutput, "output", "o", "", "Zip to write (default: powerhour-diagnostics-<timestamp>.zip in the working directory)")
+	return cmd
+}
+
+type debugBundleResult struct {
+	Path  string   `json:"path"`
+	Files []string `json:"files"`
+}
+
+func runDebugBundle(cmd *cobra.Command, _ []string) error {
+	inDebugBundle = true
+	glog, gcloser := logx.Command("debug-bundle")
+	glogf := logx.Printf(glog)
+	defer gcloser.Close()
+
+	dest := debugBundleOutput
+	if dest == "" {
+		dest = defaultDebugBundleName(time.Now())
+	}
+	files, err := writeDebugBundle(cmd.Context(), dest)
+	if err != nil {
+		glog.Error("debug bundle failed", "error", err)
+		return err
+	}
+	glogf("debug bundle written: %s (%d files)", dest, len(files))
+
+	out := cmd.OutOrStdout()
+	if outputJSON {
+		data, err := json.MarshalIndent(debugBundleResult{Path: dest, Files: files}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+	fmt.Fprintf(out, "Wrote %s (%d files)\n", dest, len(files))
+	fmt.Fprintln(out, "Look it over before attaching it to a bug report.")
+	return nil
+}
+
+func defaultDebugBundleName(now time.Time) string {
+	return "powerhour-diagnostics-" + now.Format("20060102-150405") + ".zip"
+}
+
+// writeDebugBundle gathers the project (when there is one), global logs,
+// tools and last failure into dest.
+func writeDebugBundle(ctx context.Context, dest string) ([]string, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var in diagnostics.Input
+	if pp, err := paths.Resolve(projectDir); err == nil {
+		if exists, _ := paths.FileExists(pp.ConfigFile); exists {
+			in.ProjectConfig = pp.ConfigFile
+			in.ProjectLogsDir = pp.LogsDir
+		}
+	}
+	if global, err := paths.GlobalConfigFile(); err == nil {
+		in.GlobalConfig = global
+	}
+	if logsDir, err := paths.GlobalLogsDir(); err == nil {
+		in.GlobalLogsDir = logsDir
+	}
+	// Tool detection can fail on a broken install, which is worth a
+	// bundle all the same.
+	in.Tools, _ = tools.Detect(ctx)
+	in.Failure, _ = diagnostics.LastFailure()
+	return diagnostics.Write(dest, in)
+}
+
+// recordFailure saves a failed command for debug bundle. Failures of debug
+// bundle itself are not recorded, so the report keeps the original one.
+func recordFailure(args []string, err error, panicked bool, stack []byte) {
+	if inDebugBundle {
+		return
+	}
+	dir, _ := os.Getwd()
+	_ = diagnostics.RecordFailure(diagnostics.Failure{
+		Time:  time.Now().UTC(),
+		Args:  redactArgs(args),
+		Dir:   dir,
+		Error: err.Error(),
+		Panic: panicked,
+		Stack: string(stack),
+	})
+}
+
+// credentialFlags are flags whose values are access tokens.
+var credentialFlags = []string{"--token"}
+
+// redactArgs masks what a command line can carry besides ${NAME} secrets,
+// which RecordFailure already scrubs: credential flag values, and the key
+// and query of URLs such as a stream ingest URL.
+func redactArgs(args []string) []string {
+	out := make([]string, len(args))
+	maskNext := false
+	for i, arg := range args {
+		switch {
+		case maskNext:
+			arg = "****"
+			maskNext = false
+		case strings.HasPrefix(arg, "-"):
+			for _, flag := range credentialFlags {
+				if arg == flag {
+					maskNext = true
+				} else if strings.HasPrefix(arg, flag+"=") {
+					arg = flag + "=****"
+				}
+			}
+		case strings.Contains(arg, "://"):
+			arg = render.RedactStreamURL(arg)
+		}
+		out[i] = arg
+	}
+	return out
+}
+
+// reportCrash records a panic and offers to write the diagnostics bundle
+// straight away when someone is at the terminal.
+func reportCrash(args []string, recovered any, errOut io.Writer) {
+	err := fmt.Errorf("panic: %v", recovered)
+	recordFailure(args, err, true, debug.Stack())
+	fmt.Fprintf(errOut, "powerhour crashed: %v\n", recovered)
+	if outputJSON || !xterm.IsTerminal(os.Stdin.Fd()) || !xterm.IsTerminal(os.Stderr.Fd()) {
+		fmt.Fprintln(errOut, "Run `powerhour debug bundle` and attach the zip to a bug report.")
+		return
+	}
+	ask := newPrompter(os.Stdin, errOut)
+	if !ask.confirm("Write a diagnostics bundle to attach to a bug report?", true) {
+		return
+	}
+	dest, _ := filepath.Abs(defaultDebugBundleName(time.Now()))
+	if _, err := writeDebugBundle(context.Background(), dest); err != nil {
+		fmt.Fprintf(errOut, "error: %v\n", err)
+		return
+	}
+	fmt.Fprintf(errOut, "Wrote %s\n", dest)
+}
+
+// failureHint is the line printed under a command's error on a terminal,
+// pointing at debug bundle; --quiet and --json leave it out.
+func failureHint() string {
+	if logQuiet || outputJSON || inDebugBundle || !xterm.IsTerminal(os.Stderr.Fd()) {
+		return ""
+	}
+	return "If this looks like a bug, run `powerhour debug bundle` and attach the zip to the report."
+}
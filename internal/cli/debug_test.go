@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRedactArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"serve", "--token", "s3cret"}, []string{"serve", "--token", "****"}},
+		{[]string{"party", "--token=s3cret", "--segments"}, []string{"party", "--token=****", "--segments"}},
+		{[]string{"stream", "rtmp://live.example.com/app/live_123abc"}, []string{"stream", "rtmp://live.example.com/app/****"}},
+		{[]string{"render", "--index", "3"}, []string{"render", "--index", "3"}},
+	}
+	for _, tt := range tests {
+		if got := redactArgs(tt.args); !slices.Equal(got, tt.want) {
+			t.Errorf("redactArgs(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/config"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+	"powerhour/internal/render/state"
+)
+
+var (
+	diffCollection string
+	diffAll        bool
+)
+
+// diffReasonUncached marks a segment whose source has to be fetched first;
+// render fetches it and renders it.
+const diffReasonUncached = "source not cached"
+
+func newDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Explain which segments the next render would redo, and why",
+		Long: `Compare the current plan and config against the render state and list the
+segments the next render would redo, with what changed for each: render
+settings (video, audio, encoding), source link, start time or duration,
+title and plan fields, overlays, fades, crop, or the segment filename
+template. Nothing is rendered or written.
+
+Segments last rendered before this command existed only record that
+something changed; render them once to get the details next time.`,
+		Example: `  powerhour diff
+  powerhour diff --collection songs --all
+  powerhour diff --json`,
+		Args: cobra.NoArgs,
+		RunE: runDiff,
+	}
+	cmd.Flags().StringVar(&diffCollection, "collection", "", "Only list segments of this collection")
+	cmd.Flags().BoolVar(&diffAll, "all", false, "List up-to-date segments too")
+	return cmd
+}
+
+type diffSegment struct {
+	Collection string   `json:"collection"`
+	Index      int      `json:"index"`
+	Window     int      `json:"window,omitempty"`
+	Title      string   `json:"title"`
+	Output     string   `json:"output"`
+	Action     string   `json:"action"`
+	Reason     string   `json:"reason"`
+	Changes    []string `json:"changes,omitempty"`
+}
+
+type diffReport struct {
+	GlobalChanges []string      `json:"global_changes,omitempty"`
+	Render        int           `json:"render"`
+	Skip          int           `json:"skip"`
+	Segments      []diffSegment `json:"segments"`
+}
+
+func runDiff(cmd *cobra.Command, _ []string) error {
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
+
+	resolver, err := project.NewCollectionResolver(cfg, pp)
+	if err != nil {
+		return err
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		return err
+	}
+	if diffCollection != "" {
+		if _, ok := collections[diffCollection]; !ok {
+			return fmt.Errorf("collection %q not found in configuration", diffCollection)
+		}
+	}
+	idx, err := cache.Load(pp)
+	if err != nil {
+		return err
+	}
+	rs, err := state.Load(pp.RenderStateFile)
+	if err != nil {
+		return fmt.Errorf("load render state: %w", err)
+	}
+
+	clips, err := resolver.BuildCollectionClips(collections)
+	if err != nil {
+		return err
+	}
+	applySequenceEntryOverrides(cfg, clips)
+	report, err := buildDiffReport(pp, cfg, idx, rs, clips, diffCollection)
+	if err != nil {
+		return err
+	}
+
+	if outputJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+	printDiffReport(cmd, pp, report, diffAll)
+	return nil
+}
+
+// buildDiffReport explains the render action of every clip's segment, or
+// only those of collection when it is set. Clips whose source isn't cached
+// are listed as renders without comparing inputs.
+func buildDiffReport(pp paths.ProjectPaths, cfg config.Config, idx *cache.Index, rs *state.RenderState, clips []project.CollectionClip, collection string) (diffReport, error) {
+	report := diffReport{GlobalChanges: state.ChangedGlobalParts(rs, cfg)}
+	var (
+		valid   []render.Segment
+		validAt []int
+	)
+	for _, cc := range clips {
+		if collection != "" && cc.CollectionName != collection {
+			continue
+		}
+		seg, err := render.BuildCollectionSegment(pp, cfg, idx, cc)
+		entry := diffSegment{
+			Collection: cc.CollectionName,
+			Index:      cc.Clip.Row.Index,
+			Window:     cc.Clip.Window,
+			Title:      clipDisplayTitle(cc.Clip),
+			Output:     seg.OutputPath,
+			Action:     state.ActionRender,
+		}
+		switch {
+		case errors.Is(err, errMissingCachedSource):
+			entry.Reason = diffReasonUncached
+		case err != nil:
+			return diffReport{}, err
+		default:
+			valid = append(valid, seg)
+			validAt = append(validAt, len(report.Segments))
+		}
+		report.Segments = append(report.Segments, entry)
+	}
+
+	for i, c := range state.ExplainChanges(rs, valid, cfg, cfg.SegmentFilenameTemplate()) {
+		entry := &report.Segments[validAt[i]]
+		entry.Action, entry.Reason, entry.Changes = c.Action, c.Reason, c.Changes
+	}
+	for _, s := range report.Segments {
+		if s.Action == state.ActionRender {
+			report.Render++
+		} else {
+			report.Skip++
+		}
+	}
+	return report, nil
+}
+
+func printDiffReport(cmd *cobra.Command, pp paths.ProjectPaths, report diffReport, all bool) {
+	out := cmd.OutOrStdout()
+	if len(report.GlobalChanges) > 0 {
+		fmt.Fprintf(out, "Render settings changed since the last render: %s.\n\n", describeChanges(report.GlobalChanges))
+	}
+
+	counts := make(map[string]int)
+	listed := 0
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, s := range report.Segments {
+		if s.Action == state.ActionSkip && !all {
+			continue
+		}
+		tag := "SKIP"
+		if s.Action == state.ActionRender {
+			tag = "RENDER"
+		}
+		label := fmt.Sprintf("%s #%03d", s.Collection, s.Index)
+		if s.Window > 0 {
+			label += fmt.Sprintf(".%d", s.Window)
+		}
+		why := s.Reason
+		if len(s.Changes) > 0 {
+			why = describeChanges(s.Changes)
+		}
+		for _, c := range s.Changes {
+			counts[c]++
+		}
+		listed++
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", tag, label, s.Title, why, relPath(pp.Root, s.Output))
+	}
+	tw.Flush()
+
+	total := len(report.Segments)
+	if report.Render == 0 && listed == 0 {
+		fmt.Fprintf(out, "All %d segments are up to date.\n", total)
+		return
+	}
+	if listed > 0 {
+		fmt.Fprintln(out)
+	}
+	fmt.Fprintf(out, "%d of %d segments would re-render.\n", report.Render, total)
+	if len(counts) == 0 {
+		return
+	}
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	fmt.Fprintln(out, "Changed inputs:")
+	for _, name := range names {
+		fmt.Fprintf(out, "  %-40s %d\n", state.DescribeChange(name), counts[name])
+	}
+}
+
+func describeChanges(names []string) string {
+	labels := make([]string, len(names))
+	for i, name := range names {
+		labels[i] = state.DescribeChange(name)
+	}
+	return strings.Join(labels, "; ")
+}
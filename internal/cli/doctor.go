@@ -3,7 +3,8 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
-	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
@@ -76,6 +77,9 @@ func runDoctor(cmd *cobra.Command, _ []string) error {
 			if loadErr == nil && len(collections) > 0 {
 				checks = append(checks, checkSources(pp, collections))
 				checks = append(checks, checkSegments(pp, cfg, resolver, collections))
+				if check, ok := checkFacts(collections); ok {
+					checks = append(checks, check)
+				}
 			}
 		}
 	}
@@ -200,10 +204,13 @@ func checkSources(pp paths.ProjectPaths, collections map[string]project.Collecti
 
 	var total, cached int
 	for _, coll := range collections {
+		if coll.Config.IsGenerator() {
+			continue
+		}
 		for _, row := range coll.Rows {
 			total++
 			r := row.ToRow()
-			_, ok, err := resolveEntryForRow(pp, idx, r)
+			_, ok, err := render.ResolveEntry(pp, idx, r)
 			if err == nil && ok {
 				cached++
 			}
@@ -231,6 +238,13 @@ func checkSegments(pp paths.ProjectPaths, cfg config.Config, resolver *project.C
 	if err != nil {
 		return healthCheck{Name: "Segments", Status: "error", Summary: err.Error()}
 	}
+	if collisions := render.FindOutputCollisions(cfg, pp.SegmentsDir, clips); len(collisions) > 0 {
+		return healthCheck{
+			Name:    "Segments",
+			Status:  "error",
+			Summary: fmt.Sprintf("%d segment paths shared by more than one row; run render for details", len(collisions)),
+		}
+	}
 
 	tmpl := cfg.SegmentFilenameTemplate()
 	var segments []render.Segment
@@ -249,11 +263,7 @@ func checkSegments(pp paths.ProjectPaths, cfg config.Config, resolver *project.C
 			Overlays: collClip.Overlays,
 		}
 
-		outputDir := collClip.OutputDir
-		if !filepath.IsAbs(outputDir) {
-			outputDir = filepath.Join(pp.SegmentsDir, outputDir)
-		}
-		seg.OutputPath = filepath.Join(outputDir, render.SegmentBaseName(tmpl, seg)+".mp4")
+		seg.OutputPath = render.CollectionSegmentPath(cfg, pp.SegmentsDir, collClip.CollectionName, collClip.OutputDir, seg)
 		segments = append(segments, seg)
 	}
 
@@ -291,11 +301,52 @@ func checkSegments(pp paths.ProjectPaths, cfg config.Config, resolver *project.C
 	}
 }
 
+// checkFacts flags facts file keys that match no plan row, usually a row
+// that moved or a link that changed. ok is false when no collection has a
+// facts file.
+func checkFacts(collections map[string]project.Collection) (check healthCheck, ok bool) {
+	names := make([]string, 0, len(collections))
+	for name := range collections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var (
+		entries int
+		orphans []string
+	)
+	for _, name := range names {
+		coll := collections[name]
+		if strings.TrimSpace(coll.Config.Facts) == "" {
+			continue
+		}
+		ok = true
+		entries += len(coll.Facts)
+		for _, key := range coll.OrphanedFactKeys() {
+			orphans = append(orphans, name+":"+key)
+		}
+	}
+	if len(orphans) > 0 {
+		return healthCheck{Name: "Facts", Status: "warning", Summary: fmt.Sprintf(
+			"%d of %d entries match no plan row: %s", len(orphans), entries, joinComma(orphans))}, ok
+	}
+	return healthCheck{Name: "Facts", Status: "ok", Summary: fmt.Sprintf("%d entries", entries)}, ok
+}
+
 func checkTimeline(cfg config.Config, collections map[string]project.Collection) healthCheck {
 	entries, err := project.ResolveTimeline(cfg.Timeline, collections)
 	if err != nil {
 		return healthCheck{Name: "Timeline", Status: "error", Summary: err.Error()}
 	}
+	budget, err := project.TimelineRuntime(cfg.Timeline, collections)
+	if err != nil {
+		return healthCheck{Name: "Timeline", Status: "error", Summary: err.Error()}
+	}
+	if budget.OffTarget() {
+		return healthCheck{Name: "Timeline", Status: "warning", Summary: fmt.Sprintf(
+			"%d entries, projected %s vs target %s", len(entries),
+			formatSampleTime(budget.ProjectedSeconds), formatSampleTime(budget.TargetSeconds))}
+	}
 	return healthCheck{Name: "Timeline", Status: "ok", Summary: fmt.Sprintf("%d entries", len(entries))}
 }
 
@@ -6,6 +6,8 @@ import (
 
 	"powerhour/internal/config"
 	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/pkg/csvplan"
 )
 
 func TestJoinComma(t *testing.T) {
@@ -49,3 +51,27 @@ func TestCheckConfigValid(t *testing.T) {
 		t.Errorf("got status=%q, want ok", result.Status)
 	}
 }
+
+func TestCheckFacts(t *testing.T) {
+	songs := project.Collection{
+		Config: config.CollectionConfig{Facts: "facts.yaml"},
+		Rows:   []csvplan.CollectionRow{{Index: 1, Link: "https://youtu.be/aaa"}},
+		Facts: map[string]project.RowFacts{
+			"1":                    {Trivia: "a"},
+			"https://youtu.be/aaa": {Dare: "b"},
+			"9":                    {Trivia: "c"},
+		},
+	}
+
+	if _, ok := checkFacts(map[string]project.Collection{"songs": {}}); ok {
+		t.Error("checkFacts reported without a facts file")
+	}
+	result, ok := checkFacts(map[string]project.Collection{"songs": songs})
+	if !ok || result.Status != "warning" || result.Summary != "1 of 3 entries match no plan row: songs:9" {
+		t.Errorf("got %+v (ok=%t), want a warning naming songs:9", result, ok)
+	}
+	delete(songs.Facts, "9")
+	if result, _ := checkFacts(map[string]project.Collection{"songs": songs}); result.Status != "ok" {
+		t.Errorf("got status=%q, want ok", result.Status)
+	}
+}
@@ -22,6 +22,13 @@ func newExportCmd() *cobra.Command {
 	}
 
 	cmd.Flags().BoolVar(&exportTimeline, "timeline", false, "Include resolved timeline in output")
+	cmd.Flags().StringVar(&exportBundle, "bundle", "", "Write a portable project archive (e.g. party.phz) instead of JSON")
+	cmd.Flags().BoolVar(&exportBundleSources, "include-sources", false, "With --bundle, also pack cached downloads and local source files")
+	cmd.Flags().BoolVar(&exportBundleSegments, "include-segments", false, "With --bundle, also pack rendered segments and their render state")
+	cmd.AddCommand(newExportAttributionsCmd())
+	cmd.AddCommand(newExportFramesCmd())
+	cmd.AddCommand(newExportNLECmd())
+	cmd.AddCommand(newExportPlaylistCmd())
 
 	return cmd
 }
@@ -62,6 +69,13 @@ type exportTimelineEntry struct {
 }
 
 func runExport(cmd *cobra.Command, _ []string) error {
+	if exportBundle != "" {
+		return runExportBundle(cmd)
+	}
+	if exportBundleSources || exportBundleSegments {
+		return fmt.Errorf("--include-sources and --include-segments require --bundle")
+	}
+
 	glogf, gcloser := logx.StartCommand("export")
 	defer gcloser.Close()
 	glogf("export started (timeline=%v)", exportTimeline)
@@ -0,0 +1,266 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/config"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+)
+
+var exportAttributionsOutput string
+
+func newExportAttributionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "attributions",
+		Short: "Write a credits file listing every source with its license and attribution",
+		Args:  cobra.NoArgs,
+		RunE:  runExportAttributions,
+	}
+	cmd.Flags().StringVarP(&exportAttributionsOutput, "output", "o", "", "Output file (default <project>/attributions.txt, - for stdout)")
+	return cmd
+}
+
+type attributionEntry struct {
+	Collection  string `json:"collection"`
+	Index       int    `json:"index"`
+	Title       string `json:"title,omitempty"`
+	Artist      string `json:"artist,omitempty"`
+	Link        string `json:"link,omitempty"`
+	License     string `json:"license,omitempty"`
+	Attribution string `json:"attribution,omitempty"`
+}
+
+func runExportAttributions(cmd *cobra.Command, _ []string) error {
+	glogf, gcloser := logx.StartCommand("export-attributions")
+	defer gcloser.Close()
+	glogf("export attributions started")
+
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
+
+	if len(cfg.Collections) == 0 {
+		return fmt.Errorf("no collections configured")
+	}
+
+	resolver, err := project.NewCollectionResolver(cfg, pp)
+	if err != nil {
+		return err
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		return err
+	}
+
+	// The cache fills gaps for rows that don't carry license/attribution
+	// columns themselves; a missing index just means no fallback.
+	idx, _ := cache.Load(pp)
+
+	var timeline []project.TimelineEntry
+	if len(cfg.Timeline.Sequence) > 0 {
+		timeline, err = project.ResolveTimeline(cfg.Timeline, collections)
+		if err != nil {
+			return fmt.Errorf("resolve timeline: %w", err)
+		}
+	}
+
+	entries := collectAttributions(cfg, collections, timeline, idx)
+
+	if outputJSON {
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return nil
+	}
+
+	dest := exportAttributionsOutput
+	if dest == "" {
+		dest = filepath.Join(pp.Root, "attributions.txt")
+	}
+	if dest == "-" {
+		return writeAttributions(cmd.OutOrStdout(), filepath.Base(pp.Root), entries)
+	}
+
+	f, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("create attributions file: %w", err)
+	}
+	if err := writeAttributions(f, filepath.Base(pp.Root), entries); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close attributions file: %w", err)
+	}
+	glogf("export attributions finished (%d sources)", len(entries))
+	cmd.Printf("Wrote %d attributions to %s\n", len(entries), dest)
+	return nil
+}
+
+// collectAttributions lists each distinct source once, in playback order when
+// a timeline is configured and collection/row order otherwise. Row columns
+// win; the cache index supplies license and uploader/channel when a row
+// leaves them empty.
+func collectAttributions(cfg config.Config, collections map[string]project.Collection, timeline []project.TimelineEntry, idx *cache.Index) []attributionEntry {
+	order := playbackOrder(collections, timeline)
+
+	seen := make(map[string]bool)
+	var out []attributionEntry
+	for _, r := range order {
+		coll, ok := collections[r.collection]
+		if !ok || r.index < 1 || r.index > len(coll.Rows) {
+			continue
+		}
+		row := coll.Rows[r.index-1]
+		link := strings.TrimSpace(row.Link)
+		key := link
+		if key == "" {
+			key = fmt.Sprintf("%s:%d", r.collection, r.index)
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		entry := attributionEntry{
+			Collection:  r.collection,
+			Index:       r.index,
+			Title:       strings.TrimSpace(row.CustomFields["title"]),
+			Artist:      strings.TrimSpace(row.CustomFields["artist"]),
+			Link:        link,
+			License:     strings.TrimSpace(row.CustomFields["license"]),
+			Attribution: strings.TrimSpace(row.CustomFields["attribution"]),
+		}
+		if cached, ok := lookupAttributionEntry(idx, link); ok {
+			fieldMap := cfg.Collections[r.collection].ResolveCollectionFieldMap()
+			if entry.License == "" {
+				entry.License = firstCacheField(cached, fieldMap["license"])
+			}
+			if entry.Attribution == "" {
+				entry.Attribution = firstCacheField(cached, fieldMap["attribution"])
+			}
+			if entry.Title == "" {
+				entry.Title = strings.TrimSpace(cached.Title)
+			}
+		}
+		if entry.Title == "" && entry.Link == "" {
+			continue
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+// rowRef addresses one plan row by collection name and 1-based index.
+type rowRef struct {
+	collection string
+	index      int
+}
+
+// playbackOrder lists rows in timeline order when a timeline is resolved and
+// in sorted collection, then row order otherwise.
+func playbackOrder(collections map[string]project.Collection, timeline []project.TimelineEntry) []rowRef {
+	var order []rowRef
+	if len(timeline) > 0 {
+		for _, e := range timeline {
+			if e.Collection != "" {
+				order = append(order, rowRef{e.Collection, e.Index})
+			}
+		}
+		return order
+	}
+	names := make([]string, 0, len(collections))
+	for name := range collections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, row := range collections[name].Rows {
+			order = append(order, rowRef{name, row.Index})
+		}
+	}
+	return order
+}
+
+func lookupAttributionEntry(idx *cache.Index, link string) (cache.Entry, bool) {
+	if idx == nil || link == "" {
+		return cache.Entry{}, false
+	}
+	identifier, ok := idx.LookupLink(link)
+	if !ok {
+		return cache.Entry{}, false
+	}
+	return idx.GetByIdentifier(identifier)
+}
+
+// firstCacheField returns the first non-empty value among the named cache
+// fields. Only the fields meaningful for credits are supported.
+func firstCacheField(entry cache.Entry, fields []string) string {
+	for _, field := range fields {
+		var value string
+		switch field {
+		case "license":
+			value = entry.License
+		case "uploader":
+			value = entry.Uploader
+		case "channel":
+			value = entry.Channel
+		case "artist":
+			value = entry.Artist
+		}
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func writeAttributions(w io.Writer, projectName string, entries []attributionEntry) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Credits: %s\n\n", projectName)
+	for i, e := range entries {
+		heading := e.Title
+		if e.Artist != "" {
+			heading = strings.TrimSpace(heading + " - " + e.Artist)
+		}
+		if heading == "" {
+			heading = e.Link
+		}
+		fmt.Fprintf(&b, "%d. %s\n", i+1, heading)
+		if e.Attribution != "" {
+			fmt.Fprintf(&b, "   By: %s\n", e.Attribution)
+		}
+		if e.Link != "" {
+			fmt.Fprintf(&b, "   Source: %s\n", e.Link)
+		}
+		license := e.License
+		if license == "" {
+			license = "unknown"
+		}
+		fmt.Fprintf(&b, "   License: %s\n\n", license)
+	}
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("write attributions: %w", err)
+	}
+	return nil
+}
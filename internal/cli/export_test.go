@@ -2,7 +2,14 @@ package cli
 
 import (
 	"encoding/json"
+	"reflect"
+	"strings"
 	"testing"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/config"
+	"powerhour/internal/project"
+	"powerhour/pkg/csvplan"
 )
 
 func TestExportOutputStructure(t *testing.T) {
@@ -92,3 +99,73 @@ func TestExportOmitsEmptyTimeline(t *testing.T) {
 		t.Error("timeline should be omitted when empty")
 	}
 }
+
+func TestCollectAttributions(t *testing.T) {
+	idx := &cache.Index{}
+	idx.SetEntry(cache.Entry{Identifier: "youtube:a", Title: "Cached A", License: "Creative Commons Attribution license (reuse allowed)", Uploader: "Uploader A"})
+	idx.SetLink("https://youtu.be/a", "youtube:a")
+
+	cfg := config.Config{Collections: map[string]config.CollectionConfig{"songs": {}}}
+	collections := map[string]project.Collection{
+		"songs": {
+			Name: "songs",
+			Rows: []csvplan.CollectionRow{
+				{Index: 1, Link: "https://youtu.be/a", CustomFields: map[string]string{"title": "Song A"}},
+				{Index: 2, Link: "https://youtu.be/b", CustomFields: map[string]string{"title": "Song B", "license": "CC0", "attribution": "Row Credit"}},
+				{Index: 3, Link: "https://youtu.be/a", CustomFields: map[string]string{"title": "Song A again"}},
+			},
+		},
+	}
+	timeline := []project.TimelineEntry{
+		{Collection: "songs", Index: 2},
+		{Collection: "", Index: 0, SourceFile: "intro.mp4"},
+		{Collection: "songs", Index: 1},
+		{Collection: "songs", Index: 3},
+	}
+
+	got := collectAttributions(cfg, collections, timeline, idx)
+	want := []attributionEntry{
+		{Collection: "songs", Index: 2, Title: "Song B", Link: "https://youtu.be/b", License: "CC0", Attribution: "Row Credit"},
+		{Collection: "songs", Index: 1, Title: "Song A", Link: "https://youtu.be/a", License: "Creative Commons Attribution license (reuse allowed)", Attribution: "Uploader A"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("collectAttributions =\n%+v\nwant\n%+v", got, want)
+	}
+
+	var b strings.Builder
+	if err := writeAttributions(&b, "party", got); err != nil {
+		t.Fatal(err)
+	}
+	text := b.String()
+	for _, wantLine := range []string{"Credits: party", "1. Song B", "   By: Row Credit", "   License: CC0", "2. Song A"} {
+		if !strings.Contains(text, wantLine) {
+			t.Errorf("attributions text missing %q:\n%s", wantLine, text)
+		}
+	}
+}
+
+func TestParseClipRef(t *testing.T) {
+	tests := []struct {
+		ref      string
+		wantName string
+		wantN    int
+		wantErr  bool
+	}{
+		{"songs:3", "songs", 3, false},
+		{" 7 ", "", 7, false},
+		{"songs:0", "", 0, true},
+		{":3", "", 0, true},
+		{"songs", "", 0, true},
+		{"", "", 0, true},
+	}
+	for _, tt := range tests {
+		name, n, err := parseClipRef(tt.ref)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseClipRef(%q) err = %v, wantErr %v", tt.ref, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (name != tt.wantName || n != tt.wantN) {
+			t.Errorf("parseClipRef(%q) = %q, %d; want %q, %d", tt.ref, name, n, tt.wantName, tt.wantN)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"sort"
 	"strconv"
 	"strings"
@@ -16,6 +17,7 @@ import (
 	"powerhour/internal/config"
 	"powerhour/internal/logx"
 	"powerhour/internal/paths"
+	"powerhour/internal/tools"
 	"powerhour/internal/tui"
 	"powerhour/pkg/csvplan"
 )
@@ -25,7 +27,9 @@ var (
 	fetchReprobe    bool
 	fetchNoDownload bool
 	fetchNoProgress bool
+	fetchNoUpdate   bool
 	fetchIndexArg   []string
+	fetchSkipSpace  bool
 )
 
 var newCacheServiceWithStatus = cache.NewServiceWithStatus
@@ -34,14 +38,16 @@ func newFetchCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "fetch",
 		Short: "Populate the project source cache",
-		RunE:  runFetch,
+		RunE:  withTelemetry("fetch", withNotify("fetch", runFetch)),
 	}
 
 	cmd.Flags().BoolVar(&fetchForce, "force", false, "Re-download all sources even if cached")
 	cmd.Flags().BoolVar(&fetchReprobe, "reprobe", false, "Re-run ffprobe on cached entries")
 	cmd.Flags().BoolVar(&fetchNoDownload, "no-download", false, "Skip downloading new sources; only match existing files")
 	cmd.Flags().BoolVar(&fetchNoProgress, "no-progress", false, "Disable interactive progress output")
+	cmd.Flags().BoolVar(&fetchNoUpdate, "no-update", false, "Skip the tools.yt-dlp.auto_update check for this run")
 	cmd.Flags().StringSliceVar(&fetchIndexArg, "index", nil, "Limit fetch to specific 1-based row index or range like 5-10 (repeat flag for multiple)")
+	cmd.Flags().BoolVar(&fetchSkipSpace, "skip-space-check", false, "Download even if the estimated size doesn't fit in free disk space")
 	addCollectionFetchFlags(cmd)
 
 	return cmd
@@ -53,9 +59,9 @@ func runFetch(cmd *cobra.Command, _ []string) error {
 		ctx = context.Background()
 	}
 
-	glogf, gcloser := logx.StartCommand("fetch")
+	glog, gcloser := logx.Command("fetch")
 	defer gcloser.Close()
-	glogf("fetch started")
+	glog.Info("fetch started")
 
 	status := tui.NewStatusWriter(cmd.ErrOrStderr())
 	defer status.Stop()
@@ -65,7 +71,7 @@ func runFetch(cmd *cobra.Command, _ []string) error {
 	if err != nil {
 		return err
 	}
-	glogf("project resolved: %s", pp.Root)
+	glog.Debug("project resolved", "root", pp.Root)
 
 	status.Update("Loading config...")
 	cfg, err := config.Load(pp.ConfigFile)
@@ -74,7 +80,7 @@ func runFetch(cmd *cobra.Command, _ []string) error {
 	}
 	pp = paths.ApplyConfig(pp, cfg)
 	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
-	glogf("config loaded")
+	glog.Debug("config loaded")
 
 	exists, err := paths.DirExists(pp.Root)
 	if err != nil {
@@ -88,8 +94,41 @@ func runFetch(cmd *cobra.Command, _ []string) error {
 		return fmt.Errorf("no collections configured")
 	}
 
-	glogf("routing to collection fetch (%d collections)", len(cfg.Collections))
-	return runCollectionFetch(ctx, cmd, pp, cfg, glogf, status)
+	glog.Debug("routing to collection fetch", "collections", len(cfg.Collections))
+	if err := runCollectionFetch(ctx, cmd, pp, cfg, glog, status); err != nil {
+		glog.Error("fetch failed", "error", err)
+		return err
+	}
+	glog.Info("fetch finished")
+	return nil
+}
+
+// autoUpdateYTDLP runs the tools.yt-dlp.auto_update check when configured and
+// returns a one-line note for fetch output when the binary was upgraded.
+// Failures are logged as warnings and never block the fetch.
+func autoUpdateYTDLP(ctx context.Context, cfg config.Config, glog *slog.Logger, status *tui.StatusWriter) string {
+	channel := cfg.ToolAutoUpdate("yt-dlp")
+	if channel == "" || fetchNoUpdate {
+		return ""
+	}
+	proxy, err := cache.ResolveYTDLPProxy(cfg)
+	if err != nil {
+		glog.Warn("yt-dlp auto-update skipped", "error", err)
+		return ""
+	}
+	status.Update("Checking for yt-dlp updates...")
+	ctx = tools.WithProxy(tools.WithMinimums(ctx, cfg.ToolMinimums()), proxy)
+	result, err := tools.AutoUpdate(ctx, "yt-dlp", channel)
+	if err != nil {
+		glog.Warn("yt-dlp auto-update failed", "error", err)
+		return ""
+	}
+	if !result.Updated() {
+		glog.Info("yt-dlp auto-update", "checked", result.Checked, "current", result.From)
+		return ""
+	}
+	glog.Info("yt-dlp auto-update", "from", result.From, "to", result.To)
+	return fmt.Sprintf("Updated yt-dlp %s → %s (auto_update: %s)", result.From, result.To, channel)
 }
 
 func parseIndexArgs(args []string) ([]int, error) {
@@ -245,9 +284,7 @@ func writeFetchFailures(cmd *cobra.Command, rows []fetchRowResult) {
 }
 
 func printFetchSummary(w io.Writer, counts fetchCounts) {
-	fmt.Fprintf(w, "Downloaded: %d, Matched: %d, Reused: %d, Missing: %d, Probed: %d, Failed: %d\n",
-		counts.Downloaded, counts.Matched, counts.Reused, counts.Missing, counts.Probed, counts.Failed,
-	)
+	fmt.Fprintln(w, fetchSummaryLine(counts))
 }
 
 func isRemoteLink(link string) bool {
@@ -2,8 +2,17 @@ package cli
 
 import (
 	"bytes"
+	"errors"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/tui"
+	"powerhour/pkg/csvplan"
 )
 
 func TestWriteFetchJSON(t *testing.T) {
@@ -93,3 +102,53 @@ func TestWriteFetchFailures(t *testing.T) {
 }
 
 // Index filter tests moved to index_filter_test.go
+
+func TestCollectionFetchDetail(t *testing.T) {
+	pp := paths.ProjectPaths{LogsDir: "/party/.powerhour/logs"}
+	rows := []project.CollectionPlanRow{
+		{CollectionName: "songs", Row: csvplan.Row{Index: 3, Title: "Song", Link: "https://youtu.be/abc", StartRaw: "1:05"}},
+		{CollectionName: "songs", Row: csvplan.Row{Index: 4, Title: "Local", Link: "clips/local.mp4"}},
+		{CollectionName: "closing", Row: csvplan.Row{Index: 3, Title: "Closer", Link: "https://youtu.be/xyz"}},
+	}
+	detail := collectionFetchDetail(pp, rows)
+
+	remote := detail("songs:003")
+	if remote.LogPath != filepath.Join(pp.LogsDir, "fetch_songs_003.log") {
+		t.Errorf("remote LogPath = %q", remote.LogPath)
+	}
+	if closing := detail("closing:003"); closing.LogPath != filepath.Join(pp.LogsDir, "fetch_closing_003.log") {
+		t.Errorf("closing LogPath = %q", closing.LogPath)
+	}
+	if !slices.Contains(remote.Params, tui.DetailParam{Name: "start", Value: "1:05"}) {
+		t.Errorf("remote params = %+v", remote.Params)
+	}
+	if local := detail("songs:004"); local.LogPath != "" {
+		t.Errorf("local row has a log: %q", local.LogPath)
+	}
+	if unknown := detail("other:001"); len(unknown.Params) != 0 {
+		t.Errorf("unknown key params = %+v", unknown.Params)
+	}
+}
+
+func TestFetchByLinkSharesOnlyRealLinks(t *testing.T) {
+	byLink := fetchByLink{}
+	calls := 0
+	fetch := func(status cache.ResolveStatus, err error) func() (cache.ResolveResult, error) {
+		return func() (cache.ResolveResult, error) {
+			calls++
+			return cache.ResolveResult{Status: status, Probed: true}, err
+		}
+	}
+
+	first, _ := byLink.resolve("https://youtu.be/abc", fetch(cache.ResolveStatusDownloaded, nil))
+	second, _ := byLink.resolve(" https://youtu.be/abc ", fetch(cache.ResolveStatusDownloaded, nil))
+	if calls != 1 || first.Status != cache.ResolveStatusDownloaded || second.Status != cache.ResolveStatusCached || second.Probed {
+		t.Errorf("shared link: calls=%d first=%+v second=%+v", calls, first, second)
+	}
+
+	_, err3 := byLink.resolve("", fetch("", errors.New("row 3 missing link")))
+	_, err5 := byLink.resolve("  ", fetch("", errors.New("row 5 missing link")))
+	if calls != 3 || err3 == nil || err5 == nil || err5.Error() != "row 5 missing link" {
+		t.Errorf("empty links: calls=%d err3=%v err5=%v", calls, err3, err5)
+	}
+}
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"context"
+	"sync"
+
+	"powerhour/internal/config"
+	"powerhour/internal/hooks"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+)
+
+type hookRow struct {
+	Collection string `json:"collection"`
+	Index      int    `json:"index"`
+	Title      string `json:"title,omitempty"`
+	Link       string `json:"link,omitempty"`
+}
+
+type preFetchPayload struct {
+	Collection string    `json:"collection,omitempty"`
+	Rows       []hookRow `json:"rows"`
+}
+
+type hookSegment struct {
+	Collection string `json:"collection,omitempty"`
+	Index      int    `json:"index"`
+	Title      string `json:"title,omitempty"`
+	Output     string `json:"output"`
+	Log        string `json:"log,omitempty"`
+	Status     string `json:"status"` // rendered or failed
+	Error      string `json:"error,omitempty"`
+}
+
+type postSegmentPayload struct {
+	Segment hookSegment `json:"segment"`
+}
+
+type postConcatPayload struct {
+	Output   string `json:"output"`
+	Method   string `json:"method"`
+	Segments int    `json:"segments"`
+	Timeline string `json:"timeline,omitempty"`
+	Variant  string `json:"variant,omitempty"`
+}
+
+func preFetchHookPayload(collection string, rows []project.CollectionPlanRow) preFetchPayload {
+	payload := preFetchPayload{Collection: collection, Rows: make([]hookRow, len(rows))}
+	for i, r := range rows {
+		payload.Rows[i] = hookRow{Collection: r.CollectionName, Index: r.Row.Index, Title: r.Row.Title, Link: r.Row.Link}
+	}
+	return payload
+}
+
+// segmentHookReporter runs hooks.post_segment as each segment finishes and
+// passes every event on to the wrapped reporter. Hook failures are kept for
+// printing once rendering is done, since a progress table may own the
+// terminal meanwhile.
+type segmentHookReporter struct {
+	ctx    context.Context
+	runner *hooks.Runner
+	clips  map[int]project.CollectionClip // by clip sequence
+	inner  render.ProgressReporter
+
+	mu   sync.Mutex
+	errs []error
+}
+
+func newSegmentHookReporter(ctx context.Context, runner *hooks.Runner, clips []project.CollectionClip) *segmentHookReporter {
+	bySeq := make(map[int]project.CollectionClip, len(clips))
+	for _, cc := range clips {
+		bySeq[cc.Clip.Sequence] = cc
+	}
+	return &segmentHookReporter{ctx: ctx, runner: runner, clips: bySeq}
+}
+
+// wrap returns the reporter to hand to render.Service, or inner unchanged
+// when r is nil or no post_segment hook is configured.
+func (r *segmentHookReporter) wrap(inner render.ProgressReporter) render.ProgressReporter {
+	if r == nil || !r.runner.Has(hooks.PostSegment) {
+		return inner
+	}
+	r.inner = inner
+	return r
+}
+
+func (r *segmentHookReporter) Start(seg render.Segment) {
+	if r.inner != nil {
+		r.inner.Start(seg)
+	}
+}
+
+func (r *segmentHookReporter) Progress(seg render.Segment, pct float64) {
+	if r.inner != nil {
+		r.inner.Progress(seg, pct)
+	}
+}
+
+func (r *segmentHookReporter) Complete(res render.Result) {
+	if r.inner != nil {
+		r.inner.Complete(res)
+	}
+	// Interrupted segments didn't finish either way; no hook runs for them.
+	if res.Skipped || res.Interrupted {
+		return
+	}
+	seg := hookSegment{Index: res.TypeIndex, Title: res.Title, Output: res.OutputPath, Log: res.LogPath, Status: "rendered"}
+	if cc, ok := r.clips[res.Index]; ok {
+		seg.Collection = cc.CollectionName
+		if cc.Clip.Row.Index > 0 {
+			seg.Index = cc.Clip.Row.Index
+		}
+	}
+	if res.Err != nil {
+		seg.Status, seg.Error = "failed", res.Err.Error()
+	}
+	if err := r.runner.Run(r.ctx, hooks.PostSegment, postSegmentPayload{Segment: seg}); err != nil {
+		r.mu.Lock()
+		r.errs = append(r.errs, err)
+		r.mu.Unlock()
+	}
+}
+
+// failures returns the hook errors so far.
+func (r *segmentHookReporter) failures() []error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]error(nil), r.errs...)
+}
+
+// runPostConcatHooks runs hooks.post_concat, logging their output to the
+// project log.
+func runPostConcatHooks(ctx context.Context, pp paths.ProjectPaths, cfg config.Config, payload postConcatPayload) error {
+	logger, closer, err := logx.New(pp)
+	if err != nil {
+		return err
+	}
+	defer closer.Close()
+	return hooks.New(cfg.Hooks, pp.Root, logger.Writer()).Run(ctx, hooks.PostConcat, payload)
+}
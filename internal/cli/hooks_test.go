@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"powerhour/internal/config"
+	"powerhour/internal/hooks"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+	"powerhour/pkg/csvplan"
+)
+
+func TestSegmentHookReporter(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	root := t.TempDir()
+	runner := hooks.New(config.HooksConfig{PostSegment: []string{`cat >> segments.jsonl; echo >> segments.jsonl`}}, root, nil)
+	clips := []project.CollectionClip{
+		{CollectionName: "songs", Clip: project.Clip{Sequence: 1, TypeIndex: 1, Row: csvplan.Row{Index: 4}}},
+		{CollectionName: "songs", Clip: project.Clip{Sequence: 2, TypeIndex: 2, Row: csvplan.Row{Index: 5}}},
+	}
+	r := newSegmentHookReporter(t.Context(), runner, clips)
+	reporter := r.wrap(nil)
+	if reporter == nil {
+		t.Fatal("wrap returned nil with a post_segment hook configured")
+	}
+
+	reporter.Complete(render.Result{Index: 1, TypeIndex: 1, Title: "One", OutputPath: "/seg/one.mp4"})
+	reporter.Complete(render.Result{Index: 2, TypeIndex: 2, Title: "Two", OutputPath: "/seg/two.mp4", Skipped: true})
+	reporter.Complete(render.Result{Index: 2, TypeIndex: 2, Title: "Two", OutputPath: "/seg/two.mp4", Err: errors.New("ffmpeg exited 1")})
+
+	data, err := os.ReadFile(filepath.Join(root, "segments.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("hook ran %d times, want 2 (skipped segments don't fire):\n%s", len(lines), data)
+	}
+	var got []postSegmentPayload
+	for _, line := range lines {
+		var p postSegmentPayload
+		if err := json.Unmarshal([]byte(line), &p); err != nil {
+			t.Fatalf("payload %s: %v", line, err)
+		}
+		got = append(got, p)
+	}
+	if s := got[0].Segment; s.Collection != "songs" || s.Index != 4 || s.Status != "rendered" || s.Output != "/seg/one.mp4" {
+		t.Errorf("first payload = %+v", s)
+	}
+	if s := got[1].Segment; s.Index != 5 || s.Status != "failed" || s.Error != "ffmpeg exited 1" {
+		t.Errorf("second payload = %+v", s)
+	}
+	if len(r.failures()) != 0 {
+		t.Errorf("failures = %v", r.failures())
+	}
+
+	var none *segmentHookReporter
+	if none.wrap(nil) != nil || none.failures() != nil {
+		t.Error("nil reporter should pass through")
+	}
+}
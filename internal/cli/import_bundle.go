@@ -0,0 +1,359 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	xterm "github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/config"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/projectbundle"
+	"powerhour/internal/render"
+	"powerhour/internal/render/state"
+)
+
+var (
+	importBundleForce      bool
+	importBundleAllowHooks bool
+)
+
+func newImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <bundle>",
+		Short: "Restore a project from a bundle made by export --bundle",
+		Long: `Unpack a project bundle into --project (default: the current directory).
+
+Project files keep their relative layout. Everything is restored inside
+the project: cached downloads go to its cache directory, even when the
+project uses a shared library, and rendered segments go to its segments
+directories.
+Paths in the cache index and render state are re-resolved to match.
+Anything the bundle didn't carry can be restored with powerhour fetch.
+
+Hooks in the restored powerhour.yaml are shell commands that fetch, render
+and concat would run. They are listed and kept only when you confirm, or
+with --allow-hooks; otherwise they are removed from the config.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runImportBundle,
+	}
+	cmd.Flags().BoolVar(&importBundleForce, "force", false, "Overwrite existing project files")
+	cmd.Flags().BoolVar(&importBundleAllowHooks, "allow-hooks", false, "Keep the hooks in the bundled config without asking")
+	return cmd
+}
+
+type importSummary struct {
+	Project  string   `json:"project"`
+	Files    int      `json:"files"`
+	Sources  int      `json:"sources"`
+	Segments int      `json:"segments"`
+	Missing  int      `json:"missing_sources"`
+	Skipped  []string `json:"skipped,omitempty"`
+	// DroppedHooks are the hook commands removed from the restored config.
+	DroppedHooks []string `json:"dropped_hooks,omitempty"`
+}
+
+func runImportBundle(cmd *cobra.Command, args []string) error {
+	glogf, gcloser := logx.StartCommand("import")
+	defer gcloser.Close()
+	glogf("import started: bundle=%s force=%v", args[0], importBundleForce)
+
+	// Without --project the bundle lands in the working directory, even
+	// inside another project.
+	var pp paths.ProjectPaths
+	var err error
+	if projectDir == "" {
+		pp, err = paths.ResolveAt(".")
+	} else {
+		pp, err = paths.Resolve(projectDir)
+	}
+	if err != nil {
+		return err
+	}
+	if err := pp.EnsureRoot(); err != nil {
+		return err
+	}
+	tmp, err := os.MkdirTemp(pp.Root, ".powerhour-import-")
+	if err != nil {
+		return fmt.Errorf("create import dir: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	manifest, err := projectbundle.Extract(args[0], tmp)
+	if err != nil {
+		return err
+	}
+	glogf("bundle extracted: project=%s files=%d", manifest.Project, len(manifest.Files))
+
+	// Project files first: the config decides where everything else goes.
+	projectAnchors := []projectbundle.Anchor{{Name: "$project", Dir: pp.Root}}
+	if !importBundleForce {
+		for _, name := range manifest.Files {
+			if dest := projectbundle.LocalPath(name, projectAnchors); dest != "" {
+				if _, err := os.Stat(dest); err == nil {
+					return fmt.Errorf("%s already exists; use --force to overwrite", relPath(pp.Root, dest))
+				}
+			}
+		}
+	}
+	summary := importSummary{Project: pp.Root, Skipped: manifest.Skipped}
+	for _, name := range manifest.Files {
+		if dest := projectbundle.LocalPath(name, projectAnchors); dest != "" {
+			if err := moveBundleFile(filepath.Join(tmp, filepath.FromSlash(name)), dest); err != nil {
+				return err
+			}
+			summary.Files++
+		}
+	}
+
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return fmt.Errorf("load restored config: %w", err)
+	}
+	// Hooks are arbitrary commands from whoever made the bundle.
+	if hooks := hookCommands(cfg.Hooks); len(hooks) > 0 && !keepImportedHooks(cmd, hooks) {
+		cfg.Hooks = config.HooksConfig{}
+		if err := config.Save(pp.ConfigFile, cfg); err != nil {
+			return fmt.Errorf("remove imported hooks: %w", err)
+		}
+		summary.DroppedHooks = hooks
+		glogf("import dropped %d hooks", len(hooks))
+	}
+	trusted := pp
+	pp = paths.ApplyConfig(pp, cfg)
+	anchors := importAnchors(trusted, pp, cfg)
+	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
+	if err := pp.EnsureMetaDirs(); err != nil {
+		return err
+	}
+
+	for _, name := range manifest.Files {
+		if strings.HasPrefix(name, "project/") || strings.HasPrefix(name, projectbundle.MetaDir+"/") {
+			continue
+		}
+		dest := projectbundle.LocalPath(name, anchors)
+		if dest == "" {
+			summary.Skipped = append(summary.Skipped, name+": no matching directory in this project")
+			continue
+		}
+		// A shared library may already hold the same download.
+		if _, err := os.Stat(dest); err == nil && strings.HasPrefix(name, "cache/") {
+			summary.Sources++
+			continue
+		}
+		if err := moveBundleFile(filepath.Join(tmp, filepath.FromSlash(name)), dest); err != nil {
+			return err
+		}
+		if strings.HasPrefix(name, "cache/") {
+			summary.Sources++
+		} else {
+			summary.Segments++
+		}
+	}
+
+	idx, err := cache.Load(pp)
+	if err != nil {
+		return err
+	}
+	if err := mergeBundleIndex(idx, filepath.Join(tmp, filepath.FromSlash(bundleIndexName)), anchors); err != nil {
+		return err
+	}
+	if err := cache.Save(pp, idx); err != nil {
+		return err
+	}
+
+	if summary.Segments > 0 {
+		for _, pair := range timelineStatePaths(pp, cfg) {
+			if !insideDir(trusted.Root, pair.stateFile) {
+				continue
+			}
+			bundled := filepath.Join(tmp, projectbundle.MetaDir, filepath.Base(pair.stateFile))
+			if _, err := os.Stat(bundled); err != nil {
+				continue
+			}
+			rs, _ := state.Load(bundled)
+			restored := relocateRenderState(rs, func(p string) string {
+				return projectbundle.Localize(p, anchors)
+			})
+			if err := restored.Save(pair.stateFile); err != nil {
+				return fmt.Errorf("write render state: %w", err)
+			}
+		}
+	}
+
+	if resolver, err := project.NewCollectionResolver(cfg, pp); err == nil {
+		if collections, err := resolver.LoadCollections(); err == nil {
+			summary.Missing = countMissingSources(pp, idx, collections)
+		}
+	}
+	registerProject(cmd, pp.Root)
+	glogf("import finished: files=%d sources=%d segments=%d missing=%d", summary.Files, summary.Sources, summary.Segments, summary.Missing)
+
+	if outputJSON {
+		data, err := json.MarshalIndent(summary, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	cmd.Printf("Restored %s into %s: %d project files, %d sources, %d segments\n",
+		filepath.Base(args[0]), pp.Root, summary.Files, summary.Sources, summary.Segments)
+	for _, s := range summary.Skipped {
+		cmd.Printf("  skipped %s\n", s)
+	}
+	if len(summary.DroppedHooks) > 0 {
+		cmd.Println("Removed the bundle's hooks from powerhour.yaml; import again with --allow-hooks to keep them:")
+		for _, h := range summary.DroppedHooks {
+			cmd.Printf("  %s\n", h)
+		}
+	}
+	if summary.Missing > 0 {
+		cmd.Printf("%d rows have no source on this machine yet; run powerhour fetch --project %s\n", summary.Missing, pp.Root)
+	}
+	return nil
+}
+
+// hookCommands lists every configured hook as "<event>: <command>".
+func hookCommands(h config.HooksConfig) []string {
+	var out []string
+	for _, event := range []struct {
+		name     string
+		commands []string
+	}{
+		{"pre_fetch", h.PreFetch},
+		{"post_segment", h.PostSegment},
+		{"post_concat", h.PostConcat},
+	} {
+		for _, c := range event.commands {
+			out = append(out, event.name+": "+c)
+		}
+	}
+	return out
+}
+
+// keepImportedHooks shows the hooks a restored config would run and asks
+// whether to keep them. --allow-hooks keeps them without asking; with no
+// terminal to ask on, they are dropped.
+func keepImportedHooks(cmd *cobra.Command, hooks []string) bool {
+	if importBundleAllowHooks {
+		return true
+	}
+	if outputJSON || !xterm.IsTerminal(os.Stdin.Fd()) || !xterm.IsTerminal(os.Stderr.Fd()) {
+		return false
+	}
+	errOut := cmd.ErrOrStderr()
+	fmt.Fprintln(errOut, "The bundled powerhour.yaml runs these shell commands during fetch, render and concat:")
+	for _, h := range hooks {
+		fmt.Fprintf(errOut, "  %s\n", h)
+	}
+	return newPrompter(cmd.InOrStdin(), errOut).confirm("Keep these hooks?", false)
+}
+
+// importAnchors are the directories bundle members are restored into. The
+// restored powerhour.yaml comes from the bundle, so it may only choose among
+// directories inside the project root: sources go to the project's own cache
+// directory rather than a library path the bundle names, a segments
+// directory outside the root falls back to the default one, and timelines
+// whose segments would land outside the root are left out.
+func importAnchors(trusted, configured paths.ProjectPaths, cfg config.Config) []projectbundle.Anchor {
+	segments := configured
+	if !insideDir(trusted.Root, segments.SegmentsDir) {
+		segments.SegmentsDir = trusted.SegmentsDir
+	}
+	anchors := []projectbundle.Anchor{
+		{Name: "$project", Dir: trusted.Root},
+		{Name: "$cache", Dir: trusted.CacheDir},
+		{Name: "$segments", Dir: segments.SegmentsDir},
+	}
+	for _, name := range cfg.TimelineNames() {
+		dir := paths.ApplyTimeline(segments, name).SegmentsDir
+		if insideDir(trusted.Root, dir) {
+			anchors = append(anchors, projectbundle.Anchor{Name: "$segments-" + name, Dir: dir})
+		}
+	}
+	return anchors
+}
+
+// insideDir reports whether p is strictly inside dir.
+func insideDir(dir, p string) bool {
+	rel, err := filepath.Rel(dir, p)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+// mergeBundleIndex adds the bundled cache entries to idx with their paths
+// re-resolved. Entries idx already has with a file on disk are kept, so a
+// shared library's copy wins over the bundle's.
+func mergeBundleIndex(idx *cache.Index, bundledPath string, anchors []projectbundle.Anchor) error {
+	bundled, err := cache.LoadFromPath(bundledPath)
+	if err != nil {
+		return fmt.Errorf("read bundled cache index: %w", err)
+	}
+	for id, entry := range bundled.Entries {
+		if existing, ok := idx.GetByIdentifier(id); ok {
+			if _, err := os.Stat(existing.CachedPath); err == nil {
+				continue
+			}
+		}
+		entry.CachedPath = projectbundle.Localize(entry.CachedPath, anchors)
+		idx.SetEntry(entry)
+	}
+	for link, id := range bundled.Links {
+		if _, ok := idx.LookupLink(link); !ok {
+			idx.SetLink(link, id)
+		}
+	}
+	return nil
+}
+
+// countMissingSources counts non-skipped rows whose source isn't on disk.
+func countMissingSources(pp paths.ProjectPaths, idx *cache.Index, collections map[string]project.Collection) int {
+	missing := 0
+	for _, coll := range project.WithoutSkippedRows(collections) {
+		if coll.Config.IsGenerator() {
+			continue
+		}
+		for _, collRow := range coll.Rows {
+			row := collRow.ToRow()
+			if isRemoteLink(row.Link) {
+				entry, ok, _ := render.ResolveEntry(pp, idx, row)
+				if ok {
+					if _, err := os.Stat(entry.CachedPath); err == nil {
+						continue
+					}
+				}
+				missing++
+				continue
+			}
+			local := row.Link
+			if !filepath.IsAbs(local) {
+				local = filepath.Join(pp.Root, local)
+			}
+			if _, err := os.Stat(local); err != nil {
+				missing++
+			}
+		}
+	}
+	return missing
+}
+
+func moveBundleFile(src, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("prepare %s: %w", dest, err)
+	}
+	if err := os.Rename(src, dest); err == nil {
+		return nil
+	}
+	if err := cache.CopyFile(src, dest); err != nil {
+		return fmt.Errorf("restore %s: %w", dest, err)
+	}
+	return nil
+}
@@ -13,17 +13,30 @@ import (
 )
 
 const (
+	// The YAML starter plans take the template's clip length in seconds.
 	songsPlanYAML = `columns: [title, artist, name, start_time, duration, link]
 defaults:
     start_time: "0:00"
-    duration: "60"
+    duration: "%d"
 rows: []
 `
 	interstitialsPlanYAML = `columns: [link, start_time, duration]
 defaults:
     start_time: "0:00"
-    duration: "5"
+    duration: "%d"
 rows: []
+`
+	songsPlanJSON = `{
+  "columns": ["title", "artist", "name", "start_time", "duration", "link"],
+  "defaults": {"start_time": "0:00", "duration": "%d"},
+  "rows": []
+}
+`
+	interstitialsPlanJSON = `{
+  "columns": ["link", "start_time", "duration"],
+  "defaults": {"start_time": "0:00", "duration": "%d"},
+  "rows": []
+}
 `
 	songsPlanCSV         = "title,artist,name,start_time,duration,link\n"
 	songsPlanTSV         = "title\tartist\tname\tstart_time\tduration\tlink\n"
@@ -31,9 +44,18 @@ rows: []
 	interstitialsPlanTSV = "link\tstart_time\tduration\n"
 )
 
-var initPlanFormat string
+var (
+	initPlanFormat    string
+	initTemplateName  string
+	initListTemplates bool
+)
 
 func renderDefaultConfigYAML(planFormat string) string {
+	return renderInitConfigYAML(builtinInitTemplates[0], planFormat)
+}
+
+// renderInitConfigYAML renders powerhour.yaml for a built-in template.
+func renderInitConfigYAML(t initTemplate, planFormat string) string {
 	songsPlan := "songs.yaml"
 	interstitialsPlan := "interstitials.yaml"
 	switch planFormat {
@@ -43,19 +65,22 @@ func renderDefaultConfigYAML(planFormat string) string {
 	case "tsv":
 		songsPlan = "songs.tsv"
 		interstitialsPlan = "interstitials.tsv"
+	case "json":
+		songsPlan = "songs.json"
+		interstitialsPlan = "interstitials.json"
 	}
 
 	// defaultConfigYAML is the raw template written by init. Using a string
 	// constant (rather than config.Default().Marshal()) allows embedding YAML
 	// comments for documentation and examples.
-	return fmt.Sprintf(`version: 1
+	return fmt.Sprintf(`%sversion: 1
 video:
-    width: 1920
-    height: 1080
-    fps: 30
+    width: %d
+    height: %d
+    fps: %d
     codec: libx264
-    crf: 20
-    preset: medium
+    crf: %d
+    preset: %s
 audio:
     acodec: aac
     bitrate_kbps: 192
@@ -70,10 +95,8 @@ collections:
     songs:
         plan: %s
         output_dir: songs
-        fade: 1.0
-        overlays:
-            - type: song-info
-        link_header: link
+        fade: %s
+%s        link_header: link
         start_header: start_time
         duration_header: duration
         # field_map controls how yt-dlp cache fields fill this collection's
@@ -85,36 +108,17 @@ collections:
     interstitials:
         plan: %s
         output_dir: interstitials
-        fade: 1.0
-        overlays:
-            - type: drink
-        link_header: link
+        fade: %s
+%s        link_header: link
         start_header: start_time
         duration_header: duration
-timeline:
+%stimeline:
     sequence:
-        # - file: videos/intro.mp4              # optional: play a video before songs start
-        #   fade_out: 0.5
-        - collection: songs
-          slice: start:30                       # adjust to the first half of your total song count
-          interleave:
-            collection: interstitials
-            every: 1
-            placement: between                  # between (default), after, before, around
-        # - file: videos/intermission.mp4       # optional: play a video between halves
-        #   fade: 1.0
-        - collection: songs                     # automatically continues from the remaining songs
-          interleave:
-            collection: interstitials
-            every: 1
-            placement: between                  # between (default), after, before, around
-        # - file: videos/outro.mp4              # optional: play a video after songs end
-        #   fade_in: 0.5
-outputs:
+%soutputs:
     segment_template: $INDEX_PAD3_$SAFE_TITLE
 plan:
     headers: {}
-    default_duration_s: 60
+    default_duration_s: %d
 files:
     plan: ""
     cookies: ""
@@ -134,7 +138,23 @@ cache:
         search_fields: [title, artist]
 library: {}
 segments_base_dir: segments
-`, songsPlan, interstitialsPlan)
+`,
+		t.header,
+		t.video.width, t.video.height, t.video.fps, t.video.crf, t.video.preset,
+		songsPlan, t.fade, overlayBlock(t.songOverlay),
+		interstitialsPlan, t.fade, overlayBlock(t.interstitialOverlay)+t.interstitialExtra,
+		t.extraCollections,
+		t.sequence,
+		t.songSeconds)
+}
+
+// overlayBlock is a collection's overlays list with a single preset, or
+// nothing.
+func overlayBlock(preset string) string {
+	if preset == "" {
+		return ""
+	}
+	return "        overlays:\n            - type: " + preset + "\n"
 }
 
 func newInitCmd() *cobra.Command {
@@ -144,7 +164,10 @@ func newInitCmd() *cobra.Command {
 		Args:  cobra.MaximumNArgs(1),
 		RunE:  runInit,
 	}
-	cmd.Flags().StringVar(&initPlanFormat, "plan-format", "yaml", "Collection plan storage format: yaml, csv, or tsv")
+	cmd.Flags().StringVar(&initPlanFormat, "plan-format", "yaml", "Collection plan storage format: yaml, json, csv, or tsv")
+	cmd.Flags().StringVar(&initTemplateName, "template", defaultInitTemplate, "Project template: classic, halftime, lightning, audio-only, or one in ~/.powerhour/templates")
+	cmd.Flags().BoolVar(&initListTemplates, "list-templates", false, "List the available templates and exit")
+	_ = cmd.RegisterFlagCompletionFunc("template", completeInitTemplates)
 
 	return cmd
 }
@@ -187,57 +210,85 @@ func runInit(cmd *cobra.Command, args []string) error {
 	defer gcloser.Close()
 	glogf("init started")
 
-	dir, err := resolveInitDir(projectDir, args)
+	if initListTemplates {
+		return listInitTemplates(cmd)
+	}
+
+	tmpl, err := findInitTemplate(initTemplateName)
 	if err != nil {
 		return err
 	}
-	glogf("target directory: %s", dir)
+	if tmpl.dir != "" && cmd.Flags().Changed("plan-format") {
+		return fmt.Errorf("--plan-format only applies to built-in templates; %s is copied as is", tmpl.dir)
+	}
 
-	pp, err := paths.Resolve(dir)
+	dir, err := resolveInitDir(projectDir, args)
 	if err != nil {
 		return err
 	}
+	glogf("target directory: %s template: %s", dir, tmpl.Name)
+
+	_, err = initProject(cmd, dir, initPlanFormat, tmpl)
+	return err
+}
+
+// initProject scaffolds the project at dir from the template (config plus
+// starter collection plans), printing what was created. Existing files are
+// left untouched.
+func initProject(cmd *cobra.Command, dir, format string, tmpl initTemplate) (paths.ProjectPaths, error) {
+	pp, err := paths.ResolveAt(dir)
+	if err != nil {
+		return pp, err
+	}
 
-	planFormat := strings.ToLower(strings.TrimSpace(initPlanFormat))
+	planFormat := strings.ToLower(strings.TrimSpace(format))
 	switch planFormat {
 	case "", "yaml":
 		planFormat = "yaml"
-	case "csv", "tsv":
+	case "json", "csv", "tsv":
 	default:
-		return fmt.Errorf("unsupported plan format %q (expected yaml, csv, or tsv)", initPlanFormat)
+		return pp, fmt.Errorf("unsupported plan format %q (expected yaml, json, csv, or tsv)", format)
 	}
 
 	if err := pp.EnsureRoot(); err != nil {
-		return err
+		return pp, err
 	}
 	if err := pp.EnsureMetaDirs(); err != nil {
-		return err
+		return pp, err
 	}
 
 	logger, closer, err := logx.New(pp)
 	if err != nil {
-		return err
+		return pp, err
 	}
 	defer closer.Close()
 	logger.Printf("powerhour init: project=%s", pp.Root)
 
 	created := make([]string, 0, 4)
 
-	if err := ensureSongsPlan(pp, planFormat, &created, logger); err != nil {
-		return err
-	}
+	if tmpl.dir != "" {
+		if err := copyInitTemplate(pp, tmpl, &created, logger); err != nil {
+			return pp, err
+		}
+	} else {
+		if err := ensureSongsPlan(pp, tmpl, planFormat, &created, logger); err != nil {
+			return pp, err
+		}
 
-	if err := ensureInterstitialsPlan(pp, planFormat, &created, logger); err != nil {
-		return err
-	}
+		if err := ensureInterstitialsPlan(pp, tmpl, planFormat, &created, logger); err != nil {
+			return pp, err
+		}
 
-	if err := ensureConfig(pp, planFormat, &created, logger); err != nil {
-		return err
+		if err := ensureConfig(pp, tmpl, planFormat, &created, logger); err != nil {
+			return pp, err
+		}
 	}
 
+	registerProject(cmd, pp.Root)
+
 	if len(created) == 0 {
 		cmd.Printf("Project already initialized at %s\n", pp.Root)
-		return nil
+		return pp, nil
 	}
 
 	cmd.Printf("Initialized project at %s\n", pp.Root)
@@ -245,11 +296,11 @@ func runInit(cmd *cobra.Command, args []string) error {
 		cmd.Printf("  created %s\n", entry)
 	}
 
-	return nil
+	return pp, nil
 }
 
-func ensureSongsPlan(pp paths.ProjectPaths, planFormat string, created *[]string, logger Logger) error {
-	filename, contents := initPlanTemplate("songs", planFormat)
+func ensureSongsPlan(pp paths.ProjectPaths, tmpl initTemplate, planFormat string, created *[]string, logger Logger) error {
+	filename, contents := initPlanTemplate(tmpl, "songs", planFormat)
 	planPath := filepath.Join(pp.Root, filename)
 	exists, err := paths.FileExists(planPath)
 	if err != nil {
@@ -268,8 +319,8 @@ func ensureSongsPlan(pp paths.ProjectPaths, planFormat string, created *[]string
 	return nil
 }
 
-func ensureInterstitialsPlan(pp paths.ProjectPaths, planFormat string, created *[]string, logger Logger) error {
-	filename, contents := initPlanTemplate("interstitials", planFormat)
+func ensureInterstitialsPlan(pp paths.ProjectPaths, tmpl initTemplate, planFormat string, created *[]string, logger Logger) error {
+	filename, contents := initPlanTemplate(tmpl, "interstitials", planFormat)
 	planPath := filepath.Join(pp.Root, filename)
 	exists, err := paths.FileExists(planPath)
 	if err != nil {
@@ -288,7 +339,7 @@ func ensureInterstitialsPlan(pp paths.ProjectPaths, planFormat string, created *
 	return nil
 }
 
-func ensureConfig(pp paths.ProjectPaths, planFormat string, created *[]string, logger Logger) error {
+func ensureConfig(pp paths.ProjectPaths, tmpl initTemplate, planFormat string, created *[]string, logger Logger) error {
 	exists, err := paths.FileExists(pp.ConfigFile)
 	if err != nil {
 		return fmt.Errorf("check config: %w", err)
@@ -298,15 +349,15 @@ func ensureConfig(pp paths.ProjectPaths, planFormat string, created *[]string, l
 		return nil
 	}
 
-	if err := os.WriteFile(pp.ConfigFile, []byte(renderDefaultConfigYAML(planFormat)), 0o644); err != nil {
+	if err := os.WriteFile(pp.ConfigFile, []byte(renderInitConfigYAML(tmpl, planFormat)), 0o644); err != nil {
 		return fmt.Errorf("write config: %w", err)
 	}
 	logger.Printf("created config: %s", pp.ConfigFile)
 	*created = append(*created, "powerhour.yaml")
-	return nil
+	return writeInitExamples(pp, created, logger)
 }
 
-func initPlanTemplate(collectionName, planFormat string) (string, string) {
+func initPlanTemplate(tmpl initTemplate, collectionName, planFormat string) (string, string) {
 	switch collectionName {
 	case "songs":
 		switch planFormat {
@@ -314,8 +365,10 @@ func initPlanTemplate(collectionName, planFormat string) (string, string) {
 			return "songs.csv", songsPlanCSV
 		case "tsv":
 			return "songs.tsv", songsPlanTSV
+		case "json":
+			return "songs.json", fmt.Sprintf(songsPlanJSON, tmpl.songSeconds)
 		default:
-			return "songs.yaml", songsPlanYAML
+			return "songs.yaml", fmt.Sprintf(songsPlanYAML, tmpl.songSeconds)
 		}
 	case "interstitials":
 		switch planFormat {
@@ -323,8 +376,10 @@ func initPlanTemplate(collectionName, planFormat string) (string, string) {
 			return "interstitials.csv", interstitialsPlanCSV
 		case "tsv":
 			return "interstitials.tsv", interstitialsPlanTSV
+		case "json":
+			return "interstitials.json", fmt.Sprintf(interstitialsPlanJSON, tmpl.interstitialSeconds)
 		default:
-			return "interstitials.yaml", interstitialsPlanYAML
+			return "interstitials.yaml", fmt.Sprintf(interstitialsPlanYAML, tmpl.interstitialSeconds)
 		}
 	default:
 		return "", ""
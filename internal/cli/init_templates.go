@@ -0,0 +1,301 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/config"
+	"powerhour/internal/paths"
+)
+
+const defaultInitTemplate = "classic"
+
+// initTemplate is a project layout init can scaffold. Built-in templates
+// render powerhour.yaml and the starter plans for the chosen plan format;
+// user templates (a directory under ~/.powerhour/templates) are copied as
+// they are.
+type initTemplate struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// dir is the user template directory; empty for built-ins.
+	dir string
+
+	header              string // comment written above the config
+	video               initVideo
+	fade                string
+	songSeconds         int
+	interstitialSeconds int
+	songOverlay         string
+	interstitialOverlay string
+	interstitialExtra   string // extra interstitials config lines
+	extraCollections    string
+	sequence            string
+}
+
+type initVideo struct {
+	width, height, fps, crf int
+	preset                  string
+}
+
+var fullHDVideo = initVideo{width: 1920, height: 1080, fps: 30, crf: 20, preset: "medium"}
+
+const (
+	halvesInterleave = `          interleave:
+            collection: interstitials
+            every: 1
+            placement: between                  # between (default), after, before, around
+`
+	introComment = `        # - file: videos/intro.mp4              # optional: play a video before songs start
+        #   fade_out: 0.5
+`
+	outroComment = `        # - file: videos/outro.mp4              # optional: play a video after songs end
+        #   fade_in: 0.5
+`
+	firstHalf = `        - collection: songs
+          slice: start:30                       # adjust to the first half of your total song count
+` + halvesInterleave
+	secondHalf = `        - collection: songs                     # automatically continues from the remaining songs
+` + halvesInterleave
+)
+
+var builtinInitTemplates = []initTemplate{
+	{
+		Name:                "classic",
+		Description:         "60 songs x 60 seconds with a drink interstitial between each",
+		video:               fullHDVideo,
+		fade:                "1.0",
+		songSeconds:         60,
+		interstitialSeconds: 5,
+		songOverlay:         "song-info",
+		interstitialOverlay: "drink",
+		sequence: introComment + firstHalf + `        # - file: videos/intermission.mp4       # optional: play a video between halves
+        #   fade: 1.0
+` + secondHalf + outroComment,
+	},
+	{
+		Name:                "halftime",
+		Description:         "classic with a five-minute halftime slate between the halves",
+		video:               fullHDVideo,
+		fade:                "1.0",
+		songSeconds:         60,
+		interstitialSeconds: 5,
+		songOverlay:         "song-info",
+		interstitialOverlay: "drink",
+		extraCollections: `    halftime:
+        generator: slate                        # a title card; swap the sequence entry for a file: to play a video
+        duration: 300
+        slate:
+            title: Halftime
+            rules:
+                - Refill your drink
+                - Back in five minutes
+`,
+		sequence: introComment + firstHalf + `        - collection: halftime
+` + secondHalf + outroComment,
+	},
+	{
+		Name:                "lightning",
+		Description:         "30-minute lightning round: 60 songs x 30 seconds",
+		video:               fullHDVideo,
+		fade:                "0.5",
+		songSeconds:         30,
+		interstitialSeconds: 3,
+		songOverlay:         "song-info",
+		interstitialOverlay: "drink",
+		sequence: introComment + `        - collection: songs
+` + halvesInterleave + outroComment,
+	},
+	{
+		Name:        "audio-only",
+		Description: "for playing through speakers: no overlays and a small, fast video encode",
+		header: `# Segments are still video files, but nobody is watching: the picture is
+# encoded small and fast and carries no overlays. Give the interstitials an
+# audio_cue so the drink call can be heard.
+`,
+		video:               initVideo{width: 640, height: 360, fps: 15, crf: 32, preset: "veryfast"},
+		fade:                "1.0",
+		songSeconds:         60,
+		interstitialSeconds: 5,
+		interstitialExtra: `        # audio_cue:
+        #     file: sounds/drink.mp3            # an air horn or a "drink!" call
+`,
+		sequence: `        - collection: songs
+` + halvesInterleave,
+	},
+}
+
+// userInitTemplates lists the directories in ~/.powerhour/templates that
+// hold a powerhour.yaml.
+func userInitTemplates() ([]initTemplate, error) {
+	root, err := paths.TemplatesDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(root)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read templates: %w", err)
+	}
+	var out []initTemplate
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		dir := filepath.Join(root, e.Name())
+		if ok, _ := paths.FileExists(filepath.Join(dir, "powerhour.yaml")); !ok {
+			continue
+		}
+		out = append(out, initTemplate{Name: e.Name(), Description: "user template in " + dir, dir: dir})
+	}
+	return out, nil
+}
+
+// initTemplates returns every template by name. A user template replaces
+// the built-in with the same name.
+func initTemplates() (map[string]initTemplate, error) {
+	all := make(map[string]initTemplate, len(builtinInitTemplates))
+	for _, t := range builtinInitTemplates {
+		all[t.Name] = t
+	}
+	user, err := userInitTemplates()
+	if err != nil {
+		return all, err
+	}
+	for _, t := range user {
+		all[t.Name] = t
+	}
+	return all, nil
+}
+
+// findInitTemplate resolves --template; empty means the default.
+func findInitTemplate(name string) (initTemplate, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = defaultInitTemplate
+	}
+	all, err := initTemplates()
+	if err != nil {
+		return initTemplate{}, err
+	}
+	t, ok := all[name]
+	if !ok {
+		return initTemplate{}, fmt.Errorf("unknown template %q (available: %s)", name, strings.Join(slices.Sorted(maps.Keys(all)), ", "))
+	}
+	return t, nil
+}
+
+func listInitTemplates(cmd *cobra.Command) error {
+	all, err := initTemplates()
+	if err != nil {
+		return err
+	}
+	names := slices.Sorted(maps.Keys(all))
+
+	if outputJSON {
+		list := make([]initTemplate, len(names))
+		for i, name := range names {
+			list[i] = all[name]
+		}
+		data, err := json.MarshalIndent(list, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	for _, name := range names {
+		fmt.Fprintf(w, "%s\t%s\n", name, all[name].Description)
+	}
+	return w.Flush()
+}
+
+func completeInitTemplates(_ *cobra.Command, _ []string, _ string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	all, _ := initTemplates()
+	var out []cobra.Completion
+	for _, name := range slices.Sorted(maps.Keys(all)) {
+		out = append(out, cobra.CompletionWithDesc(name, all[name].Description))
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// copyInitTemplate copies a user template into the project, skipping files
+// the project already has.
+func copyInitTemplate(pp paths.ProjectPaths, tmpl initTemplate, created *[]string, logger Logger) error {
+	return filepath.WalkDir(tmpl.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(tmpl.dir, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(pp.Root, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		exists, err := paths.FileExists(target)
+		if err != nil {
+			return fmt.Errorf("check %s: %w", rel, err)
+		}
+		if exists {
+			logger.Printf("template file exists: %s", target)
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read template: %w", err)
+		}
+		if err := os.WriteFile(target, data, 0o644); err != nil {
+			return fmt.Errorf("write %s: %w", rel, err)
+		}
+		logger.Printf("created from template: %s", target)
+		*created = append(*created, filepath.ToSlash(rel))
+		return nil
+	})
+}
+
+// writeInitExamples writes <collection>.example.csv next to each plan of a
+// freshly written config, as `plan schema` does.
+func writeInitExamples(pp paths.ProjectPaths, created *[]string, logger Logger) error {
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	for _, name := range slices.Sorted(maps.Keys(cfg.Collections)) {
+		collCfg := cfg.Collections[name]
+		if strings.TrimSpace(collCfg.Plan) == "" {
+			continue
+		}
+		rel := filepath.Join(filepath.Dir(collCfg.Plan), name+".example.csv")
+		examplePath := filepath.Join(pp.Root, rel)
+		exists, err := paths.FileExists(examplePath)
+		if err != nil {
+			return fmt.Errorf("check example: %w", err)
+		}
+		if exists {
+			continue
+		}
+		if err := writePlanExample(examplePath, collCfg, cfg.PlanDefaultDuration()); err != nil {
+			return err
+		}
+		logger.Printf("created example plan: %s", examplePath)
+		*created = append(*created, filepath.ToSlash(rel))
+	}
+	return nil
+}
@@ -1,10 +1,19 @@
 package cli
 
 import (
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/config"
+	"powerhour/internal/paths"
+	"powerhour/internal/render"
+	"powerhour/pkg/csvplan"
 )
 
 func TestResolveInitDir(t *testing.T) {
@@ -92,17 +101,17 @@ func TestInitPlanTemplate(t *testing.T) {
 		wantFile   string
 		wantBody   string
 	}{
-		{collection: "songs", format: "yaml", wantFile: "songs.yaml", wantBody: songsPlanYAML},
+		{collection: "songs", format: "yaml", wantFile: "songs.yaml", wantBody: fmt.Sprintf(songsPlanYAML, 60)},
 		{collection: "songs", format: "csv", wantFile: "songs.csv", wantBody: songsPlanCSV},
 		{collection: "songs", format: "tsv", wantFile: "songs.tsv", wantBody: songsPlanTSV},
-		{collection: "interstitials", format: "yaml", wantFile: "interstitials.yaml", wantBody: interstitialsPlanYAML},
+		{collection: "interstitials", format: "yaml", wantFile: "interstitials.yaml", wantBody: fmt.Sprintf(interstitialsPlanYAML, 5)},
 		{collection: "interstitials", format: "csv", wantFile: "interstitials.csv", wantBody: interstitialsPlanCSV},
 		{collection: "interstitials", format: "tsv", wantFile: "interstitials.tsv", wantBody: interstitialsPlanTSV},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.collection+"-"+tt.format, func(t *testing.T) {
-			gotFile, gotBody := initPlanTemplate(tt.collection, tt.format)
+			gotFile, gotBody := initPlanTemplate(builtinInitTemplates[0], tt.collection, tt.format)
 			if gotFile != tt.wantFile {
 				t.Fatalf("file = %q, want %q", gotFile, tt.wantFile)
 			}
@@ -143,3 +152,88 @@ func TestRenderDefaultConfigYAMLUsesRequestedPlanFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestInitBuiltinTemplatesValidate(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	for _, tmpl := range builtinInitTemplates {
+		for _, format := range []string{"yaml", "json", "csv"} {
+			t.Run(tmpl.Name+"-"+format, func(t *testing.T) {
+				cmd := &cobra.Command{}
+				cmd.SetOut(io.Discard)
+				pp, err := initProject(cmd, filepath.Join(t.TempDir(), "party"), format, tmpl)
+				if err != nil {
+					t.Fatalf("initProject: %v", err)
+				}
+				cfg, err := config.Load(pp.ConfigFile)
+				if err != nil {
+					t.Fatalf("load config: %v", err)
+				}
+				for _, r := range cfg.ValidateStrict(pp.Root, render.ValidSegmentTokens()) {
+					if r.Level == "error" {
+						t.Errorf("validation error: %s", r.Message)
+					}
+				}
+				if cfg.Collections["songs"].Plan == "" {
+					t.Fatal("template has no songs collection")
+				}
+				for _, name := range []string{"songs", "interstitials"} {
+					if ok, _ := paths.FileExists(filepath.Join(pp.Root, name+".example.csv")); !ok {
+						t.Errorf("%s.example.csv not written", name)
+					}
+					plan := filepath.Join(pp.Root, cfg.Collections[name].Plan)
+					if csvplan.IsStructuredPlan(plan) {
+						if _, err := csvplan.LoadCollectionStructured(plan, csvplan.CollectionOptions{}); err != nil {
+							t.Errorf("load %s plan: %v", name, err)
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestInitUserTemplate(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	tmplDir := filepath.Join(home, ".powerhour", "templates", "office")
+	if err := os.MkdirAll(filepath.Join(tmplDir, "videos"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"powerhour.yaml":   "version: 1\n",
+		"videos/intro.txt": "intro",
+		"songs.yaml":       "rows: []\n",
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(tmplDir, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tmpl, err := findInitTemplate("office")
+	if err != nil {
+		t.Fatalf("findInitTemplate: %v", err)
+	}
+	dir := filepath.Join(t.TempDir(), "party")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "songs.yaml"), []byte("mine"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	if _, err := initProject(cmd, dir, "yaml", tmpl); err != nil {
+		t.Fatalf("initProject: %v", err)
+	}
+	for name, want := range map[string]string{"powerhour.yaml": "version: 1\n", "videos/intro.txt": "intro", "songs.yaml": "mine"} {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil || string(got) != want {
+			t.Errorf("%s = %q, %v; want %q", name, got, err, want)
+		}
+	}
+
+	if _, err := findInitTemplate("nope"); err == nil || !strings.Contains(err.Error(), "office") {
+		t.Errorf("unknown template error = %v, want the available names", err)
+	}
+}
@@ -0,0 +1,335 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/config"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+	"powerhour/internal/tools"
+)
+
+var (
+	logsFailed bool
+	logsLines  int
+	logsSince  time.Duration
+)
+
+// renderLogPattern recognizes one kind of ffmpeg failure in a segment log.
+type renderLogPattern struct {
+	Kind   string
+	Label  string
+	Match  *regexp.Regexp
+	Remedy []string
+}
+
+// renderLogPatterns are tried in order; the first match classifies the log.
+var renderLogPatterns = []renderLogPattern{
+	{Kind: "disk_full", Label: "Disk full",
+		Match:  regexp.MustCompile(`(?i)no space left on device|disk quota exceeded`),
+		Remedy: []string{"Free up disk space, e.g. powerhour clean segments or powerhour cache doctor"}},
+	{Kind: "missing_filter", Label: "Missing ffmpeg filter",
+		Match: regexp.MustCompile(`(?i)no such filter: '([^']+)'|filter not found`)},
+	{Kind: "missing_encoder", Label: "Encoder not available",
+		Match:  regexp.MustCompile(`(?i)unknown encoder '([^']+)'|encoder not found|error while opening encoder`),
+		Remedy: []string{"Pick an encoder this ffmpeg has: powerhour tools encoding", "or re-detect encoders: powerhour tools reprobe"}},
+	{Kind: "pix_fmt", Label: "Unsupported pixel format",
+		Match:  regexp.MustCompile(`(?i)incompatible pixel format|pixel format .* (?:is )?not supported|does not support pixel format|doesn't support .*pix_fmt|no pixel format|unsupported pixel format`),
+		Remedy: []string{"The encoder can't take the source's pixel format; switch video codec with powerhour tools encoding", "or re-fetch the source in a standard format: powerhour fetch --force --index <n>"}},
+	{Kind: "font", Label: "Overlay font not found",
+		Match:  regexp.MustCompile(`(?i)cannot find a valid font|could not load font|fontconfig error|cannot load font`),
+		Remedy: []string{"Install the font or set a font path in the overlay options, then re-render"}},
+	{Kind: "missing_input", Label: "Source file missing",
+		Match:  regexp.MustCompile(`(?i)no such file or directory`),
+		Remedy: []string{"Re-fetch the source: powerhour fetch --index <n>"}},
+	{Kind: "corrupt_input", Label: "Corrupt or unreadable source",
+		Match:  regexp.MustCompile(`(?i)invalid data found when processing input|moov atom not found|error while decoding|corrupt|truncat|premature end|invalid nal unit`),
+		Remedy: []string{"Re-download the source: powerhour fetch --force --index <n>", "then check it: powerhour library verify"}},
+}
+
+// logTriage is one segment's log and, for failures, its classification.
+type logTriage struct {
+	Collection string    `json:"collection"`
+	Index      int       `json:"index"`
+	Title      string    `json:"title,omitempty"`
+	Segment    string    `json:"segment"`
+	LogPath    string    `json:"log_path"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	Failed     bool      `json:"failed"`
+	Kind       string    `json:"kind,omitempty"`
+	Cause      string    `json:"cause,omitempty"`
+	Detail     string    `json:"detail,omitempty"`
+	Remedy     []string  `json:"remedy,omitempty"`
+	Tail       []string  `json:"tail,omitempty"`
+}
+
+func newLogsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "List segment render logs and triage failed renders",
+		Long: `List the ffmpeg log of every segment in the plan. A segment failed its
+last render when its log ends with a "render failed" line (render keeps
+the previous output), or when the log exists but the output doesn't.
+
+--failed shows only those, with the tail of each log, the likely cause
+(missing filter, missing encoder, corrupt input, unsupported pixel format,
+missing font, disk full), and how to fix it.`,
+		Args: cobra.NoArgs,
+		RunE: runLogs,
+	}
+	cmd.Flags().BoolVar(&logsFailed, "failed", false, "Only show failed renders, with log tails and remediation")
+	cmd.Flags().IntVar(&logsLines, "lines", 15, "Log lines to show per failure")
+	cmd.Flags().DurationVar(&logsSince, "since", 0, "Only logs written within this long, e.g. 2h (default: all)")
+	return cmd
+}
+
+func runLogs(cmd *cobra.Command, _ []string) error {
+	glogf, gcloser := logx.StartCommand("logs")
+	defer gcloser.Close()
+	glogf("logs started: failed=%v since=%s", logsFailed, logsSince)
+
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
+	if len(cfg.Collections) == 0 {
+		return fmt.Errorf("no collections configured")
+	}
+	resolver, err := project.NewCollectionResolver(cfg, pp)
+	if err != nil {
+		return fmt.Errorf("create collection resolver: %w", err)
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		return fmt.Errorf("load collections: %w", err)
+	}
+	idx, err := cache.Load(pp)
+	if err != nil {
+		return fmt.Errorf("load cache index: %w", err)
+	}
+	clips, segments, err := buildProjectSegments(pp, cfg, idx, resolver, collections)
+	if err != nil {
+		return err
+	}
+
+	var cutoff time.Time
+	if logsSince > 0 {
+		cutoff = time.Now().Add(-logsSince)
+	}
+	var entries []logTriage
+	ffmpegMethod := ""
+	for i, cc := range clips {
+		seg := segments[i]
+		stem := strings.TrimSuffix(filepath.Base(seg.OutputPath), filepath.Ext(seg.OutputPath))
+		logPath := filepath.Join(pp.LogsDir, stem+".log")
+		info, err := os.Stat(logPath)
+		if err != nil || info.ModTime().Before(cutoff) {
+			continue
+		}
+		data, err := os.ReadFile(logPath)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", logPath, err)
+		}
+		_, outErr := os.Stat(seg.OutputPath)
+		entry := logTriage{
+			Collection: cc.CollectionName,
+			Index:      cc.Clip.Row.Index,
+			Title:      cc.Clip.Row.Title,
+			Segment:    seg.OutputPath,
+			LogPath:    logPath,
+			UpdatedAt:  info.ModTime(),
+			Failed:     os.IsNotExist(outErr) || render.LogShowsFailure(string(data)),
+		}
+		if entry.Failed {
+			entry.Kind, entry.Cause, entry.Detail, entry.Remedy = classifyRenderLog(string(data))
+			if entry.Kind == "missing_filter" {
+				if ffmpegMethod == "" {
+					ffmpegMethod = detectFFmpegInstallMethod(cmd)
+				}
+				entry.Remedy = tools.FilterRemediation([]string{entry.Detail}, ffmpegMethod)
+			}
+			for j, r := range entry.Remedy {
+				entry.Remedy[j] = strings.ReplaceAll(r, "<n>", fmt.Sprint(entry.Index))
+			}
+			entry.Tail = tailLines(string(data), logsLines)
+		} else if logsFailed {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].UpdatedAt.After(entries[j].UpdatedAt)
+	})
+	glogf("logs: %d entries", len(entries))
+
+	if outputJSON {
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	out := cmd.OutOrStdout()
+	if len(entries) == 0 {
+		if logsFailed {
+			fmt.Fprintln(out, "No failed renders.")
+		} else {
+			fmt.Fprintf(out, "No segment logs in %s.\n", pp.LogsDir)
+		}
+		return nil
+	}
+
+	bold := lipgloss.NewStyle().Bold(true).Inline(true)
+	green := lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Inline(true)
+	red := lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Inline(true)
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Inline(true)
+
+	if !logsFailed {
+		for _, e := range entries {
+			status := green.Render("ok    ")
+			note := ""
+			if e.Failed {
+				status = red.Render("FAILED")
+				note = "  " + e.Cause
+			}
+			fmt.Fprintf(out, "  %s  %-14s #%03d  %s  %s%s\n", status, e.Collection, e.Index,
+				e.UpdatedAt.Local().Format(time.DateTime), relPath(pp.Root, e.LogPath), note)
+		}
+		return nil
+	}
+
+	counts := map[string]int{}
+	for i, e := range entries {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		counts[e.Cause]++
+		heading := fmt.Sprintf("%s #%03d", e.Collection, e.Index)
+		if e.Title != "" {
+			heading += " — " + e.Title
+		}
+		fmt.Fprintln(out, red.Render("✘ ")+bold.Render(heading)+" "+dim.Render(e.UpdatedAt.Local().Format(time.DateTime)))
+		fmt.Fprintf(out, "  %-7s %s\n", "Log:", relPath(pp.Root, e.LogPath))
+		cause := e.Cause
+		if e.Detail != "" {
+			cause += " (" + e.Detail + ")"
+		}
+		fmt.Fprintf(out, "  %-7s %s\n", "Cause:", cause)
+		for j, r := range e.Remedy {
+			label := ""
+			if j == 0 {
+				label = "Fix:"
+			}
+			fmt.Fprintf(out, "  %-7s %s\n", label, r)
+		}
+		if len(e.Tail) > 0 {
+			fmt.Fprintln(out, dim.Render(fmt.Sprintf("  ── last %d lines ──", len(e.Tail))))
+			for _, line := range e.Tail {
+				fmt.Fprintln(out, dim.Render("  │ ")+line)
+			}
+		}
+	}
+
+	causes := make([]string, 0, len(counts))
+	for cause, n := range counts {
+		causes = append(causes, fmt.Sprintf("%d %s", n, strings.ToLower(cause)))
+	}
+	sort.Strings(causes)
+	fmt.Fprintf(out, "\n%d failed segments: %s\n", len(entries), joinComma(causes))
+	fmt.Fprintln(out, "Re-run powerhour render after fixing; only failed and stale segments render again.")
+	return nil
+}
+
+// classifyRenderLog matches a failed render log against renderLogPatterns
+// and returns the kind, a label, the matched detail (the filter or encoder
+// name when captured, otherwise the matching line), and remediation.
+func classifyRenderLog(text string) (kind, label, detail string, remedy []string) {
+	for _, p := range renderLogPatterns {
+		loc := p.Match.FindStringSubmatchIndex(text)
+		if loc == nil {
+			continue
+		}
+		if len(loc) >= 4 && loc[2] >= 0 {
+			detail = text[loc[2]:loc[3]]
+		} else {
+			detail = matchedLine(text, loc[0])
+		}
+		return p.Kind, p.Label, detail, append([]string(nil), p.Remedy...)
+	}
+	return "unknown", "Unrecognized ffmpeg error", lastNonEmptyLine(text),
+		[]string{"Read the full log; re-run with powerhour render --index <n> --no-progress to see ffmpeg output"}
+}
+
+func matchedLine(text string, offset int) string {
+	start := strings.LastIndex(text[:offset], "\n") + 1
+	end := strings.Index(text[offset:], "\n")
+	if end < 0 {
+		return strings.TrimSpace(text[start:])
+	}
+	return strings.TrimSpace(text[start : offset+end])
+}
+
+func lastNonEmptyLine(text string) string {
+	lines := tailLines(text, 1)
+	if len(lines) == 0 {
+		return ""
+	}
+	return lines[0]
+}
+
+// tailLines returns the last n non-empty lines, splitting ffmpeg's
+// carriage-return progress updates into separate lines.
+func tailLines(text string, n int) []string {
+	text = strings.ReplaceAll(text, "\r", "\n")
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimRight(line, " \t"); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if n > 0 && len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
+
+func detectFFmpegInstallMethod(cmd *cobra.Command) string {
+	statuses, err := tools.Detect(cmd.Context())
+	if err != nil {
+		return ""
+	}
+	for _, st := range statuses {
+		if st.Tool == "ffmpeg" {
+			return st.InstallMethod
+		}
+	}
+	return ""
+}
+
+func relPath(root, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
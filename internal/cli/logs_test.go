@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClassifyRenderLog(t *testing.T) {
+	tests := []struct {
+		name       string
+		log        string
+		wantKind   string
+		wantDetail string
+	}{
+		{"missing filter", "[AVFilterGraph @ 0x1] No such filter: 'drawtext'\nError initializing complex filters.\n", "missing_filter", "drawtext"},
+		{"disk full wins", "frame=  120\nav_interleaved_write_frame(): No space left on device\nError writing trailer: No such file or directory\n", "disk_full", "av_interleaved_write_frame(): No space left on device"},
+		{"encoder", "Unknown encoder 'h264_videotoolbox'\n", "missing_encoder", "h264_videotoolbox"},
+		{"pix_fmt", "[libx264 @ 0x2] Incompatible pixel format 'yuv444p12le' for codec 'libx264'\n", "pix_fmt", "[libx264 @ 0x2] Incompatible pixel format 'yuv444p12le' for codec 'libx264'"},
+		{"corrupt", "[mov,mp4 @ 0x3] moov atom not found\ncache/abc.mp4: Invalid data found when processing input\n", "corrupt_input", "[mov,mp4 @ 0x3] moov atom not found"},
+		{"missing input", "cache/abc.webm: No such file or directory\n", "missing_input", "cache/abc.webm: No such file or directory"},
+		{"font", "[Parsed_drawtext_0 @ 0x4] Cannot find a valid font for the family Oswald\n", "font", "[Parsed_drawtext_0 @ 0x4] Cannot find a valid font for the family Oswald"},
+		{"unknown", "something odd happened\nConversion failed!\n\n", "unknown", "Conversion failed!"},
+	}
+	for _, tt := range tests {
+		kind, label, detail, remedy := classifyRenderLog(tt.log)
+		if kind != tt.wantKind || detail != tt.wantDetail {
+			t.Errorf("%s: got (%s, %q), want (%s, %q)", tt.name, kind, detail, tt.wantKind, tt.wantDetail)
+		}
+		if label == "" {
+			t.Errorf("%s: empty label", tt.name)
+		}
+		if kind != "missing_filter" && len(remedy) == 0 {
+			t.Errorf("%s: no remediation", tt.name)
+		}
+	}
+}
+
+func TestTailLines(t *testing.T) {
+	log := "one\n\ntwo\rframe=1\rframe=2\nthree  \n"
+	if got, want := tailLines(log, 3), []string{"frame=1", "frame=2", "three"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("tailLines(3) = %q, want %q", got, want)
+	}
+	if got := tailLines(log, 0); len(got) != 5 {
+		t.Errorf("tailLines(0) = %q, want all 5 lines", got)
+	}
+}
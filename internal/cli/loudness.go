@@ -0,0 +1,279 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/config"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+	"powerhour/internal/tools"
+)
+
+var (
+	loudnessTimeline  string
+	loudnessTarget    float64
+	loudnessTolerance float64
+	loudnessMaxPeak   float64
+	loudnessStrict    bool
+)
+
+// Loudness flags for a segment; the first three make it an outlier.
+const (
+	loudnessFlagLoud    = "loud"
+	loudnessFlagQuiet   = "quiet"
+	loudnessFlagPeak    = "peak"
+	loudnessFlagSilent  = "silent"
+	loudnessFlagMissing = "missing"
+	loudnessFlagError   = "error"
+)
+
+func newLoudnessCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "loudness",
+		Short: "Measure loudness of every rendered timeline segment",
+		Long: `Measure integrated loudness (LUFS), true peak and loudness range of
+every rendered segment in the timeline, and flag the ones that stand out.
+
+Segments are compared against the audio.loudnorm target when loudnorm is
+enabled, or the median of the measured segments otherwise. A segment more
+than --tolerance LU from the target, or peaking above --max-true-peak, is
+an outlier. Silent segments such as generated slates are listed but not
+flagged.
+
+Each segment is decoded in full, so this takes a while on a long timeline.`,
+		Args: cobra.NoArgs,
+		RunE: runLoudness,
+	}
+	cmd.Flags().StringVar(&loudnessTimeline, "timeline", "", "Measure a named timeline from timelines:")
+	cmd.Flags().Float64Var(&loudnessTarget, "target", 0, "Target integrated loudness in LUFS (default: loudnorm target, or the median)")
+	cmd.Flags().Float64Var(&loudnessTolerance, "tolerance", 2, "Allowed distance from the target in LU")
+	cmd.Flags().Float64Var(&loudnessMaxPeak, "max-true-peak", 0, "Highest allowed true peak in dBTP (default: loudnorm ceiling, or -1)")
+	cmd.Flags().BoolVar(&loudnessStrict, "strict", false, "Exit non-zero when any segment is an outlier")
+	return cmd
+}
+
+type loudnessSegment struct {
+	Slot       int              `json:"slot"`
+	Collection string           `json:"collection,omitempty"`
+	Index      int              `json:"index,omitempty"`
+	Path       string           `json:"path"`
+	Loudness   *render.Loudness `json:"loudness,omitempty"`
+	Deviation  float64          `json:"deviation_lu"`
+	Flags      []string         `json:"flags,omitempty"`
+	Error      string           `json:"error,omitempty"`
+}
+
+type loudnessReport struct {
+	Target       float64           `json:"target_lufs"`
+	TargetSource string            `json:"target_source"`
+	Tolerance    float64           `json:"tolerance_lu"`
+	MaxTruePeak  float64           `json:"max_true_peak_db"`
+	Segments     []loudnessSegment `json:"segments"`
+	Measured     int               `json:"measured"`
+	Outliers     int               `json:"outliers"`
+}
+
+func runLoudness(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if loudnessTolerance < 0 {
+		return fmt.Errorf("--tolerance must not be negative")
+	}
+
+	glogf, gcloser := logx.StartCommand("loudness")
+	defer gcloser.Close()
+	glogf("loudness started: timeline=%s tolerance=%.1f", loudnessTimeline, loudnessTolerance)
+
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
+	cfg, pp, err = applyNamedTimeline(cfg, pp, loudnessTimeline)
+	if err != nil {
+		return err
+	}
+	if len(cfg.Collections) == 0 {
+		return fmt.Errorf("no collections configured")
+	}
+
+	resolver, err := project.NewCollectionResolver(cfg, pp)
+	if err != nil {
+		return err
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		return err
+	}
+	segments, err := render.ResolveTimelineSegments(pp, cfg, collections)
+	if err != nil {
+		return fmt.Errorf("resolve timeline: %w", err)
+	}
+	if len(segments) == 0 {
+		return fmt.Errorf("timeline is empty")
+	}
+
+	ffmpegPath, err := tools.Lookup("ffmpeg")
+	if err != nil {
+		return fmt.Errorf("locate ffmpeg: %w", err)
+	}
+
+	report := loudnessReport{Tolerance: loudnessTolerance}
+	switch {
+	case cmd.Flags().Changed("target"):
+		report.Target, report.TargetSource = loudnessTarget, "flag"
+	case cfg.Audio.Loudnorm.EnabledValue():
+		report.Target, report.TargetSource = cfg.Audio.Loudnorm.IntegratedLUFSValue(), "loudnorm"
+	default:
+		report.TargetSource = "median"
+	}
+	switch {
+	case cmd.Flags().Changed("max-true-peak"):
+		report.MaxTruePeak = loudnessMaxPeak
+	case cfg.Audio.Loudnorm.EnabledValue():
+		report.MaxTruePeak = cfg.Audio.Loudnorm.TruePeakValue()
+	default:
+		report.MaxTruePeak = -1
+	}
+
+	report.Segments = measureLoudness(ctx, ffmpegPath, segments, func(seg loudnessSegment) {
+		glogf("segment %d %s: flags=%v err=%s", seg.Slot, seg.Path, seg.Flags, seg.Error)
+	})
+	flagLoudness(&report)
+	glogf("loudness finished: measured=%d outliers=%d target=%.1f", report.Measured, report.Outliers, report.Target)
+
+	if outputJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+	} else {
+		writeLoudnessTable(cmd, pp.Root, report)
+	}
+
+	if loudnessStrict && report.Outliers > 0 {
+		return fmt.Errorf("loudness: %d outlier segment(s)", report.Outliers)
+	}
+	return nil
+}
+
+// measureLoudness measures each distinct segment once, in timeline order.
+// Segments that aren't rendered or fail to measure are flagged rather than
+// aborting the report.
+func measureLoudness(ctx context.Context, ffmpegPath string, segments []render.TimelineSegmentPath, logSegment func(loudnessSegment)) []loudnessSegment {
+	seen := make(map[string]bool, len(segments))
+	var out []loudnessSegment
+	for i, seg := range segments {
+		if seen[seg.Path] {
+			continue
+		}
+		seen[seg.Path] = true
+		entry := loudnessSegment{Slot: i + 1, Collection: seg.CollectionName, Index: seg.Index, Path: seg.Path}
+		if _, err := os.Stat(seg.Path); err != nil {
+			entry.Flags = []string{loudnessFlagMissing}
+		} else if l, err := render.MeasureLoudness(ctx, ffmpegPath, seg.Path); err != nil {
+			entry.Flags = []string{loudnessFlagError}
+			entry.Error = err.Error()
+		} else {
+			entry.Loudness = &l
+		}
+		if logSegment != nil {
+			logSegment(entry)
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+// flagLoudness fills in the median target when none was given, then each
+// measured segment's deviation and flags, and the outlier count.
+func flagLoudness(report *loudnessReport) {
+	var levels []float64
+	for _, seg := range report.Segments {
+		if seg.Loudness != nil && !seg.Loudness.Silent {
+			levels = append(levels, seg.Loudness.Integrated)
+		}
+	}
+	report.Measured = len(levels)
+	if report.TargetSource == "median" && len(levels) > 0 {
+		sort.Float64s(levels)
+		mid := len(levels) / 2
+		report.Target = levels[mid]
+		if len(levels)%2 == 0 {
+			report.Target = (levels[mid-1] + levels[mid]) / 2
+		}
+	}
+
+	report.Outliers = 0
+	for i := range report.Segments {
+		seg := &report.Segments[i]
+		l := seg.Loudness
+		if l == nil {
+			continue
+		}
+		if l.Silent {
+			seg.Flags = []string{loudnessFlagSilent}
+			continue
+		}
+		seg.Flags = nil
+		seg.Deviation = math.Round((l.Integrated-report.Target)*10) / 10
+		switch {
+		case seg.Deviation > report.Tolerance:
+			seg.Flags = append(seg.Flags, loudnessFlagLoud)
+		case seg.Deviation < -report.Tolerance:
+			seg.Flags = append(seg.Flags, loudnessFlagQuiet)
+		}
+		if l.TruePeak > report.MaxTruePeak {
+			seg.Flags = append(seg.Flags, loudnessFlagPeak)
+		}
+		if len(seg.Flags) > 0 {
+			report.Outliers++
+		}
+	}
+}
+
+func writeLoudnessTable(cmd *cobra.Command, root string, report loudnessReport) {
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Target %.1f LUFS ±%.1f LU (%s), true peak ≤ %.1f dBTP\n\n", report.Target, report.Tolerance, report.TargetSource, report.MaxTruePeak)
+
+	w := tabwriter.NewWriter(out, 0, 2, 2, ' ', 0)
+	fmt.Fprintln(w, "SLOT\tSEGMENT\tLUFS\tTRUE PEAK\tLRA\tDEV\tFLAGS")
+	for _, seg := range report.Segments {
+		lufs, peak, lra, dev := "-", "-", "-", "-"
+		if l := seg.Loudness; l != nil && !l.Silent {
+			lufs = fmt.Sprintf("%.1f", l.Integrated)
+			peak = fmt.Sprintf("%.1f", l.TruePeak)
+			lra = fmt.Sprintf("%.1f", l.LRA)
+			dev = fmt.Sprintf("%+.1f", seg.Deviation)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", seg.Slot, relPath(root, seg.Path), lufs, peak, lra, dev, strings.Join(seg.Flags, ","))
+	}
+	w.Flush()
+
+	fmt.Fprintf(out, "\n%d segments measured, %d outliers\n", report.Measured, report.Outliers)
+	for _, seg := range report.Segments {
+		if seg.Error != "" {
+			fmt.Fprintf(out, "  %s: %s\n", filepath.Base(seg.Path), seg.Error)
+		}
+	}
+}
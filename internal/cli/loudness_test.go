@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"powerhour/internal/render"
+)
+
+func TestFlagLoudness(t *testing.T) {
+	measured := func(i, tp float64) *render.Loudness {
+		return &render.Loudness{Integrated: i, TruePeak: tp, LRA: 6}
+	}
+	report := loudnessReport{
+		TargetSource: "median",
+		Tolerance:    2,
+		MaxTruePeak:  -1,
+		Segments: []loudnessSegment{
+			{Slot: 1, Loudness: &render.Loudness{Silent: true}},
+			{Slot: 2, Loudness: measured(-14, -2)},
+			{Slot: 3, Loudness: measured(-15, -0.5)},
+			{Slot: 4, Loudness: measured(-9, -3)},
+			{Slot: 5, Loudness: measured(-20, -6)},
+			{Slot: 6, Flags: []string{loudnessFlagMissing}},
+		},
+	}
+	flagLoudness(&report)
+
+	if report.Target != -14.5 {
+		t.Errorf("median target = %v, want -14.5", report.Target)
+	}
+	if report.Measured != 4 || report.Outliers != 3 {
+		t.Errorf("measured/outliers = %d/%d, want 4/3", report.Measured, report.Outliers)
+	}
+	want := [][]string{
+		{loudnessFlagSilent},
+		nil,
+		{loudnessFlagPeak},
+		{loudnessFlagLoud},
+		{loudnessFlagQuiet},
+		{loudnessFlagMissing},
+	}
+	for i, seg := range report.Segments {
+		if !reflect.DeepEqual(seg.Flags, want[i]) {
+			t.Errorf("slot %d flags = %v, want %v", seg.Slot, seg.Flags, want[i])
+		}
+	}
+	if report.Segments[3].Deviation != 5.5 {
+		t.Errorf("slot 4 deviation = %v, want 5.5", report.Segments[3].Deviation)
+	}
+
+	fixed := loudnessReport{Target: -14, TargetSource: "loudnorm", Tolerance: 1, MaxTruePeak: -1.5,
+		Segments: []loudnessSegment{{Slot: 1, Loudness: measured(-15.5, -2)}}}
+	flagLoudness(&fixed)
+	if fixed.Target != -14 || !reflect.DeepEqual(fixed.Segments[0].Flags, []string{loudnessFlagQuiet}) {
+		t.Errorf("fixed target report = %+v", fixed)
+	}
+}
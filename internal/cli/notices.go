@@ -16,7 +16,7 @@ import (
 // Called from PersistentPostRun on the root command. The check is gated by a
 // 24-hour cache so most runs only read a small JSON file.
 func printUpdateNotices(cmd *cobra.Command) {
-	if outputJSON {
+	if outputJSON || logQuiet {
 		return
 	}
 
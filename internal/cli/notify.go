@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/config"
+	"powerhour/internal/notify"
+	"powerhour/internal/paths"
+	"powerhour/internal/render"
+)
+
+// notifySummary is the counts line of the current fetch, render or concat,
+// sent along with its notification.
+var notifySummary string
+
+const notifyTimeout = 30 * time.Second
+
+// withNotify wraps a long-running command so notify: hears when it finishes
+// or fails. Dry runs and estimates never notify.
+func withNotify(command string, run func(*cobra.Command, []string) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		notifySummary = ""
+		start := time.Now()
+		err := run(cmd, args)
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		estimate, _ := cmd.Flags().GetBool("estimate")
+		if !dryRun && !estimate {
+			sendRunNotification(cmd, command, err, time.Since(start))
+		}
+		return err
+	}
+}
+
+func sendRunNotification(cmd *cobra.Command, command string, runErr error, elapsed time.Duration) {
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return
+	}
+	// A config that doesn't load has already failed the run.
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil || !cfg.Notify.NotifiesFor(command) {
+		return
+	}
+	if elapsed < time.Duration(cfg.Notify.MinSeconds)*time.Second {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+	msg := notify.Message{
+		Command: command,
+		Project: filepath.Base(pp.Root),
+		Summary: notifySummary,
+		Elapsed: elapsed,
+		Err:     runErr,
+	}
+	if err := notify.Send(ctx, cfg.Notify, msg); err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: notify: %v\n", err)
+	}
+}
+
+func fetchSummaryLine(counts fetchCounts) string {
+	return fmt.Sprintf("Downloaded: %d, Matched: %d, Reused: %d, Missing: %d, Probed: %d, Failed: %d",
+		counts.Downloaded, counts.Matched, counts.Reused, counts.Missing, counts.Probed, counts.Failed,
+	)
+}
+
+func renderSummaryLine(results []render.Result) string {
+	var rendered, skipped, failed int
+	for _, res := range results {
+		if res.Err != nil {
+			failed++
+		} else if res.Skipped {
+			skipped++
+		} else {
+			rendered++
+		}
+	}
+	return fmt.Sprintf("Rendered: %d, Skipped: %d, Failed: %d", rendered, skipped, failed)
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestWithNotifyPostsWebhook(t *testing.T) {
+	var posts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		var doc map[string]string
+		_ = json.Unmarshal(data, &doc)
+		posts = append(posts, doc["text"])
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	projectDir = dir
+	t.Cleanup(func() { projectDir = "" })
+	writeTestProjectFiles(t, dir)
+	f, err := os.OpenFile(filepath.Join(dir, "powerhour.yaml"), os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("\nnotify:\n  webhook: " + srv.URL + "\n  commands: [render]\n"); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("dry-run", false, "")
+		cmd.SetErr(io.Discard)
+		return cmd
+	}
+	run := func(err error) func(*cobra.Command, []string) error {
+		return func(*cobra.Command, []string) error {
+			notifySummary = "Rendered: 3, Skipped: 1, Failed: 0"
+			return err
+		}
+	}
+
+	if err := withNotify("render", run(nil))(newCmd(), nil); err != nil {
+		t.Fatal(err)
+	}
+	boom := errors.New("ffmpeg exited 1")
+	if err := withNotify("render", run(boom))(newCmd(), nil); !errors.Is(err, boom) {
+		t.Fatalf("run error not returned: %v", err)
+	}
+	// Not in notify.commands.
+	if err := withNotify("fetch", run(nil))(newCmd(), nil); err != nil {
+		t.Fatal(err)
+	}
+	dry := newCmd()
+	_ = dry.Flags().Set("dry-run", "true")
+	if err := withNotify("render", run(nil))(dry, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(posts) != 2 {
+		t.Fatalf("expected 2 webhook posts, got %d: %q", len(posts), posts)
+	}
+	if !strings.HasPrefix(posts[0], "powerhour render finished (") || !strings.Contains(posts[0], "Rendered: 3, Skipped: 1, Failed: 0\nElapsed: ") {
+		t.Errorf("unexpected success post %q", posts[0])
+	}
+	if !strings.Contains(posts[1], "render failed") || !strings.Contains(posts[1], "Error: ffmpeg exited 1") {
+		t.Errorf("unexpected failure post %q", posts[1])
+	}
+}
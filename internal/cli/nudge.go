@@ -0,0 +1,422 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/config"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+	"powerhour/internal/render/state"
+	"powerhour/pkg/csvplan"
+)
+
+var (
+	nudgeCollection     string
+	nudgeIndexArg       []string
+	nudgeBy             string
+	nudgePreview        bool
+	nudgePreviewSeconds int
+)
+
+func newNudgeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "nudge",
+		Short: "Shift a row's start time and mark its segment for re-render",
+		Long: `Move a plan row's start_time earlier or later by a fixed offset, write the
+plan, and mark the row's rendered segment stale so the next render picks it
+up.
+
+The offset accepts a signed duration, timecode, or seconds: -3s, +1.5s,
+-0:03, 2. With --preview, the first seconds of the nudged clip are rendered
+to samples/ so the new cut can be checked without re-rendering the segment.
+
+Rows whose start time comes from the collection's overrides file can't be
+nudged here; edit the override instead. Neither can rows with a windows
+column; edit the windows.`,
+		Example: `  powerhour nudge --index 12 --by -3s
+  powerhour nudge --index 12 --by +1.5s --preview
+  powerhour nudge --collection interstitials --index 4 --by 0:02`,
+		Args: cobra.NoArgs,
+		RunE: runNudge,
+	}
+	cmd.Flags().StringVar(&nudgeCollection, "collection", "", "Collection to change (default: the only collection, or songs)")
+	cmd.Flags().StringSliceVar(&nudgeIndexArg, "index", nil, "1-based row index or range like 5-10 (repeat flag for multiple; required)")
+	cmd.Flags().StringVar(&nudgeBy, "by", "", "Signed offset to add to the start time, e.g. -3s, +1.5s, -0:03 (required)")
+	cmd.Flags().BoolVar(&nudgePreview, "preview", false, "Render the first seconds of each nudged clip to samples/")
+	cmd.Flags().IntVar(&nudgePreviewSeconds, "preview-seconds", 10, "Length of the --preview render in seconds")
+	_ = cmd.MarkFlagRequired("index")
+	_ = cmd.MarkFlagRequired("by")
+	return cmd
+}
+
+type nudgeChange struct {
+	Index   int    `json:"index"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Segment string `json:"segment,omitempty"`
+	Stale   bool   `json:"stale"`
+	Preview string `json:"preview,omitempty"`
+}
+
+func runNudge(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	indexes, err := parseIndexArgs(nudgeIndexArg)
+	if err != nil {
+		return err
+	}
+	if len(indexes) == 0 {
+		return fmt.Errorf("--index is required")
+	}
+	offset, err := parseNudgeOffset(nudgeBy)
+	if err != nil {
+		return fmt.Errorf("invalid --by %q: %w", nudgeBy, err)
+	}
+	if nudgePreview && nudgePreviewSeconds <= 0 {
+		return fmt.Errorf("--preview-seconds must be positive")
+	}
+
+	glogf, gcloser := logx.StartCommand("nudge")
+	defer gcloser.Close()
+	glogf("nudge started: collection=%s indexes=%v by=%s preview=%t", nudgeCollection, indexes, offset, nudgePreview)
+
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
+
+	resolver, err := project.NewCollectionResolver(cfg, pp)
+	if err != nil {
+		return err
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		return err
+	}
+	name, err := pickPlanCollection(collections, nudgeCollection)
+	if err != nil {
+		return err
+	}
+	coll := collections[name]
+	if coll.Plan == "" {
+		return fmt.Errorf("collection %q has no plan file", name)
+	}
+
+	idx, err := cache.Load(pp)
+	if err != nil {
+		return err
+	}
+	// Segment paths are resolved before the edit: they can include the
+	// start time, and the stale entry is the one rendered from the old cut.
+	segments, err := nudgeSegments(pp, cfg, idx, resolver, collections, name)
+	if err != nil {
+		return err
+	}
+
+	startHeader := project.CollectionOptionsForConfig(coll).StartHeader
+	if startHeader == "" {
+		startHeader = "start_time"
+	}
+	coll, changes, err := nudgeCollectionRows(coll, startHeader, indexes, offset)
+	if err != nil {
+		return err
+	}
+	if err := project.WriteCollectionPlan(coll); err != nil {
+		return err
+	}
+	collections[name] = coll
+
+	var outputs []string
+	for i := range changes {
+		if seg, ok := segments[changes[i].Index]; ok && seg.OutputPath != "" {
+			changes[i].Segment = seg.OutputPath
+			outputs = append(outputs, seg.OutputPath)
+		}
+	}
+	stale, err := markSegmentsStale(pp.RenderStateFile, outputs)
+	if err != nil {
+		return err
+	}
+	for i := range changes {
+		changes[i].Stale = stale[changes[i].Segment]
+	}
+	glogf("nudge: wrote %s, marked %d segment(s) stale", coll.Plan, len(stale))
+
+	if nudgePreview {
+		if err := renderNudgePreviews(ctx, cmd, pp, cfg, idx, resolver, collections, name, changes, glogf); err != nil {
+			return err
+		}
+	}
+
+	if outputJSON {
+		data, err := json.MarshalIndent(struct {
+			Collection string        `json:"collection"`
+			By         float64       `json:"by_s"`
+			Changes    []nudgeChange `json:"changes"`
+		}{name, offset.Seconds(), changes}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	for _, c := range changes {
+		cmd.Printf("Row %d start %s → %s", c.Index, c.From, c.To)
+		if c.Stale {
+			cmd.Printf(" (%s marked stale)", relPath(pp.Root, c.Segment))
+		}
+		cmd.Println()
+		if c.Preview != "" {
+			cmd.Printf("  preview: %s\n", relPath(pp.Root, c.Preview))
+		}
+	}
+	cmd.Printf("Saved %s\n", coll.Plan)
+	return nil
+}
+
+// nudgeSegments returns the render segments of one collection keyed by row
+// index.
+func nudgeSegments(pp paths.ProjectPaths, cfg config.Config, idx *cache.Index, resolver *project.CollectionResolver, collections map[string]project.Collection, name string) (map[int]render.Segment, error) {
+	clips, segments, err := buildProjectSegments(pp, cfg, idx, resolver, collections)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[int]render.Segment)
+	for i, cc := range clips {
+		if cc.CollectionName == name {
+			out[cc.Clip.Row.Index] = segments[i]
+		}
+	}
+	return out, nil
+}
+
+// renderNudgePreviews renders the opening seconds of each nudged row to
+// samples/, leaving the segment and its render state alone.
+func renderNudgePreviews(ctx context.Context, cmd *cobra.Command, pp paths.ProjectPaths, cfg config.Config, idx *cache.Index, resolver *project.CollectionResolver, collections map[string]project.Collection, name string, changes []nudgeChange, glogf func(string, ...any)) error {
+	segments, err := nudgeSegments(pp, cfg, idx, resolver, collections, name)
+	if err != nil {
+		return err
+	}
+	samplesDir := filepath.Join(pp.Root, "samples")
+	if err := os.MkdirAll(samplesDir, 0o755); err != nil {
+		return fmt.Errorf("create samples directory: %w", err)
+	}
+
+	var previews []render.Segment
+	var rows []int
+	for i, c := range changes {
+		seg, ok := segments[c.Index]
+		if !ok {
+			continue
+		}
+		if seg.SourcePath == "" {
+			return fmt.Errorf("preview row %d: source not cached; run `powerhour fetch --index %d` first", c.Index, c.Index)
+		}
+		seconds := nudgePreviewSeconds
+		if d := seg.Clip.DurationSeconds; d > 0 && d < seconds {
+			seconds = d
+		}
+		seg.Clip.DurationSeconds = seconds
+		seg.Clip.Row.DurationSeconds = seconds
+		base := render.SegmentBaseName(cfg.SegmentFilenameTemplate(), seg)
+		if base == "" {
+			base = fmt.Sprintf("segment_%03d", c.Index)
+		}
+		seg.OutputPath = filepath.Join(samplesDir, base+"_nudge_preview.mp4")
+		changes[i].Preview = seg.OutputPath
+		previews = append(previews, seg)
+		rows = append(rows, i)
+	}
+	if len(previews) == 0 {
+		return nil
+	}
+
+	svc, err := render.NewService(ctx, pp, cfg, nil)
+	if err != nil {
+		return err
+	}
+	if !outputJSON {
+		svc.SetWriters(cmd.OutOrStdout(), nil)
+	}
+	for i, res := range svc.Render(ctx, previews, render.Options{Force: true}) {
+		if res.Err != nil {
+			return fmt.Errorf("preview row %d: %w", changes[rows[i]].Index, res.Err)
+		}
+		glogf("nudge: preview row %d -> %s", changes[rows[i]].Index, res.OutputPath)
+	}
+	return nil
+}
+
+// nudgeCollectionRows shifts the start time of the given 1-based rows by
+// offset. Rows whose start time is overridden, or that would start before
+// zero, are rejected so the plan is written all or nothing.
+func nudgeCollectionRows(coll project.Collection, startHeader string, indexes []int, offset time.Duration) (project.Collection, []nudgeChange, error) {
+	byIndex := make(map[int]int, len(coll.Rows))
+	for i, row := range coll.Rows {
+		byIndex[row.Index] = i
+	}
+	var missing []int
+	for _, idx := range indexes {
+		if _, ok := byIndex[idx]; !ok {
+			missing = append(missing, idx)
+		}
+	}
+	if len(missing) > 0 {
+		return coll, nil, fmt.Errorf("collection %q has no row(s) %s (1-%d)", coll.Name, formatIndexList(missing), len(coll.Rows))
+	}
+
+	rows := append([]csvplan.CollectionRow(nil), coll.Rows...)
+	seen := make(map[int]bool, len(indexes))
+	var changes []nudgeChange
+	for _, idx := range indexes {
+		if seen[idx] {
+			continue
+		}
+		seen[idx] = true
+		row := rows[byIndex[idx]]
+		if ov, ok := coll.RowOverrideFor(row); ok && ov.StartTime != "" {
+			return coll, nil, fmt.Errorf("row %d start time is set by the overrides file (%s); edit it there", idx, ov.StartTime)
+		}
+		if err := checkRowWindows(row); err != nil {
+			return coll, nil, err
+		}
+		start := row.Start + offset
+		if start < 0 {
+			return coll, nil, fmt.Errorf("row %d starts at %s; nudging by %s would start before 0:00", idx, formatStartTime(row.Start), offset)
+		}
+		raw := formatStartTime(start)
+		changes = append(changes, nudgeChange{Index: idx, From: firstNonEmpty(row.StartRaw, formatStartTime(row.Start)), To: raw})
+
+		rows[byIndex[idx]] = setRowStart(row, startHeader, start)
+	}
+	coll.Rows = rows
+	coll.Headers = csvplan.MergeHeaders(coll.Headers, rows)
+	return coll, changes, nil
+}
+
+// setRowStart returns row with its start time, and the plan's start column,
+// set to start. A row with an end time (a start range or end_time) keeps
+// its clip length, so the end moves with the start.
+func setRowStart(row csvplan.CollectionRow, startHeader string, start time.Duration) csvplan.CollectionRow {
+	raw := formatStartTime(start)
+	end := formatStartTime(start + time.Duration(row.DurationSeconds)*time.Second)
+	row.StartRaw = raw
+	row.Start = start
+	fields := make(map[string]string, len(row.CustomFields)+1)
+	for k, v := range row.CustomFields {
+		fields[k] = v
+	}
+	switch {
+	case isTimeRange(fields[startHeader]):
+		fields[startHeader] = raw + "-" + end
+	case strings.TrimSpace(fields[csvplan.EndTimeField]) != "":
+		fields[startHeader] = raw
+		fields[csvplan.EndTimeField] = end
+	default:
+		fields[startHeader] = raw
+	}
+	row.CustomFields = fields
+	return row
+}
+
+// checkRowWindows refuses start edits on a row whose clips come from its
+// windows column, since the start column doesn't place them.
+func checkRowWindows(row csvplan.CollectionRow) error {
+	if windows := strings.TrimSpace(row.CustomFields[csvplan.WindowsField]); windows != "" {
+		return fmt.Errorf("row %d plays the windows %q; edit the %s column instead", row.Index, windows, csvplan.WindowsField)
+	}
+	return nil
+}
+
+func isTimeRange(value string) bool {
+	_, _, ok := csvplan.SplitTimeRange(value)
+	return ok
+}
+
+// markSegmentsStale clears the stored input hash of each rendered output so
+// status and render treat it as stale, and returns the outputs it marked.
+// Outputs that were never rendered have nothing to mark.
+func markSegmentsStale(stateFile string, outputs []string) (map[string]bool, error) {
+	rs, _ := state.Load(stateFile)
+	marked := make(map[string]bool, len(outputs))
+	for _, output := range outputs {
+		entry, ok := rs.Segments[output]
+		if !ok {
+			continue
+		}
+		entry.InputHash = ""
+		rs.Segments[output] = entry
+		marked[output] = true
+	}
+	if len(marked) == 0 {
+		return marked, nil
+	}
+	if err := rs.Save(stateFile); err != nil {
+		return nil, fmt.Errorf("save render state: %w", err)
+	}
+	return marked, nil
+}
+
+// parseNudgeOffset parses a signed offset: a leading + or - followed by
+// anything parseSampleTime accepts.
+func parseNudgeOffset(raw string) (time.Duration, error) {
+	value := strings.TrimSpace(raw)
+	sign := 1.0
+	switch {
+	case strings.HasPrefix(value, "-"):
+		sign, value = -1, value[1:]
+	case strings.HasPrefix(value, "+"):
+		value = value[1:]
+	}
+	seconds, err := parseSampleTime(value)
+	if err != nil {
+		return 0, err
+	}
+	if seconds < 0 {
+		return 0, fmt.Errorf("offset must have a single leading sign")
+	}
+	offset := time.Duration(math.Round(sign*seconds*1000)) * time.Millisecond
+	if offset == 0 {
+		return 0, fmt.Errorf("offset must not be zero")
+	}
+	return offset, nil
+}
+
+// formatStartTime renders a start time the way plan files write it: M:SS,
+// or H:MM:SS past the hour, with milliseconds only when needed.
+func formatStartTime(d time.Duration) string {
+	d = d.Round(time.Millisecond)
+	total := int(d / time.Second)
+	ms := int((d % time.Second) / time.Millisecond)
+	h, m, s := total/3600, (total%3600)/60, total%60
+
+	out := fmt.Sprintf("%d:%02d", m, s)
+	if h > 0 {
+		out = fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	if ms > 0 {
+		out += strings.TrimRight(fmt.Sprintf(".%03d", ms), "0")
+	}
+	return out
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"powerhour/internal/project"
+	"powerhour/pkg/csvplan"
+)
+
+func TestParseNudgeOffset(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "-3s", want: -3 * time.Second},
+		{in: "+1.5s", want: 1500 * time.Millisecond},
+		{in: "2", want: 2 * time.Second},
+		{in: "-0:03", want: -3 * time.Second},
+		{in: "1m5s", want: 65 * time.Second},
+		{in: "0", wantErr: true},
+		{in: "--3s", wantErr: true},
+		{in: "+-3s", wantErr: true},
+		{in: "soon", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseNudgeOffset(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseNudgeOffset(%q) = %v, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseNudgeOffset(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestFormatStartTime(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{0, "0:00"},
+		{87 * time.Second, "1:27"},
+		{87*time.Second + 500*time.Millisecond, "1:27.5"},
+		{time.Hour + 2*time.Minute + 3*time.Second + 25*time.Millisecond, "1:02:03.025"},
+	}
+	for _, tt := range tests {
+		got := formatStartTime(tt.in)
+		if got != tt.want {
+			t.Errorf("formatStartTime(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+		if parsed, err := csvplan.ParseStartTime(got); err != nil || parsed != tt.in {
+			t.Errorf("ParseStartTime(%q) = %v, %v; want %v", got, parsed, err, tt.in)
+		}
+	}
+}
+
+func TestNudgeCollectionRows(t *testing.T) {
+	coll := project.Collection{
+		Name:    "songs",
+		Headers: []string{"title", "start_time"},
+		Rows: []csvplan.CollectionRow{
+			{Index: 1, StartRaw: "1:30", Start: 90 * time.Second, CustomFields: map[string]string{"title": "A", "start_time": "1:30"}},
+			{Index: 2, StartRaw: "0:02", Start: 2 * time.Second, CustomFields: map[string]string{"title": "B", "start_time": "0:02"}},
+		},
+		Overrides: map[string]project.RowOverride{"2": {StartTime: "0:10"}},
+	}
+
+	got, changes, err := nudgeCollectionRows(coll, "start_time", []int{1, 1}, -3*time.Second)
+	if err != nil {
+		t.Fatalf("nudge: %v", err)
+	}
+	if len(changes) != 1 || changes[0].From != "1:30" || changes[0].To != "1:27" {
+		t.Fatalf("changes = %+v, want one 1:30 → 1:27", changes)
+	}
+	row := got.Rows[0]
+	if row.StartRaw != "1:27" || row.Start != 87*time.Second || row.CustomFields["start_time"] != "1:27" {
+		t.Fatalf("row = %+v, want start 1:27", row)
+	}
+	if coll.Rows[0].CustomFields["start_time"] != "1:30" {
+		t.Error("original collection rows should not be mutated")
+	}
+
+	if _, _, err := nudgeCollectionRows(coll, "start_time", []int{2}, time.Second); err == nil || !strings.Contains(err.Error(), "overrides") {
+		t.Errorf("overridden row: err = %v, want overrides error", err)
+	}
+	coll.Overrides = nil
+	if _, _, err := nudgeCollectionRows(coll, "start_time", []int{2}, -3*time.Second); err == nil {
+		t.Error("expected error for a start before 0:00")
+	}
+	if _, _, err := nudgeCollectionRows(coll, "start_time", []int{9}, time.Second); err == nil {
+		t.Error("expected error for out-of-range index")
+	}
+}
+
+func TestSetRowStartKeepsWindow(t *testing.T) {
+	tests := []struct {
+		fields    map[string]string
+		wantStart string
+		wantEnd   string
+	}{
+		{fields: map[string]string{"start_time": "1:00-1:30"}, wantStart: "1:05-1:35"},
+		{fields: map[string]string{"start_time": "1:00", "end_time": "1:30"}, wantStart: "1:05", wantEnd: "1:35"},
+		{fields: map[string]string{"start_time": "1:00"}, wantStart: "1:05"},
+	}
+	for _, tc := range tests {
+		row := csvplan.CollectionRow{Index: 1, StartRaw: "1:00", Start: time.Minute, DurationSeconds: 30, CustomFields: tc.fields}
+		got := setRowStart(row, "start_time", 65*time.Second)
+		if got.StartRaw != "1:05" || got.CustomFields["start_time"] != tc.wantStart || got.CustomFields["end_time"] != tc.wantEnd {
+			t.Errorf("setRowStart(%v) = %q / %v, want %q end %q", tc.fields, got.StartRaw, got.CustomFields, tc.wantStart, tc.wantEnd)
+		}
+	}
+}
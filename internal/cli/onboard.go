@@ -0,0 +1,300 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	xterm "github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/config"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/tools"
+	"powerhour/pkg/csvplan"
+)
+
+// shouldOnboard reports whether a bare `powerhour` invocation should start
+// the first-run flow: an interactive terminal with no project in reach.
+func shouldOnboard() bool {
+	if outputJSON {
+		return false
+	}
+	if !xterm.IsTerminal(os.Stdin.Fd()) || !xterm.IsTerminal(os.Stdout.Fd()) {
+		return false
+	}
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return false
+	}
+	exists, err := paths.FileExists(pp.ConfigFile)
+	return err == nil && !exists
+}
+
+// runOnboarding walks a new user through tool setup, encoding defaults,
+// project creation, and an optional first import in a single session.
+func runOnboarding(cmd *cobra.Command) error {
+	glog, gcloser := logx.Command("onboard")
+	glogf := logx.Printf(glog)
+	defer gcloser.Close()
+	glogf("onboarding started")
+
+	out := cmd.OutOrStdout()
+	bold := lipgloss.NewStyle().Bold(true).Inline(true)
+	faint := lipgloss.NewStyle().Faint(true).Inline(true)
+	ask := newPrompter(cmd.InOrStdin(), out)
+
+	fmt.Fprintln(out, bold.Render("Welcome to powerhour!"))
+	fmt.Fprintln(out, faint.Render("No project found here. This short setup gets you ready to render."))
+	fmt.Fprintln(out)
+	if !ask.confirm("Set up a new project now?", true) {
+		return cmd.Help()
+	}
+
+	// Step 1: tools.
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, bold.Render("1/4 Tools"))
+	if err := onboardTools(cmd.Context(), out, ask); err != nil {
+		glog.Warn("onboarding tools failed", "error", err)
+		fmt.Fprintf(out, "  tool setup failed: %v\n", err)
+		fmt.Fprintln(out, faint.Render("  Run `powerhour tools install` later to retry."))
+	}
+
+	// Step 2: encoding defaults.
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, bold.Render("2/4 Encoding"))
+	if tools.LoadEncodingDefaults().VideoCodec != "" {
+		fmt.Fprintln(out, "  Global encoding defaults already configured.")
+	} else if _, err := tools.Lookup("ffmpeg"); err != nil {
+		fmt.Fprintln(out, "  ffmpeg unavailable; skipping. Run `powerhour tools encoding` once it is installed.")
+	} else if ask.confirm("  Pick encoding defaults for this machine?", true) {
+		if err := runToolsEncoding(cmd, nil); err != nil {
+			glog.Warn("onboarding encoding failed", "error", err)
+			fmt.Fprintf(out, "  encoding setup failed: %v\n", err)
+		}
+	}
+
+	// Step 3: project directory.
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, bold.Render("3/4 Project"))
+	dir, err := onboardProjectDir(ask)
+	if err != nil {
+		return err
+	}
+	pp, err := initProject(cmd, dir, "yaml", builtinInitTemplates[0])
+	if err != nil {
+		return err
+	}
+	glogf("onboarding created project: %s", pp.Root)
+
+	// Step 4: first import.
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, bold.Render("4/4 Songs"))
+	source := ask.line("  Playlist URL or CSV/TSV file to import (blank to skip)", "")
+	if source != "" {
+		added, err := onboardImport(cmd.Context(), pp, source)
+		if err != nil {
+			glog.Warn("onboarding import failed", "error", err)
+			fmt.Fprintf(out, "  import failed: %v\n", err)
+			fmt.Fprintln(out, faint.Render("  Use `powerhour add --collection songs --file <path>` to try again."))
+		} else {
+			fmt.Fprintf(out, "  Added %d songs.\n", added)
+		}
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, bold.Render("All set. Next steps:"))
+	rel := pp.Root
+	if cwd, err := os.Getwd(); err == nil {
+		if r, err := filepath.Rel(cwd, pp.Root); err == nil && !strings.HasPrefix(r, "..") {
+			rel = r
+		}
+	}
+	fmt.Fprintf(out, "  cd %s\n", rel)
+	fmt.Fprintln(out, "  powerhour fetch     # download sources")
+	fmt.Fprintln(out, "  powerhour render    # render clips")
+	fmt.Fprintln(out, "  powerhour tui       # or manage everything interactively")
+	return nil
+}
+
+func onboardTools(ctx context.Context, out io.Writer, ask *prompter) error {
+	statuses, err := tools.Detect(ctx)
+	if err != nil {
+		return err
+	}
+	var missing []string
+	for _, st := range statuses {
+		if st.Satisfied {
+			fmt.Fprintf(out, "  %s %s found\n", st.Tool, st.Version)
+			continue
+		}
+		if st.Optional {
+			continue
+		}
+		missing = append(missing, st.Tool)
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	if !ask.confirm(fmt.Sprintf("  Install %s into the powerhour tool cache?", strings.Join(missing, ", ")), true) {
+		return nil
+	}
+	_, err = tools.EnsureAll(ctx, missing, func(msg string) {
+		fmt.Fprintf(out, "  %s\n", msg)
+	})
+	return err
+}
+
+func onboardProjectDir(ask *prompter) (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("get working directory: %w", err)
+	}
+	def, err := nextAvailableDir(cwd)
+	if err != nil {
+		return "", err
+	}
+	if rel, err := filepath.Rel(cwd, def); err == nil {
+		def = rel
+	}
+	dir := ask.line("  Project directory", def)
+	if dir == "." {
+		return cwd, nil
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(cwd, dir)
+	}
+	return dir, nil
+}
+
+// onboardImport appends rows from a playlist URL or plan file to the songs
+// collection and returns the number of rows added.
+func onboardImport(ctx context.Context, pp paths.ProjectPaths, source string) (int, error) {
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return 0, err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+	resolver, err := project.NewCollectionResolver(cfg, pp)
+	if err != nil {
+		return 0, err
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		return 0, err
+	}
+	coll, ok := collections["songs"]
+	if !ok {
+		return 0, fmt.Errorf("collection %q not found", "songs")
+	}
+
+	var rows []csvplan.CollectionRow
+	if isRemoteLink(source) {
+		links, err := expandPlaylist(ctx, source)
+		if err != nil {
+			return 0, err
+		}
+		for _, link := range links {
+			rows = append(rows, project.BuildCollectionRow(coll, cleanYouTubeURL(link)))
+		}
+	} else {
+		raw, err := readAddInput(nil, nil, source)
+		if err != nil {
+			return 0, err
+		}
+		rows, _, err = buildRowsForAdd(raw, coll)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	coll = project.AppendCollectionRows(coll, rows)
+	if err := project.WriteCollectionPlan(coll); err != nil {
+		return 0, err
+	}
+	return len(rows), nil
+}
+
+// expandPlaylist lists the entry URLs of a playlist without downloading it.
+// A single-video URL yields itself.
+func expandPlaylist(ctx context.Context, url string) ([]string, error) {
+	ytdlp, err := tools.Lookup("yt-dlp")
+	if err != nil {
+		return nil, fmt.Errorf("yt-dlp unavailable: %w", err)
+	}
+	out, err := exec.CommandContext(ctx, ytdlp, "--flat-playlist", "--print", "url", url).Output()
+	if err != nil {
+		return nil, fmt.Errorf("list playlist: %w", err)
+	}
+	return parsePlaylistURLs(string(out)), nil
+}
+
+func parsePlaylistURLs(output string) []string {
+	var links []string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if isRemoteLink(line) {
+			links = append(links, line)
+		}
+	}
+	return links
+}
+
+// prompter reads line-oriented answers for interactive flows.
+type prompter struct {
+	in  *bufio.Scanner
+	out io.Writer
+}
+
+func newPrompter(in io.Reader, out io.Writer) *prompter {
+	return &prompter{in: bufio.NewScanner(in), out: out}
+}
+
+// line prints question with its default and returns the trimmed answer, or
+// def when the answer is blank or input is exhausted.
+func (p *prompter) line(question, def string) string {
+	if def != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", question)
+	}
+	if !p.in.Scan() {
+		fmt.Fprintln(p.out)
+		return def
+	}
+	if answer := strings.TrimSpace(p.in.Text()); answer != "" {
+		return answer
+	}
+	return def
+}
+
+func (p *prompter) confirm(question string, def bool) bool {
+	hint := "[y/N]"
+	if def {
+		hint = "[Y/n]"
+	}
+	fmt.Fprintf(p.out, "%s %s ", question, hint)
+	if !p.in.Scan() {
+		fmt.Fprintln(p.out)
+		return def
+	}
+	switch strings.ToLower(strings.TrimSpace(p.in.Text())) {
+	case "":
+		return def
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
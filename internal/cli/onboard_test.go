@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestPrompter(t *testing.T) {
+	p := newPrompter(strings.NewReader("\nno\n  my-dir  \n"), io.Discard)
+
+	if !p.confirm("continue?", true) {
+		t.Error("blank answer should take the default")
+	}
+	if p.confirm("continue?", true) {
+		t.Error("\"no\" should decline")
+	}
+	if got := p.line("dir", "powerhour-1"); got != "my-dir" {
+		t.Errorf("line = %q, want my-dir", got)
+	}
+	// Input exhausted: defaults apply.
+	if got := p.line("dir", "powerhour-1"); got != "powerhour-1" {
+		t.Errorf("line at EOF = %q, want default", got)
+	}
+	if p.confirm("continue?", false) {
+		t.Error("confirm at EOF should take the default")
+	}
+}
+
+func TestParsePlaylistURLs(t *testing.T) {
+	output := "https://www.youtube.com/watch?v=a\n\nNA\n  https://www.youtube.com/watch?v=b  \n"
+	want := []string{"https://www.youtube.com/watch?v=a", "https://www.youtube.com/watch?v=b"}
+	if got := parsePlaylistURLs(output); !reflect.DeepEqual(got, want) {
+		t.Fatalf("parsePlaylistURLs = %v, want %v", got, want)
+	}
+}
+
+func TestOnboardImportCSV(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := filepath.Join(t.TempDir(), "party")
+	cmd := &cobra.Command{}
+	cmd.SetOut(io.Discard)
+	pp, err := initProject(cmd, dir, "yaml", builtinInitTemplates[0])
+	if err != nil {
+		t.Fatalf("initProject: %v", err)
+	}
+
+	csvPath := filepath.Join(t.TempDir(), "songs.csv")
+	csv := "title,artist,start_time,duration,link\nSong A,Artist A,0:30,60,https://youtu.be/a\nSong B,Artist B,1:00,60,https://youtu.be/b\n"
+	if err := os.WriteFile(csvPath, []byte(csv), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	added, err := onboardImport(context.Background(), pp, csvPath)
+	if err != nil {
+		t.Fatalf("onboardImport: %v", err)
+	}
+	if added != 2 {
+		t.Fatalf("added = %d, want 2", added)
+	}
+	plan, err := os.ReadFile(filepath.Join(dir, "songs.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(plan), "Song B") {
+		t.Fatalf("songs plan missing imported rows:\n%s", plan)
+	}
+}
@@ -0,0 +1,286 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/config"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+	"powerhour/internal/webui"
+)
+
+var (
+	partyAddr     string
+	partyToken    string
+	partySegments bool
+	partyTimeline string
+	partyVariant  string
+)
+
+func newPartyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "party",
+		Short: "Play the hour in a browser with a synced countdown page for phones",
+		Long: `Serve the hour over HTTP for party night. Open the player page on the
+screen everyone watches: it plays the concat output (or the segments one
+after another) with the current song, who picked it, and a big countdown
+to the next drink on top. Open /companion.html on phones or a second
+screen for the same song and countdown, synced to the player's position.
+
+The concat output is played when it exists; --segments plays the rendered
+segments instead, which works before concat has run.
+
+Keys on the player page: space pauses, n/→ and p/← skip between clips,
+f toggles full screen.
+
+The server listens on every interface so phones on the same network can
+reach it. Every request needs an access token, which the printed URLs
+carry: --token sets it, otherwise a random one is made for each run.`,
+		Example: `  powerhour party
+  powerhour party --segments --addr 127.0.0.1:8788
+  powerhour party --token cheers`,
+		Args: cobra.NoArgs,
+		RunE: runParty,
+	}
+	cmd.Flags().StringVar(&partyAddr, "addr", "0.0.0.0:8788", "Address to listen on")
+	cmd.Flags().StringVar(&partyToken, "token", "", "Require this access token")
+	cmd.Flags().BoolVar(&partySegments, "segments", false, "Play the rendered segments instead of the concat output")
+	cmd.Flags().StringVar(&partyTimeline, "timeline", "", "Use a named timeline from timelines:")
+	cmd.Flags().StringVar(&partyVariant, "variant", "", "Follow the timeline as assembled with concat --variant")
+	return cmd
+}
+
+func runParty(cmd *cobra.Command, _ []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	glogf, gcloser := logx.StartCommand("party")
+	defer gcloser.Close()
+
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
+	cfg, pp, err = applyNamedTimeline(cfg, pp, partyTimeline)
+	if err != nil {
+		return err
+	}
+	if partyVariant != "" {
+		cfg.Timeline, err = cfg.Timeline.WithVariant(partyVariant)
+		if err != nil {
+			return err
+		}
+	}
+	if len(cfg.Collections) == 0 {
+		return fmt.Errorf("no collections configured")
+	}
+
+	resolver, err := project.NewCollectionResolver(cfg, pp)
+	if err != nil {
+		return err
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		return err
+	}
+	segments, err := render.ResolveTimelineSegments(pp, cfg, collections)
+	if err != nil {
+		return fmt.Errorf("resolve timeline: %w", err)
+	}
+	clips, err := resolver.BuildCollectionClips(collections)
+	if err != nil {
+		return err
+	}
+	applySequenceEntryOverrides(cfg, clips)
+
+	final := ""
+	if !partySegments {
+		final = findConcatOutput(pp.Root, concatOutputBase(partyTimeline, partyVariant))
+		if final == "" {
+			fmt.Fprintln(cmd.ErrOrStderr(), "No concat output yet; playing the rendered segments")
+		}
+	}
+	media, tracks, err := buildPartyTracks(ctx, findFFprobe(), pp, segments, clips, final)
+	if err != nil {
+		return err
+	}
+	if len(media) == 0 {
+		return fmt.Errorf("no rendered segments in the timeline; run `powerhour render` first")
+	}
+
+	token := partyToken
+	if token == "" {
+		if token, err = newServeToken(); err != nil {
+			return err
+		}
+	}
+	party := webui.NewParty(webui.PartyOptions{
+		Token:  token,
+		Hosts:  addrHosts(partyAddr),
+		Title:  filepath.Base(pp.Root),
+		Media:  media,
+		Tracks: tracks,
+		Logf:   glogf,
+	})
+	defer party.Close()
+
+	ln, err := net.Listen("tcp", partyAddr)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", partyAddr, err)
+	}
+	httpServer := &http.Server{Handler: party.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	glogf("party started: addr=%s media=%d tracks=%d final=%t", ln.Addr(), len(media), len(tracks), final != "")
+
+	base := partyURL(ln.Addr())
+	query := "?token=" + token
+	if final != "" {
+		cmd.Printf("Playing %s\n", relPath(pp.Root, final))
+	} else {
+		cmd.Printf("Playing %d segments\n", len(media))
+	}
+	cmd.Printf("Player:    %s%s\n", base, query)
+	cmd.Printf("Companion: %scompanion.html%s\n", base, query)
+	cmd.Println("Press Ctrl+C to stop")
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- httpServer.Serve(ln) }()
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("serve: %w", err)
+		}
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	glogf("party stopping")
+	return httpServer.Shutdown(shutdownCtx)
+}
+
+// partyURL returns the base URL guests can open. Listening on every
+// interface, that's this machine's LAN address rather than 0.0.0.0.
+func partyURL(addr net.Addr) string {
+	host, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return "http://" + addr.String() + "/"
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsUnspecified() {
+		host = "localhost"
+		if addrs, err := net.InterfaceAddrs(); err == nil {
+			for _, a := range addrs {
+				if ipnet, ok := a.(*net.IPNet); ok && ipnet.IP.To4() != nil && !ipnet.IP.IsLoopback() && !ipnet.IP.IsLinkLocalUnicast() {
+					host = ipnet.IP.String()
+					break
+				}
+			}
+		}
+	}
+	return "http://" + net.JoinHostPort(host, port) + "/"
+}
+
+// buildPartyTracks lists the media the party page plays and every clip's
+// place in it. With final set, the one file holds the whole timeline and
+// clips are placed by the running length of the segments before them;
+// otherwise each rendered segment is its own file and unrendered ones are
+// left out. The countdown runs to the end of the clip, before its postroll.
+func buildPartyTracks(ctx context.Context, ffprobe string, pp paths.ProjectPaths, segments []render.TimelineSegmentPath, clips []project.CollectionClip, final string) ([]string, []webui.PartyTrack, error) {
+	byKey := make(map[string]project.Clip, len(clips))
+	for _, cc := range clips {
+		byKey[subtitleClipKey(cc.CollectionName, cc.Clip.Row.Index, cc.Clip.Window)] = cc.Clip
+	}
+
+	var (
+		media  []string
+		tracks []webui.PartyTrack
+		offset float64
+	)
+	if final != "" {
+		media = []string{final}
+	}
+	for i, seg := range segments {
+		_, statErr := os.Stat(seg.Path)
+		if final == "" && statErr != nil {
+			continue
+		}
+		clip, ok := byKey[subtitleClipKey(seg.CollectionName, seg.Index, seg.Window)]
+
+		length := 0.0
+		if ffprobe != "" && statErr == nil {
+			if info, err := probeMedia(ctx, ffprobe, seg.Path); err == nil {
+				length = info.DurationSeconds
+			}
+		}
+		if length <= 0 && ok {
+			length = clip.OutputSeconds()
+		}
+		if length <= 0 {
+			return nil, nil, fmt.Errorf("segment %d (%s): unknown length; render it first or install ffprobe", i+1, relPath(pp.Root, seg.Path))
+		}
+
+		track := webui.PartyTrack{Start: offset, Seconds: length}
+		if final == "" {
+			track.Media, track.Start = len(media), 0
+			media = append(media, seg.Path)
+		}
+		if ok {
+			track.Title = clipDisplayTitle(clip)
+			track.Artist = strings.TrimSpace(clip.Row.Artist)
+			track.Submitter = partySubmitter(clip, track.Title)
+			track.Trivia = partyField(clip, project.TriviaField)
+			track.Dare = partyField(clip, project.DareField)
+			track.Start += clip.PrerollSeconds
+			track.Seconds = max(length-clip.PrerollSeconds-clip.PostrollSeconds, 0)
+			track.Drink = true
+		} else {
+			track.Title = strings.TrimSuffix(filepath.Base(seg.Path), filepath.Ext(seg.Path))
+		}
+		tracks = append(tracks, track)
+		offset += length
+	}
+	return media, tracks, nil
+}
+
+// partySubmitter is who put the clip in the plan: the submitted_by column
+// the opening slate credits (which a facts file can fill), or the name
+// column unless it's the title.
+func partySubmitter(clip project.Clip, title string) string {
+	if v := partyField(clip, project.SubmitterField); v != "" {
+		return v
+	}
+	if name := strings.TrimSpace(clip.Row.Name); name != "" && name != title {
+		return name
+	}
+	return ""
+}
+
+// partyField returns a plan column of the clip, matching its name without
+// regard to case.
+func partyField(clip project.Clip, field string) string {
+	for key, value := range clip.Row.CustomFields {
+		if strings.EqualFold(key, field) {
+			if v := strings.TrimSpace(value); v != "" {
+				return v
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+	"powerhour/internal/webui"
+	"powerhour/pkg/csvplan"
+)
+
+func TestBuildPartyTracks(t *testing.T) {
+	dir := t.TempDir()
+	rendered := filepath.Join(dir, "002.mp4")
+	if err := os.WriteFile(rendered, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	segments := []render.TimelineSegmentPath{
+		{CollectionName: "songs", Index: 1, Path: filepath.Join(dir, "001.mp4")},
+		{CollectionName: "songs", Index: 2, Path: rendered},
+	}
+	clips := []project.CollectionClip{
+		{CollectionName: "songs", Clip: project.Clip{Row: csvplan.Row{Index: 1, Title: "Song", Artist: "Band", CustomFields: map[string]string{"Submitted_By": "Sam", "trivia": "Recorded in one take", "dare": "Sing the chorus"}}, DurationSeconds: 60, PrerollSeconds: 2}},
+		{CollectionName: "songs", Clip: project.Clip{Row: csvplan.Row{Index: 2, Title: "Other", Name: "Alex"}, DurationSeconds: 30, PostrollSeconds: 1}},
+	}
+
+	media, tracks, err := buildPartyTracks(context.Background(), "", paths.ProjectPaths{}, segments, clips, "/out/powerhour.mp4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantFinal := []webui.PartyTrack{
+		{Title: "Song", Artist: "Band", Submitter: "Sam", Trivia: "Recorded in one take", Dare: "Sing the chorus", Media: 0, Start: 2, Seconds: 60, Drink: true},
+		{Title: "Other", Submitter: "Alex", Media: 0, Start: 62, Seconds: 30, Drink: true},
+	}
+	if len(media) != 1 || len(tracks) != len(wantFinal) {
+		t.Fatalf("final: media = %v, tracks = %+v", media, tracks)
+	}
+	for i := range tracks {
+		if tracks[i] != wantFinal[i] {
+			t.Errorf("final track %d = %+v, want %+v", i, tracks[i], wantFinal[i])
+		}
+	}
+
+	// Segments: only the rendered one plays, from its own start.
+	media, tracks, err = buildPartyTracks(context.Background(), "", paths.ProjectPaths{}, segments, clips, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(media) != 1 || media[0] != rendered || len(tracks) != 1 {
+		t.Fatalf("segments: media = %v, tracks = %+v", media, tracks)
+	}
+	if want := (webui.PartyTrack{Title: "Other", Submitter: "Alex", Media: 0, Start: 0, Seconds: 30, Drink: true}); tracks[0] != want {
+		t.Errorf("segment track = %+v, want %+v", tracks[0], want)
+	}
+}
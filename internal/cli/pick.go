@@ -0,0 +1,264 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/config"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+)
+
+var (
+	pickCollection string
+	pickIndex      int
+	pickPlayerName string
+)
+
+func newPickCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pick",
+		Short: "Pick a row's start time by ear in mpv or ffplay",
+		Long: `Open a row's cached source in mpv or ffplay at its current start time,
+seek to where the clip should begin, and capture that position. When the
+player quits, the last captured position is written to the plan as the new
+start time and the row's segment is marked stale.
+
+In mpv, press c to capture; the position is shown on screen. ffplay has no
+custom keys, so press Enter in the terminal instead. Quitting without a
+capture leaves the plan unchanged. Captures are rounded to a tenth of a
+second.
+
+The row's source must be cached; run fetch first.`,
+		Example: `  powerhour pick --index 12
+  powerhour pick --collection interstitials --index 4 --player ffplay`,
+		Args: cobra.NoArgs,
+		RunE: runPick,
+	}
+	cmd.Flags().StringVar(&pickCollection, "collection", "", "Collection to change (default: the only collection, or songs)")
+	cmd.Flags().IntVar(&pickIndex, "index", 0, "1-based row index (required)")
+	cmd.Flags().StringVar(&pickPlayerName, "player", "auto", "Player to use: auto, mpv, ffplay")
+	_ = cmd.RegisterFlagCompletionFunc("player", cobra.FixedCompletions([]string{"auto", "mpv", "ffplay"}, cobra.ShellCompDirectiveNoFileComp))
+	_ = cmd.MarkFlagRequired("index")
+	return cmd
+}
+
+func runPick(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if pickIndex < 1 {
+		return fmt.Errorf("--index must be 1 or greater")
+	}
+
+	glogf, gcloser := logx.StartCommand("pick")
+	defer gcloser.Close()
+	glogf("pick started: collection=%s index=%d player=%s", pickCollection, pickIndex, pickPlayerName)
+
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
+
+	resolver, err := project.NewCollectionResolver(cfg, pp)
+	if err != nil {
+		return err
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		return err
+	}
+	name, err := pickPlanCollection(collections, pickCollection)
+	if err != nil {
+		return err
+	}
+	coll := collections[name]
+	if coll.Plan == "" {
+		return fmt.Errorf("collection %q has no plan file", name)
+	}
+	var from time.Duration
+	found := false
+	for _, row := range coll.Rows {
+		if row.Index != pickIndex {
+			continue
+		}
+		if ov, ok := coll.RowOverrideFor(row); ok && ov.StartTime != "" {
+			return fmt.Errorf("row %d start time is set by the overrides file (%s); edit it there", pickIndex, ov.StartTime)
+		}
+		if err := checkRowWindows(row); err != nil {
+			return err
+		}
+		from, found = row.Start, true
+	}
+	if !found {
+		return fmt.Errorf("collection %q has no row %d (1-%d)", name, pickIndex, len(coll.Rows))
+	}
+
+	idx, err := cache.Load(pp)
+	if err != nil {
+		return err
+	}
+	segments, err := nudgeSegments(pp, cfg, idx, resolver, collections, name)
+	if err != nil {
+		return err
+	}
+	seg, ok := segments[pickIndex]
+	if !ok {
+		return fmt.Errorf("row %d is skipped; unskip it first", pickIndex)
+	}
+	if seg.SourcePath == "" {
+		return fmt.Errorf("row %d source not cached; run `powerhour fetch --index %d` first", pickIndex, pickIndex)
+	}
+
+	player, err := findPickPlayer(pickPlayerName)
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("powerhour pick: %s row %d", name, pickIndex)
+	fmt.Fprintf(cmd.ErrOrStderr(), "Playing %s row %d from %s in %s.\n%s\n", name, pickIndex, formatStartTime(from), player.Name, player.Hint())
+	picked, ok, err := runPickPlayer(ctx, cmd, player, seg.SourcePath, from.Seconds(), title)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		glogf("pick: no capture")
+		cmd.Println("No start time captured; plan unchanged")
+		return nil
+	}
+
+	start := time.Duration(math.Round(picked*10)) * 100 * time.Millisecond
+	glogf("pick: captured %.3fs -> %s", picked, formatStartTime(start))
+	if start == from {
+		cmd.Printf("Row %d start unchanged at %s\n", pickIndex, formatStartTime(from))
+		return nil
+	}
+
+	startHeader := project.CollectionOptionsForConfig(coll).StartHeader
+	if startHeader == "" {
+		startHeader = "start_time"
+	}
+	coll, changes, err := nudgeCollectionRows(coll, startHeader, []int{pickIndex}, start-from)
+	if err != nil {
+		return err
+	}
+	if err := project.WriteCollectionPlan(coll); err != nil {
+		return err
+	}
+	change := changes[0]
+	if seg.OutputPath != "" {
+		stale, err := markSegmentsStale(pp.RenderStateFile, []string{seg.OutputPath})
+		if err != nil {
+			return err
+		}
+		change.Segment, change.Stale = seg.OutputPath, stale[seg.OutputPath]
+	}
+	glogf("pick: wrote %s, stale=%t", coll.Plan, change.Stale)
+
+	if outputJSON {
+		data, err := json.MarshalIndent(struct {
+			Collection string `json:"collection"`
+			Player     string `json:"player"`
+			nudgeChange
+		}{name, player.Name, change}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+	cmd.Printf("Row %d start %s → %s", change.Index, change.From, change.To)
+	if change.Stale {
+		cmd.Printf(" (%s marked stale)", relPath(pp.Root, change.Segment))
+	}
+	cmd.Println()
+	cmd.Printf("Saved %s\n", coll.Plan)
+	return nil
+}
+
+// runPickPlayer plays source from start and returns the last position the
+// user captured before quitting the player.
+func runPickPlayer(ctx context.Context, cmd *cobra.Command, player pickPlayer, source string, start float64, title string) (float64, bool, error) {
+	inputConf := ""
+	if player.Name == "mpv" {
+		f, err := os.CreateTemp("", "powerhour-pick-*.conf")
+		if err != nil {
+			return 0, false, fmt.Errorf("write mpv key binding: %w", err)
+		}
+		defer os.Remove(f.Name())
+		if _, err := f.WriteString(pickMPVInputConf); err != nil {
+			f.Close()
+			return 0, false, fmt.Errorf("write mpv key binding: %w", err)
+		}
+		if err := f.Close(); err != nil {
+			return 0, false, fmt.Errorf("write mpv key binding: %w", err)
+		}
+		inputConf = f.Name()
+	}
+
+	proc := exec.CommandContext(ctx, player.Path, player.Args(source, start, title, inputConf)...)
+	stdout, err := proc.StdoutPipe()
+	if err != nil {
+		return 0, false, err
+	}
+	stderr, err := proc.StderrPipe()
+	if err != nil {
+		return 0, false, err
+	}
+	if err := proc.Start(); err != nil {
+		return 0, false, fmt.Errorf("start %s: %w", player.Name, err)
+	}
+
+	session := &pickSession{}
+	var wg sync.WaitGroup
+	for _, r := range []io.Reader{stdout, stderr} {
+		wg.Add(1)
+		go func(r io.Reader) {
+			defer wg.Done()
+			scanner := bufio.NewScanner(r)
+			scanner.Split(scanPlayerLines)
+			for scanner.Scan() {
+				session.Observe(scanner.Text())
+			}
+		}(r)
+	}
+	if player.Name == "ffplay" {
+		// The reader is left blocked on stdin once the player exits; the
+		// command returns right after.
+		go func() {
+			scanner := bufio.NewScanner(cmd.InOrStdin())
+			for scanner.Scan() {
+				if pos, ok := session.Capture(); ok {
+					fmt.Fprintf(cmd.ErrOrStderr(), "Captured %s\n", formatStartTime(time.Duration(pos*float64(time.Second))))
+				} else {
+					fmt.Fprintln(cmd.ErrOrStderr(), "No playback position yet")
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	if err := proc.Wait(); err != nil {
+		return 0, false, fmt.Errorf("%s: %w", player.Name, err)
+	}
+	pos, ok := session.Picked()
+	return pos, ok, nil
+}
@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"powerhour/internal/tools"
+)
+
+// pickPlayers lists the supported players in the order auto picks them.
+var pickPlayers = []string{"mpv", "ffplay"}
+
+// pickMarker prefixes the line the mpv key binding prints when a start is
+// captured.
+const pickMarker = "powerhour-pick"
+
+// ffplay's status line starts with the master clock, e.g.
+// "  87.42 A-V:  0.002 fd=   0 aq=   24KB vq=  118KB sq=    0B".
+var ffplayClockPattern = regexp.MustCompile(`^\s*(\d+(?:\.\d+)?)\s+(?:A-V|M-A|M-V|V-A):`)
+
+// pickPlayer is a media player the pick command can drive.
+type pickPlayer struct {
+	Name string
+	Path string
+}
+
+// findPickPlayer locates the requested player, or the first installed one
+// for "auto". ffplay is also looked for next to the managed ffmpeg.
+func findPickPlayer(name string) (pickPlayer, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	candidates := pickPlayers
+	if name != "" && name != "auto" {
+		if !slices.Contains(pickPlayers, name) {
+			return pickPlayer{}, fmt.Errorf("unsupported player %q (choose from auto, %s)", name, strings.Join(pickPlayers, ", "))
+		}
+		candidates = []string{name}
+	}
+	for _, candidate := range candidates {
+		if path, err := exec.LookPath(candidate); err == nil {
+			return pickPlayer{Name: candidate, Path: path}, nil
+		}
+		if candidate != "ffplay" {
+			continue
+		}
+		if ffmpeg, err := tools.Lookup("ffmpeg"); err == nil {
+			bin := "ffplay"
+			if runtime.GOOS == "windows" {
+				bin += ".exe"
+			}
+			path := filepath.Join(filepath.Dir(ffmpeg), bin)
+			if _, err := os.Stat(path); err == nil {
+				return pickPlayer{Name: candidate, Path: path}, nil
+			}
+		}
+	}
+	return pickPlayer{}, fmt.Errorf("no player found; install mpv or ffplay (part of ffmpeg)")
+}
+
+// pickMPVInputConf is the key binding added to mpv's defaults: c prints the
+// playback position for the picker and confirms it on screen.
+const pickMPVInputConf = `c print-text "` + pickMarker + ` ${=time-pos}"; show-text "Start picked: ${time-pos}"` + "\n"
+
+// Args returns the command line that plays source from start seconds.
+// inputConf is the mpv key binding file; ffplay ignores it.
+func (p pickPlayer) Args(source string, start float64, title, inputConf string) []string {
+	ss := strconv.FormatFloat(start, 'f', -1, 64)
+	if p.Name == "ffplay" {
+		return []string{
+			"-hide_banner",
+			"-ss", ss,
+			"-window_title", title,
+			source,
+		}
+	}
+	return []string{
+		"--start=" + ss,
+		"--title=" + title,
+		"--input-conf=" + inputConf,
+		"--no-input-terminal",
+		"--keep-open=yes",
+		"--osd-level=3",
+		source,
+	}
+}
+
+// PlayArgs returns the command line that plays path once and exits.
+func (p pickPlayer) PlayArgs(path, title string) []string {
+	if p.Name == "ffplay" {
+		return []string{"-hide_banner", "-loglevel", "error", "-autoexit", "-window_title", title, path}
+	}
+	return []string{"--title=" + title, "--really-quiet", path}
+}
+
+// Hint tells the user how to capture a start with this player.
+func (p pickPlayer) Hint() string {
+	if p.Name == "ffplay" {
+		return "Press Enter here to capture the playback position; quit ffplay (q) to save the last capture."
+	}
+	return "Press c in the player to capture the playback position; quit mpv (q) to save the last capture."
+}
+
+// pickSession collects what the player reports: its latest playback
+// position and the positions the user captured.
+type pickSession struct {
+	mu      sync.Mutex
+	pos     float64
+	havePos bool
+	picks   []float64
+}
+
+// Observe parses one line of player output.
+func (s *pickSession) Observe(line string) {
+	if i := strings.Index(line, pickMarker+" "); i >= 0 {
+		fields := strings.Fields(line[i+len(pickMarker):])
+		if len(fields) > 0 {
+			if v, err := strconv.ParseFloat(fields[0], 64); err == nil && v >= 0 {
+				s.mu.Lock()
+				s.picks = append(s.picks, v)
+				s.mu.Unlock()
+			}
+		}
+		return
+	}
+	if m := ffplayClockPattern.FindStringSubmatch(line); m != nil {
+		if v, err := strconv.ParseFloat(m[1], 64); err == nil {
+			s.mu.Lock()
+			s.pos, s.havePos = v, true
+			s.mu.Unlock()
+		}
+	}
+}
+
+// Capture records the latest reported position, for players that can't
+// bind a key of their own.
+func (s *pickSession) Capture() (float64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.havePos {
+		return 0, false
+	}
+	s.picks = append(s.picks, s.pos)
+	return s.pos, true
+}
+
+// Picked returns the last captured position.
+func (s *pickSession) Picked() (float64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.picks) == 0 {
+		return 0, false
+	}
+	return s.picks[len(s.picks)-1], true
+}
+
+// scanPlayerLines splits player output on newlines and on the carriage
+// returns status lines are redrawn with.
+func scanPlayerLines(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"bufio"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPickSessionObserve(t *testing.T) {
+	s := &pickSession{}
+	if _, ok := s.Capture(); ok {
+		t.Fatal("capture before any position should fail")
+	}
+
+	s.Observe("  87.42 A-V:  0.002 fd=   0 aq=   24KB vq=  118KB sq=    0B f=0/0")
+	s.Observe("nan M-A: nan fd=   0")
+	if pos, ok := s.Capture(); !ok || pos != 87.42 {
+		t.Fatalf("Capture() = %v, %v; want 87.42", pos, ok)
+	}
+
+	s.Observe("powerhour-pick 91.125000")
+	s.Observe("[cplayer] powerhour-pick ")
+	if pos, ok := s.Picked(); !ok || pos != 91.125 {
+		t.Fatalf("Picked() = %v, %v; want the last mpv capture 91.125", pos, ok)
+	}
+}
+
+func TestScanPlayerLines(t *testing.T) {
+	scanner := bufio.NewScanner(strings.NewReader("Input #0\n   1.00 A-V: 0\r   1.50 A-V: 0\rtail"))
+	scanner.Split(scanPlayerLines)
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	want := []string{"Input #0", "   1.00 A-V: 0", "   1.50 A-V: 0", "tail"}
+	if !slices.Equal(lines, want) {
+		t.Fatalf("lines = %q, want %q", lines, want)
+	}
+}
+
+func TestPickPlayerArgs(t *testing.T) {
+	mpv := pickPlayer{Name: "mpv"}.Args("song.mp4", 90.5, "pick", "/tmp/in.conf")
+	for _, want := range []string{"--start=90.5", "--input-conf=/tmp/in.conf", "song.mp4"} {
+		if !slices.Contains(mpv, want) {
+			t.Errorf("mpv args %v missing %q", mpv, want)
+		}
+	}
+	ffplay := pickPlayer{Name: "ffplay"}.Args("song.mp4", 90.5, "pick", "")
+	if i := slices.Index(ffplay, "-ss"); i < 0 || ffplay[i+1] != "90.5" || ffplay[len(ffplay)-1] != "song.mp4" {
+		t.Errorf("ffplay args = %v, want -ss 90.5 ... song.mp4", ffplay)
+	}
+	if _, err := findPickPlayer("vlc"); err == nil {
+		t.Error("expected error for unsupported player")
+	}
+}
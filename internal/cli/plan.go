@@ -0,0 +1,319 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/config"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/render/state"
+	"powerhour/internal/tui"
+	"powerhour/pkg/csvplan"
+)
+
+var (
+	planEditCollection string
+	planSkipCollection string
+	planSkipIndexArg   []string
+)
+
+func newPlanCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Work with collection plan files",
+	}
+	cmd.AddCommand(newPlanEditCmd())
+	cmd.AddCommand(newPlanSkipCmd(true))
+	cmd.AddCommand(newPlanSkipCmd(false))
+	cmd.AddCommand(newPlanSchemaCmd())
+	return cmd
+}
+
+func newPlanEditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "edit",
+		Short: "Interactively reorder, retime, and skip rows in a collection plan",
+		Long: `Open a table editor over a collection's plan file.
+
+Reorder rows (J/K), edit start times (e), and mark rows to skip (s) while
+seeing which rows are cached and rendered. Changes are held in memory until
+you save with w; the plan is rewritten in its original format with unknown
+columns preserved. Skipped rows get skip=yes and are left out of fetch,
+render, concat, and the timeline.`,
+		Args: cobra.NoArgs,
+		RunE: runPlanEdit,
+	}
+	cmd.Flags().StringVar(&planEditCollection, "collection", "", "Collection to edit (default: the only collection, or songs)")
+	return cmd
+}
+
+func runPlanEdit(cmd *cobra.Command, _ []string) error {
+	glogf, gcloser := logx.StartCommand("plan-edit")
+	defer gcloser.Close()
+	glogf("plan edit started: collection=%s", planEditCollection)
+
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
+
+	if len(cfg.Collections) == 0 {
+		return fmt.Errorf("no collections configured")
+	}
+
+	resolver, err := project.NewCollectionResolver(cfg, pp)
+	if err != nil {
+		return err
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		return err
+	}
+
+	name, err := pickPlanCollection(collections, planEditCollection)
+	if err != nil {
+		return err
+	}
+	coll := collections[name]
+	if coll.Plan == "" {
+		return fmt.Errorf("collection %q has no plan file to edit", name)
+	}
+	if len(coll.PlanErrors) > 0 {
+		return fmt.Errorf("collection %q plan has errors; fix them first (see `powerhour validate`): %w", name, coll.PlanErrors)
+	}
+
+	idx, _ := cache.Load(pp)
+	rs, _ := state.Load(pp.RenderStateFile)
+	statuses, _ := buildRowStatuses(pp, cfg, idx, rs, map[string]project.Collection{name: coll}, cfg.SegmentFilenameTemplate())
+
+	rows := make([]tui.PlanEditorRow, 0, len(coll.Rows))
+	for i, row := range coll.Rows {
+		r := tui.PlanEditorRow{Row: row, Skipped: project.RowSkipped(row), Cache: "missing", Render: "missing"}
+		if i < len(statuses) {
+			r.Cache, r.Render = statuses[i].CacheStatus, statuses[i].RenderStatus
+		}
+		rows = append(rows, r)
+	}
+
+	startHeader := project.CollectionOptionsForConfig(coll).StartHeader
+	if startHeader == "" {
+		startHeader = "start_time"
+	}
+	result, err := tui.RunPlanEditor(cmd.OutOrStdout(), tui.PlanEditorOptions{
+		Title:       fmt.Sprintf("%s (%s)", name, filepath.Base(coll.Plan)),
+		StartHeader: startHeader,
+	}, rows)
+	if err != nil {
+		return fmt.Errorf("plan editor: %w", err)
+	}
+	if !result.Saved {
+		glogf("plan edit: discarded")
+		cmd.Println("No changes saved")
+		return nil
+	}
+
+	coll = applyPlanEdits(coll, result.Rows)
+	if err := project.WriteCollectionPlan(coll); err != nil {
+		return err
+	}
+	glogf("plan edit: saved %d rows to %s", len(coll.Rows), coll.Plan)
+	cmd.Printf("Saved %d rows to %s\n", len(coll.Rows), coll.Plan)
+	return nil
+}
+
+func newPlanSkipCmd(skip bool) *cobra.Command {
+	use, short := "skip", "Exclude plan rows from fetch, render, and the timeline"
+	if !skip {
+		use, short = "unskip", "Re-include previously skipped plan rows"
+	}
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runPlanSkip(cmd, skip)
+		},
+	}
+	cmd.Flags().StringVar(&planSkipCollection, "collection", "", "Collection to change (default: the only collection, or songs)")
+	cmd.Flags().StringSliceVar(&planSkipIndexArg, "index", nil, "1-based row index or range like 5-10 (repeat flag for multiple; required)")
+	_ = cmd.MarkFlagRequired("index")
+	return cmd
+}
+
+func runPlanSkip(cmd *cobra.Command, skip bool) error {
+	action := "skip"
+	if !skip {
+		action = "unskip"
+	}
+	glogf, gcloser := logx.StartCommand("plan-" + action)
+	defer gcloser.Close()
+	glogf("plan %s started: collection=%s index=%v", action, planSkipCollection, planSkipIndexArg)
+
+	indexes, err := parseIndexArgs(planSkipIndexArg)
+	if err != nil {
+		return err
+	}
+	if len(indexes) == 0 {
+		return fmt.Errorf("--index is required")
+	}
+
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+
+	resolver, err := project.NewCollectionResolver(cfg, pp)
+	if err != nil {
+		return err
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		return err
+	}
+	name, err := pickPlanCollection(collections, planSkipCollection)
+	if err != nil {
+		return err
+	}
+	coll := collections[name]
+	if coll.Plan == "" {
+		return fmt.Errorf("collection %q has no plan file", name)
+	}
+
+	coll, changed, err := setCollectionRowsSkipped(coll, indexes, skip)
+	if err != nil {
+		return err
+	}
+	if len(changed) > 0 {
+		if err := project.WriteCollectionPlan(coll); err != nil {
+			return err
+		}
+	}
+	glogf("plan %s: changed=%v", action, changed)
+
+	if outputJSON {
+		data, err := json.MarshalIndent(struct {
+			Collection string `json:"collection"`
+			Action     string `json:"action"`
+			Changed    []int  `json:"changed"`
+		}{name, action, changed}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+	if len(changed) == 0 {
+		cmd.Printf("No changes; rows already %sped\n", action)
+		return nil
+	}
+	cmd.Printf("%s %d row(s) in %s: %s\n", strings.ToUpper(action[:1])+action[1:]+"ped", len(changed), name, formatIndexList(changed))
+	return nil
+}
+
+// setCollectionRowsSkipped applies the skip state to the given 1-based rows
+// and returns the indexes that actually changed.
+func setCollectionRowsSkipped(coll project.Collection, indexes []int, skip bool) (project.Collection, []int, error) {
+	byIndex := make(map[int]int, len(coll.Rows))
+	for i, row := range coll.Rows {
+		byIndex[row.Index] = i
+	}
+	var missing []int
+	for _, idx := range indexes {
+		if _, ok := byIndex[idx]; !ok {
+			missing = append(missing, idx)
+		}
+	}
+	if len(missing) > 0 {
+		return coll, nil, fmt.Errorf("collection %q has no row(s) %s (1-%d)", coll.Name, formatIndexList(missing), len(coll.Rows))
+	}
+
+	rows := append([]csvplan.CollectionRow(nil), coll.Rows...)
+	seen := make(map[int]bool, len(indexes))
+	var changed []int
+	for _, idx := range indexes {
+		if seen[idx] {
+			continue
+		}
+		seen[idx] = true
+		i := byIndex[idx]
+		if project.RowSkipped(rows[i]) == skip {
+			continue
+		}
+		rows[i] = project.SetRowSkipped(rows[i], skip)
+		changed = append(changed, idx)
+	}
+	sort.Ints(changed)
+	coll.Rows = rows
+	coll.Headers = csvplan.MergeHeaders(coll.Headers, rows)
+	return coll, changed, nil
+}
+
+func formatIndexList(indexes []int) string {
+	parts := make([]string, len(indexes))
+	for i, idx := range indexes {
+		parts[i] = strconv.Itoa(idx)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// pickPlanCollection resolves --collection, defaulting to the only collection
+// or to "songs" when several are configured.
+func pickPlanCollection(collections map[string]project.Collection, requested string) (string, error) {
+	if requested != "" {
+		if _, ok := collections[requested]; !ok {
+			return "", fmt.Errorf("collection %q not found", requested)
+		}
+		return requested, nil
+	}
+	if len(collections) == 1 {
+		for name := range collections {
+			return name, nil
+		}
+	}
+	if _, ok := collections["songs"]; ok {
+		return "songs", nil
+	}
+	names := make([]string, 0, len(collections))
+	for name := range collections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return "", fmt.Errorf("multiple collections configured; pass --collection (one of %v)", names)
+}
+
+// applyPlanEdits replaces the collection rows with the editor's result,
+// setting or clearing the skip column and widening headers as needed.
+func applyPlanEdits(coll project.Collection, edited []tui.PlanEditorRow) project.Collection {
+	rows := make([]csvplan.CollectionRow, 0, len(edited))
+	for i, e := range edited {
+		row := e.Row
+		if e.Skipped != project.RowSkipped(row) {
+			row = project.SetRowSkipped(row, e.Skipped)
+		}
+		row.Index = i + 1
+		rows = append(rows, row)
+	}
+	coll.Rows = rows
+	coll.Headers = csvplan.MergeHeaders(coll.Headers, rows)
+	return coll
+}
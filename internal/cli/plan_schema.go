@@ -0,0 +1,270 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/config"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+	"powerhour/pkg/csvplan"
+)
+
+var (
+	planSchemaCollection string
+	planSchemaOutput     string
+	planSchemaNoExample  bool
+)
+
+// planColumn describes one plan column for `plan schema`.
+type planColumn struct {
+	Name string `json:"name"`
+	// Canonical is the built-in role the column fills when the collection
+	// renames it (link_header, start_header, duration_header).
+	Canonical   string `json:"canonical,omitempty"`
+	Required    bool   `json:"required"`
+	Example     bool   `json:"in_example"`
+	Description string `json:"description"`
+}
+
+// overlayFieldPattern matches {field} tokens in custom overlay filters.
+var overlayFieldPattern = regexp.MustCompile(`\{([A-Za-z0-9_]+)\}`)
+
+func newPlanSchemaCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Print the expected plan columns for a collection and write an example CSV",
+		Long: `Print the columns a collection's plan is read with: the link and start
+columns (under their configured names), duration, the overlay text fields,
+any {field} tokens used by the collection's custom overlays, and the
+optional per-row control columns.
+
+An example CSV with those columns is written next to the plan as
+<collection>.example.csv, so collaborators can fill a spreadsheet in the
+right shape. Use --output to choose the path or --no-example to skip it.`,
+		Args: cobra.NoArgs,
+		RunE: runPlanSchema,
+	}
+	cmd.Flags().StringVar(&planSchemaCollection, "collection", "", "Collection to describe (default: the only collection, or songs)")
+	cmd.Flags().StringVarP(&planSchemaOutput, "output", "o", "", "Example CSV path (default: <collection>.example.csv next to the plan)")
+	cmd.Flags().BoolVar(&planSchemaNoExample, "no-example", false, "Only print the schema; don't write an example CSV")
+	return cmd
+}
+
+func runPlanSchema(cmd *cobra.Command, _ []string) error {
+	glogf, gcloser := logx.StartCommand("plan-schema")
+	defer gcloser.Close()
+	glogf("plan schema started: collection=%s", planSchemaCollection)
+
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+
+	if len(cfg.Collections) == 0 {
+		return fmt.Errorf("no collections configured")
+	}
+	// The plan file may not exist yet, so pick from the config alone.
+	configured := make(map[string]project.Collection, len(cfg.Collections))
+	for name, collCfg := range cfg.Collections {
+		configured[name] = project.Collection{Name: name, Config: collCfg}
+	}
+	name, err := pickPlanCollection(configured, planSchemaCollection)
+	if err != nil {
+		return err
+	}
+	collCfg := cfg.Collections[name]
+	if strings.TrimSpace(collCfg.Plan) == "" {
+		return fmt.Errorf("collection %q uses a single file, not a plan", name)
+	}
+
+	columns := planSchemaColumns(collCfg)
+
+	examplePath := ""
+	if !planSchemaNoExample {
+		examplePath = planSchemaOutput
+		if examplePath == "" {
+			examplePath = filepath.Join(filepath.Dir(collCfg.Plan), name+".example.csv")
+		}
+		if !filepath.IsAbs(examplePath) {
+			examplePath = filepath.Join(pp.Root, examplePath)
+		}
+		planPath := collCfg.Plan
+		if !filepath.IsAbs(planPath) {
+			planPath = filepath.Join(pp.Root, planPath)
+		}
+		if filepath.Clean(examplePath) == filepath.Clean(planPath) {
+			return fmt.Errorf("refusing to overwrite the plan %s with an example", collCfg.Plan)
+		}
+		if err := writePlanExample(examplePath, collCfg, cfg.PlanDefaultDuration()); err != nil {
+			return err
+		}
+		glogf("plan schema: wrote %s", examplePath)
+	}
+
+	if outputJSON {
+		payload := struct {
+			Collection string       `json:"collection"`
+			Plan       string       `json:"plan"`
+			Columns    []planColumn `json:"columns"`
+			Example    string       `json:"example,omitempty"`
+		}{name, collCfg.Plan, columns, examplePath}
+		data, err := json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Collection: %s (plan: %s)\n\n", name, collCfg.Plan)
+	fmt.Fprintf(out, "  %-16s %-9s %s\n", "COLUMN", "REQUIRED", "DESCRIPTION")
+	for _, c := range columns {
+		required := "no"
+		if c.Required {
+			required = "yes"
+		}
+		desc := c.Description
+		if c.Canonical != "" {
+			desc += fmt.Sprintf(" (%s column)", c.Canonical)
+		}
+		fmt.Fprintf(out, "  %-16s %-9s %s\n", c.Name, required, desc)
+	}
+	if examplePath != "" {
+		fmt.Fprintf(out, "\nWrote example: %s\n", examplePath)
+	}
+	return nil
+}
+
+// planSchemaColumns lists the columns a collection plan is read with, in
+// the order they appear in the example CSV, followed by the optional
+// control columns.
+func planSchemaColumns(collCfg config.CollectionConfig) []planColumn {
+	renamed := func(name, canonical string) string {
+		if name == "" || name == canonical {
+			return ""
+		}
+		return canonical
+	}
+	link := firstNonEmpty(collCfg.LinkHeader, "link")
+	start := firstNonEmpty(collCfg.StartHeader, "start_time")
+	duration := firstNonEmpty(collCfg.DurationHeader, "duration")
+
+	columns := []planColumn{
+		{Name: link, Canonical: renamed(link, "link"), Required: true, Example: true,
+			Description: "Video URL, YouTube ID, or local file path"},
+		{Name: start, Canonical: renamed(start, "start_time"), Required: true, Example: true,
+			Description: "Clip start, e.g. 1:05 or 65"},
+		{Name: duration, Canonical: renamed(duration, "duration"), Example: true,
+			Description: "Clip length in seconds; blank uses the collection default"},
+		{Name: "title", Example: true, Description: "Song title, {title} in overlays"},
+		{Name: "artist", Example: true, Description: "Artist, {artist} in overlays"},
+		{Name: "name", Example: true, Description: "Who picked the song, {name} in overlays"},
+	}
+	seen := make(map[string]bool, len(columns))
+	for _, c := range columns {
+		seen[c.Name] = true
+	}
+	seen["index"] = true
+
+	var custom []string
+	for _, entry := range collCfg.Overlays {
+		for _, filter := range entry.Filters {
+			for _, m := range overlayFieldPattern.FindAllStringSubmatch(filter, -1) {
+				field := strings.ToLower(m[1])
+				if !seen[field] {
+					seen[field] = true
+					custom = append(custom, field)
+				}
+			}
+		}
+	}
+	sort.Strings(custom)
+	for _, field := range custom {
+		columns = append(columns, planColumn{Name: field, Example: true,
+			Description: fmt.Sprintf("Custom overlay field, {%s}", field)})
+	}
+
+	for _, c := range []planColumn{
+		{Name: csvplan.EndTimeField, Description: "Clip end, e.g. 2:05; the clip runs from start to end (or write the start as 1:05-2:05)"},
+		{Name: csvplan.WindowsField, Description: "Several clips from this source, e.g. 0:30-1:00; 2:10-2:40; each renders as its own segment"},
+		{Name: project.SkipField, Description: "yes to leave the row out of fetch, render, and the timeline"},
+		{Name: render.FreezeField, Description: "Seconds to hold the last frame at the end of the clip"},
+		{Name: project.LoopField, Description: "yes to repeat a short source until the clip is filled, no to turn off the collection's loop"},
+		{Name: project.GainField, Description: "Volume adjustment in dB applied before loudnorm, e.g. -6 or +3"},
+		{Name: "crop", Description: "off to disable video.auto_crop for this row"},
+		{Name: project.WeightField, Description: "Selection weight for timeline pick: weighted (default 1)"},
+		{Name: project.TagsField, Description: "Tags for timeline pick: tagged, separated by , ; or |"},
+	} {
+		if !seen[c.Name] {
+			columns = append(columns, c)
+		}
+	}
+	return columns
+}
+
+// writePlanExample writes the example CSV for a collection to path.
+func writePlanExample(path string, collCfg config.CollectionConfig, defaultDuration int) error {
+	headers, rows := planSchemaExample(planSchemaColumns(collCfg), collCfg, defaultDuration)
+	if err := csvplan.WriteCSV(path, headers, rows, ','); err != nil {
+		return fmt.Errorf("write example: %w", err)
+	}
+	return nil
+}
+
+// planSchemaExample builds the example CSV header and two filled-in rows.
+func planSchemaExample(columns []planColumn, collCfg config.CollectionConfig, defaultDuration int) ([]string, []csvplan.CollectionRow) {
+	duration := collCfg.Duration
+	if duration <= 0 {
+		duration = defaultDuration
+	}
+	samples := []map[string]string{
+		{"link": "https://www.youtube.com/watch?v=VIDEO_ID", "start_time": "1:05", "title": "Song Title", "artist": "Artist Name", "name": "Your Name"},
+		{"link": "videos/local-file.mp4", "start_time": "0:30", "title": "Another Song", "artist": "Another Artist"},
+	}
+
+	var headers []string
+	rows := make([]csvplan.CollectionRow, len(samples))
+	for i := range rows {
+		rows[i] = csvplan.CollectionRow{Index: i + 1, CustomFields: map[string]string{}}
+	}
+	for _, c := range columns {
+		if !c.Example {
+			continue
+		}
+		headers = append(headers, c.Name)
+		role := firstNonEmpty(c.Canonical, c.Name)
+		for i, sample := range samples {
+			value := sample[role]
+			if role == "duration" {
+				value = strconv.Itoa(duration)
+			}
+			rows[i].CustomFields[c.Name] = value
+		}
+	}
+	return headers, rows
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"powerhour/internal/config"
+	"powerhour/internal/project"
+	"powerhour/internal/tui"
+	"powerhour/pkg/csvplan"
+)
+
+func TestApplyPlanEditsWritesCSV(t *testing.T) {
+	dir := t.TempDir()
+	plan := filepath.Join(dir, "songs.csv")
+	coll := project.Collection{
+		Name:       "songs",
+		Plan:       plan,
+		PlanFormat: "csv",
+		Delimiter:  ',',
+		Headers:    []string{"title", "start_time", "duration", "link", "notes"},
+	}
+	row := func(title string, fields map[string]string) csvplan.CollectionRow {
+		f := map[string]string{"title": title, "start_time": "0:30", "duration": "60", "link": "https://youtu.be/" + title, "notes": "n-" + title}
+		for k, v := range fields {
+			f[k] = v
+		}
+		return csvplan.CollectionRow{CustomFields: f}
+	}
+
+	edited := []tui.PlanEditorRow{
+		{Row: row("b", map[string]string{"skip": "yes"}), Skipped: false},
+		{Row: row("a", nil), Skipped: true},
+	}
+	coll = applyPlanEdits(coll, edited)
+	if err := project.WriteCollectionPlan(coll); err != nil {
+		t.Fatalf("write plan: %v", err)
+	}
+
+	data, err := os.ReadFile(plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "title,start_time,duration,link,notes,skip\n" +
+		"b,0:30,60,https://youtu.be/b,n-b,\n" +
+		"a,0:30,60,https://youtu.be/a,n-a,yes\n"
+	if string(data) != want {
+		t.Fatalf("plan =\n%s\nwant\n%s", data, want)
+	}
+	if coll.Rows[0].Index != 1 || coll.Rows[1].Index != 2 {
+		t.Errorf("rows not reindexed: %d, %d", coll.Rows[0].Index, coll.Rows[1].Index)
+	}
+}
+
+func TestPickPlanCollection(t *testing.T) {
+	colls := map[string]project.Collection{"songs": {}, "interstitials": {}}
+	if got, err := pickPlanCollection(colls, ""); err != nil || got != "songs" {
+		t.Errorf("default = %q, %v; want songs", got, err)
+	}
+	if _, err := pickPlanCollection(colls, "missing"); err == nil {
+		t.Error("expected error for unknown collection")
+	}
+	delete(colls, "songs")
+	if got, _ := pickPlanCollection(colls, ""); got != "interstitials" {
+		t.Errorf("single collection = %q", got)
+	}
+	colls["bumpers"] = project.Collection{}
+	if _, err := pickPlanCollection(colls, ""); err == nil || !strings.Contains(err.Error(), "--collection") {
+		t.Errorf("expected --collection hint, got %v", err)
+	}
+}
+
+func TestSetCollectionRowsSkipped(t *testing.T) {
+	coll := project.Collection{
+		Name:    "songs",
+		Headers: []string{"title", "link"},
+		Rows: []csvplan.CollectionRow{
+			{Index: 1, CustomFields: map[string]string{"title": "A"}},
+			{Index: 2, CustomFields: map[string]string{"title": "B", "skip": "yes"}},
+			{Index: 3, CustomFields: map[string]string{"title": "C"}},
+		},
+	}
+
+	got, changed, err := setCollectionRowsSkipped(coll, []int{3, 1, 2, 3}, true)
+	if err != nil {
+		t.Fatalf("skip: %v", err)
+	}
+	if len(changed) != 2 || changed[0] != 1 || changed[1] != 3 {
+		t.Fatalf("changed = %v, want [1 3]", changed)
+	}
+	for _, row := range got.Rows {
+		if !project.RowSkipped(row) {
+			t.Errorf("row %d not skipped", row.Index)
+		}
+	}
+	if got.Headers[len(got.Headers)-1] != "skip" {
+		t.Errorf("headers = %v, want skip appended", got.Headers)
+	}
+	if project.RowSkipped(coll.Rows[0]) {
+		t.Error("original collection rows should not be mutated")
+	}
+
+	got, changed, err = setCollectionRowsSkipped(got, []int{2}, false)
+	if err != nil || len(changed) != 1 || project.RowSkipped(got.Rows[1]) {
+		t.Fatalf("unskip: changed=%v err=%v row=%v", changed, err, got.Rows[1].CustomFields)
+	}
+
+	if _, _, err := setCollectionRowsSkipped(coll, []int{9}, true); err == nil {
+		t.Error("expected error for out-of-range index")
+	}
+}
+
+func TestPlanSchemaColumns(t *testing.T) {
+	collCfg := config.CollectionConfig{
+		Plan:        "songs.csv",
+		LinkHeader:  "url",
+		StartHeader: "start_time",
+		Duration:    45,
+		Overlays: []config.OverlayEntry{{
+			Type:    "custom",
+			Filters: []string{"drawtext=text='{year} - {title}'", "drawtext=text='{Genre} #{index}'"},
+		}},
+	}
+	columns := planSchemaColumns(collCfg)
+	byName := make(map[string]planColumn, len(columns))
+	for _, c := range columns {
+		byName[c.Name] = c
+	}
+	if c := byName["url"]; !c.Required || c.Canonical != "link" {
+		t.Fatalf("url column = %+v, want required alias of link", c)
+	}
+	if c := byName["start_time"]; c.Canonical != "" {
+		t.Fatalf("start_time canonical = %q, want empty", c.Canonical)
+	}
+	if _, ok := byName["index"]; ok {
+		t.Fatalf("index should not be listed as a column")
+	}
+	if c := byName["skip"]; c.Example || c.Required {
+		t.Fatalf("skip column = %+v, want optional and not in example", c)
+	}
+
+	headers, rows := planSchemaExample(columns, collCfg, 60)
+	want := "url,start_time,duration,title,artist,name,genre,year"
+	if got := strings.Join(headers, ","); got != want {
+		t.Fatalf("headers = %s, want %s", got, want)
+	}
+	if len(rows) != 2 || rows[0].CustomFields["duration"] != "45" || rows[0].CustomFields["url"] == "" {
+		t.Fatalf("unexpected example rows: %+v", rows)
+	}
+}
@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/paths"
+)
+
+var projectsAddName string
+
+func newProjectsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "projects",
+		Short: "List and manage the registry of known projects",
+		Long: `Projects are registered by name in ~/.powerhour/projects.json so any command
+can reach them from anywhere with --project <name> (or -p <name>).
+
+init, onboarding and import register the projects they create; use
+projects add for existing ones.`,
+	}
+	cmd.AddCommand(newProjectsListCmd(), newProjectsAddCmd(), newProjectsRemoveCmd())
+	return cmd
+}
+
+func newProjectsListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List registered projects",
+		Args:  cobra.NoArgs,
+		RunE:  runProjectsList,
+	}
+}
+
+func newProjectsAddCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add [dir]",
+		Short: "Register a project (default: the current project)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runProjectsAdd,
+	}
+	cmd.Flags().StringVar(&projectsAddName, "name", "", "Name to register under (default: the directory name)")
+	return cmd
+}
+
+func newProjectsRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "remove <name>",
+		Short:             "Forget a registered project (its files are left alone)",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeProjectNames,
+		RunE:              runProjectsRemove,
+	}
+}
+
+type projectListEntry struct {
+	paths.KnownProject
+	Missing bool `json:"missing,omitempty"`
+}
+
+func runProjectsList(cmd *cobra.Command, _ []string) error {
+	projects, err := paths.LoadProjects()
+	if err != nil {
+		return err
+	}
+	entries := make([]projectListEntry, len(projects))
+	for i, p := range projects {
+		exists, _ := paths.FileExists(filepath.Join(p.Root, "powerhour.yaml"))
+		entries[i] = projectListEntry{KnownProject: p, Missing: !exists}
+	}
+
+	if outputJSON {
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+	if len(entries) == 0 {
+		cmd.Println("No projects registered; run powerhour projects add in a project.")
+		return nil
+	}
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tPATH\t")
+	for _, e := range entries {
+		note := ""
+		if e.Missing {
+			note = "(missing)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", e.Name, e.Root, note)
+	}
+	return w.Flush()
+}
+
+func runProjectsAdd(cmd *cobra.Command, args []string) error {
+	var (
+		pp  paths.ProjectPaths
+		err error
+	)
+	if len(args) > 0 {
+		pp, err = paths.ResolveAt(args[0])
+	} else {
+		pp, err = paths.Resolve(projectDir)
+	}
+	if err != nil {
+		return err
+	}
+	exists, err := paths.FileExists(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("no powerhour.yaml in %s", pp.Root)
+	}
+	known, err := paths.RegisterProject(projectsAddName, pp.Root)
+	if err != nil {
+		return err
+	}
+	cmd.Printf("Registered %s as %q\n", known.Root, known.Name)
+	return nil
+}
+
+func runProjectsRemove(cmd *cobra.Command, args []string) error {
+	removed, err := paths.UnregisterProject(args[0])
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("no project named %q is registered", args[0])
+	}
+	cmd.Printf("Removed %q from the registry\n", args[0])
+	return nil
+}
+
+// registerProject adds a project a command just created to the registry.
+// Failing to is only a warning; the project itself is fine.
+func registerProject(cmd *cobra.Command, root string) {
+	if _, err := paths.RegisterProject("", root); err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: register project: %v\n", err)
+	}
+}
@@ -2,8 +2,9 @@ package cli
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"os"
+	"os/signal"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -23,27 +24,24 @@ var (
 	renderDryRun      bool
 	renderIndexArg    []string
 	renderNoProgress  bool
+	renderTimeline    string
+	renderSkipSpace   bool
+	renderSampleSecs  int
+	renderPrintCmd    bool
+
+	renderEstimateOnly    bool
+	renderEstimatePresets []string
+	renderCalibrate       bool
 )
 
-var errMissingCachedSource = errors.New("missing cached source")
-
-type missingCachedSourceError struct {
-	msg string
-}
-
-func (e missingCachedSourceError) Error() string {
-	return e.msg
-}
-
-func (e missingCachedSourceError) Is(target error) bool {
-	return target == errMissingCachedSource
-}
+// errMissingCachedSource marks segments whose source isn't fetched yet.
+var errMissingCachedSource = render.ErrSourceMissing
 
 func newRenderCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "render",
 		Short: "Render cached clips into individual segment files",
-		RunE:  runRender,
+		RunE:  withTelemetry("render", withNotify("render", runRender)),
 	}
 
 	defaultConcurrency := runtime.NumCPU()
@@ -56,6 +54,13 @@ func newRenderCmd() *cobra.Command {
 	cmd.Flags().BoolVar(&renderDryRun, "dry-run", false, "Show what would change without rendering")
 	cmd.Flags().BoolVar(&renderNoProgress, "no-progress", false, "Disable interactive progress output")
 	cmd.Flags().StringSliceVar(&renderIndexArg, "index", nil, "Limit render to specific 1-based row index or range like 5-10 (repeat flag for multiple)")
+	cmd.Flags().StringVar(&renderTimeline, "timeline", "", "Render for a named timeline from timelines: (own segments directory and state)")
+	cmd.Flags().IntVar(&renderSampleSecs, "sample-duration", 0, "Render only the first N seconds of each segment into samples/ to check overlays and framing")
+	cmd.Flags().BoolVar(&renderPrintCmd, "print-cmd", false, "Print the ffmpeg command for each segment without rendering")
+	cmd.Flags().BoolVar(&renderSkipSpace, "skip-space-check", false, "Render even if the estimated output doesn't fit in free disk space")
+	cmd.Flags().BoolVar(&renderEstimateOnly, "estimate", false, "Predict encode time and output sizes without rendering")
+	cmd.Flags().StringSliceVar(&renderEstimatePresets, "estimate-presets", nil, "With --estimate, compare these encoder presets with a test encode each (e.g. veryfast,medium,slow)")
+	cmd.Flags().BoolVar(&renderCalibrate, "calibrate", false, "With --estimate, run a test encode even if render history is available")
 	addCollectionRenderFlags(cmd)
 
 	return cmd
@@ -66,16 +71,20 @@ func runRender(cmd *cobra.Command, _ []string) error {
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	// Ctrl-C stops ffmpeg and cleans up its partial output instead of
+	// killing the process mid-write.
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
 
-	glogf, gcloser := logx.StartCommand("render")
+	glog, gcloser := logx.Command("render")
 	defer gcloser.Close()
-	glogf("render started")
+	glog.Info("render started")
 
 	pp, err := paths.Resolve(projectDir)
 	if err != nil {
 		return err
 	}
-	glogf("project resolved: %s", pp.Root)
+	glog.Debug("project resolved", "root", pp.Root)
 
 	cfg, err := config.Load(pp.ConfigFile)
 	if err != nil {
@@ -83,21 +92,49 @@ func runRender(cmd *cobra.Command, _ []string) error {
 	}
 	pp = paths.ApplyConfig(pp, cfg)
 	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
-	glogf("config loaded (%d collections)", len(cfg.Collections))
+	glog.Debug("config loaded", "collections", len(cfg.Collections))
+
+	cfg, pp, err = applyNamedTimeline(cfg, pp, renderTimeline)
+	if err != nil {
+		return err
+	}
+	if renderTimeline != "" {
+		glog.Info("timeline selected", "timeline", renderTimeline)
+	}
+	if renderSampleSecs < 0 {
+		return fmt.Errorf("--sample-duration must be positive")
+	}
+	if renderSampleSecs > 0 {
+		// Samples get their own segments and state, so they never replace
+		// or invalidate the real render.
+		pp = paths.ApplySamples(pp)
+		fmt.Fprintf(cmd.ErrOrStderr(), "Sample render: first %ds of each segment into %s\n", renderSampleSecs, relPath(pp.Root, pp.SegmentsDir))
+		glog.Info("sample render", "seconds", renderSampleSecs)
+	}
 
 	if cfg.Collections == nil || len(cfg.Collections) == 0 {
 		return fmt.Errorf("no collections configured")
 	}
 
-	err = runCollectionRender(ctx, cmd, pp, cfg)
+	err = runCollectionRender(ctx, cmd, pp, cfg, glog)
 	if err != nil {
-		glogf("render failed: %v", err)
+		glog.Error("render failed", "error", err)
 	} else {
-		glogf("render finished")
+		glog.Info("render finished")
 	}
 	return err
 }
 
+// renderInterrupted reports whether any segment was canceled mid-render.
+func renderInterrupted(results []render.Result) bool {
+	for _, res := range results {
+		if res.Interrupted {
+			return true
+		}
+	}
+	return false
+}
+
 func renderPreflightResult(clip project.Clip, err error) render.Result {
 	return render.Result{
 		Index:     clip.Sequence,
@@ -0,0 +1,368 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/config"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+	"powerhour/internal/render/state"
+)
+
+// calibrationSeconds is the length of the test encode render --estimate
+// runs when there is no usable render history.
+const calibrationSeconds = 10
+
+// encodeRate is how long encoding takes, and how big the output is, per
+// second of output video.
+type encodeRate struct {
+	Preset        string  `json:"preset,omitempty"`
+	Source        string  `json:"source"` // history or calibration
+	Samples       int     `json:"samples"`
+	EncodePerSec  float64 `json:"encode_seconds_per_second"`
+	BytesPerSec   float64 `json:"bytes_per_second"`
+	CalibrationOn string  `json:"calibration_on,omitempty"`
+}
+
+type segmentEstimate struct {
+	Collection    string  `json:"collection"`
+	Index         int     `json:"index"`
+	Title         string  `json:"title"`
+	Seconds       float64 `json:"seconds"`
+	Guessed       bool    `json:"guessed,omitempty"` // full-length clip; length assumed
+	Cached        bool    `json:"cached"`
+	EncodeSeconds float64 `json:"encode_seconds"`
+	Bytes         int64   `json:"bytes"`
+}
+
+type renderEstimate struct {
+	Rate          encodeRate        `json:"rate"`
+	Segments      []segmentEstimate `json:"segments"`
+	EncodeSeconds float64           `json:"encode_seconds"` // one after another
+	WallSeconds   float64           `json:"wall_seconds"`   // at --concurrency
+	SegmentBytes  int64             `json:"segment_bytes"`  // segments to render
+	FinalBytes    int64             `json:"final_bytes"`    // every segment, as concat joins them
+}
+
+// renderEstimateInput is a clip render would consider, with whether its
+// source is cached.
+type renderEstimateInput struct {
+	Collection string
+	Segment    render.Segment
+	Cached     bool
+}
+
+// runRenderEstimate predicts the encode time and output size of the
+// segments render would write, without writing them. Rates come from the
+// ffmpeg timings in render state when they were recorded with the current
+// settings, otherwise from a short calibration encode per preset.
+func runRenderEstimate(ctx context.Context, cmd *cobra.Command, pp paths.ProjectPaths, cfg config.Config, inputs []renderEstimateInput) error {
+	rs, err := state.Load(pp.RenderStateFile)
+	if err != nil {
+		return fmt.Errorf("load render state: %w", err)
+	}
+
+	pending, upToDateBytes, upToDate := pendingForEstimate(rs, cfg, inputs, renderForce)
+
+	var rates []encodeRate
+	if rate, ok := historyRate(rs, cfg); ok && len(renderEstimatePresets) == 0 && !renderCalibrate {
+		rates = append(rates, rate)
+	} else {
+		sample, ok := calibrationSegment(pending, inputs)
+		if !ok {
+			return fmt.Errorf("no cached source to calibrate with; run `powerhour fetch` first")
+		}
+		presets := renderEstimatePresets
+		if len(presets) == 0 {
+			presets = []string{cfg.Video.Preset}
+		}
+		for _, preset := range presets {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Calibrating %s with a %ds test encode...\n", presetLabel(preset), calibrationSeconds)
+			rate, err := calibrateEncode(ctx, pp, cfg, sample, preset)
+			if err != nil {
+				return fmt.Errorf("calibrate %s: %w", presetLabel(preset), err)
+			}
+			rates = append(rates, rate)
+		}
+	}
+
+	estimates := make([]renderEstimate, len(rates))
+	for i, rate := range rates {
+		estimates[i] = estimateRender(pending, rate, renderConcurrency, upToDateBytes)
+	}
+
+	if outputJSON {
+		data, err := json.MarshalIndent(map[string]any{
+			"project":     pp.Root,
+			"concurrency": renderConcurrency,
+			"up_to_date":  upToDate,
+			"estimates":   estimates,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+	printRenderEstimates(cmd, estimates, upToDate)
+	return nil
+}
+
+// pendingForEstimate returns the clips render would encode: uncached ones
+// and cached ones whose inputs changed. It also returns the size and count
+// of the segments that are up to date.
+func pendingForEstimate(rs *state.RenderState, cfg config.Config, inputs []renderEstimateInput, force bool) ([]renderEstimateInput, int64, int) {
+	var cached, pending []renderEstimateInput
+	var segments []render.Segment
+	for _, in := range inputs {
+		if !in.Cached {
+			pending = append(pending, in)
+			continue
+		}
+		in.Segment.StoredHash = rs.StoredHash(in.Segment, cfg)
+		cached = append(cached, in)
+		segments = append(segments, in.Segment)
+	}
+
+	var upToDateBytes int64
+	upToDate := 0
+	for i, action := range state.DetectChanges(rs, segments, cfg, cfg.SegmentFilenameTemplate(), force) {
+		if action.Action != state.ActionSkip {
+			pending = append(pending, cached[i])
+			continue
+		}
+		upToDate++
+		if info, err := os.Stat(action.Segment.OutputPath); err == nil {
+			upToDateBytes += info.Size()
+		}
+	}
+	return pending, upToDateBytes, upToDate
+}
+
+// historyRate averages the recorded ffmpeg timings and output sizes of the
+// current segments, falling back to past runs when none of them were timed.
+// Anything rendered under other settings doesn't count.
+func historyRate(rs *state.RenderState, cfg config.Config) (encodeRate, bool) {
+	hash := state.GlobalConfigHash(cfg)
+	var encode, seconds, bytes float64
+	rate := encodeRate{Preset: cfg.Video.Preset, Source: "history"}
+	for path, seg := range rs.Segments {
+		if seg.EncodeS <= 0 || seg.DurationS <= 0 || !rs.SettingsMatch(seg, cfg) {
+			continue
+		}
+		size := seg.SizeBytes
+		if size == 0 {
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			size = info.Size()
+		}
+		encode += seg.EncodeS
+		seconds += seg.DurationS
+		bytes += float64(size)
+		rate.Samples++
+	}
+	if rate.Samples == 0 {
+		for _, run := range rs.Runs {
+			if run.ConfigHash != hash || run.EncodeS <= 0 || run.VideoS <= 0 {
+				continue
+			}
+			encode += run.EncodeS
+			seconds += run.VideoS
+			bytes += float64(run.Bytes)
+			rate.Samples += run.Rendered
+		}
+	}
+	if rate.Samples == 0 {
+		return encodeRate{}, false
+	}
+	rate.EncodePerSec = encode / seconds
+	rate.BytesPerSec = bytes / seconds
+	return rate, true
+}
+
+// calibrationSegment picks a cached clip with a real source to test-encode,
+// preferring one that is about to be rendered.
+func calibrationSegment(pending, inputs []renderEstimateInput) (renderEstimateInput, bool) {
+	for _, list := range [][]renderEstimateInput{pending, inputs} {
+		for _, in := range list {
+			if in.Cached && in.Segment.Clip.SourceKind != project.SourceKindGenerator && in.Segment.SourcePath != "" {
+				return in, true
+			}
+		}
+	}
+	return renderEstimateInput{}, false
+}
+
+// calibrateEncode renders the first seconds of sample with preset to a
+// temporary file and measures how long ffmpeg took and how big the result
+// is. Nothing in the project changes.
+func calibrateEncode(ctx context.Context, pp paths.ProjectPaths, cfg config.Config, sample renderEstimateInput, preset string) (encodeRate, error) {
+	cfg.Video.Preset = preset
+	dir, err := os.MkdirTemp("", "powerhour-estimate-")
+	if err != nil {
+		return encodeRate{}, err
+	}
+	defer os.RemoveAll(dir)
+
+	seg := sample.Segment
+	seconds := calibrationSeconds
+	if d := seg.Clip.DurationSeconds; d > 0 && d < seconds {
+		seconds = d
+	}
+	seg.Clip.DurationSeconds = seconds
+	seg.Clip.Row.DurationSeconds = seconds
+	seg.OutputPath = filepath.Join(dir, "estimate-calibration.mp4")
+	seg.StoredHash = ""
+
+	svc, err := render.NewService(ctx, pp, cfg, nil)
+	if err != nil {
+		return encodeRate{}, err
+	}
+	res := svc.Render(ctx, []render.Segment{seg}, render.Options{Force: true})[0]
+	if res.Err != nil {
+		return encodeRate{}, res.Err
+	}
+	_ = os.Remove(res.LogPath)
+	info, err := os.Stat(res.OutputPath)
+	if err != nil {
+		return encodeRate{}, err
+	}
+	length := seg.OutputSeconds()
+	return encodeRate{
+		Preset:        preset,
+		Source:        "calibration",
+		Samples:       1,
+		EncodePerSec:  res.Elapsed.Seconds() / length,
+		BytesPerSec:   float64(info.Size()) / length,
+		CalibrationOn: fmt.Sprintf("%s #%03d", sample.Collection, seg.Clip.Row.Index),
+	}, nil
+}
+
+// estimateRender applies rate to each pending clip. Wall time assumes the
+// encodes split evenly across concurrency workers.
+func estimateRender(pending []renderEstimateInput, rate encodeRate, concurrency int, upToDateBytes int64) renderEstimate {
+	est := renderEstimate{Rate: rate, FinalBytes: upToDateBytes}
+	for _, in := range pending {
+		clip := in.Segment.Clip
+		se := segmentEstimate{
+			Collection: in.Collection,
+			Index:      clip.Row.Index,
+			Title:      clipDisplayTitle(clip),
+			Seconds:    in.Segment.OutputSeconds(),
+			Cached:     in.Cached,
+		}
+		if clip.DurationSeconds <= 0 {
+			se.Seconds = fallbackClipSeconds + clip.PrerollSeconds + clip.PostrollSeconds
+			se.Guessed = true
+		}
+		se.EncodeSeconds = se.Seconds * rate.EncodePerSec
+		se.Bytes = int64(se.Seconds * rate.BytesPerSec)
+		est.Segments = append(est.Segments, se)
+		est.EncodeSeconds += se.EncodeSeconds
+		est.SegmentBytes += se.Bytes
+	}
+	est.FinalBytes += est.SegmentBytes
+	est.WallSeconds = est.EncodeSeconds / float64(max(concurrency, 1))
+	return est
+}
+
+func presetLabel(preset string) string {
+	if preset == "" {
+		return "the default preset"
+	}
+	return "preset " + preset
+}
+
+func printRenderEstimates(cmd *cobra.Command, estimates []renderEstimate, upToDate int) {
+	out := cmd.OutOrStdout()
+	first := estimates[0]
+	if len(first.Segments) == 0 {
+		fmt.Fprintf(out, "Nothing to render: %d segments up to date (%s).\n", upToDate, formatBytes(first.FinalBytes))
+		return
+	}
+
+	if len(estimates) == 1 {
+		w := tabwriter.NewWriter(out, 0, 2, 2, ' ', 0)
+		fmt.Fprintln(w, "COLLECTION\tINDEX\tTITLE\tLENGTH\tENCODE\tSIZE")
+		for _, se := range first.Segments {
+			length := formatSampleTime(se.Seconds)
+			if se.Guessed {
+				length += "?"
+			}
+			title := se.Title
+			if !se.Cached {
+				title += " (not cached)"
+			}
+			fmt.Fprintf(w, "%s\t%03d\t%s\t%s\t%s\t%s\n", se.Collection, se.Index, title, length, formatSampleTime(se.EncodeSeconds), formatBytes(se.Bytes))
+		}
+		w.Flush()
+		fmt.Fprintln(out)
+	}
+
+	var video float64
+	guessed := 0
+	for _, se := range first.Segments {
+		video += se.Seconds
+		if se.Guessed {
+			guessed++
+		}
+	}
+	fmt.Fprintf(out, "To render: %d segments, %s of video", len(first.Segments), formatSampleTime(video))
+	if upToDate > 0 {
+		fmt.Fprintf(out, " (%d up to date)", upToDate)
+	}
+	fmt.Fprintln(out)
+	if guessed > 0 {
+		fmt.Fprintf(out, "Full-length clips (?) are assumed to be %s.\n", formatSampleTime(fallbackClipSeconds))
+	}
+
+	if len(estimates) == 1 {
+		printRateSource(out, first.Rate)
+		fmt.Fprintf(out, "Encode time: %s one at a time, ~%s at --concurrency %d\n",
+			formatSampleTime(first.EncodeSeconds), formatSampleTime(first.WallSeconds), renderConcurrency)
+		fmt.Fprintf(out, "Size: %s of new segments; final video ~%s\n", formatBytes(first.SegmentBytes), formatBytes(first.FinalBytes))
+		return
+	}
+
+	fmt.Fprintln(out)
+	w := tabwriter.NewWriter(out, 0, 2, 2, ' ', 0)
+	fmt.Fprintf(w, "PRESET\tSPEED\tENCODE\tWALL (x%d)\tSEGMENTS\tFINAL\n", renderConcurrency)
+	for _, est := range estimates {
+		preset := est.Rate.Preset
+		if preset == "" {
+			preset = "(default)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", preset, realtimeSpeed(est.Rate),
+			formatSampleTime(est.EncodeSeconds), formatSampleTime(est.WallSeconds), formatBytes(est.SegmentBytes), formatBytes(est.FinalBytes))
+	}
+	w.Flush()
+	fmt.Fprintf(out, "Calibrated on %s; speed is seconds of video per second of encoding.\n", first.Rate.CalibrationOn)
+}
+
+func printRateSource(out io.Writer, rate encodeRate) {
+	switch rate.Source {
+	case "history":
+		fmt.Fprintf(out, "Based on %d previous renders with these settings (%s realtime).\n", rate.Samples, realtimeSpeed(rate))
+	default:
+		fmt.Fprintf(out, "Based on a %ds test encode of %s with %s (%s realtime).\n", calibrationSeconds, rate.CalibrationOn, presetLabel(rate.Preset), realtimeSpeed(rate))
+	}
+}
+
+// realtimeSpeed is seconds of video encoded per second, e.g. "3.2x".
+func realtimeSpeed(rate encodeRate) string {
+	if rate.EncodePerSec <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1fx", 1/rate.EncodePerSec)
+}
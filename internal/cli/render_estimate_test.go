@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"powerhour/internal/config"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+	"powerhour/internal/render/state"
+	"powerhour/pkg/csvplan"
+)
+
+func TestHistoryRate(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.Default()
+	write := func(name string, size int) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, make([]byte, size), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	rs := &state.RenderState{
+		GlobalConfigHash: state.GlobalConfigHash(cfg),
+		Segments: map[string]state.SegmentState{
+			write("a.mp4", 6000):           {DurationS: 60, EncodeS: 30},
+			write("b.mp4", 2000):           {DurationS: 20, EncodeS: 6},
+			write("old.mp4", 100):          {DurationS: 60}, // rendered before timings were kept
+			filepath.Join(dir, "gone.mp4"): {DurationS: 60, EncodeS: 10},
+		},
+	}
+
+	rate, ok := historyRate(rs, cfg)
+	if !ok {
+		t.Fatal("expected a history rate")
+	}
+	if rate.Samples != 2 || rate.EncodePerSec != 36.0/80 || rate.BytesPerSec != 100 {
+		t.Fatalf("rate = %+v", rate)
+	}
+
+	rs.GlobalConfigHash = "other settings"
+	if _, ok := historyRate(rs, cfg); ok {
+		t.Fatal("history from other settings should not count")
+	}
+
+	// Runs still count once the settings come back around.
+	rs.Runs = []state.RunStats{
+		{ConfigHash: state.GlobalConfigHash(cfg), Rendered: 3, VideoS: 180, EncodeS: 90, Bytes: 18000},
+		{ConfigHash: "other settings", Rendered: 1, VideoS: 60, EncodeS: 600, Bytes: 1},
+	}
+	rate, ok = historyRate(rs, cfg)
+	if !ok || rate.Samples != 3 || rate.EncodePerSec != 0.5 || rate.BytesPerSec != 100 {
+		t.Fatalf("rate from runs = %+v, %v", rate, ok)
+	}
+}
+
+func TestEstimateRender(t *testing.T) {
+	clip := func(index, seconds int, preroll float64) render.Segment {
+		return render.Segment{Clip: project.Clip{
+			Row:             csvplan.Row{Index: index, Title: "Song"},
+			DurationSeconds: seconds,
+			PrerollSeconds:  preroll,
+		}}
+	}
+	pending := []renderEstimateInput{
+		{Collection: "songs", Segment: clip(1, 60, 0), Cached: true},
+		{Collection: "songs", Segment: clip(2, 58, 2), Cached: false},
+		{Collection: "songs", Segment: clip(3, 0, 0), Cached: true}, // full length
+	}
+	rate := encodeRate{EncodePerSec: 0.5, BytesPerSec: 1000}
+
+	est := estimateRender(pending, rate, 4, 50000)
+	if len(est.Segments) != 3 {
+		t.Fatalf("segments = %d", len(est.Segments))
+	}
+	if s := est.Segments[1]; s.Seconds != 60 || s.EncodeSeconds != 30 || s.Bytes != 60000 || s.Cached {
+		t.Fatalf("segment 2 = %+v", s)
+	}
+	if s := est.Segments[2]; !s.Guessed || s.Seconds != fallbackClipSeconds {
+		t.Fatalf("full-length segment = %+v", s)
+	}
+	wantEncode := (60 + 60 + float64(fallbackClipSeconds)) * 0.5
+	if est.EncodeSeconds != wantEncode || est.WallSeconds != wantEncode/4 {
+		t.Fatalf("encode %v wall %v, want %v and %v", est.EncodeSeconds, est.WallSeconds, wantEncode, wantEncode/4)
+	}
+	if est.FinalBytes != est.SegmentBytes+50000 {
+		t.Fatalf("final %d should add the up-to-date bytes to %d", est.FinalBytes, est.SegmentBytes)
+	}
+}
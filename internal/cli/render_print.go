@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/config"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+)
+
+// printedCommand is one segment's entry in render --print-cmd output.
+type printedCommand struct {
+	Segment string   `json:"segment"`
+	Output  string   `json:"output"`
+	Args    []string `json:"args,omitempty"` // ffmpeg path first
+	Error   string   `json:"error,omitempty"`
+}
+
+// printRenderCommands writes the ffmpeg command render would run for each
+// selected segment without fetching or rendering anything (render
+// --print-cmd). Inline file entries follow when the whole timeline is
+// selected, as render does them too. Segments without a command, such as
+// uncached sources, are listed with the reason and fail the run.
+func printRenderCommands(ctx context.Context, cmd *cobra.Command, pp paths.ProjectPaths, cfg config.Config, clips []project.CollectionClip, segments []render.Segment, preflight []render.Result) error {
+	svc, err := render.NewService(ctx, pp, cfg, nil)
+	if err != nil {
+		return err
+	}
+
+	type pending struct {
+		label string
+		seg   render.Segment
+		err   error
+	}
+	var all []pending
+	for i, cc := range clips {
+		label := fmt.Sprintf("%s #%03d %s", cc.CollectionName, cc.Clip.Row.Index, clipDisplayTitle(cc.Clip))
+		all = append(all, pending{label: label, seg: segments[i], err: preflight[i].Err})
+	}
+	if renderCollection == "" && len(renderIndexArg) == 0 {
+		inline, err := buildInlineSegments(pp, cfg, float64(renderSampleSecs))
+		if err != nil {
+			return err
+		}
+		for _, seg := range inline {
+			all = append(all, pending{label: "inline " + filepath.Base(seg.SourcePath), seg: seg})
+		}
+	}
+
+	var printed []printedCommand
+	failed := 0
+	for _, p := range all {
+		entry := printedCommand{Segment: p.label, Output: relPath(pp.Root, p.seg.OutputPath)}
+		err := p.err
+		if err == nil {
+			entry.Args, err = svc.Command(ctx, p.seg)
+		}
+		if err != nil {
+			entry.Error = err.Error()
+			failed++
+		}
+		printed = append(printed, entry)
+	}
+
+	out := cmd.OutOrStdout()
+	if outputJSON {
+		data, err := json.MarshalIndent(printed, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshal commands: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+	} else {
+		for _, entry := range printed {
+			fmt.Fprintf(out, "# %s -> %s\n", entry.Segment, entry.Output)
+			if entry.Error != "" {
+				fmt.Fprintf(out, "# error: %s\n\n", entry.Error)
+				continue
+			}
+			fmt.Fprintln(out, render.FormatCommand(entry.Args))
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d segment(s) have no command", failed)
+	}
+	return nil
+}
@@ -0,0 +1,362 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/config"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+	"powerhour/internal/tui"
+	"powerhour/pkg/csvplan"
+)
+
+var (
+	reviewTimeline   string
+	reviewPlayerName string
+	reviewNoAutoplay bool
+	reviewRun        bool
+)
+
+func newReviewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "review",
+		Short: "Watch rendered segments in order and decide what to redo",
+		Long: `Walk the rendered segments in timeline order, playing each in mpv or
+ffplay, and decide per segment:
+
+  a  accept      keep it as rendered
+  r  reject      skip the row in the plan
+  f  re-fetch    download the source again and re-render
+  t  re-trim     set a new start time and re-render
+
+Nothing changes until you finish with w. Then rejected rows are skipped,
+re-trimmed rows get their new start time, their segments are marked stale,
+and the fetch and render commands needed to redo them are printed. With
+--run they are run right away.
+
+Only collection rows with a plan are reviewed; inline files, slates and
+segments that aren't rendered yet are left out.`,
+		Args: cobra.NoArgs,
+		RunE: runReview,
+	}
+	cmd.Flags().StringVar(&reviewTimeline, "timeline", "", "Review a named timeline from timelines:")
+	cmd.Flags().StringVar(&reviewPlayerName, "player", "auto", "Player to use: auto, mpv, ffplay")
+	_ = cmd.RegisterFlagCompletionFunc("player", cobra.FixedCompletions([]string{"auto", "mpv", "ffplay"}, cobra.ShellCompDirectiveNoFileComp))
+	cmd.Flags().BoolVar(&reviewNoAutoplay, "no-autoplay", false, "Don't play each segment automatically; press enter to play")
+	cmd.Flags().BoolVar(&reviewRun, "run", false, "Run the queued fetch and render commands after the review")
+	return cmd
+}
+
+type reviewDecision struct {
+	Slot       int    `json:"slot"`
+	Collection string `json:"collection"`
+	Index      int    `json:"index"`
+	Decision   string `json:"decision"`
+	NewStart   string `json:"new_start,omitempty"`
+}
+
+func runReview(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	glogf, gcloser := logx.StartCommand("review")
+	defer gcloser.Close()
+	glogf("review started: timeline=%s player=%s", reviewTimeline, reviewPlayerName)
+
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
+	cfg, pp, err = applyNamedTimeline(cfg, pp, reviewTimeline)
+	if err != nil {
+		return err
+	}
+	if len(cfg.Collections) == 0 {
+		return fmt.Errorf("no collections configured")
+	}
+
+	resolver, err := project.NewCollectionResolver(cfg, pp)
+	if err != nil {
+		return err
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		return err
+	}
+	segments, err := render.ResolveTimelineSegments(pp, cfg, collections)
+	if err != nil {
+		return fmt.Errorf("resolve timeline: %w", err)
+	}
+	items, unrendered := buildReviewItems(segments, project.WithRowOverrides(collections))
+	if len(items) == 0 {
+		return fmt.Errorf("no rendered segments to review; run `powerhour render` first")
+	}
+
+	player, err := findPickPlayer(reviewPlayerName)
+	if err != nil {
+		return err
+	}
+	result, err := tui.RunReview(cmd.OutOrStdout(), tui.ReviewOptions{
+		Title: fmt.Sprintf("%s (%d segments)", firstNonEmpty(reviewTimeline, "timeline"), len(items)),
+		Play: func(item tui.ReviewItem) *exec.Cmd {
+			title := fmt.Sprintf("%d. %s", item.Slot, firstNonEmpty(item.Title, fmt.Sprintf("%s #%d", item.Collection, item.Index)))
+			return exec.Command(player.Path, player.PlayArgs(item.Path, title)...)
+		},
+		AutoPlay: !reviewNoAutoplay,
+	}, items)
+	if err != nil {
+		return fmt.Errorf("review: %w", err)
+	}
+	if !result.Done {
+		glogf("review: discarded")
+		cmd.Println("Review discarded; nothing changed")
+		return nil
+	}
+
+	decisions, queue, err := applyReviewDecisions(pp, collections, result.Items, reviewTimeline)
+	if err != nil {
+		return err
+	}
+	glogf("review: %d decisions, %d queued command(s)", len(decisions), len(queue))
+
+	if outputJSON {
+		data, err := json.MarshalIndent(struct {
+			Decisions  []reviewDecision `json:"decisions"`
+			Unrendered int              `json:"unrendered"`
+			Queue      [][]string       `json:"queue"`
+		}{decisions, unrendered, queue}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+	} else {
+		writeReviewSummary(cmd, decisions, unrendered, queue)
+	}
+
+	if !reviewRun {
+		return nil
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("locate powerhour: %w", err)
+	}
+	for _, args := range queue {
+		run := exec.CommandContext(ctx, self, args...)
+		run.Stdin = os.Stdin
+		run.Stdout = cmd.OutOrStdout()
+		run.Stderr = cmd.ErrOrStderr()
+		if err := run.Run(); err != nil {
+			return fmt.Errorf("powerhour %s: %w", strings.Join(args, " "), err)
+		}
+	}
+	return nil
+}
+
+// buildReviewItems lists the rendered collection segments in timeline
+// order, once each, and counts the ones not rendered yet.
+func buildReviewItems(segments []render.TimelineSegmentPath, collections map[string]project.Collection) ([]tui.ReviewItem, int) {
+	seen := make(map[string]bool, len(segments))
+	var items []tui.ReviewItem
+	unrendered := 0
+	for i, seg := range segments {
+		if seen[seg.Path] {
+			continue
+		}
+		seen[seg.Path] = true
+		coll, ok := collections[seg.CollectionName]
+		if !ok || coll.Plan == "" {
+			continue
+		}
+		if _, err := os.Stat(seg.Path); err != nil {
+			unrendered++
+			continue
+		}
+		item := tui.ReviewItem{Slot: i + 1, Collection: seg.CollectionName, Index: seg.Index, Path: seg.Path}
+		for _, row := range coll.Rows {
+			if row.Index == seg.Index {
+				item.Title = firstNonEmpty(row.CustomFields["title"], row.CustomFields["name"])
+				item.Artist = row.CustomFields["artist"]
+				item.Start = row.StartRaw
+				break
+			}
+		}
+		items = append(items, item)
+	}
+	return items, unrendered
+}
+
+// applyReviewDecisions writes rejects and re-trims to the plans, marks the
+// affected segments stale, and returns the fetch and render commands that
+// redo them, one pair per collection.
+func applyReviewDecisions(pp paths.ProjectPaths, collections map[string]project.Collection, items []tui.ReviewItem, timeline string) ([]reviewDecision, [][]string, error) {
+	var decisions []reviewDecision
+	rejects := make(map[string][]int)
+	refetch := make(map[string][]int)
+	rerender := make(map[string][]int)
+	retrim := make(map[string]map[int]string)
+	var stale []string
+	for _, item := range items {
+		if item.Decision == tui.ReviewUndecided {
+			continue
+		}
+		decisions = append(decisions, reviewDecision{
+			Slot:       item.Slot,
+			Collection: item.Collection,
+			Index:      item.Index,
+			Decision:   string(item.Decision),
+			NewStart:   item.NewStart,
+		})
+		switch item.Decision {
+		case tui.ReviewReject:
+			rejects[item.Collection] = append(rejects[item.Collection], item.Index)
+		case tui.ReviewRefetch:
+			refetch[item.Collection] = append(refetch[item.Collection], item.Index)
+			rerender[item.Collection] = append(rerender[item.Collection], item.Index)
+			stale = append(stale, item.Path)
+		case tui.ReviewRetrim:
+			if retrim[item.Collection] == nil {
+				retrim[item.Collection] = make(map[int]string)
+			}
+			retrim[item.Collection][item.Index] = item.NewStart
+			rerender[item.Collection] = append(rerender[item.Collection], item.Index)
+			stale = append(stale, item.Path)
+		}
+	}
+
+	// Every plan is updated before any is written, so a refused re-trim
+	// leaves all of them untouched.
+	var changed []project.Collection
+	for _, name := range sortedKeys(collections) {
+		if len(rejects[name]) == 0 && len(retrim[name]) == 0 {
+			continue
+		}
+		coll := collections[name]
+		var err error
+		if len(rejects[name]) > 0 {
+			if coll, _, err = setCollectionRowsSkipped(coll, rejects[name], true); err != nil {
+				return nil, nil, err
+			}
+		}
+		if len(retrim[name]) > 0 {
+			if coll, err = retrimCollectionRows(coll, retrim[name]); err != nil {
+				return nil, nil, err
+			}
+		}
+		changed = append(changed, coll)
+	}
+	for _, coll := range changed {
+		if err := project.WriteCollectionPlan(coll); err != nil {
+			return nil, nil, err
+		}
+	}
+	if _, err := markSegmentsStale(pp.RenderStateFile, stale); err != nil {
+		return nil, nil, err
+	}
+
+	var queue [][]string
+	for _, name := range sortedKeys(collections) {
+		if rows := refetch[name]; len(rows) > 0 {
+			queue = append(queue, []string{"fetch", "--project", pp.Root, "--collection", name, "--index", joinIndexes(rows), "--force"})
+		}
+	}
+	for _, name := range sortedKeys(collections) {
+		if rows := rerender[name]; len(rows) > 0 {
+			args := []string{"render", "--project", pp.Root, "--collection", name, "--index", joinIndexes(rows)}
+			if timeline != "" {
+				args = append(args, "--timeline", timeline)
+			}
+			queue = append(queue, args)
+		}
+	}
+	return decisions, queue, nil
+}
+
+// retrimCollectionRows sets new start times on rows keyed by 1-based index.
+func retrimCollectionRows(coll project.Collection, starts map[int]string) (project.Collection, error) {
+	startHeader := project.CollectionOptionsForConfig(coll).StartHeader
+	if startHeader == "" {
+		startHeader = "start_time"
+	}
+	rows := append([]csvplan.CollectionRow(nil), coll.Rows...)
+	for i, row := range rows {
+		raw, ok := starts[row.Index]
+		if !ok {
+			continue
+		}
+		if ov, ok := coll.RowOverrideFor(row); ok && ov.StartTime != "" {
+			return coll, fmt.Errorf("row %d start time is set by the overrides file (%s); edit it there", row.Index, ov.StartTime)
+		}
+		if err := checkRowWindows(row); err != nil {
+			return coll, err
+		}
+		start, err := csvplan.ParseStartTime(raw)
+		if err != nil {
+			return coll, fmt.Errorf("row %d: %w", row.Index, err)
+		}
+		rows[i] = setRowStart(row, startHeader, start)
+	}
+	coll.Rows = rows
+	coll.Headers = csvplan.MergeHeaders(coll.Headers, rows)
+	return coll, nil
+}
+
+func joinIndexes(indexes []int) string {
+	sorted := append([]int(nil), indexes...)
+	sort.Ints(sorted)
+	parts := make([]string, len(sorted))
+	for i, idx := range sorted {
+		parts[i] = strconv.Itoa(idx)
+	}
+	return strings.Join(parts, ",")
+}
+
+func sortedKeys(collections map[string]project.Collection) []string {
+	names := make([]string, 0, len(collections))
+	for name := range collections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func writeReviewSummary(cmd *cobra.Command, decisions []reviewDecision, unrendered int, queue [][]string) {
+	counts := make(map[string]int)
+	for _, d := range decisions {
+		counts[d.Decision]++
+	}
+	cmd.Printf("Reviewed %d segment(s): %d accepted, %d rejected, %d to re-fetch, %d re-trimmed\n",
+		len(decisions), counts[string(tui.ReviewAccept)], counts[string(tui.ReviewReject)],
+		counts[string(tui.ReviewRefetch)], counts[string(tui.ReviewRetrim)])
+	if unrendered > 0 {
+		cmd.Printf("%d segment(s) not rendered yet were left out\n", unrendered)
+	}
+	if len(queue) == 0 {
+		return
+	}
+	if reviewRun {
+		cmd.Println("Running:")
+	} else {
+		cmd.Println("Queued:")
+	}
+	for _, args := range queue {
+		cmd.Printf("  powerhour %s\n", strings.Join(args, " "))
+	}
+}
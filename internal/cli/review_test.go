@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/render/state"
+	"powerhour/internal/tui"
+	"powerhour/pkg/csvplan"
+)
+
+func TestApplyReviewDecisions(t *testing.T) {
+	dir := t.TempDir()
+	pp := paths.ProjectPaths{Root: dir, RenderStateFile: filepath.Join(dir, ".powerhour", "render_state.json")}
+	rows := make([]csvplan.CollectionRow, 4)
+	for i := range rows {
+		rows[i] = csvplan.CollectionRow{Index: i + 1, StartRaw: "0:30", CustomFields: map[string]string{"title": string(rune('a' + i)), "start_time": "0:30"}}
+	}
+	collections := map[string]project.Collection{"songs": {
+		Name:       "songs",
+		Plan:       filepath.Join(dir, "songs.csv"),
+		PlanFormat: "csv",
+		Delimiter:  ',',
+		Headers:    []string{"title", "start_time"},
+		Rows:       rows,
+	}}
+
+	seg := func(i int) string { return filepath.Join(dir, "segments", string(rune('a'+i-1))+".mp4") }
+	rs, _ := state.Load(pp.RenderStateFile)
+	for i := 1; i <= 4; i++ {
+		rs.Segments[seg(i)] = state.SegmentState{InputHash: "h"}
+	}
+	if err := rs.Save(pp.RenderStateFile); err != nil {
+		t.Fatal(err)
+	}
+
+	items := []tui.ReviewItem{
+		{Slot: 1, Collection: "songs", Index: 1, Path: seg(1), Decision: tui.ReviewAccept},
+		{Slot: 2, Collection: "songs", Index: 2, Path: seg(2), Decision: tui.ReviewReject},
+		{Slot: 3, Collection: "songs", Index: 3, Path: seg(3), Decision: tui.ReviewRetrim, NewStart: "0:42"},
+		{Slot: 4, Collection: "songs", Index: 4, Path: seg(4), Decision: tui.ReviewRefetch},
+	}
+	decisions, queue, err := applyReviewDecisions(pp, collections, items, "")
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if len(decisions) != 4 {
+		t.Fatalf("decisions = %+v, want 4", decisions)
+	}
+
+	data, err := os.ReadFile(collections["songs"].Plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "title,start_time,skip\na,0:30,\nb,0:30,yes\nc,0:42,\nd,0:30,\n"
+	if string(data) != want {
+		t.Fatalf("plan =\n%s\nwant\n%s", data, want)
+	}
+
+	rs, _ = state.Load(pp.RenderStateFile)
+	for i, wantStale := range []bool{false, false, true, true} {
+		if stale := rs.Segments[seg(i+1)].InputHash == ""; stale != wantStale {
+			t.Errorf("segment %d stale = %v, want %v", i+1, stale, wantStale)
+		}
+	}
+
+	wantQueue := [][]string{
+		{"fetch", "--project", dir, "--collection", "songs", "--index", "4", "--force"},
+		{"render", "--project", dir, "--collection", "songs", "--index", "3,4"},
+	}
+	if !slices.EqualFunc(queue, wantQueue, slices.Equal[[]string]) {
+		t.Fatalf("queue = %q, want %q", queue, wantQueue)
+	}
+}
+
+func TestApplyReviewDecisionsRefusesOverriddenStart(t *testing.T) {
+	dir := t.TempDir()
+	plan := filepath.Join(dir, "songs.csv")
+	collections := map[string]project.Collection{"songs": {
+		Name:      "songs",
+		Plan:      plan,
+		Headers:   []string{"title", "start_time"},
+		Rows:      []csvplan.CollectionRow{{Index: 1, CustomFields: map[string]string{"title": "a"}}, {Index: 2, CustomFields: map[string]string{"title": "b"}}},
+		Overrides: map[string]project.RowOverride{"2": {StartTime: "1:00"}},
+	}}
+	items := []tui.ReviewItem{
+		{Collection: "songs", Index: 1, Decision: tui.ReviewReject},
+		{Collection: "songs", Index: 2, Decision: tui.ReviewRetrim, NewStart: "0:10"},
+	}
+	if _, _, err := applyReviewDecisions(paths.ProjectPaths{Root: dir}, collections, items, ""); err == nil {
+		t.Fatal("expected error for a re-trim of an overridden start")
+	}
+	if _, err := os.Stat(plan); !os.IsNotExist(err) {
+		t.Error("no plan should be written when a decision is refused")
+	}
+}
@@ -2,20 +2,38 @@ package cli
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 
 	"github.com/spf13/cobra"
+
+	"powerhour/internal/logx"
 )
 
 var (
 	projectDir string
 	outputJSON bool
+	logVerbose bool
+	logQuiet   bool
+	logJSON    bool
 )
 
 // Execute runs the root cobra command.
+// Failures and panics are recorded for `powerhour debug bundle`.
 func Execute() {
+	args := os.Args[1:]
+	defer func() {
+		if r := recover(); r != nil {
+			reportCrash(args, r, os.Stderr)
+			os.Exit(2)
+		}
+	}()
 	if err := newRootCmd().Execute(); err != nil {
+		recordFailure(args, err, false, nil)
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		if hint := failureHint(); hint != "" {
+			fmt.Fprintln(os.Stderr, hint)
+		}
 		os.Exit(1)
 	}
 }
@@ -30,13 +48,28 @@ func newRootCmd() *cobra.Command {
 		Short:         "Power Hour generator CLI",
 		SilenceUsage:  true,
 		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		// A bare `powerhour` with no project nearby starts first-run
+		// onboarding; otherwise it prints help as before.
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if shouldOnboard() {
+				return runOnboarding(cmd)
+			}
+			return cmd.Help()
+		},
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return configureLogging(cmd)
+		},
 		PersistentPostRun: func(cmd *cobra.Command, args []string) {
 			printUpdateNotices(cmd)
 		},
 	}
 
-	cmd.PersistentFlags().StringVar(&projectDir, "project", "", "Path to project directory")
+	cmd.PersistentFlags().StringVarP(&projectDir, "project", "p", "", "Project directory or registered project name (default: nearest directory with powerhour.yaml)")
 	cmd.PersistentFlags().BoolVar(&outputJSON, "json", false, "Output machine-readable JSON")
+	cmd.PersistentFlags().BoolVarP(&logVerbose, "verbose", "v", false, "Log debug detail and echo log records to stderr")
+	cmd.PersistentFlags().BoolVarP(&logQuiet, "quiet", "q", false, "Log only warnings and errors, and skip update notices")
+	cmd.PersistentFlags().BoolVar(&logJSON, "log-json", false, "Write log files as JSON lines")
 
 	cmd.AddGroup(
 		&cobra.Group{ID: "workflow", Title: "Workflow:"},
@@ -53,19 +86,41 @@ func newRootCmd() *cobra.Command {
 
 	addTo("workflow",
 		newInitCmd(),
+		newImportCmd(),
 		newAddCmd(),
+		newPlanCmd(),
+		newNudgeCmd(),
+		newAnalyzeCmd(),
+		newPickCmd(),
 		newFetchCmd(),
 		newRenderCmd(),
+		newReviewCmd(),
 		newConcatCmd(),
+		newRunCmd(),
+		newSubtitlesCmd(),
+		newUploadCmd(),
+		newCastCmd(),
+		newStreamCmd(),
 		newTuiCmd(),
+		newServeCmd(),
+		newPartyCmd(),
 	)
 
 	addTo("inspect",
 		newStatusCmd(),
+		newWhichCmd(),
+		newLogsCmd(),
+		newStatsCmd(),
 		newSampleCmd(),
 		newValidateCmd(),
 		newDoctorCmd(),
+		newChecklistCmd(),
+		newLoudnessCmd(),
+		newTimingCmd(),
+		newSheetCmd(),
 		newCheckCmd(),
+		newDiffCmd(),
+		newStateCmd(),
 		newExportCmd(),
 		newConfigCmd(),
 	)
@@ -76,6 +131,8 @@ func newRootCmd() *cobra.Command {
 		newLibraryCmd(),
 		newCleanCmd(),
 		newToolsCmd(),
+		newProjectsCmd(),
+		newDebugCmd(),
 		convertCmd,
 	)
 	// convert operates on a standalone file path; project/json flags don't apply.
@@ -85,5 +142,25 @@ func newRootCmd() *cobra.Command {
 		}
 	}
 
+	registerCompletions(cmd)
+
 	return cmd
 }
+
+// configureLogging applies --verbose, --quiet and --log-json to the log
+// files every command writes.
+func configureLogging(cmd *cobra.Command) error {
+	if logVerbose && logQuiet {
+		return fmt.Errorf("--verbose and --quiet can't be used together")
+	}
+	opts := logx.Options{Level: slog.LevelInfo, JSON: logJSON}
+	switch {
+	case logVerbose:
+		opts.Level = slog.LevelDebug
+		opts.Console = cmd.ErrOrStderr()
+	case logQuiet:
+		opts.Level = slog.LevelWarn
+	}
+	logx.Configure(opts)
+	return nil
+}
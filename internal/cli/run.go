@@ -0,0 +1,402 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/render"
+	"powerhour/internal/tui"
+)
+
+var (
+	runConcurrency int
+	runOut         string
+	runNoProgress  bool
+)
+
+// pipelineProgress, while powerhour run has a stage going, hears how many
+// of the stage's rows or segments are done so the stage table can count.
+var pipelineProgress func(done, total int)
+
+func newRunCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Fetch, render and concat in one go",
+		Long: `Runs fetch, render and concat in order with one progress view, then
+prints what each stage did. A stage that fails stops the run, and the
+stages after it are skipped. Ctrl+C (or q in the progress view) cancels
+the running stage and skips the rest.
+
+Each stage works as its own command does, so finished downloads and
+renders are reused and an interrupted run picks up where it stopped.`,
+		Args: cobra.NoArgs,
+		RunE: withTelemetry("run", withNotify("run", runRun)),
+	}
+
+	defaultConcurrency := runtime.NumCPU()
+	if defaultConcurrency < 1 {
+		defaultConcurrency = 1
+	}
+
+	cmd.Flags().IntVar(&runConcurrency, "concurrency", defaultConcurrency, "Concurrent ffmpeg processes while rendering")
+	cmd.Flags().StringVar(&runOut, "out", "", "Output file path (default: <project>/powerhour.mp4)")
+	cmd.Flags().BoolVar(&runNoProgress, "no-progress", false, "Disable the interactive stage view")
+
+	return cmd
+}
+
+// pipelineStage is one step of powerhour run and how it went.
+type pipelineStage struct {
+	Name    string        `json:"name"`
+	Status  string        `json:"status"` // pending, <active>, complete, error, canceled or skipped
+	Summary string        `json:"summary,omitempty"`
+	Error   string        `json:"error,omitempty"`
+	Elapsed time.Duration `json:"-"`
+
+	active string // status while running, e.g. "fetching"
+	run    func(*cobra.Command, []string) error
+}
+
+type pipelineResult struct {
+	Stages  []*pipelineStage `json:"stages"`
+	Seconds float64          `json:"elapsed_seconds"`
+	Error   string           `json:"error,omitempty"`
+}
+
+func newPipelineStages() []*pipelineStage {
+	return []*pipelineStage{
+		{Name: "fetch", Status: "pending", active: "fetching", run: runFetch},
+		{Name: "render", Status: "pending", active: "rendering", run: runRender},
+		{Name: "concat", Status: "pending", active: "concatenating", run: runConcat},
+	}
+}
+
+func runRun(cmd *cobra.Command, _ []string) error {
+	glog, gcloser := logx.Command("run")
+	defer gcloser.Close()
+	glog.Info("run started")
+
+	// A missing project fails the run itself rather than the fetch stage.
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	cmd.SetContext(ctx)
+
+	defer setPipelineFlags()()
+
+	stages := newPipelineStages()
+	start := time.Now()
+	out := cmd.OutOrStdout()
+	errOut := cmd.ErrOrStderr()
+
+	var runErr error
+	switch tui.DetectMode(out, runNoProgress || logx.Echoing(), outputJSON) {
+	case tui.ModeTUI:
+		runErr = runPipelineTUI(ctx, cancel, cmd, pp.Root, stages, logx.Printf(glog))
+	case tui.ModeJSON:
+		// The stages' own JSON would run together with the run's.
+		cmd.SetOut(io.Discard)
+		runErr = runPipelineStages(ctx, cmd, stages, func(*pipelineStage, int, int) {})
+		cmd.SetOut(out)
+		notifySummary = pipelineSummaryLine(stages)
+		return writePipelineJSON(out, stages, time.Since(start), runErr)
+	default:
+		runErr = runPipelineStages(ctx, cmd, stages, func(st *pipelineStage, done, total int) {
+			if done == 0 && total == 0 && st.Status == st.active {
+				fmt.Fprintf(errOut, "==> %s\n", st.Name)
+			}
+		})
+	}
+
+	notifySummary = pipelineSummaryLine(stages)
+	printPipelineSummary(out, stages, time.Since(start))
+	if runErr != nil {
+		glog.Error("run failed", "error", runErr)
+		return runErr
+	}
+	glog.Info("run finished", "elapsed", time.Since(start).Round(time.Second))
+	return nil
+}
+
+// setPipelineFlags points the fetch, render and concat flags at run's for
+// the length of the run and returns a func restoring them. Stages print
+// plainly, since the run owns the progress view.
+func setPipelineFlags() func() {
+	savedFetchNoProgress := fetchNoProgress
+	savedRenderNoProgress := renderNoProgress
+	savedConcurrency := renderConcurrency
+	savedOut := concatOut
+	fetchNoProgress = true
+	renderNoProgress = true
+	renderConcurrency = runConcurrency
+	concatOut = runOut
+	return func() {
+		fetchNoProgress = savedFetchNoProgress
+		renderNoProgress = savedRenderNoProgress
+		renderConcurrency = savedConcurrency
+		concatOut = savedOut
+	}
+}
+
+// runPipelineStages runs the stages in order until one fails or ctx is
+// canceled, marking the rest skipped or canceled. update hears every
+// status change with zero counts, and each counted row or segment.
+func runPipelineStages(ctx context.Context, cmd *cobra.Command, stages []*pipelineStage, update func(st *pipelineStage, done, total int)) error {
+	defer func() { pipelineProgress = nil }()
+	for i, st := range stages {
+		if ctx.Err() != nil {
+			markPipelineStages(stages[i:], "canceled", update)
+			return fmt.Errorf("%s: %w", st.Name, ctx.Err())
+		}
+		st.Status = st.active
+		update(st, 0, 0)
+		pipelineProgress = func(done, total int) { update(st, done, total) }
+		notifySummary = ""
+		started := time.Now()
+		err := st.run(cmd, nil)
+		pipelineProgress = nil
+		st.Elapsed = time.Since(started)
+		st.Summary = notifySummary
+		if err != nil {
+			st.Status = "error"
+			if ctx.Err() != nil {
+				st.Status = "canceled"
+			}
+			st.Error = err.Error()
+			update(st, 0, 0)
+			markPipelineStages(stages[i+1:], "skipped", update)
+			return fmt.Errorf("%s: %w", st.Name, err)
+		}
+		st.Status = "complete"
+		update(st, 0, 0)
+	}
+	return nil
+}
+
+func markPipelineStages(stages []*pipelineStage, status string, update func(*pipelineStage, int, int)) {
+	for _, st := range stages {
+		st.Status = status
+		update(st, 0, 0)
+	}
+}
+
+// reportPipelineProgress passes a stage's counts to powerhour run, if it
+// is the one running the stage.
+func reportPipelineProgress(done, total int) {
+	if pipelineProgress != nil {
+		pipelineProgress(done, total)
+	}
+}
+
+// pipelineRenderReporter counts finished segments for powerhour run. It is
+// nil outside a run, so render reports nothing extra.
+type pipelineRenderReporter struct {
+	mu    sync.Mutex
+	done  int
+	total int
+}
+
+func newPipelineRenderReporter(total int) render.ProgressReporter {
+	if pipelineProgress == nil {
+		return nil
+	}
+	reportPipelineProgress(0, total)
+	return &pipelineRenderReporter{total: total}
+}
+
+func (r *pipelineRenderReporter) Start(render.Segment) {}
+
+func (r *pipelineRenderReporter) Progress(render.Segment, float64) {}
+
+func (r *pipelineRenderReporter) Complete(render.Result) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.done++
+	reportPipelineProgress(r.done, r.total)
+}
+
+var pipelineColumns = []tui.Column{
+	{Header: "STAGE", Width: 8},
+	{Header: "STATUS", Width: 14},
+	{Header: "PROGRESS", Width: 10},
+	{Header: "ELAPSED", Width: 8},
+}
+
+// runPipelineTUI runs the stages under one stage table. Stage output is
+// held back while the table owns the terminal: stdout is dropped, since the
+// summary covers it, and stderr lines (warnings, failed rows, ffmpeg) go to
+// the run log, and to the terminal too when the run fails.
+func runPipelineTUI(ctx context.Context, cancel context.CancelFunc, cmd *cobra.Command, root string, stages []*pipelineStage, logf func(string, ...any)) error {
+	out := cmd.OutOrStdout()
+	errOut := cmd.ErrOrStderr()
+	held := &stageLog{}
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(held)
+	defer func() {
+		cmd.SetOut(out)
+		cmd.SetErr(errOut)
+	}()
+
+	model := tui.NewProgressModel("powerhour run", pipelineColumns)
+	for _, st := range stages {
+		model.AddRow(st.Name, []string{st.Name, st.Status, "", ""})
+	}
+
+	fmt.Fprintf(out, "Project: %s\n", root)
+	var runErr error
+	workDone := make(chan struct{})
+	tuiErr := tui.RunWithWork(out, model, logf, func(send func(tea.Msg)) {
+		defer close(workDone)
+		runErr = runPipelineStages(ctx, cmd, stages, func(st *pipelineStage, done, total int) {
+			send(tui.RowUpdateMsg{Key: st.Name, Fields: pipelineStageFields(st, done, total)})
+		})
+	})
+	// Quitting the table early cancels the running stage; wait for it to
+	// wind down before printing what happened.
+	cancel()
+	<-workDone
+
+	lines := held.Lines()
+	for _, line := range lines {
+		logf("stage output: %s", line)
+	}
+	if runErr != nil {
+		for _, line := range lines {
+			fmt.Fprintln(errOut, line)
+		}
+		return runErr
+	}
+	return tuiErr
+}
+
+// pipelineStageFields returns the table fields for a stage update. Zero
+// counts leave PROGRESS as it was.
+func pipelineStageFields(st *pipelineStage, done, total int) map[string]string {
+	fields := map[string]string{"STATUS": st.Status}
+	if total > 0 {
+		fields["PROGRESS"] = fmt.Sprintf("%d/%d", done, total)
+	}
+	if st.Elapsed > 0 {
+		fields["ELAPSED"] = formatStageElapsed(st.Elapsed)
+	}
+	return fields
+}
+
+func formatStageElapsed(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	}
+	return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+func printPipelineSummary(w io.Writer, stages []*pipelineStage, elapsed time.Duration) {
+	fmt.Fprintln(w)
+	for _, st := range stages {
+		line := st.Status
+		if st.Summary != "" {
+			line = st.Summary
+		}
+		if st.Elapsed > 0 {
+			line += fmt.Sprintf(" (%s)", formatStageElapsed(st.Elapsed))
+		}
+		fmt.Fprintf(w, "%-7s %s\n", strings.ToUpper(st.Name[:1])+st.Name[1:]+":", line)
+	}
+	fmt.Fprintf(w, "Total:  %s\n", formatStageElapsed(elapsed))
+}
+
+// pipelineSummaryLine is the run's notification summary: the last stage
+// that did anything, which for a finished run is concat's output file.
+func pipelineSummaryLine(stages []*pipelineStage) string {
+	for i := len(stages) - 1; i >= 0; i-- {
+		if stages[i].Summary != "" {
+			return stages[i].Summary
+		}
+	}
+	return ""
+}
+
+func writePipelineJSON(w io.Writer, stages []*pipelineStage, elapsed time.Duration, runErr error) error {
+	result := pipelineResult{Stages: stages, Seconds: elapsed.Seconds()}
+	if runErr != nil {
+		result.Error = runErr.Error()
+	}
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode json: %w", err)
+	}
+	fmt.Fprintln(w, string(data))
+	return runErr
+}
+
+// stageLog collects the lines stages write to stderr while the stage table
+// is up. Text a carriage return writes over, such as status spinner frames
+// and ffmpeg's progress line, is dropped as a terminal would.
+type stageLog struct {
+	mu      sync.Mutex
+	partial []byte
+	lines   []string
+}
+
+func (l *stageLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.partial = append(l.partial, p...)
+	for {
+		i := bytes.IndexByte(l.partial, '\n')
+		if i < 0 {
+			break
+		}
+		line := string(l.partial[:i])
+		l.partial = l.partial[i+1:]
+		line = lastRedraw(line)
+		if strings.TrimSpace(line) != "" {
+			l.lines = append(l.lines, line)
+		}
+	}
+	// Keep a trailing \r, which may be the start of a \r\n.
+	if j := bytes.LastIndexByte(l.partial, '\r'); j >= 0 && j < len(l.partial)-1 {
+		l.partial = append([]byte(nil), l.partial[j:]...)
+	}
+	return len(p), nil
+}
+
+// lastRedraw returns what is left of line after its last carriage return,
+// without the erase-line sequence tui.StatusWriter writes after it.
+func lastRedraw(line string) string {
+	line = strings.TrimSuffix(line, "\r")
+	if j := strings.LastIndexByte(line, '\r'); j >= 0 {
+		line = strings.TrimPrefix(line[j+1:], "\033[K")
+	}
+	return line
+}
+
+// Lines returns the complete lines written so far.
+func (l *stageLog) Lines() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.lines...)
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestRunPipelineStagesStopsAtFailure(t *testing.T) {
+	var ran []string
+	stage := func(name, summary string, err error) *pipelineStage {
+		return &pipelineStage{Name: name, Status: "pending", active: name + "ing", run: func(*cobra.Command, []string) error {
+			ran = append(ran, name)
+			notifySummary = summary
+			reportPipelineProgress(1, 2)
+			return err
+		}}
+	}
+	stages := []*pipelineStage{
+		stage("fetch", "Downloaded: 2", nil),
+		stage("render", "Rendered: 0, Skipped: 0, Failed: 1", errors.New("1 segment failed")),
+		stage("concat", "", nil),
+	}
+
+	var updates []string
+	err := runPipelineStages(context.Background(), &cobra.Command{}, stages, func(st *pipelineStage, done, total int) {
+		fields := pipelineStageFields(st, done, total)
+		updates = append(updates, st.Name+" "+fields["STATUS"]+" "+fields["PROGRESS"])
+	})
+	if err == nil || err.Error() != "render: 1 segment failed" {
+		t.Fatalf("err = %v, want the render failure", err)
+	}
+	if !slices.Equal(ran, []string{"fetch", "render"}) {
+		t.Errorf("ran %v, want fetch and render only", ran)
+	}
+	var statuses []string
+	for _, st := range stages {
+		statuses = append(statuses, st.Status)
+	}
+	if !slices.Equal(statuses, []string{"complete", "error", "skipped"}) {
+		t.Errorf("statuses = %v", statuses)
+	}
+	if stages[0].Summary != "Downloaded: 2" || stages[1].Error != "1 segment failed" {
+		t.Errorf("stage results = %+v, %+v", stages[0], stages[1])
+	}
+	if !slices.Contains(updates, "fetch fetching 1/2") || !slices.Contains(updates, "concat skipped ") {
+		t.Errorf("updates = %q", updates)
+	}
+	if pipelineProgress != nil {
+		t.Error("pipelineProgress left set after the run")
+	}
+	if got := pipelineSummaryLine(stages); got != "Rendered: 0, Skipped: 0, Failed: 1" {
+		t.Errorf("pipelineSummaryLine = %q", got)
+	}
+}
+
+func TestRunPipelineStagesCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stages := newPipelineStages()
+	stages[0].run = func(*cobra.Command, []string) error {
+		cancel()
+		return ctx.Err()
+	}
+	err := runPipelineStages(ctx, &cobra.Command{}, stages, func(*pipelineStage, int, int) {})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if stages[0].Status != "canceled" || stages[1].Status != "skipped" || stages[2].Status != "skipped" {
+		t.Errorf("statuses = %s, %s, %s", stages[0].Status, stages[1].Status, stages[2].Status)
+	}
+}
+
+func TestStageLogDropsRedrawnText(t *testing.T) {
+	var l stageLog
+	writes := []string{
+		"\r\033[K⠋ Loading cache index... (12ms)",
+		"\r\033[K⠙ Checking tools... (1.2s)",
+		"\r\033[K",
+		"fetch collection=songs row 003 failed: ",
+		"HTTP 403\nframe=  10 fps=0.0\rframe=  20 fps=0.0\r\033[K",
+		"\nwarning: checkpoint render state: disk full\r",
+		"\n",
+	}
+	for _, w := range writes {
+		if _, err := l.Write([]byte(w)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []string{
+		"fetch collection=songs row 003 failed: HTTP 403",
+		"warning: checkpoint render state: disk full",
+	}
+	if got := l.Lines(); !slices.Equal(got, want) {
+		t.Fatalf("Lines() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
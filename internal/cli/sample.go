@@ -3,6 +3,7 @@ package cli
 import (
 	"context"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"strings"
@@ -23,6 +24,9 @@ var (
 	sampleOutput     string
 )
 
+// sampleOverlayNames are the overlay moments sample accepts instead of a time.
+var sampleOverlayNames = []string{"title", "artist", "credit", "number", "drink"}
+
 func newSampleCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "sample <time|overlay-name>",
@@ -36,8 +40,9 @@ the midpoint of that overlay's visible window.
 Without --index, a timestamp is treated as an absolute position in the
 concatenated timeline. With --index, the time is relative to that clip.
 Add --collection to narrow --index to a specific collection's rows.`,
-		Args: cobra.ExactArgs(1),
-		RunE: runSample,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: cobra.FixedCompletions(sampleOverlayNames, cobra.ShellCompDirectiveNoFileComp),
+		RunE:              runSample,
 	}
 
 	cmd.Flags().IntVar(&sampleIndex, "index", 0, "Target a specific clip (timeline slot, or collection row if --collection is set)")
@@ -193,7 +198,7 @@ func runSample(cmd *cobra.Command, args []string) error {
 	}
 
 	// Build the render segment for the target clip.
-	seg, err := buildCollectionRenderSegment(pp, cfg, idx, resolver, targetClip)
+	seg, err := render.BuildCollectionSegment(pp, cfg, idx, targetClip)
 	if err != nil {
 		return fmt.Errorf("build segment: %w", err)
 	}
@@ -225,14 +230,20 @@ func runSample(cmd *cobra.Command, args []string) error {
 func findClipAtTime(timeline []render.TimelineClip, absoluteTime float64) (render.TimelineClip, float64, error) {
 	var cumulative float64
 	for _, tc := range timeline {
-		duration := float64(tc.CollectionClip.Clip.DurationSeconds)
+		clip := tc.CollectionClip.Clip
+		duration := float64(clip.DurationSeconds)
 		if duration <= 0 {
 			duration = 60 // fallback
 		}
-		if absoluteTime < cumulative+duration {
-			return tc, absoluteTime - cumulative, nil
+		// Segments carry their preroll/postroll, so the timeline advances by
+		// the padded length; times inside the padding sample the nearest clip edge.
+		span := duration + clip.PrerollSeconds + clip.PostrollSeconds
+		if absoluteTime < cumulative+span {
+			offset := absoluteTime - cumulative - clip.PrerollSeconds
+			offset = math.Max(0, math.Min(offset, math.Nextafter(duration, 0)))
+			return tc, offset, nil
 		}
-		cumulative += duration
+		cumulative += span
 	}
 	return render.TimelineClip{}, 0, fmt.Errorf("time %s exceeds total timeline duration %s",
 		formatSampleTime(absoluteTime), formatSampleTime(cumulative))
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	xterm "github.com/charmbracelet/x/term"
+
+	"powerhour/internal/render/state"
+)
+
+const (
+	// scanIndicatorDelay keeps quick scans from flashing a line.
+	scanIndicatorDelay = 250 * time.Millisecond
+	// scanIndicatorEvery throttles redraws of the line.
+	scanIndicatorEvery = 100 * time.Millisecond
+)
+
+// newScanIndicator returns a state.ScanProgress that keeps a
+// "scanning segments n/total" line on w while change detection runs, and a
+// stop func that clears it. Nothing is drawn when w isn't a terminal, under
+// --json, or when the scan finishes within scanIndicatorDelay.
+func newScanIndicator(w io.Writer) (state.ScanProgress, func()) {
+	f, ok := w.(*os.File)
+	if !ok || outputJSON || !xterm.IsTerminal(f.Fd()) {
+		return nil, func() {}
+	}
+	var (
+		started = time.Now()
+		drawn   time.Time
+	)
+	progress := func(done, total int) {
+		now := time.Now()
+		if now.Sub(started) < scanIndicatorDelay || (now.Sub(drawn) < scanIndicatorEvery && done < total) {
+			return
+		}
+		drawn = now
+		fmt.Fprintf(w, "\rscanning segments %d/%d", done, total)
+	}
+	stop := func() {
+		if !drawn.IsZero() {
+			fmt.Fprint(w, "\r\033[K")
+		}
+	}
+	return progress, stop
+}
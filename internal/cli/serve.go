@@ -0,0 +1,406 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/config"
+	"powerhour/internal/diagnostics"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+	"powerhour/internal/render/state"
+	"powerhour/internal/webui"
+	"powerhour/pkg/csvplan"
+)
+
+var (
+	serveAddr    string
+	serveToken   string
+	serveAPIOnly bool
+)
+
+// serveJobCommands are the commands the dashboard may run.
+var serveJobCommands = []string{"fetch", "render", "concat"}
+
+func newServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve a web dashboard for the project",
+		Long: `Serve a browser dashboard for the project: every plan row with its
+cache and render state, inline edits of start times and skips, and fetch,
+render and concat jobs whose output streams live to every open page.
+
+The server listens on localhost by default. Every request needs an access
+token: a random one is made for each run unless --token sets it, and the
+printed URL carries it. To curate together over a network, listen on
+another address and share that URL. Edits and jobs go through the same
+code as the CLI, so only one job runs at a time.
+
+The page is built on a JSON API that scripts can call directly; the
+OpenAPI spec is served at /api/openapi.json. --api-only serves just the
+API.`,
+		Example: `  powerhour serve
+  powerhour serve --addr 0.0.0.0:8787 --token party-2026
+  powerhour serve --api-only --token $POWERHOUR_TOKEN`,
+		Args: cobra.NoArgs,
+		RunE: runServe,
+	}
+	cmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:8787", "Address to listen on")
+	cmd.Flags().StringVar(&serveToken, "token", "", "Access token to require (default: a random token per run)")
+	cmd.Flags().BoolVar(&serveAPIOnly, "api-only", false, "Serve only the JSON API, without the dashboard")
+	return cmd
+}
+
+type serveRow struct {
+	Index           int    `json:"index"`
+	Title           string `json:"title"`
+	Artist          string `json:"artist,omitempty"`
+	Link            string `json:"link,omitempty"`
+	Start           string `json:"start"`
+	StartOverridden bool   `json:"start_overridden,omitempty"`
+	Duration        int    `json:"duration,omitempty"`
+	Skipped         bool   `json:"skipped,omitempty"`
+	Cache           string `json:"cache"`
+	Render          string `json:"render"`
+}
+
+type serveCollection struct {
+	Name     string     `json:"name"`
+	Plan     string     `json:"plan,omitempty"`
+	Editable bool       `json:"editable"`
+	Rows     []serveRow `json:"rows"`
+}
+
+type serveStatus struct {
+	Name        string            `json:"name"`
+	Root        string            `json:"root"`
+	Collections []serveCollection `json:"collections"`
+}
+
+type serveConfig struct {
+	File   string         `json:"file"`
+	Config map[string]any `json:"config"`
+}
+
+type serveTimelineEntry struct {
+	Slot       int    `json:"slot"`
+	Collection string `json:"collection,omitempty"`
+	Index      int    `json:"index,omitempty"`
+	Title      string `json:"title,omitempty"`
+	Artist     string `json:"artist,omitempty"`
+	Start      string `json:"start,omitempty"`
+	Segment    string `json:"segment"`
+	Rendered   bool   `json:"rendered"`
+}
+
+func runServe(cmd *cobra.Command, _ []string) error {
+	glogf, gcloser := logx.StartCommand("serve")
+	defer gcloser.Close()
+
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	if _, err := loadServeProject(pp); err != nil {
+		return err
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("locate powerhour: %w", err)
+	}
+
+	token := serveToken
+	if token == "" {
+		if token, err = newServeToken(); err != nil {
+			return err
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	srv := webui.New(ctx, webui.Options{
+		Token:      token,
+		Hosts:      addrHosts(serveAddr),
+		Status:     func() (any, error) { return serveProjectStatus(pp) },
+		Config:     func() (any, error) { return serveProjectConfig(pp) },
+		Timeline:   func() (any, error) { return serveProjectTimeline(pp) },
+		Row:        func(coll string, index int) (any, error) { return serveProjectRow(pp, coll, index) },
+		UpdateRow:  func(u webui.RowUpdate) error { return serveUpdateRow(pp, u) },
+		JobArgs:    func(req webui.JobRequest) ([]string, error) { return serveJobArgs(pp.Root, req) },
+		Executable: self,
+		Logf:       glogf,
+		APIOnly:    serveAPIOnly,
+	})
+	defer srv.Close()
+
+	ln, err := net.Listen("tcp", serveAddr)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", serveAddr, err)
+	}
+	httpServer := &http.Server{Handler: srv.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	glogf("serve started: addr=%s root=%s token_set=%t api_only=%t", ln.Addr(), pp.Root, serveToken != "", serveAPIOnly)
+
+	url := "http://" + ln.Addr().String() + "/"
+	if serveAPIOnly {
+		cmd.Printf("Serving the %s API at %sapi (spec: %sapi/openapi.json)\n", filepath.Base(pp.Root), url, url)
+		cmd.Printf("Token: %s\n", token)
+	} else {
+		cmd.Printf("Serving %s at %s?token=%s\n", filepath.Base(pp.Root), url, token)
+	}
+	cmd.Println("Press Ctrl+C to stop")
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- httpServer.Serve(ln) }()
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("serve: %w", err)
+		}
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	glogf("serve stopping")
+	return httpServer.Shutdown(shutdownCtx)
+}
+
+// addrHosts returns the host name given in a listen address, which is how
+// others will reach the server; IP addresses need no entry.
+func addrHosts(addr string) []string {
+	if host, _, err := net.SplitHostPort(addr); err == nil && host != "" && net.ParseIP(host) == nil {
+		return []string{host}
+	}
+	return nil
+}
+
+// newServeToken returns a random access token for one serve or party run.
+func newServeToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate access token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+type serveProject struct {
+	pp          paths.ProjectPaths
+	cfg         config.Config
+	collections map[string]project.Collection
+}
+
+// loadServeProject reloads config and plans so every request sees edits
+// made from the CLI or another page.
+func loadServeProject(pp paths.ProjectPaths) (serveProject, error) {
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return serveProject{}, err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
+	if len(cfg.Collections) == 0 {
+		return serveProject{}, fmt.Errorf("no collections configured")
+	}
+	resolver, err := project.NewCollectionResolver(cfg, pp)
+	if err != nil {
+		return serveProject{}, err
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		return serveProject{}, err
+	}
+	return serveProject{pp: pp, cfg: cfg, collections: collections}, nil
+}
+
+func serveProjectStatus(pp paths.ProjectPaths) (serveStatus, error) {
+	p, err := loadServeProject(pp)
+	if err != nil {
+		return serveStatus{}, err
+	}
+	idx, _ := cache.Load(p.pp)
+	rs, _ := state.Load(p.pp.RenderStateFile)
+	statuses, _ := buildRowStatuses(p.pp, p.cfg, idx, rs, p.collections, p.cfg.SegmentFilenameTemplate())
+	byRow := make(map[string]rowStatus, len(statuses))
+	for _, st := range statuses {
+		byRow[whichKey(st.Collection, st.Index)] = st
+	}
+
+	out := serveStatus{Name: filepath.Base(p.pp.Root), Root: p.pp.Root}
+	effective := project.WithRowOverrides(p.collections)
+	for _, name := range sortedKeys(p.collections) {
+		coll := p.collections[name]
+		sc := serveCollection{Name: name, Editable: coll.Plan != "", Rows: make([]serveRow, 0, len(coll.Rows))}
+		if coll.Plan != "" {
+			sc.Plan = relPath(p.pp.Root, coll.Plan)
+		}
+		for i, row := range effective[name].Rows {
+			st := byRow[whichKey(name, row.Index)]
+			r := serveRow{
+				Index:    row.Index,
+				Title:    firstNonEmpty(row.CustomFields["title"], row.CustomFields["name"]),
+				Artist:   row.CustomFields["artist"],
+				Link:     row.Link,
+				Start:    row.StartRaw,
+				Duration: row.DurationSeconds,
+				Skipped:  st.Skipped,
+				Cache:    st.CacheStatus,
+				Render:   st.RenderStatus,
+			}
+			if ov, ok := coll.RowOverrideFor(coll.Rows[i]); ok && ov.StartTime != "" {
+				r.StartOverridden = true
+			}
+			sc.Rows = append(sc.Rows, r)
+		}
+		out.Collections = append(out.Collections, sc)
+	}
+	return out, nil
+}
+
+// serveProjectRow looks up one row in the status snapshot.
+func serveProjectRow(pp paths.ProjectPaths, collection string, index int) (serveRow, error) {
+	status, err := serveProjectStatus(pp)
+	if err != nil {
+		return serveRow{}, err
+	}
+	for _, coll := range status.Collections {
+		if coll.Name != collection {
+			continue
+		}
+		for _, row := range coll.Rows {
+			if row.Index == index {
+				return row, nil
+			}
+		}
+		return serveRow{}, fmt.Errorf("collection %q has no row %d: %w", collection, index, webui.ErrNotFound)
+	}
+	return serveRow{}, fmt.Errorf("collection %q: %w", collection, webui.ErrNotFound)
+}
+
+// serveProjectConfig returns the config as plain JSON values, keyed the
+// way powerhour.yaml is, with credentials redacted as in debug bundles.
+func serveProjectConfig(pp paths.ProjectPaths) (serveConfig, error) {
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return serveConfig{}, err
+	}
+	data, err := cfg.Marshal()
+	if err != nil {
+		return serveConfig{}, err
+	}
+	if data, err = diagnostics.RedactConfig(data); err != nil {
+		return serveConfig{}, err
+	}
+	out := serveConfig{File: pp.ConfigFile}
+	if err := yaml.Unmarshal(data, &out.Config); err != nil {
+		return serveConfig{}, fmt.Errorf("decode config: %w", err)
+	}
+	return out, nil
+}
+
+// serveProjectTimeline lists the timeline slots in playback order.
+func serveProjectTimeline(pp paths.ProjectPaths) ([]serveTimelineEntry, error) {
+	p, err := loadServeProject(pp)
+	if err != nil {
+		return nil, err
+	}
+	segments, err := render.ResolveTimelineSegments(p.pp, p.cfg, p.collections)
+	if err != nil {
+		return nil, fmt.Errorf("resolve timeline: %w", err)
+	}
+	effective := project.WithRowOverrides(p.collections)
+	entries := make([]serveTimelineEntry, 0, len(segments))
+	for i, seg := range segments {
+		e := serveTimelineEntry{Slot: i + 1, Collection: seg.CollectionName, Index: seg.Index, Segment: relPath(p.pp.Root, seg.Path)}
+		if _, err := os.Stat(seg.Path); err == nil {
+			e.Rendered = true
+		}
+		for _, row := range effective[seg.CollectionName].Rows {
+			if row.Index == seg.Index {
+				e.Title = firstNonEmpty(row.CustomFields["title"], row.CustomFields["name"])
+				e.Artist = row.CustomFields["artist"]
+				e.Start = row.StartRaw
+				break
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// serveUpdateRow applies a dashboard edit to the plan file.
+func serveUpdateRow(pp paths.ProjectPaths, u webui.RowUpdate) error {
+	p, err := loadServeProject(pp)
+	if err != nil {
+		return err
+	}
+	coll, ok := p.collections[u.Collection]
+	if !ok {
+		return fmt.Errorf("collection %q: %w", u.Collection, webui.ErrNotFound)
+	}
+	if coll.Plan == "" {
+		return fmt.Errorf("collection %q has no plan file", u.Collection)
+	}
+	if !slices.ContainsFunc(coll.Rows, func(r csvplan.CollectionRow) bool { return r.Index == u.Index }) {
+		return fmt.Errorf("collection %q has no row %d: %w", u.Collection, u.Index, webui.ErrNotFound)
+	}
+	if u.Skip != nil {
+		if coll, _, err = setCollectionRowsSkipped(coll, []int{u.Index}, *u.Skip); err != nil {
+			return err
+		}
+	}
+	if u.StartTime != nil {
+		if _, err := csvplan.ParseStartTime(*u.StartTime); err != nil {
+			return fmt.Errorf("row %d: %w", u.Index, err)
+		}
+		if coll, err = retrimCollectionRows(coll, map[int]string{u.Index: *u.StartTime}); err != nil {
+			return err
+		}
+	}
+	return project.WriteCollectionPlan(coll)
+}
+
+// serveJobArgs turns a dashboard job request into powerhour arguments.
+func serveJobArgs(root string, req webui.JobRequest) ([]string, error) {
+	if !slices.Contains(serveJobCommands, req.Command) {
+		return nil, fmt.Errorf("unsupported job %q (choose from fetch, render, concat)", req.Command)
+	}
+	args := []string{req.Command, "--project", root}
+	if req.Command == "concat" {
+		if req.Collection != "" || len(req.Indexes) > 0 {
+			return nil, fmt.Errorf("concat runs on the whole timeline")
+		}
+		return args, nil
+	}
+	args = append(args, "--no-progress")
+	if req.Collection != "" {
+		args = append(args, "--collection", req.Collection)
+	}
+	if len(req.Indexes) > 0 {
+		if req.Collection == "" {
+			return nil, fmt.Errorf("row indexes need a collection")
+		}
+		for _, i := range req.Indexes {
+			if i < 1 {
+				return nil, fmt.Errorf("invalid row index %d", i)
+			}
+		}
+		args = append(args, "--index", joinIndexes(req.Indexes))
+	}
+	return args, nil
+}
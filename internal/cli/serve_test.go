@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"powerhour/internal/paths"
+	"powerhour/internal/secrets"
+	"powerhour/internal/webui"
+)
+
+func TestServeJobArgs(t *testing.T) {
+	tests := []struct {
+		req     webui.JobRequest
+		want    []string
+		wantErr bool
+	}{
+		{req: webui.JobRequest{Command: "fetch"}, want: []string{"fetch", "--project", "/p", "--no-progress"}},
+		{
+			req:  webui.JobRequest{Command: "render", Collection: "songs", Indexes: []int{4, 2}},
+			want: []string{"render", "--project", "/p", "--no-progress", "--collection", "songs", "--index", "2,4"},
+		},
+		{req: webui.JobRequest{Command: "concat"}, want: []string{"concat", "--project", "/p"}},
+		{req: webui.JobRequest{Command: "concat", Collection: "songs"}, wantErr: true},
+		{req: webui.JobRequest{Command: "render", Indexes: []int{1}}, wantErr: true},
+		{req: webui.JobRequest{Command: "render", Collection: "songs", Indexes: []int{0}}, wantErr: true},
+		{req: webui.JobRequest{Command: "clean"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := serveJobArgs("/p", tt.req)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("serveJobArgs(%+v) = %v, want error", tt.req, got)
+			}
+			continue
+		}
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("serveJobArgs(%+v) = %v, %v; want %v", tt.req, got, err, tt.want)
+		}
+	}
+}
+
+func TestNewServeToken(t *testing.T) {
+	a, err := newServeToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := newServeToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a) != 32 || a == b {
+		t.Fatalf("newServeToken() = %q, %q; want two distinct 32-char tokens", a, b)
+	}
+}
+
+func TestServeProjectConfigRedactsCredentials(t *testing.T) {
+	pp, _ := paths.Resolve(t.TempDir())
+	yaml := "upload:\n  s3:\n    bucket: parties\n    access_key_id: AKIAEXAMPLE\n    secret_access_key: hunter2\n"
+	if err := os.WriteFile(pp.ConfigFile, []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := serveProjectConfig(pp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s3 := got.Config["upload"].(map[string]any)["s3"].(map[string]any)
+	if s3["bucket"] != "parties" {
+		t.Errorf("bucket = %v, want it kept", s3["bucket"])
+	}
+	for _, key := range []string{"access_key_id", "secret_access_key"} {
+		if v, _ := s3[key].(string); !strings.Contains(v, secrets.Placeholder) {
+			t.Errorf("%s = %q, want it redacted", key, v)
+		}
+	}
+}
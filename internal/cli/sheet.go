@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/config"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+)
+
+var (
+	sheetTimeline string
+	sheetColumns  int
+	sheetWidth    int
+	sheetOutput   string
+)
+
+func newSheetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sheet",
+		Short: "Build a contact sheet image of every rendered segment",
+		Long: `Grab a frame from the middle of each rendered segment, in timeline order,
+and lay them out as a captioned grid in a single PNG so the whole hour can
+be checked at a glance.
+
+Segments that haven't been rendered yet keep their slot as a dark
+placeholder tile.`,
+		Args: cobra.NoArgs,
+		RunE: runSheet,
+	}
+	cmd.Flags().StringVar(&sheetTimeline, "timeline", "", "Use a named timeline from timelines: (its own segments directory)")
+	cmd.Flags().IntVar(&sheetColumns, "columns", 6, "Tiles per row")
+	cmd.Flags().IntVar(&sheetWidth, "width", 320, "Tile width in pixels (height follows the video aspect ratio)")
+	cmd.Flags().StringVarP(&sheetOutput, "output", "o", "", "Output PNG (default <project>/contact-sheet.png)")
+	return cmd
+}
+
+func runSheet(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if sheetColumns < 1 {
+		return fmt.Errorf("--columns must be at least 1")
+	}
+	if sheetWidth < 64 {
+		return fmt.Errorf("--width must be at least 64")
+	}
+
+	glogf, gcloser := logx.StartCommand("sheet")
+	defer gcloser.Close()
+	glogf("sheet started: timeline=%s columns=%d width=%d", sheetTimeline, sheetColumns, sheetWidth)
+
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
+	cfg, pp, err = applyNamedTimeline(cfg, pp, sheetTimeline)
+	if err != nil {
+		return err
+	}
+	if len(cfg.Collections) == 0 {
+		return fmt.Errorf("no collections configured")
+	}
+
+	resolver, err := project.NewCollectionResolver(cfg, pp)
+	if err != nil {
+		return err
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		return err
+	}
+	segments, err := render.ResolveTimelineSegments(pp, cfg, collections)
+	if err != nil {
+		return fmt.Errorf("resolve timeline: %w", err)
+	}
+	if len(segments) == 0 {
+		return fmt.Errorf("no segments found; run `powerhour render` first")
+	}
+
+	opts := render.ContactSheetOptions{
+		Columns:     sheetColumns,
+		TileWidth:   sheetWidth,
+		TileHeight:  sheetTileHeight(cfg, sheetWidth),
+		CaptionSize: max(sheetWidth/18, 12),
+		OutputPath:  sheetOutput,
+	}
+	if opts.OutputPath == "" {
+		name := "contact-sheet"
+		if sheetTimeline != "" {
+			name += "-" + sheetTimeline
+		}
+		opts.OutputPath = name + ".png"
+	}
+	if !filepath.IsAbs(opts.OutputPath) {
+		opts.OutputPath = filepath.Join(pp.Root, opts.OutputPath)
+	}
+
+	tiles, missing := buildSheetTiles(ctx, findFFprobe(), segments, collections, opts)
+	if missing == len(tiles) {
+		return fmt.Errorf("none of the %d timeline segments are rendered; run `powerhour render` first", len(tiles))
+	}
+	glogf("sheet tiles: %d (%d not rendered)", len(tiles), missing)
+
+	svc, err := render.NewService(ctx, pp, cfg, nil)
+	if err != nil {
+		return err
+	}
+	if err := svc.RenderContactSheet(ctx, tiles, opts); err != nil {
+		return err
+	}
+	glogf("sheet written: %s", opts.OutputPath)
+
+	if outputJSON {
+		data, err := json.MarshalIndent(map[string]any{
+			"output":       opts.OutputPath,
+			"tiles":        len(tiles),
+			"not_rendered": missing,
+			"columns":      min(opts.Columns, len(tiles)),
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	cmd.Printf("Contact sheet: %s (%d tiles", relPath(pp.Root, opts.OutputPath), len(tiles))
+	if missing > 0 {
+		cmd.Printf(", %d not rendered", missing)
+	}
+	cmd.Println(")")
+	return nil
+}
+
+// sheetTileHeight keeps tiles at the project's output aspect ratio, rounded
+// to an even height.
+func sheetTileHeight(cfg config.Config, width int) int {
+	if cfg.Video.Width <= 0 || cfg.Video.Height <= 0 {
+		return width * 9 / 16 &^ 1
+	}
+	return width * cfg.Video.Height / cfg.Video.Width &^ 1
+}
+
+// buildSheetTiles turns timeline segments into contact sheet tiles captioned
+// with their timeline position and title. Each frame is taken from the
+// middle of the segment, using ffprobe's duration when available and the
+// plan duration otherwise. It also returns how many segments are missing.
+func buildSheetTiles(ctx context.Context, ffprobe string, segments []render.TimelineSegmentPath, collections map[string]project.Collection, opts render.ContactSheetOptions) ([]render.ContactSheetTile, int) {
+	tiles := make([]render.ContactSheetTile, 0, len(segments))
+	missing := 0
+	for i, seg := range segments {
+		label := strings.TrimSuffix(filepath.Base(seg.Path), filepath.Ext(seg.Path))
+		planSeconds := 0.0
+		if coll, ok := collections[seg.CollectionName]; ok {
+			for _, collRow := range coll.Rows {
+				if collRow.Index != seg.Index {
+					continue
+				}
+				row := collRow.ToRow()
+				label = firstNonEmpty(row.Title, row.Name, label)
+				if strings.TrimSpace(row.Artist) != "" {
+					label += " - " + row.Artist
+				}
+				planSeconds = float64(row.DurationSeconds)
+				break
+			}
+		}
+
+		tile := render.ContactSheetTile{
+			Caption: render.ContactSheetCaption(fmt.Sprintf("%d. %s", i+1, label), opts.TileWidth, opts.CaptionSize),
+		}
+		if _, err := os.Stat(seg.Path); err != nil {
+			tile.Caption = render.ContactSheetCaption(fmt.Sprintf("%d. not rendered", i+1), opts.TileWidth, opts.CaptionSize)
+			missing++
+		} else {
+			tile.Path = seg.Path
+			seconds := planSeconds
+			if ffprobe != "" {
+				if media, err := probeMedia(ctx, ffprobe, seg.Path); err == nil && media.DurationSeconds > 0 {
+					seconds = media.DurationSeconds
+				}
+			}
+			tile.At = seconds / 2
+		}
+		tiles = append(tiles, tile)
+	}
+	return tiles, missing
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"powerhour/internal/config"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+	"powerhour/pkg/csvplan"
+)
+
+func TestBuildSheetTiles(t *testing.T) {
+	dir := t.TempDir()
+	rendered := filepath.Join(dir, "001_song.mp4")
+	if err := os.WriteFile(rendered, []byte("video"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	inline := filepath.Join(dir, "intro.mp4")
+	if err := os.WriteFile(inline, []byte("video"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	collections := map[string]project.Collection{
+		"songs": {Name: "songs", Rows: []csvplan.CollectionRow{
+			{Index: 1, DurationSeconds: 60, CustomFields: map[string]string{"title": "Song", "artist": "Band"}},
+			{Index: 2, DurationSeconds: 60, CustomFields: map[string]string{"title": "Later"}},
+		}},
+	}
+	segments := []render.TimelineSegmentPath{
+		{CollectionName: "__inline__", Path: inline},
+		{CollectionName: "songs", Index: 1, Path: rendered},
+		{CollectionName: "songs", Index: 2, Path: filepath.Join(dir, "002_later.mp4")},
+	}
+	opts := render.ContactSheetOptions{Columns: 6, TileWidth: 320, TileHeight: 180, CaptionSize: 17}
+
+	tiles, missing := buildSheetTiles(context.Background(), "", segments, collections, opts)
+	if missing != 1 || len(tiles) != 3 {
+		t.Fatalf("got %d tiles, %d missing; want 3 and 1", len(tiles), missing)
+	}
+	want := []render.ContactSheetTile{
+		{Path: inline, At: 0, Caption: "1. intro"},
+		{Path: rendered, At: 30, Caption: "2. Song - Band"},
+		{Caption: "3. not rendered"},
+	}
+	for i := range want {
+		if tiles[i] != want[i] {
+			t.Errorf("tile %d = %+v, want %+v", i, tiles[i], want[i])
+		}
+	}
+}
+
+func TestSheetTileHeight(t *testing.T) {
+	cfg := config.Config{}
+	cfg.Video.Width, cfg.Video.Height = 1920, 1080
+	if got := sheetTileHeight(cfg, 320); got != 180 {
+		t.Errorf("16:9 height = %d, want 180", got)
+	}
+	cfg.Video.Width, cfg.Video.Height = 1080, 1920
+	if got := sheetTileHeight(cfg, 101); got != 178 {
+		t.Errorf("portrait height = %d, want 178", got)
+	}
+}
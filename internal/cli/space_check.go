@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/config"
+	"powerhour/internal/diskspace"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+	"powerhour/internal/render/state"
+	"powerhour/internal/tools"
+)
+
+const (
+	// fallbackDownloadBytes stands in for a download yt-dlp can't size
+	// when nothing is cached yet to average over.
+	fallbackDownloadBytes = 150 << 20
+	// fallbackClipSeconds stands in for full-length clips (duration 0).
+	fallbackClipSeconds = 240
+	// minSpaceHeadroom is the free space to keep beyond the estimate
+	// before warning; the headroom is also at least 10% of the estimate.
+	minSpaceHeadroom = 1 << 30
+)
+
+// spaceEstimate is how much a fetch or render expects to write to Dir.
+type spaceEstimate struct {
+	Dir      string
+	Bytes    int64
+	Items    int
+	Guessed  int // items sized from a fallback rather than metadata
+	Activity string
+}
+
+// checkDiskSpace compares an estimate with the free space on its volume.
+// Too little space is an error unless skip is set; less than the headroom
+// is a warning. Failing to read free space only warns.
+func checkDiskSpace(w io.Writer, est spaceEstimate, skip bool) error {
+	if est.Bytes <= 0 {
+		return nil
+	}
+	free, err := diskspace.Available(est.Dir)
+	if err != nil {
+		fmt.Fprintf(w, "warning: could not check free space on %s: %v\n", est.Dir, err)
+		return nil
+	}
+	need := fmt.Sprintf("%s needs about %s for %d items", est.Activity, formatBytes(est.Bytes), est.Items)
+	if est.Guessed > 0 {
+		need += fmt.Sprintf(" (%d estimated without size info)", est.Guessed)
+	}
+	headroom := max(int64(minSpaceHeadroom), est.Bytes/10)
+
+	switch {
+	case free < uint64(est.Bytes):
+		msg := fmt.Sprintf("not enough disk space: %s, but only %s is free on %s", need, formatBytes(int64(free)), est.Dir)
+		if skip {
+			fmt.Fprintf(w, "warning: %s; continuing because of --skip-space-check\n", msg)
+			return nil
+		}
+		return fmt.Errorf("%s; free up space or re-run with --skip-space-check", msg)
+	case free < uint64(est.Bytes+headroom):
+		fmt.Fprintf(w, "warning: disk space is tight: %s, %s free on %s\n", need, formatBytes(int64(free)), est.Dir)
+	}
+	return nil
+}
+
+// estimateFetchSpace sizes the downloads a fetch will make from yt-dlp
+// metadata. Rows already cached (unless forced) and local files need no
+// space; downloads yt-dlp can't size count as the average cached entry.
+func estimateFetchSpace(ctx context.Context, svc *cache.Service, pp paths.ProjectPaths, idx *cache.Index, rows []project.CollectionPlanRow, force bool, status func(string)) spaceEstimate {
+	est := spaceEstimate{Dir: pp.CacheDir, Activity: "fetch"}
+	var pending []string
+	seen := make(map[string]bool)
+	for _, r := range rows {
+		link := strings.TrimSpace(r.Row.Link)
+		// Rows sharing a link download it once.
+		if !isRemoteLink(link) || seen[link] {
+			continue
+		}
+		seen[link] = true
+		if !force {
+			if _, ok, err := render.ResolveEntry(pp, idx, r.Row); err == nil && ok {
+				continue
+			}
+		}
+		pending = append(pending, link)
+	}
+	fallback := averageCachedSize(idx)
+	for i, link := range pending {
+		if status != nil {
+			status(fmt.Sprintf("Estimating download size (%d/%d)...", i+1, len(pending)))
+		}
+		est.Items++
+		info, err := svc.QueryRemoteID(ctx, link)
+		if err != nil || info.SizeBytes <= 0 {
+			est.Guessed++
+			est.Bytes += fallback
+			continue
+		}
+		est.Bytes += info.SizeBytes
+	}
+	return est
+}
+
+func averageCachedSize(idx *cache.Index) int64 {
+	var total, n int64
+	if idx != nil {
+		for _, entry := range idx.Entries {
+			if entry.SourceType == cache.SourceTypeURL && entry.SizeBytes > 0 {
+				total += entry.SizeBytes
+				n++
+			}
+		}
+	}
+	if n == 0 {
+		return fallbackDownloadBytes
+	}
+	return total / n
+}
+
+// estimateRenderSpace sizes the segments a render will write from the
+// resolved video and audio bitrates and each clip's output length, less
+// the size of any output it replaces. progress follows the change
+// detection that picks those segments.
+func estimateRenderSpace(cfg config.Config, rs *state.RenderState, segments []render.Segment, dir string, force bool, progress state.ScanProgress) spaceEstimate {
+	est := spaceEstimate{Dir: dir, Activity: "render"}
+	enc := tools.ResolveEncoding(tools.LoadEncodingProfile(), tools.LoadEncodingDefaults(), encodingConfigToDefaults(cfg.Encoding))
+	bitsPerSecond := parseBitrate(enc.VideoBitrate) + parseBitrate(enc.AudioBitrate)
+	if bitsPerSecond <= 0 {
+		return est
+	}
+
+	actions := state.DetectChangesProgress(rs, segments, cfg, cfg.SegmentFilenameTemplate(), force, progress)
+	for _, a := range actions {
+		if a.Action == state.ActionSkip {
+			continue
+		}
+		seconds := a.Segment.OutputSeconds()
+		if a.Segment.Clip.DurationSeconds <= 0 {
+			seconds = fallbackClipSeconds + a.Segment.Clip.PrerollSeconds + a.Segment.Clip.PostrollSeconds
+			est.Guessed++
+		}
+		bytes := int64(seconds * bitsPerSecond / 8)
+		if info, err := os.Stat(a.Segment.OutputPath); err == nil {
+			bytes -= info.Size()
+		}
+		est.Items++
+		est.Bytes += max(bytes, 0)
+	}
+	return est
+}
+
+// parseBitrate parses ffmpeg-style bitrates such as "8M", "192k" or
+// "2500000" into bits per second; anything else is 0.
+func parseBitrate(value string) float64 {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	multiplier := 1.0
+	switch value[len(value)-1] {
+	case 'k', 'K':
+		multiplier = 1e3
+	case 'm', 'M':
+		multiplier = 1e6
+	case 'g', 'G':
+		multiplier = 1e9
+	}
+	if multiplier != 1 {
+		value = value[:len(value)-1]
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n * multiplier
+}
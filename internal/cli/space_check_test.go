@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"powerhour/internal/cache"
+)
+
+func TestParseBitrate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+	}{
+		{"8M", 8e6},
+		{"192k", 192e3},
+		{" 2.5m ", 2.5e6},
+		{"2500000", 2500000},
+		{"", 0},
+		{"fast", 0},
+		{"-1M", 0},
+	}
+	for _, tt := range tests {
+		if got := parseBitrate(tt.in); got != tt.want {
+			t.Errorf("parseBitrate(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCheckDiskSpace(t *testing.T) {
+	dir := t.TempDir()
+	huge := spaceEstimate{Dir: dir, Bytes: 1 << 62, Items: 3, Guessed: 1, Activity: "fetch"}
+
+	var out bytes.Buffer
+	err := checkDiskSpace(&out, huge, false)
+	if err == nil || !strings.Contains(err.Error(), "--skip-space-check") {
+		t.Fatalf("expected not-enough-space error naming --skip-space-check, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "1 estimated without size info") {
+		t.Errorf("error should mention guessed items: %v", err)
+	}
+
+	out.Reset()
+	if err := checkDiskSpace(&out, huge, true); err != nil {
+		t.Fatalf("skip should not fail: %v", err)
+	}
+	if !strings.Contains(out.String(), "warning: not enough disk space") {
+		t.Errorf("skip should still warn, got %q", out.String())
+	}
+
+	out.Reset()
+	if err := checkDiskSpace(&out, spaceEstimate{Dir: dir, Bytes: 1, Items: 1, Activity: "render"}, false); err != nil {
+		t.Fatalf("tiny estimate failed: %v", err)
+	}
+	if err := checkDiskSpace(&out, spaceEstimate{Dir: dir}, false); err != nil || out.Len() != 0 {
+		t.Errorf("empty estimate should be silent, got err=%v out=%q", err, out.String())
+	}
+}
+
+func TestAverageCachedSize(t *testing.T) {
+	if got := averageCachedSize(nil); got != fallbackDownloadBytes {
+		t.Errorf("nil index = %d, want fallback", got)
+	}
+	idx := &cache.Index{}
+	idx.SetEntry(cache.Entry{Identifier: "a", SourceType: cache.SourceTypeURL, SizeBytes: 100})
+	idx.SetEntry(cache.Entry{Identifier: "b", SourceType: cache.SourceTypeURL, SizeBytes: 300})
+	idx.SetEntry(cache.Entry{Identifier: "c", SourceType: cache.SourceTypeLocal, SizeBytes: 9000})
+	if got := averageCachedSize(idx); got != 200 {
+		t.Errorf("averageCachedSize = %d, want 200", got)
+	}
+}
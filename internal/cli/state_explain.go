@@ -0,0 +1,294 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/config"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+	"powerhour/internal/render/state"
+)
+
+func newStateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "state",
+		Short: "Inspect the render state used to skip unchanged segments",
+	}
+	cmd.AddCommand(newStateExplainCmd())
+	return cmd
+}
+
+func newStateExplainCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "explain <collection:index>",
+		Short: "Compare one segment's stored render inputs with the current ones",
+		Long: `Show why a segment would re-render, or why it wouldn't: the stored hash of
+each group of render inputs next to the hash computed from the current plan
+and config, with the current value of every input in the group.
+
+The segment is named by collection and row, like songs:014 or songs#14.
+Rows with a windows column add the window, as in songs:014.2. A bare row
+number picks the only collection, or songs.
+
+Stored groups are only recorded by renders from this version on; an older
+entry shows just its overall hash.`,
+		Example: `  powerhour state explain songs:014
+  powerhour state explain 14 --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: runStateExplain,
+	}
+}
+
+// stateExplainPart compares one input group, or one global config section,
+// between the render state and the current inputs.
+type stateExplainPart struct {
+	Part    string              `json:"part"`
+	Stored  string              `json:"stored,omitempty"`
+	Current string              `json:"current"`
+	Changed bool                `json:"changed"`
+	Fields  []render.InputField `json:"fields,omitempty"`
+}
+
+type stateExplainReport struct {
+	Collection    string              `json:"collection"`
+	Index         int                 `json:"index"`
+	Window        int                 `json:"window,omitempty"`
+	Title         string              `json:"title"`
+	Output        string              `json:"output"`
+	Action        string              `json:"action"`
+	Reason        string              `json:"reason"`
+	Stored        *state.SegmentState `json:"stored,omitempty"`
+	StoredHash    string              `json:"stored_hash,omitempty"`
+	CurrentHash   string              `json:"current_hash"`
+	SettingsHash  string              `json:"settings_hash"`
+	Global        []stateExplainPart  `json:"global"`
+	Parts         []stateExplainPart  `json:"parts"`
+	PartsRecorded bool                `json:"parts_recorded"`
+}
+
+func runStateExplain(cmd *cobra.Command, args []string) error {
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
+
+	resolver, err := project.NewCollectionResolver(cfg, pp)
+	if err != nil {
+		return err
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		return err
+	}
+	name, index, window, err := parseSegmentRef(args[0], collections)
+	if err != nil {
+		return err
+	}
+	idx, err := cache.Load(pp)
+	if err != nil {
+		return err
+	}
+	rs, err := state.Load(pp.RenderStateFile)
+	if err != nil {
+		return fmt.Errorf("load render state: %w", err)
+	}
+
+	clips, err := resolver.BuildCollectionClips(collections)
+	if err != nil {
+		return err
+	}
+	applySequenceEntryOverrides(cfg, clips)
+	var (
+		found project.CollectionClip
+		ok    bool
+	)
+	for _, cc := range clips {
+		if cc.CollectionName == name && cc.Clip.Row.Index == index && cc.Clip.Window == window {
+			found, ok = cc, true
+			break
+		}
+	}
+	if !ok {
+		if window > 0 {
+			return fmt.Errorf("collection %q has no rendered row %d window %d", name, index, window)
+		}
+		return fmt.Errorf("collection %q has no rendered row %d (skipped rows and rows with windows need no segment of their own)", name, index)
+	}
+	// An uncached source still has an output path and input hashes.
+	seg, _ := render.BuildCollectionSegment(pp, cfg, idx, found)
+
+	report := explainSegmentState(rs, seg, cfg)
+	report.Collection, report.Index, report.Window = name, index, window
+	report.Title = clipDisplayTitle(found.Clip)
+
+	if outputJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+	printStateExplain(cmd, pp, report)
+	return nil
+}
+
+// parseSegmentRef reads "<collection>:<index>[.<window>]" ("#" works too),
+// or a bare index in the default plan collection.
+func parseSegmentRef(ref string, collections map[string]project.Collection) (name string, index, window int, err error) {
+	ref = strings.TrimSpace(ref)
+	rest := ref
+	if i := strings.LastIndexAny(ref, ":#"); i >= 0 {
+		name, rest = strings.TrimSpace(ref[:i]), ref[i+1:]
+		if _, ok := collections[name]; !ok {
+			return "", 0, 0, fmt.Errorf("collection %q not found", name)
+		}
+	} else if name, err = pickPlanCollection(collections, ""); err != nil {
+		return "", 0, 0, err
+	}
+	if row, w, ok := strings.Cut(rest, "."); ok {
+		rest = row
+		if window, err = strconv.Atoi(w); err != nil || window < 1 {
+			return "", 0, 0, fmt.Errorf("invalid segment %q: window must be a positive number", ref)
+		}
+	}
+	if index, err = strconv.Atoi(strings.TrimSpace(rest)); err != nil || index < 1 {
+		return "", 0, 0, fmt.Errorf("invalid segment %q: want collection:index, like songs:014", ref)
+	}
+	return name, index, window, nil
+}
+
+// explainSegmentState compares seg's stored render state with its current
+// inputs, part by part.
+func explainSegmentState(rs *state.RenderState, seg render.Segment, cfg config.Config) stateExplainReport {
+	template := cfg.SegmentFilenameTemplate()
+	change := state.ExplainChanges(rs, []render.Segment{seg}, cfg, template)[0]
+	report := stateExplainReport{
+		Output:       seg.OutputPath,
+		Action:       change.Action,
+		Reason:       change.Reason,
+		CurrentHash:  state.SegmentInputHash(seg, template),
+		SettingsHash: state.SettingsHash(seg, cfg),
+	}
+
+	currentGlobal := state.GlobalConfigParts(cfg)
+	for _, part := range []string{state.GlobalPartVideo, state.GlobalPartAudio, state.GlobalPartEncoding} {
+		p := stateExplainPart{Part: part, Stored: rs.GlobalConfigParts[part], Current: currentGlobal[part]}
+		p.Changed = rs.GlobalConfigHash != "" && p.Stored != p.Current
+		report.Global = append(report.Global, p)
+	}
+
+	prior, exists := rs.Segments[seg.OutputPath]
+	if exists {
+		report.Stored = &prior
+		report.StoredHash = prior.InputHash
+	}
+	report.PartsRecorded = len(prior.InputParts) > 0
+	current := state.SegmentInputParts(seg, template)
+	fields := render.SegmentInputFields(seg, template)
+	for _, part := range render.InputPartNames {
+		p := stateExplainPart{Part: part, Stored: prior.InputParts[part], Current: current[part]}
+		p.Changed = report.PartsRecorded && p.Stored != p.Current
+		for _, f := range fields {
+			if f.Part == part {
+				p.Fields = append(p.Fields, f)
+			}
+		}
+		report.Parts = append(report.Parts, p)
+	}
+	return report
+}
+
+func printStateExplain(cmd *cobra.Command, pp paths.ProjectPaths, r stateExplainReport) {
+	out := cmd.OutOrStdout()
+	label := fmt.Sprintf("%s #%03d", r.Collection, r.Index)
+	if r.Window > 0 {
+		label += fmt.Sprintf(".%d", r.Window)
+	}
+	fmt.Fprintf(out, "%s  %s\n", label, r.Title)
+	fmt.Fprintf(out, "Output:    %s\n", relPath(pp.Root, r.Output))
+	switch {
+	case r.Stored == nil:
+		fmt.Fprintln(out, "Rendered:  never")
+	case r.Stored.InputHash == "":
+		fmt.Fprintf(out, "Rendered:  %s (hash cleared: marked stale)\n", r.Stored.RenderedAt.Local().Format(time.DateTime))
+	default:
+		fmt.Fprintf(out, "Rendered:  %s\n", r.Stored.RenderedAt.Local().Format(time.DateTime))
+	}
+	fmt.Fprintf(out, "Hash:      stored %s, current %s\n", shortHash(r.StoredHash), shortHash(r.CurrentHash))
+	if r.Stored != nil && r.Stored.SettingsHash != "" {
+		fmt.Fprintf(out, "Settings:  stored %s, current %s\n", shortHash(r.Stored.SettingsHash), shortHash(r.SettingsHash))
+	}
+	fmt.Fprintf(out, "Next run:  %s (%s)\n\n", r.Action, r.Reason)
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RENDER SETTINGS\tSTORED\tCURRENT\t")
+	for _, p := range r.Global {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", p.Part, shortHash(p.Stored), shortHash(p.Current), partStatus(p, p.Stored != ""))
+	}
+	fmt.Fprintln(tw, "\t\t\t")
+	fmt.Fprintln(tw, "INPUTS\tSTORED\tCURRENT\t")
+	for _, p := range r.Parts {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", p.Part, shortHash(p.Stored), shortHash(p.Current), partStatus(p, r.PartsRecorded))
+		for _, f := range p.Fields {
+			fmt.Fprintf(tw, "  %s\t%s\t\t\n", f.Name, formatInputValue(f.Value))
+		}
+	}
+	tw.Flush()
+
+	if r.Stored != nil && !r.PartsRecorded {
+		fmt.Fprintln(out, "\nThe last render predates per-input hashes; render this segment again to compare inputs.")
+	}
+}
+
+func partStatus(p stateExplainPart, recorded bool) string {
+	switch {
+	case !recorded:
+		return "-"
+	case p.Changed:
+		return "changed"
+	default:
+		return "same"
+	}
+}
+
+// shortHash trims a "sha256:<hex>" hash to its first 12 hex digits.
+func shortHash(hash string) string {
+	if hash == "" {
+		return "-"
+	}
+	hex := strings.TrimPrefix(hash, "sha256:")
+	if len(hex) > 12 {
+		hex = hex[:12]
+	}
+	return hex
+}
+
+// formatInputValue renders an input value as compact JSON, shortened to
+// fit a table column.
+func formatInputValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	s := string(data)
+	if len(s) > 80 {
+		s = s[:77] + "..."
+	}
+	return s
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"testing"
+
+	"powerhour/internal/config"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+	"powerhour/internal/render/state"
+	"powerhour/pkg/csvplan"
+)
+
+func TestParseSegmentRef(t *testing.T) {
+	collections := map[string]project.Collection{"songs": {}, "interstitials": {}}
+	tests := []struct {
+		ref        string
+		wantName   string
+		wantIndex  int
+		wantWindow int
+		wantErr    bool
+	}{
+		{ref: "songs:014", wantName: "songs", wantIndex: 14},
+		{ref: "interstitials#3", wantName: "interstitials", wantIndex: 3},
+		{ref: "songs:7.2", wantName: "songs", wantIndex: 7, wantWindow: 2},
+		{ref: "12", wantName: "songs", wantIndex: 12},
+		{ref: "extras:1", wantErr: true},
+		{ref: "songs:abc", wantErr: true},
+		{ref: "songs:0", wantErr: true},
+		{ref: "songs:3.0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			name, index, window, err := parseSegmentRef(tt.ref, collections)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseSegmentRef(%q) = %s, %d, %d, want error", tt.ref, name, index, window)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseSegmentRef(%q): %v", tt.ref, err)
+			}
+			if name != tt.wantName || index != tt.wantIndex || window != tt.wantWindow {
+				t.Errorf("parseSegmentRef(%q) = %s, %d, %d, want %s, %d, %d", tt.ref, name, index, window, tt.wantName, tt.wantIndex, tt.wantWindow)
+			}
+		})
+	}
+}
+
+func TestExplainSegmentState(t *testing.T) {
+	cfg := config.Default()
+	seg := render.Segment{
+		Clip: project.Clip{
+			DurationSeconds: 60,
+			Row:             csvplan.Row{Index: 14, Title: "Teenagers", StartRaw: "1:30", Link: "https://example.com/a"},
+		},
+		OutputPath: "/segments/014.mp4",
+	}
+	rs := &state.RenderState{Segments: map[string]state.SegmentState{
+		seg.OutputPath: state.NewSegmentState(seg, render.Result{}, cfg),
+	}}
+	rs.SetGlobalConfig(cfg)
+
+	seg.Clip.Row.StartRaw = "1:45"
+	report := explainSegmentState(rs, seg, cfg)
+	if report.Action != state.ActionRender || report.Reason != state.ReasonInputChanged {
+		t.Errorf("action = %s (%s), want render (input changed)", report.Action, report.Reason)
+	}
+	if !report.PartsRecorded || report.StoredHash == report.CurrentHash {
+		t.Errorf("report = %+v, want recorded parts and differing hashes", report)
+	}
+	for _, p := range report.Parts {
+		if want := p.Part == render.InputPartTiming; p.Changed != want {
+			t.Errorf("part %s changed = %t, want %t", p.Part, p.Changed, want)
+		}
+		if p.Part == render.InputPartTiming && (len(p.Fields) == 0 || p.Fields[0].Value != "1:45") {
+			t.Errorf("timing fields = %+v, want current start first", p.Fields)
+		}
+	}
+	for _, p := range report.Global {
+		if p.Changed {
+			t.Errorf("global part %s changed, want same", p.Part)
+		}
+	}
+}
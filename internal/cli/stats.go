@@ -0,0 +1,259 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/config"
+	"powerhour/internal/paths"
+	"powerhour/internal/render/state"
+)
+
+var (
+	statsTimeline string
+	statsRuns     int
+	statsSlowest  int
+)
+
+// segmentTiming is one rendered segment's recorded metrics.
+type segmentTiming struct {
+	Segment  string  `json:"segment"`
+	VideoS   float64 `json:"video_s"`
+	EncodeS  float64 `json:"encode_s"`
+	FPS      float64 `json:"fps,omitempty"`
+	Bytes    int64   `json:"bytes,omitempty"`
+	Realtime float64 `json:"realtime"` // seconds of video per second of encoding
+}
+
+// timingTotals aggregates encode metrics over segments or runs.
+type timingTotals struct {
+	Segments    int     `json:"segments"`
+	VideoS      float64 `json:"video_s"`
+	EncodeS     float64 `json:"encode_s"`
+	Bytes       int64   `json:"bytes"`
+	Realtime    float64 `json:"realtime,omitempty"`
+	BitrateKbps float64 `json:"bitrate_kbps,omitempty"`
+}
+
+func (t *timingTotals) finish() {
+	if t.EncodeS > 0 {
+		t.Realtime = t.VideoS / t.EncodeS
+	}
+	if t.VideoS > 0 {
+		t.BitrateKbps = float64(t.Bytes) * 8 / 1000 / t.VideoS
+	}
+}
+
+// settingsTotals aggregates the runs made with one set of encode settings.
+type settingsTotals struct {
+	Settings string    `json:"settings"`
+	Runs     int       `json:"runs"`
+	FirstRun time.Time `json:"first_run"`
+	LastRun  time.Time `json:"last_run"`
+	timingTotals
+}
+
+type renderStatsReport struct {
+	Current    timingTotals     `json:"current"`
+	Untimed    int              `json:"untimed"` // rendered before metrics were recorded
+	AvgFPS     float64          `json:"avg_fps,omitempty"`
+	Slowest    []segmentTiming  `json:"slowest,omitempty"`
+	BySettings []settingsTotals `json:"by_settings,omitempty"`
+	Runs       []state.RunStats `json:"runs,omitempty"`
+}
+
+func newStatsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show render timing, speed and size history",
+		Long: `Summarize the encode metrics render records: ffmpeg time, frames per
+second and output size of every current segment, plus every render run
+(up to the last 100) grouped by codec, preset, CRF and resolution so the
+effect of changing settings shows up.
+
+The same history feeds render --estimate.`,
+		Args: cobra.NoArgs,
+		RunE: runStats,
+	}
+	cmd.Flags().StringVar(&statsTimeline, "timeline", "", "Named timeline to report on")
+	cmd.Flags().IntVar(&statsRuns, "runs", 10, "Recent runs to list")
+	cmd.Flags().IntVar(&statsSlowest, "slowest", 5, "Slowest segments to list")
+	return cmd
+}
+
+func runStats(cmd *cobra.Command, _ []string) error {
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+	if _, pp, err = applyNamedTimeline(cfg, pp, statsTimeline); err != nil {
+		return err
+	}
+	rs, err := state.Load(pp.RenderStateFile)
+	if err != nil {
+		return fmt.Errorf("load render state: %w", err)
+	}
+
+	report := buildRenderStats(rs, pp.Root, statsSlowest, statsRuns)
+	if outputJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+	printRenderStats(cmd.OutOrStdout(), report)
+	return nil
+}
+
+// buildRenderStats aggregates render state. Segment paths are shown relative
+// to root; slowest and runs cap the lists.
+func buildRenderStats(rs *state.RenderState, root string, slowest, runs int) renderStatsReport {
+	var report renderStatsReport
+	var timings []segmentTiming
+	var fpsSum float64
+	var fpsCount int
+	for path, seg := range rs.Segments {
+		if seg.EncodeS <= 0 || seg.DurationS <= 0 {
+			report.Untimed++
+			continue
+		}
+		timings = append(timings, segmentTiming{
+			Segment:  relPath(root, path),
+			VideoS:   seg.DurationS,
+			EncodeS:  seg.EncodeS,
+			FPS:      seg.FPS,
+			Bytes:    seg.SizeBytes,
+			Realtime: seg.DurationS / seg.EncodeS,
+		})
+		report.Current.Segments++
+		report.Current.VideoS += seg.DurationS
+		report.Current.EncodeS += seg.EncodeS
+		report.Current.Bytes += seg.SizeBytes
+		if seg.FPS > 0 {
+			fpsSum += seg.FPS
+			fpsCount++
+		}
+	}
+	report.Current.finish()
+	if fpsCount > 0 {
+		report.AvgFPS = fpsSum / float64(fpsCount)
+	}
+	sort.Slice(timings, func(i, j int) bool {
+		if timings[i].Realtime != timings[j].Realtime {
+			return timings[i].Realtime < timings[j].Realtime
+		}
+		return timings[i].Segment < timings[j].Segment
+	})
+	report.Slowest = timings[:min(slowest, len(timings))]
+
+	bySettings := map[string]*settingsTotals{}
+	for _, run := range rs.Runs {
+		key := run.Settings()
+		st, ok := bySettings[key]
+		if !ok {
+			st = &settingsTotals{Settings: key, FirstRun: run.StartedAt}
+			bySettings[key] = st
+		}
+		st.Runs++
+		st.LastRun = run.StartedAt
+		st.Segments += run.Rendered
+		st.VideoS += run.VideoS
+		st.EncodeS += run.EncodeS
+		st.Bytes += run.Bytes
+	}
+	for _, st := range bySettings {
+		st.finish()
+		report.BySettings = append(report.BySettings, *st)
+	}
+	sort.Slice(report.BySettings, func(i, j int) bool {
+		return report.BySettings[i].FirstRun.Before(report.BySettings[j].FirstRun)
+	})
+
+	start := max(len(rs.Runs)-runs, 0)
+	for i := len(rs.Runs) - 1; i >= start; i-- {
+		report.Runs = append(report.Runs, rs.Runs[i])
+	}
+	return report
+}
+
+func printRenderStats(out io.Writer, report renderStatsReport) {
+	if report.Current.Segments == 0 && len(report.Runs) == 0 {
+		if report.Untimed > 0 {
+			fmt.Fprintf(out, "%d segments were rendered before timings were recorded; re-render to collect them.\n", report.Untimed)
+		} else {
+			fmt.Fprintln(out, "No render history yet; run powerhour render first.")
+		}
+		return
+	}
+
+	cur := report.Current
+	if cur.Segments > 0 {
+		fmt.Fprintf(out, "Current segments: %d timed", cur.Segments)
+		if report.Untimed > 0 {
+			fmt.Fprintf(out, " (%d untimed)", report.Untimed)
+		}
+		fmt.Fprintln(out)
+		fmt.Fprintf(out, "  Video %s, encode %s, %.1fx realtime", formatSampleTime(cur.VideoS), formatSampleTime(cur.EncodeS), cur.Realtime)
+		if report.AvgFPS > 0 {
+			fmt.Fprintf(out, ", %.0f fps", report.AvgFPS)
+		}
+		fmt.Fprintln(out)
+		if cur.Bytes > 0 {
+			fmt.Fprintf(out, "  Size %s, %.0f kb/s\n", formatBytes(cur.Bytes), cur.BitrateKbps)
+		}
+		if len(report.Slowest) > 0 {
+			fmt.Fprintln(out, "\nSlowest segments:")
+			w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "  SEGMENT\tLENGTH\tENCODE\tSPEED\tFPS")
+			for _, t := range report.Slowest {
+				fmt.Fprintf(w, "  %s\t%s\t%s\t%.1fx\t%s\n", t.Segment, formatSampleTime(t.VideoS), formatSampleTime(t.EncodeS), t.Realtime, statsFPS(t.FPS))
+			}
+			w.Flush()
+		}
+	}
+
+	if len(report.BySettings) > 0 {
+		fmt.Fprintln(out, "\nBy settings (oldest first):")
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "  SETTINGS\tRUNS\tSEGMENTS\tSPEED\tBITRATE\tLAST RUN")
+		for _, st := range report.BySettings {
+			fmt.Fprintf(w, "  %s\t%d\t%d\t%.1fx\t%.0f kb/s\t%s\n", st.Settings, st.Runs, st.Segments, st.Realtime, st.BitrateKbps, st.LastRun.Local().Format(time.DateTime))
+		}
+		w.Flush()
+	}
+
+	if len(report.Runs) > 0 {
+		fmt.Fprintln(out, "\nRecent runs:")
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "  STARTED\tSETTINGS\tRENDERED\tFAILED\tWALL\tSPEED\tSIZE")
+		for _, run := range report.Runs {
+			speed := "-"
+			if run.EncodeS > 0 {
+				speed = fmt.Sprintf("%.1fx", run.VideoS/run.EncodeS)
+			}
+			fmt.Fprintf(w, "  %s\t%s\t%d\t%d\t%s\t%s\t%s\n", run.StartedAt.Local().Format(time.DateTime), run.Settings(),
+				run.Rendered, run.Failed, formatSampleTime(run.WallS), speed, formatBytes(run.Bytes))
+		}
+		w.Flush()
+	}
+}
+
+func statsFPS(fps float64) string {
+	if fps <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f", fps)
+}
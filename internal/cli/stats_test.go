@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"powerhour/internal/render/state"
+)
+
+func TestBuildRenderStats(t *testing.T) {
+	day := time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)
+	x264 := state.RunStats{Codec: "libx264", Preset: "medium", CRF: 20, Width: 1920, Height: 1080, FPS: 30}
+	x265 := x264
+	x265.Codec = "libx265"
+	run := func(base state.RunStats, at time.Time, rendered int, videoS, encodeS float64) state.RunStats {
+		base.StartedAt, base.Rendered, base.VideoS, base.EncodeS, base.Bytes = at, rendered, videoS, encodeS, int64(videoS)*1000
+		return base
+	}
+	rs := &state.RenderState{
+		Segments: map[string]state.SegmentState{
+			"/p/segments/001.mp4": {DurationS: 60, EncodeS: 20, FPS: 90, SizeBytes: 60000},
+			"/p/segments/002.mp4": {DurationS: 60, EncodeS: 60, FPS: 30, SizeBytes: 60000},
+			"/p/segments/003.mp4": {DurationS: 60},
+		},
+		Runs: []state.RunStats{
+			run(x264, day, 10, 600, 200),
+			run(x265, day.Add(time.Hour), 10, 600, 600),
+			run(x264, day.Add(2*time.Hour), 2, 120, 40),
+		},
+	}
+
+	report := buildRenderStats(rs, "/p", 1, 2)
+	if report.Current.Segments != 2 || report.Untimed != 1 || report.Current.Realtime != 1.5 || report.AvgFPS != 60 {
+		t.Fatalf("current = %+v untimed %d fps %v", report.Current, report.Untimed, report.AvgFPS)
+	}
+	if len(report.Slowest) != 1 || report.Slowest[0].Segment != "segments/002.mp4" {
+		t.Fatalf("slowest = %+v", report.Slowest)
+	}
+	if len(report.BySettings) != 2 {
+		t.Fatalf("by settings = %+v", report.BySettings)
+	}
+	if st := report.BySettings[0]; st.Settings != x264.Settings() || st.Runs != 2 || st.Segments != 12 || st.Realtime != 3 || st.BitrateKbps != 8 {
+		t.Fatalf("x264 = %+v", st)
+	}
+	if st := report.BySettings[1]; st.Realtime != 1 || !st.LastRun.Equal(day.Add(time.Hour)) {
+		t.Fatalf("x265 = %+v", st)
+	}
+	if len(report.Runs) != 2 || report.Runs[0].Rendered != 2 {
+		t.Fatalf("runs should be newest first, got %+v", report.Runs)
+	}
+
+	var out bytes.Buffer
+	printRenderStats(&out, report)
+	for _, want := range []string{"Current segments: 2 timed (1 untimed)", "libx265 medium crf 20 1920x1080@30", "Recent runs:"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"sort"
@@ -21,12 +22,15 @@ import (
 	"powerhour/pkg/csvplan"
 )
 
+var statusTimeline string
+
 func newStatusCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "Show project status with per-row cache and render state",
 		RunE:  runStatus,
 	}
+	cmd.Flags().StringVar(&statusTimeline, "timeline", "", "Report render state for a named timeline from timelines:")
 	return cmd
 }
 
@@ -51,6 +55,7 @@ type rowStatus struct {
 	RenderReason string `json:"render_reason,omitempty"`
 	StoredHash   string `json:"stored_hash,omitempty"`
 	ComputedHash string `json:"computed_hash,omitempty"`
+	Skipped      bool   `json:"skipped,omitempty"`
 }
 
 // collectionSummary aggregates row statuses for a collection.
@@ -62,6 +67,7 @@ type collectionSummary struct {
 	Rendered     int    `json:"rendered"`
 	Stale        int    `json:"stale"`
 	Missing      int    `json:"missing"`
+	Skipped      int    `json:"skipped,omitempty"`
 }
 
 // collectionPalette maps sorted collection index to a terminal color.
@@ -98,6 +104,10 @@ func runStatus(cmd *cobra.Command, _ []string) error {
 	}
 	pp = paths.ApplyConfig(pp, cfg)
 	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
+	cfg, pp, err = applyNamedTimeline(cfg, pp, statusTimeline)
+	if err != nil {
+		return err
+	}
 
 	if cfg.Collections == nil || len(cfg.Collections) == 0 {
 		return fmt.Errorf("no collections configured")
@@ -123,6 +133,7 @@ func runStatus(cmd *cobra.Command, _ []string) error {
 
 	// Resolve timeline
 	var timelineEntries []timelineEntryOutput
+	var runtime *project.TimelineBudget
 	hasTimeline := len(cfg.Timeline.Sequence) > 0
 
 	if hasTimeline {
@@ -165,6 +176,9 @@ func runStatus(cmd *cobra.Command, _ []string) error {
 						SourceKind:     project.SourceKindPlan,
 						FadeInSeconds:  fadeIn,
 						FadeOutSeconds: fadeOut,
+						FadeCurve:      entry.FadeCurve,
+						AudioFadeIn:    entry.AudioFadeIn,
+						AudioFadeOut:   entry.AudioFadeOut,
 						Row: csvplan.Row{
 							Index: seqIdx + 1,
 							Link:  sourcePath,
@@ -181,20 +195,28 @@ func runStatus(cmd *cobra.Command, _ []string) error {
 			}
 			timelineEntries = append(timelineEntries, out)
 		}
+
+		budget, err := project.TimelineRuntime(cfg.Timeline, collections)
+		if err != nil {
+			return fmt.Errorf("project timeline runtime: %w", err)
+		}
+		runtime = &budget
 	}
 
 	payload := struct {
-		Project     string                `json:"project"`
-		Summaries   []collectionSummary   `json:"summaries"`
-		Rows        []rowStatus           `json:"rows"`
-		HasTimeline bool                  `json:"has_timeline"`
-		Timeline    []timelineEntryOutput `json:"timeline,omitempty"`
+		Project     string                  `json:"project"`
+		Summaries   []collectionSummary     `json:"summaries"`
+		Rows        []rowStatus             `json:"rows"`
+		HasTimeline bool                    `json:"has_timeline"`
+		Timeline    []timelineEntryOutput   `json:"timeline,omitempty"`
+		Runtime     *project.TimelineBudget `json:"runtime,omitempty"`
 	}{
 		Project:     pp.Root,
 		Summaries:   summaries,
 		Rows:        rows,
 		HasTimeline: hasTimeline,
 		Timeline:    timelineEntries,
+		Runtime:     runtime,
 	}
 
 	if outputJSON {
@@ -206,11 +228,14 @@ func runStatus(cmd *cobra.Command, _ []string) error {
 		return nil
 	}
 
-	printStatusResult(pp.Root, collections, summaries, rows, timelineEntries)
+	printStatusResult(pp.Root, collections, summaries, rows, timelineEntries, runtime)
 	return nil
 }
 
 func buildRowStatuses(pp paths.ProjectPaths, cfg config.Config, idx *cache.Index, rs *state.RenderState, collections map[string]project.Collection, tmpl string) ([]rowStatus, []collectionSummary) {
+	// Rows are reported as rendered, with overrides applied.
+	collections = project.WithRowOverrides(collections)
+
 	// Sort collection names for deterministic output
 	sortedNames := make([]string, 0, len(collections))
 	for name := range collections {
@@ -221,24 +246,25 @@ func buildRowStatuses(pp paths.ProjectPaths, cfg config.Config, idx *cache.Index
 	var allRows []rowStatus
 	var summaries []collectionSummary
 
-	globalHash := state.GlobalConfigHash(cfg)
-	configChanged := globalHash != rs.GlobalConfigHash
-
 	for _, collName := range sortedNames {
 		coll := collections[collName]
 		summary := collectionSummary{Name: collName, Total: len(coll.Rows)}
 
 		for _, collRow := range coll.Rows {
 			r := collRow.ToRow()
+			skipped := project.RowSkipped(collRow)
 
 			// Cache status
 			cacheStatus := "missing"
 			link := strings.TrimSpace(r.Link)
 			isURL := strings.HasPrefix(link, "http://") || strings.HasPrefix(link, "https://") || strings.HasPrefix(link, "youtu")
 
-			if isURL {
-				_, ok, err := resolveEntryForRow(pp, idx, r)
-				if err == nil && ok {
+			entry, hasEntry, entryErr := render.ResolveEntry(pp, idx, r)
+			hasEntry = entryErr == nil && hasEntry
+			if coll.Config.IsGenerator() {
+				cacheStatus = "generated"
+			} else if isURL {
+				if hasEntry {
 					cacheStatus = "cached"
 				}
 			} else {
@@ -264,6 +290,15 @@ func buildRowStatuses(pp paths.ProjectPaths, cfg config.Config, idx *cache.Index
 				DurationSeconds: r.DurationSeconds,
 				FadeInSeconds:   fadeIn,
 				FadeOutSeconds:  fadeOut,
+				FadeCurve:       collCfg.FadeCurve,
+				AudioFadeIn:     collCfg.AudioFadeIn,
+				AudioFadeOut:    collCfg.AudioFadeOut,
+				PrerollSeconds:  collCfg.Preroll,
+				PostrollSeconds: collCfg.Postroll,
+				PadMode:         collCfg.PadMode,
+				AudioCue:        collCfg.AudioCue,
+				Loop:            project.RowLoops(collCfg, collRow),
+				PadShort:        strings.ToLower(strings.TrimSpace(collCfg.PadShort)),
 			}
 
 			clip.Row.DurationSeconds = clip.DurationSeconds
@@ -271,50 +306,64 @@ func buildRowStatuses(pp paths.ProjectPaths, cfg config.Config, idx *cache.Index
 				clip.Row.Index = clip.TypeIndex
 			}
 
-			seg := render.Segment{
-				Clip:     clip,
-				Overlays: collCfg.Overlays,
+			overlays := collCfg.Overlays
+			if ov, ok := coll.RowOverrideFor(collRow); ok && len(ov.Overlays) > 0 {
+				overlays = config.MergeOverlays(collCfg.Overlays, ov.Overlays)
 			}
 
-			outputDir := coll.OutputDir
-			if !filepath.IsAbs(outputDir) {
-				outputDir = filepath.Join(pp.SegmentsDir, outputDir)
-			}
-			seg.OutputPath = filepath.Join(outputDir, render.SegmentBaseName(tmpl, seg)+".mp4")
-
-			// Render status
-			renderStatus := "missing"
-			renderReason := ""
-			currentHash := state.SegmentInputHash(seg, tmpl)
-			storedHash := ""
-			if prior, exists := rs.Segments[seg.OutputPath]; exists {
-				storedHash = prior.InputHash
-				if configChanged {
-					renderStatus = "stale"
-					renderReason = "config changed"
-				} else {
-					if currentHash != prior.InputHash {
-						renderStatus = "stale"
-						renderReason = "input changed"
-					} else if _, err := os.Stat(seg.OutputPath); os.IsNotExist(err) {
-						renderStatus = "stale"
-						renderReason = "output missing"
+			// Render status. A row with windows has a segment per window
+			// and reports the first one that isn't rendered.
+			var renderStatus, renderReason, currentHash, storedHash string
+			for _, windowClip := range project.WindowClips(clip) {
+				windowClip.Row.DurationSeconds = windowClip.DurationSeconds
+				seg := render.Segment{
+					Clip:     windowClip,
+					Overlays: overlays,
+				}
+				if hasEntry {
+					seg.Crop = render.ResolveCrop(cfg, r, entry)
+					seg.Color = render.ResolveColor(cfg, entry)
+					seg.Downmix = render.ResolveDownmix(cfg, entry)
+				}
+				seg.OutputPath = render.CollectionSegmentPath(cfg, pp.SegmentsDir, collName, coll.OutputDir, seg)
+
+				status, reason, stored := "missing", "", ""
+				hash := state.SegmentInputHash(seg, tmpl)
+				if prior, exists := rs.Segments[seg.OutputPath]; exists {
+					stored = prior.InputHash
+					if !rs.SettingsCurrent(seg, prior, cfg) {
+						status = "stale"
+						reason = "config changed"
 					} else {
-						renderStatus = "rendered"
+						if hash != prior.InputHash {
+							status = "stale"
+							reason = "input changed"
+						} else if _, err := os.Stat(seg.OutputPath); os.IsNotExist(err) {
+							status = "stale"
+							reason = "output missing"
+						} else {
+							status = "rendered"
+						}
 					}
 				}
+				if renderStatus == "" || renderStatus == "rendered" {
+					renderStatus, renderReason, currentHash, storedHash = status, reason, hash, stored
+				}
 			}
 
-			// Update summary
-			if cacheStatus == "cached" {
+			// Update summary; skipped rows are counted on their own.
+			if skipped {
+				summary.Skipped++
+			} else if cacheStatus == "cached" || cacheStatus == "generated" {
 				summary.Cached++
 			} else {
 				summary.CacheMissing++
 			}
-			switch renderStatus {
-			case "rendered":
+			switch {
+			case skipped:
+			case renderStatus == "rendered":
 				summary.Rendered++
-			case "stale":
+			case renderStatus == "stale":
 				summary.Stale++
 			default:
 				summary.Missing++
@@ -334,6 +383,7 @@ func buildRowStatuses(pp paths.ProjectPaths, cfg config.Config, idx *cache.Index
 				RenderReason: renderReason,
 				StoredHash:   storedHash,
 				ComputedHash: currentHash,
+				Skipped:      skipped,
 			})
 		}
 
@@ -379,7 +429,7 @@ func sanitizeField(s string) string {
 	return strings.TrimSpace(s)
 }
 
-func printStatusResult(projectPath string, collections map[string]project.Collection, summaries []collectionSummary, rows []rowStatus, timeline []timelineEntryOutput) {
+func printStatusResult(projectPath string, collections map[string]project.Collection, summaries []collectionSummary, rows []rowStatus, timeline []timelineEntryOutput, runtime *project.TimelineBudget) {
 	bold := lipgloss.NewStyle().Bold(true).Inline(true)
 	faint := lipgloss.NewStyle().Faint(true).Inline(true)
 	green := lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Inline(true)
@@ -413,6 +463,9 @@ func printStatusResult(projectPath string, collections map[string]project.Collec
 			if s.Missing > 0 {
 				renderPart += fmt.Sprintf(", %d missing", s.Missing)
 			}
+			if s.Skipped > 0 {
+				renderPart += faint.Render(fmt.Sprintf("   %d skipped", s.Skipped))
+			}
 
 			fmt.Printf("  %s  %d rows   %s   %s\n",
 				style.Width(14).Render(s.Name),
@@ -457,6 +510,12 @@ func printStatusResult(projectPath string, collections map[string]project.Collec
 				renderLabel = yellow.Render("stale") + faint.Render(reason)
 			}
 
+			if r.Skipped {
+				fmt.Printf("  %s\n", faint.Render(fmt.Sprintf("%4d  %-14s %-30s %-10s %s",
+					r.Index, r.Collection, title, r.CacheStatus, "skipped")))
+				continue
+			}
+
 			fmt.Printf("  %4d  %s %-30s %-10s %s\n",
 				r.Index,
 				style.Width(14).Render(r.Collection),
@@ -479,6 +538,9 @@ func printStatusResult(projectPath string, collections map[string]project.Collec
 	}
 
 	fmt.Println(bold.Render("Timeline:") + fmt.Sprintf(" %d entries", len(timeline)))
+	if runtime != nil {
+		fmt.Println("  " + timelineRuntimeLine(*runtime, faint, yellow))
+	}
 	fmt.Println()
 	fmt.Printf("  %4s  %s\n", bold.Render("#"), bold.Render("Title"))
 
@@ -513,6 +575,35 @@ func printStatusResult(projectPath string, collections map[string]project.Collec
 	}
 }
 
+// timelineRuntimeLine describes the projected runtime and, when a target is
+// set, how far it lands from timeline.target_duration_s.
+func timelineRuntimeLine(b project.TimelineBudget, faint, yellow lipgloss.Style) string {
+	line := "Projected runtime: " + formatSampleTime(b.ProjectedSeconds)
+	if b.UnknownEntries > 0 {
+		line += faint.Render(fmt.Sprintf(" (+%d of unknown length)", b.UnknownEntries))
+	}
+	if b.TargetSeconds <= 0 {
+		return line
+	}
+	delta := b.DeltaSeconds()
+	sign := "+"
+	if delta < 0 {
+		sign = "-"
+	}
+	target := fmt.Sprintf("   target %s (%s%s)", formatSampleTime(b.TargetSeconds), sign, formatSampleTime(math.Abs(delta)))
+	if b.OffTarget() {
+		target = yellow.Render(target)
+	}
+	line += target
+	switch {
+	case b.Trimmed > 0:
+		line += faint.Render(fmt.Sprintf("   fit: trimmed %d clips", b.Trimmed))
+	case b.InterleaveEvery > 0:
+		line += faint.Render(fmt.Sprintf("   fit: interleave every %d", b.InterleaveEvery))
+	}
+	return line
+}
+
 func ensureProjectDirs(pp paths.ProjectPaths) error {
 	exists, err := paths.DirExists(pp.Root)
 	if err != nil {
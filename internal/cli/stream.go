@@ -0,0 +1,278 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/config"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/render"
+	"powerhour/internal/secrets"
+	"powerhour/internal/tools"
+	"powerhour/internal/tui"
+)
+
+var (
+	streamSegments     bool
+	streamTimeline     string
+	streamVariant      string
+	streamFrom         string
+	streamRetries      int
+	streamVideoBitrate string
+	streamAudioBitrate string
+	streamDryRun       bool
+)
+
+// streamHealthy is how long a pass has to run before a drop counts as a
+// fresh failure rather than another retry of the same one.
+const streamHealthy = time.Minute
+
+func newStreamCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stream [target]",
+		Short: "Stream the hour live to RTMP (Twitch, YouTube), SRT or NDI",
+		Long: `Play the hour in real time to a live stream so remote friends can join.
+
+Targets:
+  rtmp://… or rtmps://…   an RTMP ingest with the stream key, e.g.
+                          rtmp://live.twitch.tv/app/<key>
+  srt://host:port         an SRT caller; add ?mode=listener and point an
+                          OBS Media Source at it to pick the hour up locally
+  ndi:<source name>       an NDI source (needs an ffmpeg built with NDI)
+
+The target defaults to stream.url in powerhour.yaml, which may use ${NAME}
+secret references so the key stays out of the file. Stream keys are
+hidden in all output.
+
+The concat output is streamed when it exists; --segments streams the
+rendered segments instead. When the connection drops, the stream picks up
+where it left off, waiting longer between each of up to --retries
+attempts.`,
+		Example: `  powerhour stream 'rtmp://live.twitch.tv/app/${TWITCH_KEY}'
+  powerhour stream 'srt://0.0.0.0:9000?mode=listener'
+  powerhour stream --from 20:00 --video-bitrate 6000k`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runStream,
+	}
+	cmd.Flags().BoolVar(&streamSegments, "segments", false, "Stream the rendered segments instead of the concat output")
+	cmd.Flags().StringVar(&streamTimeline, "timeline", "", "Use a named timeline from timelines:")
+	cmd.Flags().StringVar(&streamVariant, "variant", "", "Follow the timeline as assembled with concat --variant")
+	cmd.Flags().StringVar(&streamFrom, "from", "", "Start this far into the hour (e.g. 20:00 or 90s)")
+	cmd.Flags().IntVar(&streamRetries, "retries", 5, "Reconnect attempts after the stream drops (0 to give up at once)")
+	cmd.Flags().StringVar(&streamVideoBitrate, "video-bitrate", "", "Video bitrate (default: stream.video_bitrate or 4500k)")
+	cmd.Flags().StringVar(&streamAudioBitrate, "audio-bitrate", "", "Audio bitrate (default: stream.audio_bitrate or 160k)")
+	cmd.Flags().BoolVar(&streamDryRun, "dry-run", false, "Print the ffmpeg command without streaming")
+	return cmd
+}
+
+func runStream(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	glog, gcloser := logx.Command("stream")
+	glogf := logx.Printf(glog)
+	defer gcloser.Close()
+
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
+	cfg, pp, err = applyNamedTimeline(cfg, pp, streamTimeline)
+	if err != nil {
+		return err
+	}
+	if streamVariant != "" {
+		cfg.Timeline, err = cfg.Timeline.WithVariant(streamVariant)
+		if err != nil {
+			return err
+		}
+	}
+
+	raw := cfg.Stream.URL
+	if len(args) > 0 {
+		raw = args[0]
+	}
+	if strings.TrimSpace(raw) == "" {
+		return fmt.Errorf("no stream target; pass one or set stream.url in %s", filepath.Base(pp.ConfigFile))
+	}
+	raw, err = secrets.Expand(raw)
+	if err != nil {
+		return err
+	}
+	target, err := render.ParseStreamTarget(raw)
+	if err != nil {
+		return err
+	}
+
+	opts := render.StreamOptions{
+		VideoBitrate: firstNonEmpty(streamVideoBitrate, cfg.Stream.VideoBitrate),
+		AudioBitrate: firstNonEmpty(streamAudioBitrate, cfg.Stream.AudioBitrate),
+	}
+	if streamFrom != "" {
+		if opts.Start, err = parseSampleTime(streamFrom); err != nil {
+			return fmt.Errorf("invalid --from %q: %w", streamFrom, err)
+		}
+	}
+
+	total := 0.0
+	source := ""
+	if !streamSegments {
+		opts.Input = findConcatOutput(pp.Root, concatOutputBase(streamTimeline, streamVariant))
+	}
+	if opts.Input != "" {
+		source = relPath(pp.Root, opts.Input)
+		if ffprobe := findFFprobe(); ffprobe != "" {
+			if info, err := probeMedia(ctx, ffprobe, opts.Input); err == nil {
+				total = info.DurationSeconds
+			}
+		}
+	} else {
+		if !streamSegments {
+			fmt.Fprintln(cmd.ErrOrStderr(), "No concat output yet; streaming the rendered segments")
+		}
+		entries, missing, err := timelinePlaylistEntries(ctx, pp, cfg)
+		if err != nil {
+			return err
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("%d segments in the timeline are not rendered yet (first: %s); run `powerhour render` first", len(missing), missing[0])
+		}
+		if len(entries) == 0 {
+			return fmt.Errorf("no segments in the timeline")
+		}
+		segments := make([]render.TimelineSegmentPath, len(entries))
+		for i, e := range entries {
+			segments[i].Path = e.Location
+			total += e.Seconds
+		}
+		list, err := os.CreateTemp("", "powerhour-stream-*.txt")
+		if err != nil {
+			return err
+		}
+		list.Close()
+		defer os.Remove(list.Name())
+		if err := render.WriteConcatList(list.Name(), segments); err != nil {
+			return err
+		}
+		opts.Input, opts.ConcatList = list.Name(), true
+		source = fmt.Sprintf("%d segments", len(entries))
+	}
+	if total > 0 && opts.Start >= total {
+		return fmt.Errorf("--from %s is past the end (%s)", streamFrom, formatSampleTime(total))
+	}
+
+	redacted := target.URL
+	if target.Kind != render.StreamNDI {
+		redacted = render.RedactStreamURL(target.URL)
+	}
+	if streamDryRun {
+		shown := render.BuildStreamArgs(target, opts)
+		shown[len(shown)-1] = redacted
+		cmd.Println("ffmpeg " + strings.Join(shown, " "))
+		return nil
+	}
+
+	ffmpeg, err := tools.Lookup("ffmpeg")
+	if err != nil {
+		return fmt.Errorf("ffmpeg not found: %w", err)
+	}
+	if target.Kind == render.StreamNDI && !render.NDIAvailable(ctx, ffmpeg) {
+		return fmt.Errorf("this ffmpeg was built without NDI output; stream to srt://0.0.0.0:9000?mode=listener and add it to OBS as a Media Source instead")
+	}
+
+	glogf("stream started: kind=%s target=%s source=%s start=%.1f", target.Kind, redacted, source, opts.Start)
+	cmd.Printf("Streaming %s to %s\n", source, redacted)
+	cmd.Println("Press Ctrl+C to stop")
+
+	status := tui.NewStatusWriter(cmd.ErrOrStderr())
+	defer status.Stop()
+	run := func(start float64) (float64, error) {
+		pass := opts
+		pass.Start = start
+		return render.RunStream(ctx, ffmpeg, target, pass, func(t float64) {
+			status.Update(streamStatusLine(start+t, total))
+		})
+	}
+	onRetry := func(attempt int, at float64, wait time.Duration, err error) {
+		glog.Warn("stream dropped", "at", at, "error", err, "retry", attempt, "wait", wait)
+		status.Update(fmt.Sprintf("Reconnecting in %s (attempt %d/%d)...", wait, attempt, streamRetries))
+		fmt.Fprintf(cmd.ErrOrStderr(), "\r\033[Kwarning: stream dropped at %s: %v\n", formatSampleTime(at), err)
+	}
+	err = streamWithReconnect(ctx, run, opts.Start, streamRetries, streamBackoff, onRetry)
+	status.Stop()
+	if ctx.Err() != nil {
+		glogf("stream stopped")
+		cmd.Println("Stream stopped")
+		return nil
+	}
+	if err != nil {
+		glog.Error("stream failed", "error", err)
+		return err
+	}
+	glogf("stream finished")
+	cmd.Println("Stream finished")
+	return nil
+}
+
+// streamWithReconnect calls run until it finishes cleanly. After a failure
+// it waits backoff(attempt) and runs again from where the last pass got to,
+// up to retries times; a pass that stayed up for streamHealthy resets the
+// count. A cancelled context stops it without another attempt.
+func streamWithReconnect(ctx context.Context, run func(start float64) (float64, error), start float64, retries int, backoff func(int) time.Duration, onRetry func(attempt int, at float64, wait time.Duration, err error)) error {
+	attempt := 0
+	for {
+		began := time.Now()
+		reached, err := run(start)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+		start += reached
+		if time.Since(began) >= streamHealthy {
+			attempt = 0
+		}
+		attempt++
+		if attempt > retries {
+			return fmt.Errorf("stream dropped and %d reconnect attempts failed: %w", retries, err)
+		}
+		wait := backoff(attempt)
+		if onRetry != nil {
+			onRetry(attempt, start, wait, err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+// streamBackoff waits 2s, 4s, 8s… between reconnects, up to 30s.
+func streamBackoff(attempt int) time.Duration {
+	wait := 2 * time.Second << min(attempt-1, 4)
+	return min(wait, 30*time.Second)
+}
+
+func streamStatusLine(at, total float64) string {
+	if total > 0 {
+		return fmt.Sprintf("Live %s / %s", formatSampleTime(at), formatSampleTime(total))
+	}
+	return "Live " + formatSampleTime(at)
+}
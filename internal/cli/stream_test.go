@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStreamWithReconnect(t *testing.T) {
+	drop := errors.New("connection reset")
+	noWait := func(int) time.Duration { return 0 }
+
+	tests := []struct {
+		name       string
+		results    []error
+		retries    int
+		wantErr    bool
+		wantStarts []float64
+	}{
+		{name: "clean run", results: []error{nil}, retries: 3, wantStarts: []float64{10}},
+		{name: "resumes after drops", results: []error{drop, drop, nil}, retries: 3, wantStarts: []float64{10, 40, 70}},
+		{name: "gives up", results: []error{drop, drop, drop}, retries: 2, wantErr: true, wantStarts: []float64{10, 40, 70}},
+		{name: "no retries", results: []error{drop}, retries: 0, wantErr: true, wantStarts: []float64{10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var starts []float64
+			run := func(start float64) (float64, error) {
+				starts = append(starts, start)
+				return 30, tt.results[len(starts)-1]
+			}
+			retried := 0
+			onRetry := func(int, float64, time.Duration, error) { retried++ }
+			err := streamWithReconnect(context.Background(), run, 10, tt.retries, noWait, onRetry)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %t", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, drop) {
+				t.Errorf("err = %v, want it to wrap the last failure", err)
+			}
+			if len(starts) != len(tt.wantStarts) {
+				t.Fatalf("starts = %v, want %v", starts, tt.wantStarts)
+			}
+			for i := range starts {
+				if starts[i] != tt.wantStarts[i] {
+					t.Errorf("starts = %v, want %v", starts, tt.wantStarts)
+					break
+				}
+			}
+			if retried != len(starts)-1 {
+				t.Errorf("onRetry called %d times, want %d", retried, len(starts)-1)
+			}
+		})
+	}
+}
+
+func TestStreamWithReconnectStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	run := func(float64) (float64, error) {
+		calls++
+		cancel()
+		return 5, context.Canceled
+	}
+	if err := streamWithReconnect(ctx, run, 0, 5, func(int) time.Duration { return time.Hour }, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if calls != 1 {
+		t.Errorf("run called %d times, want 1", calls)
+	}
+}
+
+func TestStreamBackoff(t *testing.T) {
+	want := []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 30 * time.Second, 30 * time.Second}
+	for i, w := range want {
+		if got := streamBackoff(i + 1); got != w {
+			t.Errorf("streamBackoff(%d) = %s, want %s", i+1, got, w)
+		}
+	}
+}
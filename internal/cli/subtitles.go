@@ -0,0 +1,328 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/config"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+	"powerhour/pkg/csvplan"
+)
+
+var (
+	subtitlesFormat      string
+	subtitlesOutput      string
+	subtitlesTimeline    string
+	subtitlesVariant     string
+	subtitlesCollections []string
+	subtitlesCueSeconds  float64
+	subtitlesLyrics      bool
+	subtitlesMux         bool
+	subtitlesVideo       string
+)
+
+// subtitlesLyricsPlaceholder fills the rest of each captioned clip when
+// --lyrics-placeholders is set, ready to be replaced by hand.
+const subtitlesLyricsPlaceholder = "♪ [lyrics] ♪"
+
+func newSubtitlesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "subtitles",
+		Short: "Write a caption track with each clip's title and artist",
+		Long: `Write an SRT or ASS subtitle file timed against the concatenated video,
+showing each clip's title and artist as it starts. Handy for accessibility
+and for venues where the TV is muted.
+
+The default output sits next to the concat output with the same name
+(powerhour.srt beside powerhour.mp4) so most players load it automatically.
+With --mux the track is also embedded in the video: MKV keeps the format
+as-is, MP4 and MOV store it as mov_text.
+
+Clip lengths come from ffprobe on the rendered segments, falling back to
+the plan durations for segments that aren't rendered.`,
+		Args: cobra.NoArgs,
+		RunE: runSubtitles,
+	}
+	cmd.Flags().StringVar(&subtitlesFormat, "format", "srt", "Subtitle format: srt or ass")
+	_ = cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"srt", "ass"}, cobra.ShellCompDirectiveNoFileComp))
+	cmd.Flags().StringVarP(&subtitlesOutput, "output", "o", "", "Output file (default <project>/powerhour[-<timeline>][-<variant>].<format>)")
+	cmd.Flags().StringVar(&subtitlesTimeline, "timeline", "", "Caption a named timeline from timelines:")
+	cmd.Flags().StringVar(&subtitlesVariant, "variant", "", "Caption the timeline as assembled with concat --variant")
+	cmd.Flags().StringSliceVar(&subtitlesCollections, "collection", nil, "Only caption clips from these collections (repeatable; default all)")
+	cmd.Flags().Float64Var(&subtitlesCueSeconds, "cue-seconds", 8, "How long each title card stays up (0 keeps it for the whole clip)")
+	cmd.Flags().BoolVar(&subtitlesLyrics, "lyrics-placeholders", false, "Add a placeholder cue for the rest of each captioned clip")
+	cmd.Flags().BoolVar(&subtitlesMux, "mux", false, "Also embed the track in the concatenated video")
+	cmd.Flags().StringVar(&subtitlesVideo, "video", "", "Video to mux into (default: the concat output)")
+	return cmd
+}
+
+type subtitleOptions struct {
+	Collections []string
+	CueSeconds  float64
+	Lyrics      bool
+}
+
+func runSubtitles(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	format := strings.ToLower(strings.TrimSpace(subtitlesFormat))
+	if !slices.Contains(render.SubtitleFormats, format) {
+		return fmt.Errorf("invalid --format %q (choose from %s)", subtitlesFormat, strings.Join(render.SubtitleFormats, ", "))
+	}
+	if subtitlesCueSeconds < 0 {
+		return fmt.Errorf("--cue-seconds must not be negative")
+	}
+	if subtitlesVideo != "" && !subtitlesMux {
+		return fmt.Errorf("--video requires --mux")
+	}
+
+	glogf, gcloser := logx.StartCommand("subtitles")
+	defer gcloser.Close()
+	glogf("subtitles started: format=%s timeline=%s variant=%s mux=%v", format, subtitlesTimeline, subtitlesVariant, subtitlesMux)
+
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
+	cfg, pp, err = applyNamedTimeline(cfg, pp, subtitlesTimeline)
+	if err != nil {
+		return err
+	}
+	if subtitlesVariant != "" {
+		cfg.Timeline, err = cfg.Timeline.WithVariant(subtitlesVariant)
+		if err != nil {
+			return err
+		}
+	}
+	if len(cfg.Collections) == 0 {
+		return fmt.Errorf("no collections configured")
+	}
+	for _, name := range subtitlesCollections {
+		if _, ok := cfg.Collections[name]; !ok {
+			return fmt.Errorf("unknown collection %q", name)
+		}
+	}
+
+	resolver, err := project.NewCollectionResolver(cfg, pp)
+	if err != nil {
+		return err
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		return err
+	}
+	segments, err := render.ResolveTimelineSegments(pp, cfg, collections)
+	if err != nil {
+		return fmt.Errorf("resolve timeline: %w", err)
+	}
+	if len(segments) == 0 {
+		return fmt.Errorf("timeline is empty")
+	}
+	clips, err := resolver.BuildCollectionClips(collections)
+	if err != nil {
+		return err
+	}
+	applySequenceEntryOverrides(cfg, clips)
+
+	cues, total, err := buildSubtitleCues(ctx, findFFprobe(), pp, segments, clips, subtitleOptions{
+		Collections: subtitlesCollections,
+		CueSeconds:  subtitlesCueSeconds,
+		Lyrics:      subtitlesLyrics,
+	})
+	if err != nil {
+		return err
+	}
+	if len(cues) == 0 {
+		return fmt.Errorf("no clips with a title or artist to caption")
+	}
+
+	base := concatOutputBase(subtitlesTimeline, subtitlesVariant)
+	output := subtitlesOutput
+	if output == "" {
+		output = base + "." + format
+	}
+	if !filepath.IsAbs(output) {
+		output = filepath.Join(pp.Root, output)
+	}
+	if err := writeSubtitleFile(output, format, cues, cfg.Video); err != nil {
+		return err
+	}
+	glogf("subtitles written: %s cues=%d total=%.1fs", output, len(cues), total)
+
+	muxed := ""
+	if subtitlesMux {
+		video := subtitlesVideo
+		if video == "" {
+			video = findConcatOutput(pp.Root, base)
+			if video == "" {
+				return fmt.Errorf("no %s.mp4/.mkv/.mov in %s; run `powerhour concat` first or pass --video", base, pp.Root)
+			}
+		}
+		if !filepath.IsAbs(video) {
+			video = filepath.Join(pp.Root, video)
+		}
+		if err := muxSubtitlesInPlace(ctx, video, output); err != nil {
+			return err
+		}
+		muxed = video
+		glogf("subtitles muxed: %s", video)
+	}
+
+	if outputJSON {
+		data, err := json.MarshalIndent(map[string]any{
+			"format":        format,
+			"output":        output,
+			"cues":          len(cues),
+			"total_seconds": total,
+			"muxed_into":    muxed,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	cmd.Printf("Wrote %s (%d cues over %s)\n", relPath(pp.Root, output), len(cues), formatSampleTime(total))
+	if muxed != "" {
+		cmd.Printf("Embedded in %s\n", relPath(pp.Root, muxed))
+	}
+	return nil
+}
+
+func subtitleClipKey(collection string, index, window int) string {
+	return fmt.Sprintf("%s\x00%d\x00%d", collection, index, window)
+}
+
+// buildSubtitleCues walks the timeline segments in order and captions each
+// clip with its title and artist, starting after any preroll. It returns the
+// cues and the timeline's total length. Segment lengths come from ffprobe
+// when the segment is rendered and from the clip's padded duration otherwise.
+func buildSubtitleCues(ctx context.Context, ffprobe string, pp paths.ProjectPaths, segments []render.TimelineSegmentPath, clips []project.CollectionClip, opts subtitleOptions) ([]render.SubtitleCue, float64, error) {
+	byKey := make(map[string]project.Clip, len(clips))
+	for _, cc := range clips {
+		byKey[subtitleClipKey(cc.CollectionName, cc.Clip.Row.Index, cc.Clip.Window)] = cc.Clip
+	}
+
+	var cues []render.SubtitleCue
+	offset := 0.0
+	for i, seg := range segments {
+		clip, ok := byKey[subtitleClipKey(seg.CollectionName, seg.Index, seg.Window)]
+
+		length := 0.0
+		if ffprobe != "" {
+			if _, err := os.Stat(seg.Path); err == nil {
+				if media, err := probeMedia(ctx, ffprobe, seg.Path); err == nil {
+					length = media.DurationSeconds
+				}
+			}
+		}
+		if length <= 0 && ok {
+			length = clip.OutputSeconds()
+		}
+		if length <= 0 {
+			return nil, 0, fmt.Errorf("segment %d (%s): unknown length; render it first or install ffprobe", i+1, relPath(pp.Root, seg.Path))
+		}
+
+		captioned := ok && (len(opts.Collections) == 0 || slices.Contains(opts.Collections, seg.CollectionName))
+		if text := subtitleText(clip.Row); captioned && text != "" {
+			start := offset + clip.PrerollSeconds
+			clipEnd := offset + length - clip.PostrollSeconds
+			end := clipEnd
+			if opts.CueSeconds > 0 && start+opts.CueSeconds < clipEnd {
+				end = start + opts.CueSeconds
+			}
+			if end > start {
+				cues = append(cues, render.SubtitleCue{Start: start, End: end, Text: text})
+			}
+			if opts.Lyrics && clipEnd > end {
+				cues = append(cues, render.SubtitleCue{Start: end, End: clipEnd, Text: subtitlesLyricsPlaceholder})
+			}
+		}
+		offset += length
+	}
+	return cues, offset, nil
+}
+
+// subtitleText is the title on the first line and the artist on the second.
+func subtitleText(row csvplan.Row) string {
+	var lines []string
+	for _, s := range []string{row.Title, row.Artist} {
+		if s = strings.TrimSpace(s); s != "" {
+			lines = append(lines, s)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func writeSubtitleFile(path, format string, cues []render.SubtitleCue, video config.VideoConfig) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("prepare output dir: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", path, err)
+	}
+	if format == "ass" {
+		err = render.WriteASS(f, cues, video.Width, video.Height)
+	} else {
+		err = render.WriteSRT(f, cues)
+	}
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close %s: %w", path, err)
+	}
+	return nil
+}
+
+// findConcatOutput returns the first existing concat output named base in
+// root, trying each container concat can produce.
+func findConcatOutput(root, base string) string {
+	for _, container := range []string{"mp4", "mkv", "mov"} {
+		candidate := filepath.Join(root, base+containerExt(container))
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// muxSubtitlesInPlace embeds subs into video through a temporary file in the
+// same directory, then replaces the video.
+func muxSubtitlesInPlace(ctx context.Context, video, subs string) error {
+	if _, err := os.Stat(video); err != nil {
+		return fmt.Errorf("video %s: %w", video, err)
+	}
+	ext := filepath.Ext(video)
+	tmp := strings.TrimSuffix(video, ext) + ".subtitled" + ext
+	if err := render.MuxSubtitles(ctx, video, subs, tmp, nil, nil); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, video); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("replace %s: %w", video, err)
+	}
+	return nil
+}
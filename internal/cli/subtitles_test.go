@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+	"powerhour/pkg/csvplan"
+)
+
+func TestBuildSubtitleCues(t *testing.T) {
+	segments := []render.TimelineSegmentPath{
+		{CollectionName: "songs", Index: 1, Path: "/missing/001.mp4"},
+		{CollectionName: "drinks", Index: 1, Path: "/missing/drink.mp4"},
+		{CollectionName: "songs", Index: 2, Path: "/missing/002.mp4"},
+	}
+	clips := []project.CollectionClip{
+		{CollectionName: "songs", Clip: project.Clip{Row: csvplan.Row{Index: 1, Title: "Song", Artist: "Band"}, DurationSeconds: 60, PrerollSeconds: 2}},
+		{CollectionName: "drinks", Clip: project.Clip{Row: csvplan.Row{Index: 1, Title: "Drink!"}, DurationSeconds: 5}},
+		{CollectionName: "songs", Clip: project.Clip{Row: csvplan.Row{Index: 2, Title: "Short"}, DurationSeconds: 4}},
+	}
+
+	tests := []struct {
+		name  string
+		opts  subtitleOptions
+		wants []render.SubtitleCue
+	}{
+		{
+			name: "title cards",
+			opts: subtitleOptions{CueSeconds: 8},
+			wants: []render.SubtitleCue{
+				{Start: 2, End: 10, Text: "Song\nBand"},
+				{Start: 62, End: 67, Text: "Drink!"},
+				{Start: 67, End: 71, Text: "Short"},
+			},
+		},
+		{
+			name: "collection filter with lyrics",
+			opts: subtitleOptions{Collections: []string{"songs"}, CueSeconds: 8, Lyrics: true},
+			wants: []render.SubtitleCue{
+				{Start: 2, End: 10, Text: "Song\nBand"},
+				{Start: 10, End: 62, Text: subtitlesLyricsPlaceholder},
+				{Start: 67, End: 71, Text: "Short"},
+			},
+		},
+		{
+			name: "whole clip",
+			opts: subtitleOptions{Collections: []string{"songs"}},
+			wants: []render.SubtitleCue{
+				{Start: 2, End: 62, Text: "Song\nBand"},
+				{Start: 67, End: 71, Text: "Short"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cues, total, err := buildSubtitleCues(context.Background(), "", paths.ProjectPaths{}, segments, clips, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if total != 71 {
+				t.Errorf("total = %v, want 71", total)
+			}
+			if len(cues) != len(tt.wants) {
+				t.Fatalf("cues = %+v, want %+v", cues, tt.wants)
+			}
+			for i := range cues {
+				if cues[i] != tt.wants[i] {
+					t.Errorf("cue %d = %+v, want %+v", i, cues[i], tt.wants[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBuildSubtitleCuesUnknownLength(t *testing.T) {
+	segments := []render.TimelineSegmentPath{{CollectionName: "__inline__", Index: 0, Path: "/missing/intro.mp4"}}
+	if _, _, err := buildSubtitleCues(context.Background(), "", paths.ProjectPaths{}, segments, nil, subtitleOptions{}); err == nil {
+		t.Fatal("expected error for a segment with no known length")
+	}
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
+
+	"powerhour/internal/config"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/telemetry"
+)
+
+const telemetryShutdownTimeout = 10 * time.Second
+
+// withTelemetry wraps a long-running command in a root "powerhour <command>"
+// span when telemetry: or OTEL_EXPORTER_OTLP_ENDPOINT sets a collector, so
+// its resolves, tool runs and segment renders nest under one trace.
+// Spans still export when the command fails.
+func withTelemetry(command string, run func(*cobra.Command, []string) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		var cfg config.TelemetryConfig
+		project := ""
+		if pp, err := paths.Resolve(projectDir); err == nil {
+			project = filepath.Base(pp.Root)
+			// A config that doesn't load fails the run itself.
+			if loaded, err := config.Load(pp.ConfigFile); err == nil {
+				cfg = loaded.Telemetry
+			}
+		}
+		if !telemetry.Enabled(cfg) {
+			return run(cmd, args)
+		}
+
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		glog, closer := logx.Command("telemetry")
+		defer closer.Close()
+		shutdown, err := telemetry.Setup(ctx, cfg, logx.Printf(glog))
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: telemetry: %v\n", err)
+			return run(cmd, args)
+		}
+		defer func() {
+			sctx, cancel := context.WithTimeout(context.Background(), telemetryShutdownTimeout)
+			defer cancel()
+			if err := shutdown(sctx); err != nil {
+				glog.Warn("telemetry export failed", "error", err)
+			}
+		}()
+
+		ctx, span := telemetry.Start(ctx, "powerhour "+command, attribute.String("powerhour.project", project))
+		cmd.SetContext(ctx)
+		err = run(cmd, args)
+		span.End(err)
+		return err
+	}
+}
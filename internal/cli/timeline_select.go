@@ -0,0 +1,21 @@
+package cli
+
+import (
+	"powerhour/internal/config"
+	"powerhour/internal/paths"
+)
+
+// applyNamedTimeline switches cfg and pp to the named timeline from
+// config.Timelines: its sequence replaces cfg.Timeline and its segments,
+// render state, and concat list move to their own paths. An empty name
+// keeps the default timeline.
+func applyNamedTimeline(cfg config.Config, pp paths.ProjectPaths, name string) (config.Config, paths.ProjectPaths, error) {
+	if name == "" {
+		return cfg, pp, nil
+	}
+	named, err := cfg.WithTimeline(name)
+	if err != nil {
+		return cfg, pp, err
+	}
+	return named, paths.ApplyTimeline(pp, name), nil
+}
@@ -0,0 +1,282 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/config"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+)
+
+var (
+	timingTimeline  string
+	timingVariant   string
+	timingTolerance float64
+	timingStrict    bool
+)
+
+// Timing flags for a segment; long and short make it off the grid.
+const (
+	timingFlagLong      = "long"
+	timingFlagShort     = "short"
+	timingFlagUnplanned = "unplanned"
+	timingFlagMissing   = "missing"
+	timingFlagError     = "error"
+)
+
+func newTimingCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "timing",
+		Short: "Compare rendered segment lengths with the planned ones",
+		Long: `Probe every rendered segment in the timeline and compare its real length
+with the planned one: the clip duration plus preroll and postroll.
+
+A power hour keeps its cadence only if every song lasts exactly as long as
+planned. A segment more than --tolerance seconds off is flagged long or
+short, and the DRIFT column shows how far each segment's start has moved
+from the planned grid by then, so small errors that add up are visible
+too. Inline files have no planned length; they are listed with their
+real length but neither flagged nor counted as drift.`,
+		Example: `  powerhour timing
+  powerhour timing --tolerance 0.05 --strict
+  powerhour timing --timeline short --json`,
+		Args: cobra.NoArgs,
+		RunE: runTiming,
+	}
+	cmd.Flags().StringVar(&timingTimeline, "timeline", "", "Check a named timeline from timelines:")
+	cmd.Flags().StringVar(&timingVariant, "variant", "", "Check the timeline as assembled with concat --variant")
+	cmd.Flags().Float64Var(&timingTolerance, "tolerance", 0.1, "Allowed difference from the planned length in seconds")
+	cmd.Flags().BoolVar(&timingStrict, "strict", false, "Exit non-zero when any segment is off by more than the tolerance")
+	return cmd
+}
+
+type timingSegment struct {
+	Slot       int      `json:"slot"`
+	Collection string   `json:"collection,omitempty"`
+	Index      int      `json:"index,omitempty"`
+	Title      string   `json:"title,omitempty"`
+	Path       string   `json:"path"`
+	Planned    float64  `json:"planned_s,omitempty"`
+	Actual     float64  `json:"actual_s,omitempty"`
+	Deviation  float64  `json:"deviation_s"`
+	Drift      float64  `json:"drift_s"` // start offset from the planned grid
+	Flags      []string `json:"flags,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+type timingReport struct {
+	Tolerance  float64         `json:"tolerance_s"`
+	Segments   []timingSegment `json:"segments"`
+	Measured   int             `json:"measured"`
+	OffGrid    int             `json:"off_grid"`
+	TotalDrift float64         `json:"total_drift_s"`
+}
+
+func runTiming(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if timingTolerance < 0 {
+		return fmt.Errorf("--tolerance must not be negative")
+	}
+
+	glogf, gcloser := logx.StartCommand("timing")
+	defer gcloser.Close()
+	glogf("timing started: timeline=%s tolerance=%.3f", timingTimeline, timingTolerance)
+
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
+	cfg, pp, err = applyNamedTimeline(cfg, pp, timingTimeline)
+	if err != nil {
+		return err
+	}
+	if timingVariant != "" {
+		cfg.Timeline, err = cfg.Timeline.WithVariant(timingVariant)
+		if err != nil {
+			return err
+		}
+	}
+	if len(cfg.Collections) == 0 {
+		return fmt.Errorf("no collections configured")
+	}
+
+	resolver, err := project.NewCollectionResolver(cfg, pp)
+	if err != nil {
+		return err
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		return err
+	}
+	segments, err := render.ResolveTimelineSegments(pp, cfg, collections)
+	if err != nil {
+		return fmt.Errorf("resolve timeline: %w", err)
+	}
+	if len(segments) == 0 {
+		return fmt.Errorf("timeline is empty")
+	}
+	clips, err := resolver.BuildCollectionClips(collections)
+	if err != nil {
+		return err
+	}
+	applySequenceEntryOverrides(cfg, clips)
+
+	ffprobe := findFFprobe()
+	if ffprobe == "" {
+		return fmt.Errorf("ffprobe not found; run `powerhour tools install`")
+	}
+	probe := func(path string) (float64, error) {
+		info, err := probeMedia(ctx, ffprobe, path)
+		return info.DurationSeconds, err
+	}
+
+	report := timingReport{Tolerance: timingTolerance}
+	report.Segments = measureTiming(segments, clips, probe)
+	flagTiming(&report)
+	for _, seg := range report.Segments {
+		glogf("segment %d %s: planned=%.3f actual=%.3f flags=%v err=%s", seg.Slot, seg.Path, seg.Planned, seg.Actual, seg.Flags, seg.Error)
+	}
+	glogf("timing finished: measured=%d off_grid=%d drift=%.3f", report.Measured, report.OffGrid, report.TotalDrift)
+
+	if outputJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+	} else {
+		writeTimingTable(cmd, pp.Root, report)
+	}
+
+	if timingStrict && report.OffGrid > 0 {
+		return fmt.Errorf("timing: %d segment(s) off by more than %gs", report.OffGrid, report.Tolerance)
+	}
+	return nil
+}
+
+// measureTiming lists every timeline slot with its planned length, from
+// the clip it renders, and its real length from probe. A segment used in
+// several slots is probed once.
+func measureTiming(segments []render.TimelineSegmentPath, clips []project.CollectionClip, probe func(path string) (float64, error)) []timingSegment {
+	byKey := clipsBySegment(clips)
+	type probed struct {
+		seconds float64
+		err     error
+	}
+	cache := make(map[string]probed, len(segments))
+
+	out := make([]timingSegment, 0, len(segments))
+	for i, seg := range segments {
+		entry := timingSegment{Slot: i + 1, Collection: seg.CollectionName, Index: seg.Index, Path: seg.Path}
+		if clip, ok := byKey[subtitleClipKey(seg.CollectionName, seg.Index, seg.Window)]; ok {
+			entry.Title = clipDisplayTitle(clip)
+			entry.Planned = clip.OutputSeconds()
+		}
+		result, ok := cache[seg.Path]
+		if !ok {
+			if _, err := os.Stat(seg.Path); err != nil {
+				result.err = os.ErrNotExist
+			} else {
+				result.seconds, result.err = probe(seg.Path)
+			}
+			cache[seg.Path] = result
+		}
+		switch {
+		case result.err == os.ErrNotExist:
+			entry.Flags = []string{timingFlagMissing}
+		case result.err != nil:
+			entry.Flags = []string{timingFlagError}
+			entry.Error = result.err.Error()
+		default:
+			entry.Actual = result.seconds
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+// flagTiming fills in each segment's deviation, flags and drift from the
+// planned grid, plus the totals. A segment that couldn't be measured is
+// assumed to run as planned, so it doesn't move the grid.
+func flagTiming(report *timingReport) {
+	report.Measured, report.OffGrid = 0, 0
+	drift := 0.0
+	for i := range report.Segments {
+		seg := &report.Segments[i]
+		seg.Drift = roundMillis(drift)
+		if seg.Actual <= 0 {
+			continue
+		}
+		report.Measured++
+		if seg.Planned <= 0 {
+			seg.Flags = []string{timingFlagUnplanned}
+			continue
+		}
+		seg.Flags = nil
+		seg.Deviation = roundMillis(seg.Actual - seg.Planned)
+		drift += seg.Actual - seg.Planned
+		switch {
+		case seg.Deviation > report.Tolerance:
+			seg.Flags = []string{timingFlagLong}
+		case seg.Deviation < -report.Tolerance:
+			seg.Flags = []string{timingFlagShort}
+		}
+		if len(seg.Flags) > 0 {
+			report.OffGrid++
+		}
+	}
+	report.TotalDrift = roundMillis(drift)
+}
+
+func roundMillis(seconds float64) float64 {
+	return math.Round(seconds*1000) / 1000
+}
+
+func writeTimingTable(cmd *cobra.Command, root string, report timingReport) {
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Tolerance ±%gs\n\n", report.Tolerance)
+
+	w := tabwriter.NewWriter(out, 0, 2, 2, ' ', 0)
+	fmt.Fprintln(w, "SLOT\tSEGMENT\tTITLE\tPLANNED\tACTUAL\tDEV\tDRIFT\tFLAGS")
+	for _, seg := range report.Segments {
+		planned, actual, dev := "-", "-", "-"
+		if seg.Planned > 0 {
+			planned = fmt.Sprintf("%.3f", seg.Planned)
+		}
+		if seg.Actual > 0 {
+			actual = fmt.Sprintf("%.3f", seg.Actual)
+			if seg.Planned > 0 {
+				dev = fmt.Sprintf("%+.3f", seg.Deviation)
+			}
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%+.3f\t%s\n", seg.Slot, relPath(root, seg.Path), truncateString(seg.Title, 30), planned, actual, dev, seg.Drift, strings.Join(seg.Flags, ","))
+	}
+	w.Flush()
+
+	fmt.Fprintf(out, "\n%d segments measured, %d off the grid, %+.3fs drift by the end\n", report.Measured, report.OffGrid, report.TotalDrift)
+	for _, seg := range report.Segments {
+		if seg.Error != "" {
+			fmt.Fprintf(out, "  %s: %s\n", filepath.Base(seg.Path), seg.Error)
+		}
+	}
+}
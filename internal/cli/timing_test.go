@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+	"powerhour/pkg/csvplan"
+)
+
+func TestMeasureTiming(t *testing.T) {
+	dir := t.TempDir()
+	path := func(name string, exists bool) string {
+		p := filepath.Join(dir, name)
+		if exists {
+			if err := os.WriteFile(p, nil, 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return p
+	}
+	one, bumper, broken := path("001.mp4", true), path("bumper.mp4", true), path("003.mp4", true)
+	segments := []render.TimelineSegmentPath{
+		{CollectionName: "songs", Index: 1, Path: one},
+		{Path: bumper},
+		{CollectionName: "songs", Index: 2, Path: path("002.mp4", false)},
+		{CollectionName: "songs", Index: 3, Path: broken},
+		{Path: bumper},
+	}
+	clips := []project.CollectionClip{
+		{CollectionName: "songs", Clip: project.Clip{Row: csvplan.Row{Index: 1, Title: "One"}, DurationSeconds: 60, PrerollSeconds: 0.5}},
+		{CollectionName: "songs", Clip: project.Clip{Row: csvplan.Row{Index: 2, Title: "Two"}, DurationSeconds: 60}},
+	}
+	probes := 0
+	probe := func(p string) (float64, error) {
+		probes++
+		switch p {
+		case broken:
+			return 0, errors.New("moov atom not found")
+		case bumper:
+			return 4, nil
+		}
+		return 60.7, nil
+	}
+
+	got := measureTiming(segments, clips, probe)
+	if probes != 3 {
+		t.Errorf("probed %d times, want 3 (the bumper once)", probes)
+	}
+	want := []timingSegment{
+		{Slot: 1, Collection: "songs", Index: 1, Title: "One", Path: one, Planned: 60.5, Actual: 60.7},
+		{Slot: 2, Path: bumper, Actual: 4},
+		{Slot: 3, Collection: "songs", Index: 2, Title: "Two", Path: segments[2].Path, Planned: 60, Flags: []string{timingFlagMissing}},
+		{Slot: 4, Collection: "songs", Index: 3, Path: broken, Flags: []string{timingFlagError}, Error: "moov atom not found"},
+		{Slot: 5, Path: bumper, Actual: 4},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("measureTiming =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestFlagTiming(t *testing.T) {
+	report := timingReport{
+		Tolerance: 0.1,
+		Segments: []timingSegment{
+			{Slot: 1, Planned: 60, Actual: 60.04},
+			{Slot: 2, Planned: 60, Actual: 60.3},
+			{Slot: 3, Actual: 5},
+			{Slot: 4, Planned: 60, Flags: []string{timingFlagMissing}},
+			{Slot: 5, Planned: 61, Actual: 60.5},
+			{Slot: 6, Planned: 60, Actual: 60},
+		},
+	}
+	flagTiming(&report)
+
+	if report.Measured != 5 || report.OffGrid != 2 {
+		t.Errorf("measured/off grid = %d/%d, want 5/2", report.Measured, report.OffGrid)
+	}
+	if report.TotalDrift != -0.16 {
+		t.Errorf("total drift = %v, want -0.16", report.TotalDrift)
+	}
+	wantFlags := [][]string{nil, {timingFlagLong}, {timingFlagUnplanned}, {timingFlagMissing}, {timingFlagShort}, nil}
+	wantDrift := []float64{0, 0.04, 0.34, 0.34, 0.34, -0.16}
+	for i, seg := range report.Segments {
+		if !reflect.DeepEqual(seg.Flags, wantFlags[i]) {
+			t.Errorf("slot %d flags = %v, want %v", seg.Slot, seg.Flags, wantFlags[i])
+		}
+		if seg.Drift != wantDrift[i] {
+			t.Errorf("slot %d drift = %v, want %v", seg.Slot, seg.Drift, wantDrift[i])
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -24,8 +25,12 @@ import (
 )
 
 var (
-	installVersion string
-	installForce   bool
+	installVersion    string
+	installForce      bool
+	installFromBundle string
+	bundleOutput      string
+	listSizes         bool
+	toolsPruneDryRun  bool
 )
 
 func newToolsCmd() *cobra.Command {
@@ -36,7 +41,14 @@ func newToolsCmd() *cobra.Command {
 
 	cmd.AddCommand(newToolsListCmd())
 	cmd.AddCommand(newToolsInstallCmd())
+	cmd.AddCommand(newToolsBundleCmd())
+	cmd.AddCommand(newToolsPruneCmd())
+	cmd.AddCommand(newToolsUninstallCmd())
+	cmd.AddCommand(newToolsPinCmd())
+	cmd.AddCommand(newToolsUnpinCmd())
+	cmd.AddCommand(newToolsWhichCmd())
 	cmd.AddCommand(newToolsEncodingCmd())
+	cmd.AddCommand(newToolsReprobeCmd())
 
 	return cmd
 }
@@ -47,6 +59,7 @@ func newToolsListCmd() *cobra.Command {
 		Short: "List resolved tool statuses",
 		RunE:  runToolsList,
 	}
+	cmd.Flags().BoolVar(&listSizes, "sizes", false, "Include disk usage of every cached tool version")
 	return cmd
 }
 
@@ -69,8 +82,23 @@ func runToolsList(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
+	var usage tools.CacheUsage
+	if listSizes {
+		usage, err = tools.CacheStats(pinnedToolVersions(cfg))
+		if err != nil {
+			return err
+		}
+	}
+
 	if outputJSON {
-		data, err := json.MarshalIndent(statuses, "", "  ")
+		var payload any = statuses
+		if listSizes {
+			payload = struct {
+				Tools []tools.Status   `json:"tools"`
+				Cache tools.CacheUsage `json:"cache"`
+			}{Tools: statuses, Cache: usage}
+		}
+		data, err := json.MarshalIndent(payload, "", "  ")
 		if err != nil {
 			return fmt.Errorf("encode json: %w", err)
 		}
@@ -79,19 +107,161 @@ func runToolsList(cmd *cobra.Command, _ []string) error {
 	}
 
 	printStatusTable(cmd, statuses)
+	if listSizes {
+		printCacheUsage(cmd, usage)
+	}
 	return nil
 }
 
+// pinnedToolVersions returns the tools.<name>.version pins from the project config.
+func pinnedToolVersions(cfg config.Config) map[string]string {
+	pinned := map[string]string{}
+	for _, name := range tools.InstallableTools() {
+		if v := cfg.ToolVersion(name); v != "" {
+			pinned[name] = v
+		}
+	}
+	return pinned
+}
+
+// knownToolPins returns every version pinned by the current project or a
+// registered one, so pruning for one project keeps the pins of the others.
+// A registered project whose config can't be read fails the lookup rather
+// than having its pins ignored; one whose directory is gone is skipped.
+func knownToolPins(cfg config.Config) (map[string][]string, error) {
+	pins := map[string][]string{}
+	add := func(cfg config.Config) {
+		for name, v := range pinnedToolVersions(cfg) {
+			if !slices.Contains(pins[name], v) {
+				pins[name] = append(pins[name], v)
+			}
+		}
+	}
+	add(cfg)
+	projects, err := paths.LoadProjects()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range projects {
+		pp, err := paths.Resolve(p.Root)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(pp.Root); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		other, err := config.Load(pp.ConfigFile)
+		if err != nil {
+			return nil, fmt.Errorf("read tool pins of project %s: %w", p.Name, err)
+		}
+		add(other)
+	}
+	return pins, nil
+}
+
+func printCacheUsage(cmd *cobra.Command, usage tools.CacheUsage) {
+	bold := lipgloss.NewStyle().Bold(true).Inline(true)
+	faint := lipgloss.NewStyle().Faint(true).Inline(true)
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "  %s %s\n", bold.Render("Tool cache"), faint.Render(usage.Root))
+	for _, v := range usage.Versions {
+		var tags []string
+		if v.Current {
+			tags = append(tags, "current")
+		}
+		if v.Pinned {
+			tags = append(tags, "pinned")
+		}
+		label := ""
+		if len(tags) > 0 {
+			label = faint.Render("(" + strings.Join(tags, ", ") + ")")
+		}
+		fmt.Fprintf(out, "  %-10s %-14s %10s %s\n", v.Tool, v.Version, formatBytes(v.Bytes), label)
+	}
+	fmt.Fprintf(out, "  %-25s %10s\n", "downloads", formatBytes(usage.DownloadsBytes))
+	if usage.StaleBytes > 0 {
+		fmt.Fprintf(out, "  %-25s %10s %s\n", "stale install files", formatBytes(usage.StaleBytes), faint.Render("(run `powerhour tools prune`)"))
+	}
+	fmt.Fprintf(out, "  %-25s %10s\n", bold.Render("total"), formatBytes(usage.TotalBytes))
+}
+
+func newToolsPruneCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove cached tool versions other than the current and pinned ones",
+		RunE:  runToolsPrune,
+	}
+	cmd.Flags().BoolVar(&toolsPruneDryRun, "dry-run", false, "List what would be removed without deleting anything")
+	return cmd
+}
+
+func runToolsPrune(cmd *cobra.Command, _ []string) error {
+	glog, gcloser := logx.Command("tools-prune")
+	glogf := logx.Printf(glog)
+	defer gcloser.Close()
+	glogf("tools prune started: dry_run=%v", toolsPruneDryRun)
+
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+
+	pins, err := knownToolPins(cfg)
+	if err != nil {
+		return err
+	}
+	result, pruneErr := tools.PruneCache(cmd.Context(), pins, toolsPruneDryRun)
+	if pruneErr != nil && len(result.Removed) == 0 {
+		return pruneErr
+	}
+	glogf("tools prune: removed=%d freed=%d", len(result.Removed), result.FreedBytes)
+	if pruneErr != nil {
+		glog.Error("tools prune failed", "error", pruneErr)
+	}
+
+	if outputJSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+		return pruneErr
+	}
+
+	out := cmd.OutOrStdout()
+	if len(result.Removed) == 0 {
+		fmt.Fprintln(out, "Tool cache is already clean.")
+		return nil
+	}
+	verb := "Removed"
+	if toolsPruneDryRun {
+		verb = "Would remove"
+	}
+	for _, path := range result.Removed {
+		fmt.Fprintf(out, "  %s\n", path)
+	}
+	fmt.Fprintf(out, "%s %d item(s), %s\n", verb, len(result.Removed), formatBytes(result.FreedBytes))
+	return pruneErr
+}
+
 func newToolsInstallCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "install [tool|all]",
-		Short: "Install or update managed tools",
-		Args:  cobra.MaximumNArgs(1),
-		RunE:  runToolsInstall,
+		Use:               "install [tool|all]",
+		Short:             "Install or update managed tools",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeToolNamesOrAll,
+		RunE:              runToolsInstall,
 	}
 
 	cmd.Flags().StringVar(&installVersion, "version", "", "Specific version to install when supported")
 	cmd.Flags().BoolVar(&installForce, "force", false, "Reinstall even if a cached copy exists")
+	cmd.Flags().StringVar(&installFromBundle, "from-bundle", "", "Install from an offline bundle created by `tools bundle` instead of downloading")
 
 	return cmd
 }
@@ -121,6 +291,15 @@ func runToolsInstall(cmd *cobra.Command, args []string) error {
 		toolsToInstall = []string{target}
 	}
 
+	if installFromBundle != "" {
+		glogf("installing from bundle %s", installFromBundle)
+		statuses, err := tools.InstallFromBundle(cmd.Context(), installFromBundle, toolsToInstall, tools.InstallOptions{Force: installForce})
+		if err := writeToolStatuses(cmd, statuses); err != nil {
+			return err
+		}
+		return err
+	}
+
 	pp, err := paths.Resolve(projectDir)
 	if err != nil {
 		return err
@@ -139,26 +318,94 @@ func runToolsInstall(cmd *cobra.Command, args []string) error {
 	)
 
 	for _, name := range toolsToInstall {
-		status, err := tools.Install(ctx, name, installVersion, tools.InstallOptions{Force: installForce, Version: installVersion})
+		// An explicit --version wins; otherwise honour the project pin.
+		version := installVersion
+		if version == "" {
+			version = cfg.ToolVersion(name)
+		}
+		status, err := tools.Install(ctx, name, version, tools.InstallOptions{Force: installForce, Version: version})
 		if err != nil {
 			errs = append(errs, fmt.Errorf("%s: %w", name, err))
 		}
 		statuses = append(statuses, status)
 	}
 
+	if err := writeToolStatuses(cmd, statuses); err != nil {
+		return err
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+func writeToolStatuses(cmd *cobra.Command, statuses []tools.Status) error {
 	if outputJSON {
 		data, err := json.MarshalIndent(statuses, "", "  ")
 		if err != nil {
 			return fmt.Errorf("encode json: %w", err)
 		}
 		cmd.Println(string(data))
-	} else {
-		printStatusTable(cmd, statuses)
+		return nil
+	}
+	printStatusTable(cmd, statuses)
+	return nil
+}
+
+func newToolsBundleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "bundle [tool|all]",
+		Short:             "Package resolved tools into an offline install bundle",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeToolNamesOrAll,
+		RunE:              runToolsBundle,
 	}
+	cmd.Flags().StringVarP(&bundleOutput, "output", "o", "powerhour-tools.tar.gz", "Bundle file to write")
+	return cmd
+}
 
-	if len(errs) > 0 {
-		return errors.Join(errs...)
+func runToolsBundle(cmd *cobra.Command, args []string) error {
+	glogf, gcloser := logx.StartCommand("tools-bundle")
+	defer gcloser.Close()
+
+	target := "all"
+	if len(args) == 1 {
+		target = strings.ToLower(args[0])
+	}
+	names := tools.InstallableTools()
+	if target != "all" {
+		def, ok := tools.Definition(target)
+		if !ok {
+			return fmt.Errorf("unknown tool: %s", target)
+		}
+		if !def.Installable {
+			return fmt.Errorf("tool %s cannot be bundled", target)
+		}
+		names = []string{target}
+	}
+	glogf("tools bundle started: tools=%v output=%s", names, bundleOutput)
+
+	manifest, err := tools.CreateBundle(cmd.Context(), bundleOutput, names)
+	if err != nil {
+		return err
 	}
+
+	if outputJSON {
+		data, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Wrote %s (%s)\n", bundleOutput, manifest.Platform)
+	for _, t := range manifest.Tools {
+		fmt.Fprintf(out, "  %-8s %s\n", t.Tool, t.Version)
+	}
+	fmt.Fprintf(out, "Install offline with: powerhour tools install --from-bundle %s\n", bundleOutput)
 	return nil
 }
 
@@ -259,6 +506,72 @@ func runToolsEncoding(cmd *cobra.Command, _ []string) error {
 	return nil
 }
 
+func newToolsReprobeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "reprobe",
+		Short: "Re-probe ffmpeg encoders and refresh the cached encoding profile",
+		RunE:  runToolsReprobe,
+	}
+}
+
+func runToolsReprobe(cmd *cobra.Command, _ []string) error {
+	glogf, gcloser := logx.StartCommand("tools-reprobe")
+	defer gcloser.Close()
+	glogf("tools reprobe started")
+
+	ffmpegPath, err := tools.Lookup("ffmpeg")
+	if err != nil {
+		return fmt.Errorf("locate ffmpeg: %w", err)
+	}
+
+	previous := tools.LastEncodingProfile()
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 2*time.Minute)
+	defer cancel()
+	profile, err := tools.ProbeEncoders(ctx, ffmpegPath)
+	if err != nil {
+		return fmt.Errorf("probe encoders: %w", err)
+	}
+	if err := tools.SaveEncodingProfile(profile); err != nil {
+		return fmt.Errorf("save encoding profile: %w", err)
+	}
+
+	gained, lost := tools.DiffEncoders(previous, &profile)
+	glogf("tools reprobe: gained=%v lost=%v", gained, lost)
+
+	if outputJSON {
+		payload := struct {
+			Profile tools.EncodingProfile `json:"profile"`
+			Gained  []string              `json:"gained"`
+			Lost    []string              `json:"lost"`
+		}{Profile: profile, Gained: gained, Lost: lost}
+		data, err := json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	green := lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Inline(true)
+	red := lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Inline(true)
+	faint := lipgloss.NewStyle().Faint(true).Inline(true)
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Probed %s\n", ffmpegPath)
+	fmt.Fprintf(out, "  selected: %s\n", profile.SelectedCodec)
+	for _, codec := range gained {
+		fmt.Fprintf(out, "  %s\n", green.Render("+ "+codec))
+	}
+	for _, codec := range lost {
+		fmt.Fprintf(out, "  %s\n", red.Render("- "+codec))
+	}
+	if len(gained) == 0 && len(lost) == 0 {
+		fmt.Fprintf(out, "  %s\n", faint.Render("no encoder changes"))
+	}
+	return nil
+}
+
 func printEncodingField(cmd *cobra.Command, name, value string) {
 	if value == "" {
 		value = "(not set)"
@@ -0,0 +1,298 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/config"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/tools"
+)
+
+var toolsUninstallForce bool
+
+// --- tools uninstall ---
+
+func newToolsUninstallCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "uninstall <tool> [version]",
+		Short:             "Remove cached tool versions (all versions when none is given)",
+		Args:              cobra.RangeArgs(1, 2),
+		ValidArgsFunction: completeToolNames,
+		RunE:              runToolsUninstall,
+	}
+	cmd.Flags().BoolVar(&toolsUninstallForce, "force", false, "Remove a version even if the project pins it")
+	return cmd
+}
+
+func runToolsUninstall(cmd *cobra.Command, args []string) error {
+	glogf, gcloser := logx.StartCommand("tools-uninstall")
+	defer gcloser.Close()
+
+	tool := strings.ToLower(args[0])
+	version := ""
+	if len(args) == 2 {
+		version = args[1]
+	}
+	glogf("tools uninstall started: tool=%s version=%s force=%v", tool, version, toolsUninstallForce)
+
+	def, ok := tools.Definition(tool)
+	if !ok {
+		return fmt.Errorf("unknown tool: %s", tool)
+	}
+	if !def.Installable {
+		return fmt.Errorf("tool %s is not managed by powerhour", tool)
+	}
+
+	cfg, _, err := loadToolsProjectConfig()
+	if err != nil {
+		return err
+	}
+	if pin := cfg.ToolVersion(tool); pin != "" && (version == "" || version == pin) && !toolsUninstallForce {
+		return fmt.Errorf("%s %s is pinned in powerhour.yaml; run `powerhour tools unpin %s` or pass --force", tool, pin, tool)
+	}
+
+	result, err := tools.Uninstall(cmd.Context(), tool, version)
+	if err != nil {
+		return err
+	}
+	glogf("tools uninstall: removed=%d freed=%d current=%v", len(result.Removed), result.FreedBytes, result.WasCurrent)
+
+	if outputJSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	out := cmd.OutOrStdout()
+	for _, path := range result.Removed {
+		fmt.Fprintf(out, "  removed %s\n", path)
+	}
+	fmt.Fprintf(out, "Freed %s\n", formatBytes(result.FreedBytes))
+	if result.WasCurrent {
+		fmt.Fprintf(out, "The active %s was removed; commands will use a system copy if one exists. Run `powerhour tools install %s` to reinstall.\n", tool, tool)
+	}
+	return nil
+}
+
+// --- tools pin / unpin ---
+
+func newToolsPinCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "pin <tool> <version>",
+		Short:             "Pin a tool version in the project config and activate it",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeToolNames,
+		RunE:              runToolsPin,
+	}
+}
+
+func runToolsPin(cmd *cobra.Command, args []string) error {
+	glogf, gcloser := logx.StartCommand("tools-pin")
+	defer gcloser.Close()
+
+	tool, version := strings.ToLower(args[0]), strings.TrimSpace(args[1])
+	glogf("tools pin started: tool=%s version=%s", tool, version)
+
+	def, ok := tools.Definition(tool)
+	if !ok {
+		return fmt.Errorf("unknown tool: %s", tool)
+	}
+	if !def.Installable {
+		return fmt.Errorf("tool %s is not managed by powerhour", tool)
+	}
+
+	_, pp, err := loadToolsProjectConfig()
+	if err != nil {
+		return err
+	}
+	if err := requireProjectConfig(pp); err != nil {
+		return err
+	}
+	if err := config.SetToolVersion(pp.ConfigFile, tool, version); err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Pinned %s to %s in %s\n", tool, version, pp.ConfigFile)
+
+	// Switch to the pinned build right away when it is already cached; otherwise
+	// point at install rather than downloading behind the user's back.
+	if _, err := tools.Activate(cmd.Context(), tool, version); err != nil {
+		glogf("tools pin: activate: %v", err)
+		fmt.Fprintf(out, "%s %s is not cached yet; run `powerhour tools install %s` to download it.\n", tool, version, tool)
+		return nil
+	}
+	fmt.Fprintf(out, "Now using cached %s %s\n", tool, version)
+	return nil
+}
+
+func newToolsUnpinCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "unpin <tool>",
+		Short:             "Remove a tool version pin from the project config",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeToolNames,
+		RunE:              runToolsUnpin,
+	}
+}
+
+func runToolsUnpin(cmd *cobra.Command, args []string) error {
+	glogf, gcloser := logx.StartCommand("tools-unpin")
+	defer gcloser.Close()
+
+	tool := strings.ToLower(args[0])
+	glogf("tools unpin started: tool=%s", tool)
+
+	cfg, pp, err := loadToolsProjectConfig()
+	if err != nil {
+		return err
+	}
+	if err := requireProjectConfig(pp); err != nil {
+		return err
+	}
+	pin, ok := cfg.Tools[tool]
+	if !ok || strings.TrimSpace(pin.Version) == "" {
+		cmd.Printf("%s is not pinned\n", tool)
+		return nil
+	}
+	if err := config.SetToolVersion(pp.ConfigFile, tool, ""); err != nil {
+		return err
+	}
+	cmd.Printf("Unpinned %s\n", tool)
+	return nil
+}
+
+// --- tools which ---
+
+type toolResolution struct {
+	Tool       string   `json:"tool"`
+	Source     string   `json:"source,omitempty"`
+	Version    string   `json:"version,omitempty"`
+	Path       string   `json:"path,omitempty"`
+	Pinned     string   `json:"pinned,omitempty"`
+	SystemPath string   `json:"system_path,omitempty"`
+	Cached     []string `json:"cached,omitempty"`
+}
+
+func newToolsWhichCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "which [tool]",
+		Short:             "Show which binary commands will run for each tool (cache vs system)",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeToolNames,
+		RunE:              runToolsWhich,
+	}
+}
+
+func runToolsWhich(cmd *cobra.Command, args []string) error {
+	glogf, gcloser := logx.StartCommand("tools-which")
+	defer gcloser.Close()
+	glogf("tools which started")
+
+	cfg, _, err := loadToolsProjectConfig()
+	if err != nil {
+		return err
+	}
+	ctx := tools.WithMinimums(cmd.Context(), cfg.ToolMinimums())
+	statuses, err := tools.Detect(ctx)
+	if err != nil {
+		return err
+	}
+	usage, err := tools.CacheStats(nil)
+	if err != nil {
+		return err
+	}
+
+	var filter string
+	if len(args) == 1 {
+		filter = strings.ToLower(args[0])
+		if _, ok := tools.Definition(filter); !ok {
+			return fmt.Errorf("unknown tool: %s", filter)
+		}
+	}
+
+	var rows []toolResolution
+	for _, st := range statuses {
+		if filter != "" && st.Tool != filter {
+			continue
+		}
+		r := toolResolution{
+			Tool:       st.Tool,
+			Source:     string(st.Source),
+			Version:    st.Version,
+			Path:       st.Path,
+			Pinned:     cfg.ToolVersion(st.Tool),
+			SystemPath: tools.SystemPath(st.Tool),
+		}
+		for _, v := range usage.Versions {
+			if v.Tool == st.Tool {
+				r.Cached = append(r.Cached, v.Version)
+			}
+		}
+		rows = append(rows, r)
+	}
+
+	if outputJSON {
+		data, err := json.MarshalIndent(rows, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	bold := lipgloss.NewStyle().Bold(true).Inline(true)
+	faint := lipgloss.NewStyle().Faint(true).Inline(true)
+	out := cmd.OutOrStdout()
+	for _, r := range rows {
+		if r.Path == "" {
+			fmt.Fprintf(out, "  %-10s %s\n", bold.Render(r.Tool), faint.Render("(not found)"))
+		} else {
+			fmt.Fprintf(out, "  %-10s %-7s %-14s %s\n", bold.Render(r.Tool), r.Source, r.Version, r.Path)
+		}
+		if r.Pinned != "" {
+			fmt.Fprintf(out, "  %-10s %s\n", "", faint.Render("pinned: "+r.Pinned))
+		}
+		if r.SystemPath != "" && r.SystemPath != r.Path {
+			fmt.Fprintf(out, "  %-10s %s\n", "", faint.Render("system: "+r.SystemPath))
+		}
+		if len(r.Cached) > 0 {
+			fmt.Fprintf(out, "  %-10s %s\n", "", faint.Render("cached: "+strings.Join(r.Cached, ", ")))
+		}
+	}
+	return nil
+}
+
+// loadToolsProjectConfig loads the project config when one is reachable. Tool
+// commands work outside a project, so a missing file yields defaults.
+func loadToolsProjectConfig() (config.Config, paths.ProjectPaths, error) {
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return config.Config{}, pp, err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return config.Config{}, pp, err
+	}
+	return cfg, pp, nil
+}
+
+func requireProjectConfig(pp paths.ProjectPaths) error {
+	exists, err := paths.FileExists(pp.ConfigFile)
+	if err != nil {
+		return fmt.Errorf("check config: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("no powerhour.yaml at %s; pins are stored per project (use --project)", pp.Root)
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/config"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+	"powerhour/internal/tui"
+	"powerhour/internal/upload"
+)
+
+var (
+	uploadTo         string
+	uploadFile       string
+	uploadTimeline   string
+	uploadVariant    string
+	uploadTitle      string
+	uploadPrivacy    string
+	uploadNoChapters bool
+	uploadDryRun     bool
+)
+
+func newUploadCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upload",
+		Short: "Upload the concatenated video to YouTube, S3 or an HTTP endpoint",
+		Long: `Upload the concat output to a backend configured under upload: in
+powerhour.yaml and record where it went in .powerhour/uploads.json.
+
+youtube  resumable upload through the YouTube Data API with an OAuth
+         refresh token; the title defaults to the project name and the
+         description gets a chapter per clip
+s3       single PUT to an S3 bucket or S3-compatible store (up to 5 GiB)
+http     PUT to a URL, e.g. a presigned URL or WebDAV folder
+
+With one backend configured, --to can be left out.`,
+		Args: cobra.NoArgs,
+		RunE: runUpload,
+	}
+	cmd.Flags().StringVar(&uploadTo, "to", "", "Backend: youtube, s3 or http (default: the only one configured)")
+	_ = cmd.RegisterFlagCompletionFunc("to", cobra.FixedCompletions([]string{"youtube", "s3", "http"}, cobra.ShellCompDirectiveNoFileComp))
+	cmd.Flags().StringVar(&uploadFile, "file", "", "Video to upload (default: the concat output)")
+	cmd.Flags().StringVar(&uploadTimeline, "timeline", "", "Upload a named timeline's concat output")
+	cmd.Flags().StringVar(&uploadVariant, "variant", "", "Upload the concat --variant output")
+	cmd.Flags().StringVar(&uploadTitle, "title", "", "Video title (default: upload.youtube.title or the project name)")
+	cmd.Flags().StringVar(&uploadPrivacy, "privacy", "", "YouTube privacy: private, unlisted or public (overrides upload.youtube.privacy)")
+	cmd.Flags().BoolVar(&uploadNoChapters, "no-chapters", false, "Don't add chapters to the YouTube description")
+	cmd.Flags().BoolVar(&uploadDryRun, "dry-run", false, "Show what would be uploaded without sending anything")
+	return cmd
+}
+
+func runUpload(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	glog, gcloser := logx.Command("upload")
+	glogf := logx.Printf(glog)
+	defer gcloser.Close()
+
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
+	cfg, pp, err = applyNamedTimeline(cfg, pp, uploadTimeline)
+	if err != nil {
+		return err
+	}
+	if uploadVariant != "" {
+		cfg.Timeline, err = cfg.Timeline.WithVariant(uploadVariant)
+		if err != nil {
+			return err
+		}
+	}
+
+	name, err := chooseUploadBackend(cfg.Upload, uploadTo)
+	if err != nil {
+		return err
+	}
+	if uploadPrivacy != "" {
+		if name != "youtube" {
+			return fmt.Errorf("--privacy only applies to --to youtube")
+		}
+		yt := *cfg.Upload.YouTube
+		yt.Privacy = uploadPrivacy
+		cfg.Upload.YouTube = &yt
+	}
+	backend, err := upload.New(cfg.Upload, name)
+	if err != nil {
+		return err
+	}
+
+	base := concatOutputBase(uploadTimeline, uploadVariant)
+	file := uploadFile
+	if file == "" {
+		file = findConcatOutput(pp.Root, base)
+		if file == "" {
+			return fmt.Errorf("no %s.mp4/.mkv/.mov in %s; run `powerhour concat` first or pass --file", base, pp.Root)
+		}
+	}
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(pp.Root, file)
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return fmt.Errorf("video %s: %w", file, err)
+	}
+
+	meta := uploadMetadata(cfg.Upload, filepath.Base(pp.Root), file)
+	if name == "youtube" && !uploadNoChapters {
+		chapters, err := uploadChapters(ctx, pp, cfg)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: chapters left out: %v\n", err)
+		}
+		meta.Chapters = chapters
+	}
+
+	if uploadDryRun {
+		cmd.Printf("Would upload %s (%s) to %s\n", relPath(pp.Root, file), formatBytes(info.Size()), name)
+		if name == "youtube" {
+			cmd.Printf("Title: %s\n", meta.Title)
+			if list := upload.ChapterList(meta.Chapters); list != "" {
+				cmd.Printf("Chapters:\n%s\n", list)
+			}
+		}
+		return nil
+	}
+
+	glogf("upload started: backend=%s file=%s size=%d", name, file, info.Size())
+	status := tui.NewStatusWriter(cmd.ErrOrStderr())
+	status.Update(fmt.Sprintf("Uploading %s to %s...", filepath.Base(file), name))
+	url, err := backend.Upload(ctx, file, meta, func(sent, total int64) {
+		status.Update(fmt.Sprintf("Uploading %s to %s... %d%% of %s", filepath.Base(file), name, sent*100/max(total, 1), formatBytes(total)))
+	})
+	status.Stop()
+	if err != nil {
+		glog.Error("upload failed", "error", err)
+		return err
+	}
+	glogf("upload finished: %s", url)
+
+	entry := upload.Entry{
+		Backend:    name,
+		File:       relPath(pp.Root, file),
+		URL:        url,
+		SizeBytes:  info.Size(),
+		UploadedAt: time.Now().UTC(),
+	}
+	if err := upload.Record(pp.UploadsFile, entry); err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: record upload: %v\n", err)
+	}
+
+	if outputJSON {
+		data, err := json.MarshalIndent(entry, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+	cmd.Printf("Uploaded %s to %s: %s\n", entry.File, name, url)
+	return nil
+}
+
+// chooseUploadBackend returns the requested backend, or the only configured
+// one when none was requested.
+func chooseUploadBackend(cfg config.UploadConfig, requested string) (string, error) {
+	if requested = strings.ToLower(strings.TrimSpace(requested)); requested != "" {
+		return requested, nil
+	}
+	backends := cfg.Backends()
+	switch len(backends) {
+	case 0:
+		return "", fmt.Errorf("no upload backend configured; add upload.youtube, upload.s3 or upload.http to powerhour.yaml")
+	case 1:
+		return backends[0], nil
+	default:
+		return "", fmt.Errorf("several upload backends configured (%s); choose one with --to", strings.Join(backends, ", "))
+	}
+}
+
+// uploadMetadata fills the title, description and tags from the flags and
+// upload.youtube, falling back to the project name for the title.
+func uploadMetadata(cfg config.UploadConfig, projectName, file string) upload.Metadata {
+	meta := upload.Metadata{Title: projectName, ContentType: upload.ContentType(file)}
+	if yt := cfg.YouTube; yt != nil {
+		if title := strings.TrimSpace(yt.Title); title != "" {
+			meta.Title = title
+		}
+		meta.Description = yt.Description
+		meta.Tags = yt.Tags
+	}
+	if title := strings.TrimSpace(uploadTitle); title != "" {
+		meta.Title = title
+	}
+	return meta
+}
+
+// uploadChapters puts a chapter at the start of each captioned clip, using
+// the same timing as the subtitles command.
+func uploadChapters(ctx context.Context, pp paths.ProjectPaths, cfg config.Config) ([]upload.Chapter, error) {
+	resolver, err := project.NewCollectionResolver(cfg, pp)
+	if err != nil {
+		return nil, err
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		return nil, err
+	}
+	segments, err := render.ResolveTimelineSegments(pp, cfg, collections)
+	if err != nil {
+		return nil, fmt.Errorf("resolve timeline: %w", err)
+	}
+	clips, err := resolver.BuildCollectionClips(collections)
+	if err != nil {
+		return nil, err
+	}
+	applySequenceEntryOverrides(cfg, clips)
+	cues, _, err := buildSubtitleCues(ctx, findFFprobe(), pp, segments, clips, subtitleOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return chaptersFromCues(cues), nil
+}
+
+func chaptersFromCues(cues []render.SubtitleCue) []upload.Chapter {
+	chapters := make([]upload.Chapter, len(cues))
+	for i, cue := range cues {
+		chapters[i] = upload.Chapter{Start: cue.Start, Title: strings.ReplaceAll(cue.Text, "\n", " - ")}
+	}
+	return chapters
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"powerhour/internal/config"
+	"powerhour/internal/render"
+)
+
+func TestChooseUploadBackend(t *testing.T) {
+	youtube := &config.YouTubeUploadConfig{}
+	s3 := &config.S3UploadConfig{}
+	tests := []struct {
+		name      string
+		cfg       config.UploadConfig
+		requested string
+		want      string
+		wantErr   string
+	}{
+		{name: "none configured", wantErr: "no upload backend configured"},
+		{name: "only one", cfg: config.UploadConfig{S3: s3}, want: "s3"},
+		{name: "several", cfg: config.UploadConfig{YouTube: youtube, S3: s3}, wantErr: "several upload backends configured (youtube, s3)"},
+		{name: "requested", cfg: config.UploadConfig{YouTube: youtube, S3: s3}, requested: "YouTube", want: "youtube"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := chooseUploadBackend(tt.cfg, tt.requested)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("chooseUploadBackend() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestUploadMetadata(t *testing.T) {
+	t.Cleanup(func() { uploadTitle = "" })
+
+	meta := uploadMetadata(config.UploadConfig{}, "party", "/p/powerhour.mkv")
+	if meta.Title != "party" || meta.ContentType != "video/x-matroska" {
+		t.Fatalf("defaults = %+v", meta)
+	}
+
+	cfg := config.UploadConfig{YouTube: &config.YouTubeUploadConfig{Title: "Spring Power Hour", Description: "60 songs", Tags: []string{"power hour"}}}
+	meta = uploadMetadata(cfg, "party", "/p/powerhour.mp4")
+	if meta.Title != "Spring Power Hour" || meta.Description != "60 songs" || len(meta.Tags) != 1 {
+		t.Fatalf("from config = %+v", meta)
+	}
+
+	uploadTitle = "Take Two"
+	if meta = uploadMetadata(cfg, "party", "/p/powerhour.mp4"); meta.Title != "Take Two" {
+		t.Fatalf("--title not applied: %+v", meta)
+	}
+}
+
+func TestChaptersFromCues(t *testing.T) {
+	chapters := chaptersFromCues([]render.SubtitleCue{
+		{Start: 2, End: 60, Text: "Song A\nArtist A"},
+		{Start: 62, End: 120, Text: "Song B"},
+	})
+	if len(chapters) != 2 || chapters[0].Title != "Song A - Artist A" || chapters[1].Start != 62 {
+		t.Fatalf("chapters = %+v", chapters)
+	}
+}
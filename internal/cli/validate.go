@@ -25,6 +25,8 @@ func newValidateCmd() *cobra.Command {
 	cmd.AddCommand(newValidateFilenamesCmd())
 	cmd.AddCommand(newValidateSegmentsCmd())
 	cmd.AddCommand(newValidateCollectionCmd())
+	cmd.AddCommand(newValidateDuplicatesCmd())
+	cmd.AddCommand(newValidatePlanCmd())
 	return cmd
 }
 
@@ -1,15 +1,18 @@
 package cli
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/tabwriter"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 
 	"powerhour/internal/cache"
@@ -17,6 +20,7 @@ import (
 	"powerhour/internal/logx"
 	"powerhour/internal/paths"
 	"powerhour/internal/project"
+	"powerhour/internal/render"
 	"powerhour/pkg/csvplan"
 )
 
@@ -97,12 +101,26 @@ func runValidateCollection(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	// Build validation results
-	results := make([]collectionValidationRow, 0, len(clips))
+	// Build validation results; skipped rows have no clip and are listed as-is.
+	results := make([]collectionValidationRow, 0, len(collection.Rows))
 	for _, collClip := range clips {
 		result := validateCollectionRow(pp, idx, collClip)
 		results = append(results, result)
 	}
+	for _, collRow := range collection.Rows {
+		if !project.RowSkipped(collRow) {
+			continue
+		}
+		results = append(results, collectionValidationRow{
+			Index:        collRow.Index,
+			Link:         collRow.Link,
+			StartTime:    collRow.StartRaw,
+			Duration:     collRow.DurationSeconds,
+			CustomFields: collRow.CustomFields,
+			Status:       "skipped",
+		})
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Index < results[j].Index })
 
 	// Output results
 	if outputJSON {
@@ -129,7 +147,7 @@ func validateCollectionRow(pp paths.ProjectPaths, idx *cache.Index, collClip pro
 	result.ExpectedID = expectedID
 
 	// Resolve cache entry
-	entry, hasEntry, err := resolveEntryForRow(pp, idx, row)
+	entry, hasEntry, err := render.ResolveEntry(pp, idx, row)
 	if err != nil {
 		result.Status = "error"
 		result.Error = err.Error()
@@ -215,11 +233,14 @@ func writeCollectionValidationJSON(cmd *cobra.Command, collectionName string, co
 		Plan       string                      `json:"plan"`
 		Rows       []collectionValidationRow   `json:"rows"`
 		Summary    collectionValidationSummary `json:"summary"`
+		// OrphanedFacts are facts file keys that match no plan row.
+		OrphanedFacts []string `json:"orphaned_facts,omitempty"`
 	}{
-		Collection: collectionName,
-		Plan:       collection.Plan,
-		Rows:       rows,
-		Summary:    buildValidationSummary(rows),
+		Collection:    collectionName,
+		Plan:          collection.Plan,
+		Rows:          rows,
+		Summary:       buildValidationSummary(rows),
+		OrphanedFacts: collection.OrphanedFactKeys(),
 	}
 
 	data, err := json.MarshalIndent(payload, "", "  ")
@@ -236,7 +257,10 @@ func writeCollectionValidationTable(cmd *cobra.Command, collectionName string, c
 	fmt.Fprintf(cmd.OutOrStdout(), "Plan: %s\n", collection.Plan)
 	fmt.Fprintln(cmd.OutOrStdout())
 
-	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 2, 2, ' ', 0)
+	// Lay the table out in a buffer so skipped rows can be dimmed whole-line
+	// without escape codes skewing tabwriter's column widths.
+	var table bytes.Buffer
+	w := tabwriter.NewWriter(&table, 0, 2, 2, ' ', 0)
 	fmt.Fprintln(w, "INDEX\tSTATUS\tCACHE FILE / EXPECTED ID\tSEGMENTS\tOUTPUT\tDATA")
 
 	for _, row := range rows {
@@ -287,10 +311,27 @@ func writeCollectionValidationTable(cmd *cobra.Command, collectionName string, c
 	}
 	w.Flush()
 
+	faint := lipgloss.NewStyle().Faint(true).Inline(true)
+	lines := strings.Split(strings.TrimSuffix(table.String(), "\n"), "\n")
+	for i, line := range lines {
+		if i > 0 && i <= len(rows) && rows[i-1].Status == "skipped" {
+			line = faint.Render(line)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), line)
+	}
+
 	// Print summary
 	summary := buildValidationSummary(rows)
-	fmt.Fprintf(cmd.OutOrStdout(), "\nSummary: %d valid, %d missing, %d errors\n",
+	fmt.Fprintf(cmd.OutOrStdout(), "\nSummary: %d valid, %d missing, %d errors",
 		summary.Valid, summary.Missing, summary.Errors)
+	if summary.Skipped > 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), ", %d skipped", summary.Skipped)
+	}
+	fmt.Fprintln(cmd.OutOrStdout())
+
+	for _, key := range collection.OrphanedFactKeys() {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: facts key %q matches no row index or link in the plan\n", key)
+	}
 }
 
 func formatDynamicData(customFields map[string]string) string {
@@ -327,6 +368,8 @@ func buildValidationSummary(rows []collectionValidationRow) collectionValidation
 			summary.Missing++
 		case "error":
 			summary.Errors++
+		case "skipped":
+			summary.Skipped++
 		}
 	}
 	summary.Total = len(rows)
@@ -354,4 +397,5 @@ type collectionValidationSummary struct {
 	Valid   int `json:"valid"`
 	Missing int `json:"missing"`
 	Errors  int `json:"errors"`
+	Skipped int `json:"skipped,omitempty"`
 }
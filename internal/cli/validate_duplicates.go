@@ -0,0 +1,292 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/config"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+)
+
+var (
+	validateDuplicatesStrict bool
+	validateDuplicatesDedupe bool
+)
+
+func newValidateDuplicatesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "duplicates",
+		Short: "Find sources that appear more than once across all collections",
+		Long: `Scan every collection plan for rows pointing at the same source.
+
+Links are compared by video ID for YouTube (watch, youtu.be, shorts, and
+music URLs all match), by cached identifier when the link has been fetched,
+and by absolute path for local files. Skipped rows are ignored. The first
+occurrence in playback order (timeline order when configured) is kept.
+
+Duplicates are reported as warnings; --strict makes them an error. --dedupe
+marks every later occurrence skip=yes in its plan so it drops out of fetch,
+render, and the timeline without losing the row.`,
+		Args: cobra.NoArgs,
+		RunE: runValidateDuplicates,
+	}
+	cmd.Flags().BoolVar(&validateDuplicatesStrict, "strict", false, "Exit with an error when duplicates are found")
+	cmd.Flags().BoolVar(&validateDuplicatesDedupe, "dedupe", false, "Mark later duplicates as skipped in their plan files")
+	return cmd
+}
+
+type duplicateOccurrence struct {
+	Collection string `json:"collection"`
+	Index      int    `json:"index"`
+	Title      string `json:"title,omitempty"`
+	Link       string `json:"link"`
+}
+
+type duplicateGroup struct {
+	Source      string                `json:"source"`
+	Occurrences []duplicateOccurrence `json:"occurrences"`
+}
+
+func runValidateDuplicates(cmd *cobra.Command, _ []string) error {
+	glogf, gcloser := logx.StartCommand("validate-duplicates")
+	defer gcloser.Close()
+	glogf("validate duplicates started: strict=%v dedupe=%v", validateDuplicatesStrict, validateDuplicatesDedupe)
+
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
+
+	if len(cfg.Collections) == 0 {
+		return fmt.Errorf("no collections configured")
+	}
+
+	resolver, err := project.NewCollectionResolver(cfg, pp)
+	if err != nil {
+		return err
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		return err
+	}
+
+	// Cached identifiers catch differently-shaped links to the same source;
+	// without an index, links are still compared by their normalized form.
+	idx, _ := cache.Load(pp)
+
+	var timeline []project.TimelineEntry
+	if len(cfg.Timeline.Sequence) > 0 {
+		timeline, err = project.ResolveTimeline(cfg.Timeline, collections)
+		if err != nil {
+			return fmt.Errorf("resolve timeline: %w", err)
+		}
+	}
+
+	groups := findDuplicateSources(pp.Root, collections, timeline, idx)
+	glogf("validate duplicates: %d duplicated sources", len(groups))
+
+	var deduped map[string][]int
+	if validateDuplicatesDedupe && len(groups) > 0 {
+		deduped, err = dedupeCollections(collections, groups)
+		if err != nil {
+			return err
+		}
+		glogf("validate duplicates: deduped %v", deduped)
+	}
+
+	if outputJSON {
+		data, err := json.MarshalIndent(struct {
+			Duplicates []duplicateGroup `json:"duplicates"`
+			Skipped    map[string][]int `json:"skipped,omitempty"`
+		}{groups, deduped}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+	} else {
+		printDuplicateGroups(cmd, groups, deduped)
+	}
+
+	if validateDuplicatesStrict && len(groups) > 0 && !validateDuplicatesDedupe {
+		return fmt.Errorf("%d source(s) appear more than once", len(groups))
+	}
+	return nil
+}
+
+// findDuplicateSources groups non-skipped plan rows by source and returns the
+// groups with more than one row. Occurrences are in playback order, with rows
+// the timeline doesn't reach appended in collection/row order.
+func findDuplicateSources(root string, collections map[string]project.Collection, timeline []project.TimelineEntry, idx *cache.Index) []duplicateGroup {
+	order := playbackOrder(collections, timeline)
+	if len(timeline) > 0 {
+		order = append(order, playbackOrder(collections, nil)...)
+	}
+
+	seen := make(map[rowRef]bool, len(order))
+	bySource := make(map[string]int)
+	var groups []duplicateGroup
+	for _, r := range order {
+		if seen[r] {
+			continue
+		}
+		seen[r] = true
+		coll, ok := collections[r.collection]
+		// Single-file collections (intros, interstitials) are meant to repeat.
+		if !ok || coll.Plan == "" || r.index < 1 || r.index > len(coll.Rows) {
+			continue
+		}
+		row := coll.Rows[r.index-1]
+		if project.RowSkipped(row) {
+			continue
+		}
+		key := duplicateSourceKey(root, row.Link, idx)
+		if key == "" {
+			continue
+		}
+		occ := duplicateOccurrence{
+			Collection: r.collection,
+			Index:      r.index,
+			Title:      strings.TrimSpace(row.CustomFields["title"]),
+			Link:       strings.TrimSpace(row.Link),
+		}
+		if i, ok := bySource[key]; ok {
+			groups[i].Occurrences = append(groups[i].Occurrences, occ)
+			continue
+		}
+		bySource[key] = len(groups)
+		groups = append(groups, duplicateGroup{Source: key, Occurrences: []duplicateOccurrence{occ}})
+	}
+
+	out := groups[:0]
+	for _, g := range groups {
+		if len(g.Occurrences) > 1 {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+// duplicateSourceKey reduces a plan link to the identity used for duplicate
+// detection. It returns "" for empty links.
+func duplicateSourceKey(root, link string, idx *cache.Index) string {
+	link = strings.TrimSpace(link)
+	if link == "" {
+		return ""
+	}
+	if !isRemoteLink(link) {
+		if !filepath.IsAbs(link) {
+			link = filepath.Join(root, link)
+		}
+		return "file:" + filepath.Clean(link)
+	}
+	if id := cache.ExtractYouTubeID(link); id != "" {
+		return "youtube:" + id
+	}
+	if identifier, ok := idx.LookupLink(link); ok {
+		return identifier
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	return host + strings.TrimSuffix(u.EscapedPath(), "/") + querySuffix(u.RawQuery)
+}
+
+func querySuffix(q string) string {
+	if q == "" {
+		return ""
+	}
+	return "?" + q
+}
+
+// dedupeCollections marks every occurrence after the first skipped and
+// writes each changed plan. It returns the skipped indexes per collection.
+func dedupeCollections(collections map[string]project.Collection, groups []duplicateGroup) (map[string][]int, error) {
+	later := make(map[string][]int)
+	for _, g := range groups {
+		for _, occ := range g.Occurrences[1:] {
+			later[occ.Collection] = append(later[occ.Collection], occ.Index)
+		}
+	}
+
+	names := make([]string, 0, len(later))
+	for name := range later {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	skipped := make(map[string][]int, len(later))
+	for _, name := range names {
+		coll, changed, err := setCollectionRowsSkipped(collections[name], later[name], true)
+		if err != nil {
+			return skipped, err
+		}
+		if len(changed) == 0 {
+			continue
+		}
+		if err := project.WriteCollectionPlan(coll); err != nil {
+			return skipped, fmt.Errorf("write collection %q plan: %w", name, err)
+		}
+		collections[name] = coll
+		skipped[name] = changed
+	}
+	return skipped, nil
+}
+
+func printDuplicateGroups(cmd *cobra.Command, groups []duplicateGroup, deduped map[string][]int) {
+	if len(groups) == 0 {
+		cmd.Println("No duplicate sources found")
+		return
+	}
+	warn := "Warning"
+	if validateDuplicatesStrict && !validateDuplicatesDedupe {
+		warn = "Error"
+	}
+	cmd.Printf("%s: %d source(s) appear more than once\n", warn, len(groups))
+	for _, g := range groups {
+		cmd.Printf("\n  %s\n", g.Source)
+		for i, occ := range g.Occurrences {
+			label := "keep"
+			if i > 0 {
+				label = "dup "
+			}
+			title := occ.Title
+			if title == "" {
+				title = occ.Link
+			}
+			cmd.Printf("    %s  %s:%d  %s\n", label, occ.Collection, occ.Index, title)
+		}
+	}
+
+	if len(deduped) == 0 {
+		if !validateDuplicatesDedupe {
+			cmd.Println("\nRun with --dedupe to skip the later duplicates.")
+		}
+		return
+	}
+	cmd.Println()
+	names := make([]string, 0, len(deduped))
+	for name := range deduped {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		cmd.Printf("Skipped %d row(s) in %s: %s\n", len(deduped[name]), name, formatIndexList(deduped[name]))
+	}
+}
@@ -0,0 +1,360 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/config"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/pkg/csvplan"
+)
+
+var (
+	validatePlanCollection string
+	validatePlanExplain    bool
+)
+
+func newValidatePlanCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Check that a collection plan loads, and explain how its columns are read",
+		Long: `Load a collection's plan the way fetch and render do and report row errors.
+
+With --explain, list every column in the file and what it is mapped to: the
+link, start or duration column (via link_header/start_header/duration_header),
+a field the config uses (overlays, skip, gain, ...), a custom field, or
+ignored. For CSV/TSV plans the IMPORTER column shows what the permissive
+importer behind convert would pick, marking the columns it guessed from the
+data rather than the header.`,
+		Args: cobra.NoArgs,
+		RunE: runValidatePlan,
+	}
+	cmd.Flags().StringVar(&validatePlanCollection, "collection", "", "Collection whose plan to check (default: the only collection, or songs)")
+	cmd.Flags().BoolVar(&validatePlanExplain, "explain", false, "Print how every column in the file is mapped")
+	return cmd
+}
+
+// planColumnMapping is one column of a plan file in the --explain report.
+type planColumnMapping struct {
+	Position int    `json:"position"`
+	Column   string `json:"column"`
+	// MappedAs is link, start, duration, field, custom, or ignored.
+	MappedAs string `json:"mapped_as"`
+	Detail   string `json:"detail,omitempty"`
+	// Importer is the role or field the permissive importer reads the
+	// column as (CSV/TSV plans only).
+	Importer        string `json:"importer,omitempty"`
+	ImporterGuessed bool   `json:"importer_guessed,omitempty"`
+}
+
+type planValidation struct {
+	Collection string              `json:"collection"`
+	Plan       string              `json:"plan"`
+	Rows       int                 `json:"rows"`
+	Errors     []string            `json:"errors,omitempty"`
+	Columns    []planColumnMapping `json:"columns,omitempty"`
+	Notes      []string            `json:"notes,omitempty"`
+}
+
+func runValidatePlan(cmd *cobra.Command, _ []string) error {
+	glogf, gcloser := logx.StartCommand("validate-plan")
+	defer gcloser.Close()
+	glogf("validate plan started: collection=%s explain=%v", validatePlanCollection, validatePlanExplain)
+
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+
+	if len(cfg.Collections) == 0 {
+		return fmt.Errorf("no collections configured")
+	}
+	configured := make(map[string]project.Collection, len(cfg.Collections))
+	for name, collCfg := range cfg.Collections {
+		configured[name] = project.Collection{Name: name, Config: collCfg}
+	}
+	name, err := pickPlanCollection(configured, validatePlanCollection)
+	if err != nil {
+		return err
+	}
+	collCfg := cfg.Collections[name]
+	if strings.TrimSpace(collCfg.Plan) == "" {
+		return fmt.Errorf("collection %q has no plan", name)
+	}
+	planPath := collCfg.Plan
+	if !filepath.IsAbs(planPath) {
+		planPath = filepath.Join(pp.Root, planPath)
+	}
+
+	report, err := validatePlanFile(name, collCfg, planPath, validatePlanExplain)
+	if err != nil {
+		return err
+	}
+	report.Plan = collCfg.Plan
+	glogf("validate plan: rows=%d errors=%d", report.Rows, len(report.Errors))
+
+	if outputJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+	} else {
+		printPlanValidation(cmd.OutOrStdout(), report)
+	}
+	if len(report.Errors) > 0 {
+		return fmt.Errorf("plan %s has %d error(s)", collCfg.Plan, len(report.Errors))
+	}
+	return nil
+}
+
+// validatePlanFile loads the plan with the collection's options and, when
+// explain is set, maps every column in the file.
+func validatePlanFile(name string, collCfg config.CollectionConfig, planPath string, explain bool) (planValidation, error) {
+	report := planValidation{Collection: name}
+	opts := project.CollectionOptionsForConfig(project.Collection{Config: collCfg})
+	isYAML := csvplan.IsStructuredPlan(planPath)
+
+	var (
+		rows     []csvplan.CollectionRow
+		columns  []string
+		defaults map[string]string
+		loadErr  error
+	)
+	if isYAML {
+		var result csvplan.YAMLResult
+		result, loadErr = csvplan.LoadCollectionStructured(planPath, opts)
+		rows, defaults = result.Rows, result.Defaults
+		columns = yamlPlanColumns(result)
+	} else {
+		rows, loadErr = csvplan.LoadCollection(planPath, opts)
+	}
+	report.Rows = len(rows)
+	var issues csvplan.ValidationErrors
+	switch {
+	case errors.As(loadErr, &issues):
+		for _, issue := range issues.Issues() {
+			report.Errors = append(report.Errors, issue.Error())
+		}
+	case loadErr != nil:
+		report.Errors = append(report.Errors, loadErr.Error())
+	}
+	if !explain {
+		return report, nil
+	}
+
+	var imported []csvplan.ImportColumn
+	if !isYAML {
+		header, err := csvplan.ReadHeaderRow(planPath, collCfg.Sheet)
+		if err != nil {
+			return report, err
+		}
+		columns = header
+		imported, _ = csvplan.ExplainImport(planPath, csvplan.ImportOptions{
+			LinkHeader:     collCfg.LinkHeader,
+			StartHeader:    collCfg.StartHeader,
+			DurationHeader: collCfg.DurationHeader,
+			Sheet:          collCfg.Sheet,
+		})
+	}
+	report.Columns, report.Notes = explainPlanColumns(collCfg, opts, isYAML, columns, defaults, imported)
+	return report, nil
+}
+
+// yamlPlanColumns lists a YAML plan's declared columns followed by any other
+// keys its defaults or rows use.
+func yamlPlanColumns(result csvplan.YAMLResult) []string {
+	columns := slices.Clone(result.Columns)
+	add := func(name string) {
+		if name != "" && !slices.Contains(columns, name) {
+			columns = append(columns, name)
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(result.Defaults)) {
+		add(key)
+	}
+	for _, row := range result.Rows {
+		for _, key := range slices.Sorted(maps.Keys(row.CustomFields)) {
+			add(key)
+		}
+	}
+	return columns
+}
+
+// explainPlanColumns maps each column the way the plan loader reads it and
+// adds notes for the link, start and duration columns it could not find.
+// CSV/TSV plans only read a duration column when duration_header is set;
+// YAML plans default it to "duration".
+func explainPlanColumns(collCfg config.CollectionConfig, opts csvplan.CollectionOptions, isYAML bool, columns []string, defaults map[string]string, imported []csvplan.ImportColumn) ([]planColumnMapping, []string) {
+	type planRole struct {
+		role, setting, configured, header string
+	}
+	roleHeader := func(configured, fallback string) string {
+		return firstNonEmpty(csvplan.NormalizeHeader(configured), fallback)
+	}
+	durationHeader := roleHeader(collCfg.DurationHeader, "duration")
+	if !isYAML && strings.TrimSpace(collCfg.DurationHeader) == "" {
+		durationHeader = ""
+	}
+	roles := []planRole{
+		{csvplan.RoleLink, "link_header", collCfg.LinkHeader, roleHeader(collCfg.LinkHeader, "link")},
+		{csvplan.RoleStart, "start_header", collCfg.StartHeader, roleHeader(collCfg.StartHeader, "start_time")},
+		{csvplan.RoleDuration, "duration_header", collCfg.DurationHeader, durationHeader},
+	}
+	known := map[string]string{}
+	for _, c := range planSchemaColumns(collCfg) {
+		if c.Canonical == "" && !slices.Contains([]string{"link", "start_time", "duration"}, c.Name) {
+			known[c.Name] = c.Description
+		}
+	}
+
+	mappings := make([]planColumnMapping, len(columns))
+	found := map[string]bool{}
+	for i, name := range columns {
+		m := planColumnMapping{Position: i + 1, Column: name}
+		switch {
+		case name == "":
+			m.MappedAs = "ignored"
+			m.Detail = "blank header; the values are dropped"
+		case found[name]:
+			m.MappedAs = "ignored"
+			m.Detail = "duplicate header"
+		default:
+			m.MappedAs = "custom"
+			m.Detail = "kept with the row; nothing in the config reads it"
+			for _, r := range roles {
+				if r.header == "" || name != r.header {
+					continue
+				}
+				m.MappedAs = r.role
+				m.Detail = "default name"
+				if configured := strings.TrimSpace(r.configured); configured != "" {
+					m.Detail = fmt.Sprintf("%s: %s", r.setting, configured)
+				}
+			}
+			if desc, ok := known[name]; ok && m.MappedAs == "custom" {
+				m.MappedAs = "field"
+				m.Detail = desc
+			}
+			if def, ok := defaults[name]; ok {
+				m.Detail += fmt.Sprintf("; plan default %q", def)
+			}
+			found[name] = true
+		}
+		if i < len(imported) {
+			col := imported[i]
+			m.Importer = firstNonEmpty(col.Role, col.Field)
+			m.ImporterGuessed = col.Guessed
+		}
+		mappings[i] = m
+	}
+
+	var notes []string
+	for _, r := range roles {
+		switch {
+		case r.header == "":
+			notes = append(notes, fmt.Sprintf("duration_header is not set, so no column is read as the duration: every row uses the default %ds", opts.DefaultDuration))
+		case found[r.header]:
+			continue
+		case r.role == csvplan.RoleDuration:
+			notes = append(notes, fmt.Sprintf("no %q column (%s): every row uses the default %ds", r.header, r.setting, opts.DefaultDuration))
+		default:
+			notes = append(notes, fmt.Sprintf("no %q column (%s): the plan can't load", r.header, r.setting))
+		}
+		if candidate := roleCandidate(r.role, r.header, mappings, imported); candidate != "" {
+			notes = append(notes, fmt.Sprintf("  column %q looks like the %s column; rename it or set %s: %s", candidate, r.role, r.setting, candidate))
+		}
+	}
+	return mappings, notes
+}
+
+// roleCandidate picks the column that probably holds a role the plan loader
+// missed: the importer's guess, else a custom column whose name mentions
+// the role.
+func roleCandidate(role, header string, mappings []planColumnMapping, imported []csvplan.ImportColumn) string {
+	for _, col := range imported {
+		if col.Role == role && col.Header != "" && col.Header != header {
+			return col.Header
+		}
+	}
+	hints := map[string][]string{
+		csvplan.RoleLink:     {"link", "url", "video", "youtube"},
+		csvplan.RoleStart:    {"start", "begin", "from"},
+		csvplan.RoleDuration: {"duration", "length", "seconds", "secs"},
+	}[role]
+	for _, m := range mappings {
+		if m.MappedAs != "custom" {
+			continue
+		}
+		for _, hint := range hints {
+			if strings.Contains(m.Column, hint) {
+				return m.Column
+			}
+		}
+	}
+	return ""
+}
+
+func printPlanValidation(out io.Writer, report planValidation) {
+	fmt.Fprintf(out, "Collection: %s (plan: %s)\n", report.Collection, report.Plan)
+	fmt.Fprintf(out, "Rows: %d\n", report.Rows)
+
+	if len(report.Columns) > 0 {
+		fmt.Fprintln(out)
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		withImporter := slices.ContainsFunc(report.Columns, func(m planColumnMapping) bool { return m.Importer != "" })
+		if withImporter {
+			fmt.Fprintln(w, "  #\tCOLUMN\tMAPPED AS\tIMPORTER\tDETAIL")
+		} else {
+			fmt.Fprintln(w, "  #\tCOLUMN\tMAPPED AS\tDETAIL")
+		}
+		for _, m := range report.Columns {
+			column := m.Column
+			if column == "" {
+				column = "(blank)"
+			}
+			if withImporter {
+				importer := m.Importer
+				if m.ImporterGuessed {
+					importer += " (guessed)"
+				}
+				fmt.Fprintf(w, "  %d\t%s\t%s\t%s\t%s\n", m.Position, column, m.MappedAs, importer, m.Detail)
+			} else {
+				fmt.Fprintf(w, "  %d\t%s\t%s\t%s\n", m.Position, column, m.MappedAs, m.Detail)
+			}
+		}
+		w.Flush()
+	}
+	if len(report.Notes) > 0 {
+		fmt.Fprintln(out)
+		for _, note := range report.Notes {
+			fmt.Fprintln(out, note)
+		}
+	}
+
+	fmt.Fprintln(out)
+	if len(report.Errors) == 0 {
+		fmt.Fprintln(out, "OK")
+		return
+	}
+	fmt.Fprintf(out, "%d error(s):\n", len(report.Errors))
+	for _, e := range report.Errors {
+		fmt.Fprintf(out, "  %s\n", e)
+	}
+}
@@ -1,6 +1,16 @@
 package cli
 
-import "testing"
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"powerhour/internal/config"
+	"powerhour/internal/project"
+	"powerhour/pkg/csvplan"
+)
 
 func TestMatchTemplateBase(t *testing.T) {
 	tests := []struct {
@@ -29,3 +39,159 @@ func TestMatchTemplateBase(t *testing.T) {
 		})
 	}
 }
+
+func TestDuplicateSourceKey(t *testing.T) {
+	root := "/proj"
+	tests := []struct {
+		name string
+		a, b string
+		same bool
+	}{
+		{"watch vs short link", "https://www.youtube.com/watch?v=abc123&t=30", "https://youtu.be/abc123?si=x", true},
+		{"music vs watch", "https://music.youtube.com/watch?v=abc123", "https://youtube.com/watch?v=abc123", true},
+		{"different videos", "https://youtu.be/abc123", "https://youtu.be/def456", false},
+		{"other host www and slash", "https://www.example.com/clip/", "https://example.com/clip", true},
+		{"relative vs absolute file", "media/song.mp4", "/proj/media/../media/song.mp4", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			a, b := duplicateSourceKey(root, tc.a, nil), duplicateSourceKey(root, tc.b, nil)
+			if (a == b) != tc.same {
+				t.Fatalf("keys %q and %q: same=%v, want %v", a, b, a == b, tc.same)
+			}
+		})
+	}
+	if got := duplicateSourceKey(root, "  ", nil); got != "" {
+		t.Errorf("empty link key = %q, want empty", got)
+	}
+}
+
+func TestFindDuplicateSourcesAndDedupe(t *testing.T) {
+	dir := t.TempDir()
+	newColl := func(name string, links ...string) project.Collection {
+		coll := project.Collection{
+			Name:       name,
+			Plan:       filepath.Join(dir, name+".csv"),
+			PlanFormat: "csv",
+			Delimiter:  ',',
+			Headers:    []string{"title", "link"},
+		}
+		for i, link := range links {
+			fields := map[string]string{"title": fmt.Sprintf("%s-%d", name, i+1), "link": link}
+			if strings.HasPrefix(link, "skip:") {
+				link = strings.TrimPrefix(link, "skip:")
+				fields["link"], fields["skip"] = link, "yes"
+			}
+			coll.Rows = append(coll.Rows, csvplan.CollectionRow{Index: i + 1, Link: link, CustomFields: fields})
+		}
+		return coll
+	}
+	collections := map[string]project.Collection{
+		"songs": newColl("songs",
+			"https://youtu.be/aaa",
+			"https://youtu.be/bbb",
+			"https://www.youtube.com/watch?v=aaa",
+			"skip:https://youtu.be/bbb",
+		),
+		"extras": newColl("extras", "https://youtube.com/watch?v=bbb", "https://youtu.be/ccc"),
+		"intro":  {Name: "intro", Rows: []csvplan.CollectionRow{{Index: 1, Link: "https://youtu.be/ccc"}}},
+	}
+
+	groups := findDuplicateSources(dir, collections, nil, nil)
+	if len(groups) != 2 {
+		t.Fatalf("groups = %+v, want 2", groups)
+	}
+	// Without a timeline, collections are ordered by name: extras before songs.
+	if g := groups[0]; g.Source != "youtube:bbb" || len(g.Occurrences) != 2 ||
+		g.Occurrences[0].Collection != "extras" || g.Occurrences[1].Index != 2 {
+		t.Errorf("first group = %+v", g)
+	}
+	if g := groups[1]; g.Source != "youtube:aaa" || g.Occurrences[0].Index != 1 || g.Occurrences[1].Index != 3 {
+		t.Errorf("second group = %+v", g)
+	}
+
+	timeline := []project.TimelineEntry{{Collection: "songs", Index: 2}, {Collection: "extras", Index: 1}}
+	groups = findDuplicateSources(dir, collections, timeline, nil)
+	if g := groups[0]; g.Source != "youtube:bbb" || g.Occurrences[0].Collection != "songs" {
+		t.Errorf("timeline order should keep songs:2 first, got %+v", g)
+	}
+
+	skipped, err := dedupeCollections(collections, groups)
+	if err != nil {
+		t.Fatalf("dedupe: %v", err)
+	}
+	if got := fmt.Sprint(skipped); got != "map[extras:[1] songs:[3]]" {
+		t.Errorf("skipped = %s", got)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "extras.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "title,link,skip\nextras-1,https://youtube.com/watch?v=bbb,yes\nextras-2,https://youtu.be/ccc,\n"; string(data) != want {
+		t.Errorf("extras.csv =\n%s\nwant\n%s", data, want)
+	}
+	if again := findDuplicateSources(dir, collections, timeline, nil); len(again) != 0 {
+		t.Errorf("duplicates remain after dedupe: %+v", again)
+	}
+}
+
+func TestValidatePlanFileExplain(t *testing.T) {
+	dir := t.TempDir()
+	plan := filepath.Join(dir, "songs.csv")
+	data := "Title,Video URL,start_time,Length,,notes\n" +
+		"One,https://youtu.be/a,0:30,45,x,first\n" +
+		"Two,https://youtu.be/b,1:00,50,y,second\n"
+	if err := os.WriteFile(plan, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	collCfg := config.CollectionConfig{Plan: "songs.csv", LinkHeader: "Video URL"}
+
+	report, err := validatePlanFile("songs", collCfg, plan, true)
+	if err != nil {
+		t.Fatalf("validatePlanFile: %v", err)
+	}
+	if len(report.Errors) != 0 || report.Rows != 2 {
+		t.Fatalf("rows = %d, errors = %v; want 2 rows, no errors", report.Rows, report.Errors)
+	}
+
+	want := []struct{ column, mappedAs string }{
+		{"title", "field"},
+		{"video_url", "link"},
+		{"start_time", "start"},
+		{"length", "custom"},
+		{"", "ignored"},
+		{"notes", "custom"},
+	}
+	if len(report.Columns) != len(want) {
+		t.Fatalf("columns = %+v", report.Columns)
+	}
+	for i, w := range want {
+		got := report.Columns[i]
+		if got.Column != w.column || got.MappedAs != w.mappedAs {
+			t.Errorf("column %d = %q as %q, want %q as %q", i+1, got.Column, got.MappedAs, w.column, w.mappedAs)
+		}
+	}
+	if got := report.Columns[1].Detail; got != "link_header: Video URL" {
+		t.Errorf("link detail = %q", got)
+	}
+	if got := report.Columns[1]; got.Importer != csvplan.RoleLink || got.ImporterGuessed {
+		t.Errorf("importer for video_url = %q (guessed %v), want link from the header", got.Importer, got.ImporterGuessed)
+	}
+
+	notes := strings.Join(report.Notes, "\n")
+	if !strings.Contains(notes, "duration_header is not set") || !strings.Contains(notes, "set duration_header: length") {
+		t.Errorf("notes missing duration hint:\n%s", notes)
+	}
+
+	collCfg.DurationHeader = "Length"
+	report, err = validatePlanFile("songs", collCfg, plan, true)
+	if err != nil {
+		t.Fatalf("validatePlanFile: %v", err)
+	}
+	if got := report.Columns[3].MappedAs; got != "duration" {
+		t.Errorf("with duration_header, length mapped as %q", got)
+	}
+	if len(report.Notes) != 0 {
+		t.Errorf("unexpected notes: %v", report.Notes)
+	}
+}
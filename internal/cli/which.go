@@ -0,0 +1,474 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/config"
+	"powerhour/internal/logx"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+	"powerhour/internal/render/state"
+)
+
+var (
+	whichCollection string
+	whichTimeline   string
+)
+
+// whichMatch is everything known about one plan row.
+type whichMatch struct {
+	Collection string       `json:"collection"`
+	Index      int          `json:"index"`
+	MatchedBy  string       `json:"matched_by"`
+	Title      string       `json:"title,omitempty"`
+	Artist     string       `json:"artist,omitempty"`
+	Link       string       `json:"link"`
+	Start      string       `json:"start"`
+	Duration   int          `json:"duration"`
+	Skipped    bool         `json:"skipped,omitempty"`
+	Source     string       `json:"source,omitempty"`
+	Cache      *whichCache  `json:"cache,omitempty"`
+	Segment    *whichRender `json:"segment,omitempty"`
+}
+
+// whichCache is the cache entry minus the raw probe blobs.
+type whichCache struct {
+	Identifier  string                `json:"identifier"`
+	Source      string                `json:"source"`
+	CachedPath  string                `json:"cached_path"`
+	SizeBytes   int64                 `json:"size_bytes,omitempty"`
+	RetrievedAt time.Time             `json:"retrieved_at"`
+	Title       string                `json:"title,omitempty"`
+	Artist      string                `json:"artist,omitempty"`
+	Duration    float64               `json:"probe_duration_s,omitempty"`
+	Format      string                `json:"probe_format,omitempty"`
+	Streams     []string              `json:"probe_streams,omitempty"`
+	Crop        *cache.CropSuggestion `json:"probe_crop,omitempty"`
+	Color       *cache.ColorInfo      `json:"probe_color,omitempty"`
+}
+
+type whichRender struct {
+	OutputPath string    `json:"output_path"`
+	Exists     bool      `json:"exists"`
+	Status     string    `json:"status"` // "rendered", "stale", "missing"
+	Reason     string    `json:"reason"`
+	RenderedAt time.Time `json:"rendered_at,omitempty"`
+	DurationS  float64   `json:"duration_s,omitempty"`
+	LogPath    string    `json:"log_path"`
+	LogExists  bool      `json:"log_exists"`
+}
+
+func newWhichCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "which <file|index|url>",
+		Short: "Show which plan row a segment, cached file, index, or URL belongs to",
+		Long: `Look up a rendered segment filename, a cached or local source file, a plan
+row index, or a URL, and print everything known about the matching rows:
+collection and row, cache entry and probe data, render state, the segment's
+output path, and its render log.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runWhich,
+	}
+	cmd.Flags().StringVar(&whichCollection, "collection", "", "Only look in this collection")
+	cmd.Flags().StringVar(&whichTimeline, "timeline", "", "Report segments of a named timeline from timelines:")
+	return cmd
+}
+
+func runWhich(cmd *cobra.Command, args []string) error {
+	query := strings.TrimSpace(args[0])
+	glogf, gcloser := logx.StartCommand("which")
+	defer gcloser.Close()
+	glogf("which started: %s", query)
+
+	pp, err := paths.Resolve(projectDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(pp.ConfigFile)
+	if err != nil {
+		return err
+	}
+	pp = paths.ApplyConfig(pp, cfg)
+	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
+	cfg, pp, err = applyNamedTimeline(cfg, pp, whichTimeline)
+	if err != nil {
+		return err
+	}
+	if len(cfg.Collections) == 0 {
+		return fmt.Errorf("no collections configured")
+	}
+	if whichCollection != "" {
+		if _, ok := cfg.Collections[whichCollection]; !ok {
+			return fmt.Errorf("collection %q not found in configuration", whichCollection)
+		}
+	}
+
+	resolver, err := project.NewCollectionResolver(cfg, pp)
+	if err != nil {
+		return fmt.Errorf("create collection resolver: %w", err)
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		return fmt.Errorf("load collections: %w", err)
+	}
+	idx, err := cache.Load(pp)
+	if err != nil {
+		return fmt.Errorf("load cache index: %w", err)
+	}
+	rs, err := state.Load(pp.RenderStateFile)
+	if err != nil {
+		return fmt.Errorf("load render state: %w", err)
+	}
+
+	clips, segmentList, err := buildProjectSegments(pp, cfg, idx, resolver, collections)
+	if err != nil {
+		return err
+	}
+	segments := make(map[string]render.Segment, len(clips))
+	for i, cc := range clips {
+		segments[whichKey(cc.CollectionName, cc.Clip.Row.Index)] = segmentList[i]
+	}
+
+	matches := findWhichMatches(query, whichCollection, pp, cfg, idx, rs, collections, segments)
+	glogf("which: %d matches", len(matches))
+
+	var orphan *whichCache
+	if len(matches) == 0 {
+		if entry, ok := lookupCacheEntry(query, idx); ok {
+			orphan = summarizeCacheEntry(entry)
+		}
+	}
+
+	if outputJSON {
+		payload := struct {
+			Query   string       `json:"query"`
+			Matches []whichMatch `json:"matches"`
+			Cache   *whichCache  `json:"cache,omitempty"`
+		}{query, matches, orphan}
+		data, err := json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	out := cmd.OutOrStdout()
+	if orphan != nil {
+		fmt.Fprintf(out, "%s is in the cache but no plan row uses it.\n\n", query)
+		printWhichCache(out, orphan)
+		return nil
+	}
+	if len(matches) == 0 {
+		return fmt.Errorf("nothing in the project matches %q", query)
+	}
+	for i, m := range matches {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		printWhichMatch(out, m)
+	}
+	return nil
+}
+
+// buildProjectSegments builds every clip's segment the way render does,
+// with sequence entry overrides applied, without checking sources.
+func buildProjectSegments(pp paths.ProjectPaths, cfg config.Config, idx *cache.Index, resolver *project.CollectionResolver, collections map[string]project.Collection) ([]project.CollectionClip, []render.Segment, error) {
+	clips, err := resolver.BuildCollectionClips(collections)
+	if err != nil {
+		return nil, nil, err
+	}
+	applySequenceEntryOverrides(cfg, clips)
+	segments := make([]render.Segment, len(clips))
+	for i, cc := range clips {
+		segments[i], _ = render.BuildCollectionSegment(pp, cfg, idx, cc)
+	}
+	return clips, segments, nil
+}
+
+func whichKey(collection string, index int) string {
+	return collection + "#" + strconv.Itoa(index)
+}
+
+// findWhichMatches walks every plan row and keeps those the query names:
+// a bare number is a row index, a URL is compared by link and cache
+// identifier, and anything else is a file compared by base name against
+// the row's segment, log, cached file, and local source.
+func findWhichMatches(query, only string, pp paths.ProjectPaths, cfg config.Config, idx *cache.Index, rs *state.RenderState, collections map[string]project.Collection, segments map[string]render.Segment) []whichMatch {
+	index, indexErr := strconv.Atoi(query)
+	isIndex := indexErr == nil
+	isURL := isWhichURL(query)
+	queryKey := ""
+	if isURL {
+		queryKey, _ = idx.LookupLink(query)
+	}
+	queryStem := strings.TrimSuffix(filepath.Base(query), filepath.Ext(query))
+
+	names := make([]string, 0, len(collections))
+	for name := range collections {
+		if only == "" || name == only {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var matches []whichMatch
+	for _, name := range names {
+		for _, collRow := range collections[name].Rows {
+			row := collRow.ToRow()
+			entry, hasEntry, _ := render.ResolveEntry(pp, idx, row)
+			seg, hasSeg := segments[whichKey(name, row.Index)]
+
+			matchedBy := ""
+			switch {
+			case isIndex:
+				if row.Index == index {
+					matchedBy = "index"
+				}
+			case isURL:
+				link := strings.TrimSpace(row.Link)
+				if link == query {
+					matchedBy = "link"
+				} else if key, ok := idx.LookupLink(link); ok && queryKey != "" && key == queryKey {
+					matchedBy = "cache identifier"
+				}
+			default:
+				stem := func(path string) string {
+					return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+				}
+				switch {
+				case hasSeg && stem(seg.OutputPath) == queryStem:
+					matchedBy = "segment"
+				case hasEntry && stem(entry.CachedPath) == queryStem:
+					matchedBy = "cached file"
+				case !isWhichURL(row.Link) && stem(row.Link) == queryStem:
+					matchedBy = "source file"
+				}
+			}
+			if matchedBy == "" {
+				continue
+			}
+
+			m := whichMatch{
+				Collection: name,
+				Index:      row.Index,
+				MatchedBy:  matchedBy,
+				Title:      row.Title,
+				Artist:     row.Artist,
+				Link:       row.Link,
+				Start:      row.StartRaw,
+				Duration:   row.DurationSeconds,
+				Skipped:    project.RowSkipped(collRow),
+			}
+			if hasEntry {
+				m.Cache = summarizeCacheEntry(entry)
+			}
+			if hasSeg {
+				// Show what render sees, after row and entry overrides.
+				m.Title, m.Artist = seg.Clip.Row.Title, seg.Clip.Row.Artist
+				m.Start = seg.Clip.Row.StartRaw
+				m.Duration = seg.Clip.DurationSeconds
+				m.Source = seg.SourcePath
+				m.Segment = describeSegment(pp, cfg, rs, seg)
+			}
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
+
+func isWhichURL(link string) bool {
+	link = strings.TrimSpace(link)
+	return strings.HasPrefix(link, "http://") || strings.HasPrefix(link, "https://") || strings.HasPrefix(link, "youtu")
+}
+
+// lookupCacheEntry finds a cache entry by link, identifier, or cached file
+// name, for queries no plan row matches.
+func lookupCacheEntry(query string, idx *cache.Index) (cache.Entry, bool) {
+	if key, ok := idx.LookupLink(query); ok {
+		if entry, ok := idx.GetByIdentifier(key); ok {
+			return entry, true
+		}
+	}
+	if entry, ok := idx.GetByIdentifier(query); ok {
+		return entry, true
+	}
+	base := filepath.Base(query)
+	for _, entry := range idx.Entries {
+		if entry.CachedPath != "" && filepath.Base(entry.CachedPath) == base {
+			return entry, true
+		}
+	}
+	return cache.Entry{}, false
+}
+
+func summarizeCacheEntry(entry cache.Entry) *whichCache {
+	c := &whichCache{
+		Identifier:  entry.Identifier,
+		Source:      entry.Source,
+		CachedPath:  entry.CachedPath,
+		SizeBytes:   entry.SizeBytes,
+		RetrievedAt: entry.RetrievedAt,
+		Title:       entry.Title,
+		Artist:      entry.Artist,
+	}
+	if entry.Probe != nil {
+		c.Duration = entry.Probe.DurationSeconds
+		c.Format = entry.Probe.FormatName
+		c.Streams = summarizeProbeStreams(entry.Probe.Streams)
+		c.Crop = entry.Probe.Crop
+		if color := entry.Probe.Color(); color != (cache.ColorInfo{}) {
+			c.Color = &color
+		}
+	}
+	return c
+}
+
+// summarizeProbeStreams renders ffprobe streams as short labels such as
+// "video h264 1920x1080" or "audio aac 2ch".
+func summarizeProbeStreams(raw json.RawMessage) []string {
+	var streams []struct {
+		CodecType string `json:"codec_type"`
+		CodecName string `json:"codec_name"`
+		Width     int    `json:"width"`
+		Height    int    `json:"height"`
+		Channels  int    `json:"channels"`
+	}
+	if len(raw) == 0 || json.Unmarshal(raw, &streams) != nil {
+		return nil
+	}
+	var labels []string
+	for _, s := range streams {
+		label := strings.TrimSpace(s.CodecType + " " + s.CodecName)
+		switch {
+		case s.Width > 0 && s.Height > 0:
+			label += fmt.Sprintf(" %dx%d", s.Width, s.Height)
+		case s.Channels > 0:
+			label += fmt.Sprintf(" %dch", s.Channels)
+		}
+		labels = append(labels, label)
+	}
+	return labels
+}
+
+func describeSegment(pp paths.ProjectPaths, cfg config.Config, rs *state.RenderState, seg render.Segment) *whichRender {
+	base := strings.TrimSuffix(filepath.Base(seg.OutputPath), filepath.Ext(seg.OutputPath))
+	r := &whichRender{
+		OutputPath: seg.OutputPath,
+		LogPath:    filepath.Join(pp.LogsDir, base+".log"),
+	}
+	_, err := os.Stat(seg.OutputPath)
+	r.Exists = err == nil
+	_, err = os.Stat(r.LogPath)
+	r.LogExists = err == nil
+	prior, rendered := rs.Segments[seg.OutputPath]
+	if rendered {
+		r.RenderedAt = prior.RenderedAt
+		r.DurationS = prior.DurationS
+	}
+
+	switch {
+	case !rendered:
+		r.Status, r.Reason = "missing", state.ReasonNew
+	case !r.Exists:
+		r.Status, r.Reason = "missing", state.ReasonOutputMissing
+	default:
+		action := state.DetectChanges(rs, []render.Segment{seg}, cfg, cfg.SegmentFilenameTemplate(), false)[0]
+		r.Status, r.Reason = "stale", action.Reason
+		if action.Action == state.ActionSkip {
+			r.Status = "rendered"
+		}
+	}
+	return r
+}
+
+func printWhichMatch(out io.Writer, m whichMatch) {
+	bold := lipgloss.NewStyle().Bold(true).Inline(true)
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Inline(true)
+
+	heading := fmt.Sprintf("%s #%03d", m.Collection, m.Index)
+	if m.Title != "" || m.Artist != "" {
+		heading += " — " + strings.Trim(m.Title+" / "+m.Artist, " /")
+	}
+	fmt.Fprintln(out, bold.Render(heading)+" "+dim.Render("(matched by "+m.MatchedBy+")"))
+	fmt.Fprintf(out, "  %-10s %s\n", "Link:", m.Link)
+	fmt.Fprintf(out, "  %-10s %s for %ds\n", "Clip:", m.Start, m.Duration)
+	if m.Skipped {
+		fmt.Fprintf(out, "  %-10s %s\n", "Skipped:", "yes (not rendered or placed on the timeline)")
+	}
+	if m.Source != "" {
+		fmt.Fprintf(out, "  %-10s %s\n", "Source:", m.Source)
+	}
+
+	if m.Cache != nil {
+		fmt.Fprintln(out)
+		printWhichCache(out, m.Cache)
+	} else if isWhichURL(m.Link) {
+		fmt.Fprintf(out, "  %-10s %s\n", "Cache:", "not cached; run powerhour fetch")
+	}
+
+	if seg := m.Segment; seg != nil {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, bold.Render("Render"))
+		fmt.Fprintf(out, "  %-10s %s\n", "Output:", seg.OutputPath)
+		status := seg.Status
+		if seg.Status != "rendered" {
+			status += " (" + seg.Reason + ")"
+		}
+		fmt.Fprintf(out, "  %-10s %s\n", "Status:", status)
+		if !seg.RenderedAt.IsZero() {
+			fmt.Fprintf(out, "  %-10s %s, %.1fs\n", "Rendered:", seg.RenderedAt.Local().Format(time.DateTime), seg.DurationS)
+		}
+		logLine := seg.LogPath
+		if !seg.LogExists {
+			logLine += " (not written yet)"
+		}
+		fmt.Fprintf(out, "  %-10s %s\n", "Log:", logLine)
+	}
+}
+
+func printWhichCache(out io.Writer, c *whichCache) {
+	bold := lipgloss.NewStyle().Bold(true).Inline(true)
+	fmt.Fprintln(out, bold.Render("Cache"))
+	fmt.Fprintf(out, "  %-10s %s\n", "Entry:", c.Identifier)
+	fmt.Fprintf(out, "  %-10s %s\n", "File:", c.CachedPath)
+	if c.SizeBytes > 0 {
+		fmt.Fprintf(out, "  %-10s %s\n", "Size:", formatBytes(c.SizeBytes))
+	}
+	if !c.RetrievedAt.IsZero() {
+		fmt.Fprintf(out, "  %-10s %s\n", "Fetched:", c.RetrievedAt.Local().Format(time.DateTime))
+	}
+	if c.Title != "" || c.Artist != "" {
+		fmt.Fprintf(out, "  %-10s %s\n", "Metadata:", strings.Trim(c.Title+" / "+c.Artist, " /"))
+	}
+	if c.Format != "" || c.Duration > 0 {
+		fmt.Fprintf(out, "  %-10s %s, %s\n", "Probe:", c.Format, formatSampleTime(c.Duration))
+	}
+	for _, s := range c.Streams {
+		fmt.Fprintf(out, "  %-10s %s\n", "", s)
+	}
+	if c.Crop != nil {
+		fmt.Fprintf(out, "  %-10s %dx%d+%d+%d of %dx%d\n", "Crop:", c.Crop.Width, c.Crop.Height, c.Crop.X, c.Crop.Y, c.Crop.SourceWidth, c.Crop.SourceHeight)
+	}
+	if c.Color != nil {
+		hdr := ""
+		if c.Color.HDR() {
+			hdr = " (HDR)"
+		}
+		fmt.Fprintf(out, "  %-10s matrix %s, transfer %s, primaries %s, range %s%s\n", "Color:",
+			firstNonEmpty(c.Color.Space, "-"), firstNonEmpty(c.Color.Transfer, "-"), firstNonEmpty(c.Color.Primaries, "-"), firstNonEmpty(c.Color.Range, "-"), hdr)
+	}
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/config"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+	"powerhour/internal/render/state"
+	"powerhour/pkg/csvplan"
+)
+
+func TestFindWhichMatches(t *testing.T) {
+	pp, _ := paths.Resolve(t.TempDir())
+	rs, _ := state.Load(filepath.Join(pp.Root, "missing.json"))
+
+	idx := &cache.Index{}
+	idx.SetEntry(cache.Entry{Identifier: "youtube:bbb", CachedPath: "/cache/bbb.webm", Source: "https://youtu.be/bbb"})
+	idx.SetLink("https://youtu.be/bbb", "youtube:bbb")
+	idx.SetLink("https://www.youtube.com/watch?v=bbb", "youtube:bbb")
+
+	row := func(index int, link string) csvplan.CollectionRow {
+		return csvplan.CollectionRow{Index: index, Link: link, StartRaw: "0:30", DurationSeconds: 60, CustomFields: map[string]string{}}
+	}
+	collections := map[string]project.Collection{
+		"songs":         {Name: "songs", Rows: []csvplan.CollectionRow{row(1, "https://youtu.be/aaa"), row(2, "https://youtu.be/bbb")}},
+		"interstitials": {Name: "interstitials", Rows: []csvplan.CollectionRow{row(2, "clips/drink.mp4")}},
+	}
+	segments := map[string]render.Segment{
+		whichKey("songs", 2): {
+			OutputPath: filepath.Join(pp.SegmentsDir, "songs", "002_two.mp4"),
+			Clip:       project.Clip{Row: csvplan.Row{Index: 2, StartRaw: "0:45"}, DurationSeconds: 40},
+		},
+	}
+
+	tests := []struct {
+		query string
+		only  string
+		want  []string // collection#index:matched_by
+	}{
+		{"2", "", []string{"interstitials#2:index", "songs#2:index"}},
+		{"002", "songs", []string{"songs#2:index"}},
+		{"https://youtu.be/bbb", "", []string{"songs#2:link"}},
+		{"https://www.youtube.com/watch?v=bbb", "", []string{"songs#2:cache identifier"}},
+		{"002_two.mp4", "", []string{"songs#2:segment"}},
+		{"logs/002_two.log", "", []string{"songs#2:segment"}},
+		{"drink.mp4", "", []string{"interstitials#2:source file"}},
+		{"nothing.mp4", "", nil},
+	}
+	for _, tt := range tests {
+		matches := findWhichMatches(tt.query, tt.only, pp, config.Config{}, idx, rs, collections, segments)
+		var got []string
+		for _, m := range matches {
+			got = append(got, whichKey(m.Collection, m.Index)+":"+m.MatchedBy)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%q: got %v, want %v", tt.query, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%q: got %v, want %v", tt.query, got, tt.want)
+				break
+			}
+		}
+	}
+
+	matches := findWhichMatches("002_two.mp4", "", pp, config.Config{}, idx, rs, collections, segments)
+	m := matches[0]
+	if m.Start != "0:45" || m.Duration != 40 {
+		t.Errorf("match should report the rendered clip window, got start=%s duration=%d", m.Start, m.Duration)
+	}
+	if m.Cache == nil || m.Cache.CachedPath != "/cache/bbb.webm" {
+		t.Errorf("cache = %+v, want the bbb entry", m.Cache)
+	}
+	if m.Segment == nil || m.Segment.Status != "missing" || m.Segment.LogPath != filepath.Join(pp.LogsDir, "002_two.log") {
+		t.Errorf("segment = %+v, want missing with log under logs/", m.Segment)
+	}
+}
@@ -3,8 +3,10 @@ package config
 import (
 	"errors"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -19,27 +21,324 @@ type OverlayEntry struct {
 
 // CollectionConfig defines a collection of clips with configurable CSV headers.
 type CollectionConfig struct {
-	Plan           string         `yaml:"plan"`
-	File           string         `yaml:"file,omitempty"`
-	Duration       int            `yaml:"duration,omitempty"`
-	OutputDir      string         `yaml:"output_dir"`
-	Fade           float64        `yaml:"fade,omitempty"`
-	FadeIn         float64        `yaml:"fade_in,omitempty"`
-	FadeOut        float64        `yaml:"fade_out,omitempty"`
+	Plan      string  `yaml:"plan"`
+	File      string  `yaml:"file,omitempty"`
+	Duration  int     `yaml:"duration,omitempty"`
+	OutputDir string  `yaml:"output_dir"`
+	Fade      float64 `yaml:"fade,omitempty"`
+	FadeIn    float64 `yaml:"fade_in,omitempty"`
+	FadeOut   float64 `yaml:"fade_out,omitempty"`
+	// FadeCurve shapes the audio fades: "tri" (linear, the default),
+	// "qsin" or "exp". Video fades are always linear.
+	FadeCurve string `yaml:"fade_curve,omitempty"`
+	// AudioFadeIn and AudioFadeOut fade the audio apart from the picture,
+	// such as a hard cut with a short audio fade against pops. Unset
+	// follows the video fades; 0 turns the audio fade off.
+	AudioFadeIn  *float64 `yaml:"audio_fade_in,omitempty"`
+	AudioFadeOut *float64 `yaml:"audio_fade_out,omitempty"`
+	// Preroll and Postroll pad every segment with seconds of black (or the
+	// first/last frame when PadMode is "freeze") plus silence, for players
+	// that drop the first frames of each file.
+	Preroll  float64 `yaml:"preroll,omitempty"`
+	Postroll float64 `yaml:"postroll,omitempty"`
+	PadMode  string  `yaml:"pad_mode,omitempty"`
+	// Loop repeats a source shorter than its clip, video and audio, until
+	// the clip is filled instead of ending the segment early. A row's loop
+	// column overrides it.
+	Loop bool `yaml:"loop,omitempty"`
+	// PadShort fills out a source that ends before its clip does: "freeze"
+	// holds the last frame, "black" shows black, both over silence, and
+	// "error" fails the render. Empty leaves the segment short.
+	PadShort       string         `yaml:"pad_short,omitempty"`
 	Overlays       []OverlayEntry `yaml:"overlays,omitempty"`
 	LinkHeader     string         `yaml:"link_header"`
 	StartHeader    string         `yaml:"start_header"`
 	DurationHeader string         `yaml:"duration_header"`
+	// StrictHeaders makes `convert --collection` require the exact link and
+	// start headers instead of guessing columns from their contents.
+	StrictHeaders bool `yaml:"strict_headers,omitempty"`
+	// Sheet names the worksheet to read when Plan is an .xlsx workbook;
+	// empty reads the first sheet.
+	Sheet string `yaml:"sheet,omitempty"`
+	// Overrides is an optional YAML file of per-row tweaks keyed by row
+	// index or link, applied on top of the plan without rewriting it.
+	Overrides string `yaml:"overrides,omitempty"`
+	// Facts is an optional YAML file of per-row trivia, submitter names and
+	// dares keyed like Overrides. They fill the trivia, submitted_by and
+	// dare columns for overlays and show on the party page.
+	Facts string `yaml:"facts,omitempty"`
+	// OutputTemplate names each segment relative to the segments directory,
+	// replacing OutputDir and outputs.segment_template for this collection.
+	// It may contain "/" to nest segments, and accepts the segment template
+	// tokens plus CollectionTemplateTokens.
+	OutputTemplate string `yaml:"output_template,omitempty"`
 	// FieldMap describes how yt-dlp metadata fields back this collection's
 	// canonical columns. Keys are collection columns ("title", "artist",
 	// "link"); values are ordered lists of cache entry fields consulted to
 	// fill that column. When unset, DefaultCollectionFieldMap is used.
 	FieldMap map[string][]string `yaml:"field_map,omitempty"`
+	// Generator builds the collection's clips from config instead of a plan
+	// or file. GeneratorSlate renders an opening slate from Slate;
+	// GeneratorCards draws drink cards from Cards, one clip per card.
+	Generator string      `yaml:"generator,omitempty"`
+	Slate     SlateConfig `yaml:"slate,omitempty"`
+	Cards     CardsConfig `yaml:"cards,omitempty"`
+	// AudioCue mixes a sound over every clip in the collection; a sequence
+	// entry's audio_cue replaces it for the rows that entry places.
+	AudioCue *AudioCueConfig `yaml:"audio_cue,omitempty"`
 }
 
+// CollectionTemplateTokens are the tokens output_template adds to the segment
+// template tokens: the collection name, its filename-safe form, and the
+// overlay profile (the first overlay type, or "none").
+var CollectionTemplateTokens = []string{"COLLECTION", "SAFE_COLLECTION", "PROFILE"}
+
+// IsGenerator reports whether the collection is generated rather than
+// sourced from a plan or file.
+func (c CollectionConfig) IsGenerator() bool {
+	return strings.TrimSpace(c.Generator) != ""
+}
+
+// Collection generators for CollectionConfig.Generator.
+const (
+	GeneratorSlate = "slate"
+	GeneratorCards = "cards"
+)
+
+// DefaultSlateDuration is the slate length in seconds when the collection
+// sets no duration.
+const DefaultSlateDuration = 20
+
+// DefaultSlateCreditsField is the plan column read for credits when
+// SlateConfig.CreditsField is unset.
+const DefaultSlateCreditsField = "submitted_by"
+
+// SlateConfig describes a generated opening slate: a title card with the
+// date and house rules, followed by scrolling credits for who picked each
+// song.
+type SlateConfig struct {
+	Title string   `yaml:"title,omitempty"`
+	Date  string   `yaml:"date,omitempty"`
+	Rules []string `yaml:"rules,omitempty"`
+	// CreditsFrom names the collection credited, in plan order. Each row
+	// with a CreditsField value becomes one line formatted by
+	// CreditsTemplate, which takes overlay-style {field} tokens (default
+	// "{title} - {<credits_field>}").
+	CreditsFrom     string `yaml:"credits_from,omitempty"`
+	CreditsField    string `yaml:"credits_field,omitempty"`
+	CreditsTemplate string `yaml:"credits_template,omitempty"`
+	// Background is an ffmpeg color (default black); FontColor and Font
+	// style all slate text like the overlay presets do.
+	Background string `yaml:"background,omitempty"`
+	FontColor  string `yaml:"font_color,omitempty"`
+	Font       string `yaml:"font,omitempty"`
+}
+
+// CreditsFieldName returns the credits column, defaulting to
+// DefaultSlateCreditsField.
+func (s SlateConfig) CreditsFieldName() string {
+	if f := strings.TrimSpace(s.CreditsField); f != "" {
+		return f
+	}
+	return DefaultSlateCreditsField
+}
+
+// Drink card defaults: clip length in seconds and cards drawn when the
+// collection sets neither.
+const (
+	DefaultCardDuration = 10
+	DefaultCardCount    = 10
+)
+
+// CardsConfig describes generated drink cards, each showing one rule drawn
+// at random by weight. Draws are seeded, so the same config always gives
+// the same cards; change Seed to reshuffle.
+type CardsConfig struct {
+	Count int        `yaml:"count,omitempty"` // default: DefaultCardCount
+	Rules []CardRule `yaml:"rules,omitempty"`
+	// NoRepeatWithin keeps a rule from coming back until this many other
+	// cards have been drawn.
+	NoRepeatWithin int   `yaml:"no_repeat_within,omitempty"`
+	Seed           int64 `yaml:"seed,omitempty"`
+	// Title heads every card (default "Drink card"). Background, FontColor
+	// and Font style the card like the slate's.
+	Title      string `yaml:"title,omitempty"`
+	Background string `yaml:"background,omitempty"`
+	FontColor  string `yaml:"font_color,omitempty"`
+	Font       string `yaml:"font,omitempty"`
+}
+
+// CardRule is one rule a drink card can show. A rule given as a plain
+// string has weight 1 and no use limit.
+type CardRule struct {
+	Text    string  `yaml:"text"`
+	Weight  float64 `yaml:"weight,omitempty"`   // relative odds; default 1
+	MaxUses int     `yaml:"max_uses,omitempty"` // 0: unlimited
+}
+
+// UnmarshalYAML accepts a rule as a bare string or a mapping.
+func (r *CardRule) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		r.Text = value.Value
+		return nil
+	}
+	type plain CardRule
+	return value.Decode((*plain)(r))
+}
+
+// RuleWeight returns the rule's weight, defaulting to 1 when unset.
+func (r CardRule) RuleWeight() float64 {
+	if r.Weight <= 0 {
+		return 1
+	}
+	return r.Weight
+}
+
+// Validate checks the card settings. Whether Count cards can be drawn
+// under the use limits is checked when the cards are drawn.
+func (c CardsConfig) Validate() error {
+	if len(c.Rules) == 0 {
+		return fmt.Errorf("at least one rule is required")
+	}
+	if c.Count < 0 {
+		return fmt.Errorf("count cannot be negative")
+	}
+	if c.NoRepeatWithin < 0 {
+		return fmt.Errorf("no_repeat_within cannot be negative")
+	}
+	for i, rule := range c.Rules {
+		switch {
+		case strings.TrimSpace(rule.Text) == "":
+			return fmt.Errorf("rule %d: text is required", i+1)
+		case rule.Weight < 0 || math.IsNaN(rule.Weight) || math.IsInf(rule.Weight, 0):
+			return fmt.Errorf("rule %d: weight must be a positive number", i+1)
+		case rule.MaxUses < 0:
+			return fmt.Errorf("rule %d: max_uses cannot be negative", i+1)
+		}
+	}
+	return nil
+}
+
+// CardCount returns the number of cards to draw, defaulting to
+// DefaultCardCount.
+func (c CardsConfig) CardCount() int {
+	if c.Count > 0 {
+		return c.Count
+	}
+	return DefaultCardCount
+}
+
+// AudioCueConfig mixes a short sound, such as an air horn or a DJ drop,
+// over a clip. The music is ducked under the cue with a sidechain
+// compressor so the cue cuts through.
+type AudioCueConfig struct {
+	File string `yaml:"file" json:"file"`
+	// Offset is seconds into the clip, after any preroll, where the cue
+	// starts.
+	Offset float64 `yaml:"offset,omitempty" json:"offset,omitempty"`
+	// Volume scales the cue (default 1).
+	Volume float64 `yaml:"volume,omitempty" json:"volume,omitempty"`
+	// Ratio, Threshold, Attack and Release tune the ducking; see
+	// DefaultCueRatio and friends. Attack and release are milliseconds.
+	Ratio     float64 `yaml:"ratio,omitempty" json:"ratio,omitempty"`
+	Threshold float64 `yaml:"threshold,omitempty" json:"threshold,omitempty"`
+	Attack    float64 `yaml:"attack,omitempty" json:"attack,omitempty"`
+	Release   float64 `yaml:"release,omitempty" json:"release,omitempty"`
+}
+
+// Audio cue ducking defaults: the music drops by up to the ratio once the
+// cue passes the threshold, and recovers over the release.
+const (
+	DefaultCueVolume    = 1.0
+	DefaultCueRatio     = 8.0
+	DefaultCueThreshold = 0.03
+	DefaultCueAttack    = 20.0
+	DefaultCueRelease   = 400.0
+)
+
+// Validate checks the cue values against the ranges ffmpeg accepts.
+func (c AudioCueConfig) Validate() error {
+	switch {
+	case strings.TrimSpace(c.File) == "":
+		return fmt.Errorf("file is required")
+	case c.Offset < 0:
+		return fmt.Errorf("offset cannot be negative")
+	case c.Volume < 0:
+		return fmt.Errorf("volume cannot be negative")
+	case c.Ratio != 0 && (c.Ratio < 1 || c.Ratio > 20):
+		return fmt.Errorf("ratio must be between 1 and 20")
+	case c.Threshold < 0 || c.Threshold > 1:
+		return fmt.Errorf("threshold must be between 0 and 1")
+	case c.Attack < 0 || c.Attack > 2000:
+		return fmt.Errorf("attack must be between 0 and 2000 ms")
+	case c.Release < 0 || c.Release > 9000:
+		return fmt.Errorf("release must be between 0 and 9000 ms")
+	}
+	return nil
+}
+
+// Segment padding modes for CollectionConfig.PadMode.
+const (
+	PadModeBlack  = "black"
+	PadModeFreeze = "freeze"
+)
+
+// Audio fade curves for CollectionConfig.FadeCurve, named after ffmpeg's
+// afade curves.
+const (
+	FadeCurveTri  = "tri"
+	FadeCurveQsin = "qsin"
+	FadeCurveExp  = "exp"
+)
+
+// ValidFadeCurve reports whether curve is empty or a known FadeCurve*.
+func ValidFadeCurve(curve string) bool {
+	switch strings.ToLower(strings.TrimSpace(curve)) {
+	case "", FadeCurveTri, FadeCurveQsin, FadeCurveExp:
+		return true
+	}
+	return false
+}
+
+// Short source handling for CollectionConfig.PadShort.
+const (
+	PadShortFreeze = "freeze"
+	PadShortBlack  = "black"
+	PadShortError  = "error"
+)
+
 // TimelineConfig defines the playback sequence for the power hour.
 type TimelineConfig struct {
 	Sequence []SequenceEntry `yaml:"sequence"`
+	// TargetDurationS is the intended total runtime in seconds (e.g. 3600);
+	// 0 disables the budget. Fit optionally adjusts the timeline toward it:
+	// "trim" drops the last clips of the largest collection, "interleave"
+	// rescales interleave every. ToleranceS is the deviation allowed before
+	// status and doctor warn (default 30).
+	TargetDurationS int    `yaml:"target_duration_s,omitempty"`
+	Fit             string `yaml:"fit,omitempty"`
+	ToleranceS      int    `yaml:"tolerance_s,omitempty"`
+	// Locale renders this timeline in another language than the project's
+	// (see Config.Locale). Only named timelines use it.
+	Locale string `yaml:"locale,omitempty"`
+}
+
+// Timeline fit modes for TimelineConfig.Fit.
+const (
+	FitTrim       = "trim"
+	FitInterleave = "interleave"
+)
+
+// DefaultTimelineToleranceS is the runtime deviation allowed when
+// TimelineConfig.ToleranceS is unset.
+const DefaultTimelineToleranceS = 30
+
+// ToleranceSeconds returns the configured tolerance or the default.
+func (t TimelineConfig) ToleranceSeconds() float64 {
+	if t.ToleranceS > 0 {
+		return float64(t.ToleranceS)
+	}
+	return DefaultTimelineToleranceS
 }
 
 // SequenceEntry defines how a single collection or inline file appears in the timeline.
@@ -52,8 +351,39 @@ type SequenceEntry struct {
 	Fade       float64           `yaml:"fade,omitempty"`
 	FadeIn     float64           `yaml:"fade_in,omitempty"`
 	FadeOut    float64           `yaml:"fade_out,omitempty"`
+	FadeCurve  string            `yaml:"fade_curve,omitempty"` // overrides the collection's fade_curve
+	// AudioFadeIn and AudioFadeOut override the collection's audio fades
+	// for the rows this entry places, or fade an inline file's audio.
+	AudioFadeIn  *float64 `yaml:"audio_fade_in,omitempty"`
+	AudioFadeOut *float64 `yaml:"audio_fade_out,omitempty"`
+	// Duration and Overlays override the collection's settings for the rows
+	// this entry places (duration wins over each row's own duration column).
+	// Overlays apply on top of the collection's overlays; see MergeOverlays.
+	// Only valid with Collection.
+	Duration int            `yaml:"duration,omitempty"`
+	Overlays []OverlayEntry `yaml:"overlays,omitempty"`
+	// Pick samples rows from the slice instead of taking them in plan order:
+	// "random", "weighted" (per-row weight column), or "tagged" (rows whose
+	// tags column matches Tags, then weighted). Only valid with Collection.
+	Pick  string   `yaml:"pick,omitempty"`
+	Count int      `yaml:"count,omitempty"` // rows to pick; 0 picks the whole pool
+	Seed  int64    `yaml:"seed,omitempty"`  // same seed and pool give the same picks
+	Tags  []string `yaml:"tags,omitempty"`  // required for pick: tagged
+	// Variants maps a variant name to the collection (or, for file entries,
+	// the file) that replaces this entry when assembling with --variant.
+	Variants map[string]string `yaml:"variants,omitempty"`
+	// AudioCue replaces the collection's audio cue for the rows this entry
+	// places. Only valid with Collection.
+	AudioCue *AudioCueConfig `yaml:"audio_cue,omitempty"`
 }
 
+// Selection strategies for SequenceEntry.Pick.
+const (
+	PickRandom   = "random"
+	PickWeighted = "weighted"
+	PickTagged   = "tagged"
+)
+
 // ResolveFade computes effective fade-in and fade-out durations from the three
 // fade fields. fade is a shorthand that splits evenly; individual values
 // override the split when set.
@@ -70,6 +400,71 @@ func ResolveFade(fade, fadeIn, fadeOut float64) (in, out float64) {
 	return
 }
 
+// HasAudioFade reports whether either audio fade is set apart from the
+// video fades.
+func (e SequenceEntry) HasAudioFade() bool {
+	return e.AudioFadeIn != nil || e.AudioFadeOut != nil
+}
+
+// MergeOverlays layers override on top of base. An override entry with the
+// same preset type as a base entry replaces that entry's options key by key;
+// custom entries and new types are appended. A "none" entry drops everything
+// inherited from base.
+func MergeOverlays(base, override []OverlayEntry) []OverlayEntry {
+	if len(override) == 0 {
+		return base
+	}
+	merged := make([]OverlayEntry, 0, len(base)+len(override))
+	for _, entry := range override {
+		if strings.TrimSpace(entry.Type) == "none" {
+			base = nil
+			break
+		}
+	}
+	for _, entry := range base {
+		entry.Options = copyOverlayOptions(entry.Options)
+		merged = append(merged, entry)
+	}
+	for _, entry := range override {
+		typeName := strings.TrimSpace(entry.Type)
+		if typeName == "none" {
+			continue
+		}
+		matched := false
+		if typeName != "custom" {
+			for i := range merged {
+				if strings.TrimSpace(merged[i].Type) != typeName {
+					continue
+				}
+				if merged[i].Options == nil {
+					merged[i].Options = make(map[string]string, len(entry.Options))
+				}
+				for k, v := range entry.Options {
+					merged[i].Options[k] = v
+				}
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			entry.Options = copyOverlayOptions(entry.Options)
+			merged = append(merged, entry)
+		}
+	}
+	return merged
+}
+
+func copyOverlayOptions(opts map[string]string) map[string]string {
+	if opts == nil {
+		return nil
+	}
+	out := make(map[string]string, len(opts))
+	for k, v := range opts {
+		out[k] = v
+	}
+	return out
+}
+
 // InterleaveConfig describes how to splice a second collection into a sequence entry.
 type InterleaveConfig struct {
 	Collection string `yaml:"collection"`
@@ -125,12 +520,16 @@ type EncodingConfig struct {
 
 // Config captures the rendering and overlay configuration for a project.
 type Config struct {
-	Version         int                         `yaml:"version"`
+	Version int `yaml:"version"`
+	// Extends lists the base configs merged beneath this one (see
+	// resolveExtends). Load fills it from the `extends:` key.
+	Extends         []string                    `yaml:"extends,omitempty"`
 	Video           VideoConfig                 `yaml:"video"`
 	Audio           AudioConfig                 `yaml:"audio"`
 	CollectionFiles []string                    `yaml:"collection_files,omitempty"`
 	Collections     map[string]CollectionConfig `yaml:"collections"`
 	Timeline        TimelineConfig              `yaml:"timeline"`
+	Timelines       map[string]TimelineConfig   `yaml:"timelines,omitempty"` // named alternates, selected with --timeline
 	Outputs         OutputConfig                `yaml:"outputs"`
 	Plan            PlanConfig                  `yaml:"plan"`
 	Files           FileOverrides               `yaml:"files"`
@@ -140,6 +539,16 @@ type Config struct {
 	Library         LibraryConfig               `yaml:"library"`
 	SegmentsBaseDir string                      `yaml:"segments_base_dir"`
 	Encoding        EncodingConfig              `yaml:"encoding,omitempty"`
+	Render          RenderConfig                `yaml:"render,omitempty"`
+	Hooks           HooksConfig                 `yaml:"hooks,omitempty"`
+	Notify          NotifyConfig                `yaml:"notify,omitempty"`
+	Upload          UploadConfig                `yaml:"upload,omitempty"`
+	Stream          StreamConfig                `yaml:"stream,omitempty"`
+	Telemetry       TelemetryConfig             `yaml:"telemetry,omitempty"`
+	// Locale picks translated overlay options and plan columns: with "es",
+	// text_es replaces an overlay's text and a title_es column the title.
+	// A named timeline's own locale takes precedence.
+	Locale string `yaml:"locale,omitempty"`
 }
 
 // CacheConfig controls how cache metadata is displayed and searched in the TUI.
@@ -167,6 +576,9 @@ func DefaultCollectionFieldMap() map[string][]string {
 		"title":  {"title", "track"},
 		"artist": {"artist", "uploader", "channel"},
 		"link":   {"source", "links"},
+		// Only used when a collection declares license/attribution columns.
+		"license":     {"license"},
+		"attribution": {"uploader", "channel"},
 	}
 }
 
@@ -197,6 +609,9 @@ type ToolPin struct {
 	MinimumVersion string `yaml:"minimum_version"`
 	Proxy          string `yaml:"proxy"`
 	SourceAddress  string `yaml:"source_address"`
+	// AutoUpdate enables periodic upgrades of the managed binary during fetch:
+	// "daily", "weekly", or "off" (default). Ignored when Version is pinned.
+	AutoUpdate string `yaml:"auto_update,omitempty"`
 }
 
 // DownloadsConfig controls caching/downloading behaviour.
@@ -204,6 +619,153 @@ type DownloadsConfig struct {
 	FilenameTemplate string `yaml:"filename_template"`
 }
 
+// RenderConfig tunes segment rendering. None of it changes what plays, so
+// it isn't part of the render state's config hash.
+type RenderConfig struct {
+	Retries *int  `yaml:"retries,omitempty"` // extra attempts after a transient ffmpeg failure; default 2
+	Threads int   `yaml:"threads,omitempty"` // encoder and filter threads per ffmpeg; 0 lets ffmpeg decide
+	Nice    int   `yaml:"nice,omitempty"`    // 1-19 lowers ffmpeg's CPU priority (below normal or idle on Windows)
+	Tags    *bool `yaml:"tags,omitempty"`    // title/artist/album/track tags on each segment; default true
+}
+
+// DefaultRenderRetries is used when render.retries is unset.
+const DefaultRenderRetries = 2
+
+// RetryCount returns how many times a transiently failed segment is retried.
+func (r RenderConfig) RetryCount() int {
+	if r.Retries == nil {
+		return DefaultRenderRetries
+	}
+	return max(*r.Retries, 0)
+}
+
+// TagsEnabled reports whether segments get container tags.
+func (r RenderConfig) TagsEnabled() bool {
+	return r.Tags == nil || *r.Tags
+}
+
+// HooksConfig lists shell commands run at pipeline events. Each command
+// runs in the project root with the event as JSON on stdin.
+type HooksConfig struct {
+	PreFetch    []string `yaml:"pre_fetch,omitempty"`    // before fetch downloads anything; a failure aborts the fetch
+	PostSegment []string `yaml:"post_segment,omitempty"` // after each segment render, successful or not
+	PostConcat  []string `yaml:"post_concat,omitempty"`  // after concat writes the final video
+}
+
+// NotifyConfig sends a message when fetch, render or concat finishes or
+// fails. The webhook URL and SMTP password may use ${NAME} secret
+// references.
+type NotifyConfig struct {
+	Desktop    bool              `yaml:"desktop,omitempty"`
+	Webhook    string            `yaml:"webhook,omitempty"` // Slack or Discord incoming webhook URL
+	Email      NotifyEmailConfig `yaml:"email,omitempty"`
+	Commands   []string          `yaml:"commands,omitempty"`    // default: fetch, render, concat
+	MinSeconds int               `yaml:"min_seconds,omitempty"` // skip runs shorter than this
+}
+
+// NotifyEmailConfig sends notifications over SMTP.
+type NotifyEmailConfig struct {
+	To       []string `yaml:"to,omitempty"`
+	From     string   `yaml:"from,omitempty"`
+	SMTP     string   `yaml:"smtp,omitempty"` // host:port
+	Username string   `yaml:"username,omitempty"`
+	Password string   `yaml:"password,omitempty"`
+}
+
+// NotifyCommands are the commands notify: can report on.
+var NotifyCommands = []string{"fetch", "render", "concat", "run"}
+
+// Enabled reports whether any notification channel is configured.
+func (n NotifyConfig) Enabled() bool {
+	return n.Desktop || strings.TrimSpace(n.Webhook) != "" || len(n.Email.To) > 0
+}
+
+// NotifiesFor reports whether a finished run of command should notify.
+func (n NotifyConfig) NotifiesFor(command string) bool {
+	if !n.Enabled() {
+		return false
+	}
+	if len(n.Commands) == 0 {
+		return slices.Contains(NotifyCommands, command)
+	}
+	return slices.Contains(n.Commands, command)
+}
+
+// UploadConfig configures the upload command's backends. Credentials may use
+// ${NAME} secret references.
+type UploadConfig struct {
+	YouTube *YouTubeUploadConfig `yaml:"youtube,omitempty"`
+	S3      *S3UploadConfig      `yaml:"s3,omitempty"`
+	HTTP    *HTTPUploadConfig    `yaml:"http,omitempty"`
+}
+
+// YouTubeUploadConfig uploads through the YouTube Data API with an OAuth
+// refresh token.
+type YouTubeUploadConfig struct {
+	ClientID     string   `yaml:"client_id"`
+	ClientSecret string   `yaml:"client_secret"`
+	RefreshToken string   `yaml:"refresh_token"`
+	Title        string   `yaml:"title,omitempty"`       // default: project directory name
+	Description  string   `yaml:"description,omitempty"` // chapters are appended
+	Tags         []string `yaml:"tags,omitempty"`
+	CategoryID   string   `yaml:"category_id,omitempty"` // default: 10 (Music)
+	Privacy      string   `yaml:"privacy,omitempty"`     // private, unlisted (default) or public
+}
+
+// S3UploadConfig uploads to an S3 bucket or an S3-compatible store.
+type S3UploadConfig struct {
+	Bucket          string `yaml:"bucket"`
+	Region          string `yaml:"region,omitempty"`   // default: us-east-1
+	Endpoint        string `yaml:"endpoint,omitempty"` // for S3-compatible stores; path-style addressing
+	Prefix          string `yaml:"prefix,omitempty"`   // key prefix, e.g. "parties/"
+	AccessKeyID     string `yaml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key"`
+	SessionToken    string `yaml:"session_token,omitempty"`
+	PublicURL       string `yaml:"public_url,omitempty"` // base URL recorded instead of the bucket URL
+}
+
+// HTTPUploadConfig PUTs the file to a URL. {file} in the URL is replaced
+// with the file name.
+type HTTPUploadConfig struct {
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+}
+
+// UploadBackends are the upload backend names, in preference order.
+var UploadBackends = []string{"youtube", "s3", "http"}
+
+// Backends returns the configured upload backends.
+func (u UploadConfig) Backends() []string {
+	var names []string
+	if u.YouTube != nil {
+		names = append(names, "youtube")
+	}
+	if u.S3 != nil {
+		names = append(names, "s3")
+	}
+	if u.HTTP != nil {
+		names = append(names, "http")
+	}
+	return names
+}
+
+// StreamConfig configures the stream command. URL may use ${NAME} secret
+// references, which keeps the stream key out of the file.
+type StreamConfig struct {
+	URL          string `yaml:"url,omitempty"`           // rtmp(s)://, srt:// or ndi:<source name>
+	VideoBitrate string `yaml:"video_bitrate,omitempty"` // default: 4500k
+	AudioBitrate string `yaml:"audio_bitrate,omitempty"` // default: 160k
+}
+
+// TelemetryConfig exports OpenTelemetry traces and metrics of fetch, render
+// and concat runs over OTLP/HTTP. The standard OTEL_EXPORTER_OTLP_*
+// variables work too and enable export without an endpoint here.
+type TelemetryConfig struct {
+	Endpoint    string            `yaml:"endpoint,omitempty"`     // collector base URL, e.g. http://localhost:4318
+	Headers     map[string]string `yaml:"headers,omitempty"`      // e.g. an auth token; values may be ${SECRET} references
+	ServiceName string            `yaml:"service_name,omitempty"` // default: powerhour
+}
+
 // LibraryConfig controls the shared media library.
 type LibraryConfig struct {
 	Mode string `yaml:"mode,omitempty"` // "shared" (default) or "local"
@@ -218,6 +780,52 @@ type VideoConfig struct {
 	Codec  string `yaml:"codec"`
 	CRF    int    `yaml:"crf"`
 	Preset string `yaml:"preset"`
+	// AutoCrop applies the cropdetect suggestion stored with each cached
+	// source, removing baked-in bars before scaling. Rows opt out with a
+	// "crop: off" column. Omitted from JSON so render-state hashes of
+	// existing projects are unchanged.
+	AutoCrop bool `yaml:"auto_crop,omitempty" json:",omitempty"`
+	// Color normalizes every source to limited-range BT.709 using the
+	// color metadata probed into the cache: "auto" (the default when
+	// empty) converts BT.601, BT.2020, full-range and HDR sources and tags
+	// the output, "off" passes sources through untouched. Omitted from
+	// JSON while empty so render-state hashes are unchanged.
+	Color string `yaml:"color,omitempty" json:",omitempty"`
+	// HDR switches the output to 10-bit HEVC in BT.2020 with the HLG ("hlg")
+	// or PQ/HDR10 ("pq") transfer. HDR sources are converted to it and SDR
+	// sources mapped up, so every segment matches. Empty keeps SDR output.
+	HDR string `yaml:"hdr,omitempty" json:",omitempty"`
+	// PixFmt is the pixel format segments are encoded in, such as
+	// yuv420p10le for smoother gradients with libx265 or AV1. Empty uses
+	// yuv420p, or the encoder's 10-bit format with video.hdr. Checked
+	// against the formats the encoder lists before rendering.
+	PixFmt string `yaml:"pix_fmt,omitempty" json:",omitempty"`
+}
+
+// Video color modes for VideoConfig.Color.
+const (
+	ColorAuto = "auto"
+	ColorOff  = "off"
+)
+
+// ColorManaged reports whether sources are converted to BT.709.
+func (v VideoConfig) ColorManaged() bool {
+	return !strings.EqualFold(strings.TrimSpace(v.Color), ColorOff)
+}
+
+// HDR output modes for VideoConfig.HDR.
+const (
+	HDRHLG = "hlg"
+	HDRPQ  = "pq"
+)
+
+// HDREncoders lists the HEVC encoders that can write the 10-bit output
+// video.hdr needs.
+var HDREncoders = []string{"libx265", "hevc_nvenc", "hevc_videotoolbox", "hevc_amf", "hevc_qsv"}
+
+// HDRMode returns the normalized video.hdr mode, or "" for SDR output.
+func (v VideoConfig) HDRMode() string {
+	return strings.ToLower(strings.TrimSpace(v.HDR))
 }
 
 // AudioConfig describes audio encoding parameters.
@@ -227,6 +835,83 @@ type AudioConfig struct {
 	SampleRate  int            `yaml:"sample_rate"`
 	Channels    int            `yaml:"channels"`
 	Loudnorm    LoudnormConfig `yaml:"loudnorm"`
+	// Downmix sets the levels surround sources are folded down with.
+	// Omitted from JSON while unset so render-state hashes are unchanged.
+	Downmix *DownmixConfig `yaml:"downmix,omitempty" json:",omitempty"`
+	// TrimSilence moves a clip's start past leading silence when set.
+	// Omitted from JSON while unset so render-state hashes are unchanged.
+	TrimSilence *TrimSilenceConfig `yaml:"trim_silence,omitempty" json:",omitempty"`
+}
+
+// TrimSilenceConfig skips silence at a clip's start time, so a start that
+// lands in a quiet bar kicks in with the music. The start moves forward by
+// at most MaxSeconds; the clip keeps its length.
+type TrimSilenceConfig struct {
+	MaxSeconds  float64 `yaml:"max_seconds,omitempty"`  // default 2
+	ThresholdDB float64 `yaml:"threshold_db,omitempty"` // audio below counts as silence; default -45
+}
+
+// Default TrimSilenceConfig values.
+const (
+	DefaultTrimSilenceMaxSeconds  = 2.0
+	DefaultTrimSilenceThresholdDB = -45.0
+)
+
+// MaxSecondsValue returns the longest trim applied.
+func (t TrimSilenceConfig) MaxSecondsValue() float64 {
+	if t.MaxSeconds <= 0 {
+		return DefaultTrimSilenceMaxSeconds
+	}
+	return t.MaxSeconds
+}
+
+// ThresholdDBValue returns the level below which audio counts as silence.
+func (t TrimSilenceConfig) ThresholdDBValue() float64 {
+	if t.ThresholdDB == 0 {
+		return DefaultTrimSilenceThresholdDB
+	}
+	return t.ThresholdDB
+}
+
+// DownmixConfig sets how much of each surround channel a downmix folds into
+// the left and right output, as linear gains relative to the front
+// channels. Unset levels use the ITU-R BS.775 values: center and surrounds
+// at 0.707 (-3 dB), LFE left out.
+type DownmixConfig struct {
+	Center   *float64 `yaml:"center,omitempty"`
+	Surround *float64 `yaml:"surround,omitempty"`
+	LFE      *float64 `yaml:"lfe,omitempty"`
+}
+
+// Default downmix levels for DownmixConfig.
+const (
+	DefaultDownmixCenter   = 0.707
+	DefaultDownmixSurround = 0.707
+	DefaultDownmixLFE      = 0.0
+)
+
+// CenterValue returns the center channel level.
+func (d *DownmixConfig) CenterValue() float64 {
+	if d == nil || d.Center == nil {
+		return DefaultDownmixCenter
+	}
+	return *d.Center
+}
+
+// SurroundValue returns the level of the side and back channels.
+func (d *DownmixConfig) SurroundValue() float64 {
+	if d == nil || d.Surround == nil {
+		return DefaultDownmixSurround
+	}
+	return *d.Surround
+}
+
+// LFEValue returns the LFE channel level.
+func (d *DownmixConfig) LFEValue() float64 {
+	if d == nil || d.LFE == nil {
+		return DefaultDownmixLFE
+	}
+	return *d.LFE
 }
 
 // OutputConfig captures naming templates for generated assets.
@@ -392,7 +1077,16 @@ func Load(path string) (Config, error) {
 	}
 
 	cfg := Default()
-	if err := yaml.Unmarshal(contents, &cfg); err != nil {
+	merged, extends, err := resolveExtends(path, contents)
+	if err != nil {
+		return Config{}, err
+	}
+	if merged != nil {
+		if err := merged.Decode(&cfg); err != nil {
+			return Config{}, fmt.Errorf("unmarshal config: %w", err)
+		}
+		cfg.Extends = extends
+	} else if err := yaml.Unmarshal(contents, &cfg); err != nil {
 		return Config{}, fmt.Errorf("unmarshal config: %w", err)
 	}
 
@@ -525,6 +1219,23 @@ func (c Config) ToolSourceAddress(tool string) string {
 	return ""
 }
 
+// ToolAutoUpdate returns the auto-update channel for a given tool, or "" when
+// auto-update is off or the tool's version is pinned.
+func (c Config) ToolAutoUpdate(tool string) string {
+	if c.Tools == nil {
+		return ""
+	}
+	pin, ok := c.Tools[tool]
+	if !ok || strings.TrimSpace(pin.Version) != "" {
+		return ""
+	}
+	channel := strings.ToLower(strings.TrimSpace(pin.AutoUpdate))
+	if channel == "off" {
+		return ""
+	}
+	return channel
+}
+
 // LibraryShared returns true when the shared media library should be used.
 // Defaults to true when mode is empty or "shared".
 func (c Config) LibraryShared() bool {
@@ -741,13 +1452,40 @@ func (c Config) ValidateCollections() error {
 		hasFile := strings.TrimSpace(collection.File) != ""
 		hasPlan := strings.TrimSpace(collection.Plan) != ""
 
-		if hasFile && hasPlan {
+		if collection.IsGenerator() {
+			if hasFile || hasPlan {
+				return fmt.Errorf("collection %q: generator cannot be combined with file or plan", name)
+			}
+			if err := c.validateGenerator(name, collection); err != nil {
+				return err
+			}
+		} else if hasFile && hasPlan {
 			return fmt.Errorf("collection %q: cannot specify both file and plan", name)
-		}
-		if !hasFile && !hasPlan {
+		} else if !hasFile && !hasPlan {
 			return fmt.Errorf("collection %q: either file or plan is required", name)
 		}
 
+		if collection.Preroll < 0 || collection.Postroll < 0 {
+			return fmt.Errorf("collection %q: preroll and postroll cannot be negative", name)
+		}
+		switch strings.ToLower(strings.TrimSpace(collection.PadMode)) {
+		case "", PadModeBlack, PadModeFreeze:
+		default:
+			return fmt.Errorf("collection %q: pad_mode must be %q or %q, got %q", name, PadModeBlack, PadModeFreeze, collection.PadMode)
+		}
+		if !ValidFadeCurve(collection.FadeCurve) {
+			return fmt.Errorf("collection %q: fade_curve must be %q, %q or %q, got %q", name, FadeCurveTri, FadeCurveQsin, FadeCurveExp, collection.FadeCurve)
+		}
+		switch strings.ToLower(strings.TrimSpace(collection.PadShort)) {
+		case "":
+		case PadShortFreeze, PadShortBlack, PadShortError:
+			if collection.Loop {
+				return fmt.Errorf("collection %q: loop and pad_short can't both be set; a looped source never runs short", name)
+			}
+		default:
+			return fmt.Errorf("collection %q: pad_short must be %q, %q or %q, got %q", name, PadShortFreeze, PadShortBlack, PadShortError, collection.PadShort)
+		}
+
 		// Header validation only applies to plan-based collections
 		if hasPlan {
 			if protectedHeaders[normalizeHeaderName(collection.LinkHeader)] {
@@ -764,3 +1502,31 @@ func (c Config) ValidateCollections() error {
 
 	return nil
 }
+
+func (c Config) validateGenerator(name string, collection CollectionConfig) error {
+	if collection.Duration < 0 {
+		return fmt.Errorf("collection %q: duration cannot be negative", name)
+	}
+	switch strings.ToLower(strings.TrimSpace(collection.Generator)) {
+	case GeneratorSlate:
+	case GeneratorCards:
+		if err := collection.Cards.Validate(); err != nil {
+			return fmt.Errorf("collection %q: cards: %w", name, err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("collection %q: unknown generator %q (supported: %s, %s)", name, collection.Generator, GeneratorSlate, GeneratorCards)
+	}
+	from := strings.TrimSpace(collection.Slate.CreditsFrom)
+	if from == "" {
+		return nil
+	}
+	source, ok := c.Collections[from]
+	if !ok {
+		return fmt.Errorf("collection %q: slate.credits_from references unknown collection %q", name, from)
+	}
+	if source.IsGenerator() {
+		return fmt.Errorf("collection %q: slate.credits_from cannot be another generated collection", name)
+	}
+	return nil
+}
@@ -1,8 +1,11 @@
 package config
 
 import (
+	"reflect"
 	"strings"
 	"testing"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestLibrarySharedDefault(t *testing.T) {
@@ -93,3 +96,122 @@ func TestValidateCollections_FileSkipsHeaderValidation(t *testing.T) {
 		t.Fatalf("file-based collection should skip header validation: %v", err)
 	}
 }
+
+func TestValidateCollections_Padding(t *testing.T) {
+	tests := []struct {
+		name    string
+		coll    CollectionConfig
+		wantErr string
+	}{
+		{"black padding", CollectionConfig{Plan: "songs.csv", Preroll: 0.5, Postroll: 0.25}, ""},
+		{"freeze mode", CollectionConfig{Plan: "songs.csv", Preroll: 1, PadMode: "freeze"}, ""},
+		{"negative preroll", CollectionConfig{Plan: "songs.csv", Preroll: -1}, "cannot be negative"},
+		{"unknown mode", CollectionConfig{Plan: "songs.csv", PadMode: "blur"}, "pad_mode must be"},
+		{"pad short freeze", CollectionConfig{Plan: "songs.csv", PadShort: "Freeze"}, ""},
+		{"unknown pad short", CollectionConfig{Plan: "songs.csv", PadShort: "loop"}, "pad_short must be"},
+		{"pad short with loop", CollectionConfig{File: "meme.mp4", Loop: true, PadShort: "black"}, "can't both be set"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := Config{Collections: map[string]CollectionConfig{"songs": tc.coll}}
+			err := cfg.ValidateCollections()
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestMergeOverlays(t *testing.T) {
+	base := []OverlayEntry{
+		{Type: "song-info", Options: map[string]string{"color": "white", "title_size": "64"}},
+		{Type: "custom", Filters: []string{"drawbox=c=black"}},
+	}
+	tests := []struct {
+		name     string
+		override []OverlayEntry
+		want     []string // type:color per entry
+	}{
+		{"no override", nil, []string{"song-info:white", "custom:"}},
+		{"same type merges options", []OverlayEntry{{Type: "song-info", Options: map[string]string{"color": "yellow"}}}, []string{"song-info:yellow", "custom:"}},
+		{"new type appends", []OverlayEntry{{Type: "drink"}}, []string{"song-info:white", "custom:", "drink:"}},
+		{"none replaces base", []OverlayEntry{{Type: "none"}, {Type: "drink"}}, []string{"drink:"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			merged := MergeOverlays(base, tc.override)
+			var got []string
+			for _, e := range merged {
+				got = append(got, e.Type+":"+e.Options["color"])
+			}
+			if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+	if merged := MergeOverlays(base, []OverlayEntry{{Type: "song-info", Options: map[string]string{"color": "red"}}}); merged[0].Options["title_size"] != "64" {
+		t.Fatalf("merged options lost title_size: %v", merged[0].Options)
+	}
+	if base[0].Options["color"] != "white" {
+		t.Fatalf("MergeOverlays modified base: %v", base[0].Options)
+	}
+}
+
+func TestValidateCollections_Generator(t *testing.T) {
+	tests := []struct {
+		name    string
+		coll    CollectionConfig
+		wantErr string
+	}{
+		{"slate", CollectionConfig{Generator: "slate", Slate: SlateConfig{Title: "Power Hour", CreditsFrom: "songs"}}, ""},
+		{"with plan", CollectionConfig{Generator: "slate", Plan: "intro.csv"}, "cannot be combined with file or plan"},
+		{"unknown generator", CollectionConfig{Generator: "countdown"}, "unknown generator"},
+		{"cards", CollectionConfig{Generator: "cards", Cards: CardsConfig{Rules: []CardRule{{Text: "Waterfall"}}}}, ""},
+		{"cards without rules", CollectionConfig{Generator: "cards"}, "at least one rule"},
+		{"card without text", CollectionConfig{Generator: "cards", Cards: CardsConfig{Rules: []CardRule{{Weight: 2}}}}, "rule 1: text is required"},
+		{"negative card weight", CollectionConfig{Generator: "cards", Cards: CardsConfig{Rules: []CardRule{{Text: "x", Weight: -1}}}}, "weight"},
+		{"missing credits source", CollectionConfig{Generator: "slate", Slate: SlateConfig{CreditsFrom: "nope"}}, "credits_from"},
+		{"credits from generator", CollectionConfig{Generator: "slate", Slate: SlateConfig{CreditsFrom: "intro"}}, "credits_from"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{
+				Collections: map[string]CollectionConfig{
+					"intro": tt.coll,
+					"songs": {Plan: "songs.csv"},
+				},
+			}
+			err := cfg.ValidateCollections()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCardRuleUnmarshal(t *testing.T) {
+	var cards CardsConfig
+	src := "rules:\n  - Waterfall\n  - text: Everyone with a hat drinks\n    weight: 2\n    max_uses: 1\n"
+	if err := yaml.Unmarshal([]byte(src), &cards); err != nil {
+		t.Fatal(err)
+	}
+	want := []CardRule{{Text: "Waterfall"}, {Text: "Everyone with a hat drinks", Weight: 2, MaxUses: 1}}
+	if !reflect.DeepEqual(cards.Rules, want) {
+		t.Errorf("rules = %+v, want %+v", cards.Rules, want)
+	}
+	if w := cards.Rules[0].RuleWeight(); w != 1 {
+		t.Errorf("default weight = %v, want 1", w)
+	}
+}
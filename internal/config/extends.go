@@ -0,0 +1,191 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// resolveExtends merges the base configs named by the document's `extends:`
+// key beneath it. It returns a nil node when the document extends nothing,
+// so plain configs decode exactly as before.
+//
+// Precedence, lowest first: built-in defaults, then each base in the order
+// listed (a base's own extends beneath it), then the document. Mappings merge
+// key by key; scalars and lists replace; a null value drops what the bases
+// set, falling back to the default.
+func resolveExtends(path string, contents []byte) (*yaml.Node, []string, error) {
+	root, refs, err := parseConfigNode(path, contents)
+	if err != nil || len(refs) == 0 {
+		return nil, nil, err
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolve config path: %w", err)
+	}
+	merged, err := mergeExtends(abs, root, refs, []string{abs})
+	if err != nil {
+		return nil, nil, err
+	}
+	return merged, refs, nil
+}
+
+// parseConfigNode parses a config document and removes its extends key,
+// returning the top-level mapping and the referenced bases.
+func parseConfigNode(path string, contents []byte) (*yaml.Node, []string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(contents, &doc); err != nil {
+		return nil, nil, fmt.Errorf("unmarshal config %s: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}, nil, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("config %s: top level must be a mapping", path)
+	}
+
+	var refs []string
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "extends" {
+			continue
+		}
+		value := root.Content[i+1]
+		switch value.Kind {
+		case yaml.ScalarNode:
+			if value.Tag != "!!null" && strings.TrimSpace(value.Value) != "" {
+				refs = append(refs, strings.TrimSpace(value.Value))
+			}
+		case yaml.SequenceNode:
+			for _, item := range value.Content {
+				if item.Kind != yaml.ScalarNode || strings.TrimSpace(item.Value) == "" {
+					return nil, nil, fmt.Errorf("config %s: extends entries must be file paths", path)
+				}
+				refs = append(refs, strings.TrimSpace(item.Value))
+			}
+		default:
+			return nil, nil, fmt.Errorf("config %s: extends must be a path or a list of paths", path)
+		}
+		root.Content = slices.Delete(root.Content, i, i+2)
+		break
+	}
+	return root, refs, nil
+}
+
+// mergeExtends loads each base (relative to the file naming it) and merges
+// root over them. stack holds the files being loaded, to report cycles.
+func mergeExtends(path string, root *yaml.Node, refs []string, stack []string) (*yaml.Node, error) {
+	base := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, ref := range refs {
+		basePath, err := resolveExtendsPath(filepath.Dir(path), ref)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(stack, basePath) {
+			return nil, fmt.Errorf("config extends cycle: %s", strings.Join(append(stack, basePath), " -> "))
+		}
+		contents, err := os.ReadFile(basePath)
+		if err != nil {
+			return nil, fmt.Errorf("load base config %q: %w", ref, err)
+		}
+		node, baseRefs, err := parseConfigNode(basePath, contents)
+		if err != nil {
+			return nil, err
+		}
+		if len(baseRefs) > 0 {
+			if node, err = mergeExtends(basePath, node, baseRefs, append(stack, basePath)); err != nil {
+				return nil, err
+			}
+		}
+		base = mergeConfigNodes(base, node)
+	}
+	return mergeConfigNodes(base, root), nil
+}
+
+// resolveExtendsPath resolves an extends entry: absolute, ~/-relative, or
+// relative to dir.
+func resolveExtendsPath(dir, ref string) (string, error) {
+	if rest, ok := strings.CutPrefix(ref, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("detect user home: %w", err)
+		}
+		ref = filepath.Join(home, rest)
+	}
+	return filepath.Abs(resolveExternalPath(dir, ref))
+}
+
+// mergeConfigNodes deep-merges over onto base. Both mappings merge key by
+// key; anything else in over replaces base. A null in over removes the key.
+func mergeConfigNodes(base, over *yaml.Node) *yaml.Node {
+	if base == nil || base.Kind != yaml.MappingNode || over.Kind != yaml.MappingNode {
+		return over
+	}
+	merged := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: slices.Clone(base.Content)}
+	for i := 0; i+1 < len(over.Content); i += 2 {
+		key, value := over.Content[i], over.Content[i+1]
+		at := -1
+		for j := 0; j+1 < len(merged.Content); j += 2 {
+			if merged.Content[j].Value == key.Value {
+				at = j
+				break
+			}
+		}
+		switch {
+		case value.Tag == "!!null":
+			if at >= 0 {
+				merged.Content = slices.Delete(merged.Content, at, at+2)
+			}
+		case at >= 0:
+			merged.Content[at+1] = mergeConfigNodes(merged.Content[at+1], value)
+		default:
+			merged.Content = append(merged.Content, key, value)
+		}
+	}
+	return merged
+}
+
+// BaseConfigFiles returns the absolute paths of every config the file at
+// path extends, directly or through other bases, in load order.
+func BaseConfigFiles(path string) ([]string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("resolve config path: %w", err)
+	}
+	var out []string
+	visited := map[string]bool{abs: true}
+	var walk func(string) error
+	walk = func(file string) error {
+		contents, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("load base config %q: %w", file, err)
+		}
+		_, refs, err := parseConfigNode(file, contents)
+		if err != nil {
+			return err
+		}
+		for _, ref := range refs {
+			basePath, err := resolveExtendsPath(filepath.Dir(file), ref)
+			if err != nil {
+				return err
+			}
+			if visited[basePath] {
+				continue
+			}
+			visited[basePath] = true
+			if err := walk(basePath); err != nil {
+				return err
+			}
+			out = append(out, basePath)
+		}
+		return nil
+	}
+	if err := walk(abs); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadExtends_DeepMerge(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "shared", "house.yaml"), `
+extends: style.yaml
+video:
+  crf: 18
+  preset: slow
+collections:
+  songs:
+    plan: songs.yaml
+    output_dir: songs
+    fade: 1.5
+    overlays:
+      - type: song-info
+cache:
+  view:
+    columns: [title, artist, uploader]
+`)
+	writeFile(t, filepath.Join(root, "shared", "style.yaml"), `
+video:
+  width: 1280
+  height: 720
+  crf: 30
+audio:
+  bitrate_kbps: 256
+`)
+	writeFile(t, filepath.Join(root, "party", "powerhour.yaml"), `
+extends: ../shared/house.yaml
+video:
+  crf: 22
+collections:
+  songs:
+    fade: 0.5
+  interstitials:
+    plan: interstitials.yaml
+    output_dir: interstitials
+cache:
+  view:
+    columns: [title]
+`)
+
+	cfg, err := Load(filepath.Join(root, "party", "powerhour.yaml"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Video.Width != 1280 || cfg.Video.Height != 720 {
+		t.Errorf("video size = %dx%d, want 1280x720 from the base's base", cfg.Video.Width, cfg.Video.Height)
+	}
+	if cfg.Video.CRF != 22 || cfg.Video.Preset != "slow" {
+		t.Errorf("crf/preset = %d/%s, want 22/slow", cfg.Video.CRF, cfg.Video.Preset)
+	}
+	if cfg.Audio.BitrateKbps != 256 {
+		t.Errorf("bitrate = %d, want 256", cfg.Audio.BitrateKbps)
+	}
+	songs := cfg.Collections["songs"]
+	if songs.Plan != "songs.yaml" || songs.Fade != 0.5 || len(songs.Overlays) != 1 {
+		t.Errorf("songs = %+v, want the base collection with fade 0.5", songs)
+	}
+	if _, ok := cfg.Collections["interstitials"]; !ok {
+		t.Error("interstitials missing")
+	}
+	if !reflect.DeepEqual(cfg.Cache.View.Columns, []string{"title"}) {
+		t.Errorf("columns = %v, want lists replaced, not appended", cfg.Cache.View.Columns)
+	}
+	if !reflect.DeepEqual(cfg.Extends, []string{"../shared/house.yaml"}) {
+		t.Errorf("Extends = %v", cfg.Extends)
+	}
+}
+
+func TestLoadExtends_Precedence(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.yaml"), "video:\n  fps: 24\n  crf: 30\n")
+	writeFile(t, filepath.Join(dir, "b.yaml"), "video:\n  fps: 60\n")
+	writeFile(t, filepath.Join(dir, "powerhour.yaml"), "extends: [a.yaml, b.yaml]\nvideo:\n  crf: null\n")
+
+	cfg, err := Load(filepath.Join(dir, "powerhour.yaml"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Video.FPS != 60 {
+		t.Errorf("fps = %d, want 60 from the later base", cfg.Video.FPS)
+	}
+	if want := Default().Video.CRF; cfg.Video.CRF != want {
+		t.Errorf("crf = %d, want the default %d after null", cfg.Video.CRF, want)
+	}
+}
+
+func TestLoadExtends_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name:    "missing base",
+			files:   map[string]string{"powerhour.yaml": "extends: nope.yaml\n"},
+			wantErr: `load base config "nope.yaml"`,
+		},
+		{
+			name: "cycle",
+			files: map[string]string{
+				"powerhour.yaml": "extends: a.yaml\n",
+				"a.yaml":         "extends: b.yaml\n",
+				"b.yaml":         "extends: a.yaml\n",
+			},
+			wantErr: "extends cycle",
+		},
+		{
+			name:    "bad value",
+			files:   map[string]string{"powerhour.yaml": "extends:\n  base: a.yaml\n"},
+			wantErr: "extends must be a path",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, body := range tt.files {
+				writeFile(t, filepath.Join(dir, name), body)
+			}
+			_, err := Load(filepath.Join(dir, "powerhour.yaml"))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBaseConfigFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "powerhour.yaml"), "extends: [shared/house.yaml, local.yaml]\n")
+	writeFile(t, filepath.Join(dir, "shared", "house.yaml"), "extends: style.yaml\n")
+	writeFile(t, filepath.Join(dir, "shared", "style.yaml"), "video:\n  fps: 24\n")
+	writeFile(t, filepath.Join(dir, "local.yaml"), "extends: shared/style.yaml\n")
+
+	got, err := BaseConfigFiles(filepath.Join(dir, "powerhour.yaml"))
+	if err != nil {
+		t.Fatalf("BaseConfigFiles: %v", err)
+	}
+	want := []string{
+		filepath.Join(dir, "shared", "style.yaml"),
+		filepath.Join(dir, "shared", "house.yaml"),
+		filepath.Join(dir, "local.yaml"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
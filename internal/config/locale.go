@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var localePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// NormalizeLocale lowercases and trims a locale code; plan headers are
+// lowercased on load, so "ES" and "es" pick the same columns.
+func NormalizeLocale(locale string) string {
+	return strings.ToLower(strings.TrimSpace(locale))
+}
+
+// LocaleCode returns the normalized locale overlays and plan rows are
+// rendered in; empty means the untranslated text.
+func (c Config) LocaleCode() string {
+	return NormalizeLocale(c.Locale)
+}
+
+// LocalizeFields returns a copy of fields where every "<key>_<locale>"
+// entry with a value replaces "<key>". The translated entries stay in the
+// copy, so localizing again after a merge picks them up once more. An empty
+// locale returns fields unchanged.
+func LocalizeFields(fields map[string]string, locale string) map[string]string {
+	locale = NormalizeLocale(locale)
+	if locale == "" || len(fields) == 0 {
+		return fields
+	}
+	suffix := "_" + locale
+	out := make(map[string]string, len(fields))
+	for k, v := range fields {
+		out[k] = v
+	}
+	for k, v := range fields {
+		lower := strings.ToLower(k)
+		if !strings.HasSuffix(lower, suffix) || strings.TrimSpace(v) == "" {
+			continue
+		}
+		if base := k[:len(k)-len(suffix)]; base != "" {
+			out[base] = v
+		}
+	}
+	return out
+}
+
+// LocalizeOverlays returns the overlays with their options localized by
+// LocalizeFields, so text_es replaces text when the locale is es.
+func LocalizeOverlays(overlays []OverlayEntry, locale string) []OverlayEntry {
+	if NormalizeLocale(locale) == "" || len(overlays) == 0 {
+		return overlays
+	}
+	out := make([]OverlayEntry, len(overlays))
+	for i, entry := range overlays {
+		entry.Options = LocalizeFields(entry.Options, locale)
+		out[i] = entry
+	}
+	return out
+}
+
+// validateLocales checks the project locale and each named timeline's.
+func (c Config) validateLocales() []ValidationResult {
+	var results []ValidationResult
+	check := func(label, locale string) {
+		if locale = NormalizeLocale(locale); locale != "" && !localePattern.MatchString(locale) {
+			results = append(results, ValidationResult{
+				Level:   "error",
+				Message: fmt.Sprintf("%s %q is not a valid locale (use letters, digits, - or _, e.g. es or pt-br)", label, locale),
+			})
+		}
+	}
+	check("locale", c.Locale)
+	for _, name := range c.TimelineNames() {
+		check(fmt.Sprintf("timelines.%s.locale", name), c.Timelines[name].Locale)
+	}
+	return results
+}
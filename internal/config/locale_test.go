@@ -0,0 +1,79 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLocalizeFields(t *testing.T) {
+	fields := map[string]string{"title": "Rain", "title_es": "Lluvia", "artist": "Band", "artist_es": "", "trivia_fr": "Pluie"}
+
+	tests := []struct {
+		locale string
+		want   map[string]string
+	}{
+		{"", fields},
+		{"es", map[string]string{"title": "Lluvia", "title_es": "Lluvia", "artist": "Band", "artist_es": "", "trivia_fr": "Pluie"}},
+		{" ES ", map[string]string{"title": "Lluvia", "title_es": "Lluvia", "artist": "Band", "artist_es": "", "trivia_fr": "Pluie"}},
+		{"fr", map[string]string{"title": "Rain", "title_es": "Lluvia", "artist": "Band", "artist_es": "", "trivia": "Pluie", "trivia_fr": "Pluie"}},
+		{"de", fields},
+	}
+	for _, tt := range tests {
+		if got := LocalizeFields(fields, tt.locale); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("LocalizeFields(%q) = %v, want %v", tt.locale, got, tt.want)
+		}
+	}
+	if fields["title"] != "Rain" {
+		t.Error("LocalizeFields modified its input")
+	}
+}
+
+func TestLocalizeOverlays(t *testing.T) {
+	overlays := []OverlayEntry{
+		{Type: "drink", Options: map[string]string{"text": "Drink!", "text_es": "¡Bebe!"}},
+		{Type: "custom", Filters: []string{"drawtext=text='{title}'"}},
+	}
+	got := LocalizeOverlays(overlays, "es")
+	if got[0].Options["text"] != "¡Bebe!" {
+		t.Errorf("drink text = %q, want ¡Bebe!", got[0].Options["text"])
+	}
+	if overlays[0].Options["text"] != "Drink!" {
+		t.Error("LocalizeOverlays modified its input")
+	}
+	if !reflect.DeepEqual(got[1], overlays[1]) {
+		t.Errorf("custom overlay = %+v, want unchanged", got[1])
+	}
+
+	// Localizing after a merge picks the translation up again.
+	merged := MergeOverlays(got, []OverlayEntry{{Type: "drink", Options: map[string]string{"color": "red"}}})
+	if again := LocalizeOverlays(merged, "es"); again[0].Options["text"] != "¡Bebe!" || again[0].Options["color"] != "red" {
+		t.Errorf("merged drink options = %v", again[0].Options)
+	}
+}
+
+func TestTimelineLocale(t *testing.T) {
+	cfg := Config{
+		Locale:   "en",
+		Timeline: TimelineConfig{Sequence: []SequenceEntry{{Collection: "songs"}}},
+		Timelines: map[string]TimelineConfig{
+			"es":   {Locale: "es", Sequence: []SequenceEntry{{Collection: "songs"}}},
+			"same": {Sequence: []SequenceEntry{{Collection: "songs"}}},
+			"bad":  {Locale: "es mx", Sequence: []SequenceEntry{{Collection: "songs"}}},
+		},
+	}
+	for name, want := range map[string]string{"": "en", "es": "es", "same": "en"} {
+		named, err := cfg.WithTimeline(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := named.LocaleCode(); got != want {
+			t.Errorf("WithTimeline(%q) locale = %q, want %q", name, got, want)
+		}
+	}
+
+	results := cfg.validateLocales()
+	if len(results) != 1 || !strings.Contains(results[0].Message, "timelines.bad.locale") {
+		t.Errorf("validateLocales = %+v, want one error for timelines.bad", results)
+	}
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TimelineVariants returns the sorted variant names defined across the
+// timeline's sequence entries.
+func (t TimelineConfig) TimelineVariants() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, entry := range t.Sequence {
+		for name := range entry.Variants {
+			name = strings.TrimSpace(name)
+			if name != "" && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WithVariant returns a copy of the timeline with every entry that defines
+// the named variant pointed at its replacement collection or file. Entries
+// without the variant are unchanged, so their segments are shared between
+// variants. An empty name returns the timeline as-is.
+func (t TimelineConfig) WithVariant(name string) (TimelineConfig, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return t, nil
+	}
+	out := t
+	out.Sequence = make([]SequenceEntry, len(t.Sequence))
+	found := false
+	for i, entry := range t.Sequence {
+		out.Sequence[i] = entry
+		target, ok := entry.Variants[name]
+		if !ok {
+			continue
+		}
+		found = true
+		target = strings.TrimSpace(target)
+		if entry.File != "" {
+			out.Sequence[i].File = target
+		} else {
+			out.Sequence[i].Collection = target
+		}
+	}
+	if !found {
+		available := t.TimelineVariants()
+		if len(available) == 0 {
+			return t, fmt.Errorf("unknown variant %q: no timeline entries define variants", name)
+		}
+		return t, fmt.Errorf("unknown variant %q (available: %s)", name, strings.Join(available, ", "))
+	}
+	return out, nil
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTimelineWithVariant(t *testing.T) {
+	timeline := TimelineConfig{Sequence: []SequenceEntry{
+		{Collection: "songs"},
+		{Collection: "closing_a", Variants: map[string]string{"b": "closing_b"}},
+		{File: "outro.mp4", Variants: map[string]string{"b": "outro_b.mp4", "c": "outro_c.mp4"}},
+	}}
+
+	if got := strings.Join(timeline.TimelineVariants(), ","); got != "b,c" {
+		t.Fatalf("TimelineVariants = %q, want b,c", got)
+	}
+
+	b, err := timeline.WithVariant("b")
+	if err != nil {
+		t.Fatalf("WithVariant(b): %v", err)
+	}
+	if b.Sequence[0].Collection != "songs" || b.Sequence[1].Collection != "closing_b" || b.Sequence[2].File != "outro_b.mp4" {
+		t.Errorf("variant b sequence = %+v", b.Sequence)
+	}
+	if timeline.Sequence[1].Collection != "closing_a" {
+		t.Error("WithVariant modified the original timeline")
+	}
+
+	c, err := timeline.WithVariant("c")
+	if err != nil {
+		t.Fatalf("WithVariant(c): %v", err)
+	}
+	if c.Sequence[1].Collection != "closing_a" || c.Sequence[2].File != "outro_c.mp4" {
+		t.Errorf("variant c sequence = %+v", c.Sequence)
+	}
+
+	if _, err := timeline.WithVariant("z"); err == nil || !strings.Contains(err.Error(), "available: b, c") {
+		t.Errorf("expected unknown variant error listing b, c; got %v", err)
+	}
+	if same, err := timeline.WithVariant(""); err != nil || same.Sequence[1].Collection != "closing_a" {
+		t.Errorf("empty variant should be a no-op, got %+v, %v", same.Sequence, err)
+	}
+}
+
+func TestValidateTimeline_VariantCollectionMissing(t *testing.T) {
+	cfg := Config{
+		Collections: map[string]CollectionConfig{
+			"closing_a": {Plan: "a.csv"},
+			"closing_b": {Plan: "b.csv"},
+		},
+		Timeline: TimelineConfig{Sequence: []SequenceEntry{
+			{Collection: "closing_a", Variants: map[string]string{"b": "closing_b", "c": "closing_c"}},
+		}},
+	}
+	results := cfg.validateTimeline("")
+	if len(results) != 1 || !strings.Contains(results[0].Message, `variant "c" collection "closing_c" does not exist`) {
+		t.Fatalf("results = %+v", results)
+	}
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TimelineNames returns the sorted names of the configured named timelines.
+func (c Config) TimelineNames() []string {
+	names := make([]string, 0, len(c.Timelines))
+	for name := range c.Timelines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WithTimeline returns a copy of the config whose Timeline is the named
+// timeline from Timelines, in the timeline's locale when it sets one. An
+// empty name returns the config as-is.
+func (c Config) WithTimeline(name string) (Config, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return c, nil
+	}
+	timeline, ok := c.Timelines[name]
+	if !ok {
+		available := c.TimelineNames()
+		if len(available) == 0 {
+			return c, fmt.Errorf("unknown timeline %q: no named timelines configured", name)
+		}
+		return c, fmt.Errorf("unknown timeline %q (available: %s)", name, strings.Join(available, ", "))
+	}
+	c.Timeline = timeline
+	if timeline.Locale != "" {
+		c.Locale = timeline.Locale
+	}
+	return c, nil
+}
+
+// validateNamedTimelines runs the timeline checks against every named
+// timeline, prefixing each message with its name.
+func (c Config) validateNamedTimelines(projectRoot string) []ValidationResult {
+	var results []ValidationResult
+	for _, name := range c.TimelineNames() {
+		if strings.TrimSpace(name) == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+			results = append(results, ValidationResult{
+				Level:   "error",
+				Message: fmt.Sprintf("timelines: name %q is not valid (it names a directory; avoid slashes)", name),
+			})
+			continue
+		}
+		named, _ := c.WithTimeline(name)
+		for _, r := range named.validateTimeline(projectRoot) {
+			r.Message = fmt.Sprintf("timelines.%s: %s", name, r.Message)
+			results = append(results, r)
+		}
+	}
+	return results
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfigWithTimeline(t *testing.T) {
+	cfg := Config{
+		Collections: map[string]CollectionConfig{"songs": {Plan: "songs.csv"}},
+		Timeline:    TimelineConfig{Sequence: []SequenceEntry{{Collection: "songs"}}},
+		Timelines: map[string]TimelineConfig{
+			"warmup": {TargetDurationS: 1800, Sequence: []SequenceEntry{{Collection: "songs", Slice: "start:30"}}},
+			"full":   {Sequence: []SequenceEntry{{Collection: "songs"}}},
+		},
+	}
+
+	if got := strings.Join(cfg.TimelineNames(), ","); got != "full,warmup" {
+		t.Fatalf("TimelineNames = %q, want full,warmup", got)
+	}
+
+	same, err := cfg.WithTimeline("")
+	if err != nil || same.Timeline.Sequence[0].Slice != "" {
+		t.Fatalf("WithTimeline(\"\") = %+v, %v; want default timeline", same.Timeline, err)
+	}
+
+	warmup, err := cfg.WithTimeline("warmup")
+	if err != nil {
+		t.Fatalf("WithTimeline(warmup): %v", err)
+	}
+	if warmup.Timeline.TargetDurationS != 1800 || warmup.Timeline.Sequence[0].Slice != "start:30" {
+		t.Errorf("warmup timeline = %+v", warmup.Timeline)
+	}
+	if cfg.Timeline.TargetDurationS != 0 {
+		t.Error("WithTimeline modified the original config")
+	}
+
+	if _, err := cfg.WithTimeline("encore"); err == nil || !strings.Contains(err.Error(), "available: full, warmup") {
+		t.Errorf("expected unknown timeline error listing full, warmup; got %v", err)
+	}
+	if _, err := (Config{}).WithTimeline("warmup"); err == nil || !strings.Contains(err.Error(), "no named timelines") {
+		t.Errorf("expected no named timelines error; got %v", err)
+	}
+}
+
+func TestValidateNamedTimelines(t *testing.T) {
+	cfg := Config{
+		Collections: map[string]CollectionConfig{"songs": {Plan: "songs.csv"}},
+		Timelines: map[string]TimelineConfig{
+			"warmup": {Sequence: []SequenceEntry{{Collection: "drinks"}}},
+			"a/b":    {Sequence: []SequenceEntry{{Collection: "songs"}}},
+			"full":   {Sequence: []SequenceEntry{{Collection: "songs"}}},
+		},
+	}
+	results := cfg.validateNamedTimelines("")
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2: %+v", len(results), results)
+	}
+	if !strings.Contains(results[0].Message, `"a/b"`) {
+		t.Errorf("results[0] = %q, want invalid name", results[0].Message)
+	}
+	if !strings.HasPrefix(results[1].Message, "timelines.warmup: ") || !strings.Contains(results[1].Message, `"drinks" does not exist`) {
+		t.Errorf("results[1] = %q, want prefixed missing collection", results[1].Message)
+	}
+}
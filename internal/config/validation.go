@@ -2,9 +2,17 @@ package config
 
 import (
 	"fmt"
+	"maps"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
+
+	"powerhour/internal/secrets"
 )
 
 // ValidationResult captures a single validation finding.
@@ -30,8 +38,20 @@ func (c Config) ValidateStrict(projectRoot string, knownSegmentTokens []string)
 	results = append(results, c.validateOverlayEntries()...)
 	results = append(results, c.validateCacheConfig()...)
 	results = append(results, c.validatePlanPaths(projectRoot)...)
+	results = append(results, c.validateCollectionCues(projectRoot)...)
 	results = append(results, c.validateSegmentTemplate(knownSegmentTokens)...)
 	results = append(results, c.validateTimeline(projectRoot)...)
+	results = append(results, c.validateNamedTimelines(projectRoot)...)
+	results = append(results, c.validateToolPins()...)
+	results = append(results, c.validateRender()...)
+	results = append(results, c.validateVideo()...)
+	results = append(results, c.validateAudio()...)
+	results = append(results, c.validateFades()...)
+	results = append(results, c.validateNotify()...)
+	results = append(results, c.validateUpload()...)
+	results = append(results, c.validateTelemetry()...)
+	results = append(results, c.validateSecretRefs()...)
+	results = append(results, c.validateLocales()...)
 	return results
 }
 
@@ -43,6 +63,7 @@ var knownCacheFields = map[string]bool{
 	"uploader":    true,
 	"channel":     true,
 	"upload_date": true,
+	"license":     true,
 	"description": true,
 	"source":      true,
 	"links":       true,
@@ -52,6 +73,309 @@ var knownCacheFields = map[string]bool{
 	"cached_path": true,
 }
 
+var validAutoUpdateChannels = map[string]bool{
+	"":       true,
+	"off":    true,
+	"daily":  true,
+	"weekly": true,
+}
+
+func (c Config) validateToolPins() []ValidationResult {
+	var results []ValidationResult
+	for name, pin := range c.Tools {
+		channel := strings.ToLower(strings.TrimSpace(pin.AutoUpdate))
+		if !validAutoUpdateChannels[channel] {
+			results = append(results, ValidationResult{
+				Level:   "error",
+				Message: fmt.Sprintf("tools.%s.auto_update: unknown value %q (use daily, weekly, or off)", name, pin.AutoUpdate),
+			})
+			continue
+		}
+		if channel != "" && channel != "off" && strings.TrimSpace(pin.Version) != "" {
+			results = append(results, ValidationResult{
+				Level:   "warning",
+				Message: fmt.Sprintf("tools.%s.auto_update is ignored because version is pinned to %s", name, pin.Version),
+			})
+		}
+	}
+	return results
+}
+
+func (c Config) validateVideo() []ValidationResult {
+	var results []ValidationResult
+	switch strings.ToLower(strings.TrimSpace(c.Video.Color)) {
+	case "", ColorAuto, ColorOff:
+	default:
+		results = append(results, ValidationResult{Level: "error", Message: fmt.Sprintf("video.color: %q is not valid (use auto or off)", c.Video.Color)})
+	}
+	switch c.Video.HDRMode() {
+	case "":
+	case HDRHLG, HDRPQ:
+		if codec := strings.TrimSpace(c.Video.Codec); !slices.Contains(HDREncoders, codec) {
+			results = append(results, ValidationResult{
+				Level:   "error",
+				Message: fmt.Sprintf("video.hdr needs a 10-bit HEVC encoder, not video.codec %q (use %s)", codec, strings.Join(HDREncoders, ", ")),
+			})
+		}
+		if !c.Video.ColorManaged() {
+			results = append(results, ValidationResult{Level: "error", Message: "video.hdr needs video.color auto to map every source to HDR"})
+		}
+		if pixFmt := strings.TrimSpace(c.Video.PixFmt); pixFmt != "" && !tenBitPixFmt(pixFmt) {
+			results = append(results, ValidationResult{Level: "error", Message: fmt.Sprintf("video.pix_fmt %q is not 10-bit, which video.hdr needs (use yuv420p10le or p010le)", pixFmt)})
+		}
+	default:
+		results = append(results, ValidationResult{Level: "error", Message: fmt.Sprintf("video.hdr: %q is not valid (use hlg or pq)", c.Video.HDR)})
+	}
+	if pixFmt := strings.TrimSpace(c.Video.PixFmt); pixFmt != "" && !pixFmtPattern.MatchString(pixFmt) {
+		results = append(results, ValidationResult{Level: "error", Message: fmt.Sprintf("video.pix_fmt: %q is not an ffmpeg pixel format name (e.g. yuv420p10le)", pixFmt)})
+	}
+	return results
+}
+
+var pixFmtPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// tenBitPixFmt reports whether an ffmpeg pixel format stores 10 bits per
+// component (yuv420p10le, yuv422p10be, p010le, …).
+func tenBitPixFmt(pixFmt string) bool {
+	return strings.Contains(pixFmt, "p10") || strings.HasPrefix(pixFmt, "p010")
+}
+
+func (c Config) validateAudio() []ValidationResult {
+	var results []ValidationResult
+	if t := c.Audio.TrimSilence; t != nil {
+		if t.MaxSeconds < 0 || t.MaxSeconds > 30 {
+			results = append(results, ValidationResult{Level: "error", Message: fmt.Sprintf("audio.trim_silence.max_seconds: %g is outside 0-30", t.MaxSeconds)})
+		}
+		if t.ThresholdDB > 0 {
+			results = append(results, ValidationResult{Level: "error", Message: fmt.Sprintf("audio.trim_silence.threshold_db: %g must be negative (dBFS)", t.ThresholdDB)})
+		}
+	}
+	d := c.Audio.Downmix
+	if d == nil {
+		return results
+	}
+	for _, level := range []struct {
+		name  string
+		value *float64
+	}{{"center", d.Center}, {"surround", d.Surround}, {"lfe", d.LFE}} {
+		if level.value != nil && (*level.value < 0 || *level.value > 2) {
+			results = append(results, ValidationResult{Level: "error", Message: fmt.Sprintf("audio.downmix.%s: %g is outside 0-2", level.name, *level.value)})
+		}
+	}
+	return results
+}
+
+// validateFades checks the video and audio fades of collections and of the
+// entries of the default and every named timeline: none negative, and a
+// warning for any longer than half the clip where the config sets its
+// duration, since render shortens each fade to half the clip so fade-in and
+// fade-out never overlap (rows with their own durations are clamped the same
+// way at render time).
+func (c Config) validateFades() []ValidationResult {
+	var results []ValidationResult
+	for _, name := range slices.Sorted(maps.Keys(c.Collections)) {
+		coll := c.Collections[name]
+		in, out := ResolveFade(coll.Fade, coll.FadeIn, coll.FadeOut)
+		results = append(results, checkFades(fmt.Sprintf("collection %q", name), coll.Duration, fadeLengths(in, out, coll.AudioFadeIn, coll.AudioFadeOut))...)
+	}
+	results = append(results, c.validateSequenceFades("timeline", c.Timeline.Sequence)...)
+	for _, name := range c.TimelineNames() {
+		results = append(results, c.validateSequenceFades(fmt.Sprintf("timelines.%s", name), c.Timelines[name].Sequence)...)
+	}
+	return results
+}
+
+// validateSequenceFades checks the fades of one timeline's sequence entries,
+// falling back to their collections' fades where an entry sets none.
+func (c Config) validateSequenceFades(timeline string, sequence []SequenceEntry) []ValidationResult {
+	var results []ValidationResult
+	for i, entry := range sequence {
+		where := fmt.Sprintf("%s sequence[%d]", timeline, i)
+		if strings.TrimSpace(entry.File) != "" {
+			// The entry checks cover a file's video fades.
+			results = append(results, checkFades(where, 0, fadeLengths(0, 0, entry.AudioFadeIn, entry.AudioFadeOut))...)
+			continue
+		}
+		hasFade := entry.Fade != 0 || entry.FadeIn != 0 || entry.FadeOut != 0
+		if !hasFade && !entry.HasAudioFade() && entry.Duration <= 0 {
+			continue
+		}
+		coll := c.Collections[entry.Collection]
+		duration := coll.Duration
+		if entry.Duration > 0 {
+			duration = entry.Duration
+		}
+		in, out := ResolveFade(coll.Fade, coll.FadeIn, coll.FadeOut)
+		if hasFade {
+			in, out = ResolveFade(entry.Fade, entry.FadeIn, entry.FadeOut)
+		}
+		audioIn, audioOut := coll.AudioFadeIn, coll.AudioFadeOut
+		if entry.AudioFadeIn != nil {
+			audioIn = entry.AudioFadeIn
+		}
+		if entry.AudioFadeOut != nil {
+			audioOut = entry.AudioFadeOut
+		}
+		results = append(results, checkFades(where, duration, fadeLengths(in, out, audioIn, audioOut))...)
+	}
+	return results
+}
+
+type fadeLength struct {
+	name    string
+	seconds float64
+}
+
+func fadeLengths(in, out float64, audioIn, audioOut *float64) []fadeLength {
+	fades := []fadeLength{{"fade_in", in}, {"fade_out", out}}
+	if audioIn != nil {
+		fades = append(fades, fadeLength{"audio_fade_in", *audioIn})
+	}
+	if audioOut != nil {
+		fades = append(fades, fadeLength{"audio_fade_out", *audioOut})
+	}
+	return fades
+}
+
+func checkFades(where string, duration int, fades []fadeLength) []ValidationResult {
+	var results []ValidationResult
+	for _, f := range fades {
+		switch {
+		case f.seconds < 0:
+			results = append(results, ValidationResult{Level: "error", Message: fmt.Sprintf("%s: %s must be >= 0", where, f.name)})
+		case duration > 0 && f.seconds > float64(duration)/2:
+			results = append(results, ValidationResult{Level: "warning", Message: fmt.Sprintf("%s: %s of %gs is more than half the %ds clip; render shortens it to %gs", where, f.name, f.seconds, duration, float64(duration)/2)})
+		}
+	}
+	return results
+}
+
+func (c Config) validateRender() []ValidationResult {
+	var results []ValidationResult
+	r := c.Render
+	if r.Retries != nil && *r.Retries < 0 {
+		results = append(results, ValidationResult{Level: "error", Message: "render.retries must not be negative"})
+	}
+	if r.Threads < 0 {
+		results = append(results, ValidationResult{Level: "error", Message: "render.threads must not be negative"})
+	}
+	if r.Nice < 0 || r.Nice > 19 {
+		results = append(results, ValidationResult{Level: "error", Message: fmt.Sprintf("render.nice: %d is outside 0-19 (raising priority isn't supported)", r.Nice)})
+	}
+	return results
+}
+
+func (c Config) validateNotify() []ValidationResult {
+	var results []ValidationResult
+	n := c.Notify
+	for _, command := range n.Commands {
+		if !slices.Contains(NotifyCommands, command) {
+			results = append(results, ValidationResult{
+				Level:   "error",
+				Message: fmt.Sprintf("notify.commands: unknown command %q (use %s)", command, strings.Join(NotifyCommands, ", ")),
+			})
+		}
+	}
+	if n.MinSeconds < 0 {
+		results = append(results, ValidationResult{Level: "error", Message: "notify.min_seconds must not be negative"})
+	}
+	if webhook := strings.TrimSpace(n.Webhook); webhook != "" && !strings.HasPrefix(webhook, "https://") && !strings.HasPrefix(webhook, "http://") && !strings.HasPrefix(webhook, "${") {
+		results = append(results, ValidationResult{Level: "error", Message: fmt.Sprintf("notify.webhook: %q is not an http(s) URL", webhook)})
+	}
+	if len(n.Email.To) > 0 {
+		if strings.TrimSpace(n.Email.SMTP) == "" {
+			results = append(results, ValidationResult{Level: "error", Message: "notify.email.smtp is required (host:port)"})
+		} else if _, _, err := net.SplitHostPort(n.Email.SMTP); err != nil {
+			results = append(results, ValidationResult{Level: "error", Message: fmt.Sprintf("notify.email.smtp: %v", err)})
+		}
+		if strings.TrimSpace(n.Email.From) == "" {
+			results = append(results, ValidationResult{Level: "error", Message: "notify.email.from is required"})
+		}
+	}
+	if (len(n.Commands) > 0 || n.MinSeconds > 0) && !n.Enabled() {
+		results = append(results, ValidationResult{Level: "warning", Message: "notify has no desktop, webhook or email channel configured"})
+	}
+	return results
+}
+
+func (c Config) validateTelemetry() []ValidationResult {
+	endpoint := strings.TrimSpace(c.Telemetry.Endpoint)
+	if endpoint == "" || strings.HasPrefix(endpoint, "${") {
+		return nil
+	}
+	if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return []ValidationResult{{Level: "error", Message: fmt.Sprintf("telemetry.endpoint: %q is not an http(s) URL", endpoint)}}
+	}
+	return nil
+}
+
+var validYouTubePrivacy = map[string]bool{
+	"":         true,
+	"private":  true,
+	"unlisted": true,
+	"public":   true,
+}
+
+func (c Config) validateUpload() []ValidationResult {
+	var results []ValidationResult
+	required := func(field, value string) {
+		if strings.TrimSpace(value) == "" {
+			results = append(results, ValidationResult{Level: "error", Message: fmt.Sprintf("upload.%s is required", field)})
+		}
+	}
+	httpURL := func(field, value string) {
+		value = strings.TrimSpace(value)
+		if value != "" && !strings.HasPrefix(value, "https://") && !strings.HasPrefix(value, "http://") && !strings.HasPrefix(value, "${") {
+			results = append(results, ValidationResult{Level: "error", Message: fmt.Sprintf("upload.%s: %q is not an http(s) URL", field, value)})
+		}
+	}
+	if yt := c.Upload.YouTube; yt != nil {
+		required("youtube.client_id", yt.ClientID)
+		required("youtube.client_secret", yt.ClientSecret)
+		required("youtube.refresh_token", yt.RefreshToken)
+		if !validYouTubePrivacy[strings.ToLower(strings.TrimSpace(yt.Privacy))] {
+			results = append(results, ValidationResult{
+				Level:   "error",
+				Message: fmt.Sprintf("upload.youtube.privacy: unknown value %q (use private, unlisted, or public)", yt.Privacy),
+			})
+		}
+		if len(yt.Title) > 100 {
+			results = append(results, ValidationResult{Level: "warning", Message: "upload.youtube.title is longer than YouTube's 100 characters and will be cut"})
+		}
+	}
+	if s3 := c.Upload.S3; s3 != nil {
+		required("s3.bucket", s3.Bucket)
+		required("s3.access_key_id", s3.AccessKeyID)
+		required("s3.secret_access_key", s3.SecretAccessKey)
+		httpURL("s3.endpoint", s3.Endpoint)
+		httpURL("s3.public_url", s3.PublicURL)
+	}
+	if h := c.Upload.HTTP; h != nil {
+		required("http.url", h.URL)
+		httpURL("http.url", h.URL)
+	}
+	return results
+}
+
+// validateSecretRefs reports ${NAME} references that resolve from neither the
+// environment nor the OS keychain. Resolved values are never included.
+func (c Config) validateSecretRefs() []ValidationResult {
+	data, err := c.Marshal()
+	if err != nil {
+		return nil
+	}
+	var results []ValidationResult
+	for _, name := range secrets.References(string(data)) {
+		if _, err := secrets.Lookup(name); err != nil {
+			results = append(results, ValidationResult{
+				Level:   "error",
+				Message: fmt.Sprintf("secret %q not set in environment or %s keychain", name, secrets.KeychainService),
+			})
+		}
+	}
+	return results
+}
+
 func (c Config) validateExternalFiles(projectRoot string) []ValidationResult {
 	var results []ValidationResult
 	for _, path := range c.CollectionFiles {
@@ -72,35 +396,7 @@ func (c Config) validateExternalFiles(projectRoot string) []ValidationResult {
 func (c Config) validateOverlayEntries() []ValidationResult {
 	var results []ValidationResult
 	for name, coll := range c.Collections {
-		for i, entry := range coll.Overlays {
-			typeName := strings.TrimSpace(entry.Type)
-			if typeName == "" {
-				results = append(results, ValidationResult{
-					Level:   "error",
-					Message: fmt.Sprintf("collection %q: overlay[%d] missing type", name, i),
-				})
-				continue
-			}
-			if !KnownOverlayTypes[typeName] {
-				results = append(results, ValidationResult{
-					Level:   "error",
-					Message: fmt.Sprintf("collection %q: overlay[%d] unknown type %q", name, i, typeName),
-				})
-				continue
-			}
-			if typeName == "custom" && len(entry.Filters) == 0 {
-				results = append(results, ValidationResult{
-					Level:   "error",
-					Message: fmt.Sprintf("collection %q: overlay[%d] type \"custom\" requires filters", name, i),
-				})
-			}
-			if typeName != "custom" && len(entry.Filters) > 0 {
-				results = append(results, ValidationResult{
-					Level:   "error",
-					Message: fmt.Sprintf("collection %q: overlay[%d] type %q does not accept filters", name, i, typeName),
-				})
-			}
-		}
+		results = append(results, validateOverlayList(fmt.Sprintf("collection %q", name), coll.Overlays)...)
 		if coll.Fade < 0 || coll.FadeIn < 0 || coll.FadeOut < 0 {
 			results = append(results, ValidationResult{
 				Level:   "error",
@@ -111,6 +407,42 @@ func (c Config) validateOverlayEntries() []ValidationResult {
 	return results
 }
 
+// validateOverlayList checks overlay entries declared on a collection or a
+// timeline sequence entry; context prefixes each message.
+func validateOverlayList(context string, overlays []OverlayEntry) []ValidationResult {
+	var results []ValidationResult
+	for i, entry := range overlays {
+		typeName := strings.TrimSpace(entry.Type)
+		if typeName == "" {
+			results = append(results, ValidationResult{
+				Level:   "error",
+				Message: fmt.Sprintf("%s: overlay[%d] missing type", context, i),
+			})
+			continue
+		}
+		if !KnownOverlayTypes[typeName] {
+			results = append(results, ValidationResult{
+				Level:   "error",
+				Message: fmt.Sprintf("%s: overlay[%d] unknown type %q", context, i, typeName),
+			})
+			continue
+		}
+		if typeName == "custom" && len(entry.Filters) == 0 {
+			results = append(results, ValidationResult{
+				Level:   "error",
+				Message: fmt.Sprintf("%s: overlay[%d] type \"custom\" requires filters", context, i),
+			})
+		}
+		if typeName != "custom" && len(entry.Filters) > 0 {
+			results = append(results, ValidationResult{
+				Level:   "error",
+				Message: fmt.Sprintf("%s: overlay[%d] type %q does not accept filters", context, i, typeName),
+			})
+		}
+	}
+	return results
+}
+
 func (c Config) validateCacheConfig() []ValidationResult {
 	var results []ValidationResult
 
@@ -175,36 +507,129 @@ func (c Config) validatePlanPaths(projectRoot string) []ValidationResult {
 				Message: fmt.Sprintf("collection %q: plan file %q not found", name, plan),
 			})
 		}
+
+		if overrides := strings.TrimSpace(coll.Overrides); overrides != "" {
+			resolved := overrides
+			if !filepath.IsAbs(resolved) {
+				resolved = filepath.Join(projectRoot, resolved)
+			}
+			if _, err := os.Stat(resolved); err != nil {
+				results = append(results, ValidationResult{
+					Level:   "error",
+					Message: fmt.Sprintf("collection %q: overrides file %q not found", name, overrides),
+				})
+			}
+		}
+
+		if facts := strings.TrimSpace(coll.Facts); facts != "" {
+			resolved := facts
+			if !filepath.IsAbs(resolved) {
+				resolved = filepath.Join(projectRoot, resolved)
+			}
+			if _, err := os.Stat(resolved); err != nil {
+				results = append(results, ValidationResult{
+					Level:   "error",
+					Message: fmt.Sprintf("collection %q: facts file %q not found", name, facts),
+				})
+			}
+		}
 	}
 	return results
 }
 
-func (c Config) validateSegmentTemplate(knownTokens []string) []ValidationResult {
-	tmpl := strings.TrimSpace(c.Outputs.SegmentTemplate)
-	if tmpl == "" {
+func (c Config) validateCollectionCues(projectRoot string) []ValidationResult {
+	var results []ValidationResult
+	for name, coll := range c.Collections {
+		results = append(results, validateAudioCue(fmt.Sprintf("collection %q", name), coll.AudioCue, projectRoot)...)
+	}
+	return results
+}
+
+// validateAudioCue checks an optional audio cue's values and that its file
+// exists.
+func validateAudioCue(label string, cue *AudioCueConfig, projectRoot string) []ValidationResult {
+	if cue == nil {
 		return nil
 	}
+	if err := cue.Validate(); err != nil {
+		return []ValidationResult{{Level: "error", Message: fmt.Sprintf("%s: audio_cue: %v", label, err)}}
+	}
+	resolved := strings.TrimSpace(cue.File)
+	if !filepath.IsAbs(resolved) {
+		resolved = filepath.Join(projectRoot, resolved)
+	}
+	if _, err := os.Stat(resolved); err != nil {
+		return []ValidationResult{{Level: "error", Message: fmt.Sprintf("%s: audio_cue file %q not found", label, cue.File)}}
+	}
+	return nil
+}
 
+func (c Config) validateSegmentTemplate(knownTokens []string) []ValidationResult {
 	known := make(map[string]bool, len(knownTokens))
 	for _, t := range knownTokens {
 		known[t] = true
 	}
 
-	tokens := extractTemplateTokens(tmpl)
 	var results []ValidationResult
-	for _, tok := range tokens {
-		if !known[tok] {
+	if tmpl := strings.TrimSpace(c.Outputs.SegmentTemplate); tmpl != "" {
+		for _, tok := range extractTemplateTokens(tmpl) {
+			if !known[tok] {
+				results = append(results, ValidationResult{
+					Level:   "error",
+					Message: fmt.Sprintf("segment template contains unknown token $%s (known tokens: %s)", tok, strings.Join(knownTokens, ", ")),
+				})
+			}
+		}
+	}
+
+	collectionTokens := append(slices.Clone(knownTokens), CollectionTemplateTokens...)
+	for _, name := range slices.Sorted(maps.Keys(c.Collections)) {
+		tmpl := strings.TrimSpace(c.Collections[name].OutputTemplate)
+		if tmpl == "" {
+			continue
+		}
+		slashed := filepath.ToSlash(tmpl)
+		if filepath.IsAbs(tmpl) || strings.HasPrefix(slashed, "/") || slices.Contains(strings.Split(slashed, "/"), "..") {
 			results = append(results, ValidationResult{
 				Level:   "error",
-				Message: fmt.Sprintf("segment template contains unknown token $%s (known tokens: %s)", tok, strings.Join(knownTokens, ", ")),
+				Message: fmt.Sprintf("collections.%s.output_template must stay inside the segments directory: %q", name, tmpl),
 			})
 		}
+		for _, tok := range extractTemplateTokens(tmpl) {
+			if !slices.Contains(collectionTokens, tok) {
+				results = append(results, ValidationResult{
+					Level:   "error",
+					Message: fmt.Sprintf("collections.%s.output_template contains unknown token $%s (known tokens: %s)", name, tok, strings.Join(collectionTokens, ", ")),
+				})
+			}
+		}
 	}
 	return results
 }
 
 func (c Config) validateTimeline(projectRoot string) []ValidationResult {
 	var results []ValidationResult
+	if c.Timeline.TargetDurationS < 0 || c.Timeline.ToleranceS < 0 {
+		results = append(results, ValidationResult{
+			Level:   "error",
+			Message: "timeline target_duration_s and tolerance_s must be >= 0",
+		})
+	}
+	switch strings.ToLower(strings.TrimSpace(c.Timeline.Fit)) {
+	case "":
+	case FitTrim, FitInterleave:
+		if c.Timeline.TargetDurationS == 0 {
+			results = append(results, ValidationResult{
+				Level:   "warning",
+				Message: fmt.Sprintf("timeline fit %q has no effect without target_duration_s", c.Timeline.Fit),
+			})
+		}
+	default:
+		results = append(results, ValidationResult{
+			Level:   "error",
+			Message: fmt.Sprintf("timeline fit %q is not valid (use trim or interleave)", c.Timeline.Fit),
+		})
+	}
 	for i, entry := range c.Timeline.Sequence {
 		hasCollection := strings.TrimSpace(entry.Collection) != ""
 		hasFile := strings.TrimSpace(entry.File) != ""
@@ -224,6 +649,13 @@ func (c Config) validateTimeline(projectRoot string) []ValidationResult {
 			continue
 		}
 
+		if !ValidFadeCurve(entry.FadeCurve) {
+			results = append(results, ValidationResult{
+				Level:   "error",
+				Message: fmt.Sprintf("timeline sequence[%d]: fade_curve %q is not valid (use tri, qsin or exp)", i, entry.FadeCurve),
+			})
+		}
+
 		// Inline file entry: slice and interleave are not valid; file must exist.
 		if hasFile {
 			if entry.Fade < 0 || entry.FadeIn < 0 || entry.FadeOut < 0 {
@@ -244,6 +676,24 @@ func (c Config) validateTimeline(projectRoot string) []ValidationResult {
 					Message: fmt.Sprintf("timeline sequence[%d] (file %q): interleave is not valid for file entries", i, entry.File),
 				})
 			}
+			if strings.TrimSpace(entry.Pick) != "" {
+				results = append(results, ValidationResult{
+					Level:   "error",
+					Message: fmt.Sprintf("timeline sequence[%d] (file %q): pick is not valid for file entries", i, entry.File),
+				})
+			}
+			if entry.Duration != 0 || len(entry.Overlays) > 0 {
+				results = append(results, ValidationResult{
+					Level:   "error",
+					Message: fmt.Sprintf("timeline sequence[%d] (file %q): duration and overlays are not valid for file entries", i, entry.File),
+				})
+			}
+			if entry.AudioCue != nil {
+				results = append(results, ValidationResult{
+					Level:   "error",
+					Message: fmt.Sprintf("timeline sequence[%d] (file %q): audio_cue is not valid for file entries", i, entry.File),
+				})
+			}
 			resolved := entry.File
 			if !filepath.IsAbs(resolved) {
 				resolved = filepath.Join(projectRoot, resolved)
@@ -254,6 +704,18 @@ func (c Config) validateTimeline(projectRoot string) []ValidationResult {
 					Message: fmt.Sprintf("timeline sequence[%d] (file %q): file not found", i, entry.File),
 				})
 			}
+			for _, variant := range sortedVariantNames(entry.Variants) {
+				file := strings.TrimSpace(entry.Variants[variant])
+				if file != "" && !filepath.IsAbs(file) {
+					file = filepath.Join(projectRoot, file)
+				}
+				if _, err := os.Stat(file); file == "" || os.IsNotExist(err) {
+					results = append(results, ValidationResult{
+						Level:   "error",
+						Message: fmt.Sprintf("timeline sequence[%d] (file %q): variant %q file %q not found", i, entry.File, variant, entry.Variants[variant]),
+					})
+				}
+			}
 			continue
 		}
 
@@ -264,6 +726,14 @@ func (c Config) validateTimeline(projectRoot string) []ValidationResult {
 				Message: fmt.Sprintf("timeline sequence[%d]: collection %q does not exist", i, entry.Collection),
 			})
 		}
+		for _, variant := range sortedVariantNames(entry.Variants) {
+			if _, ok := c.Collections[strings.TrimSpace(entry.Variants[variant])]; !ok {
+				results = append(results, ValidationResult{
+					Level:   "error",
+					Message: fmt.Sprintf("timeline sequence[%d] (%q): variant %q collection %q does not exist", i, entry.Collection, variant, entry.Variants[variant]),
+				})
+			}
+		}
 		if _, err := ParseTimelineSlice(entry.Slice); err != nil {
 			results = append(results, ValidationResult{
 				Level:   "error",
@@ -276,6 +746,41 @@ func (c Config) validateTimeline(projectRoot string) []ValidationResult {
 				Message: fmt.Sprintf("timeline sequence[%d] (%q): fade values must be >= 0", i, entry.Collection),
 			})
 		}
+		if entry.Duration < 0 {
+			results = append(results, ValidationResult{
+				Level:   "error",
+				Message: fmt.Sprintf("timeline sequence[%d] (%q): duration must be >= 0", i, entry.Collection),
+			})
+		}
+		results = append(results, validateOverlayList(fmt.Sprintf("timeline sequence[%d] (%q)", i, entry.Collection), entry.Overlays)...)
+		results = append(results, validateAudioCue(fmt.Sprintf("timeline sequence[%d] (%q)", i, entry.Collection), entry.AudioCue, projectRoot)...)
+		switch strings.ToLower(strings.TrimSpace(entry.Pick)) {
+		case "", PickRandom, PickWeighted:
+		case PickTagged:
+			if len(entry.Tags) == 0 {
+				results = append(results, ValidationResult{
+					Level:   "error",
+					Message: fmt.Sprintf("timeline sequence[%d] (%q): pick tagged requires tags", i, entry.Collection),
+				})
+			}
+		default:
+			results = append(results, ValidationResult{
+				Level:   "error",
+				Message: fmt.Sprintf("timeline sequence[%d] (%q): pick %q is not valid (use random, weighted, or tagged)", i, entry.Collection, entry.Pick),
+			})
+		}
+		if entry.Count < 0 {
+			results = append(results, ValidationResult{
+				Level:   "error",
+				Message: fmt.Sprintf("timeline sequence[%d] (%q): count must be >= 0", i, entry.Collection),
+			})
+		}
+		if strings.TrimSpace(entry.Pick) == "" && (entry.Count != 0 || entry.Seed != 0 || len(entry.Tags) > 0) {
+			results = append(results, ValidationResult{
+				Level:   "warning",
+				Message: fmt.Sprintf("timeline sequence[%d] (%q): count, seed, and tags only apply with pick", i, entry.Collection),
+			})
+		}
 		if entry.Interleave != nil {
 			if strings.TrimSpace(entry.Interleave.Collection) == "" {
 				results = append(results, ValidationResult{
@@ -308,6 +813,15 @@ func (c Config) validateTimeline(projectRoot string) []ValidationResult {
 	return results
 }
 
+func sortedVariantNames(variants map[string]string) []string {
+	names := make([]string, 0, len(variants))
+	for name := range variants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // extractTemplateTokens parses $TOKEN patterns from a template string,
 // using the same token-boundary rules as the render template engine.
 func extractTemplateTokens(template string) []string {
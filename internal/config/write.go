@@ -1,19 +1,30 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"gopkg.in/yaml.v3"
 )
 
-// Save marshals the config to YAML and writes it atomically to path.
-// Comments from the original file are not preserved.
+// Save writes cfg to path atomically. When path already holds a config, only
+// the keys whose values differ from what Load returns for it are written
+// into that document, so comments, key order and an extends: child's own
+// keys are kept and values inherited from its bases are not copied in.
+// Otherwise the whole config is marshaled.
 func Save(path string, cfg Config) error {
-	data, err := cfg.Marshal()
+	data, err := patchConfigFile(path, cfg)
 	if err != nil {
 		return err
 	}
+	return writeAtomic(path, data)
+}
 
+// writeAtomic writes data to a temp file beside path and renames it over.
+func writeAtomic(path string, data []byte) error {
 	dir := filepath.Dir(path)
 	tmp, err := os.CreateTemp(dir, ".config-*.tmp")
 	if err != nil {
@@ -39,3 +50,199 @@ func Save(path string, cfg Config) error {
 
 	return nil
 }
+
+// SetToolVersion sets tools.<tool>.version in the config at path, or
+// removes it when version is empty, editing only that node of the document
+// so the rest of the file, comments included, is left as written. In a
+// config with extends: an unpin is written as null so a base's pin does not
+// show through.
+func SetToolVersion(path, tool, version string) error {
+	contents, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("read config: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(contents, &doc); err != nil {
+		return fmt.Errorf("unmarshal config %s: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("config %s: top level must be a mapping", path)
+	}
+
+	var value *yaml.Node
+	switch {
+	case version != "":
+		value = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: version}
+	case mappingValue(root, "extends") != nil:
+		value = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	}
+
+	toolsNode := mappingValue(root, "tools")
+	if value != nil {
+		if toolsNode == nil || toolsNode.Kind != yaml.MappingNode {
+			toolsNode = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			setMappingValue(root, "tools", toolsNode)
+		}
+		pin := mappingValue(toolsNode, tool)
+		if pin == nil || pin.Kind != yaml.MappingNode {
+			pin = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			setMappingValue(toolsNode, tool, pin)
+		}
+		setMappingValue(pin, "version", value)
+	} else if toolsNode != nil {
+		if pin := mappingValue(toolsNode, tool); pin != nil {
+			deleteMappingKey(pin, "version")
+			if len(pin.Content) == 0 {
+				deleteMappingKey(toolsNode, tool)
+			}
+			if len(toolsNode.Content) == 0 {
+				deleteMappingKey(root, "tools")
+			}
+		}
+	}
+
+	data, err := encodeDocument(&doc)
+	if err != nil {
+		return err
+	}
+	return writeAtomic(path, data)
+}
+
+// patchConfigFile returns the contents of path with cfg's changes applied.
+func patchConfigFile(path string, cfg Config) ([]byte, error) {
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg.Marshal()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(contents, &doc); err != nil {
+		return nil, fmt.Errorf("unmarshal config %s: %w", path, err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return cfg.Marshal()
+	}
+	root := doc.Content[0]
+
+	current, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ApplyDefaults()
+	var before, after yaml.Node
+	if err := before.Encode(&current); err != nil {
+		return nil, fmt.Errorf("marshal config: %w", err)
+	}
+	if err := after.Encode(&cfg); err != nil {
+		return nil, fmt.Errorf("marshal config: %w", err)
+	}
+	patchMapping(root, &before, &after, mappingValue(root, "extends") != nil)
+
+	return encodeDocument(&doc)
+}
+
+// encodeDocument encodes a parsed config document with two-space indents.
+func encodeDocument(doc *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("marshal config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("marshal config: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// patchMapping writes into doc the keys that differ between the before and
+// after mappings, recursing into nested mappings so untouched siblings stay
+// as they are (or inherited). A key dropped in after is deleted from doc, or
+// set to null when a base or default would otherwise supply it again.
+func patchMapping(doc, before, after *yaml.Node, extends bool) {
+	for i := 0; i+1 < len(after.Content); i += 2 {
+		key, value := after.Content[i].Value, after.Content[i+1]
+		old := mappingValue(before, key)
+		if old != nil && nodesEqual(old, value) {
+			continue
+		}
+		existing := mappingValue(doc, key)
+		if old != nil && old.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode {
+			if existing == nil || existing.Kind != yaml.MappingNode {
+				existing = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+				setMappingValue(doc, key, existing)
+			}
+			patchMapping(existing, old, value, extends)
+			continue
+		}
+		setMappingValue(doc, key, value)
+	}
+	for i := 0; i+1 < len(before.Content); i += 2 {
+		key := before.Content[i].Value
+		if mappingValue(after, key) != nil {
+			continue
+		}
+		if mappingValue(doc, key) != nil && !extends {
+			deleteMappingKey(doc, key)
+			continue
+		}
+		setMappingValue(doc, key, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"})
+	}
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingValue replaces the value of key in node, keeping the key's
+// comments, or appends the pair when key is missing.
+func setMappingValue(node *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			old := node.Content[i+1]
+			value.LineComment, value.FootComment = old.LineComment, old.FootComment
+			node.Content[i+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+func deleteMappingKey(node *yaml.Node, key string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// nodesEqual compares two encoded values, ignoring style and comments.
+func nodesEqual(a, b *yaml.Node) bool {
+	if a.Kind != b.Kind || len(a.Content) != len(b.Content) {
+		return false
+	}
+	if a.Kind == yaml.ScalarNode && (a.Tag != b.Tag || a.Value != b.Value) {
+		return false
+	}
+	for i := range a.Content {
+		if !nodesEqual(a.Content[i], b.Content[i]) {
+			return false
+		}
+	}
+	return true
+}
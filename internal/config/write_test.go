@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -132,3 +133,114 @@ func TestSave_AtomicWrite(t *testing.T) {
 		}
 	}
 }
+
+func TestSave_ExtendingConfigKeepsOwnKeys(t *testing.T) {
+	root := t.TempDir()
+	base := filepath.Join(root, "base.yaml")
+	path := filepath.Join(root, "powerhour.yaml")
+	writeFile(t, base, `
+video:
+  width: 1280
+  height: 720
+  crf: 30
+audio:
+  bitrate_kbps: 256
+`)
+	writeFile(t, path, `# party night
+extends: base.yaml
+video:
+  crf: 22 # a bit sharper
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	cfg.Video.Preset = "slow"
+	if err := Save(path, cfg); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	for _, want := range []string{"# party night", "extends: base.yaml", "crf: 22 # a bit sharper", "preset: slow"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("saved config missing %q:\n%s", want, got)
+		}
+	}
+	for _, inherited := range []string{"width", "height", "bitrate_kbps", "collections"} {
+		if strings.Contains(got, inherited) {
+			t.Errorf("saved config copied %q from the base or defaults:\n%s", inherited, got)
+		}
+	}
+
+	// The child still follows its base after the save.
+	writeFile(t, base, "video:\n  width: 1920\n")
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if reloaded.Video.Width != 1920 || reloaded.Video.CRF != 22 || reloaded.Video.Preset != "slow" {
+		t.Errorf("reloaded video = %+v, want width 1920 from the base, crf 22, preset slow", reloaded.Video)
+	}
+}
+
+func TestSetToolVersion_EditsOnlyToolsNode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "powerhour.yaml")
+	original := `# party night
+version: 1
+collections:
+  songs:
+    plan: songs.csv # the plan
+    output_dir: songs
+tools:
+  yt-dlp:
+    proxy: socks5://127.0.0.1:9050
+`
+	writeFile(t, path, original)
+
+	if err := SetToolVersion(path, "yt-dlp", "2024.07.16"); err != nil {
+		t.Fatalf("SetToolVersion: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# party night", "plan: songs.csv # the plan", "proxy: socks5://127.0.0.1:9050", "version: 2024.07.16"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("pinned config missing %q:\n%s", want, data)
+		}
+	}
+
+	if err := SetToolVersion(path, "yt-dlp", ""); err != nil {
+		t.Fatalf("SetToolVersion unpin: %v", err)
+	}
+	data, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != original {
+		t.Errorf("unpin did not restore the file:\n%s\nwant:\n%s", data, original)
+	}
+}
+
+func TestSetToolVersion_UnpinOverridesBase(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "powerhour.yaml")
+	writeFile(t, filepath.Join(root, "base.yaml"), "tools:\n  yt-dlp:\n    version: 2024.07.16\n")
+	writeFile(t, path, "extends: base.yaml\n")
+
+	if err := SetToolVersion(path, "yt-dlp", ""); err != nil {
+		t.Fatalf("SetToolVersion: %v", err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if v := cfg.ToolVersion("yt-dlp"); v != "" {
+		t.Errorf("ToolVersion = %q after unpin, want the base pin dropped", v)
+	}
+}
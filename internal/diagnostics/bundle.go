@@ -0,0 +1,276 @@
+package diagnostics
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"powerhour/internal/secrets"
+	"powerhour/internal/tools"
+)
+
+const (
+	// maxLogsPerDir is how many of the newest logs each logs directory
+	// contributes.
+	maxLogsPerDir = 10
+	// maxLogBytes keeps the tail of each log; the end is where failures are.
+	maxLogBytes = 256 << 10
+)
+
+// Input is what a bundle collects. Empty paths are skipped.
+type Input struct {
+	ProjectConfig  string // powerhour.yaml
+	GlobalConfig   string // ~/.powerhour/config.yaml
+	ProjectLogsDir string
+	GlobalLogsDir  string
+	Tools          []tools.Status
+	Failure        *Failure
+}
+
+// System describes the machine the bundle was written on.
+type System struct {
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+	GoVersion string `json:"go_version"`
+	CPUs      int    `json:"cpus"`
+	Created   string `json:"created"`
+}
+
+// CurrentSystem returns this machine's System.
+func CurrentSystem() System {
+	return System{
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		GoVersion: runtime.Version(),
+		CPUs:      runtime.NumCPU(),
+		Created:   time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+// Write creates the zip at dest and returns the names of its entries.
+// Secret references in the configs are resolved first, where they can be,
+// so their values are scrubbed from every file, and credential fields in
+// the configs are replaced outright. A config or log that can't be read is
+// noted in errors.txt instead of failing the bundle.
+func Write(dest string, in Input) ([]string, error) {
+	b := &bundleWriter{}
+	configs := []struct{ name, path string }{
+		{"config/powerhour.yaml", in.ProjectConfig},
+		{"config/global.yaml", in.GlobalConfig},
+	}
+	for _, c := range configs {
+		if c.path != "" {
+			registerSecrets(c.path)
+		}
+	}
+
+	b.addJSON("system.json", CurrentSystem())
+	if in.Failure != nil {
+		b.addJSON("failure.json", in.Failure)
+	}
+	if len(in.Tools) > 0 {
+		b.addJSON("tools.json", in.Tools)
+	}
+	for _, c := range configs {
+		if c.path == "" {
+			continue
+		}
+		data, err := os.ReadFile(c.path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err == nil {
+			data, err = RedactConfig(data)
+		}
+		if err != nil {
+			b.note(fmt.Sprintf("%s: %v", c.name, err))
+			continue
+		}
+		b.add(c.name, data)
+	}
+	b.addLogs("logs/project", in.ProjectLogsDir)
+	b.addLogs("logs/global", in.GlobalLogsDir)
+	if len(b.errors) > 0 {
+		b.add("errors.txt", []byte(strings.Join(b.errors, "\n")+"\n"))
+	}
+
+	if err := b.write(dest); err != nil {
+		return nil, err
+	}
+	names := make([]string, len(b.files))
+	for i, f := range b.files {
+		names[i] = f.name
+	}
+	return names, nil
+}
+
+// registerSecrets resolves the secret references in a config so
+// secrets.Redact knows their values. Unresolvable ones are skipped.
+func registerSecrets(path string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	for _, name := range secrets.References(string(data)) {
+		_, _ = secrets.Lookup(name)
+	}
+}
+
+type bundleFile struct {
+	name string
+	data []byte
+}
+
+type bundleWriter struct {
+	files  []bundleFile
+	errors []string
+}
+
+func (b *bundleWriter) add(name string, data []byte) {
+	b.files = append(b.files, bundleFile{name: name, data: []byte(secrets.Redact(string(data)))})
+}
+
+func (b *bundleWriter) addJSON(name string, v any) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		b.note(fmt.Sprintf("%s: %v", name, err))
+		return
+	}
+	b.add(name, append(data, '\n'))
+}
+
+func (b *bundleWriter) note(msg string) {
+	b.errors = append(b.errors, msg)
+}
+
+// addLogs adds the tails of the newest maxLogsPerDir logs in dir.
+func (b *bundleWriter) addLogs(prefix, dir string) {
+	if dir == "" {
+		return
+	}
+	logs, err := newestLogs(dir, maxLogsPerDir)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			b.note(fmt.Sprintf("%s: %v", prefix, err))
+		}
+		return
+	}
+	for _, name := range logs {
+		data, err := readTail(filepath.Join(dir, name), maxLogBytes)
+		if err != nil {
+			b.note(fmt.Sprintf("%s/%s: %v", prefix, name, err))
+			continue
+		}
+		b.add(prefix+"/"+name, data)
+	}
+}
+
+// newestLogs returns the names of the newest max .log files in dir, newest
+// first.
+func newestLogs(dir string, max int) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	type logFile struct {
+		name    string
+		modTime time.Time
+	}
+	var logs []logFile
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".log" {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		logs = append(logs, logFile{e.Name(), info.ModTime()})
+	}
+	sort.SliceStable(logs, func(i, j int) bool {
+		if !logs[i].modTime.Equal(logs[j].modTime) {
+			return logs[i].modTime.After(logs[j].modTime)
+		}
+		return logs[i].name > logs[j].name
+	})
+	if len(logs) > max {
+		logs = logs[:max]
+	}
+	names := make([]string, len(logs))
+	for i, l := range logs {
+		names[i] = l.name
+	}
+	return names, nil
+}
+
+// readTail returns the last max bytes of path, starting at a line boundary
+// when it had to cut.
+func readTail(path string, max int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() <= max {
+		return io.ReadAll(f)
+	}
+	if _, err := f.Seek(info.Size()-max, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	if i := strings.IndexByte(string(data), '\n'); i >= 0 {
+		data = data[i+1:]
+	}
+	return append([]byte("[earlier lines omitted]\n"), data...), nil
+}
+
+// write stores the files in a new zip at dest, removing it on failure.
+func (b *bundleWriter) write(dest string) (err error) {
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("create bundle directory: %w", err)
+	}
+	file, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("create bundle: %w", err)
+	}
+	defer func() {
+		if cerr := file.Close(); err == nil && cerr != nil {
+			err = fmt.Errorf("close bundle: %w", cerr)
+		}
+		if err != nil {
+			_ = os.Remove(dest)
+		}
+	}()
+
+	zw := zip.NewWriter(file)
+	now := time.Now()
+	for _, f := range b.files {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return fmt.Errorf("add %s: %w", f.name, err)
+		}
+		if _, err := w.Write(f.data); err != nil {
+			return fmt.Errorf("add %s: %w", f.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("finish bundle: %w", err)
+	}
+	return nil
+}
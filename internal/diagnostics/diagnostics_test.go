@@ -0,0 +1,156 @@
+package diagnostics
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"powerhour/internal/secrets"
+	"powerhour/internal/tools"
+)
+
+func TestRedactConfig(t *testing.T) {
+	in := `video:
+  width: 1920
+notify:
+  webhook: https://hooks.slack.com/services/T0/B0/abc123
+  email:
+    username: me@example.com
+    password: hunter2
+upload:
+  youtube:
+    client_id: my-client
+    client_secret: ${YT_SECRET}
+    refresh_token: 1//refresh
+  http:
+    headers:
+      Authorization: Bearer xyz
+      X-Team: ${TEAM_ID}
+`
+	out, err := RedactConfig([]byte(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(out)
+	for _, leaked := range []string{"abc123", "hunter2", "1//refresh", "Bearer xyz"} {
+		if strings.Contains(got, leaked) {
+			t.Errorf("redacted config still contains %q:\n%s", leaked, got)
+		}
+	}
+	for _, kept := range []string{"width: 1920", "username: me@example.com", "client_id: my-client", "client_secret: ${YT_SECRET}", "X-Team: ${TEAM_ID}"} {
+		if !strings.Contains(got, kept) {
+			t.Errorf("redacted config lost %q:\n%s", kept, got)
+		}
+	}
+	if n := strings.Count(got, secrets.Placeholder); n != 4 {
+		t.Errorf("expected 4 redactions, got %d:\n%s", n, got)
+	}
+}
+
+func TestWriteBundle(t *testing.T) {
+	t.Setenv("PH_DIAG_TOKEN", "s3cret-token-value")
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "powerhour.yaml")
+	writeFile(t, configPath, "telemetry:\n  endpoint: http://localhost:4318\n  headers:\n    Authorization: ${PH_DIAG_TOKEN}\n")
+
+	logsDir := filepath.Join(dir, "logs")
+	if err := os.MkdirAll(logsDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for i := 0; i < maxLogsPerDir+2; i++ {
+		path := filepath.Join(logsDir, strings.Repeat("a", i+1)+".log")
+		writeFile(t, path, "token=s3cret-token-value\n")
+		// Older logs first, so the two shortest names fall off.
+		if err := os.Chtimes(path, now, now.Add(time.Duration(i)*time.Second)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(t, filepath.Join(logsDir, "notes.txt"), "not a log")
+
+	dest := filepath.Join(dir, "out", "bundle.zip")
+	files, err := Write(dest, Input{
+		ProjectConfig:  configPath,
+		GlobalConfig:   filepath.Join(dir, "missing.yaml"),
+		ProjectLogsDir: logsDir,
+		Tools:          []tools.Status{{Tool: "ffmpeg", Version: "7.1", Satisfied: true}},
+		Failure:        &Failure{Args: []string{"render"}, Error: "ffmpeg failed"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"system.json", "failure.json", "tools.json", "config/powerhour.yaml", "logs/project/" + strings.Repeat("a", maxLogsPerDir+2) + ".log"} {
+		if !slices.Contains(files, want) {
+			t.Errorf("bundle is missing %s: %v", want, files)
+		}
+	}
+	if slices.Contains(files, "config/global.yaml") || slices.Contains(files, "logs/project/a.log") {
+		t.Errorf("bundle has files it shouldn't: %v", files)
+	}
+
+	zr, err := zip.OpenReader(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	logs := 0
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		if strings.Contains(string(data), "s3cret-token-value") {
+			t.Errorf("%s leaks the secret:\n%s", f.Name, data)
+		}
+		if strings.HasPrefix(f.Name, "logs/") {
+			logs++
+		}
+	}
+	if logs != maxLogsPerDir {
+		t.Errorf("expected %d logs, got %d", maxLogsPerDir, logs)
+	}
+}
+
+func TestReadTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "long.log")
+	writeFile(t, path, "first line\nsecond line\nthird line\n")
+	data, err := readTail(path, 15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "[earlier lines omitted]\nthird line\n"; got != want {
+		t.Fatalf("readTail = %q, want %q", got, want)
+	}
+}
+
+func TestFailureRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if f, err := LastFailure(); err != nil || f != nil {
+		t.Fatalf("LastFailure with no record = %v, %v", f, err)
+	}
+	want := Failure{Time: time.Date(2026, 10, 17, 20, 0, 0, 0, time.UTC), Args: []string{"render", "--index", "3"}, Dir: "/parties/halloween", Error: "panic: nil map", Panic: true, Stack: "goroutine 1 [running]:"}
+	if err := RecordFailure(want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LastFailure()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Time.Equal(want.Time) || !slices.Equal(got.Args, want.Args) || got.Error != want.Error || !got.Panic || got.Stack != want.Stack {
+		t.Fatalf("LastFailure = %+v, want %+v", got, want)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,64 @@
+// Package diagnostics records the last failed command and writes the
+// diagnostics zip behind `powerhour debug bundle`: the redacted configs,
+// recent logs, tool versions, system info and that failure, for attaching
+// to a bug report.
+package diagnostics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"powerhour/internal/paths"
+	"powerhour/internal/secrets"
+)
+
+// Failure describes a command that returned an error or panicked.
+type Failure struct {
+	Time  time.Time `json:"time"`
+	Args  []string  `json:"args"` // command line after the executable
+	Dir   string    `json:"dir"`  // working directory
+	Error string    `json:"error"`
+	Panic bool      `json:"panic,omitempty"`
+	Stack string    `json:"stack,omitempty"` // goroutine stack of a panic
+}
+
+// RecordFailure saves f as the last failure, replacing the previous one,
+// with resolved secrets redacted.
+func RecordFailure(f Failure) error {
+	path, err := paths.LastFailureFile()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal failure: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(secrets.Redact(string(data))+"\n"), 0o644); err != nil {
+		return fmt.Errorf("write failure record: %w", err)
+	}
+	return nil
+}
+
+// LastFailure returns the last recorded failure, or nil when there is none.
+func LastFailure() (*Failure, error) {
+	path, err := paths.LastFailureFile()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read failure record: %w", err)
+	}
+	var f Failure
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return &f, nil
+}
@@ -0,0 +1,82 @@
+package diagnostics
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"powerhour/internal/secrets"
+)
+
+// sensitiveKeys are config keys whose values are credentials, or URLs that
+// commonly carry them (webhook tokens, stream keys, proxy logins).
+var sensitiveKeys = map[string]bool{
+	"password":          true,
+	"client_secret":     true,
+	"refresh_token":     true,
+	"access_key_id":     true,
+	"secret_access_key": true,
+	"session_token":     true,
+	"webhook":           true,
+	"url":               true,
+	"proxy":             true,
+}
+
+// sensitiveMaps are config keys whose every value is sensitive.
+var sensitiveMaps = map[string]bool{
+	"headers": true,
+}
+
+// RedactConfig returns a powerhour.yaml with credential values replaced by
+// secrets.Placeholder. Values that are only ${NAME} references are kept,
+// since they name a secret without revealing it, and any secret resolved
+// in this process is scrubbed from the rest.
+func RedactConfig(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+	redactNode(&doc, false)
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("encode config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("encode config: %w", err)
+	}
+	return []byte(secrets.Redact(buf.String())), nil
+}
+
+// redactNode walks node, redacting the scalars below a sensitive key.
+func redactNode(node *yaml.Node, sensitive bool) {
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			redactNode(child, sensitive)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := strings.ToLower(node.Content[i].Value)
+			redactNode(node.Content[i+1], sensitive || sensitiveKeys[key] || sensitiveMaps[key])
+		}
+	case yaml.ScalarNode:
+		if sensitive && node.Value != "" && !onlyReferences(node.Value) {
+			node.Value = secrets.Placeholder
+			node.Style = 0
+			node.Tag = "!!str"
+		}
+	}
+}
+
+// onlyReferences reports whether value is made of ${NAME} references alone.
+func onlyReferences(value string) bool {
+	rest := value
+	for _, name := range secrets.References(value) {
+		rest = strings.ReplaceAll(rest, "${"+name+"}", "")
+	}
+	return strings.TrimSpace(rest) == ""
+}
@@ -0,0 +1,26 @@
+// Package diskspace reports free space on the volume holding a path, so
+// fetch and render can check for headroom before they start writing.
+package diskspace
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Available returns the bytes available to the current user on the volume
+// holding path. A path that doesn't exist yet is checked through its
+// nearest existing parent.
+func Available(path string) (uint64, error) {
+	path = filepath.Clean(path)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			break
+		}
+		path = parent
+	}
+	return available(path)
+}
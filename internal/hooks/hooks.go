@@ -0,0 +1,140 @@
+// Package hooks runs the user's shell commands at pipeline events
+// (hooks.pre_fetch, hooks.post_segment, hooks.post_concat in powerhour.yaml).
+// Each command runs in the project root with the event as JSON on stdin and
+// POWERHOUR_EVENT/POWERHOUR_PROJECT in its environment, so scripts can
+// upload segments, post progress, or run their own checks.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+
+	"powerhour/internal/config"
+)
+
+// Event names a pipeline point hooks can attach to.
+type Event string
+
+const (
+	PreFetch    Event = "pre_fetch"
+	PostSegment Event = "post_segment"
+	PostConcat  Event = "post_concat"
+)
+
+// Runner runs a project's hooks. The zero value runs nothing.
+type Runner struct {
+	commands map[Event][]string
+	root     string
+	output   io.Writer
+
+	mu sync.Mutex // one hook at a time, even from concurrent renders
+}
+
+// New returns a runner for cfg's hooks that runs them in root and sends
+// their stdout and stderr to output (nil discards it).
+func New(cfg config.HooksConfig, root string, output io.Writer) *Runner {
+	if output == nil {
+		output = io.Discard
+	}
+	return &Runner{
+		commands: map[Event][]string{
+			PreFetch:    cfg.PreFetch,
+			PostSegment: cfg.PostSegment,
+			PostConcat:  cfg.PostConcat,
+		},
+		root:   root,
+		output: output,
+	}
+}
+
+// Has reports whether any command is configured for event.
+func (r *Runner) Has(event Event) bool {
+	return r != nil && len(r.commands[event]) > 0
+}
+
+// Run runs event's commands in order, each with payload merged into the
+// JSON document on its stdin. Every command runs even if an earlier one
+// fails; the failures are joined into the returned error.
+func (r *Runner) Run(ctx context.Context, event Event, payload any) error {
+	if !r.Has(event) {
+		return nil
+	}
+	input, err := encodePayload(event, r.root, payload)
+	if err != nil {
+		return fmt.Errorf("%s hook: %w", event, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var errs []error
+	for _, command := range r.commands[event] {
+		cmd := shellCommand(ctx, command)
+		cmd.Dir = r.root
+		cmd.Env = append(os.Environ(), "POWERHOUR_EVENT="+string(event), "POWERHOUR_PROJECT="+r.root)
+		cmd.Stdin = bytes.NewReader(input)
+		var tail tailWriter
+		out := io.MultiWriter(r.output, &tail)
+		cmd.Stdout, cmd.Stderr = out, out
+		if err := cmd.Run(); err != nil {
+			if line := tail.lastLine(); line != "" {
+				err = fmt.Errorf("%w: %s", err, line)
+			}
+			errs = append(errs, fmt.Errorf("%s hook %q: %w", event, command, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// encodePayload adds the event and project to payload's JSON object.
+func encodePayload(event Event, root string, payload any) ([]byte, error) {
+	doc := map[string]any{}
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("payload must be a JSON object: %w", err)
+		}
+	}
+	doc["event"] = string(event)
+	doc["project"] = root
+	return json.Marshal(doc)
+}
+
+// tailWriter keeps the end of a hook's output so a failure can say why.
+type tailWriter struct {
+	buf []byte
+}
+
+const tailSize = 4096
+
+func (t *tailWriter) Write(p []byte) (int, error) {
+	t.buf = append(t.buf, p...)
+	if len(t.buf) > tailSize {
+		t.buf = t.buf[len(t.buf)-tailSize:]
+	}
+	return len(p), nil
+}
+
+// lastLine returns the last non-blank output line.
+func (t *tailWriter) lastLine() string {
+	lines := strings.Split(strings.TrimSpace(string(t.buf)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
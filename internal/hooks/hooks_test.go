@@ -0,0 +1,68 @@
+package hooks
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"powerhour/internal/config"
+)
+
+func TestRunPassesPayloadAndEnv(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	root := t.TempDir()
+	r := New(config.HooksConfig{
+		PostConcat: []string{
+			`cat > payload.json`,
+			`echo "$POWERHOUR_EVENT $POWERHOUR_PROJECT" > env.txt`,
+		},
+	}, root, nil)
+
+	if err := r.Run(t.Context(), PostConcat, map[string]any{"output": "powerhour.mp4"}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(root, "payload.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var payload map[string]string
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("payload %s: %v", data, err)
+	}
+	if payload["event"] != "post_concat" || payload["project"] != root || payload["output"] != "powerhour.mp4" {
+		t.Errorf("payload = %v", payload)
+	}
+	env, _ := os.ReadFile(filepath.Join(root, "env.txt"))
+	if got := strings.TrimSpace(string(env)); got != "post_concat "+root {
+		t.Errorf("env = %q", got)
+	}
+}
+
+func TestRunReportsFailuresAndKeepsGoing(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	root := t.TempDir()
+	r := New(config.HooksConfig{
+		PreFetch: []string{`echo checking; echo "plan has 3 duplicates" >&2; exit 3`, `touch ran`},
+	}, root, nil)
+
+	err := r.Run(t.Context(), PreFetch, nil)
+	if err == nil || !strings.Contains(err.Error(), "exit status 3: plan has 3 duplicates") {
+		t.Fatalf("err = %v", err)
+	}
+	if _, statErr := os.Stat(filepath.Join(root, "ran")); statErr != nil {
+		t.Error("second hook did not run after the first failed")
+	}
+	if r.Has(PostSegment) {
+		t.Error("Has(PostSegment) = true with no commands")
+	}
+	if err := r.Run(t.Context(), PostSegment, nil); err != nil {
+		t.Errorf("Run with no commands = %v", err)
+	}
+}
@@ -1,26 +1,90 @@
 package logx
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
+	"sync"
 	"time"
 
 	"powerhour/internal/paths"
+	"powerhour/internal/secrets"
 )
 
-const maxGlobalLogFiles = 50
+const (
+	maxGlobalLogFiles  = 50
+	maxProjectLogFiles = 50
+)
 
-// New creates a logger that writes to a timestamped file inside the project's
-// logs directory. The returned closer should be closed when logging is no
-// longer needed.
-func New(p paths.ProjectPaths) (*log.Logger, io.Closer, error) {
+// runLogName matches the per-run log files New writes to a project's logs
+// directory, which it shares with the per-segment ffmpeg logs.
+var runLogName = regexp.MustCompile(`^\d{8}-\d{6}\.log$`)
+
+// Options control the logs of a run. The root command sets them from
+// --verbose, --quiet and --log-json before any command runs.
+type Options struct {
+	Level   slog.Level // lowest level written; info by default
+	JSON    bool       // JSON lines instead of key=value text
+	Console io.Writer  // when set, records are echoed here as text too
+}
+
+var (
+	optionsMu sync.RWMutex
+	options   = Options{Level: slog.LevelInfo}
+)
+
+// Configure sets the options for loggers opened afterwards.
+func Configure(opts Options) {
+	optionsMu.Lock()
+	defer optionsMu.Unlock()
+	options = opts
+}
+
+func currentOptions() Options {
+	optionsMu.RLock()
+	defer optionsMu.RUnlock()
+	return options
+}
+
+// Echoing reports whether log records are echoed to the console
+// (--verbose), which progress tables would draw over.
+func Echoing() bool {
+	return currentOptions().Console != nil
+}
+
+// newLogger returns a logger writing to w in the configured format, with
+// secrets redacted, and echoing to the console when configured.
+func newLogger(w io.Writer) *slog.Logger {
+	opts := currentOptions()
+	handlerOpts := &slog.HandlerOptions{Level: opts.Level}
+	w = secrets.NewRedactingWriter(w)
+	var handler slog.Handler
+	if opts.JSON {
+		handler = slog.NewJSONHandler(w, handlerOpts)
+	} else {
+		handler = slog.NewTextHandler(w, handlerOpts)
+	}
+	if opts.Console != nil {
+		handler = teeHandler{handler, slog.NewTextHandler(secrets.NewRedactingWriter(opts.Console), handlerOpts)}
+	}
+	return slog.New(handler)
+}
+
+// Open creates a logger that writes to a timestamped file inside the
+// project's logs directory, keeping the newest maxProjectLogFiles run logs.
+// The returned closer should be closed when logging is no longer needed.
+func Open(p paths.ProjectPaths) (*slog.Logger, io.Closer, error) {
 	if err := os.MkdirAll(p.LogsDir, 0o755); err != nil {
 		return nil, nil, fmt.Errorf("ensure logs directory: %w", err)
 	}
+	pruneLogs(p.LogsDir, maxProjectLogFiles, runLogName)
 
 	filename := time.Now().Format("20060102-150405") + ".log"
 	filePath := filepath.Join(p.LogsDir, filename)
@@ -28,19 +92,36 @@ func New(p paths.ProjectPaths) (*log.Logger, io.Closer, error) {
 	if err != nil {
 		return nil, nil, fmt.Errorf("open log file: %w", err)
 	}
+	return newLogger(file), file, nil
+}
 
-	logger := log.New(file, "", log.LstdFlags|log.Lmicroseconds)
-	return logger, file, nil
+// New is Open for callers that log with Printf; each line is an info
+// record, so failures belong on the logger from Open instead, where they
+// survive --quiet.
+func New(p paths.ProjectPaths) (*log.Logger, io.Closer, error) {
+	logger, closer, err := Open(p)
+	if err != nil {
+		return nil, nil, err
+	}
+	return PrintLogger(logger), closer, nil
 }
 
-// NewGlobal creates a logger that writes to ~/.powerhour/logs/<timestamp>.log.
-// Use this for logging that happens outside a project context or for
-// debugging CLI startup issues.
-func NewGlobal(prefix string) (*log.Logger, io.Closer, error) {
+// PrintLogger adapts logger for code that takes a *log.Logger (the cache
+// service, hooks); each line is an info record.
+func PrintLogger(logger *slog.Logger) *log.Logger {
+	return slog.NewLogLogger(logger.Handler(), slog.LevelInfo)
+}
+
+// OpenGlobal creates a logger that writes to
+// ~/.powerhour/logs/<prefix>-<timestamp>.log, keeping the newest
+// maxGlobalLogFiles logs. Use this for logging that happens outside a
+// project context or for debugging CLI startup issues.
+func OpenGlobal(prefix string) (*slog.Logger, io.Closer, error) {
 	logsDir, err := paths.GlobalLogsDir()
 	if err != nil {
 		return nil, nil, err
 	}
+	pruneGlobalLogs(logsDir, maxGlobalLogFiles)
 
 	filename := time.Now().Format("20060102-150405")
 	if prefix != "" {
@@ -53,9 +134,16 @@ func NewGlobal(prefix string) (*log.Logger, io.Closer, error) {
 	if err != nil {
 		return nil, nil, fmt.Errorf("open global log file: %w", err)
 	}
+	return newLogger(file).With("command", prefix), file, nil
+}
 
-	logger := log.New(file, "", log.LstdFlags|log.Lmicroseconds)
-	return logger, file, nil
+// NewGlobal is OpenGlobal for callers that log with Printf.
+func NewGlobal(prefix string) (*log.Logger, io.Closer, error) {
+	logger, closer, err := OpenGlobal(prefix)
+	if err != nil {
+		return nil, nil, err
+	}
+	return PrintLogger(logger), closer, nil
 }
 
 // nopCloser is a no-op closer for when logger creation fails.
@@ -63,51 +151,122 @@ type nopCloser struct{}
 
 func (nopCloser) Close() error { return nil }
 
-// StartCommand creates a global logger for a CLI command and returns a printf-style
-// logging function along with a closer. The caller must defer closer.Close().
-// On failure the returned logf is a no-op and closer is safe to call.
-func StartCommand(prefix string) (logf func(string, ...any), closer io.Closer) {
-	logsDir, _ := paths.GlobalLogsDir()
-	if logsDir != "" {
-		pruneGlobalLogs(logsDir, maxGlobalLogFiles)
-	}
-
-	glog, c, err := NewGlobal(prefix)
+// Command creates the global logger for a CLI command. The caller must
+// defer closer.Close(). On failure the logger discards everything and the
+// closer is safe to call.
+func Command(prefix string) (*slog.Logger, io.Closer) {
+	logger, c, err := OpenGlobal(prefix)
 	if err != nil || c == nil {
-		return func(string, ...any) {}, nopCloser{}
+		return slog.New(discardHandler{}), nopCloser{}
 	}
-	return func(format string, v ...any) { glog.Printf(format, v...) }, c
+	return logger, c
+}
+
+// StartCommand is Command for commands that log with a printf-style
+// function; each call is an info record. Commands that log failures use
+// Command and its Error/Warn instead, so --quiet keeps them.
+func StartCommand(prefix string) (logf func(string, ...any), closer io.Closer) {
+	logger, c := Command(prefix)
+	return Printf(logger), c
+}
+
+// Printf returns a printf-style function logging info records to logger.
+func Printf(logger *slog.Logger) func(string, ...any) {
+	return func(format string, v ...any) { logger.Info(fmt.Sprintf(format, v...)) }
 }
 
 // pruneGlobalLogs removes the oldest log files when the count exceeds maxFiles.
 // Errors are silently ignored — this is best-effort cleanup.
 func pruneGlobalLogs(logsDir string, maxFiles int) {
+	pruneLogs(logsDir, maxFiles, nil)
+}
+
+// pruneLogs removes the oldest .log files in logsDir, those matching name
+// when it is set, while there are more than maxFiles. Names start with
+// their timestamp, or a command prefix and then the timestamp, so runs
+// are ordered by modification time. Errors are ignored.
+func pruneLogs(logsDir string, maxFiles int, name *regexp.Regexp) {
 	entries, err := os.ReadDir(logsDir)
 	if err != nil {
 		return
 	}
 
-	// Filter to only .log files
-	var logs []string
+	type logFile struct {
+		name    string
+		modTime time.Time
+	}
+	var logs []logFile
 	for _, e := range entries {
-		if e.IsDir() {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".log" {
 			continue
 		}
-		if filepath.Ext(e.Name()) == ".log" {
-			logs = append(logs, e.Name())
+		if name != nil && !name.MatchString(e.Name()) {
+			continue
 		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		logs = append(logs, logFile{e.Name(), info.ModTime()})
 	}
 
 	if len(logs) <= maxFiles {
 		return
 	}
 
-	// Sort by name ascending (timestamps sort naturally)
-	sort.Strings(logs)
+	sort.SliceStable(logs, func(i, j int) bool {
+		if !logs[i].modTime.Equal(logs[j].modTime) {
+			return logs[i].modTime.Before(logs[j].modTime)
+		}
+		return logs[i].name < logs[j].name
+	})
+	for _, l := range logs[:len(logs)-maxFiles] {
+		os.Remove(filepath.Join(logsDir, l.name))
+	}
+}
+
+// teeHandler sends each record to every handler that takes its level.
+type teeHandler []slog.Handler
+
+func (t teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range t {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
 
-	// Remove the oldest files beyond the cap
-	toRemove := logs[:len(logs)-maxFiles]
-	for _, name := range toRemove {
-		os.Remove(filepath.Join(logsDir, name))
+func (t teeHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range t {
+		if h.Enabled(ctx, r.Level) {
+			errs = append(errs, h.Handle(ctx, r.Clone()))
+		}
 	}
+	return errors.Join(errs...)
 }
+
+func (t teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make(teeHandler, len(t))
+	for i, h := range t {
+		out[i] = h.WithAttrs(attrs)
+	}
+	return out
+}
+
+func (t teeHandler) WithGroup(name string) slog.Handler {
+	out := make(teeHandler, len(t))
+	for i, h := range t {
+		out[i] = h.WithGroup(name)
+	}
+	return out
+}
+
+// discardHandler drops every record.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (d discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return d }
+func (d discardHandler) WithGroup(string) slog.Handler           { return d }
@@ -1,10 +1,16 @@
 package logx
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"powerhour/internal/paths"
 )
 
 func TestPruneGlobalLogs_RemovesOldest(t *testing.T) {
@@ -102,3 +108,86 @@ func TestPruneGlobalLogs_NonexistentDir(t *testing.T) {
 	// Should not panic
 	pruneGlobalLogs("/nonexistent/path/that/doesnt/exist", 50)
 }
+
+func TestPruneProjectLogs_KeepsSegmentLogs(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 5; i++ {
+		os.WriteFile(filepath.Join(dir, fmt.Sprintf("20240101-00000%d.log", i)), []byte("test"), 0o644)
+		os.WriteFile(filepath.Join(dir, fmt.Sprintf("%03d_song.log", i)), []byte("test"), 0o644)
+	}
+
+	pruneLogs(dir, 2, runLogName)
+
+	entries, _ := os.ReadDir(dir)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	want := "000_song.log 001_song.log 002_song.log 003_song.log 004_song.log 20240101-000003.log 20240101-000004.log"
+	if got := strings.Join(names, " "); got != want {
+		t.Fatalf("remaining logs = %s\nwant %s", got, want)
+	}
+}
+
+func configureForTest(t *testing.T, opts Options) {
+	t.Helper()
+	Configure(opts)
+	t.Cleanup(func() { Configure(Options{Level: slog.LevelInfo}) })
+}
+
+func TestOpenLevels(t *testing.T) {
+	configureForTest(t, Options{Level: slog.LevelWarn})
+	pp := paths.ProjectPaths{LogsDir: t.TempDir()}
+	logger, closer, err := Open(pp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Debug("debug detail")
+	logger.Info("progress")
+	logger.Warn("slow source", "index", 3)
+	closer.Close()
+
+	data := readOnlyLog(t, pp.LogsDir)
+	if strings.Contains(data, "debug detail") || strings.Contains(data, "progress") {
+		t.Errorf("records below warn were written:\n%s", data)
+	}
+	if !strings.Contains(data, `level=WARN msg="slow source" index=3`) {
+		t.Errorf("missing warn record:\n%s", data)
+	}
+}
+
+func TestOpenJSONAndConsole(t *testing.T) {
+	var console bytes.Buffer
+	configureForTest(t, Options{Level: slog.LevelDebug, JSON: true, Console: &console})
+	pp := paths.ProjectPaths{LogsDir: t.TempDir()}
+	logger, closer, err := Open(pp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Debug("resolved", "root", "/tmp/project")
+	closer.Close()
+
+	var record map[string]any
+	if err := json.Unmarshal([]byte(readOnlyLog(t, pp.LogsDir)), &record); err != nil {
+		t.Fatalf("log file isn't JSON lines: %v", err)
+	}
+	if record["level"] != "DEBUG" || record["msg"] != "resolved" || record["root"] != "/tmp/project" {
+		t.Errorf("unexpected record %v", record)
+	}
+	if !strings.Contains(console.String(), `level=DEBUG msg=resolved root=/tmp/project`) {
+		t.Errorf("console echo = %q", console.String())
+	}
+}
+
+func readOnlyLog(t *testing.T, dir string) string {
+	t.Helper()
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.log"))
+	if len(matches) != 1 {
+		t.Fatalf("expected one log file, got %v", matches)
+	}
+	data, err := os.ReadFile(matches[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
@@ -0,0 +1,50 @@
+package nle
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// edlRecordStartHours is where the record side begins, the usual 01:00:00:00
+// program start that Resolve and Premiere default to.
+const edlRecordStartHours = 1
+
+// WriteEDL writes a CMX3600 EDL. Every event uses the AX (auxiliary) reel
+// with the clip name and source file in comments, which Resolve and
+// Premiere use to relink media.
+func WriteEDL(w io.Writer, tl Timeline) error {
+	bw := bufio.NewWriter(w)
+	title := strings.TrimSpace(tl.Name)
+	if title == "" {
+		title = "Power Hour"
+	}
+	fmt.Fprintf(bw, "TITLE: %s\n", title)
+	fmt.Fprintf(bw, "FCM: NON-DROP FRAME\n\n")
+
+	record := int64(edlRecordStartHours*3600) * int64(tl.FPS)
+	for i, c := range tl.Clips {
+		in := frames(c.In, tl.FPS)
+		length := frames(c.Duration, tl.FPS)
+		fmt.Fprintf(bw, "%03d  AX       AA/V  C        %s %s %s %s\n",
+			i+1,
+			timecode(in, tl.FPS), timecode(in+length, tl.FPS),
+			timecode(record, tl.FPS), timecode(record+length, tl.FPS))
+		fmt.Fprintf(bw, "* FROM CLIP NAME: %s\n", edlComment(c.Name))
+		fmt.Fprintf(bw, "* SOURCE FILE: %s\n\n", edlComment(c.Source))
+		record += length
+	}
+	return bw.Flush()
+}
+
+// timecode formats a frame count as non-drop HH:MM:SS:FF.
+func timecode(frame int64, fps int) string {
+	f := int64(fps)
+	return fmt.Sprintf("%02d:%02d:%02d:%02d", frame/(3600*f), frame/(60*f)%60, frame/f%60, frame%f)
+}
+
+// edlComment keeps a comment on one line.
+func edlComment(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(strings.TrimSpace(s))
+}
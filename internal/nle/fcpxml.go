@@ -0,0 +1,179 @@
+package nle
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// fcpxmlVersion is the oldest FCPXML version that Final Cut Pro 10.5+ and
+// Resolve both import with media-rep assets.
+const fcpxmlVersion = "1.9"
+
+type fcpxmlDoc struct {
+	XMLName   xml.Name        `xml:"fcpxml"`
+	Version   string          `xml:"version,attr"`
+	Resources fcpxmlResources `xml:"resources"`
+	Library   fcpxmlLibrary   `xml:"library"`
+}
+
+type fcpxmlResources struct {
+	Format fcpxmlFormat  `xml:"format"`
+	Assets []fcpxmlAsset `xml:"asset"`
+}
+
+type fcpxmlFormat struct {
+	ID            string `xml:"id,attr"`
+	Name          string `xml:"name,attr,omitempty"`
+	FrameDuration string `xml:"frameDuration,attr"`
+	Width         int    `xml:"width,attr,omitempty"`
+	Height        int    `xml:"height,attr,omitempty"`
+}
+
+type fcpxmlAsset struct {
+	ID       string         `xml:"id,attr"`
+	Name     string         `xml:"name,attr"`
+	Start    string         `xml:"start,attr"`
+	Duration string         `xml:"duration,attr,omitempty"`
+	HasVideo int            `xml:"hasVideo,attr"`
+	HasAudio int            `xml:"hasAudio,attr"`
+	Format   string         `xml:"format,attr"`
+	MediaRep fcpxmlMediaRep `xml:"media-rep"`
+}
+
+type fcpxmlMediaRep struct {
+	Kind string `xml:"kind,attr"`
+	Src  string `xml:"src,attr"`
+}
+
+type fcpxmlLibrary struct {
+	Event fcpxmlEvent `xml:"event"`
+}
+
+type fcpxmlEvent struct {
+	Name    string        `xml:"name,attr"`
+	Project fcpxmlProject `xml:"project"`
+}
+
+type fcpxmlProject struct {
+	Name     string         `xml:"name,attr"`
+	Sequence fcpxmlSequence `xml:"sequence"`
+}
+
+type fcpxmlSequence struct {
+	Format   string      `xml:"format,attr"`
+	Duration string      `xml:"duration,attr"`
+	TCStart  string      `xml:"tcStart,attr"`
+	TCFormat string      `xml:"tcFormat,attr"`
+	Spine    fcpxmlSpine `xml:"spine"`
+}
+
+type fcpxmlSpine struct {
+	Clips []fcpxmlAssetClip `xml:"asset-clip"`
+}
+
+type fcpxmlAssetClip struct {
+	Ref      string          `xml:"ref,attr"`
+	Name     string          `xml:"name,attr"`
+	Offset   string          `xml:"offset,attr"`
+	Start    string          `xml:"start,attr"`
+	Duration string          `xml:"duration,attr"`
+	Metadata *fcpxmlMetadata `xml:"metadata,omitempty"`
+}
+
+type fcpxmlMetadata struct {
+	Items []fcpxmlMD `xml:"md"`
+}
+
+type fcpxmlMD struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// WriteFCPXML writes an FCPXML document with one asset per distinct source
+// and an asset-clip per cut on the primary storyline.
+func WriteFCPXML(w io.Writer, tl Timeline) error {
+	name := strings.TrimSpace(tl.Name)
+	if name == "" {
+		name = "Power Hour"
+	}
+	rational := func(frameCount int64) string {
+		if frameCount == 0 {
+			return "0s"
+		}
+		return fmt.Sprintf("%d/%ds", frameCount, tl.FPS)
+	}
+
+	doc := fcpxmlDoc{Version: fcpxmlVersion}
+	doc.Resources.Format = fcpxmlFormat{
+		ID:            "r1",
+		FrameDuration: fmt.Sprintf("1/%ds", tl.FPS),
+		Width:         tl.Width,
+		Height:        tl.Height,
+	}
+
+	durations := map[string]float64{}
+	for _, c := range tl.Clips {
+		if c.SourceDuration > durations[c.Source] {
+			durations[c.Source] = c.SourceDuration
+		}
+	}
+	refs := map[string]string{}
+	for i, src := range sources(tl.Clips) {
+		id := fmt.Sprintf("r%d", i+2)
+		refs[src] = id
+		asset := fcpxmlAsset{
+			ID:       id,
+			Name:     strings.TrimSuffix(filepath.Base(src), filepath.Ext(src)),
+			Start:    "0s",
+			HasVideo: 1,
+			HasAudio: 1,
+			Format:   "r1",
+			MediaRep: fcpxmlMediaRep{Kind: "original-media", Src: fileURL(src)},
+		}
+		if d := durations[src]; d > 0 {
+			asset.Duration = rational(frames(d, tl.FPS))
+		}
+		doc.Resources.Assets = append(doc.Resources.Assets, asset)
+	}
+
+	var offset int64
+	for _, c := range tl.Clips {
+		length := frames(c.Duration, tl.FPS)
+		clip := fcpxmlAssetClip{
+			Ref:      refs[c.Source],
+			Name:     c.Name,
+			Offset:   rational(offset),
+			Start:    rational(frames(c.In, tl.FPS)),
+			Duration: rational(length),
+		}
+		if len(c.Metadata) > 0 {
+			md := &fcpxmlMetadata{}
+			for _, k := range sortedKeys(c.Metadata) {
+				md.Items = append(md.Items, fcpxmlMD{Key: "com.powerhour." + k, Value: c.Metadata[k]})
+			}
+			clip.Metadata = md
+		}
+		doc.Library.Event.Project.Sequence.Spine.Clips = append(doc.Library.Event.Project.Sequence.Spine.Clips, clip)
+		offset += length
+	}
+	doc.Library.Event.Name = name
+	doc.Library.Event.Project.Name = name
+	doc.Library.Event.Project.Sequence.Format = "r1"
+	doc.Library.Event.Project.Sequence.Duration = rational(offset)
+	doc.Library.Event.Project.Sequence.TCStart = "0s"
+	doc.Library.Event.Project.Sequence.TCFormat = "NDF"
+
+	if _, err := io.WriteString(w, xml.Header+"<!DOCTYPE fcpxml>\n"); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "    ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
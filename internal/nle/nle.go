@@ -0,0 +1,108 @@
+// Package nle writes a resolved power hour timeline in interchange formats
+// that editing applications import: CMX3600 EDL, OpenTimelineIO JSON, and
+// FCPXML. Each clip references its original source file with in/out points,
+// so the hour can be finished in DaVinci Resolve, Premiere, or Final Cut.
+package nle
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Timeline is an ordered list of clips on a single video+audio track.
+type Timeline struct {
+	Name   string
+	FPS    int
+	Width  int
+	Height int
+	Clips  []Clip
+}
+
+// Clip is one cut of a source file.
+type Clip struct {
+	Name   string
+	Source string // absolute path to the source media
+	// In is where the cut starts in the source, in seconds.
+	In float64
+	// Duration is the cut length in seconds.
+	Duration float64
+	// SourceDuration is the full source length when known, 0 otherwise.
+	SourceDuration float64
+	// Metadata is carried where the format allows (OTIO metadata, EDL
+	// comments are limited to the clip name and source file).
+	Metadata map[string]string
+}
+
+// Formats lists the supported format names in display order.
+var Formats = []string{"edl", "otio", "fcpxml"}
+
+// Extension returns the conventional file extension for a format.
+func Extension(format string) string {
+	switch format {
+	case "otio":
+		return ".otio"
+	case "fcpxml":
+		return ".fcpxml"
+	default:
+		return ".edl"
+	}
+}
+
+// Write encodes tl in the named format.
+func Write(w io.Writer, format string, tl Timeline) error {
+	if tl.FPS <= 0 {
+		return fmt.Errorf("timeline frame rate must be positive")
+	}
+	switch format {
+	case "edl":
+		return WriteEDL(w, tl)
+	case "otio":
+		return WriteOTIO(w, tl)
+	case "fcpxml":
+		return WriteFCPXML(w, tl)
+	default:
+		return fmt.Errorf("unknown format %q (choose from %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// frames converts seconds to a whole frame count at fps.
+func frames(seconds float64, fps int) int64 {
+	return int64(math.Round(seconds * float64(fps)))
+}
+
+// fileURL returns a file:// URL for an absolute path, with a leading slash
+// before Windows drive letters.
+func fileURL(path string) string {
+	p := filepath.ToSlash(path)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	return (&url.URL{Scheme: "file", Path: p}).String()
+}
+
+// sources returns the distinct source paths in first-use order.
+func sources(clips []Clip) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, c := range clips {
+		if !seen[c.Source] {
+			seen[c.Source] = true
+			out = append(out, c.Source)
+		}
+	}
+	return out
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
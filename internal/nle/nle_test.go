@@ -0,0 +1,126 @@
+package nle
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func testTimeline() Timeline {
+	return Timeline{
+		Name:   "party",
+		FPS:    30,
+		Width:  1920,
+		Height: 1080,
+		Clips: []Clip{
+			{Name: "01. Song - Band", Source: "/media/a b.webm", In: 83, Duration: 60, SourceDuration: 200, Metadata: map[string]string{"row": "1"}},
+			{Name: "02. Intro", Source: "/media/intro.mp4", Duration: 4.5},
+		},
+	}
+}
+
+func TestTimecode(t *testing.T) {
+	tests := []struct {
+		frame int64
+		fps   int
+		want  string
+	}{
+		{0, 30, "00:00:00:00"},
+		{29, 30, "00:00:00:29"},
+		{30, 30, "00:00:01:00"},
+		{108000, 30, "01:00:00:00"},
+		{25*3661 + 3, 25, "01:01:01:03"},
+	}
+	for _, tt := range tests {
+		if got := timecode(tt.frame, tt.fps); got != tt.want {
+			t.Errorf("timecode(%d, %d) = %q, want %q", tt.frame, tt.fps, got, tt.want)
+		}
+	}
+}
+
+func TestWriteEDL(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, "edl", testTimeline()); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"TITLE: party\n",
+		"001  AX       AA/V  C        00:01:23:00 00:02:23:00 01:00:00:00 01:01:00:00\n",
+		"002  AX       AA/V  C        00:00:00:00 00:00:04:15 01:01:00:00 01:01:04:15\n",
+		"* FROM CLIP NAME: 01. Song - Band\n",
+		"* SOURCE FILE: /media/a b.webm\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("EDL missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteOTIO(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, "otio", testTimeline()); err != nil {
+		t.Fatal(err)
+	}
+	var doc otioTimeline
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(doc.Tracks.Children) != 2 || doc.Tracks.Children[1].Kind != "Audio" {
+		t.Fatalf("tracks = %+v", doc.Tracks.Children)
+	}
+	clip := doc.Tracks.Children[0].Children[0]
+	if clip.SourceRange.StartTime.Value != 2490 || clip.SourceRange.Duration.Value != 1800 {
+		t.Errorf("source_range = %+v", clip.SourceRange)
+	}
+	if clip.MediaReference.TargetURL != "file:///media/a%20b.webm" {
+		t.Errorf("target_url = %q", clip.MediaReference.TargetURL)
+	}
+	if clip.MediaReference.AvailableRange == nil || clip.MediaReference.AvailableRange.Duration.Value != 6000 {
+		t.Errorf("available_range = %+v", clip.MediaReference.AvailableRange)
+	}
+	if doc.Tracks.Children[0].Children[1].MediaReference.AvailableRange != nil {
+		t.Error("expected no available_range without a source duration")
+	}
+}
+
+func TestWriteFCPXML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, "fcpxml", testTimeline()); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "<!DOCTYPE fcpxml>") {
+		t.Error("missing doctype")
+	}
+	var doc fcpxmlDoc
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid XML: %v", err)
+	}
+	if doc.Resources.Format.FrameDuration != "1/30s" || len(doc.Resources.Assets) != 2 {
+		t.Fatalf("resources = %+v", doc.Resources)
+	}
+	if got := doc.Resources.Assets[0].Duration; got != "6000/30s" {
+		t.Errorf("asset duration = %q", got)
+	}
+	seq := doc.Library.Event.Project.Sequence
+	if seq.Duration != "1935/30s" {
+		t.Errorf("sequence duration = %q", seq.Duration)
+	}
+	second := seq.Spine.Clips[1]
+	if second.Ref != "r3" || second.Offset != "1800/30s" || second.Start != "0s" || second.Duration != "135/30s" {
+		t.Errorf("second clip = %+v", second)
+	}
+}
+
+func TestWriteRejectsBadInput(t *testing.T) {
+	tl := testTimeline()
+	if err := Write(&bytes.Buffer{}, "aaf", tl); err == nil {
+		t.Error("expected error for unknown format")
+	}
+	tl.FPS = 0
+	if err := Write(&bytes.Buffer{}, "edl", tl); err == nil {
+		t.Error("expected error for zero frame rate")
+	}
+}
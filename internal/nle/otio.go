@@ -0,0 +1,114 @@
+package nle
+
+import (
+	"encoding/json"
+	"io"
+)
+
+type otioRationalTime struct {
+	Schema string  `json:"OTIO_SCHEMA"`
+	Rate   float64 `json:"rate"`
+	Value  float64 `json:"value"`
+}
+
+type otioTimeRange struct {
+	Schema    string           `json:"OTIO_SCHEMA"`
+	StartTime otioRationalTime `json:"start_time"`
+	Duration  otioRationalTime `json:"duration"`
+}
+
+type otioReference struct {
+	Schema         string            `json:"OTIO_SCHEMA"`
+	Name           string            `json:"name"`
+	TargetURL      string            `json:"target_url"`
+	AvailableRange *otioTimeRange    `json:"available_range"`
+	Metadata       map[string]string `json:"metadata"`
+}
+
+type otioClip struct {
+	Schema         string         `json:"OTIO_SCHEMA"`
+	Name           string         `json:"name"`
+	SourceRange    otioTimeRange  `json:"source_range"`
+	MediaReference otioReference  `json:"media_reference"`
+	Effects        []any          `json:"effects"`
+	Markers        []any          `json:"markers"`
+	Metadata       map[string]any `json:"metadata"`
+}
+
+type otioTrack struct {
+	Schema      string         `json:"OTIO_SCHEMA"`
+	Name        string         `json:"name"`
+	Kind        string         `json:"kind"`
+	Children    []otioClip     `json:"children"`
+	SourceRange *otioTimeRange `json:"source_range"`
+	Effects     []any          `json:"effects"`
+	Markers     []any          `json:"markers"`
+	Metadata    map[string]any `json:"metadata"`
+}
+
+type otioStack struct {
+	Schema      string         `json:"OTIO_SCHEMA"`
+	Name        string         `json:"name"`
+	Children    []otioTrack    `json:"children"`
+	SourceRange *otioTimeRange `json:"source_range"`
+	Effects     []any          `json:"effects"`
+	Markers     []any          `json:"markers"`
+	Metadata    map[string]any `json:"metadata"`
+}
+
+type otioTimeline struct {
+	Schema          string            `json:"OTIO_SCHEMA"`
+	Name            string            `json:"name"`
+	GlobalStartTime *otioRationalTime `json:"global_start_time"`
+	Tracks          otioStack         `json:"tracks"`
+	Metadata        map[string]any    `json:"metadata"`
+}
+
+// WriteOTIO writes an OpenTimelineIO JSON document with matching video and
+// audio tracks. Clip metadata is kept under metadata.powerhour.
+func WriteOTIO(w io.Writer, tl Timeline) error {
+	rate := float64(tl.FPS)
+	rt := func(seconds float64) otioRationalTime {
+		return otioRationalTime{Schema: "RationalTime.1", Rate: rate, Value: float64(frames(seconds, tl.FPS))}
+	}
+	track := func(kind string) otioTrack {
+		t := otioTrack{Schema: "Track.1", Name: kind, Kind: kind, Children: []otioClip{}, Effects: []any{}, Markers: []any{}, Metadata: map[string]any{}}
+		for _, c := range tl.Clips {
+			ref := otioReference{Schema: "ExternalReference.1", Name: c.Name, TargetURL: fileURL(c.Source), Metadata: map[string]string{}}
+			if c.SourceDuration > 0 {
+				ref.AvailableRange = &otioTimeRange{Schema: "TimeRange.1", StartTime: rt(0), Duration: rt(c.SourceDuration)}
+			}
+			meta := map[string]any{}
+			if len(c.Metadata) > 0 {
+				meta["powerhour"] = c.Metadata
+			}
+			t.Children = append(t.Children, otioClip{
+				Schema:         "Clip.1",
+				Name:           c.Name,
+				SourceRange:    otioTimeRange{Schema: "TimeRange.1", StartTime: rt(c.In), Duration: rt(c.Duration)},
+				MediaReference: ref,
+				Effects:        []any{},
+				Markers:        []any{},
+				Metadata:       meta,
+			})
+		}
+		return t
+	}
+
+	doc := otioTimeline{
+		Schema: "Timeline.1",
+		Name:   tl.Name,
+		Tracks: otioStack{
+			Schema:   "Stack.1",
+			Name:     "tracks",
+			Children: []otioTrack{track("Video"), track("Audio")},
+			Effects:  []any{},
+			Markers:  []any{},
+			Metadata: map[string]any{},
+		},
+		Metadata: map[string]any{},
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	return enc.Encode(doc)
+}
@@ -0,0 +1,200 @@
+// Package notify tells the user a long fetch, render or concat has finished
+// or failed (notify: in powerhour.yaml): a desktop notification, a Slack or
+// Discord webhook post, or an email, each with the run's summary counts and
+// elapsed time.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"powerhour/internal/config"
+	"powerhour/internal/secrets"
+)
+
+// Message describes a finished run.
+type Message struct {
+	Command string        // fetch, render or concat
+	Project string        // project directory name
+	Summary string        // e.g. "Rendered: 58, Skipped: 2, Failed: 0"
+	Elapsed time.Duration // wall time of the run
+	Err     error         // nil when the run succeeded
+}
+
+// Title is the one-line headline, e.g. "powerhour render finished (party)".
+func (m Message) Title() string {
+	verb := "finished"
+	if m.Err != nil {
+		verb = "failed"
+	}
+	title := fmt.Sprintf("powerhour %s %s", m.Command, verb)
+	if m.Project != "" {
+		title += " (" + m.Project + ")"
+	}
+	return title
+}
+
+// Body is the summary, elapsed time and error, one per line.
+func (m Message) Body() string {
+	var lines []string
+	if m.Summary != "" {
+		lines = append(lines, m.Summary)
+	}
+	lines = append(lines, "Elapsed: "+m.Elapsed.Round(time.Second).String())
+	if m.Err != nil {
+		lines = append(lines, "Error: "+m.Err.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Text is the title and body together, as posted to webhooks.
+func (m Message) Text() string {
+	return m.Title() + "\n" + m.Body()
+}
+
+// Send delivers m on every channel configured in cfg. A failing channel
+// doesn't stop the others; the failures are joined into the returned error.
+func Send(ctx context.Context, cfg config.NotifyConfig, m Message) error {
+	var errs []error
+	if cfg.Desktop {
+		if err := sendDesktop(ctx, m); err != nil {
+			errs = append(errs, fmt.Errorf("desktop: %w", err))
+		}
+	}
+	if strings.TrimSpace(cfg.Webhook) != "" {
+		if err := sendWebhook(ctx, cfg.Webhook, m); err != nil {
+			errs = append(errs, fmt.Errorf("webhook: %w", err))
+		}
+	}
+	if len(cfg.Email.To) > 0 {
+		if err := sendEmail(cfg.Email, m); err != nil {
+			errs = append(errs, fmt.Errorf("email: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// desktopCommand builds the OS command that shows a notification. Tests
+// replace it.
+var desktopCommand = func(ctx context.Context, title, body string) (*exec.Cmd, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(body), appleScriptString(title))
+		return exec.CommandContext(ctx, "osascript", "-e", script), nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return exec.CommandContext(ctx, "notify-send", title, body), nil
+	default:
+		return nil, fmt.Errorf("not supported on %s", runtime.GOOS)
+	}
+}
+
+func sendDesktop(ctx context.Context, m Message) error {
+	cmd, err := desktopCommand(ctx, m.Title(), m.Body())
+	if err != nil {
+		return err
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s: %w: %s", cmd.Args[0], err, msg)
+		}
+		return fmt.Errorf("%s: %w", cmd.Args[0], err)
+	}
+	return nil
+}
+
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+const webhookTimeout = 10 * time.Second
+
+func sendWebhook(ctx context.Context, rawURL string, m Message) error {
+	target, err := secrets.Expand(strings.TrimSpace(rawURL))
+	if err != nil {
+		return err
+	}
+	body, err := webhookPayload(target, m)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		// The URL may hold a token; don't echo it.
+		return errors.New("invalid webhook URL")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+	return nil
+}
+
+// webhookPayload formats m for the service behind target: Discord reads
+// "content", Slack and most others read "text".
+func webhookPayload(target string, m Message) ([]byte, error) {
+	key := "text"
+	if u, err := url.Parse(target); err == nil {
+		host := strings.ToLower(u.Hostname())
+		if host == "discord.com" || host == "discordapp.com" || strings.HasSuffix(host, ".discord.com") {
+			key = "content"
+		}
+	}
+	return json.Marshal(map[string]string{key: m.Text()})
+}
+
+// sendMail is smtp.SendMail; tests replace it.
+var sendMail = smtp.SendMail
+
+func sendEmail(cfg config.NotifyEmailConfig, m Message) error {
+	host, _, err := net.SplitHostPort(cfg.SMTP)
+	if err != nil {
+		return fmt.Errorf("smtp %q: %w", cfg.SMTP, err)
+	}
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		password, err := secrets.Expand(cfg.Password)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", cfg.Username, password, host)
+	}
+	return sendMail(cfg.SMTP, auth, cfg.From, cfg.To, emailMessage(cfg, m, time.Now()))
+}
+
+func emailMessage(cfg config.NotifyEmailConfig, m Message, now time.Time) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(cfg.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", m.Title())
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(m.Body(), "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
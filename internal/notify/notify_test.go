@@ -0,0 +1,158 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"powerhour/internal/config"
+)
+
+func TestMessageText(t *testing.T) {
+	tests := []struct {
+		name  string
+		msg   Message
+		title string
+		body  string
+	}{
+		{
+			name:  "finished",
+			msg:   Message{Command: "render", Project: "party", Summary: "Rendered: 58, Skipped: 2, Failed: 0", Elapsed: 61*time.Minute + 4400*time.Millisecond},
+			title: "powerhour render finished (party)",
+			body:  "Rendered: 58, Skipped: 2, Failed: 0\nElapsed: 1h1m4s",
+		},
+		{
+			name:  "failed",
+			msg:   Message{Command: "concat", Elapsed: 3 * time.Second, Err: errors.New("ffmpeg exited 1")},
+			title: "powerhour concat failed",
+			body:  "Elapsed: 3s\nError: ffmpeg exited 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.msg.Title(); got != tt.title {
+				t.Errorf("Title() = %q, want %q", got, tt.title)
+			}
+			if got := tt.msg.Body(); got != tt.body {
+				t.Errorf("Body() = %q, want %q", got, tt.body)
+			}
+		})
+	}
+}
+
+func TestWebhookPayload(t *testing.T) {
+	msg := Message{Command: "fetch", Elapsed: time.Minute}
+	tests := []struct {
+		url string
+		key string
+	}{
+		{"https://hooks.slack.com/services/T0/B0/x", "text"},
+		{"https://discord.com/api/webhooks/1/abc", "content"},
+		{"https://canary.discord.com/api/webhooks/1/abc", "content"},
+		{"https://example.com/hook", "text"},
+	}
+	for _, tt := range tests {
+		data, err := webhookPayload(tt.url, msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var doc map[string]string
+		if err := json.Unmarshal(data, &doc); err != nil {
+			t.Fatal(err)
+		}
+		if doc[tt.key] != msg.Text() || len(doc) != 1 {
+			t.Errorf("%s: payload = %s, want %q key", tt.url, data, tt.key)
+		}
+	}
+}
+
+func TestSendWebhook(t *testing.T) {
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Content-Type = %q", r.Header.Get("Content-Type"))
+		}
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &got)
+		if strings.HasSuffix(r.URL.Path, "/fail") {
+			http.Error(w, "no_service", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	t.Setenv("PH_NOTIFY_TEST_HOOK", srv.URL+"/hook")
+	msg := Message{Command: "render", Summary: "Rendered: 1, Skipped: 0, Failed: 0", Elapsed: time.Second}
+	if err := Send(context.Background(), config.NotifyConfig{Webhook: "${PH_NOTIFY_TEST_HOOK}"}, msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if got["text"] != msg.Text() {
+		t.Fatalf("posted %v", got)
+	}
+
+	err := Send(context.Background(), config.NotifyConfig{Webhook: srv.URL + "/fail"}, msg)
+	if err == nil || !strings.Contains(err.Error(), "HTTP 404: no_service") {
+		t.Fatalf("expected HTTP 404 error, got %v", err)
+	}
+}
+
+func TestSendEmail(t *testing.T) {
+	var addr, from string
+	var to []string
+	var body []byte
+	var auth smtp.Auth
+	orig := sendMail
+	sendMail = func(a string, au smtp.Auth, f string, t []string, msg []byte) error {
+		addr, auth, from, to, body = a, au, f, t, msg
+		return nil
+	}
+	defer func() { sendMail = orig }()
+
+	t.Setenv("PH_NOTIFY_TEST_SMTP_PASS", "hunter2")
+	cfg := config.NotifyConfig{Email: config.NotifyEmailConfig{
+		To:       []string{"me@example.com"},
+		From:     "powerhour@example.com",
+		SMTP:     "smtp.example.com:587",
+		Username: "me",
+		Password: "${PH_NOTIFY_TEST_SMTP_PASS}",
+	}}
+	msg := Message{Command: "render", Project: "party", Summary: "Rendered: 60, Skipped: 0, Failed: 0", Elapsed: time.Hour}
+	if err := Send(context.Background(), cfg, msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if addr != "smtp.example.com:587" || from != "powerhour@example.com" || len(to) != 1 || auth == nil {
+		t.Fatalf("sendMail(%q, %v, %q, %v)", addr, auth, from, to)
+	}
+	text := string(body)
+	for _, want := range []string{"Subject: powerhour render finished (party)\r\n", "\r\n\r\nRendered: 60, Skipped: 0, Failed: 0\r\nElapsed: 1h0m0s\r\n"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("email missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestSendJoinsChannelErrors(t *testing.T) {
+	orig := desktopCommand
+	desktopCommand = func(ctx context.Context, title, body string) (*exec.Cmd, error) {
+		return nil, errors.New("not supported on plan9")
+	}
+	defer func() { desktopCommand = orig }()
+
+	cfg := config.NotifyConfig{Desktop: true, Email: config.NotifyEmailConfig{To: []string{"me@example.com"}, SMTP: "no-port"}}
+	err := Send(context.Background(), cfg, Message{Command: "fetch"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, want := range []string{"desktop: not supported on plan9", "email: smtp \"no-port\""} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q missing %q", err, want)
+		}
+	}
+}
@@ -22,26 +22,47 @@ type ProjectPaths struct {
 	IndexFile         string
 	ConcatListFile    string // .powerhour/concat.txt
 	RenderStateFile   string // .powerhour/render-state.json
+	UploadsFile       string // .powerhour/uploads.json
 	LibrarySourcesDir string // ~/.powerhour/library/sources/
 	LibraryIndexFile  string // ~/.powerhour/library/index.json
 }
 
-// Resolve determines the project root using the optional --project flag or the
-// current working directory when the flag is empty.
+// Resolve determines the project root from the optional --project flag: a
+// directory, or the name of a registered project (see RegisterProject).
+// When the flag is empty it is the nearest directory at or above the working
+// directory holding powerhour.yaml, falling back to the working directory.
 func Resolve(projectFlag string) (ProjectPaths, error) {
 	var (
 		root string
 		err  error
 	)
 
-	if projectFlag != "" {
+	switch {
+	case projectFlag == "":
+		root, err = findProjectRoot()
+	case isProjectName(projectFlag):
+		if known, ok := LookupProject(projectFlag); ok {
+			root = known.Root
+			break
+		}
+		root, err = filepath.Abs(projectFlag)
+	default:
 		root, err = filepath.Abs(projectFlag)
-	} else {
-		root, err = os.Getwd()
 	}
 	if err != nil {
 		return ProjectPaths{}, fmt.Errorf("resolve project root: %w", err)
 	}
+	return ResolveAt(root)
+}
+
+// ResolveAt returns the paths of the project rooted exactly at dir, without
+// searching parent directories or the registry. Use it for commands that
+// create a project.
+func ResolveAt(dir string) (ProjectPaths, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return ProjectPaths{}, fmt.Errorf("resolve project root: %w", err)
+	}
 
 	pp := newProjectPaths(root)
 
@@ -56,6 +77,38 @@ func Resolve(projectFlag string) (ProjectPaths, error) {
 	return pp, nil
 }
 
+// findProjectRoot walks up from the working directory to the nearest
+// directory holding powerhour.yaml, the way git finds its repository. With
+// none, the working directory is the root.
+func findProjectRoot() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for dir := cwd; ; {
+		if info, err := os.Stat(filepath.Join(dir, "powerhour.yaml")); err == nil && !info.IsDir() {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return cwd, nil
+		}
+		dir = parent
+	}
+}
+
+// isProjectName reports whether a --project value should be looked up in
+// the registry: a bare name that isn't a directory here.
+func isProjectName(value string) bool {
+	if value == "." || value == ".." || strings.ContainsAny(value, `/\`) || filepath.IsAbs(value) {
+		return false
+	}
+	if info, err := os.Stat(value); err == nil && info.IsDir() {
+		return false
+	}
+	return true
+}
+
 func newProjectPaths(root string) ProjectPaths {
 	metaDir := filepath.Join(root, ".powerhour")
 	return ProjectPaths{
@@ -70,6 +123,7 @@ func newProjectPaths(root string) ProjectPaths {
 		IndexFile:       filepath.Join(metaDir, "index.json"),
 		ConcatListFile:  filepath.Join(metaDir, "concat.txt"),
 		RenderStateFile: filepath.Join(metaDir, "render-state.json"),
+		UploadsFile:     filepath.Join(metaDir, "uploads.json"),
 	}
 }
 
@@ -87,6 +141,33 @@ func ApplyConfig(pp ProjectPaths, cfg config.Config) ProjectPaths {
 	return pp
 }
 
+// ApplyTimeline points segments, render state, and the concat list at the
+// named timeline's own locations: segments go to a sibling "<segments>-<name>"
+// directory and state files get a "-<name>" suffix. An empty name leaves the
+// default timeline's paths unchanged.
+func ApplyTimeline(pp ProjectPaths, name string) ProjectPaths {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return pp
+	}
+	pp.SegmentsDir = filepath.Clean(pp.SegmentsDir) + "-" + name
+	pp.RenderStateFile = filepath.Join(pp.MetaDir, "render-state-"+name+".json")
+	pp.ConcatListFile = filepath.Join(pp.MetaDir, "concat-"+name+".txt")
+	return pp
+}
+
+// ApplySamples points segments, render state, and the concat list at the
+// sample renders of render --sample-duration: segments go to a "samples"
+// directory in the project, under the name of the segments directory they
+// stand in for, and state files get a "-samples" suffix. Apply it after
+// ApplyTimeline so each timeline keeps its own samples.
+func ApplySamples(pp ProjectPaths) ProjectPaths {
+	pp.SegmentsDir = filepath.Join(pp.Root, "samples", filepath.Base(filepath.Clean(pp.SegmentsDir)))
+	pp.RenderStateFile = strings.TrimSuffix(pp.RenderStateFile, ".json") + "-samples.json"
+	pp.ConcatListFile = strings.TrimSuffix(pp.ConcatListFile, ".txt") + "-samples.txt"
+	return pp
+}
+
 // CollectionOutputDir returns the output directory for a specific collection.
 func (p ProjectPaths) CollectionOutputDir(cfg config.Config, collectionName string) string {
 	collection, ok := cfg.Collections[collectionName]
@@ -165,6 +246,17 @@ func GlobalDir() (string, error) {
 	return dir, nil
 }
 
+// TemplatesDir returns the directory of user project templates
+// (~/.powerhour/templates/), one subdirectory per template. It does not
+// create the directory.
+func TemplatesDir() (string, error) {
+	global, err := GlobalDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(global, "templates"), nil
+}
+
 // LibraryDir returns the resolved library root directory.
 // Resolution order: POWERHOUR_LIBRARY env var → configPath argument → ~/.powerhour/library/.
 // It creates the directory if it does not exist.
@@ -279,6 +371,17 @@ func GlobalEncodingProfileFile() (string, error) {
 	return filepath.Join(global, "encoding_profile.json"), nil
 }
 
+// LastFailureFile returns the path to the record of the last failed command
+// (~/.powerhour/last-failure.json), which debug bundle includes. It does not
+// create the file.
+func LastFailureFile() (string, error) {
+	global, err := GlobalDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(global, "last-failure.json"), nil
+}
+
 // GlobalConfigFile returns the path to the unified global config
 // (~/.powerhour/config.yaml). It does not create the file.
 func GlobalConfigFile() (string, error) {
@@ -197,3 +197,165 @@ func TestApplyConfigNoOverrides(t *testing.T) {
 		t.Fatalf("expected cookies path unchanged")
 	}
 }
+
+func TestApplyTimeline(t *testing.T) {
+	root := t.TempDir()
+	pp := newProjectPaths(root)
+
+	if got := ApplyTimeline(pp, ""); got != pp {
+		t.Fatalf("empty timeline changed paths: %+v", got)
+	}
+
+	applied := ApplyTimeline(pp, "warmup")
+	if want := filepath.Join(root, "segments-warmup"); applied.SegmentsDir != want {
+		t.Fatalf("expected segments dir %s, got %s", want, applied.SegmentsDir)
+	}
+	if want := filepath.Join(root, ".powerhour", "render-state-warmup.json"); applied.RenderStateFile != want {
+		t.Fatalf("expected render state %s, got %s", want, applied.RenderStateFile)
+	}
+	if want := filepath.Join(root, ".powerhour", "concat-warmup.txt"); applied.ConcatListFile != want {
+		t.Fatalf("expected concat list %s, got %s", want, applied.ConcatListFile)
+	}
+	if applied.CacheDir != pp.CacheDir || applied.IndexFile != pp.IndexFile {
+		t.Fatalf("timeline should share the cache: %+v", applied)
+	}
+}
+
+func TestApplySamples(t *testing.T) {
+	root := t.TempDir()
+	applied := ApplySamples(ApplyTimeline(newProjectPaths(root), "warmup"))
+	if want := filepath.Join(root, "samples", "segments-warmup"); applied.SegmentsDir != want {
+		t.Fatalf("expected segments dir %s, got %s", want, applied.SegmentsDir)
+	}
+	if want := filepath.Join(root, ".powerhour", "render-state-warmup-samples.json"); applied.RenderStateFile != want {
+		t.Fatalf("expected render state %s, got %s", want, applied.RenderStateFile)
+	}
+	if want := filepath.Join(root, ".powerhour", "concat-warmup-samples.txt"); applied.ConcatListFile != want {
+		t.Fatalf("expected concat list %s, got %s", want, applied.ConcatListFile)
+	}
+}
+
+func TestPortableName(t *testing.T) {
+	tests := map[string]string{
+		"con":           "con_",
+		"Con.mp4":       "Con_.mp4",
+		"NUL.tar.gz":    "NUL_.tar.gz",
+		"com1":          "com1_",
+		"lpt9.log":      "lpt9_.log",
+		"console.mp4":   "console.mp4",
+		"001_con.mp4":   "001_con.mp4",
+		"com10.mp4":     "com10.mp4",
+		"teenagers.mp4": "teenagers.mp4",
+	}
+	for name, want := range tests {
+		if got := PortableName(name); got != want {
+			t.Errorf("PortableName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestResolveWalksUpToProject(t *testing.T) {
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "powerhour.yaml"), []byte("version: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(root, "segments", "songs")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(sub)
+
+	pp, err := Resolve("")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if pp.Root != root {
+		t.Errorf("Root = %q, want %q", pp.Root, root)
+	}
+
+	// ResolveAt never searches upward.
+	pp, err = ResolveAt(".")
+	if err != nil {
+		t.Fatalf("ResolveAt: %v", err)
+	}
+	if pp.Root != sub {
+		t.Errorf("ResolveAt Root = %q, want %q", pp.Root, sub)
+	}
+}
+
+func TestResolveRegisteredName(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", os.Getenv("HOME"))
+	root := filepath.Join(t.TempDir(), "party")
+	if _, err := RegisterProject("", root); err != nil {
+		t.Fatalf("RegisterProject: %v", err)
+	}
+	t.Chdir(t.TempDir())
+
+	pp, err := Resolve("party")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if pp.Root != root {
+		t.Errorf("Root = %q, want %q", pp.Root, root)
+	}
+
+	// A directory in the working directory wins over a registered name.
+	if err := os.Mkdir("party", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	pp, err = Resolve("party")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if pp.Root == root {
+		t.Errorf("Root = %q, want the local directory", pp.Root)
+	}
+}
+
+func TestRegisterProject(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", os.Getenv("HOME"))
+	base := t.TempDir()
+	first := filepath.Join(base, "a", "party")
+	second := filepath.Join(base, "b", "party")
+
+	got, err := RegisterProject("", first)
+	if err != nil || got.Name != "party" {
+		t.Fatalf("RegisterProject(first) = %+v, %v", got, err)
+	}
+	got, err = RegisterProject("", second)
+	if err != nil || got.Name != "party-2" {
+		t.Fatalf("RegisterProject(second) = %+v, %v; want party-2", got, err)
+	}
+	if got, _ := RegisterProject("", first); got.Name != "party" {
+		t.Errorf("re-registering kept name %q, want party", got.Name)
+	}
+	if _, err := RegisterProject("party", second); err == nil {
+		t.Error("expected an error for a taken name")
+	}
+	if got, err := RegisterProject("friday", second); err != nil || got.Name != "friday" {
+		t.Fatalf("rename = %+v, %v", got, err)
+	}
+
+	projects, err := LoadProjects()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(projects) != 2 || projects[0].Name != "friday" || projects[1].Name != "party" {
+		t.Fatalf("projects = %+v", projects)
+	}
+
+	if removed, err := UnregisterProject("friday"); err != nil || !removed {
+		t.Fatalf("UnregisterProject = %v, %v", removed, err)
+	}
+	if removed, _ := UnregisterProject("friday"); removed {
+		t.Error("second UnregisterProject reported a removal")
+	}
+	if _, ok := LookupProject("friday"); ok {
+		t.Error("friday still registered")
+	}
+}
@@ -0,0 +1,27 @@
+package paths
+
+import "strings"
+
+// windowsReservedNames are device names Windows won't create a file under,
+// whatever the case or extension.
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// PortableName returns the file name with "_" added to its stem when Windows
+// reserves it ("con.mp4" becomes "con_.mp4"), so generated files can be
+// copied to a Windows machine or a FAT/exFAT drive.
+func PortableName(name string) string {
+	stem, ext, hasExt := strings.Cut(name, ".")
+	if !windowsReservedNames[strings.ToUpper(strings.TrimRight(stem, " "))] {
+		return name
+	}
+	if hasExt {
+		return stem + "_." + ext
+	}
+	return stem + "_"
+}
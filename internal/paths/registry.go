@@ -0,0 +1,147 @@
+package paths
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// KnownProject is a project in the registry, so --project can name it from
+// any directory.
+type KnownProject struct {
+	Name    string    `json:"name"`
+	Root    string    `json:"root"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+type projectRegistry struct {
+	Projects []KnownProject `json:"projects"`
+}
+
+// ProjectsFile returns the path to the project registry
+// (~/.powerhour/projects.json). It does not create the file.
+func ProjectsFile() (string, error) {
+	global, err := GlobalDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(global, "projects.json"), nil
+}
+
+// LoadProjects returns the registered projects sorted by name. A missing
+// registry is empty.
+func LoadProjects() ([]KnownProject, error) {
+	path, err := ProjectsFile()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read project registry: %w", err)
+	}
+	var reg projectRegistry
+	if err := json.Unmarshal(data, &reg); err != nil {
+		return nil, fmt.Errorf("parse project registry %s: %w", path, err)
+	}
+	slices.SortFunc(reg.Projects, func(a, b KnownProject) int { return strings.Compare(a.Name, b.Name) })
+	return reg.Projects, nil
+}
+
+func saveProjects(projects []KnownProject) error {
+	path, err := ProjectsFile()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(projectRegistry{Projects: projects}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode project registry: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write project registry: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("write project registry: %w", err)
+	}
+	return nil
+}
+
+// LookupProject finds a registered project by name.
+func LookupProject(name string) (KnownProject, bool) {
+	projects, err := LoadProjects()
+	if err != nil {
+		return KnownProject{}, false
+	}
+	i := slices.IndexFunc(projects, func(p KnownProject) bool { return p.Name == name })
+	if i < 0 {
+		return KnownProject{}, false
+	}
+	return projects[i], true
+}
+
+// RegisterProject adds the project at root under name. An empty name uses
+// the directory name, with a numeric suffix if another project already has
+// it; an explicit name that is taken is an error. A root that is already
+// registered keeps its entry, unless an explicit name renames it.
+func RegisterProject(name, root string) (KnownProject, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return KnownProject{}, fmt.Errorf("resolve project root: %w", err)
+	}
+	name = strings.TrimSpace(name)
+	if strings.ContainsAny(name, `/\`) {
+		return KnownProject{}, fmt.Errorf("project name %q must not contain path separators", name)
+	}
+	projects, err := LoadProjects()
+	if err != nil {
+		return KnownProject{}, err
+	}
+
+	existing := slices.IndexFunc(projects, func(p KnownProject) bool { return p.Root == root })
+	taken := func(n string) bool {
+		return slices.ContainsFunc(projects, func(p KnownProject) bool { return p.Name == n && p.Root != root })
+	}
+	switch {
+	case name != "" && taken(name):
+		return KnownProject{}, fmt.Errorf("project name %q is already registered", name)
+	case name == "" && existing >= 0:
+		return projects[existing], nil
+	case name == "":
+		base := filepath.Base(root)
+		name = base
+		for i := 2; taken(name); i++ {
+			name = fmt.Sprintf("%s-%d", base, i)
+		}
+	}
+
+	if existing >= 0 {
+		projects[existing].Name = name
+	} else {
+		projects = append(projects, KnownProject{Name: name, Root: root, AddedAt: time.Now().UTC()})
+		existing = len(projects) - 1
+	}
+	entry := projects[existing]
+	return entry, saveProjects(projects)
+}
+
+// UnregisterProject removes the named project from the registry. It
+// reports whether the name was registered; the project itself is untouched.
+func UnregisterProject(name string) (bool, error) {
+	projects, err := LoadProjects()
+	if err != nil {
+		return false, err
+	}
+	kept := slices.DeleteFunc(slices.Clone(projects), func(p KnownProject) bool { return p.Name == name })
+	if len(kept) == len(projects) {
+		return false, nil
+	}
+	return true, saveProjects(kept)
+}
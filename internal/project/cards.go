@@ -0,0 +1,114 @@
+package project
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"strings"
+
+	"powerhour/internal/config"
+	"powerhour/pkg/csvplan"
+)
+
+// CardRuleField holds a drink card's rule. Cards share the slate's style
+// fields (SlateBackgroundField and friends).
+const CardRuleField = "rule"
+
+// DefaultCardTitle heads a drink card when cards.title is unset.
+const DefaultCardTitle = "Drink card"
+
+// generatorRows synthesizes the rows of a generated collection.
+func generatorRows(name string, collCfg config.CollectionConfig) ([]csvplan.CollectionRow, error) {
+	if strings.EqualFold(strings.TrimSpace(collCfg.Generator), config.GeneratorCards) {
+		return cardRows(name, collCfg)
+	}
+	return []csvplan.CollectionRow{slateRow(collCfg)}, nil
+}
+
+// cardRows draws the collection's cards and makes each one a row.
+func cardRows(name string, collCfg config.CollectionConfig) ([]csvplan.CollectionRow, error) {
+	cards := collCfg.Cards
+	drawn, err := DrawCards(name, cards)
+	if err != nil {
+		return nil, fmt.Errorf("collection %q: cards: %w", name, err)
+	}
+	duration := collCfg.Duration
+	if duration <= 0 {
+		duration = config.DefaultCardDuration
+	}
+	title := strings.TrimSpace(cards.Title)
+	if title == "" {
+		title = DefaultCardTitle
+	}
+	rows := make([]csvplan.CollectionRow, len(drawn))
+	for i, rule := range drawn {
+		fields := map[string]string{
+			"title":              title,
+			CardRuleField:        strings.TrimSpace(cards.Rules[rule].Text),
+			SlateBackgroundField: strings.TrimSpace(cards.Background),
+			SlateFontColorField:  strings.TrimSpace(cards.FontColor),
+			SlateFontField:       strings.TrimSpace(cards.Font),
+		}
+		for key, value := range fields {
+			if value == "" {
+				delete(fields, key)
+			}
+		}
+		rows[i] = csvplan.CollectionRow{
+			Index:           i + 1,
+			StartRaw:        "0:00",
+			DurationSeconds: duration,
+			CustomFields:    fields,
+		}
+	}
+	return rows, nil
+}
+
+// DrawCards draws cards.CardCount() rules by weight and returns their
+// indexes in cards.Rules, in card order. A rule is left out of a draw once
+// it has been used max_uses times, or while it was drawn within the last
+// no_repeat_within cards. Draws are seeded by cards.Seed and the collection
+// name, so the same config gives the same cards.
+func DrawCards(collection string, cards config.CardsConfig) ([]int, error) {
+	h := fnv.New64a()
+	h.Write([]byte(collection))
+	rng := rand.New(rand.NewPCG(uint64(cards.Seed), h.Sum64()))
+
+	count := cards.CardCount()
+	uses := make([]int, len(cards.Rules))
+	last := make([]int, len(cards.Rules))
+	for i := range last {
+		last[i] = -1
+	}
+	drawn := make([]int, 0, count)
+	eligible := make([]int, 0, len(cards.Rules))
+	for card := 0; card < count; card++ {
+		eligible = eligible[:0]
+		total := 0.0
+		for i, rule := range cards.Rules {
+			if rule.MaxUses > 0 && uses[i] >= rule.MaxUses {
+				continue
+			}
+			if last[i] >= 0 && card-last[i] <= cards.NoRepeatWithin {
+				continue
+			}
+			eligible = append(eligible, i)
+			total += rule.RuleWeight()
+		}
+		if len(eligible) == 0 {
+			return nil, fmt.Errorf("only %d of %d cards can be drawn within max_uses and no_repeat_within; add rules or loosen the limits", card, count)
+		}
+		pick := eligible[len(eligible)-1]
+		r := rng.Float64() * total
+		for _, i := range eligible {
+			if r -= cards.Rules[i].RuleWeight(); r < 0 {
+				pick = i
+				break
+			}
+		}
+		uses[pick]++
+		last[pick] = card
+		drawn = append(drawn, pick)
+	}
+	return drawn, nil
+}
@@ -0,0 +1,107 @@
+package project
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"powerhour/internal/config"
+)
+
+func TestDrawCards(t *testing.T) {
+	rules := []config.CardRule{
+		{Text: "Waterfall", Weight: 3},
+		{Text: "Everyone with a hat drinks"},
+		{Text: "Social", MaxUses: 1},
+	}
+	tests := []struct {
+		name    string
+		cards   config.CardsConfig
+		wantLen int
+		wantErr string
+	}{
+		{name: "default count", cards: config.CardsConfig{Rules: rules}, wantLen: config.DefaultCardCount},
+		{name: "no repeats", cards: config.CardsConfig{Rules: rules, Count: 12, NoRepeatWithin: 1}, wantLen: 12},
+		{name: "too strict", cards: config.CardsConfig{Rules: rules, Count: 6, NoRepeatWithin: 2}, wantErr: "only 4 of 6 cards"},
+		{name: "used up", cards: config.CardsConfig{Rules: []config.CardRule{{Text: "a", MaxUses: 2}}, Count: 3}, wantErr: "only 2 of 3 cards"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drawn, err := DrawCards("cards", tt.cards)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(drawn) != tt.wantLen {
+				t.Fatalf("drew %d cards, want %d", len(drawn), tt.wantLen)
+			}
+			uses := make([]int, len(tt.cards.Rules))
+			for i, rule := range drawn {
+				uses[rule]++
+				for back := 1; back <= tt.cards.NoRepeatWithin && i-back >= 0; back++ {
+					if drawn[i-back] == rule {
+						t.Errorf("rule %d repeats within %d cards: %v", rule, tt.cards.NoRepeatWithin, drawn)
+					}
+				}
+			}
+			for i, rule := range tt.cards.Rules {
+				if rule.MaxUses > 0 && uses[i] > rule.MaxUses {
+					t.Errorf("rule %d used %d times, max %d", i, uses[i], rule.MaxUses)
+				}
+			}
+
+			again, _ := DrawCards("cards", tt.cards)
+			if !reflect.DeepEqual(drawn, again) {
+				t.Errorf("draws differ for the same config: %v vs %v", drawn, again)
+			}
+		})
+	}
+}
+
+func TestLoadCollectionsCardsGenerator(t *testing.T) {
+	pp := makeProjectPaths(t)
+	cfg := config.Config{
+		Collections: map[string]config.CollectionConfig{
+			"cards": {
+				Generator: config.GeneratorCards,
+				Duration:  8,
+				Cards: config.CardsConfig{
+					Count:      3,
+					Rules:      []config.CardRule{{Text: "Waterfall"}},
+					Background: "navy",
+				},
+			},
+		},
+	}
+	resolver, err := NewCollectionResolver(cfg, pp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := collections["cards"].Rows
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want 3", len(rows))
+	}
+	want := map[string]string{"title": DefaultCardTitle, CardRuleField: "Waterfall", SlateBackgroundField: "navy"}
+	for i, row := range rows {
+		if row.Index != i+1 || row.DurationSeconds != 8 || !reflect.DeepEqual(row.CustomFields, want) {
+			t.Errorf("row %d = %+v", i+1, row)
+		}
+	}
+
+	clips, err := resolver.BuildCollectionClips(collections)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clips) != 3 || clips[0].Clip.SourceKind != SourceKindGenerator {
+		t.Errorf("clips = %+v, want 3 generated clips", clips)
+	}
+}
@@ -1,6 +1,8 @@
 package project
 
 import (
+	"fmt"
+	"path/filepath"
 	"strconv"
 	"time"
 
@@ -18,6 +20,7 @@ func CollectionOptionsForConfig(cfg Collection) csvplan.CollectionOptions {
 		StartHeader:     cfg.Config.StartHeader,
 		DurationHeader:  cfg.Config.DurationHeader,
 		DefaultDuration: defaultDuration,
+		Sheet:           cfg.Config.Sheet,
 	}
 }
 
@@ -117,8 +120,13 @@ func DuplicateCollectionRow(coll Collection, rowIdx int) Collection {
 
 // WriteCollectionPlan persists a collection back to its configured plan file.
 func WriteCollectionPlan(coll Collection) error {
-	if coll.PlanFormat == "yaml" {
+	switch coll.PlanFormat {
+	case csvplan.PlanFormatXLSX:
+		return fmt.Errorf("plan %s is a spreadsheet, which powerhour can't write; edit it in your spreadsheet app, or run `powerhour convert` and point plan: at the YAML", filepath.Base(coll.Plan))
+	case csvplan.PlanFormatYAML:
 		return csvplan.WriteYAML(coll.Plan, coll.Headers, coll.Defaults, coll.Rows)
+	case csvplan.PlanFormatJSON:
+		return csvplan.WriteJSON(coll.Plan, coll.Headers, coll.Defaults, coll.Rows)
 	}
 	delimiter := coll.Delimiter
 	if delimiter == 0 {
@@ -130,3 +138,25 @@ func WriteCollectionPlan(coll Collection) error {
 	}
 	return csvplan.WriteCSV(coll.Plan, headers, coll.Rows, delimiter)
 }
+
+// SetRowSkipped marks or clears a row's skip state, returning the updated
+// row. Clearing removes the skip column value and re-enables a row that was
+// disabled through the enabled column.
+func SetRowSkipped(row csvplan.CollectionRow, skipped bool) csvplan.CollectionRow {
+	fields := make(map[string]string, len(row.CustomFields)+1)
+	for k, v := range row.CustomFields {
+		fields[k] = v
+	}
+	if skipped {
+		if !FieldsSkipped(fields) {
+			fields[SkipField] = "yes"
+		}
+	} else {
+		delete(fields, SkipField)
+		if FieldsSkipped(fields) {
+			fields[EnabledField] = "yes"
+		}
+	}
+	row.CustomFields = fields
+	return row
+}
@@ -1,6 +1,7 @@
 package project
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -91,3 +92,44 @@ func TestBuildCollectionRowParsesDefaultStartTime(t *testing.T) {
 		t.Fatalf("Start = %v, want %v", row.Start, 75*time.Second)
 	}
 }
+
+func TestSetRowSkipped(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields map[string]string
+		skip   bool
+		want   map[string]string
+	}{
+		{"skip plain row", map[string]string{"title": "A"}, true, map[string]string{"title": "A", "skip": "yes"}},
+		{"skip already disabled row", map[string]string{"enabled": "no"}, true, map[string]string{"enabled": "no"}},
+		{"unskip skip column", map[string]string{"skip": "x"}, false, map[string]string{}},
+		{"unskip disabled row", map[string]string{"enabled": "0", "skip": ""}, false, map[string]string{"enabled": "yes"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(tt.fields)
+			got := SetRowSkipped(csvplan.CollectionRow{CustomFields: tt.fields}, tt.skip)
+			if RowSkipped(got) != tt.skip {
+				t.Fatalf("RowSkipped = %v, want %v", RowSkipped(got), tt.skip)
+			}
+			if len(got.CustomFields) != len(tt.want) {
+				t.Fatalf("fields = %v, want %v", got.CustomFields, tt.want)
+			}
+			for k, v := range tt.want {
+				if got.CustomFields[k] != v {
+					t.Errorf("fields[%q] = %q, want %q", k, got.CustomFields[k], v)
+				}
+			}
+			if len(tt.fields) != before {
+				t.Error("input fields should not be mutated")
+			}
+		})
+	}
+}
+
+func TestWriteCollectionPlanRefusesSpreadsheet(t *testing.T) {
+	err := WriteCollectionPlan(Collection{Name: "songs", Plan: "/tmp/songs.xlsx", PlanFormat: "xlsx"})
+	if err == nil || !strings.Contains(err.Error(), "powerhour convert") {
+		t.Fatalf("err = %v, want a read-only error with a convert hint", err)
+	}
+}
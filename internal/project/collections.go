@@ -2,7 +2,6 @@ package project
 
 import (
 	"fmt"
-	"path/filepath"
 	"strings"
 
 	"powerhour/internal/config"
@@ -18,10 +17,19 @@ type Collection struct {
 	Config     config.CollectionConfig
 	Rows       []csvplan.CollectionRow
 	PlanErrors csvplan.ValidationErrors
+	// Overrides are per-row tweaks from the collection's overrides file,
+	// keyed by row index or link; see WithRowOverrides.
+	Overrides map[string]RowOverride
+	// Facts are per-row trivia, submitters and dares from the collection's
+	// facts file, keyed like Overrides.
+	Facts map[string]RowFacts
+	// Locale is the config's locale: WithRowOverrides fills each field from
+	// its "<field>_<locale>" column when that has a value.
+	Locale     string
 	Headers    []string          // Raw CSV headers (normalized), for write-back
 	Defaults   map[string]string // YAML column defaults, for write-back and row creation
 	Delimiter  rune              // CSV delimiter (comma or tab), for write-back
-	PlanFormat string            // "csv" or "yaml", for write-back
+	PlanFormat string            // csvplan.PlanFormat*: "csv", "yaml", "json" or "xlsx" (read-only), for write-back
 }
 
 // CollectionResolver loads and resolves collections from configuration.
@@ -54,6 +62,21 @@ func (r *CollectionResolver) LoadCollections() (map[string]Collection, error) {
 	for name, collCfg := range r.cfg.Collections {
 		outputDir := r.paths.CollectionOutputDir(r.cfg, name)
 
+		// Generated collection: one row carrying everything render needs
+		if collCfg.IsGenerator() {
+			rows, err := generatorRows(name, collCfg)
+			if err != nil {
+				return nil, err
+			}
+			collections[name] = Collection{
+				Name:      name,
+				OutputDir: outputDir,
+				Config:    collCfg,
+				Rows:      rows,
+			}
+			continue
+		}
+
 		// Single-file collection: synthesize one row, no CSV loading
 		if file := strings.TrimSpace(collCfg.File); file != "" {
 			filePath := resolveProjectPath(r.paths.Root, file)
@@ -65,11 +88,21 @@ func (r *CollectionResolver) LoadCollections() (map[string]Collection, error) {
 				DurationSeconds: collCfg.Duration,
 				CustomFields:    map[string]string{},
 			}}
+			overrides, err := r.loadRowOverrides(name, collCfg)
+			if err != nil {
+				return nil, err
+			}
+			facts, err := r.loadRowFacts(name, collCfg)
+			if err != nil {
+				return nil, err
+			}
 			collections[name] = Collection{
 				Name:      name,
 				OutputDir: outputDir,
 				Config:    collCfg,
 				Rows:      rows,
+				Overrides: overrides,
+				Facts:     facts,
 			}
 			continue
 		}
@@ -84,23 +117,29 @@ func (r *CollectionResolver) LoadCollections() (map[string]Collection, error) {
 		opts := CollectionOptionsForConfig(Collection{Config: collCfg})
 
 		var (
-			rows       []csvplan.CollectionRow
-			err        error
-			headers    []string
-			defaults   map[string]string
-			delimiter  rune
-			planFormat string
+			rows      []csvplan.CollectionRow
+			err       error
+			headers   []string
+			defaults  map[string]string
+			delimiter rune
 		)
-		ext := strings.ToLower(filepath.Ext(planPath))
-		if ext == ".yaml" || ext == ".yml" {
-			planFormat = "yaml"
-			result, yamlErr := csvplan.LoadCollectionYAML(planPath, opts)
+		planFormat := csvplan.DetectPlanFormat(planPath)
+		switch planFormat {
+		case csvplan.PlanFormatYAML, csvplan.PlanFormatJSON:
+			result, structErr := csvplan.LoadCollectionStructured(planPath, opts)
 			rows = result.Rows
 			headers = result.Columns
 			defaults = result.Defaults
-			err = yamlErr
-		} else {
-			planFormat = "csv"
+			err = structErr
+		case csvplan.PlanFormatXLSX:
+			rows, err = csvplan.LoadCollection(planPath, opts)
+			header, _ := csvplan.ReadHeaderRow(planPath, opts.Sheet)
+			for _, h := range header {
+				if h != "" {
+					headers = append(headers, h)
+				}
+			}
+		default:
 			rows, err = csvplan.LoadCollection(planPath, opts)
 			headers, delimiter, _ = csvplan.ReadHeaders(planPath)
 		}
@@ -115,6 +154,15 @@ func (r *CollectionResolver) LoadCollections() (map[string]Collection, error) {
 			}
 		}
 
+		overrides, err := r.loadRowOverrides(name, collCfg)
+		if err != nil {
+			return nil, err
+		}
+		facts, err := r.loadRowFacts(name, collCfg)
+		if err != nil {
+			return nil, err
+		}
+
 		collections[name] = Collection{
 			Name:       name,
 			Plan:       planPath,
@@ -126,19 +174,65 @@ func (r *CollectionResolver) LoadCollections() (map[string]Collection, error) {
 			Defaults:   defaults,
 			Delimiter:  delimiter,
 			PlanFormat: planFormat,
+			Overrides:  overrides,
+			Facts:      facts,
 		}
 	}
 
+	fillSlateCredits(collections)
+	if locale := r.cfg.LocaleCode(); locale != "" {
+		for name, coll := range collections {
+			coll.Locale = locale
+			collections[name] = coll
+		}
+	}
 	return collections, nil
 }
 
+// OverridesPath returns the resolved path of a collection's overrides
+// file, or "" when it has none configured.
+func (r *CollectionResolver) OverridesPath(collCfg config.CollectionConfig) string {
+	return resolveProjectPath(r.paths.Root, strings.TrimSpace(collCfg.Overrides))
+}
+
+func (r *CollectionResolver) loadRowOverrides(name string, collCfg config.CollectionConfig) (map[string]RowOverride, error) {
+	path := r.OverridesPath(collCfg)
+	if path == "" {
+		return nil, nil
+	}
+	overrides, err := LoadRowOverrides(path)
+	if err != nil {
+		return nil, fmt.Errorf("collection %q: %w", name, err)
+	}
+	return overrides, nil
+}
+
+// FactsPath returns the resolved path of a collection's facts file, or ""
+// when it has none configured.
+func (r *CollectionResolver) FactsPath(collCfg config.CollectionConfig) string {
+	return resolveProjectPath(r.paths.Root, strings.TrimSpace(collCfg.Facts))
+}
+
+func (r *CollectionResolver) loadRowFacts(name string, collCfg config.CollectionConfig) (map[string]RowFacts, error) {
+	path := r.FactsPath(collCfg)
+	if path == "" {
+		return nil, nil
+	}
+	facts, err := LoadRowFacts(path)
+	if err != nil {
+		return nil, fmt.Errorf("collection %q: %w", name, err)
+	}
+	return facts, nil
+}
+
 // CollectionPlanRow represents a row from a collection for fetch/validate operations.
 type CollectionPlanRow struct {
 	CollectionName string
 	Row            csvplan.Row
 }
 
-// FlattenCollections converts collections into a flat list of plan rows for fetch operations.
+// FlattenCollections converts collections into a flat list of plan rows for
+// fetch operations. Skipped rows and generated collections are left out.
 func FlattenCollections(collections map[string]Collection) []CollectionPlanRow {
 	if len(collections) == 0 {
 		return nil
@@ -146,7 +240,13 @@ func FlattenCollections(collections map[string]Collection) []CollectionPlanRow {
 
 	var flat []CollectionPlanRow
 	for name, coll := range collections {
+		if coll.Config.IsGenerator() {
+			continue
+		}
 		for _, collRow := range coll.Rows {
+			if RowSkipped(collRow) {
+				continue
+			}
 			flat = append(flat, CollectionPlanRow{
 				CollectionName: name,
 				Row:            collRow.ToRow(),
@@ -166,6 +266,64 @@ type CollectionClip struct {
 	DefaultDuration int
 }
 
+// Plan columns that exclude a row: a truthy skip or a falsy enabled.
+const (
+	SkipField    = "skip"
+	EnabledField = "enabled"
+)
+
+// RowSkipped reports whether a plan row is excluded from fetch, render, and
+// the timeline. Skipped rows stay in the plan and keep their index.
+func RowSkipped(row csvplan.CollectionRow) bool {
+	return FieldsSkipped(row.CustomFields)
+}
+
+// FieldsSkipped applies the RowSkipped rules to a row's raw fields.
+func FieldsSkipped(fields map[string]string) bool {
+	switch strings.ToLower(strings.TrimSpace(fields[SkipField])) {
+	case "1", "x", "y", "yes", "true", "skip":
+		return true
+	}
+	switch strings.ToLower(strings.TrimSpace(fields[EnabledField])) {
+	case "0", "n", "no", "false", "off", "disabled":
+		return true
+	}
+	return false
+}
+
+// LoopField is the plan column that turns source looping on or off for one
+// row, overriding the collection's loop setting.
+const LoopField = "loop"
+
+// RowLoops reports whether a row's source loops to fill its clip: its loop
+// column when set to a yes or no value, otherwise the collection's loop.
+func RowLoops(collCfg config.CollectionConfig, row csvplan.CollectionRow) bool {
+	switch strings.ToLower(strings.TrimSpace(row.CustomFields[LoopField])) {
+	case "1", "x", "y", "yes", "true", "on":
+		return true
+	case "0", "n", "no", "false", "off":
+		return false
+	}
+	return collCfg.Loop
+}
+
+// WithoutSkippedRows returns collections with skipped rows removed. Row
+// indexes are preserved, so callers still address rows by plan position.
+func WithoutSkippedRows(collections map[string]Collection) map[string]Collection {
+	out := make(map[string]Collection, len(collections))
+	for name, coll := range collections {
+		rows := make([]csvplan.CollectionRow, 0, len(coll.Rows))
+		for _, row := range coll.Rows {
+			if !RowSkipped(row) {
+				rows = append(rows, row)
+			}
+		}
+		coll.Rows = rows
+		out[name] = coll
+	}
+	return out
+}
+
 // BuildCollectionClips creates render-ready clips from all collections.
 func (r *CollectionResolver) BuildCollectionClips(collections map[string]Collection) ([]CollectionClip, error) {
 	if len(collections) == 0 {
@@ -175,17 +333,18 @@ func (r *CollectionResolver) BuildCollectionClips(collections map[string]Collect
 	var clips []CollectionClip
 	sequence := 0
 
-	for name, coll := range collections {
+	for name, coll := range WithRowOverrides(collections) {
 		collCfg := coll.Config
 
 		// Build clips from collection rows
 		fadeIn, fadeOut := config.ResolveFade(collCfg.Fade, collCfg.FadeIn, collCfg.FadeOut)
 		for _, collRow := range coll.Rows {
-			sequence++
+			if RowSkipped(collRow) {
+				continue
+			}
 			row := collRow.ToRow()
 
 			clip := Clip{
-				Sequence:        sequence,
 				ClipType:        ClipType(name),
 				TypeIndex:       row.Index,
 				Row:             row,
@@ -193,17 +352,38 @@ func (r *CollectionResolver) BuildCollectionClips(collections map[string]Collect
 				DurationSeconds: row.DurationSeconds,
 				FadeInSeconds:   fadeIn,
 				FadeOutSeconds:  fadeOut,
+				FadeCurve:       collCfg.FadeCurve,
+				AudioFadeIn:     collCfg.AudioFadeIn,
+				AudioFadeOut:    collCfg.AudioFadeOut,
+				PrerollSeconds:  collCfg.Preroll,
+				PostrollSeconds: collCfg.Postroll,
+				PadMode:         collCfg.PadMode,
+				AudioCue:        collCfg.AudioCue,
+				Loop:            RowLoops(collCfg, collRow),
+				PadShort:        strings.ToLower(strings.TrimSpace(collCfg.PadShort)),
 			}
 
-			collClip := CollectionClip{
-				CollectionName:  name,
-				Clip:            clip,
-				Overlays:        collCfg.Overlays,
-				OutputDir:       coll.OutputDir,
-				DefaultDuration: 60,
+			if collCfg.IsGenerator() {
+				clip.SourceKind = SourceKindGenerator
 			}
 
-			clips = append(clips, collClip)
+			overlays := collCfg.Overlays
+			if ov, ok := coll.RowOverrideFor(collRow); ok && len(ov.Overlays) > 0 {
+				overlays = config.MergeOverlays(overlays, ov.Overlays)
+			}
+			overlays = config.LocalizeOverlays(overlays, coll.Locale)
+
+			for _, windowClip := range WindowClips(clip) {
+				sequence++
+				windowClip.Sequence = sequence
+				clips = append(clips, CollectionClip{
+					CollectionName:  name,
+					Clip:            windowClip,
+					Overlays:        overlays,
+					OutputDir:       coll.OutputDir,
+					DefaultDuration: 60,
+				})
+			}
 		}
 	}
 
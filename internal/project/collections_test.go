@@ -346,6 +346,30 @@ func TestBuildCollectionClips(t *testing.T) {
 		}
 	})
 
+	t.Run("skipped rows produce no clip", func(t *testing.T) {
+		cfg := config.Config{}
+		r, _ := NewCollectionResolver(cfg, pp)
+
+		colls := map[string]Collection{
+			"songs": {
+				Name: "songs",
+				Rows: []csvplan.CollectionRow{
+					{Index: 1, Link: "https://1.com", CustomFields: map[string]string{"skip": "yes"}},
+					{Index: 2, Link: "https://2.com", CustomFields: map[string]string{"skip": ""}},
+					{Index: 3, Link: "https://3.com", CustomFields: map[string]string{"skip": "TRUE"}},
+				},
+			},
+		}
+
+		clips, err := r.BuildCollectionClips(colls)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(clips) != 1 || clips[0].Clip.Row.Index != 2 {
+			t.Fatalf("clips = %+v, want only row 2", clips)
+		}
+	})
+
 	t.Run("sequence numbers are sequential", func(t *testing.T) {
 		cfg := config.Config{}
 		r, _ := NewCollectionResolver(cfg, pp)
@@ -371,4 +395,60 @@ func TestBuildCollectionClips(t *testing.T) {
 			}
 		}
 	})
+	t.Run("windows split a row into clips", func(t *testing.T) {
+		cfg := config.Config{}
+		r, _ := NewCollectionResolver(cfg, pp)
+
+		colls := map[string]Collection{
+			"songs": {
+				Name: "songs",
+				Rows: []csvplan.CollectionRow{
+					{Index: 1, Link: "https://1.com", DurationSeconds: 50, CustomFields: map[string]string{"windows": "0:30-1:00; 2:10-2:30"}},
+					{Index: 2, Link: "https://2.com", DurationSeconds: 60, CustomFields: map[string]string{}},
+				},
+			},
+		}
+
+		clips, err := r.BuildCollectionClips(colls)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(clips) != 3 {
+			t.Fatalf("len = %d, want 3", len(clips))
+		}
+		want := []struct {
+			index, window, start, seconds int
+		}{{1, 1, 30, 30}, {1, 2, 130, 20}, {2, 0, 0, 60}}
+		for i, w := range want {
+			c := clips[i].Clip
+			if c.Row.Index != w.index || c.Window != w.window || int(c.Row.Start.Seconds()) != w.start || c.DurationSeconds != w.seconds || c.Sequence != i+1 {
+				t.Errorf("clip[%d] = row %d window %d start %s %ds seq %d, want %+v", i, c.Row.Index, c.Window, c.Row.Start, c.DurationSeconds, c.Sequence, w)
+			}
+		}
+
+		folded := CollectionsFromClips(cfg, clips)["songs"].Rows
+		if len(folded) != 2 || folded[0].DurationSeconds != 50 {
+			t.Errorf("CollectionsFromClips rows = %+v, want row 1 folded back to 50s", folded)
+		}
+	})
+}
+
+func TestRowLoops(t *testing.T) {
+	tests := []struct {
+		collLoop bool
+		column   string
+		want     bool
+	}{
+		{false, "", false},
+		{true, "", true},
+		{false, "yes", true},
+		{true, "no", false},
+		{true, "maybe", true},
+	}
+	for _, tt := range tests {
+		row := csvplan.CollectionRow{CustomFields: map[string]string{LoopField: tt.column}}
+		if got := RowLoops(config.CollectionConfig{Loop: tt.collLoop}, row); got != tt.want {
+			t.Errorf("RowLoops(loop=%v, column %q) = %v, want %v", tt.collLoop, tt.column, got, tt.want)
+		}
+	}
 }
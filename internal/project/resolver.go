@@ -4,6 +4,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"powerhour/internal/config"
 	"powerhour/pkg/csvplan"
 )
 
@@ -24,6 +25,9 @@ const (
 	SourceKindUnknown ClipSourceKind = ""
 	SourceKindPlan    ClipSourceKind = "plan"
 	SourceKindMedia   ClipSourceKind = "media"
+	// SourceKindGenerator clips have no source file; render synthesizes
+	// them from the row (see slate.go).
+	SourceKindGenerator ClipSourceKind = "generator"
 )
 
 // Clip models a single entry in the resolved render timeline.
@@ -37,6 +41,47 @@ type Clip struct {
 	DurationSeconds int
 	FadeInSeconds   float64
 	FadeOutSeconds  float64
+	FadeCurve       string  // audio fade curve (config.FadeCurve*); "" is linear
+	PrerollSeconds  float64 // padding baked in before the clip
+	PostrollSeconds float64 // padding baked in after the clip
+	PadMode         string  // "black" (default) or "freeze"
+
+	// AudioFadeIn and AudioFadeOut are the audio fade lengths when set
+	// apart from the video fades; see AudioFades.
+	AudioFadeIn  *float64
+	AudioFadeOut *float64
+
+	// AudioCue is a sound mixed over the clip with the music ducked under it.
+	AudioCue *config.AudioCueConfig
+
+	// Loop repeats the source to fill the clip when it is too short.
+	Loop bool
+	// PadShort is how a source that ends before the clip is filled out
+	// (config.PadShort*); empty leaves the segment short.
+	PadShort string
+
+	// Window numbers the clips of a row with a windows column from 1; it
+	// is 0 for an ordinary row. See WindowClips.
+	Window int
+}
+
+// OutputSeconds is the rendered segment length: the clip duration plus any
+// preroll/postroll padding.
+func (c Clip) OutputSeconds() float64 {
+	return float64(c.DurationSeconds) + c.PrerollSeconds + c.PostrollSeconds
+}
+
+// AudioFades returns the clip's audio fade lengths: AudioFadeIn and
+// AudioFadeOut where set, otherwise the video fades.
+func (c Clip) AudioFades() (in, out float64) {
+	in, out = c.FadeInSeconds, c.FadeOutSeconds
+	if c.AudioFadeIn != nil {
+		in = *c.AudioFadeIn
+	}
+	if c.AudioFadeOut != nil {
+		out = *c.AudioFadeOut
+	}
+	return in, out
 }
 
 func resolveProjectPath(root, value string) string {
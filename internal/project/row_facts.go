@@ -0,0 +1,104 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"powerhour/internal/config"
+	"powerhour/pkg/csvplan"
+)
+
+// Plan columns a facts file fills. The submitter goes in the column the
+// opening slate credits by default.
+const (
+	TriviaField    = "trivia"
+	DareField      = "dare"
+	SubmitterField = config.DefaultSlateCreditsField
+)
+
+// RowFacts is one plan row's entry in a collection's facts file: party
+// extras that overlays show as {trivia}, {submitted_by} and {dare}.
+type RowFacts struct {
+	Trivia    string `yaml:"trivia,omitempty"`
+	Submitter string `yaml:"submitter,omitempty"`
+	Dare      string `yaml:"dare,omitempty"`
+}
+
+// LoadRowFacts reads a facts file: a YAML mapping from a 1-based row index
+// or a row's link to RowFacts.
+func LoadRowFacts(path string) (map[string]RowFacts, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read facts: %w", err)
+	}
+	var facts map[string]RowFacts
+	if err := yaml.Unmarshal(data, &facts); err != nil {
+		return nil, fmt.Errorf("parse facts %s: %w", path, err)
+	}
+	return facts, nil
+}
+
+// RowFactsFor returns the facts for a row, merged like RowOverrideFor: a
+// link key first, then an index key on top.
+func (c Collection) RowFactsFor(row csvplan.CollectionRow) (RowFacts, bool) {
+	var (
+		merged RowFacts
+		found  bool
+	)
+	for _, key := range []string{strings.TrimSpace(row.Link), strconv.Itoa(row.Index)} {
+		f, ok := c.Facts[key]
+		if key == "" || !ok {
+			continue
+		}
+		found = true
+		if f.Trivia != "" {
+			merged.Trivia = f.Trivia
+		}
+		if f.Submitter != "" {
+			merged.Submitter = f.Submitter
+		}
+		if f.Dare != "" {
+			merged.Dare = f.Dare
+		}
+	}
+	return merged, found
+}
+
+// OrphanedFactKeys lists the facts keys that match no row in the plan,
+// neither an index nor a link, sorted.
+func (c Collection) OrphanedFactKeys() []string {
+	known := make(map[string]bool, 2*len(c.Rows))
+	for _, row := range c.Rows {
+		known[strconv.Itoa(row.Index)] = true
+		if link := strings.TrimSpace(row.Link); link != "" {
+			known[link] = true
+		}
+	}
+	var orphans []string
+	for key := range c.Facts {
+		if !known[strings.TrimSpace(key)] {
+			orphans = append(orphans, key)
+		}
+	}
+	sort.Strings(orphans)
+	return orphans
+}
+
+func applyRowFacts(row csvplan.CollectionRow, f RowFacts) csvplan.CollectionRow {
+	fields := make(map[string]string, len(row.CustomFields)+3)
+	for k, v := range row.CustomFields {
+		fields[k] = v
+	}
+	for key, value := range map[string]string{TriviaField: f.Trivia, SubmitterField: f.Submitter, DareField: f.Dare} {
+		if value = strings.TrimSpace(value); value != "" {
+			fields[key] = value
+		}
+	}
+	row.CustomFields = fields
+	return row
+}
@@ -0,0 +1,82 @@
+package project
+
+import (
+	"reflect"
+	"testing"
+
+	"powerhour/internal/config"
+)
+
+func TestRowFacts(t *testing.T) {
+	pp := makeProjectPaths(t)
+	writeCSV(t, pp.Root, "songs.csv", "title,artist,start_time,duration,link,submitted_by\n"+
+		"One,Band A,0:30,60,https://youtu.be/aaa,Sam\n"+
+		"Two,Band B,1:00,60,https://youtu.be/bbb,\n"+
+		"Three,Band C,1:30,60,https://youtu.be/ccc,\n")
+	writeCSV(t, pp.Root, "songs.facts.yaml", `
+1:
+  trivia: Recorded in a barn
+"https://youtu.be/bbb":
+  submitter: Alex
+  dare: Sing the chorus
+2:
+  dare: Do the robot
+7:
+  trivia: Nobody plays this
+"https://youtu.be/gone":
+  trivia: Removed from the plan
+`)
+	writeCSV(t, pp.Root, "songs.overrides.yaml", `
+3:
+  fields:
+    trivia: From the overrides
+`)
+
+	cfg := config.Config{
+		Collections: map[string]config.CollectionConfig{
+			"songs": {Plan: "songs.csv", Facts: "songs.facts.yaml", Overrides: "songs.overrides.yaml"},
+		},
+	}
+	resolver, err := NewCollectionResolver(cfg, pp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		t.Fatalf("LoadCollections: %v", err)
+	}
+	if _, ok := collections["songs"].Rows[0].CustomFields[TriviaField]; ok {
+		t.Fatalf("loaded rows should stay as planned")
+	}
+
+	rows := WithRowOverrides(collections)["songs"].Rows
+	tests := []struct {
+		row                     int
+		trivia, submitter, dare string
+	}{
+		{row: 0, trivia: "Recorded in a barn", submitter: "Sam"},
+		{row: 1, submitter: "Alex", dare: "Do the robot"},
+		{row: 2, trivia: "From the overrides"},
+	}
+	for _, tt := range tests {
+		fields := rows[tt.row].CustomFields
+		got := []string{fields[TriviaField], fields[SubmitterField], fields[DareField]}
+		want := []string{tt.trivia, tt.submitter, tt.dare}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("row %d trivia/submitter/dare = %q, want %q", tt.row+1, got, want)
+		}
+	}
+
+	orphans := collections["songs"].OrphanedFactKeys()
+	if want := []string{"7", "https://youtu.be/gone"}; !reflect.DeepEqual(orphans, want) {
+		t.Errorf("OrphanedFactKeys = %q, want %q", orphans, want)
+	}
+}
+
+func TestLoadRowFactsRejectsBadYAML(t *testing.T) {
+	pp := makeProjectPaths(t)
+	path := writeCSV(t, pp.Root, "facts.yaml", "1: [not, a, mapping]\n")
+	if _, err := LoadRowFacts(path); err == nil {
+		t.Error("LoadRowFacts accepted a list entry")
+	}
+}
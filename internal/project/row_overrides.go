@@ -0,0 +1,183 @@
+package project
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"powerhour/internal/config"
+	"powerhour/pkg/csvplan"
+)
+
+// RowOverride adjusts one plan row from a collection's overrides file,
+// leaving the shared plan untouched.
+type RowOverride struct {
+	StartTime string `yaml:"start_time,omitempty"`
+	Duration  int    `yaml:"duration,omitempty"`
+	// Fields replaces plan columns such as title, artist, name, or the
+	// {field} values used by custom overlays.
+	Fields   map[string]string     `yaml:"fields,omitempty"`
+	Overlays []config.OverlayEntry `yaml:"overlays,omitempty"`
+	// GainDB sets the row's gain_db column, e.g. "-6" or "+4.5dB".
+	GainDB string `yaml:"gain_db,omitempty"`
+}
+
+// LoadRowOverrides reads an overrides file: a YAML mapping from a 1-based
+// row index or a row's link to a RowOverride.
+func LoadRowOverrides(path string) (map[string]RowOverride, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read overrides: %w", err)
+	}
+	var overrides map[string]RowOverride
+	if err := yaml.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("parse overrides %s: %w", path, err)
+	}
+	for key, ov := range overrides {
+		if ov.Duration < 0 {
+			return nil, fmt.Errorf("overrides %s: %q: duration must be positive", path, key)
+		}
+		if ov.StartTime != "" {
+			if _, err := csvplan.ParseStartTime(ov.StartTime); err != nil {
+				return nil, fmt.Errorf("overrides %s: %q: %w", path, key, err)
+			}
+		}
+		if _, err := ParseGainDB(ov.GainDB); err != nil {
+			return nil, fmt.Errorf("overrides %s: %q: %w", path, key, err)
+		}
+	}
+	return overrides, nil
+}
+
+// WriteRowOverrides writes overrides to path as YAML, replacing the file.
+func WriteRowOverrides(path string, overrides map[string]RowOverride) error {
+	data, err := yaml.Marshal(overrides)
+	if err != nil {
+		return fmt.Errorf("encode overrides: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create overrides directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write overrides: %w", err)
+	}
+	return nil
+}
+
+// RowOverrideFor returns the override for a row. A link key applies
+// first and an index key on top of it, so the index wins where both set
+// the same thing.
+func (c Collection) RowOverrideFor(row csvplan.CollectionRow) (RowOverride, bool) {
+	var (
+		merged RowOverride
+		found  bool
+	)
+	for _, key := range []string{strings.TrimSpace(row.Link), strconv.Itoa(row.Index)} {
+		ov, ok := c.Overrides[key]
+		if key == "" || !ok {
+			continue
+		}
+		found = true
+		if ov.StartTime != "" {
+			merged.StartTime = ov.StartTime
+		}
+		if ov.Duration > 0 {
+			merged.Duration = ov.Duration
+		}
+		for k, v := range ov.Fields {
+			if merged.Fields == nil {
+				merged.Fields = make(map[string]string, len(ov.Fields))
+			}
+			merged.Fields[k] = v
+		}
+		if len(ov.Overlays) > 0 {
+			merged.Overlays = config.MergeOverlays(merged.Overlays, ov.Overlays)
+		}
+		if ov.GainDB != "" {
+			merged.GainDB = ov.GainDB
+		}
+	}
+	return merged, found
+}
+
+// WithRowOverrides returns collections with each collection's facts and
+// then its overrides applied to copies of its rows, so an override's fields
+// win over facts, and the fields translated into the collection's Locale.
+// Plan write-back should keep using the original rows.
+func WithRowOverrides(collections map[string]Collection) map[string]Collection {
+	out := make(map[string]Collection, len(collections))
+	for name, coll := range collections {
+		if len(coll.Overrides) > 0 || len(coll.Facts) > 0 || coll.Locale != "" {
+			rows := make([]csvplan.CollectionRow, len(coll.Rows))
+			for i, row := range coll.Rows {
+				if f, ok := coll.RowFactsFor(row); ok {
+					row = applyRowFacts(row, f)
+				}
+				if ov, ok := coll.RowOverrideFor(row); ok {
+					row = applyRowOverride(row, ov)
+				}
+				row.CustomFields = config.LocalizeFields(row.CustomFields, coll.Locale)
+				rows[i] = row
+			}
+			coll.Rows = rows
+		}
+		out[name] = coll
+	}
+	return out
+}
+
+func applyRowOverride(row csvplan.CollectionRow, ov RowOverride) csvplan.CollectionRow {
+	if ov.StartTime != "" {
+		if start, err := csvplan.ParseStartTime(ov.StartTime); err == nil {
+			row.StartRaw = ov.StartTime
+			row.Start = start
+		}
+	}
+	if ov.Duration > 0 {
+		row.DurationSeconds = ov.Duration
+	}
+	if len(ov.Fields) > 0 {
+		fields := make(map[string]string, len(row.CustomFields)+len(ov.Fields))
+		for k, v := range row.CustomFields {
+			fields[k] = v
+		}
+		for k, v := range ov.Fields {
+			fields[strings.ToLower(strings.TrimSpace(k))] = v
+		}
+		row.CustomFields = fields
+	}
+	if ov.GainDB != "" {
+		fields := make(map[string]string, len(row.CustomFields)+1)
+		for k, v := range row.CustomFields {
+			fields[k] = v
+		}
+		fields[GainField] = ov.GainDB
+		row.CustomFields = fields
+	}
+	return row
+}
+
+// GainField is the plan column holding a clip's volume adjustment in dB.
+const GainField = "gain_db"
+
+// ParseGainDB parses a gain_db value such as "-6", "+4.5" or "-3dB". Empty
+// means no adjustment.
+func ParseGainDB(raw string) (float64, error) {
+	value := strings.TrimSpace(raw)
+	if lower := strings.ToLower(value); strings.HasSuffix(lower, "db") {
+		value = strings.TrimSpace(value[:len(value)-2])
+	}
+	if value == "" {
+		return 0, nil
+	}
+	gain, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(gain) || gain < -60 || gain > 60 {
+		return 0, fmt.Errorf("invalid gain_db %q: expected decibels between -60 and 60", raw)
+	}
+	return gain, nil
+}
@@ -0,0 +1,173 @@
+package project
+
+import (
+	"testing"
+	"time"
+
+	"powerhour/internal/config"
+)
+
+func TestRowOverrides(t *testing.T) {
+	pp := makeProjectPaths(t)
+	writeCSV(t, pp.Root, "songs.csv", "title,artist,start_time,duration,link\n"+
+		"One,Band A,0:30,60,https://youtu.be/aaa\n"+
+		"Two,Band B,1:00,60,https://youtu.be/bbb\n"+
+		"Three,Band C,1:30,60,https://youtu.be/ccc\n")
+	writeCSV(t, pp.Root, "songs.overrides.yaml", `
+2:
+  start_time: "1:15"
+  duration: 45
+  fields:
+    title: Two (Live)
+  overlays:
+    - type: song-info
+      position: top-left
+"https://youtu.be/bbb":
+  duration: 50
+  fields:
+    artist: The B Band
+"https://youtu.be/ccc":
+  gain_db: "-4.5"
+  fields:
+    name: Host
+`)
+
+	cfg := config.Config{
+		Collections: map[string]config.CollectionConfig{
+			"songs": {
+				Plan:      "songs.csv",
+				Overrides: "songs.overrides.yaml",
+				Overlays:  []config.OverlayEntry{{Type: "song-info", Options: map[string]string{"position": "bottom-left"}}},
+			},
+		},
+	}
+	resolver, err := NewCollectionResolver(cfg, pp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		t.Fatalf("LoadCollections: %v", err)
+	}
+	if got := collections["songs"].Rows[1].CustomFields["title"]; got != "Two" {
+		t.Fatalf("loaded rows should stay as planned, title = %q", got)
+	}
+
+	rows := WithRowOverrides(collections)["songs"].Rows
+	two := rows[1]
+	if two.Start != 75*time.Second || two.StartRaw != "1:15" {
+		t.Errorf("start = %v (%q), want 1:15", two.Start, two.StartRaw)
+	}
+	if two.DurationSeconds != 45 {
+		t.Errorf("duration = %d, want 45 (index key wins over link)", two.DurationSeconds)
+	}
+	if two.CustomFields["title"] != "Two (Live)" || two.CustomFields["artist"] != "The B Band" {
+		t.Errorf("fields = %v, want title and artist overridden", two.CustomFields)
+	}
+	if rows[2].CustomFields["name"] != "Host" || rows[2].DurationSeconds != 60 {
+		t.Errorf("row 3 = %+v, want name Host and planned duration", rows[2])
+	}
+	if rows[2].CustomFields[GainField] != "-4.5" {
+		t.Errorf("row 3 gain_db = %q, want -4.5", rows[2].CustomFields[GainField])
+	}
+	if rows[0].CustomFields["title"] != "One" || rows[0].DurationSeconds != 60 {
+		t.Errorf("row 1 should be untouched, got %+v", rows[0])
+	}
+
+	clips, err := resolver.BuildCollectionClips(collections)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cc := range clips {
+		position := cc.Overlays[0].Options["position"]
+		want := "bottom-left"
+		if cc.Clip.Row.Index == 2 {
+			want = "top-left"
+			if cc.Clip.DurationSeconds != 45 || cc.Clip.Row.Title != "Two (Live)" {
+				t.Errorf("clip 2 = %+v, want overridden duration and title", cc.Clip)
+			}
+		}
+		if len(cc.Overlays) != 1 || position != want {
+			t.Errorf("clip %d overlays = %+v, want one song-info at %s", cc.Clip.Row.Index, cc.Overlays, want)
+		}
+	}
+}
+
+func TestLoadRowOverridesRejectsBadValues(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"bad start":    "1:\n  start_time: soon\n",
+		"bad duration": "1:\n  duration: -5\n",
+		"bad gain":     "1:\n  gain_db: loud\n",
+		"not a map":    "- 1\n- 2\n",
+	} {
+		path := writeCSV(t, dir, "overrides.yaml", content)
+		if _, err := LoadRowOverrides(path); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestParseGainDB(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    float64
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"-6", -6, false},
+		{"+4.5", 4.5, false},
+		{"-3dB", -3, false},
+		{" 2 db ", 2, false},
+		{"loud", 0, true},
+		{"-90", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseGainDB(tt.raw)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseGainDB(%q) = %v, %v; want %v, err %v", tt.raw, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestRowLocale(t *testing.T) {
+	pp := makeProjectPaths(t)
+	writeCSV(t, pp.Root, "songs.csv", "title,title_es,artist,start_time,duration,link\n"+
+		"Rain,Lluvia,Band A,0:30,60,https://youtu.be/aaa\n"+
+		"Sun,,Band B,1:00,60,https://youtu.be/bbb\n")
+
+	cfg := config.Config{
+		Locale: "es",
+		Collections: map[string]config.CollectionConfig{
+			"songs": {
+				Plan:     "songs.csv",
+				Overlays: []config.OverlayEntry{{Type: "drink", Options: map[string]string{"text": "Drink!", "text_es": "¡Bebe!"}}},
+			},
+		},
+	}
+	resolver, err := NewCollectionResolver(cfg, pp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		t.Fatalf("LoadCollections: %v", err)
+	}
+	if got := collections["songs"].Rows[0].CustomFields["title"]; got != "Rain" {
+		t.Fatalf("loaded rows should stay as planned, title = %q", got)
+	}
+
+	clips, err := resolver.BuildCollectionClips(collections)
+	if err != nil {
+		t.Fatal(err)
+	}
+	titles := map[int]string{1: "Lluvia", 2: "Sun"}
+	for _, cc := range clips {
+		if cc.Clip.Row.Title != titles[cc.Clip.Row.Index] {
+			t.Errorf("clip %d title = %q, want %q", cc.Clip.Row.Index, cc.Clip.Row.Title, titles[cc.Clip.Row.Index])
+		}
+		if text := cc.Overlays[0].Options["text"]; text != "¡Bebe!" {
+			t.Errorf("clip %d drink text = %q, want ¡Bebe!", cc.Clip.Row.Index, text)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package project
+
+import (
+	"strconv"
+	"strings"
+
+	"powerhour/internal/config"
+	"powerhour/pkg/csvplan"
+)
+
+// Fields of a generated slate row. The row carries the whole slate so the
+// segment hash changes whenever any of it does; render reads them back.
+const (
+	SlateDateField       = "date"
+	SlateRulesField      = "rules"
+	SlateCreditsField    = "credits"
+	SlateBackgroundField = "background"
+	SlateFontColorField  = "font_color"
+	SlateFontField       = "font"
+)
+
+// slateRow synthesizes the single row of a slate collection. Credits are
+// filled in by fillSlateCredits once every collection is loaded.
+func slateRow(collCfg config.CollectionConfig) csvplan.CollectionRow {
+	slate := collCfg.Slate
+	duration := collCfg.Duration
+	if duration <= 0 {
+		duration = config.DefaultSlateDuration
+	}
+	var rules []string
+	for _, rule := range slate.Rules {
+		if rule = strings.TrimSpace(rule); rule != "" {
+			rules = append(rules, rule)
+		}
+	}
+	fields := map[string]string{
+		"title":              strings.TrimSpace(slate.Title),
+		SlateDateField:       strings.TrimSpace(slate.Date),
+		SlateRulesField:      strings.Join(rules, "\n"),
+		SlateBackgroundField: strings.TrimSpace(slate.Background),
+		SlateFontColorField:  strings.TrimSpace(slate.FontColor),
+		SlateFontField:       strings.TrimSpace(slate.Font),
+	}
+	for key, value := range fields {
+		if value == "" {
+			delete(fields, key)
+		}
+	}
+	return csvplan.CollectionRow{
+		Index:           1,
+		StartRaw:        "0:00",
+		DurationSeconds: duration,
+		CustomFields:    fields,
+	}
+}
+
+// fillSlateCredits sets each slate row's credits from its credits_from
+// collection: one line per non-skipped row, in plan order, that has a
+// value in the credits column.
+func fillSlateCredits(collections map[string]Collection) {
+	for name, coll := range collections {
+		slate := coll.Config.Slate
+		if !strings.EqualFold(strings.TrimSpace(coll.Config.Generator), config.GeneratorSlate) || strings.TrimSpace(slate.CreditsFrom) == "" || len(coll.Rows) == 0 {
+			continue
+		}
+		source, ok := collections[strings.TrimSpace(slate.CreditsFrom)]
+		if !ok {
+			continue
+		}
+		lines := SlateCredits(slate, source.Rows)
+		if len(lines) == 0 {
+			continue
+		}
+		coll.Rows[0].CustomFields[SlateCreditsField] = strings.Join(lines, "\n")
+		collections[name] = coll
+	}
+}
+
+// SlateCredits formats the credit lines for rows.
+func SlateCredits(slate config.SlateConfig, rows []csvplan.CollectionRow) []string {
+	field := strings.ToLower(slate.CreditsFieldName())
+	tmpl := strings.TrimSpace(slate.CreditsTemplate)
+	if tmpl == "" {
+		tmpl = "{title} - {" + field + "}"
+	}
+	var lines []string
+	for _, row := range rows {
+		if RowSkipped(row) || strings.TrimSpace(creditField(row.CustomFields, field)) == "" {
+			continue
+		}
+		replacements := []string{"{index}", strconv.Itoa(row.Index), "{link}", row.Link}
+		for key, value := range row.CustomFields {
+			replacements = append(replacements, "{"+key+"}", value, "{"+strings.ToLower(key)+"}", value)
+		}
+		line := strings.Join(strings.Fields(strings.NewReplacer(replacements...).Replace(tmpl)), " ")
+		line = strings.Trim(line, " -–—:")
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func creditField(fields map[string]string, field string) string {
+	if v, ok := fields[field]; ok {
+		return v
+	}
+	for key, value := range fields {
+		if strings.ToLower(key) == field {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,101 @@
+package project
+
+import (
+	"reflect"
+	"testing"
+
+	"powerhour/internal/config"
+	"powerhour/pkg/csvplan"
+)
+
+func TestSlateCredits(t *testing.T) {
+	rows := []csvplan.CollectionRow{
+		{Index: 1, CustomFields: map[string]string{"title": "Song One", "submitted_by": "Alice"}},
+		{Index: 2, CustomFields: map[string]string{"title": "Song Two"}},
+		{Index: 3, CustomFields: map[string]string{"title": "Song Three", "Submitted_By": "Bob"}},
+		{Index: 4, CustomFields: map[string]string{"title": "Song Four", "submitted_by": "Carol", SkipField: "yes"}},
+	}
+	tests := []struct {
+		name  string
+		slate config.SlateConfig
+		want  []string
+	}{
+		{"default template", config.SlateConfig{}, []string{"Song One - Alice", "Song Three - Bob"}},
+		{"custom template", config.SlateConfig{CreditsTemplate: "{index}. {submitted_by}"}, []string{"1. Alice", "3. Bob"}},
+		{"custom field", config.SlateConfig{CreditsField: "title", CreditsTemplate: "{title}"}, []string{"Song One", "Song Two", "Song Three"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SlateCredits(tt.slate, rows)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SlateCredits = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadCollectionsSlateGenerator(t *testing.T) {
+	pp := makeProjectPaths(t)
+	writeCSV(t, pp.Root, "songs.csv", "link,title,start_time,submitted_by\nhttps://example.com/1,Song One,0:30,Alice\nhttps://example.com/2,Song Two,1:00,\n")
+
+	cfg := config.Config{
+		Collections: map[string]config.CollectionConfig{
+			"intro": {
+				Generator: config.GeneratorSlate,
+				Slate: config.SlateConfig{
+					Title:       "Power Hour",
+					Date:        "Oct 17",
+					Rules:       []string{"Drink on the horn", " ", "No skipping"},
+					CreditsFrom: "songs",
+				},
+			},
+			"songs": {Plan: "songs.csv"},
+		},
+	}
+	r, err := NewCollectionResolver(cfg, pp)
+	if err != nil {
+		t.Fatalf("NewCollectionResolver: %v", err)
+	}
+	colls, err := r.LoadCollections()
+	if err != nil {
+		t.Fatalf("LoadCollections: %v", err)
+	}
+
+	intro := colls["intro"]
+	if len(intro.Rows) != 1 {
+		t.Fatalf("len(intro.Rows) = %d, want 1", len(intro.Rows))
+	}
+	row := intro.Rows[0]
+	if row.DurationSeconds != config.DefaultSlateDuration {
+		t.Errorf("DurationSeconds = %d, want %d", row.DurationSeconds, config.DefaultSlateDuration)
+	}
+	want := map[string]string{
+		"title":           "Power Hour",
+		SlateDateField:    "Oct 17",
+		SlateRulesField:   "Drink on the horn\nNo skipping",
+		SlateCreditsField: "Song One - Alice",
+	}
+	if !reflect.DeepEqual(row.CustomFields, want) {
+		t.Errorf("CustomFields = %v, want %v", row.CustomFields, want)
+	}
+
+	if got := FlattenCollections(colls); len(got) != 2 {
+		t.Errorf("FlattenCollections returned %d rows, want only the 2 song rows", len(got))
+	}
+	clips, err := r.BuildCollectionClips(colls)
+	if err != nil {
+		t.Fatalf("BuildCollectionClips: %v", err)
+	}
+	for _, cc := range clips {
+		if cc.CollectionName == "intro" {
+			if cc.Clip.SourceKind != SourceKindGenerator {
+				t.Errorf("intro SourceKind = %q, want %q", cc.Clip.SourceKind, SourceKindGenerator)
+			}
+			if cc.Clip.Row.Title != "Power Hour" {
+				t.Errorf("intro Title = %q", cc.Clip.Row.Title)
+			}
+		} else if cc.Clip.SourceKind == SourceKindGenerator {
+			t.Errorf("%s clip marked as generated", cc.CollectionName)
+		}
+	}
+}
@@ -16,7 +16,7 @@ type TimelineEntry struct {
 }
 
 func ResolveTimeline(timeline config.TimelineConfig, collections map[string]Collection) ([]TimelineEntry, error) {
-	placements, err := BuildTimelinePlacements(timeline, collections)
+	placements, err := BuildTimelinePlacements(timeline, WithoutSkippedRows(collections))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,226 @@
+package project
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"powerhour/internal/config"
+)
+
+// TimelineBudget summarizes a timeline's projected runtime against
+// timeline.target_duration_s.
+type TimelineBudget struct {
+	TargetSeconds    float64 `json:"target_seconds,omitempty"`
+	ProjectedSeconds float64 `json:"projected_seconds"`
+	ToleranceSeconds float64 `json:"tolerance_seconds,omitempty"`
+	// UnknownEntries counts inline files and full-length clips, whose
+	// length isn't known until render and is left out of the projection.
+	UnknownEntries  int    `json:"unknown_entries,omitempty"`
+	Fit             string `json:"fit,omitempty"`
+	Trimmed         int    `json:"trimmed,omitempty"`          // clips dropped by fit: trim
+	InterleaveEvery int    `json:"interleave_every,omitempty"` // every chosen by fit: interleave
+}
+
+// DeltaSeconds is the projected runtime minus the target (negative when under).
+func (b TimelineBudget) DeltaSeconds() float64 {
+	return b.ProjectedSeconds - b.TargetSeconds
+}
+
+// OffTarget reports whether a target is set and the projection misses it by
+// more than the tolerance.
+func (b TimelineBudget) OffTarget() bool {
+	return b.TargetSeconds > 0 && math.Abs(b.DeltaSeconds()) > b.ToleranceSeconds
+}
+
+// TimelineRuntime projects the runtime of the resolved timeline, after any
+// fit, from row durations plus collection preroll/postroll.
+func TimelineRuntime(timeline config.TimelineConfig, collections map[string]Collection) (TimelineBudget, error) {
+	collections = WithRowOverrides(WithoutSkippedRows(collections))
+	placements, budget, err := fitTimelinePlacements(timeline, collections)
+	if err != nil {
+		return TimelineBudget{}, err
+	}
+	budget.ProjectedSeconds, budget.UnknownEntries = placementsRuntime(placements, timeline, collections)
+	return budget, nil
+}
+
+// fitTimelinePlacements builds placements and, when a target duration and
+// fit mode are set, adjusts them toward the target.
+func fitTimelinePlacements(timeline config.TimelineConfig, collections map[string]Collection) ([]TimelinePlacement, TimelineBudget, error) {
+	budget := TimelineBudget{
+		TargetSeconds:    float64(timeline.TargetDurationS),
+		ToleranceSeconds: timeline.ToleranceSeconds(),
+	}
+	placements, err := buildTimelinePlacements(timeline, collections)
+	if err != nil || timeline.TargetDurationS <= 0 {
+		if timeline.TargetDurationS <= 0 {
+			budget.ToleranceSeconds = 0
+		}
+		return placements, budget, err
+	}
+
+	target := float64(timeline.TargetDurationS)
+	switch strings.ToLower(strings.TrimSpace(timeline.Fit)) {
+	case config.FitTrim:
+		budget.Fit = config.FitTrim
+		before := len(placements)
+		placements = trimPlacements(placements, timeline, collections, target)
+		budget.Trimmed = before - len(placements)
+	case config.FitInterleave:
+		budget.Fit = config.FitInterleave
+		placements, budget.InterleaveEvery, err = fitInterleave(timeline, collections, placements, target)
+	}
+	return placements, budget, err
+}
+
+// placementSeconds returns a placement's rendered length and whether it is
+// known. A sequence entry's duration override wins over the row's. Inline
+// files and rows without a duration are unknown.
+func placementSeconds(p TimelinePlacement, timeline config.TimelineConfig, collections map[string]Collection) (float64, bool) {
+	if p.SourceFile != "" {
+		return 0, false
+	}
+	coll, ok := collections[p.Collection]
+	if !ok {
+		return 0, false
+	}
+	duration := 0
+	if !p.Interleaved && p.SequenceEntryIndex >= 0 && p.SequenceEntryIndex < len(timeline.Sequence) {
+		duration = timeline.Sequence[p.SequenceEntryIndex].Duration
+	}
+	if duration <= 0 {
+		for _, row := range coll.Rows {
+			if row.Index == p.RowIndex {
+				duration = row.DurationSeconds
+				break
+			}
+		}
+	}
+	if duration <= 0 {
+		duration = coll.Config.Duration
+	}
+	if duration <= 0 {
+		return 0, false
+	}
+	return float64(duration) + coll.Config.Preroll + coll.Config.Postroll, true
+}
+
+func placementsRuntime(placements []TimelinePlacement, timeline config.TimelineConfig, collections map[string]Collection) (float64, int) {
+	var total float64
+	unknown := 0
+	for _, p := range placements {
+		seconds, ok := placementSeconds(p, timeline, collections)
+		if !ok {
+			unknown++
+			continue
+		}
+		total += seconds
+	}
+	return total, unknown
+}
+
+// trimPlacements drops the last clips of the collection with the most
+// primary placements until the runtime fits the target. An interstitial
+// interleaved next to a dropped clip goes with it, so the pattern stays
+// intact.
+func trimPlacements(placements []TimelinePlacement, timeline config.TimelineConfig, collections map[string]Collection, target float64) []TimelinePlacement {
+	total, _ := placementsRuntime(placements, timeline, collections)
+	if total <= target {
+		return placements
+	}
+
+	counts := make(map[string]int)
+	for _, p := range placements {
+		if p.SourceFile == "" && !p.Interleaved {
+			counts[p.Collection]++
+		}
+	}
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) == 0 {
+		return placements
+	}
+	trimmed := names[0]
+
+	out := append([]TimelinePlacement(nil), placements...)
+	for total > target {
+		at := -1
+		for i := len(out) - 1; i >= 0; i-- {
+			if out[i].Collection == trimmed && out[i].SourceFile == "" && !out[i].Interleaved {
+				at = i
+				break
+			}
+		}
+		if at < 0 {
+			break
+		}
+		drop := []int{at}
+		for _, n := range []int{at + 1, at - 1} {
+			if n >= 0 && n < len(out) && out[n].Interleaved && out[n].SequenceEntryIndex == out[at].SequenceEntryIndex {
+				drop = append(drop, n)
+				break
+			}
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(drop)))
+		for _, i := range drop {
+			if seconds, ok := placementSeconds(out[i], timeline, collections); ok {
+				total -= seconds
+			}
+			out = append(out[:i], out[i+1:]...)
+		}
+	}
+	return out
+}
+
+// fitInterleave tries every interleave spacing from 1 up to the largest
+// collection size across all interleaved entries and keeps the one whose
+// runtime lands closest to the target. The configured spacing wins ties.
+func fitInterleave(timeline config.TimelineConfig, collections map[string]Collection, placements []TimelinePlacement, target float64) ([]TimelinePlacement, int, error) {
+	interleaved := false
+	for _, entry := range timeline.Sequence {
+		if entry.Interleave != nil {
+			interleaved = true
+			break
+		}
+	}
+	if !interleaved {
+		return placements, 0, nil
+	}
+
+	total, _ := placementsRuntime(placements, timeline, collections)
+	best, bestEvery, bestDiff := placements, 0, math.Abs(total-target)
+	maxEvery := 1
+	for _, coll := range collections {
+		maxEvery = max(maxEvery, len(coll.Rows))
+	}
+	for every := 1; every <= maxEvery; every++ {
+		candidate := timeline
+		candidate.Sequence = make([]config.SequenceEntry, len(timeline.Sequence))
+		for i, entry := range timeline.Sequence {
+			if entry.Interleave != nil {
+				il := *entry.Interleave
+				il.Every = every
+				entry.Interleave = &il
+			}
+			candidate.Sequence[i] = entry
+		}
+		p, err := buildTimelinePlacements(candidate, collections)
+		if err != nil {
+			return nil, 0, err
+		}
+		total, _ := placementsRuntime(p, candidate, collections)
+		if diff := math.Abs(total - target); diff < bestDiff {
+			best, bestEvery, bestDiff = p, every, diff
+		}
+	}
+	return best, bestEvery, nil
+}
@@ -0,0 +1,122 @@
+package project
+
+import (
+	"testing"
+
+	"powerhour/internal/config"
+)
+
+func budgetCollections() map[string]Collection {
+	songs := makeCollectionWithRows("songs", 10)
+	songs.Config = config.CollectionConfig{Duration: 60}
+	drinks := makeCollectionWithRows("drinks", 10)
+	drinks.Config = config.CollectionConfig{Duration: 5}
+	return map[string]Collection{"songs": songs, "drinks": drinks}
+}
+
+func TestTimelineRuntime(t *testing.T) {
+	interleaved := func(target int, fit string) config.TimelineConfig {
+		return config.TimelineConfig{
+			TargetDurationS: target,
+			Fit:             fit,
+			Sequence: []config.SequenceEntry{{
+				Collection: "songs",
+				Interleave: &config.InterleaveConfig{Collection: "drinks", Every: 1},
+			}},
+		}
+	}
+	tests := []struct {
+		name      string
+		timeline  config.TimelineConfig
+		projected float64
+		unknown   int
+		trimmed   int
+		every     int
+		offTarget bool
+	}{
+		{
+			name: "no target",
+			timeline: config.TimelineConfig{Sequence: []config.SequenceEntry{
+				{Collection: "songs"}, {File: "outro.mp4"},
+			}},
+			projected: 600,
+			unknown:   1,
+		},
+		{
+			name: "entry duration overrides rows",
+			timeline: config.TimelineConfig{Sequence: []config.SequenceEntry{
+				{Collection: "songs", Slice: "start:5"}, {Collection: "songs", Duration: 30},
+			}},
+			projected: 450,
+		},
+		{
+			name: "overshoot without fit warns",
+			timeline: config.TimelineConfig{TargetDurationS: 480, Sequence: []config.SequenceEntry{
+				{Collection: "songs"},
+			}},
+			projected: 600,
+			offTarget: true,
+		},
+		{
+			name: "trim drops last clips",
+			timeline: config.TimelineConfig{TargetDurationS: 480, Fit: "trim", Sequence: []config.SequenceEntry{
+				{Collection: "songs"},
+			}},
+			projected: 480,
+			trimmed:   2,
+		},
+		{
+			name:      "trim takes interstitials with their clip",
+			timeline:  interleaved(520, "trim"),
+			projected: 515,
+			trimmed:   4,
+		},
+		{
+			name:      "interleave rescales spacing",
+			timeline:  interleaved(620, "interleave"),
+			projected: 620,
+			every:     2,
+		},
+		{
+			name:      "interleave keeps configured spacing when closest",
+			timeline:  interleaved(645, "interleave"),
+			projected: 645,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			budget, err := TimelineRuntime(tc.timeline, budgetCollections())
+			if err != nil {
+				t.Fatalf("TimelineRuntime: %v", err)
+			}
+			if budget.ProjectedSeconds != tc.projected {
+				t.Errorf("projected = %v, want %v", budget.ProjectedSeconds, tc.projected)
+			}
+			if budget.UnknownEntries != tc.unknown {
+				t.Errorf("unknown = %d, want %d", budget.UnknownEntries, tc.unknown)
+			}
+			if budget.Trimmed != tc.trimmed {
+				t.Errorf("trimmed = %d, want %d", budget.Trimmed, tc.trimmed)
+			}
+			if budget.InterleaveEvery != tc.every {
+				t.Errorf("interleave every = %d, want %d", budget.InterleaveEvery, tc.every)
+			}
+			if budget.OffTarget() != tc.offTarget {
+				t.Errorf("off target = %v, want %v", budget.OffTarget(), tc.offTarget)
+			}
+		})
+	}
+}
+
+func TestResolveTimelineAppliesFit(t *testing.T) {
+	timeline := config.TimelineConfig{TargetDurationS: 300, Fit: "trim", Sequence: []config.SequenceEntry{
+		{Collection: "songs"},
+	}}
+	entries, err := ResolveTimeline(timeline, budgetCollections())
+	if err != nil {
+		t.Fatalf("ResolveTimeline: %v", err)
+	}
+	if len(entries) != 5 || entries[len(entries)-1].Index != 5 {
+		t.Fatalf("got %d entries, want rows 1-5", len(entries))
+	}
+}
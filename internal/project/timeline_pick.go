@@ -0,0 +1,133 @@
+package project
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+
+	"powerhour/internal/config"
+	"powerhour/pkg/csvplan"
+)
+
+// Plan columns read by SequenceEntry.Pick.
+const (
+	WeightField = "weight"
+	TagsField   = "tags"
+)
+
+// pickRows samples rows from pool according to entry.Pick. The result order
+// is the pick order. Draws are seeded by entry.Seed and the collection name,
+// so the same pool always yields the same picks.
+func pickRows(pool []csvplan.CollectionRow, entry config.SequenceEntry) ([]csvplan.CollectionRow, error) {
+	mode := strings.ToLower(strings.TrimSpace(entry.Pick))
+	h := fnv.New64a()
+	h.Write([]byte(entry.Collection))
+	rng := rand.New(rand.NewPCG(uint64(entry.Seed), h.Sum64()))
+
+	var picked []csvplan.CollectionRow
+	switch mode {
+	case config.PickRandom:
+		picked = append(picked, pool...)
+		rng.Shuffle(len(picked), func(i, j int) {
+			picked[i], picked[j] = picked[j], picked[i]
+		})
+	case config.PickWeighted, config.PickTagged:
+		candidates := pool
+		if mode == config.PickTagged {
+			candidates = rowsWithTags(pool, entry.Tags)
+		}
+		var err error
+		picked, err = weightedOrder(candidates, rng)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown pick %q", entry.Pick)
+	}
+
+	if entry.Count > 0 && entry.Count < len(picked) {
+		picked = picked[:entry.Count]
+	}
+	return picked, nil
+}
+
+// weightedOrder orders rows for weighted sampling without replacement
+// (Efraimidis-Spirakis): each row draws key u^(1/w), highest keys first.
+// Rows with weight 0 are left out.
+func weightedOrder(rows []csvplan.CollectionRow, rng *rand.Rand) ([]csvplan.CollectionRow, error) {
+	type keyed struct {
+		row csvplan.CollectionRow
+		key float64
+	}
+	items := make([]keyed, 0, len(rows))
+	for _, row := range rows {
+		weight, err := RowWeight(row)
+		if err != nil {
+			return nil, err
+		}
+		u := rng.Float64()
+		if weight == 0 {
+			continue
+		}
+		// log(u)/w orders identically to u^(1/w) without underflow.
+		items = append(items, keyed{row: row, key: math.Log(u) / weight})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].key > items[j].key
+	})
+	out := make([]csvplan.CollectionRow, len(items))
+	for i, item := range items {
+		out[i] = item.row
+	}
+	return out, nil
+}
+
+// RowWeight returns a row's selection weight from its weight column,
+// defaulting to 1 when the column is empty.
+func RowWeight(row csvplan.CollectionRow) (float64, error) {
+	raw := strings.TrimSpace(row.CustomFields[WeightField])
+	if raw == "" {
+		return 1, nil
+	}
+	weight, err := strconv.ParseFloat(raw, 64)
+	if err != nil || weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+		return 0, fmt.Errorf("row %d: invalid weight %q", row.Index, raw)
+	}
+	return weight, nil
+}
+
+// RowTags splits a row's tags column on commas, semicolons, and pipes.
+func RowTags(row csvplan.CollectionRow) []string {
+	parts := strings.FieldsFunc(row.CustomFields[TagsField], func(r rune) bool {
+		return r == ',' || r == ';' || r == '|'
+	})
+	tags := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if tag := strings.ToLower(strings.TrimSpace(part)); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// rowsWithTags keeps rows carrying at least one of the wanted tags.
+func rowsWithTags(rows []csvplan.CollectionRow, wanted []string) []csvplan.CollectionRow {
+	want := make(map[string]bool, len(wanted))
+	for _, tag := range wanted {
+		want[strings.ToLower(strings.TrimSpace(tag))] = true
+	}
+	var out []csvplan.CollectionRow
+	for _, row := range rows {
+		for _, tag := range RowTags(row) {
+			if want[tag] {
+				out = append(out, row)
+				break
+			}
+		}
+	}
+	return out
+}
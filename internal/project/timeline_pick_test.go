@@ -0,0 +1,119 @@
+package project
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"powerhour/internal/config"
+	"powerhour/pkg/csvplan"
+)
+
+func pickedIndexes(t *testing.T, timeline config.TimelineConfig, collections map[string]Collection) []int {
+	t.Helper()
+	entries, err := ResolveTimeline(timeline, collections)
+	if err != nil {
+		t.Fatalf("ResolveTimeline: %v", err)
+	}
+	out := make([]int, len(entries))
+	for i, e := range entries {
+		out[i] = e.Index
+	}
+	return out
+}
+
+func TestTimelinePickRandomIsSeeded(t *testing.T) {
+	collections := map[string]Collection{"songs": makeCollectionWithRows("songs", 120)}
+	timeline := func(seed int64) config.TimelineConfig {
+		return config.TimelineConfig{Sequence: []config.SequenceEntry{
+			{Collection: "songs", Pick: "random", Count: 60, Seed: seed},
+		}}
+	}
+
+	first := pickedIndexes(t, timeline(7), collections)
+	if len(first) != 60 {
+		t.Fatalf("picked %d rows, want 60", len(first))
+	}
+	seen := make(map[int]bool)
+	for _, idx := range first {
+		if seen[idx] {
+			t.Fatalf("row %d picked twice", idx)
+		}
+		seen[idx] = true
+	}
+	if again := pickedIndexes(t, timeline(7), collections); fmt.Sprint(again) != fmt.Sprint(first) {
+		t.Errorf("same seed gave different picks:\n%v\n%v", first, again)
+	}
+	if other := pickedIndexes(t, timeline(8), collections); fmt.Sprint(other) == fmt.Sprint(first) {
+		t.Errorf("different seeds gave identical picks")
+	}
+}
+
+func TestTimelinePickWeightedAndTagged(t *testing.T) {
+	rows := []csvplan.CollectionRow{
+		{Index: 1, CustomFields: map[string]string{"weight": "0", "tags": "rock"}},
+		{Index: 2, CustomFields: map[string]string{"weight": "5", "tags": "Rock; 90s"}},
+		{Index: 3, CustomFields: map[string]string{"tags": "pop"}},
+		{Index: 4, CustomFields: map[string]string{"weight": "2", "tags": "90s|pop"}},
+	}
+	collections := map[string]Collection{"songs": {Name: "songs", Rows: rows}}
+
+	weighted := pickedIndexes(t, config.TimelineConfig{Sequence: []config.SequenceEntry{
+		{Collection: "songs", Pick: "weighted"},
+	}}, collections)
+	if len(weighted) != 3 || !containsAll(weighted, 2, 3, 4) {
+		t.Errorf("weighted picks = %v, want rows 2-4 with weight-0 row 1 left out", weighted)
+	}
+
+	tagged := pickedIndexes(t, config.TimelineConfig{Sequence: []config.SequenceEntry{
+		{Collection: "songs", Pick: "tagged", Tags: []string{"90S"}},
+	}}, collections)
+	if len(tagged) != 2 || !containsAll(tagged, 2, 4) {
+		t.Errorf("tagged picks = %v, want rows 2 and 4", tagged)
+	}
+
+	bad := map[string]Collection{"songs": {Name: "songs", Rows: []csvplan.CollectionRow{
+		{Index: 1, CustomFields: map[string]string{"weight": "lots"}},
+	}}}
+	_, err := ResolveTimeline(config.TimelineConfig{Sequence: []config.SequenceEntry{
+		{Collection: "songs", Pick: "weighted"},
+	}}, bad)
+	if err == nil || !strings.Contains(err.Error(), "invalid weight") {
+		t.Errorf("expected invalid weight error, got %v", err)
+	}
+}
+
+func TestTimelinePickRowsAreNotRepeated(t *testing.T) {
+	collections := map[string]Collection{"songs": makeCollectionWithRows("songs", 10)}
+	got := pickedIndexes(t, config.TimelineConfig{Sequence: []config.SequenceEntry{
+		{Collection: "songs", Pick: "random", Count: 4, Seed: 1},
+		{Collection: "songs"},
+	}}, collections)
+	if len(got) != 10 {
+		t.Fatalf("timeline = %v, want every row exactly once", got)
+	}
+	seen := make(map[int]bool)
+	for i, idx := range got {
+		if seen[idx] {
+			t.Fatalf("row %d repeated in %v", idx, got)
+		}
+		seen[idx] = true
+		// The plain entry plays the leftover rows in plan order.
+		if i > 4 && idx < got[i-1] {
+			t.Errorf("leftover rows out of order: %v", got[4:])
+		}
+	}
+}
+
+func containsAll(have []int, want ...int) bool {
+	set := make(map[int]bool, len(have))
+	for _, v := range have {
+		set[v] = true
+	}
+	for _, v := range want {
+		if !set[v] {
+			return false
+		}
+	}
+	return true
+}
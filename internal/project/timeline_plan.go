@@ -3,6 +3,7 @@ package project
 import (
 	"fmt"
 	"sort"
+	"strings"
 
 	"powerhour/internal/config"
 	"powerhour/pkg/csvplan"
@@ -17,10 +18,17 @@ type TimelinePlacement struct {
 	Interleaved        bool
 }
 
-// BuildTimelinePlacements resolves the timeline into ordered placements.
+// BuildTimelinePlacements resolves the timeline into ordered placements,
+// applying timeline.fit when a target duration is set.
 func BuildTimelinePlacements(timeline config.TimelineConfig, collections map[string]Collection) ([]TimelinePlacement, error) {
+	placements, _, err := fitTimelinePlacements(timeline, collections)
+	return placements, err
+}
+
+func buildTimelinePlacements(timeline config.TimelineConfig, collections map[string]Collection) ([]TimelinePlacement, error) {
 	var placements []TimelinePlacement
 	cursor := make(map[string]int)
+	picked := make(map[string]map[int]bool)
 
 	for entryIdx, entry := range timeline.Sequence {
 		if entry.File != "" {
@@ -40,14 +48,31 @@ func BuildTimelinePlacements(timeline config.TimelineConfig, collections map[str
 		if err != nil {
 			return nil, fmt.Errorf("timeline sequence[%d] (%q): %w", entryIdx, entry.Collection, err)
 		}
-		cursor[entry.Collection] = selected.nextCursor
+		// Rows already chosen by a pick entry don't play twice. Pick entries
+		// sample from their slice without advancing the cursor, so later
+		// entries can still reach the rows they passed over.
+		rows := withoutPicked(selected.rows, picked[entry.Collection])
+		if strings.TrimSpace(entry.Pick) == "" {
+			cursor[entry.Collection] = selected.nextCursor
+		} else {
+			rows, err = pickRows(rows, entry)
+			if err != nil {
+				return nil, fmt.Errorf("timeline sequence[%d] (%q): %w", entryIdx, entry.Collection, err)
+			}
+			if picked[entry.Collection] == nil {
+				picked[entry.Collection] = make(map[int]bool)
+			}
+			for _, row := range rows {
+				picked[entry.Collection][row.Index] = true
+			}
+		}
 
-		if len(selected.rows) == 0 {
+		if len(rows) == 0 {
 			continue
 		}
 
 		if entry.Interleave == nil {
-			for _, row := range selected.rows {
+			for _, row := range rows {
 				placements = append(placements, TimelinePlacement{
 					SequenceEntryIndex: entryIdx,
 					Collection:         entry.Collection,
@@ -91,8 +116,8 @@ func BuildTimelinePlacements(timeline config.TimelineConfig, collections map[str
 			ilIdx++
 		}
 
-		for i, row := range selected.rows {
-			isLast := i == len(selected.rows)-1
+		for i, row := range rows {
+			isLast := i == len(rows)-1
 
 			if placement == "before" || placement == "around" {
 				if i%every == 0 {
@@ -135,6 +160,19 @@ type selectedCollectionRows struct {
 	nextCursor int
 }
 
+func withoutPicked(rows []csvplan.CollectionRow, picked map[int]bool) []csvplan.CollectionRow {
+	if len(picked) == 0 {
+		return rows
+	}
+	out := make([]csvplan.CollectionRow, 0, len(rows))
+	for _, row := range rows {
+		if !picked[row.Index] {
+			out = append(out, row)
+		}
+	}
+	return out
+}
+
 func selectCollectionRows(rows []csvplan.CollectionRow, cursor int, slice string) (selectedCollectionRows, error) {
 	if cursor >= len(rows) {
 		return selectedCollectionRows{nextCursor: len(rows)}, nil
@@ -149,29 +187,70 @@ func selectCollectionRows(rows []csvplan.CollectionRow, cursor int, slice string
 	}, nil
 }
 
-// ApplySequenceEntryFades applies per-entry fade overrides to primary clips.
-func ApplySequenceEntryFades(cfg config.Config, clips []CollectionClip) {
-	byCollection := make(map[string]map[int]int)
+// ApplySequenceEntryOverrides applies per-entry duration, fade, overlay and
+// audio cue overrides to the primary clips each sequence entry places.
+func ApplySequenceEntryOverrides(cfg config.Config, clips []CollectionClip) {
+	byCollection := make(map[string]map[int][]int)
 	for i, cc := range clips {
 		if byCollection[cc.CollectionName] == nil {
-			byCollection[cc.CollectionName] = make(map[int]int)
+			byCollection[cc.CollectionName] = make(map[int][]int)
+		}
+		byCollection[cc.CollectionName][cc.Clip.Row.Index] = append(byCollection[cc.CollectionName][cc.Clip.Row.Index], i)
+	}
+	collections := CollectionsFromClips(cfg, clips)
+	// Entry overlays merge onto each clip's own stack (collection plus any
+	// row override), snapshotted so variant passes don't stack twice.
+	base := make([][]config.OverlayEntry, len(clips))
+	for i := range clips {
+		base[i] = clips[i].Overlays
+	}
+
+	applyPlacementOverrides(cfg.Timeline, collections, byCollection, clips, base, cfg.LocaleCode(), "")
+	// Variant replacements are rendered alongside everything else, so give
+	// them their entry's overrides too; only the swapped entries are touched.
+	for _, variant := range cfg.Timeline.TimelineVariants() {
+		timeline, err := cfg.Timeline.WithVariant(variant)
+		if err != nil {
+			continue
 		}
-		byCollection[cc.CollectionName][cc.Clip.Row.Index] = i
+		applyPlacementOverrides(timeline, collections, byCollection, clips, base, cfg.LocaleCode(), variant)
 	}
+}
 
-	collections := make(map[string]Collection, len(byCollection))
-	for name, indices := range byCollection {
-		rows := make([]csvplan.CollectionRow, 0, len(indices))
-		for rowIndex := range indices {
-			rows = append(rows, csvplan.CollectionRow{Index: rowIndex})
+// CollectionsFromClips rebuilds the minimal collections needed to replay the
+// timeline over render clips: row indexes plus the durations and columns that
+// pick and fit read. The window clips of a row fold back into one row.
+func CollectionsFromClips(cfg config.Config, clips []CollectionClip) map[string]Collection {
+	byName := make(map[string][]csvplan.CollectionRow)
+	for _, cc := range clips {
+		rows := byName[cc.CollectionName]
+		if cc.Clip.Window > 1 && len(rows) > 0 && rows[len(rows)-1].Index == cc.Clip.Row.Index {
+			rows[len(rows)-1].DurationSeconds += cc.Clip.DurationSeconds
+			continue
 		}
+		byName[cc.CollectionName] = append(rows, csvplan.CollectionRow{
+			Index:           cc.Clip.Row.Index,
+			DurationSeconds: cc.Clip.DurationSeconds,
+			CustomFields:    cc.Clip.Row.CustomFields,
+		})
+	}
+	collections := make(map[string]Collection, len(byName))
+	for name, rows := range byName {
 		sort.Slice(rows, func(i, j int) bool {
 			return rows[i].Index < rows[j].Index
 		})
-		collections[name] = Collection{Name: name, Rows: rows}
+		collections[name] = Collection{Name: name, Config: cfg.Collections[name], Rows: rows}
 	}
+	return collections
+}
 
-	placements, err := BuildTimelinePlacements(cfg.Timeline, collections)
+// applyPlacementOverrides copies each sequence entry's duration, fade,
+// overlay and audio cue overrides onto the clips it places; merged overlays
+// are localized again so an entry's translated options apply. The window
+// clips of a row each take the overrides, except duration. With a variant,
+// only entries defining it are applied.
+func applyPlacementOverrides(timeline config.TimelineConfig, collections map[string]Collection, byCollection map[string]map[int][]int, clips []CollectionClip, base [][]config.OverlayEntry, locale, variant string) {
+	placements, err := BuildTimelinePlacements(timeline, collections)
 	if err != nil {
 		return
 	}
@@ -180,23 +259,51 @@ func ApplySequenceEntryFades(cfg config.Config, clips []CollectionClip) {
 		if placement.SourceFile != "" || placement.Interleaved {
 			continue
 		}
-		if placement.SequenceEntryIndex < 0 || placement.SequenceEntryIndex >= len(cfg.Timeline.Sequence) {
+		if placement.SequenceEntryIndex < 0 || placement.SequenceEntryIndex >= len(timeline.Sequence) {
 			continue
 		}
-		entry := cfg.Timeline.Sequence[placement.SequenceEntryIndex]
-		if entry.Fade == 0 && entry.FadeIn == 0 && entry.FadeOut == 0 {
+		entry := timeline.Sequence[placement.SequenceEntryIndex]
+		if variant != "" {
+			if _, ok := entry.Variants[variant]; !ok {
+				continue
+			}
+		}
+		hasFade := entry.Fade != 0 || entry.FadeIn != 0 || entry.FadeOut != 0
+		hasCurve := strings.TrimSpace(entry.FadeCurve) != ""
+		if !hasFade && !hasCurve && !entry.HasAudioFade() && entry.Duration <= 0 && len(entry.Overlays) == 0 && entry.AudioCue == nil {
 			continue
 		}
 		indices := byCollection[placement.Collection]
 		if indices == nil {
 			continue
 		}
-		idx, ok := indices[placement.RowIndex]
-		if !ok {
-			continue
+		for _, idx := range indices[placement.RowIndex] {
+			if hasFade {
+				fadeIn, fadeOut := config.ResolveFade(entry.Fade, entry.FadeIn, entry.FadeOut)
+				clips[idx].Clip.FadeInSeconds = fadeIn
+				clips[idx].Clip.FadeOutSeconds = fadeOut
+			}
+			if hasCurve {
+				clips[idx].Clip.FadeCurve = entry.FadeCurve
+			}
+			if entry.AudioFadeIn != nil {
+				clips[idx].Clip.AudioFadeIn = entry.AudioFadeIn
+			}
+			if entry.AudioFadeOut != nil {
+				clips[idx].Clip.AudioFadeOut = entry.AudioFadeOut
+			}
+			// A window clip keeps its own length; the windows column
+			// sets it, not the entry placing the row.
+			if entry.Duration > 0 && clips[idx].Clip.Window == 0 {
+				clips[idx].Clip.DurationSeconds = entry.Duration
+				clips[idx].Clip.Row.DurationSeconds = entry.Duration
+			}
+			if len(entry.Overlays) > 0 {
+				clips[idx].Overlays = config.LocalizeOverlays(config.MergeOverlays(base[idx], entry.Overlays), locale)
+			}
+			if entry.AudioCue != nil {
+				clips[idx].Clip.AudioCue = entry.AudioCue
+			}
 		}
-		fadeIn, fadeOut := config.ResolveFade(entry.Fade, entry.FadeIn, entry.FadeOut)
-		clips[idx].Clip.FadeInSeconds = fadeIn
-		clips[idx].Clip.FadeOutSeconds = fadeOut
 	}
 }
@@ -56,6 +56,26 @@ func TestResolveTimeline(t *testing.T) {
 				{coll: "songs", idx: 4, seq: 4},
 			},
 		},
+		{
+			name: "skipped rows are left out",
+			timeline: config.TimelineConfig{
+				Sequence: []config.SequenceEntry{
+					{Collection: "songs"},
+				},
+			},
+			collections: map[string]Collection{
+				"songs": {Name: "songs", Rows: []csvplan.CollectionRow{
+					{Index: 1},
+					{Index: 2, CustomFields: map[string]string{"skip": "yes"}},
+					{Index: 3, CustomFields: map[string]string{"enabled": "false"}},
+					{Index: 4},
+				}},
+			},
+			want: []entry{
+				{coll: "songs", idx: 1, seq: 1},
+				{coll: "songs", idx: 4, seq: 2},
+			},
+		},
 		{
 			name: "slice limit",
 			timeline: config.TimelineConfig{
@@ -559,3 +579,107 @@ func TestResolveTimeline(t *testing.T) {
 		})
 	}
 }
+
+func TestApplySequenceEntryOverridesCoversVariants(t *testing.T) {
+	cfg := config.Config{Timeline: config.TimelineConfig{Sequence: []config.SequenceEntry{
+		{Collection: "songs"},
+		{Collection: "closing_a", Fade: 2, Variants: map[string]string{"b": "closing_b"}},
+	}}}
+	clips := []CollectionClip{
+		{CollectionName: "songs", Clip: Clip{Row: csvplan.Row{Index: 1}}},
+		{CollectionName: "closing_a", Clip: Clip{Row: csvplan.Row{Index: 1}}},
+		{CollectionName: "closing_b", Clip: Clip{Row: csvplan.Row{Index: 1}}},
+	}
+	ApplySequenceEntryOverrides(cfg, clips)
+
+	if clips[0].Clip.FadeInSeconds != 0 {
+		t.Errorf("songs picked up a fade: %+v", clips[0].Clip)
+	}
+	for _, cc := range clips[1:] {
+		if cc.Clip.FadeInSeconds != 1 || cc.Clip.FadeOutSeconds != 1 {
+			t.Errorf("%s fades = %v/%v, want 1/1", cc.CollectionName, cc.Clip.FadeInSeconds, cc.Clip.FadeOutSeconds)
+		}
+	}
+}
+
+func TestApplySequenceEntryOverrides(t *testing.T) {
+	songInfo := config.OverlayEntry{Type: "song-info", Options: map[string]string{"color": "white"}}
+	cfg := config.Config{
+		Collections: map[string]config.CollectionConfig{
+			"songs": {Plan: "songs.csv", Overlays: []config.OverlayEntry{songInfo}},
+		},
+		Timeline: config.TimelineConfig{Sequence: []config.SequenceEntry{
+			{Collection: "songs", Slice: "start:1"},
+			{Collection: "songs", Duration: 30, Overlays: []config.OverlayEntry{
+				{Type: "song-info", Options: map[string]string{"color": "yellow"}},
+			}},
+		}},
+	}
+	clips := []CollectionClip{
+		{CollectionName: "songs", Overlays: cfg.Collections["songs"].Overlays, Clip: Clip{DurationSeconds: 60, Row: csvplan.Row{Index: 1, DurationSeconds: 60}}},
+		{CollectionName: "songs", Overlays: cfg.Collections["songs"].Overlays, Clip: Clip{DurationSeconds: 60, Row: csvplan.Row{Index: 2, DurationSeconds: 60}}},
+	}
+	ApplySequenceEntryOverrides(cfg, clips)
+
+	if clips[0].Clip.DurationSeconds != 60 || clips[0].Overlays[0].Options["color"] != "white" {
+		t.Errorf("first-half clip changed: %+v", clips[0])
+	}
+	if clips[1].Clip.DurationSeconds != 30 || clips[1].Clip.Row.DurationSeconds != 30 {
+		t.Errorf("second-half duration = %d/%d, want 30", clips[1].Clip.DurationSeconds, clips[1].Clip.Row.DurationSeconds)
+	}
+	if len(clips[1].Overlays) != 1 || clips[1].Overlays[0].Options["color"] != "yellow" {
+		t.Errorf("second-half overlays = %+v, want song-info in yellow", clips[1].Overlays)
+	}
+	if cfg.Collections["songs"].Overlays[0].Options["color"] != "white" {
+		t.Errorf("collection overlays were modified")
+	}
+}
+
+func TestApplySequenceEntryOverridesKeepsWindowLengths(t *testing.T) {
+	cfg := config.Config{Timeline: config.TimelineConfig{Sequence: []config.SequenceEntry{
+		{Collection: "songs", Duration: 45, Fade: 2},
+	}}}
+	windows := map[string]string{"windows": "0:30-0:50; 2:10-2:40"}
+	clips := []CollectionClip{
+		{CollectionName: "songs", Clip: Clip{Window: 1, DurationSeconds: 20, Row: csvplan.Row{Index: 1, DurationSeconds: 20, CustomFields: windows}}},
+		{CollectionName: "songs", Clip: Clip{Window: 2, DurationSeconds: 30, Row: csvplan.Row{Index: 1, DurationSeconds: 30, CustomFields: windows}}},
+		{CollectionName: "songs", Clip: Clip{DurationSeconds: 60, Row: csvplan.Row{Index: 2, DurationSeconds: 60}}},
+	}
+	ApplySequenceEntryOverrides(cfg, clips)
+
+	for i, want := range []int{20, 30, 45} {
+		c := clips[i].Clip
+		if c.DurationSeconds != want || c.Row.DurationSeconds != want {
+			t.Errorf("clip[%d] duration = %d/%d, want %d", i, c.DurationSeconds, c.Row.DurationSeconds, want)
+		}
+		if c.FadeInSeconds != 1 || c.FadeOutSeconds != 1 {
+			t.Errorf("clip[%d] fades = %v/%v, want 1/1", i, c.FadeInSeconds, c.FadeOutSeconds)
+		}
+	}
+}
+
+func TestApplySequenceEntryOverridesAudioCue(t *testing.T) {
+	horn := &config.AudioCueConfig{File: "horn.wav"}
+	drop := &config.AudioCueConfig{File: "drop.wav", Offset: 2}
+	cfg := config.Config{
+		Collections: map[string]config.CollectionConfig{
+			"songs": {Plan: "songs.csv", AudioCue: horn},
+		},
+		Timeline: config.TimelineConfig{Sequence: []config.SequenceEntry{
+			{Collection: "songs", Slice: "start:1"},
+			{Collection: "songs", AudioCue: drop},
+		}},
+	}
+	clips := []CollectionClip{
+		{CollectionName: "songs", Clip: Clip{AudioCue: horn, Row: csvplan.Row{Index: 1}}},
+		{CollectionName: "songs", Clip: Clip{AudioCue: horn, Row: csvplan.Row{Index: 2}}},
+	}
+	ApplySequenceEntryOverrides(cfg, clips)
+
+	if clips[0].Clip.AudioCue != horn {
+		t.Errorf("first clip cue = %+v, want the collection cue", clips[0].Clip.AudioCue)
+	}
+	if clips[1].Clip.AudioCue != drop {
+		t.Errorf("second clip cue = %+v, want the entry cue", clips[1].Clip.AudioCue)
+	}
+}
@@ -0,0 +1,25 @@
+package project
+
+import "powerhour/pkg/csvplan"
+
+// WindowClips splits a clip whose row lists two or more windows (the
+// windows column) into one clip per window, numbered from 1 in Window.
+// Each gets the window's start and length; everything else is shared, so
+// the source is fetched once. Other clips are returned as they are.
+func WindowClips(clip Clip) []Clip {
+	windows := csvplan.RowWindows(clip.Row.CustomFields)
+	if len(windows) < 2 {
+		return []Clip{clip}
+	}
+	clips := make([]Clip, len(windows))
+	for i, w := range windows {
+		c := clip
+		c.Window = i + 1
+		c.Row.StartRaw = w.StartText
+		c.Row.Start = w.Start
+		c.Row.DurationSeconds = w.Seconds()
+		c.DurationSeconds = w.Seconds()
+		clips[i] = c
+	}
+	return clips
+}
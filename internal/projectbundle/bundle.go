@@ -0,0 +1,318 @@
+// Package projectbundle packs a project into a single portable archive and
+// unpacks it again on another machine. Paths recorded inside the bundle
+// (cache index entries, render state keys) are stored relative to named
+// anchors such as $cache or $project so they can be re-resolved against the
+// destination's own directories. Files are stored under the anchor's name
+// without the "$", so a cached download lives at cache/<file>.
+package projectbundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// ManifestName is the bundle manifest at the root of the archive.
+	ManifestName = "bundle.json"
+	// Format is the bundle layout version written by Write.
+	Format = 1
+)
+
+// MetaDir holds metadata that needs its paths re-resolved on import, such
+// as the cache index and render state.
+const MetaDir = "meta"
+
+// Manifest describes a bundle. It is stored as bundle.json.
+type Manifest struct {
+	Format    int       `json:"format"`
+	Project   string    `json:"project"`
+	CreatedAt time.Time `json:"created_at"`
+	// Files lists every archive member other than the manifest.
+	Files    []string `json:"files"`
+	Sources  int      `json:"sources"`
+	Segments int      `json:"segments"`
+	// Skipped names inputs that were left out, such as files outside the
+	// project root, with the reason.
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// Item is a file on disk and the archive name it is stored under.
+type Item struct {
+	Src  string
+	Name string
+}
+
+// Write creates a gzip-compressed tar at dest holding the manifest, the
+// in-memory docs (archive name to contents) and the items. The archive is
+// written to a temporary file and renamed into place.
+func Write(dest string, manifest Manifest, docs map[string][]byte, items []Item) (Manifest, error) {
+	manifest.Format = Format
+	manifest.Files = nil
+	for name := range docs {
+		manifest.Files = append(manifest.Files, name)
+	}
+	for _, item := range items {
+		manifest.Files = append(manifest.Files, item.Name)
+	}
+	sort.Strings(manifest.Files)
+	for _, name := range manifest.Files {
+		if err := checkName(name); err != nil {
+			return Manifest{}, err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return Manifest{}, fmt.Errorf("prepare bundle dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dest), ".bundle-*.tmp")
+	if err != nil {
+		return Manifest{}, fmt.Errorf("create bundle: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	// Media is already compressed; favor speed over a few saved bytes.
+	gz, err := gzip.NewWriterLevel(tmp, gzip.BestSpeed)
+	if err != nil {
+		tmp.Close()
+		return Manifest{}, fmt.Errorf("create bundle: %w", err)
+	}
+	tw := tar.NewWriter(gz)
+
+	meta, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		tmp.Close()
+		return Manifest{}, fmt.Errorf("marshal bundle manifest: %w", err)
+	}
+	docs = withDoc(docs, ManifestName, meta)
+	docNames := make([]string, 0, len(docs))
+	for name := range docs {
+		docNames = append(docNames, name)
+	}
+	sort.Strings(docNames)
+	for _, name := range docNames {
+		if err := writeDoc(tw, name, docs[name], manifest.CreatedAt); err != nil {
+			tmp.Close()
+			return Manifest{}, err
+		}
+	}
+	for _, item := range items {
+		if err := addFile(tw, item.Src, item.Name); err != nil {
+			tmp.Close()
+			return Manifest{}, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		tmp.Close()
+		return Manifest{}, fmt.Errorf("finalize bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		tmp.Close()
+		return Manifest{}, fmt.Errorf("finalize bundle: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return Manifest{}, fmt.Errorf("close bundle: %w", err)
+	}
+	if err := os.Rename(tmpPath, dest); err != nil {
+		return Manifest{}, fmt.Errorf("write bundle: %w", err)
+	}
+	return manifest, nil
+}
+
+func withDoc(docs map[string][]byte, name string, data []byte) map[string][]byte {
+	out := make(map[string][]byte, len(docs)+1)
+	for k, v := range docs {
+		out[k] = v
+	}
+	out[name] = data
+	return out
+}
+
+func writeDoc(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), ModTime: modTime}); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+func addFile(tw *tar.Writer, src, name string) error {
+	file, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", src, err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("stat %s: %w", src, err)
+	}
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	if _, err := io.Copy(tw, file); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+// Extract unpacks a bundle into dir, which should be empty, and returns its
+// manifest. Members with absolute paths or ".." components are rejected.
+func Extract(src, dir string) (Manifest, error) {
+	file, err := os.Open(src)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("open bundle: %w", err)
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("read bundle: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return Manifest{}, fmt.Errorf("read bundle: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := checkName(header.Name); err != nil {
+			return Manifest{}, err
+		}
+		target := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return Manifest{}, fmt.Errorf("prepare %s: %w", header.Name, err)
+		}
+		out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+		if err != nil {
+			return Manifest{}, fmt.Errorf("create %s: %w", header.Name, err)
+		}
+		if _, err := io.Copy(out, tr); err != nil {
+			out.Close()
+			return Manifest{}, fmt.Errorf("write %s: %w", header.Name, err)
+		}
+		if err := out.Close(); err != nil {
+			return Manifest{}, fmt.Errorf("close %s: %w", header.Name, err)
+		}
+		_ = os.Chtimes(target, header.ModTime, header.ModTime)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, ManifestName))
+	if err != nil {
+		return Manifest{}, fmt.Errorf("bundle is missing %s: %w", ManifestName, err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return Manifest{}, fmt.Errorf("parse %s: %w", ManifestName, err)
+	}
+	if manifest.Format != Format {
+		return Manifest{}, fmt.Errorf("unsupported bundle format %d", manifest.Format)
+	}
+	return manifest, nil
+}
+
+func checkName(name string) error {
+	clean := path.Clean(name)
+	if name == "" || path.IsAbs(name) || clean != name || clean == ".." || strings.HasPrefix(clean, "../") {
+		return fmt.Errorf("unsafe bundle path %q", name)
+	}
+	return nil
+}
+
+// Anchor names a directory that paths inside the bundle are recorded
+// relative to, e.g. {"$cache", "/home/me/party/cache"}.
+type Anchor struct {
+	Name string
+	Dir  string
+}
+
+// Portable rewrites path relative to the most specific anchor containing
+// it ("$cache/abc.webm"). Paths outside every anchor are returned as-is.
+func Portable(p string, anchors []Anchor) string {
+	best := -1
+	rel := ""
+	for i, a := range anchors {
+		r, ok := within(a.Dir, p)
+		if ok && (best < 0 || len(a.Dir) > len(anchors[best].Dir)) {
+			best, rel = i, r
+		}
+	}
+	if best < 0 {
+		return p
+	}
+	if rel == "." {
+		return anchors[best].Name
+	}
+	return anchors[best].Name + "/" + filepath.ToSlash(rel)
+}
+
+// Localize reverses Portable against the destination anchors. Unknown
+// anchors and plain paths are returned unchanged.
+func Localize(p string, anchors []Anchor) string {
+	if !strings.HasPrefix(p, "$") {
+		return p
+	}
+	name, rest, _ := strings.Cut(p, "/")
+	for _, a := range anchors {
+		if a.Name == name {
+			return filepath.Join(a.Dir, filepath.FromSlash(rest))
+		}
+	}
+	return p
+}
+
+// within reports p's path relative to dir when p is dir or inside it.
+func within(dir, p string) (string, bool) {
+	if dir == "" || p == "" || !filepath.IsAbs(p) {
+		return "", false
+	}
+	rel, err := filepath.Rel(dir, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
+
+// ArchiveName is where a file under an anchor is stored in the bundle, or
+// "" when p is outside every anchor.
+func ArchiveName(p string, anchors []Anchor) string {
+	portable := Portable(p, anchors)
+	if !strings.HasPrefix(portable, "$") || !strings.Contains(portable, "/") {
+		return ""
+	}
+	return strings.TrimPrefix(portable, "$")
+}
+
+// LocalPath maps an archive member back to its destination path, or ""
+// when its top directory names no anchor.
+func LocalPath(name string, anchors []Anchor) string {
+	local := Localize("$"+name, anchors)
+	if strings.HasPrefix(local, "$") {
+		return ""
+	}
+	return local
+}
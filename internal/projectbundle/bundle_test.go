@@ -0,0 +1,90 @@
+package projectbundle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPortableAndLocalize(t *testing.T) {
+	src := []Anchor{
+		{Name: "$project", Dir: "/home/a/party"},
+		{Name: "$cache", Dir: "/home/a/party/cache"},
+		{Name: "$segments", Dir: "/home/a/party/segments"},
+		{Name: "$segments-warmup", Dir: "/home/a/party/segments-warmup"},
+	}
+	dst := []Anchor{
+		{Name: "$project", Dir: "/srv/b"},
+		{Name: "$cache", Dir: "/lib/sources"},
+		{Name: "$segments", Dir: "/srv/b/segments"},
+		{Name: "$segments-warmup", Dir: "/srv/b/segments-warmup"},
+	}
+	tests := []struct {
+		in, portable, local string
+	}{
+		{"/home/a/party/cache/abc.webm", "$cache/abc.webm", "/lib/sources/abc.webm"},
+		{"/home/a/party/segments/songs/001.mp4", "$segments/songs/001.mp4", "/srv/b/segments/songs/001.mp4"},
+		{"/home/a/party/segments-warmup/songs/001.mp4", "$segments-warmup/songs/001.mp4", "/srv/b/segments-warmup/songs/001.mp4"},
+		{"/home/a/party/songs.yaml", "$project/songs.yaml", "/srv/b/songs.yaml"},
+		{"/home/a/partytime/x.mp4", "/home/a/partytime/x.mp4", "/home/a/partytime/x.mp4"},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		portable := Portable(tt.in, src)
+		if portable != tt.portable {
+			t.Errorf("Portable(%q) = %q, want %q", tt.in, portable, tt.portable)
+		}
+		if local := Localize(portable, dst); local != tt.local {
+			t.Errorf("Localize(%q) = %q, want %q", portable, local, tt.local)
+		}
+	}
+
+	if got := ArchiveName("/home/a/party/cache/abc.webm", src); got != "cache/abc.webm" {
+		t.Errorf("ArchiveName = %q", got)
+	}
+	if got := ArchiveName("/elsewhere/x.mp4", src); got != "" {
+		t.Errorf("ArchiveName outside anchors = %q, want empty", got)
+	}
+	if got := LocalPath("segments-warmup/songs/001.mp4", dst); got != "/srv/b/segments-warmup/songs/001.mp4" {
+		t.Errorf("LocalPath = %q", got)
+	}
+	if got := LocalPath("meta/index.json", dst); got != "" {
+		t.Errorf("LocalPath for unanchored member = %q, want empty", got)
+	}
+}
+
+func TestWriteExtractRoundtrip(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "clip.mp4")
+	if err := os.WriteFile(src, []byte("video"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	dest := filepath.Join(dir, "out", "party.phz")
+	written, err := Write(dest, Manifest{Project: "party", CreatedAt: time.Now().UTC(), Sources: 1},
+		map[string][]byte{"meta/index.json": []byte("{}")},
+		[]Item{{Src: src, Name: "cache/clip.mp4"}})
+	if err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if len(written.Files) != 2 || written.Files[0] != "cache/clip.mp4" || written.Files[1] != "meta/index.json" {
+		t.Errorf("manifest files = %v", written.Files)
+	}
+
+	out := filepath.Join(dir, "extract")
+	manifest, err := Extract(dest, out)
+	if err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	if manifest.Project != "party" || manifest.Sources != 1 || manifest.Format != Format {
+		t.Errorf("manifest = %+v", manifest)
+	}
+	data, err := os.ReadFile(filepath.Join(out, "cache", "clip.mp4"))
+	if err != nil || string(data) != "video" {
+		t.Errorf("extracted clip = %q, %v", data, err)
+	}
+
+	if _, err := Write(dest, Manifest{}, nil, []Item{{Src: src, Name: "../escape.mp4"}}); err == nil {
+		t.Error("expected unsafe archive name to be rejected")
+	}
+}
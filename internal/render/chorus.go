@@ -0,0 +1,124 @@
+package render
+
+import (
+	"math"
+	"sort"
+)
+
+// ChorusCandidate is a suggested clip start from FindChorusCandidates.
+type ChorusCandidate struct {
+	Start      float64 `json:"start_s"`
+	Confidence float64 `json:"confidence"`
+}
+
+// chorusMaxWindow caps the stretch compared when looking for a chorus; a
+// chorus rarely runs longer, and shorter stretches repeat more reliably.
+const chorusMaxWindow = 15
+
+// FindChorusCandidates suggests up to n starts for a clip of length
+// seconds, best first. The samples are bucketed into one-second loudness
+// readings, and each second is scored on how loud the window after it is
+// relative to the rest of the song and how closely the shape of that
+// window's loudness repeats somewhere else, as a chorus does. Candidates
+// are at least a window apart. Confidence is the score, from 0 to 1.
+func FindChorusCandidates(samples []LoudnessSample, length float64, n int) []ChorusCandidate {
+	envelope := loudnessEnvelope(samples)
+	window := int(math.Min(math.Max(length, 4), chorusMaxWindow))
+	if n <= 0 || len(envelope) < window {
+		return nil
+	}
+	starts := len(envelope) - window + 1
+
+	energy := make([]float64, starts)
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for s := range energy {
+		for _, v := range envelope[s : s+window] {
+			energy[s] += v
+		}
+		energy[s] /= float64(window)
+		lo, hi = math.Min(lo, energy[s]), math.Max(hi, energy[s])
+	}
+
+	scores := make([]ChorusCandidate, starts)
+	for s := range scores {
+		loud := 0.0
+		if hi > lo {
+			loud = (energy[s] - lo) / (hi - lo)
+		}
+		repeat := 0.0
+		for other := 0; other < starts; other++ {
+			if other > s-window && other < s+window {
+				continue
+			}
+			repeat = math.Max(repeat, correlation(envelope[s:s+window], envelope[other:other+window]))
+		}
+		scores[s] = ChorusCandidate{Start: float64(s), Confidence: (loud + repeat) / 2}
+	}
+	sort.SliceStable(scores, func(i, j int) bool { return scores[i].Confidence > scores[j].Confidence })
+
+	var picked []ChorusCandidate
+	for _, c := range scores {
+		if len(picked) == n {
+			break
+		}
+		near := false
+		for _, p := range picked {
+			near = near || math.Abs(p.Start-c.Start) < float64(window)
+		}
+		if !near {
+			c.Confidence = math.Round(c.Confidence*100) / 100
+			picked = append(picked, c)
+		}
+	}
+	return picked
+}
+
+// loudnessEnvelope averages samples into one reading per second, with
+// silence floored at -70 LUFS.
+func loudnessEnvelope(samples []LoudnessSample) []float64 {
+	var sums, counts []float64
+	for _, s := range samples {
+		if s.Time < 0 {
+			continue
+		}
+		i := int(s.Time)
+		for len(sums) <= i {
+			sums = append(sums, 0)
+			counts = append(counts, 0)
+		}
+		sums[i] += math.Max(s.LUFS, -70)
+		counts[i]++
+	}
+	envelope := make([]float64, len(sums))
+	prev := -70.0
+	for i := range sums {
+		if counts[i] > 0 {
+			prev = sums[i] / counts[i]
+		}
+		envelope[i] = prev
+	}
+	return envelope
+}
+
+// correlation returns the Pearson correlation of two equal-length series,
+// clamped to 0 when they don't move together or either is flat.
+func correlation(a, b []float64) float64 {
+	var meanA, meanB float64
+	for i := range a {
+		meanA += a[i]
+		meanB += b[i]
+	}
+	meanA /= float64(len(a))
+	meanB /= float64(len(b))
+	var cov, varA, varB float64
+	for i := range a {
+		da, db := a[i]-meanA, b[i]-meanB
+		cov += da * db
+		varA += da * da
+		varB += db * db
+	}
+	if varA == 0 || varB == 0 {
+		return 0
+	}
+	return math.Max(0, cov/math.Sqrt(varA*varB))
+}
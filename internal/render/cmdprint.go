@@ -0,0 +1,105 @@
+package render
+
+import (
+	"regexp"
+	"strings"
+)
+
+// filterArgFlags are the ffmpeg options whose values are filter graphs.
+var filterArgFlags = map[string]string{
+	"-vf":             "video filters",
+	"-af":             "audio filters",
+	"-filter_complex": "filter complex",
+}
+
+// FormatCommand returns argv (the ffmpeg path followed by its arguments)
+// as a shell command to copy and paste: one option and its value per line,
+// values quoted where the shell needs it, followed by each filter graph
+// one filter per line as comments.
+func FormatCommand(argv []string) string {
+	if len(argv) == 0 {
+		return ""
+	}
+	var lines []string
+	var graphs []string
+	args := argv[1:]
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if isOption(arg) && i+1 < len(args) && !isOption(args[i+1]) {
+			value := args[i+1]
+			if label, ok := filterArgFlags[arg]; ok {
+				graphs = append(graphs, "# "+label+":")
+				for _, f := range SplitFilterGraph(value) {
+					graphs = append(graphs, "#   "+f)
+				}
+			}
+			lines = append(lines, arg+" "+shellQuote(value))
+			i++
+			continue
+		}
+		lines = append(lines, shellQuote(arg))
+	}
+
+	var b strings.Builder
+	b.WriteString(shellQuote(argv[0]))
+	for _, line := range lines {
+		b.WriteString(" \\\n  " + line)
+	}
+	b.WriteString("\n")
+	for _, line := range graphs {
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// isOption reports whether arg is an ffmpeg option rather than a value;
+// "-" (stdout) and negative numbers are values.
+func isOption(arg string) bool {
+	if len(arg) < 2 || arg[0] != '-' {
+		return false
+	}
+	return arg[1] < '0' || arg[1] > '9'
+}
+
+// SplitFilterGraph splits an ffmpeg filter graph into its filters at the
+// commas and semicolons between them, leaving separators that are quoted
+// or escaped inside a filter's options alone.
+func SplitFilterGraph(graph string) []string {
+	var filters []string
+	var current strings.Builder
+	quoted := false
+	flush := func() {
+		if f := strings.TrimSpace(current.String()); f != "" {
+			filters = append(filters, f)
+		}
+		current.Reset()
+	}
+	for i := 0; i < len(graph); i++ {
+		c := graph[i]
+		switch {
+		case c == '\\' && i+1 < len(graph):
+			current.WriteByte(c)
+			i++
+			current.WriteByte(graph[i])
+			continue
+		case c == '\'':
+			quoted = !quoted
+		case (c == ',' || c == ';') && !quoted:
+			flush()
+			continue
+		}
+		current.WriteByte(c)
+	}
+	flush()
+	return filters
+}
+
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9_./:=+,@%^-]+$`)
+
+// shellQuote quotes s for a POSIX shell, leaving plain words as they are.
+func shellQuote(s string) string {
+	if shellSafe.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,83 @@
+package render
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/config"
+	"powerhour/internal/paths"
+	"powerhour/pkg/csvplan"
+)
+
+func TestSplitFilterGraph(t *testing.T) {
+	graph := `scale=w=1920:h=1080,drawtext=text='Hello\, world':enable='between(t,1,5)',fps=30;[a]anull[b]`
+	want := []string{
+		"scale=w=1920:h=1080",
+		`drawtext=text='Hello\, world':enable='between(t,1,5)'`,
+		"fps=30",
+		"[a]anull[b]",
+	}
+	if got := SplitFilterGraph(graph); !slices.Equal(got, want) {
+		t.Fatalf("SplitFilterGraph =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestFormatCommand(t *testing.T) {
+	argv := []string{
+		"/usr/bin/ffmpeg", "-hide_banner", "-y",
+		"-ss", "1:30.000", "-i", "/media/It's a song.mp4",
+		"-t", "60",
+		"-vf", "scale=w=640:h=360,fps=30",
+		"-af", "volume=-3dB",
+		"-movflags", "+faststart",
+		"/out/001.mp4",
+	}
+	want := `/usr/bin/ffmpeg \
+  -hide_banner \
+  -y \
+  -ss 1:30.000 \
+  -i '/media/It'\''s a song.mp4' \
+  -t 60 \
+  -vf scale=w=640:h=360,fps=30 \
+  -af volume=-3dB \
+  -movflags +faststart \
+  /out/001.mp4
+# video filters:
+#   scale=w=640:h=360
+#   fps=30
+# audio filters:
+#   volume=-3dB
+`
+	if got := FormatCommand(argv); got != want {
+		t.Fatalf("FormatCommand =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestServiceCommandWritesNothing(t *testing.T) {
+	root := t.TempDir()
+	pp := paths.ProjectPaths{Root: root, SegmentsDir: filepath.Join(root, "segments"), LogsDir: root}
+	cfg := config.Default()
+	svc := &Service{Paths: pp, Config: cfg, ffmpegPath: "/opt/ffmpeg"}
+	seg := newTestSegment(cfg, csvplan.Row{Index: 1, Title: "Song", DurationSeconds: 60})
+	seg.Overlays = nil
+	seg.Entry.Probe = &cache.ProbeMetadata{DurationSeconds: 300}
+	seg.OutputPath = filepath.Join(pp.SegmentsDir, "001.mp4")
+
+	argv, err := svc.Command(context.Background(), seg)
+	if err != nil {
+		t.Fatalf("Command: %v", err)
+	}
+	if argv[0] != "/opt/ffmpeg" || argv[len(argv)-1] != seg.OutputPath {
+		t.Fatalf("unexpected command %v", argv)
+	}
+	if slices.Contains(argv, "-progress") {
+		t.Error("the printed command shouldn't carry render's progress pipe")
+	}
+	if _, err := os.Stat(pp.SegmentsDir); !os.IsNotExist(err) {
+		t.Errorf("Command created %s", pp.SegmentsDir)
+	}
+}
@@ -0,0 +1,90 @@
+package render
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"powerhour/internal/config"
+	"powerhour/internal/project"
+)
+
+// OutputCollision is a set of clips whose segments would be written to the
+// same file, so all but the last to render would be lost.
+type OutputCollision struct {
+	OutputPath string
+	Clips      []project.CollectionClip
+}
+
+// CollisionError reports every output path shared by more than one clip.
+type CollisionError struct {
+	Collisions []OutputCollision
+}
+
+func (e *CollisionError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d segment file(s) would be written by more than one row:", len(e.Collisions))
+	for _, c := range e.Collisions {
+		labels := make([]string, len(c.Clips))
+		for i, cc := range c.Clips {
+			labels[i] = fmt.Sprintf("%s #%03d", cc.CollectionName, clipRowIndex(cc.Clip))
+		}
+		fmt.Fprintf(&b, "\n  %s: %s", c.OutputPath, strings.Join(labels, ", "))
+	}
+	b.WriteString("\nmake the segment template unique per row, e.g. include $INDEX_PAD3 (or $COLLECTION when collections share a directory)")
+	return b.String()
+}
+
+// FindOutputCollisions builds the output path of every clip the way the
+// segment builders do and groups the clips that share one. Paths compare
+// case-insensitively: FAT, exFAT and default macOS volumes can't hold two
+// names that differ only in case.
+func FindOutputCollisions(cfg config.Config, segmentsDir string, clips []project.CollectionClip) []OutputCollision {
+	byKey := map[string]*OutputCollision{}
+	var keys []string
+	for _, cc := range clips {
+		clip := cc.Clip
+		clip.Row.DurationSeconds = clip.DurationSeconds
+		clip.Row.Index = clipRowIndex(clip)
+		path := CollectionSegmentPath(cfg, segmentsDir, cc.CollectionName, cc.OutputDir, Segment{Clip: clip, Overlays: cc.Overlays})
+
+		key := strings.ToLower(filepath.Clean(path))
+		c, ok := byKey[key]
+		if !ok {
+			c = &OutputCollision{OutputPath: path}
+			byKey[key] = c
+			keys = append(keys, key)
+		}
+		c.Clips = append(c.Clips, cc)
+	}
+
+	var collisions []OutputCollision
+	for _, key := range keys {
+		if c := byKey[key]; len(c.Clips) > 1 {
+			collisions = append(collisions, *c)
+		}
+	}
+	sort.Slice(collisions, func(i, j int) bool { return collisions[i].OutputPath < collisions[j].OutputPath })
+	return collisions
+}
+
+// CheckOutputCollisions returns a *CollisionError when two clips would write
+// the same segment file.
+func CheckOutputCollisions(cfg config.Config, segmentsDir string, clips []project.CollectionClip) error {
+	if collisions := FindOutputCollisions(cfg, segmentsDir, clips); len(collisions) > 0 {
+		return &CollisionError{Collisions: collisions}
+	}
+	return nil
+}
+
+// clipRowIndex is the index a segment builder gives the clip's row.
+func clipRowIndex(clip project.Clip) int {
+	if clip.Row.Index > 0 {
+		return clip.Row.Index
+	}
+	if clip.TypeIndex > 0 {
+		return clip.TypeIndex
+	}
+	return clip.Sequence
+}
@@ -0,0 +1,60 @@
+package render
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"powerhour/internal/config"
+	"powerhour/internal/project"
+	"powerhour/pkg/csvplan"
+)
+
+func TestFindOutputCollisions(t *testing.T) {
+	cfg := config.Default()
+	cfg.Outputs.SegmentTemplate = "$TITLE"
+	clip := func(collection, outputDir string, index int, title string) project.CollectionClip {
+		return project.CollectionClip{
+			CollectionName: collection,
+			OutputDir:      outputDir,
+			Clip: project.Clip{
+				Sequence:  index,
+				TypeIndex: index,
+				Row:       csvplan.Row{Index: index, Title: title},
+			},
+		}
+	}
+
+	clips := []project.CollectionClip{
+		clip("songs", "songs", 1, "Intro"),
+		clip("songs", "songs", 2, "Teenagers"),
+		clip("songs", "songs", 3, "intro"), // differs only in case
+		clip("bumpers", "bumpers", 1, "Intro"),
+		clip("extras", "songs", 7, "Teenagers"), // shares the songs directory
+	}
+	collisions := FindOutputCollisions(cfg, "/segs", clips)
+	if len(collisions) != 2 {
+		t.Fatalf("got %d collisions, want 2: %+v", len(collisions), collisions)
+	}
+	for _, c := range collisions {
+		if len(c.Clips) != 2 {
+			t.Errorf("%s: got %d clips, want 2", c.OutputPath, len(c.Clips))
+		}
+	}
+
+	err := CheckOutputCollisions(cfg, "/segs", clips)
+	var collisionErr *CollisionError
+	if !errors.As(err, &collisionErr) {
+		t.Fatalf("expected CollisionError, got %v", err)
+	}
+	for _, want := range []string{"songs #001, songs #003", "songs #002, extras #007"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error missing %q:\n%s", want, err)
+		}
+	}
+
+	cfg.Outputs.SegmentTemplate = "$INDEX_PAD3_$TITLE"
+	if err := CheckOutputCollisions(cfg, "/segs", clips); err != nil {
+		t.Fatalf("unique template still collides: %v", err)
+	}
+}
@@ -0,0 +1,250 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/config"
+)
+
+// Input values the colorspace filter accepts, by option. A source tagged
+// with anything else is left alone rather than failing the render.
+var (
+	colorspaceSpaces = map[string]bool{
+		"bt709": true, "fcc": true, "bt470bg": true, "smpte170m": true,
+		"smpte240m": true, "ycgco": true, "bt2020nc": true, "bt2020ncl": true,
+	}
+	colorspacePrimaries = map[string]bool{
+		"bt709": true, "bt470m": true, "bt470bg": true, "smpte170m": true,
+		"smpte240m": true, "smpte428": true, "film": true, "smpte431": true,
+		"smpte432": true, "bt2020": true, "jedec-p22": true, "ebu3213": true,
+	}
+	colorspaceTransfers = map[string]bool{
+		"bt709": true, "bt470m": true, "gamma22": true, "bt470bg": true,
+		"gamma28": true, "smpte170m": true, "smpte240m": true, "linear": true,
+		"srgb": true, "iec61966-2-1": true, "xvycc": true, "iec61966-2-4": true,
+		"bt2020-10": true, "bt2020-12": true,
+	}
+	// colorspaceDefaults picks the colorspace filter's iall preset from a
+	// matrix or primaries tag, so untagged properties get the standard's
+	// values instead of failing the filter.
+	colorspaceDefaults = map[string]string{
+		"smpte170m": "bt601-6-525",
+		"bt470bg":   "bt601-6-625",
+		"bt470m":    "bt470m",
+		"smpte240m": "smpte240m",
+		"bt2020nc":  "bt2020",
+		"bt2020ncl": "bt2020",
+		"bt2020":    "bt2020",
+	}
+)
+
+// ResolveColor returns the filters that bring a cached source to
+// limited-range BT.709, from the color metadata in its probe, or "" when
+// it already is, isn't probed, or video.color is off. With video.hdr set
+// the target is the HDR output instead, and unprobed sources are treated
+// as BT.709 so they are mapped up like every other SDR source.
+func ResolveColor(cfg config.Config, entry cache.Entry) string {
+	if !cfg.Video.ColorManaged() {
+		return ""
+	}
+	var color cache.ColorInfo
+	if entry.Probe != nil {
+		color = entry.Probe.Color()
+	}
+	if mode := cfg.Video.HDRMode(); mode != "" {
+		return HDRFilters(color, mode)
+	}
+	if entry.Probe == nil {
+		return ""
+	}
+	return ColorFilters(color)
+}
+
+// ColorFilters returns the conversion for a source with the given color
+// metadata. HDR (PQ or HLG) is tone-mapped to SDR with zscale and hable;
+// SDR with other primaries or matrix (BT.601, BT.2020) goes through the
+// colorspace filter; full-range BT.709 is only squeezed to limited range.
+// Untagged sources are assumed to be BT.709 already.
+func ColorFilters(c cache.ColorInfo) string {
+	if c.HDR() {
+		return hdrToSDR(c)
+	}
+	space := c.Space != "" && c.Space != "bt709"
+	primaries := c.Primaries != "" && c.Primaries != "bt709"
+	if !space && !primaries {
+		if c.Range == "pc" {
+			return "scale=in_range=full:out_range=limited"
+		}
+		return ""
+	}
+
+	preset := colorspaceDefaults[c.Space]
+	if preset == "" {
+		preset = colorspaceDefaults[c.Primaries]
+	}
+	opts := []string{"all=bt709", "range=tv"}
+	if preset != "" {
+		opts = append(opts, "iall="+preset)
+	}
+	known := preset != ""
+	if colorspaceSpaces[c.Space] {
+		opts = append(opts, "ispace="+c.Space)
+	} else if c.Space != "" {
+		return ""
+	}
+	if colorspacePrimaries[c.Primaries] {
+		opts = append(opts, "iprimaries="+c.Primaries)
+		known = known || c.Space != ""
+	} else if c.Primaries != "" {
+		return ""
+	}
+	if colorspaceTransfers[c.Transfer] {
+		opts = append(opts, "itrc="+c.Transfer)
+	}
+	if !known {
+		return ""
+	}
+	if c.Range == "tv" || c.Range == "pc" {
+		opts = append(opts, "irange="+c.Range)
+	}
+	return "colorspace=" + strings.Join(opts, ":")
+}
+
+// hdrToSDR linearizes the HDR source, maps BT.2020 to BT.709 primaries,
+// tone-maps with hable and converts back to limited-range BT.709. zscale
+// needs ffmpeg built with libzimg.
+func hdrToSDR(c cache.ColorInfo) string {
+	in := []string{
+		"tin=" + c.Transfer,
+		"min=" + firstColor(c.Space, "bt2020nc"),
+		"pin=" + firstColor(c.Primaries, "bt2020"),
+		"rin=" + firstColor(c.Range, "tv"),
+	}
+	return strings.Join([]string{
+		"zscale=" + strings.Join(in, ":") + ":t=linear:npl=100",
+		"format=gbrpf32le",
+		"zscale=p=bt709",
+		"tonemap=tonemap=hable:desat=0",
+		"zscale=t=bt709:m=bt709:r=tv",
+		"format=yuv420p",
+	}, ",")
+}
+
+// SDR reference white lands at 203 nits when mapped up (ITU-R BT.2408);
+// HDR sources are converted between PQ and HLG against a 1000-nit display.
+const (
+	sdrWhiteNits = 203
+	hdrPeakNits  = 1000
+)
+
+// hdrMasterDisplay and hdrContentLight describe the output to HDR10 TVs:
+// a P3-D65 mastering display of 0.0001-1000 nits, and content peaking at
+// 1000 nits with a 400-nit frame average.
+const (
+	hdrMasterDisplay = "G(13250,34500)B(7500,3000)R(34000,16000)WP(15635,16450)L(10000000,1)"
+	hdrContentLight  = "1000,400"
+)
+
+// hdrTransfer returns ffmpeg's transfer name for a video.hdr mode.
+func hdrTransfer(mode string) string {
+	if mode == config.HDRHLG {
+		return "arib-std-b67"
+	}
+	return "smpte2084"
+}
+
+// HDRFilters returns the conversion for a source with the given color
+// metadata to 10-bit limited-range BT.2020 with the transfer of the
+// video.hdr mode. SDR sources, tagged or not, are linearized and mapped up
+// with their white at sdrWhiteNits, so every SDR segment sits at the same
+// brightness; HDR sources in the other transfer are converted, and those
+// already matching are left alone.
+func HDRFilters(c cache.ColorInfo, mode string) string {
+	target := hdrTransfer(mode)
+	out := "p=bt2020:t=" + target + ":m=bt2020nc:r=tv"
+	if c.HDR() {
+		if c.Transfer == target && firstColor(c.Primaries, "bt2020") == "bt2020" &&
+			firstColor(c.Space, "bt2020nc") == "bt2020nc" && c.Range != "pc" {
+			return ""
+		}
+		in := []string{
+			"tin=" + c.Transfer,
+			"min=" + firstColor(c.Space, "bt2020nc"),
+			"pin=" + firstColor(c.Primaries, "bt2020"),
+			"rin=" + firstColor(c.Range, "tv"),
+		}
+		return fmt.Sprintf("zscale=%s:%s:npl=%d,format=yuv420p10le", strings.Join(in, ":"), out, hdrPeakNits)
+	}
+	in := []string{
+		"tin=" + firstColor(c.Transfer, "bt709"),
+		"min=" + firstColor(c.Space, "bt709"),
+		"pin=" + firstColor(c.Primaries, "bt709"),
+		"rin=" + firstColor(c.Range, "tv"),
+	}
+	return strings.Join([]string{
+		fmt.Sprintf("zscale=%s:t=linear:npl=%d", strings.Join(in, ":"), sdrWhiteNits),
+		"format=gbrpf32le",
+		fmt.Sprintf("zscale=%s:npl=%d", out, sdrWhiteNits),
+		"format=yuv420p10le",
+	}, ",")
+}
+
+func firstColor(value, fallback string) string {
+	if value != "" {
+		return value
+	}
+	return fallback
+}
+
+// colorTagArgs tags the encoded output as limited-range BT.709, so players
+// don't guess, when video.color is on, or as BT.2020 HDR with video.hdr.
+func colorTagArgs(cfg config.Config) []string {
+	if !cfg.Video.ColorManaged() {
+		return nil
+	}
+	if mode := cfg.Video.HDRMode(); mode != "" {
+		return []string{"-colorspace", "bt2020nc", "-color_primaries", "bt2020", "-color_trc", hdrTransfer(mode), "-color_range", "tv"}
+	}
+	return []string{"-colorspace", "bt709", "-color_primaries", "bt709", "-color_trc", "bt709", "-color_range", "tv"}
+}
+
+// OutputPixFmt returns the pixel format segments are encoded in:
+// video.pix_fmt when set, else 8-bit yuv420p, or with video.hdr the 10-bit
+// format the encoder takes (libx265 reads planar yuv420p10le, the hardware
+// HEVC encoders p010le).
+func OutputPixFmt(cfg config.Config) string {
+	if pixFmt := strings.TrimSpace(cfg.Video.PixFmt); pixFmt != "" {
+		return pixFmt
+	}
+	if cfg.Video.HDRMode() == "" {
+		return "yuv420p"
+	}
+	if strings.TrimSpace(cfg.Video.Codec) == "libx265" {
+		return "yuv420p10le"
+	}
+	return "p010le"
+}
+
+// HDREncodeArgs returns the encoder arguments of the video.hdr output:
+// pixel format, color tags, the Main 10 profile and the hvc1 tag Apple
+// players need. libx265 also writes the HDR10 mastering display and
+// content light level for PQ; the hardware encoders only carry the tags.
+// Nil for SDR output.
+func HDREncodeArgs(cfg config.Config) []string {
+	mode := cfg.Video.HDRMode()
+	if mode == "" {
+		return nil
+	}
+	args := append([]string{"-pix_fmt", OutputPixFmt(cfg)}, colorTagArgs(cfg)...)
+	args = append(args, "-tag:v", "hvc1")
+	if strings.TrimSpace(cfg.Video.Codec) != "libx265" {
+		return append(args, "-profile:v", "main10")
+	}
+	params := []string{"colorprim=bt2020", "colormatrix=bt2020nc", "transfer=" + hdrTransfer(mode), "repeat-headers=1"}
+	if mode == config.HDRPQ {
+		params = append(params, "hdr10=1", "hdr10-opt=1", "master-display="+hdrMasterDisplay, "max-cll="+hdrContentLight)
+	}
+	return append(args, "-x265-params", strings.Join(params, ":"))
+}
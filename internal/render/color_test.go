@@ -0,0 +1,165 @@
+package render
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/config"
+	"powerhour/pkg/csvplan"
+)
+
+func TestColorFilters(t *testing.T) {
+	tests := []struct {
+		name string
+		in   cache.ColorInfo
+		want string
+	}{
+		{"untagged", cache.ColorInfo{}, ""},
+		{"bt709", cache.ColorInfo{Space: "bt709", Transfer: "bt709", Primaries: "bt709", Range: "tv"}, ""},
+		{"bt709 full range", cache.ColorInfo{Space: "bt709", Range: "pc"}, "scale=in_range=full:out_range=limited"},
+		{"ntsc sd", cache.ColorInfo{Space: "smpte170m", Transfer: "smpte170m", Primaries: "smpte170m", Range: "tv"},
+			"colorspace=all=bt709:range=tv:iall=bt601-6-525:ispace=smpte170m:iprimaries=smpte170m:itrc=smpte170m:irange=tv"},
+		{"pal sd matrix only", cache.ColorInfo{Space: "bt470bg"}, "colorspace=all=bt709:range=tv:iall=bt601-6-625:ispace=bt470bg"},
+		{"bt2020 sdr", cache.ColorInfo{Space: "bt2020nc", Transfer: "bt709", Primaries: "bt2020"},
+			"colorspace=all=bt709:range=tv:iall=bt2020:ispace=bt2020nc:iprimaries=bt2020:itrc=bt709"},
+		{"unsupported matrix", cache.ColorInfo{Space: "chroma-derived-nc"}, ""},
+		{"hdr10", cache.ColorInfo{Space: "bt2020nc", Transfer: "smpte2084", Primaries: "bt2020", Range: "tv"},
+			"zscale=tin=smpte2084:min=bt2020nc:pin=bt2020:rin=tv:t=linear:npl=100,format=gbrpf32le,zscale=p=bt709,tonemap=tonemap=hable:desat=0,zscale=t=bt709:m=bt709:r=tv,format=yuv420p"},
+		{"hlg untagged primaries", cache.ColorInfo{Transfer: "arib-std-b67"},
+			"zscale=tin=arib-std-b67:min=bt2020nc:pin=bt2020:rin=tv:t=linear:npl=100,format=gbrpf32le,zscale=p=bt709,tonemap=tonemap=hable:desat=0,zscale=t=bt709:m=bt709:r=tv,format=yuv420p"},
+	}
+	for _, tt := range tests {
+		if got := ColorFilters(tt.in); got != tt.want {
+			t.Errorf("%s: ColorFilters =\n  %s\nwant\n  %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestResolveColor(t *testing.T) {
+	entry := cache.Entry{Probe: &cache.ProbeMetadata{Streams: json.RawMessage(`[{"codec_type":"video","color_space":"bt470bg"}]`)}}
+	off := config.Default()
+	off.Video.Color = config.ColorOff
+
+	if got := ResolveColor(config.Default(), entry); !strings.HasPrefix(got, "colorspace=") {
+		t.Errorf("default config: ResolveColor = %q, want a colorspace conversion", got)
+	}
+	if got := ResolveColor(off, entry); got != "" {
+		t.Errorf("video.color off: ResolveColor = %q, want none", got)
+	}
+	if got := ResolveColor(config.Default(), cache.Entry{}); got != "" {
+		t.Errorf("no probe: ResolveColor = %q, want none", got)
+	}
+	if args := colorTagArgs(off); args != nil {
+		t.Errorf("video.color off: tag args = %v, want none", args)
+	}
+	if args := colorTagArgs(config.Default()); !slices.Contains(args, "-color_primaries") {
+		t.Errorf("tag args = %v, want BT.709 tags", args)
+	}
+}
+
+func TestBuildFilterGraphConvertsColorFirst(t *testing.T) {
+	cfg := config.Default()
+	seg := newTestSegment(cfg, csvplan.Row{Index: 1, DurationSeconds: 60})
+	seg.Overlays = nil
+	seg.Crop = "crop=1920:800:0:140"
+	seg.Color = "scale=in_range=full:out_range=limited"
+
+	graph, err := BuildFilterGraph(seg, cfg)
+	if err != nil {
+		t.Fatalf("BuildFilterGraph error: %v", err)
+	}
+	if !strings.HasPrefix(graph, "scale=in_range=full:out_range=limited,crop=1920:800:0:140,scale=") {
+		t.Fatalf("expected color conversion ahead of crop, got %s", graph)
+	}
+
+	before := SegmentInputHash(Segment{Clip: seg.Clip}, "")
+	if SegmentInputHash(seg, "") == before {
+		t.Error("a color conversion should change the input hash")
+	}
+	if _, ok := SegmentInputParts(Segment{Clip: seg.Clip}, "")[InputPartColor]; ok {
+		t.Error("segments without a conversion should have no color part")
+	}
+}
+
+func TestHDRFilters(t *testing.T) {
+	sdrToPQ := "zscale=tin=bt709:min=bt709:pin=bt709:rin=tv:t=linear:npl=203,format=gbrpf32le,zscale=p=bt2020:t=smpte2084:m=bt2020nc:r=tv:npl=203,format=yuv420p10le"
+	tests := []struct {
+		name string
+		in   cache.ColorInfo
+		mode string
+		want string
+	}{
+		{"untagged to pq", cache.ColorInfo{}, config.HDRPQ, sdrToPQ},
+		{"bt709 to pq", cache.ColorInfo{Space: "bt709", Transfer: "bt709", Primaries: "bt709", Range: "tv"}, config.HDRPQ, sdrToPQ},
+		{"ntsc sd to hlg", cache.ColorInfo{Space: "smpte170m", Transfer: "smpte170m", Primaries: "smpte170m"}, config.HDRHLG,
+			"zscale=tin=smpte170m:min=smpte170m:pin=smpte170m:rin=tv:t=linear:npl=203,format=gbrpf32le,zscale=p=bt2020:t=arib-std-b67:m=bt2020nc:r=tv:npl=203,format=yuv420p10le"},
+		{"hdr10 to pq", cache.ColorInfo{Space: "bt2020nc", Transfer: "smpte2084", Primaries: "bt2020", Range: "tv"}, config.HDRPQ, ""},
+		{"hlg to pq", cache.ColorInfo{Transfer: "arib-std-b67"}, config.HDRPQ,
+			"zscale=tin=arib-std-b67:min=bt2020nc:pin=bt2020:rin=tv:p=bt2020:t=smpte2084:m=bt2020nc:r=tv:npl=1000,format=yuv420p10le"},
+	}
+	for _, tt := range tests {
+		if got := HDRFilters(tt.in, tt.mode); got != tt.want {
+			t.Errorf("%s: HDRFilters =\n  %s\nwant\n  %s", tt.name, got, tt.want)
+		}
+	}
+
+	cfg := config.Default()
+	cfg.Video.HDR = config.HDRPQ
+	if got := ResolveColor(cfg, cache.Entry{}); got != sdrToPQ {
+		t.Errorf("unprobed source with video.hdr: ResolveColor = %q, want it mapped up", got)
+	}
+}
+
+func TestBuildFFmpegCmdHDR(t *testing.T) {
+	cfg := config.Default()
+	cfg.Video.HDR = config.HDRPQ
+	cfg.Video.Codec = "libx265"
+	seg := newTestSegment(cfg, csvplan.Row{Index: 1, DurationSeconds: 60})
+
+	args, err := BuildFFmpegCmd(seg, "out.mp4", "scale=1920:1080", "", cfg)
+	if err != nil {
+		t.Fatalf("BuildFFmpegCmd error: %v", err)
+	}
+	joined := strings.Join(args, " ")
+	for _, want := range []string{"-pix_fmt yuv420p10le", "-color_trc smpte2084", "-color_primaries bt2020", "master-display=", "-tag:v hvc1"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("args missing %q: %s", want, joined)
+		}
+	}
+
+	cfg.Video.HDR = config.HDRHLG
+	cfg.Video.Codec = "hevc_nvenc"
+	args, err = BuildFFmpegCmd(seg, "out.mp4", "scale=1920:1080", "", cfg)
+	if err != nil {
+		t.Fatalf("BuildFFmpegCmd error: %v", err)
+	}
+	joined = strings.Join(args, " ")
+	for _, want := range []string{"-pix_fmt p010le", "-color_trc arib-std-b67", "-profile:v main10"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("args missing %q: %s", want, joined)
+		}
+	}
+	if strings.Contains(joined, "x265-params") {
+		t.Errorf("hardware encoder should not get x265 params: %s", joined)
+	}
+}
+
+func TestOutputPixFmt(t *testing.T) {
+	cfg := config.Default()
+	if got := OutputPixFmt(cfg); got != "yuv420p" {
+		t.Errorf("default: OutputPixFmt = %q, want yuv420p", got)
+	}
+	cfg.Video.Codec = "libsvtav1"
+	cfg.Video.PixFmt = "yuv420p10le"
+	seg := newTestSegment(cfg, csvplan.Row{Index: 1, DurationSeconds: 60})
+	args, err := BuildFFmpegCmd(seg, "out.mp4", "scale=1920:1080", "", cfg)
+	if err != nil {
+		t.Fatalf("BuildFFmpegCmd error: %v", err)
+	}
+	if i := slices.Index(args, "-pix_fmt"); i < 0 || args[i+1] != "yuv420p10le" {
+		t.Errorf("expected -pix_fmt yuv420p10le, got %v", args)
+	}
+}
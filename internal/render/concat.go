@@ -6,15 +6,19 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"math"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"sort"
 	"strings"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"powerhour/internal/config"
 	"powerhour/internal/paths"
 	"powerhour/internal/project"
+	"powerhour/internal/telemetry"
 	"powerhour/internal/tools"
 	"powerhour/pkg/csvplan"
 )
@@ -24,6 +28,7 @@ import (
 type TimelineSegmentPath struct {
 	CollectionName string
 	Index          int
+	Window         int // project.Clip.Window
 	Path           string
 }
 
@@ -35,20 +40,21 @@ func ResolveTimelineSegments(pp paths.ProjectPaths, cfg config.Config, collectio
 		return resolveSegmentsFallback(pp)
 	}
 
-	placements, err := project.BuildTimelinePlacements(cfg.Timeline, collections)
+	placements, err := project.BuildTimelinePlacements(cfg.Timeline, project.WithoutSkippedRows(collections))
 	if err != nil {
 		return nil, err
 	}
 
-	collPaths := make(map[string]map[int]TimelineSegmentPath, len(collections))
+	// A row with windows has one path per window, played back to back.
+	collPaths := make(map[string]map[int][]TimelineSegmentPath, len(collections))
 	for name, coll := range collections {
 		ordered, err := buildCollectionPaths(pp, cfg, name, coll)
 		if err != nil {
 			return nil, err
 		}
-		byIndex := make(map[int]TimelineSegmentPath, len(ordered))
+		byIndex := make(map[int][]TimelineSegmentPath, len(ordered))
 		for _, path := range ordered {
-			byIndex[path.Index] = path
+			byIndex[path.Index] = append(byIndex[path.Index], path)
 		}
 		collPaths[name] = byIndex
 	}
@@ -67,11 +73,11 @@ func ResolveTimelineSegments(pp paths.ProjectPaths, cfg config.Config, collectio
 		if !ok {
 			return nil, fmt.Errorf("timeline references unknown collection %q", placement.Collection)
 		}
-		path, ok := pathsByIndex[placement.RowIndex]
+		rowPaths, ok := pathsByIndex[placement.RowIndex]
 		if !ok {
 			return nil, fmt.Errorf("timeline references missing row %d in collection %q", placement.RowIndex, placement.Collection)
 		}
-		result = append(result, path)
+		result = append(result, rowPaths...)
 	}
 
 	return result, nil
@@ -91,25 +97,15 @@ func ResolveTimelineClips(cfg config.Config, collClips []project.CollectionClip)
 		return nil, fmt.Errorf("no timeline sequence configured")
 	}
 
-	byCollection := make(map[string]map[int]project.CollectionClip)
+	byCollection := make(map[string]map[int][]project.CollectionClip)
 	for _, cc := range collClips {
 		if byCollection[cc.CollectionName] == nil {
-			byCollection[cc.CollectionName] = make(map[int]project.CollectionClip)
+			byCollection[cc.CollectionName] = make(map[int][]project.CollectionClip)
 		}
-		byCollection[cc.CollectionName][cc.Clip.Row.Index] = cc
+		byCollection[cc.CollectionName][cc.Clip.Row.Index] = append(byCollection[cc.CollectionName][cc.Clip.Row.Index], cc)
 	}
 
-	collections := make(map[string]project.Collection, len(byCollection))
-	for name, clips := range byCollection {
-		rows := make([]csvplan.CollectionRow, 0, len(clips))
-		for rowIndex := range clips {
-			rows = append(rows, csvplan.CollectionRow{Index: rowIndex})
-		}
-		sort.Slice(rows, func(i, j int) bool {
-			return rows[i].Index < rows[j].Index
-		})
-		collections[name] = project.Collection{Name: name, Rows: rows}
-	}
+	collections := project.CollectionsFromClips(cfg, collClips)
 
 	placements, err := project.BuildTimelinePlacements(cfg.Timeline, collections)
 	if err != nil {
@@ -125,11 +121,13 @@ func ResolveTimelineClips(cfg config.Config, collClips []project.CollectionClip)
 		if !ok {
 			return nil, fmt.Errorf("timeline references unknown collection %q", placement.Collection)
 		}
-		cc, ok := clipsByIndex[placement.RowIndex]
+		rowClips, ok := clipsByIndex[placement.RowIndex]
 		if !ok {
 			return nil, fmt.Errorf("timeline references missing row %d in collection %q", placement.RowIndex, placement.Collection)
 		}
-		result = append(result, TimelineClip{CollectionName: cc.CollectionName, CollectionClip: cc})
+		for _, cc := range rowClips {
+			result = append(result, TimelineClip{CollectionName: cc.CollectionName, CollectionClip: cc})
+		}
 	}
 
 	return result, nil
@@ -138,13 +136,6 @@ func ResolveTimelineClips(cfg config.Config, collClips []project.CollectionClip)
 // buildCollectionPaths returns the expected output paths for all rows in a
 // collection, sorted by row index.
 func buildCollectionPaths(pp paths.ProjectPaths, cfg config.Config, name string, coll project.Collection) ([]TimelineSegmentPath, error) {
-	outputDir := coll.OutputDir
-	if !filepath.IsAbs(outputDir) {
-		outputDir = filepath.Join(pp.SegmentsDir, outputDir)
-	}
-
-	tmpl := cfg.SegmentFilenameTemplate()
-
 	// Sort rows by index for stable ordering.
 	rows := make([]csvplan.CollectionRow, len(coll.Rows))
 	copy(rows, coll.Rows)
@@ -153,22 +144,23 @@ func buildCollectionPaths(pp paths.ProjectPaths, cfg config.Config, name string,
 	})
 
 	segPaths := make([]TimelineSegmentPath, 0, len(rows))
-	for seq, collRow := range rows {
+	for _, collRow := range rows {
 		row := collRow.ToRow()
 		clip := project.Clip{
-			Sequence:  seq + 1,
 			ClipType:  project.ClipType(name),
 			TypeIndex: row.Index,
 			Row:       row,
 		}
-		seg := Segment{Clip: clip}
-		baseName := SegmentBaseName(tmpl, seg)
-		outputPath := filepath.Join(outputDir, baseName+".mp4")
-		segPaths = append(segPaths, TimelineSegmentPath{
-			CollectionName: name,
-			Index:          row.Index,
-			Path:           outputPath,
-		})
+		for _, windowClip := range project.WindowClips(clip) {
+			windowClip.Sequence = len(segPaths) + 1
+			outputPath := CollectionSegmentPath(cfg, pp.SegmentsDir, name, coll.OutputDir, Segment{Clip: windowClip})
+			segPaths = append(segPaths, TimelineSegmentPath{
+				CollectionName: name,
+				Index:          row.Index,
+				Window:         windowClip.Window,
+				Path:           outputPath,
+			})
+		}
 	}
 	return segPaths, nil
 }
@@ -203,6 +195,33 @@ func resolveSegmentsFallback(pp paths.ProjectPaths) ([]TimelineSegmentPath, erro
 // WriteConcatList writes an ffmpeg concat demuxer list to concatFile.
 // It verifies each segment path exists before writing.
 func WriteConcatList(concatFile string, segments []TimelineSegmentPath) error {
+	return writeConcatList(concatFile, segments, nil, 0)
+}
+
+// WriteGridConcatList writes a concat list like WriteConcatList, but pins
+// every segment with a planned length (planned[i] > 0) to exactly that many
+// seconds, rounded to whole frames at fps: an outpoint trims a segment that
+// runs long and a duration makes the next one start on time when it runs
+// short. Only a re-encode (RunGridConcat) honours both.
+func WriteGridConcatList(concatFile string, segments []TimelineSegmentPath, planned []float64, fps int) error {
+	if fps <= 0 {
+		return fmt.Errorf("grid concat needs a frame rate")
+	}
+	if len(planned) != len(segments) {
+		return fmt.Errorf("grid concat: %d planned lengths for %d segments", len(planned), len(segments))
+	}
+	return writeConcatList(concatFile, segments, planned, fps)
+}
+
+// SnapToFrames rounds seconds to a whole number of frames at fps.
+func SnapToFrames(seconds float64, fps int) float64 {
+	if fps <= 0 {
+		return seconds
+	}
+	return math.Round(seconds*float64(fps)) / float64(fps)
+}
+
+func writeConcatList(concatFile string, segments []TimelineSegmentPath, planned []float64, fps int) error {
 	var missing []string
 	for _, seg := range segments {
 		if _, err := os.Stat(seg.Path); os.IsNotExist(err) {
@@ -219,7 +238,7 @@ func WriteConcatList(concatFile string, segments []TimelineSegmentPath) error {
 	}
 	defer f.Close()
 
-	for _, seg := range segments {
+	for i, seg := range segments {
 		abs, err := filepath.Abs(seg.Path)
 		if err != nil {
 			abs = seg.Path
@@ -227,6 +246,10 @@ func WriteConcatList(concatFile string, segments []TimelineSegmentPath) error {
 		// Escape single quotes in paths for the concat file format.
 		escaped := strings.ReplaceAll(abs, "'", "'\\''")
 		fmt.Fprintf(f, "file '%s'\n", escaped)
+		if i < len(planned) && planned[i] > 0 {
+			length := formatFloat(SnapToFrames(planned[i], fps))
+			fmt.Fprintf(f, "outpoint %s\nduration %s\n", length, length)
+		}
 	}
 	return nil
 }
@@ -234,7 +257,7 @@ func WriteConcatList(concatFile string, segments []TimelineSegmentPath) error {
 // ConcatResult holds the outcome of a concat run.
 type ConcatResult struct {
 	OutputPath string
-	Method     string // "single_copy", "stream_copy", or "re-encode"
+	Method     string // "single_copy", "stream_copy", "re-encode", or "grid"
 }
 
 // RunConcat concatenates segments using the ffmpeg concat demuxer. It tries
@@ -284,6 +307,36 @@ func RunConcat(ctx context.Context, concatFile, outputPath string, enc tools.Res
 	return ConcatResult{OutputPath: outputPath, Method: "re-encode"}, nil
 }
 
+// RunGridConcat assembles a list written by WriteGridConcatList. Stream copy
+// can only cut on keyframes and leaves gaps where a segment ran short, so it
+// always re-encodes at a constant fps: the fps filter repeats the last frame
+// and aresample fills silence into each gap.
+func RunGridConcat(ctx context.Context, concatFile, outputPath string, enc tools.ResolvedEncoding, fps int, stdout, stderr io.Writer) (ConcatResult, error) {
+	ffmpegPath, err := tools.Lookup("ffmpeg")
+	if err != nil {
+		return ConcatResult{}, fmt.Errorf("locate ffmpeg: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
+		return ConcatResult{}, fmt.Errorf("prepare output dir: %w", err)
+	}
+	if err := runFFmpeg(ctx, ffmpegPath, buildGridReencodeArgs(concatFile, outputPath, enc, fps), stdout, stderr); err != nil {
+		return ConcatResult{}, fmt.Errorf("concat re-encode failed: %w", err)
+	}
+	return ConcatResult{OutputPath: outputPath, Method: "grid"}, nil
+}
+
+func buildGridReencodeArgs(concatFile, outputPath string, enc tools.ResolvedEncoding, fps int) []string {
+	args := buildReencodeArgs(concatFile, outputPath, enc)
+	args = args[:len(args)-1]
+	args = append(args,
+		"-vf", fmt.Sprintf("fps=%d", fps),
+		"-fps_mode", "cfr",
+		"-af", "aresample=async=1:first_pts=0",
+		outputPath,
+	)
+	return args
+}
+
 func buildReencodeArgs(concatFile, outputPath string, enc tools.ResolvedEncoding) []string {
 	args := []string{
 		"-y",
@@ -292,9 +345,12 @@ func buildReencodeArgs(concatFile, outputPath string, enc tools.ResolvedEncoding
 		"-i", concatFile,
 		"-c:v", enc.VideoCodec,
 		"-b:v", enc.VideoBitrate,
+	}
+	args = append(args, enc.VideoArgs...)
+	args = append(args,
 		"-c:a", enc.AudioCodec,
 		"-b:a", enc.AudioBitrate,
-	}
+	)
 	if enc.SampleRate > 0 {
 		args = append(args, "-ar", fmt.Sprintf("%d", enc.SampleRate))
 	}
@@ -325,7 +381,10 @@ func InlineSegmentPath(segmentsDir string, seqIdx int, sourceFile string) string
 	return filepath.Join(segmentsDir, "__inline__", fmt.Sprintf("%03d-%s.mp4", seqIdx, sanitizeSegment(basename)))
 }
 
-func runFFmpeg(ctx context.Context, ffmpegPath string, args []string, stdout, stderr io.Writer) error {
+func runFFmpeg(ctx context.Context, ffmpegPath string, args []string, stdout, stderr io.Writer) (err error) {
+	ctx, span := telemetry.Start(ctx, "exec ffmpeg", attribute.String("process.executable.name", "ffmpeg"))
+	defer func() { span.End(err) }()
+
 	cmd := exec.CommandContext(ctx, ffmpegPath, args...)
 	if stdout != nil {
 		cmd.Stdout = stdout
@@ -346,7 +405,7 @@ func readConcatList(concatFile string) ([]string, error) {
 	segments := make([]string, 0, len(lines))
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
-		if line == "" {
+		if line == "" || strings.HasPrefix(line, "outpoint ") || strings.HasPrefix(line, "duration ") {
 			continue
 		}
 		if !strings.HasPrefix(line, "file '") || !strings.HasSuffix(line, "'") {
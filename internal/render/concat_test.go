@@ -2,8 +2,10 @@ package render
 
 import (
 	"context"
+	"math"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"powerhour/internal/tools"
@@ -75,3 +77,84 @@ func TestRunConcatSingleSegmentNoOpWhenOutputMatchesSource(t *testing.T) {
 		t.Fatalf("source bytes = %q, want %q", got, want)
 	}
 }
+
+func TestWriteGridConcatList(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	song := filepath.Join(dir, "song.mp4")
+	inline := filepath.Join(dir, "intro.mp4")
+	for _, p := range []string{song, inline} {
+		if err := os.WriteFile(p, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	concatFile := filepath.Join(dir, "concat.txt")
+	segments := []TimelineSegmentPath{{Path: inline}, {Path: song}, {Path: song}}
+
+	// 60.01s isn't a whole number of frames at 30fps; 60.0 is.
+	if err := WriteGridConcatList(concatFile, segments, []float64{0, 60, 60.01}, 30); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(concatFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "file '" + inline + "'\n" +
+		"file '" + song + "'\noutpoint 60\nduration 60\n" +
+		"file '" + song + "'\noutpoint 60\nduration 60\n"
+	if string(got) != want {
+		t.Fatalf("concat list =\n%s\nwant\n%s", got, want)
+	}
+
+	paths, err := readConcatList(concatFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 3 {
+		t.Fatalf("readConcatList returned %d paths, want 3", len(paths))
+	}
+
+	if err := WriteGridConcatList(concatFile, segments, []float64{60}, 30); err == nil {
+		t.Fatal("expected an error for mismatched planned lengths")
+	}
+	if err := WriteGridConcatList(concatFile, segments, []float64{0, 60, 60}, 0); err == nil {
+		t.Fatal("expected an error without a frame rate")
+	}
+}
+
+func TestSnapToFrames(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		seconds float64
+		fps     int
+		want    float64
+	}{
+		{60, 30, 60},
+		{60.02, 30, 60.033333333333333},
+		{59.99, 25, 60},
+		{12.5, 0, 12.5},
+	}
+	for _, tt := range tests {
+		if got := SnapToFrames(tt.seconds, tt.fps); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("SnapToFrames(%v, %d) = %v, want %v", tt.seconds, tt.fps, got, tt.want)
+		}
+	}
+}
+
+func TestBuildGridReencodeArgs(t *testing.T) {
+	t.Parallel()
+
+	enc := tools.ResolvedEncoding{VideoCodec: "libx264", VideoBitrate: "8M", AudioCodec: "aac", AudioBitrate: "192k"}
+	args := buildGridReencodeArgs("list.txt", "out.mp4", enc, 30)
+	joined := strings.Join(args, " ")
+	for _, want := range []string{"-vf fps=30", "-fps_mode cfr", "-af aresample=async=1:first_pts=0", "-c:v libx264"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("args missing %q: %s", want, joined)
+		}
+	}
+	if args[len(args)-1] != "out.mp4" {
+		t.Errorf("last arg = %q, want the output path", args[len(args)-1])
+	}
+}
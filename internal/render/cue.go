@@ -0,0 +1,72 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"powerhour/internal/config"
+	"powerhour/internal/project"
+)
+
+// audioCueInput is the input index of a clip's music: generated clips read
+// video from input 0 and silence from input 1.
+func audioCueInput(clip project.Clip) int {
+	if clip.SourceKind == project.SourceKindGenerator {
+		return 1
+	}
+	return 0
+}
+
+// AudioCueGraph builds the -filter_complex that mixes cue over the music
+// at delay seconds, ducking the music with a sidechain compressor keyed on
+// the cue. audioFilters (loudnorm, resample, padding) run on the music
+// first. The mixed audio is labelled [aout].
+func AudioCueGraph(cue config.AudioCueConfig, musicInput, cueInput int, audioFilters string, delay float64, cfg config.Config) string {
+	volume := cue.Volume
+	if volume == 0 {
+		volume = config.DefaultCueVolume
+	}
+	ratio := cue.Ratio
+	if ratio == 0 {
+		ratio = config.DefaultCueRatio
+	}
+	threshold := cue.Threshold
+	if threshold == 0 {
+		threshold = config.DefaultCueThreshold
+	}
+	attack := cue.Attack
+	if attack == 0 {
+		attack = config.DefaultCueAttack
+	}
+	release := cue.Release
+	if release == 0 {
+		release = config.DefaultCueRelease
+	}
+
+	music := strings.TrimSpace(audioFilters)
+	if music == "" {
+		music = "anull"
+	}
+
+	layout := "stereo"
+	if cfg.Audio.Channels == 1 {
+		layout = "mono"
+	}
+	format := "aformat=channel_layouts=" + layout
+	if cfg.Audio.SampleRate > 0 {
+		format = fmt.Sprintf("aformat=sample_rates=%d:channel_layouts=%s", cfg.Audio.SampleRate, layout)
+	}
+	cueChain := []string{format, "volume=" + formatFloat(volume)}
+	if ms := int(delay*1000 + 0.5); ms > 0 {
+		cueChain = append(cueChain, fmt.Sprintf("adelay=delays=%d:all=1", ms))
+	}
+	cueChain = append(cueChain, "asplit=2[cue][key]")
+
+	return strings.Join([]string{
+		fmt.Sprintf("[%d:a]%s[music]", musicInput, music),
+		fmt.Sprintf("[%d:a]%s", cueInput, strings.Join(cueChain, ",")),
+		fmt.Sprintf("[music][key]sidechaincompress=threshold=%s:ratio=%s:attack=%s:release=%s[ducked]",
+			formatFloat(threshold), formatFloat(ratio), formatFloat(attack), formatFloat(release)),
+		"[ducked][cue]amix=inputs=2:duration=first:dropout_transition=0:normalize=0[aout]",
+	}, ";")
+}
@@ -0,0 +1,70 @@
+package render
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"powerhour/internal/config"
+	"powerhour/pkg/csvplan"
+)
+
+func TestAudioCueGraphDefaults(t *testing.T) {
+	cfg := config.Default()
+	graph := AudioCueGraph(config.AudioCueConfig{File: "horn.wav"}, 0, 1, "aresample=48000", 0.5, cfg)
+
+	want := strings.Join([]string{
+		"[0:a]aresample=48000[music]",
+		"[1:a]aformat=sample_rates=48000:channel_layouts=stereo,volume=1,adelay=delays=500:all=1,asplit=2[cue][key]",
+		"[music][key]sidechaincompress=threshold=0.03:ratio=8:attack=20:release=400[ducked]",
+		"[ducked][cue]amix=inputs=2:duration=first:dropout_transition=0:normalize=0[aout]",
+	}, ";")
+	if graph != want {
+		t.Errorf("AudioCueGraph =\n%s\nwant\n%s", graph, want)
+	}
+}
+
+func TestBuildFFmpegCmdAudioCue(t *testing.T) {
+	cfg := config.Default()
+	tests := []struct {
+		name  string
+		seg   Segment
+		music string
+		cue   string
+	}{
+		{"plan clip", newTestSegment(cfg, csvplan.Row{Index: 1, DurationSeconds: 60}), "[0:a]", "[1:a]"},
+		{"generated slate", newSlateSegment(map[string]string{"title": "Power Hour"}), "[1:a]", "[2:a]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seg := tt.seg
+			seg.Clip.PrerollSeconds = 1
+			seg.Clip.AudioCue = &config.AudioCueConfig{File: "/tmp/horn.wav", Offset: 2, Volume: 1.5}
+
+			cmd, err := BuildFFmpegCmd(seg, "/tmp/out.mp4", "null", "aresample=48000", cfg)
+			if err != nil {
+				t.Fatalf("BuildFFmpegCmd error: %v", err)
+			}
+			if slices.Contains(cmd, "-af") {
+				t.Errorf("cue render should use -filter_complex, not -af: %v", cmd)
+			}
+			i := slices.Index(cmd, "-filter_complex")
+			if i < 0 {
+				t.Fatalf("missing -filter_complex: %v", cmd)
+			}
+			graph := cmd[i+1]
+			for _, want := range []string{tt.music + "aresample=48000", tt.cue + "aformat", "volume=1.5", "adelay=delays=3000:all=1"} {
+				if !strings.Contains(graph, want) {
+					t.Errorf("graph missing %q:\n%s", want, graph)
+				}
+			}
+			if cue := slices.Index(cmd, "/tmp/horn.wav"); cue < 0 || cmd[cue-1] != "-i" || cue > slices.Index(cmd, "-t") {
+				t.Errorf("cue should be the last input, before -t: %v", cmd)
+			}
+			joined := strings.Join(cmd, " ")
+			if !strings.Contains(joined, "-map 0:v -map [aout]") {
+				t.Errorf("missing stream maps: %s", joined)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package render
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/config"
+)
+
+// downmixLayouts lists the channels of the surround layouts a downmix
+// knows, by the ffmpeg layout name ffprobe reports.
+var downmixLayouts = map[string][]string{
+	"3.0":        {"FL", "FR", "FC"},
+	"3.1":        {"FL", "FR", "FC", "LFE"},
+	"quad":       {"FL", "FR", "BL", "BR"},
+	"quad(side)": {"FL", "FR", "SL", "SR"},
+	"4.0":        {"FL", "FR", "FC", "BC"},
+	"4.1":        {"FL", "FR", "FC", "LFE", "BC"},
+	"5.0":        {"FL", "FR", "FC", "BL", "BR"},
+	"5.0(side)":  {"FL", "FR", "FC", "SL", "SR"},
+	"5.1":        {"FL", "FR", "FC", "LFE", "BL", "BR"},
+	"5.1(side)":  {"FL", "FR", "FC", "LFE", "SL", "SR"},
+	"6.1":        {"FL", "FR", "FC", "LFE", "BC", "SL", "SR"},
+	"7.1":        {"FL", "FR", "FC", "LFE", "BL", "BR", "SL", "SR"},
+}
+
+// defaultLayouts guesses the layout of untagged streams by channel count,
+// as ffmpeg does.
+var defaultLayouts = map[int]string{3: "3.0", 4: "quad", 5: "5.0", 6: "5.1", 8: "7.1"}
+
+// ResolveDownmix returns the pan filter that folds a cached source's
+// surround audio down to stereo, from the channel layout in its probe, or
+// "" when the source is stereo or mono, isn't probed, or has a layout
+// without a known channel order (-ac then downmixes it).
+func ResolveDownmix(cfg config.Config, entry cache.Entry) string {
+	if entry.Probe == nil {
+		return ""
+	}
+	channels, layout := entry.Probe.AudioLayout()
+	return DownmixFilter(channels, layout, cfg.Audio.Downmix)
+}
+
+// DownmixFilter returns the stereo downmix of a stream with the given
+// channel count and layout. Each side gets its front channel, the center
+// and LFE at their levels, and the surrounds on its side (back center on
+// both); the "<" form of pan scales the gains to sum to one, so the fold
+// doesn't clip and loudnorm brings the level back up.
+func DownmixFilter(channels int, layout string, levels *config.DownmixConfig) string {
+	if channels <= 2 {
+		return ""
+	}
+	layout = strings.TrimSpace(layout)
+	if layout == "" {
+		layout = defaultLayouts[channels]
+	}
+	names, ok := downmixLayouts[layout]
+	if !ok || len(names) != channels {
+		return ""
+	}
+
+	side := func(front string, surrounds ...string) string {
+		terms := []string{front}
+		add := func(level float64, channel string) {
+			if level > 0 && slices.Contains(names, channel) {
+				terms = append(terms, fmt.Sprintf("%s*%s", formatFloat(level), channel))
+			}
+		}
+		add(levels.CenterValue(), "FC")
+		add(levels.LFEValue(), "LFE")
+		for _, ch := range surrounds {
+			add(levels.SurroundValue(), ch)
+		}
+		return front + "<" + strings.Join(terms, "+")
+	}
+	return fmt.Sprintf("pan=stereo|%s|%s", side("FL", "SL", "BL", "BC"), side("FR", "SR", "BR", "BC"))
+}
@@ -0,0 +1,65 @@
+package render
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/config"
+	"powerhour/internal/project"
+	"powerhour/pkg/csvplan"
+)
+
+func TestDownmixFilter(t *testing.T) {
+	one := 1.0
+	half := 0.5
+	tests := []struct {
+		name     string
+		channels int
+		layout   string
+		levels   *config.DownmixConfig
+		want     string
+	}{
+		{"stereo", 2, "stereo", nil, ""},
+		{"mono", 1, "mono", nil, ""},
+		{"5.1 defaults", 6, "5.1", nil,
+			"pan=stereo|FL<FL+0.707*FC+0.707*BL|FR<FR+0.707*FC+0.707*BR"},
+		{"5.1 side untagged", 6, "", nil,
+			"pan=stereo|FL<FL+0.707*FC+0.707*BL|FR<FR+0.707*FC+0.707*BR"},
+		{"5.1(side) louder center with lfe", 6, "5.1(side)", &config.DownmixConfig{Center: &one, LFE: &half},
+			"pan=stereo|FL<FL+1*FC+0.5*LFE+0.707*SL|FR<FR+1*FC+0.5*LFE+0.707*SR"},
+		{"6.1 back center on both sides", 7, "6.1", nil,
+			"pan=stereo|FL<FL+0.707*FC+0.707*SL+0.707*BC|FR<FR+0.707*FC+0.707*SR+0.707*BC"},
+		{"unknown layout", 6, "hexagonal", nil, ""},
+		{"count mismatch", 8, "5.1", nil, ""},
+	}
+	for _, tt := range tests {
+		if got := DownmixFilter(tt.channels, tt.layout, tt.levels); got != tt.want {
+			t.Errorf("%s: DownmixFilter =\n  %s\nwant\n  %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBuildFFmpegCmdDownmixesFirst(t *testing.T) {
+	cfg := config.Default()
+	entry := cache.Entry{Probe: &cache.ProbeMetadata{Streams: json.RawMessage(`[{"codec_type":"audio","channels":6,"channel_layout":"5.1"}]`)}}
+	seg := newTestSegment(cfg, csvplan.Row{Index: 1, DurationSeconds: 60, CustomFields: map[string]string{project.GainField: "-3"}})
+	seg.Downmix = ResolveDownmix(cfg, entry)
+	if seg.Downmix == "" {
+		t.Fatal("expected a downmix for a 5.1 source")
+	}
+
+	args, err := BuildFFmpegCmd(seg, "out.mp4", "scale=1920:1080", BuildAudioFilters(cfg), cfg)
+	if err != nil {
+		t.Fatalf("BuildFFmpegCmd error: %v", err)
+	}
+	joined := strings.Join(args, " ")
+	if !strings.Contains(joined, "-af "+seg.Downmix+",volume=") {
+		t.Errorf("expected downmix ahead of gain, got %s", joined)
+	}
+
+	if SegmentInputHash(seg, "") == SegmentInputHash(Segment{Clip: seg.Clip}, "") {
+		t.Error("a downmix should change the input hash")
+	}
+}
@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"math"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
 	"time"
 
+	"powerhour/internal/cache"
 	"powerhour/internal/config"
 	"powerhour/internal/project"
 	"powerhour/pkg/csvplan"
@@ -31,17 +33,53 @@ func BuildFilterGraph(seg Segment, cfg config.Config) (string, error) {
 		return "", fmt.Errorf("clip %s#%d missing duration", clip.ClipType, clip.TypeIndex)
 	}
 
-	filters := []string{
+	freeze, err := FreezeOutroSeconds(clip.Row)
+	if err != nil {
+		return "", fmt.Errorf("clip %s#%d: %w", clip.ClipType, clip.TypeIndex, err)
+	}
+	if freeze >= clipDuration {
+		return "", fmt.Errorf("clip %s#%d: freeze %ss must be shorter than the %ss clip", clip.ClipType, clip.TypeIndex, formatFloat(freeze), formatFloat(clipDuration))
+	}
+	if err := checkShortSource(seg); err != nil {
+		return "", err
+	}
+
+	var filters []string
+	if color := strings.TrimSpace(seg.Color); color != "" {
+		filters = append(filters, color)
+	}
+	if freeze > 0 {
+		// Stop the source early; tpad below holds its last frame so the
+		// segment keeps its full length while audio plays on.
+		filters = append(filters, fmt.Sprintf("trim=duration=%s", formatFloat(clipDuration-freeze)))
+	}
+	if crop := strings.TrimSpace(seg.Crop); crop != "" {
+		filters = append(filters, crop)
+	}
+	filters = append(filters,
 		fmt.Sprintf("scale=w=%d:h=%d:force_original_aspect_ratio=1:flags=lanczos", width, height),
 		fmt.Sprintf("pad=w=%d:h=%d:x=(ow-iw)/2:y=(oh-ih)/2:color=black", width, height),
 		"setsar=1",
 		fmt.Sprintf("fps=%d", cfg.Video.FPS),
+	)
+	if freeze > 0 {
+		filters = append(filters, fmt.Sprintf("tpad=stop_mode=clone:stop_duration=%s", formatFloat(freeze)))
+	}
+	// Fill out a source that ends early before the fades, so a fade-out
+	// lands on the held frame.
+	if padShort, _ := ShortSourceFilters(clip); padShort != "" {
+		filters = append(filters, padShort)
+	}
+	if clip.SourceKind == project.SourceKindGenerator {
+		filters = append(filters, GeneratorFilters(clip.Row, clipDuration, width, height)...)
 	}
 
-	if fadeIn := math.Min(clipDuration, clip.FadeInSeconds); fadeIn > 0 {
+	// Like the audio fades, each is held to half the clip so the two never
+	// overlap.
+	if fadeIn := math.Min(clipDuration/2, clip.FadeInSeconds); fadeIn > 0 {
 		filters = append(filters, fmt.Sprintf("fade=t=in:st=0:d=%s", formatFloat(fadeIn)))
 	}
-	if fadeOut := math.Min(clipDuration, clip.FadeOutSeconds); fadeOut > 0 {
+	if fadeOut := math.Min(clipDuration/2, clip.FadeOutSeconds); fadeOut > 0 {
 		start := math.Max(clipDuration-fadeOut, 0)
 		filters = append(filters, fmt.Sprintf("fade=t=out:st=%s:d=%s", formatFloat(start), formatFloat(fadeOut)))
 	}
@@ -52,6 +90,159 @@ func BuildFilterGraph(seg Segment, cfg config.Config) (string, error) {
 	return strings.Join(filters, ","), nil
 }
 
+// ResolveCrop returns the crop filter to apply to a row's source: the
+// cropdetect suggestion from its cache entry when video.auto_crop is on and
+// the row hasn't opted out with a "crop" column of off/no/none/false.
+func ResolveCrop(cfg config.Config, row csvplan.Row, entry cache.Entry) string {
+	if !cfg.Video.AutoCrop || entry.Probe == nil || entry.Probe.Crop == nil {
+		return ""
+	}
+	switch strings.ToLower(strings.TrimSpace(row.CustomFields["crop"])) {
+	case "off", "no", "none", "false", "0":
+		return ""
+	}
+	return entry.Probe.Crop.Filter()
+}
+
+// FreezeField is the plan column holding a clip's freeze-frame outro length.
+const FreezeField = "freeze"
+
+// FreezeOutroSeconds returns how long the final frame is held at the end of a
+// row's clip, from its "freeze" column ("3", "2.5", "4s"). Empty means none.
+func FreezeOutroSeconds(row csvplan.Row) (float64, error) {
+	raw := strings.ToLower(strings.TrimSpace(row.CustomFields[FreezeField]))
+	if raw == "" {
+		return 0, nil
+	}
+	value, err := strconv.ParseFloat(strings.TrimSuffix(raw, "s"), 64)
+	if err != nil || value < 0 || math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("invalid freeze %q: expected seconds", row.CustomFields[FreezeField])
+	}
+	return value, nil
+}
+
+// ShortSourceFilters returns the video and audio filters that fill out a
+// source ending before its clip, following the clip's pad_short: the last
+// frame held (freeze) or black, over silence. Sources that run long enough
+// are unaffected. Both are empty for other modes and looped clips.
+func ShortSourceFilters(clip project.Clip) (string, string) {
+	if clip.Loop || clip.SourceKind == project.SourceKindGenerator || clip.DurationSeconds <= 0 {
+		return "", ""
+	}
+	duration := formatFloat(float64(clip.DurationSeconds))
+	var video string
+	switch clip.PadShort {
+	case config.PadShortFreeze:
+		video = "tpad=stop_mode=clone:stop_duration=" + duration
+	case config.PadShortBlack:
+		video = "tpad=stop_mode=add:stop_duration=" + duration + ":color=black"
+	default:
+		return "", ""
+	}
+	return video + ",trim=duration=" + duration, "apad=whole_dur=" + duration
+}
+
+// checkShortSource fails a pad_short: error clip whose probed source ends
+// before the clip does. Sources without a probed length pass.
+func checkShortSource(seg Segment) error {
+	clip := seg.Clip
+	if clip.PadShort != config.PadShortError || clip.Loop || seg.Entry.Probe == nil || seg.Entry.Probe.DurationSeconds <= 0 {
+		return nil
+	}
+	available := seg.Entry.Probe.DurationSeconds
+	if clip.SourceKind == project.SourceKindPlan {
+		available -= clip.Row.Start.Seconds()
+	}
+	// Allow for container durations that round a frame short.
+	if available+0.1 < float64(clip.DurationSeconds) {
+		return fmt.Errorf("clip %s#%d: source has %ss after the start, shorter than the %ds clip (pad_short: error)", clip.ClipType, clip.TypeIndex, formatFloat(math.Round(math.Max(available, 0)*10)/10), clip.DurationSeconds)
+	}
+	return nil
+}
+
+// GainFilter returns the volume filter for a row's gain_db column, or ""
+// when it has none.
+func GainFilter(row csvplan.Row) (string, error) {
+	gain, err := project.ParseGainDB(row.CustomFields[project.GainField])
+	if err != nil || gain == 0 {
+		return "", err
+	}
+	return "volume=" + formatFloat(gain) + "dB", nil
+}
+
+// AudioFadeFilters returns the afade filters of a clip's audio fades (its
+// video fades unless set apart), shaped by its fade curve. Each fade is
+// held to half the clip so the two never overlap. Empty when the audio
+// doesn't fade.
+func AudioFadeFilters(clip project.Clip) string {
+	curve := audioFadeCurve(clip)
+	if curve == "" {
+		return ""
+	}
+	clipDuration := float64(clip.DurationSeconds)
+	fadeIn, fadeOut := clip.AudioFades()
+	var filters []string
+	if fadeIn := math.Min(clipDuration/2, fadeIn); fadeIn > 0 {
+		filters = append(filters, fmt.Sprintf("afade=t=in:st=0:d=%s:curve=%s", formatFloat(fadeIn), curve))
+	}
+	if fadeOut := math.Min(clipDuration/2, fadeOut); fadeOut > 0 {
+		start := clipDuration - fadeOut
+		filters = append(filters, fmt.Sprintf("afade=t=out:st=%s:d=%s:curve=%s", formatFloat(start), formatFloat(fadeOut), curve))
+	}
+	return strings.Join(filters, ",")
+}
+
+// audioFadeCurve returns the afade curve of a clip whose audio fades,
+// defaulting to linear, or "" when it has no audio fades.
+func audioFadeCurve(clip project.Clip) string {
+	fadeIn, fadeOut := clip.AudioFades()
+	if clip.DurationSeconds <= 0 || (fadeIn <= 0 && fadeOut <= 0) {
+		return ""
+	}
+	if curve := strings.ToLower(strings.TrimSpace(clip.FadeCurve)); curve != "" {
+		return curve
+	}
+	return config.FadeCurveTri
+}
+
+// PaddingFilters returns the video and audio filters that add a clip's
+// preroll/postroll. Video is padded with black, or with the first/last frame
+// in freeze mode; audio with silence. Both are empty when there's no padding.
+func PaddingFilters(clip project.Clip) (string, string) {
+	pre, post := math.Max(clip.PrerollSeconds, 0), math.Max(clip.PostrollSeconds, 0)
+	if pre == 0 && post == 0 {
+		return "", ""
+	}
+	mode := "add"
+	if strings.EqualFold(strings.TrimSpace(clip.PadMode), config.PadModeFreeze) {
+		mode = "clone"
+	}
+
+	var video, audio []string
+	if pre > 0 {
+		video = append(video, fmt.Sprintf("start_mode=%s:start_duration=%s", mode, formatFloat(pre)))
+		audio = append(audio, fmt.Sprintf("adelay=delays=%d:all=1", int(math.Round(pre*1000))))
+	}
+	if post > 0 {
+		video = append(video, fmt.Sprintf("stop_mode=%s:stop_duration=%s", mode, formatFloat(post)))
+		audio = append(audio, fmt.Sprintf("apad=pad_dur=%s", formatFloat(post)))
+	}
+	if mode == "add" {
+		video = append(video, "color=black")
+	}
+	return "tpad=" + strings.Join(video, ":"), strings.Join(audio, ",")
+}
+
+func joinFilters(chain, extra string) string {
+	switch {
+	case strings.TrimSpace(extra) == "":
+		return chain
+	case strings.TrimSpace(chain) == "":
+		return extra
+	}
+	return chain + "," + extra
+}
+
 // BuildAudioFilters builds the ffmpeg audio filter chain.
 func BuildAudioFilters(cfg config.Config) string {
 	filters := []string{}
@@ -75,12 +266,9 @@ func BuildAudioFilters(cfg config.Config) string {
 
 // BuildFFmpegCmd assembles the ffmpeg CLI arguments for the segment render.
 func BuildFFmpegCmd(seg Segment, outputPath, videoFilters, audioFilters string, cfg config.Config) ([]string, error) {
-	sourcePath := strings.TrimSpace(seg.SourcePath)
-	if sourcePath == "" {
-		sourcePath = strings.TrimSpace(seg.CachedPath)
-	}
-	if sourcePath == "" {
-		return nil, errors.New("source path is empty")
+	inputArgs, err := sourceInputArgs(seg, cfg)
+	if err != nil {
+		return nil, err
 	}
 	if strings.TrimSpace(outputPath) == "" {
 		return nil, errors.New("output path is empty")
@@ -95,23 +283,53 @@ func BuildFFmpegCmd(seg Segment, outputPath, videoFilters, audioFilters string,
 		return nil, fmt.Errorf("clip %s#%d missing duration", clip.ClipType, clip.TypeIndex)
 	}
 
+	// Gain runs first so loudnorm and any cue ducking see the adjusted level;
+	// only a surround downmix goes ahead of it.
+	gain, err := GainFilter(clip.Row)
+	if err != nil {
+		return nil, fmt.Errorf("clip %s#%d: %w", clip.ClipType, clip.TypeIndex, err)
+	}
+	audioFilters = joinFilters(joinFilters(strings.TrimSpace(seg.Downmix), gain), audioFilters)
+	_, padShort := ShortSourceFilters(clip)
+	audioFilters = joinFilters(audioFilters, padShort)
+	// Fades come after loudnorm, which would otherwise lift them back up.
+	audioFilters = joinFilters(audioFilters, AudioFadeFilters(clip))
+
 	args := []string{
 		"-hide_banner",
 		"-y",
 	}
-
-	if clip.SourceKind == project.SourceKindPlan {
-		args = append(args, "-ss", formatTimecode(clip.Row.Start))
-	}
-
-	args = append(args,
-		"-i", sourcePath,
-		"-t", strconv.Itoa(duration),
-		"-vf", videoFilters,
-	)
-
-	if strings.TrimSpace(audioFilters) != "" {
-		args = append(args, "-af", audioFilters)
+	threads := cfg.Render.Threads
+	if threads > 0 {
+		args = append(args, "-filter_threads", strconv.Itoa(threads), "-filter_complex_threads", strconv.Itoa(threads))
+	}
+
+	args = append(args, inputArgs...)
+
+	padVideo, padAudio := PaddingFilters(clip)
+	videoFilters = joinFilters(videoFilters, padVideo)
+	audioFilters = joinFilters(audioFilters, padAudio)
+
+	if cue := clip.AudioCue; cue != nil {
+		// The cue is read after the source inputs, so it follows the music.
+		music := audioCueInput(clip)
+		graph := AudioCueGraph(*cue, music, music+1, audioFilters, clip.PrerollSeconds+cue.Offset, cfg)
+		args = append(args,
+			"-i", cue.File,
+			"-t", formatFloat(seg.OutputSeconds()),
+			"-filter_complex", graph,
+			"-map", "0:v",
+			"-map", "[aout]",
+			"-vf", videoFilters,
+		)
+	} else {
+		args = append(args,
+			"-t", formatFloat(seg.OutputSeconds()),
+			"-vf", videoFilters,
+		)
+		if strings.TrimSpace(audioFilters) != "" {
+			args = append(args, "-af", audioFilters)
+		}
 	}
 
 	videoCodec := strings.TrimSpace(cfg.Video.Codec)
@@ -128,7 +346,12 @@ func BuildFFmpegCmd(seg Segment, outputPath, videoFilters, audioFilters string,
 		args = append(args, "-crf", strconv.Itoa(cfg.Video.CRF))
 	}
 
-	args = append(args, "-pix_fmt", "yuv420p")
+	if hdr := HDREncodeArgs(cfg); hdr != nil {
+		args = append(args, hdr...)
+	} else {
+		args = append(args, "-pix_fmt", OutputPixFmt(cfg))
+		args = append(args, colorTagArgs(cfg)...)
+	}
 
 	if acodec := strings.TrimSpace(cfg.Audio.ACodec); acodec != "" {
 		args = append(args, "-c:a", acodec)
@@ -143,6 +366,13 @@ func BuildFFmpegCmd(seg Segment, outputPath, videoFilters, audioFilters string,
 		args = append(args, "-ac", strconv.Itoa(cfg.Audio.Channels))
 	}
 
+	if cfg.Render.TagsEnabled() {
+		args = append(args, seg.Tags.Args()...)
+	}
+
+	if threads > 0 {
+		args = append(args, "-threads", strconv.Itoa(threads))
+	}
 	args = append(args,
 		"-movflags", "+faststart",
 		outputPath,
@@ -202,7 +432,7 @@ func buildDrawText(opts drawTextOptions) string {
 	if strings.TrimSpace(opts.FontFile) != "" {
 		values = append(values, fmt.Sprintf("fontfile='%s'", escapeFFmpegPath(opts.FontFile)))
 	} else if strings.TrimSpace(opts.Font) != "" {
-		values = append(values, fmt.Sprintf("font='%s'", opts.Font))
+		values = append(values, fmt.Sprintf("font='%s'", escapeQuotedFilterValue(opts.Font)))
 	}
 
 	if !opts.Persistent {
@@ -346,11 +576,32 @@ func escapeDrawText(value string) string {
 	return value
 }
 
+// escapeFFmpegPath escapes a file path for a single-quoted filter option
+// such as fontfile='...'.
 func escapeFFmpegPath(value string) string {
-	value = filepath.Clean(value)
+	return escapeFilterPath(filepath.Clean(value), runtime.GOOS)
+}
+
+// escapeFilterPath escapes path for a single-quoted filter option on goos.
+// Filter graphs are unescaped twice: the graph parser strips the quotes and
+// keeps what's inside as is, then the option parser treats ":" as the next
+// option and "\" as an escape. On Windows the separators become "/", which
+// Windows and ffmpeg both accept, so "C:\fonts\x.ttf" becomes
+// "C\:/fonts/x.ttf" and only the drive letter's colon needs escaping. A "'"
+// can't appear inside quotes: it closes them, adds an escaped quote for both
+// parsers and reopens them.
+func escapeFilterPath(path, goos string) string {
+	if goos == "windows" {
+		path = strings.ReplaceAll(path, `\`, "/")
+	}
+	return escapeQuotedFilterValue(path)
+}
+
+// escapeQuotedFilterValue escapes a value for a single-quoted filter option.
+func escapeQuotedFilterValue(value string) string {
 	value = strings.ReplaceAll(value, `\`, `\\`)
 	value = strings.ReplaceAll(value, ":", `\:`)
-	value = strings.ReplaceAll(value, "'", `\'`)
+	value = strings.ReplaceAll(value, "'", `'\\\''`)
 	return value
 }
 
@@ -1,11 +1,19 @@
 package render
 
 import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
+	"powerhour/internal/cache"
 	"powerhour/internal/config"
+	"powerhour/internal/project"
 	"powerhour/pkg/csvplan"
 )
 
@@ -98,7 +106,7 @@ func TestBuildFFmpegCmd(t *testing.T) {
 		{"-i", "/tmp/source.mp4"},
 		{"-t", "45"},
 		{"-vf", graph},
-		{"-af", "aresample=48000"},
+		{"-af", "aresample=48000,afade=t=in:st=0:d=0.5:curve=tri,afade=t=out:st=44.5:d=0.5:curve=tri"},
 		{"-c:v", "libx264"},
 		{"-preset", "medium"},
 		{"-crf", "20"},
@@ -152,3 +160,411 @@ func TestSafeFileSlug(t *testing.T) {
 		}
 	}
 }
+
+func TestResolveCrop(t *testing.T) {
+	crop := &cache.CropSuggestion{Width: 1920, Height: 800, X: 0, Y: 140, SourceWidth: 1920, SourceHeight: 1080}
+	entry := cache.Entry{Probe: &cache.ProbeMetadata{Crop: crop}}
+	on := config.Default()
+	on.Video.AutoCrop = true
+
+	tests := []struct {
+		name  string
+		cfg   config.Config
+		row   csvplan.Row
+		entry cache.Entry
+		want  string
+	}{
+		{"disabled", config.Default(), csvplan.Row{}, entry, ""},
+		{"enabled", on, csvplan.Row{}, entry, "crop=1920:800:0:140"},
+		{"row opt-out", on, csvplan.Row{CustomFields: map[string]string{"crop": "Off"}}, entry, ""},
+		{"no suggestion", on, csvplan.Row{}, cache.Entry{Probe: &cache.ProbeMetadata{}}, ""},
+		{"no probe", on, csvplan.Row{}, cache.Entry{}, ""},
+	}
+	for _, tt := range tests {
+		if got := ResolveCrop(tt.cfg, tt.row, tt.entry); got != tt.want {
+			t.Errorf("%s: ResolveCrop = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBuildFilterGraphCropsBeforeScale(t *testing.T) {
+	cfg := config.Default()
+	seg := newTestSegment(cfg, csvplan.Row{Index: 1, DurationSeconds: 60})
+	seg.Overlays = nil
+	seg.Crop = "crop=1920:800:0:140"
+
+	graph, err := BuildFilterGraph(seg, cfg)
+	if err != nil {
+		t.Fatalf("BuildFilterGraph error: %v", err)
+	}
+	if !strings.HasPrefix(graph, "crop=1920:800:0:140,scale=") {
+		t.Fatalf("expected crop ahead of scale, got %s", graph)
+	}
+}
+
+func TestBuildFilterGraphFreezeOutro(t *testing.T) {
+	cfg := config.Default()
+	seg := newTestSegment(cfg, csvplan.Row{Index: 1, DurationSeconds: 60, CustomFields: map[string]string{"freeze": "4s"}})
+	seg.Overlays = nil
+
+	graph, err := BuildFilterGraph(seg, cfg)
+	if err != nil {
+		t.Fatalf("BuildFilterGraph error: %v", err)
+	}
+	if !strings.HasPrefix(graph, "trim=duration=56,scale=") {
+		t.Fatalf("expected source trimmed ahead of scale, got %s", graph)
+	}
+	wantPad := fmt.Sprintf("fps=%d,tpad=stop_mode=clone:stop_duration=4", cfg.Video.FPS)
+	if !strings.Contains(graph, wantPad) {
+		t.Fatalf("expected %q in %s", wantPad, graph)
+	}
+
+	seg.Clip.Row.CustomFields["freeze"] = "60"
+	if _, err := BuildFilterGraph(seg, cfg); err == nil {
+		t.Fatal("expected error when freeze covers the whole clip")
+	}
+}
+
+func TestBuildFilterGraphPadShort(t *testing.T) {
+	cfg := config.Default()
+	seg := newTestSegment(cfg, csvplan.Row{Index: 1, DurationSeconds: 60, Start: 10 * time.Second})
+	seg.Overlays = nil
+
+	tests := []struct {
+		mode      string
+		wantVideo string
+		wantAudio string
+	}{
+		{config.PadShortFreeze, "tpad=stop_mode=clone:stop_duration=60,trim=duration=60,fade=t=in", "apad=whole_dur=60"},
+		{config.PadShortBlack, "tpad=stop_mode=add:stop_duration=60:color=black,trim=duration=60,fade=t=in", "apad=whole_dur=60"},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		seg.Clip.PadShort = tt.mode
+		graph, err := BuildFilterGraph(seg, cfg)
+		if err != nil {
+			t.Fatalf("%q: BuildFilterGraph error: %v", tt.mode, err)
+		}
+		if tt.wantVideo != "" && !strings.Contains(graph, tt.wantVideo) || tt.wantVideo == "" && strings.Contains(graph, "trim=") {
+			t.Errorf("%q: graph = %s", tt.mode, graph)
+		}
+		cmd, err := BuildFFmpegCmd(seg, "/tmp/out.mp4", graph, "loudnorm", cfg)
+		if err != nil {
+			t.Fatalf("%q: BuildFFmpegCmd error: %v", tt.mode, err)
+		}
+		if got := strings.Join(cmd, " "); tt.wantAudio != "" && !strings.Contains(got, "-af loudnorm,"+tt.wantAudio) || tt.wantAudio == "" && strings.Contains(got, "apad") {
+			t.Errorf("%q: args = %s", tt.mode, got)
+		}
+	}
+
+	seg.Clip.PadShort = config.PadShortError
+	seg.Entry.Probe = &cache.ProbeMetadata{DurationSeconds: 45.04}
+	if _, err := BuildFilterGraph(seg, cfg); err == nil || !strings.Contains(err.Error(), "35s after the start") {
+		t.Errorf("short source with pad_short: error: err = %v", err)
+	}
+	seg.Entry.Probe.DurationSeconds = 70
+	if _, err := BuildFilterGraph(seg, cfg); err != nil {
+		t.Errorf("long enough source: %v", err)
+	}
+}
+
+func TestFreezeOutroSeconds(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    float64
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"3", 3, false},
+		{" 2.5s ", 2.5, false},
+		{"-1", 0, true},
+		{"soon", 0, true},
+	}
+	for _, tc := range tests {
+		got, err := FreezeOutroSeconds(csvplan.Row{CustomFields: map[string]string{"freeze": tc.value}})
+		if (err != nil) != tc.wantErr || got != tc.want {
+			t.Errorf("FreezeOutroSeconds(%q) = %v, %v; want %v (err=%v)", tc.value, got, err, tc.want, tc.wantErr)
+		}
+	}
+}
+
+func TestPaddingFilters(t *testing.T) {
+	tests := []struct {
+		name      string
+		clip      project.Clip
+		wantVideo string
+		wantAudio string
+	}{
+		{"none", project.Clip{PadMode: "freeze"}, "", ""},
+		{"black both", project.Clip{PrerollSeconds: 0.5, PostrollSeconds: 0.25},
+			"tpad=start_mode=add:start_duration=0.5:stop_mode=add:stop_duration=0.25:color=black",
+			"adelay=delays=500:all=1,apad=pad_dur=0.25"},
+		{"freeze preroll", project.Clip{PrerollSeconds: 1, PadMode: "Freeze"},
+			"tpad=start_mode=clone:start_duration=1", "adelay=delays=1000:all=1"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			video, audio := PaddingFilters(tc.clip)
+			if video != tc.wantVideo || audio != tc.wantAudio {
+				t.Fatalf("PaddingFilters = %q, %q; want %q, %q", video, audio, tc.wantVideo, tc.wantAudio)
+			}
+		})
+	}
+}
+
+func TestBuildFFmpegCmdAppliesPadding(t *testing.T) {
+	cfg := config.Default()
+	seg := newTestSegment(cfg, csvplan.Row{Index: 1, DurationSeconds: 60})
+	seg.Clip.PrerollSeconds = 0.5
+	seg.Clip.PostrollSeconds = 1
+
+	cmd, err := BuildFFmpegCmd(seg, "/tmp/out.mp4", "fps=30", "", cfg)
+	if err != nil {
+		t.Fatalf("BuildFFmpegCmd error: %v", err)
+	}
+	args := strings.Join(cmd, " ")
+	for _, want := range []string{
+		"-t 61.5",
+		"-vf fps=30,tpad=start_mode=add:start_duration=0.5:stop_mode=add:stop_duration=1:color=black",
+		"-af afade=t=in:st=0:d=0.5:curve=tri,afade=t=out:st=59.5:d=0.5:curve=tri,adelay=delays=500:all=1,apad=pad_dur=1",
+	} {
+		if !strings.Contains(args, want) {
+			t.Errorf("expected %q in %s", want, args)
+		}
+	}
+}
+
+func TestBuildFFmpegCmdCapsThreads(t *testing.T) {
+	cfg := config.Default()
+	seg := newTestSegment(cfg, csvplan.Row{Index: 1, DurationSeconds: 60})
+
+	cmd, err := BuildFFmpegCmd(seg, "/tmp/out.mp4", "fps=30", "", cfg)
+	if err != nil {
+		t.Fatalf("BuildFFmpegCmd error: %v", err)
+	}
+	if args := strings.Join(cmd, " "); strings.Contains(args, "threads") {
+		t.Fatalf("no thread options expected by default: %s", args)
+	}
+
+	cfg.Render.Threads = 2
+	cmd, err = BuildFFmpegCmd(seg, "/tmp/out.mp4", "fps=30", "", cfg)
+	if err != nil {
+		t.Fatalf("BuildFFmpegCmd error: %v", err)
+	}
+	args := strings.Join(cmd, " ")
+	if !strings.HasPrefix(args, "-hide_banner -y -filter_threads 2 -filter_complex_threads 2 ") {
+		t.Errorf("filter thread caps should lead the global options: %s", args)
+	}
+	if !strings.HasSuffix(args, "-threads 2 -movflags +faststart /tmp/out.mp4") {
+		t.Errorf("encoder thread cap should precede the output: %s", args)
+	}
+}
+
+func TestBuildFFmpegCmdAppliesGain(t *testing.T) {
+	cfg := config.Default()
+	row := csvplan.Row{Index: 1, DurationSeconds: 30, CustomFields: map[string]string{"gain_db": "-6dB"}}
+	seg := newTestSegment(cfg, row)
+
+	cmd, err := BuildFFmpegCmd(seg, "/tmp/out.mp4", "null", "loudnorm=I=-14", cfg)
+	if err != nil {
+		t.Fatalf("BuildFFmpegCmd error: %v", err)
+	}
+	i := slices.Index(cmd, "-af")
+	if i < 0 || !strings.HasPrefix(cmd[i+1], "volume=-6dB,loudnorm=I=-14,") {
+		t.Errorf("-af should apply gain before loudnorm: %v", cmd)
+	}
+
+	seg.Clip.Row.CustomFields["gain_db"] = "loud"
+	if _, err := BuildFFmpegCmd(seg, "/tmp/out.mp4", "null", "", cfg); err == nil {
+		t.Error("expected error for invalid gain_db")
+	}
+}
+
+func TestBuildFFmpegCmdLoopsSource(t *testing.T) {
+	cfg := config.Default()
+	seg := newTestSegment(cfg, csvplan.Row{Index: 1, DurationSeconds: 60})
+	seg.Clip.Loop = true
+
+	cmd, err := BuildFFmpegCmd(seg, "/tmp/out.mp4", "null", "", cfg)
+	if err != nil {
+		t.Fatalf("BuildFFmpegCmd error: %v", err)
+	}
+	args := strings.Join(cmd, " ")
+	if !strings.Contains(args, "-stream_loop -1 -ss 0:00.000 -i /tmp/source.mp4") || !strings.Contains(args, "-t 60") {
+		t.Errorf("expected a looped input cut to 60s: %s", args)
+	}
+
+	seg.Clip.Loop = false
+	if cmd, _ := BuildFFmpegCmd(seg, "/tmp/out.mp4", "null", "", cfg); slices.Contains(cmd, "-stream_loop") {
+		t.Errorf("unlooped clip should not loop: %v", cmd)
+	}
+}
+
+func TestEscapeFilterPath(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		goos string
+		want string
+	}{
+		{"unix", "/usr/share/fonts/Oswald.ttf", "linux", "/usr/share/fonts/Oswald.ttf"},
+		{"unix colon", "/fonts/a:b.ttf", "darwin", `/fonts/a\:b.ttf`},
+		{"unix backslash in name", `/fonts/a\b.ttf`, "linux", `/fonts/a\\b.ttf`},
+		{"quote", "/fonts/it's.ttf", "linux", `/fonts/it'\\\''s.ttf`},
+		{"drive letter", `C:\Windows\Fonts\arial.ttf`, "windows", `C\:/Windows/Fonts/arial.ttf`},
+		{"unc share", `\\nas\fonts\Oswald Bold.ttf`, "windows", "//nas/fonts/Oswald Bold.ttf"},
+		{"windows quote", `D:\Bob's Fonts\x.otf`, "windows", `D\:/Bob'\\\''s Fonts/x.otf`},
+	}
+	for _, tt := range tests {
+		if got := escapeFilterPath(tt.path, tt.goos); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestDrawTextFontFileReachesFFmpeg runs the escaped filter through ffmpeg,
+// from a directory whose name needs every kind of escaping the platform
+// allows.
+func TestDrawTextFontFileReachesFFmpeg(t *testing.T) {
+	ffmpeg, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		t.Skip("ffmpeg not installed")
+	}
+	font := fontFilePath("sans")
+	if font == "" {
+		t.Skip("no font found with fc-match")
+	}
+	data, err := os.ReadFile(font)
+	if err != nil {
+		t.Skipf("read %s: %v", font, err)
+	}
+
+	dirName := "it's a: [font], dir"
+	if runtime.GOOS == "windows" {
+		dirName = "it's a [font], dir" // ":" isn't allowed in Windows names
+	}
+	dir := filepath.Join(t.TempDir(), dirName)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	fontFile := filepath.Join(dir, "font"+filepath.Ext(font))
+	if err := os.WriteFile(fontFile, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	filter := buildDrawText(drawTextOptions{Text: "Test", Start: 0, End: 1, FontSize: 24, FontFile: fontFile, Persistent: true})
+	cmd := exec.Command(ffmpeg, "-hide_banner", "-v", "error",
+		"-f", "lavfi", "-i", "color=c=black:s=160x90:d=0.1",
+		"-vf", filter, "-frames:v", "1", "-f", "null", "-")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("ffmpeg rejected %s: %v\n%s", filter, err, out)
+	}
+}
+
+func TestBuildFFmpegCmdWritesTags(t *testing.T) {
+	cfg := config.Default()
+	seg := newTestSegment(cfg, csvplan.Row{Index: 3, DurationSeconds: 60, Name: "Teenagers", Artist: "My Chemical Romance"})
+	seg.Tags = NewSegmentTags(seg.Clip, "party", 5)
+
+	cmd, err := BuildFFmpegCmd(seg, "/tmp/out.mp4", "fps=30", "", cfg)
+	if err != nil {
+		t.Fatalf("BuildFFmpegCmd error: %v", err)
+	}
+	args := strings.Join(cmd, " ")
+	want := "-metadata title=Teenagers -metadata artist=My Chemical Romance -metadata album=party -metadata track=5 -movflags"
+	if !strings.Contains(args, want) {
+		t.Errorf("expected tags before the output options:\n%s", args)
+	}
+
+	off := false
+	cfg.Render.Tags = &off
+	cmd, err = BuildFFmpegCmd(seg, "/tmp/out.mp4", "fps=30", "", cfg)
+	if err != nil {
+		t.Fatalf("BuildFFmpegCmd error: %v", err)
+	}
+	if slices.Contains(cmd, "-metadata") {
+		t.Errorf("render.tags: false should drop the tags: %v", cmd)
+	}
+}
+
+func TestBuildFFmpegCmdSampleSeconds(t *testing.T) {
+	cfg := config.Default()
+	seg := newTestSegment(cfg, csvplan.Row{Index: 1, DurationSeconds: 60})
+	full := SegmentInputHash(seg, "")
+	graph, err := BuildFilterGraph(seg, cfg)
+	if err != nil {
+		t.Fatalf("BuildFilterGraph error: %v", err)
+	}
+
+	seg.SampleSeconds = 10
+	cmd, err := BuildFFmpegCmd(seg, "/tmp/out.mp4", graph, "", cfg)
+	if err != nil {
+		t.Fatalf("BuildFFmpegCmd error: %v", err)
+	}
+	if i := slices.Index(cmd, "-t"); i < 0 || cmd[i+1] != "10" {
+		t.Fatalf("expected -t 10 for a 10s sample, got %v", cmd)
+	}
+	if SegmentInputHash(seg, "") == full {
+		t.Error("a sample should hash apart from the full segment")
+	}
+
+	seg.SampleSeconds = 90
+	if got := seg.OutputSeconds(); got != 60 {
+		t.Errorf("OutputSeconds with a longer sample = %v, want the 60s clip", got)
+	}
+}
+
+func TestAudioFadeFilters(t *testing.T) {
+	cfg := config.Default()
+	seg := newTestSegment(cfg, csvplan.Row{Index: 1, DurationSeconds: 60})
+	clip := seg.Clip
+	clip.FadeInSeconds, clip.FadeOutSeconds = 1, 2
+	clip.FadeCurve = "QSin"
+	if got, want := AudioFadeFilters(clip), "afade=t=in:st=0:d=1:curve=qsin,afade=t=out:st=58:d=2:curve=qsin"; got != want {
+		t.Errorf("AudioFadeFilters = %q, want %q", got, want)
+	}
+
+	clip.FadeInSeconds, clip.FadeOutSeconds = 0, 0
+	if got := AudioFadeFilters(clip); got != "" {
+		t.Errorf("no fades: AudioFadeFilters = %q, want none", got)
+	}
+	if _, ok := SegmentInputParts(Segment{Clip: clip}, "")[InputPartFades]; !ok {
+		t.Fatal("fades part should exist")
+	}
+	before := SegmentInputHash(Segment{Clip: clip}, "")
+	clip.FadeCurve = config.FadeCurveExp
+	if SegmentInputHash(Segment{Clip: clip}, "") != before {
+		t.Error("a curve without fades shouldn't change the input hash")
+	}
+	clip.FadeOutSeconds = 2
+	withExp := SegmentInputHash(Segment{Clip: clip}, "")
+	clip.FadeCurve = ""
+	if SegmentInputHash(Segment{Clip: clip}, "") == withExp {
+		t.Error("changing the curve of a fading clip should change the input hash")
+	}
+}
+
+func TestAudioFadeFiltersIndependentOfVideo(t *testing.T) {
+	cfg := config.Default()
+	seg := newTestSegment(cfg, csvplan.Row{Index: 1, DurationSeconds: 10})
+	clip := seg.Clip
+	clip.FadeInSeconds, clip.FadeOutSeconds = 0, 0
+	in, out := 1.0, 8.0
+	clip.AudioFadeIn, clip.AudioFadeOut = &in, &out
+	if got, want := AudioFadeFilters(clip), "afade=t=in:st=0:d=1:curve=tri,afade=t=out:st=5:d=5:curve=tri"; got != want {
+		t.Errorf("AudioFadeFilters = %q, want %q", got, want)
+	}
+
+	before := SegmentInputHash(Segment{Clip: clip}, "")
+	out = 2
+	if SegmentInputHash(Segment{Clip: clip}, "") == before {
+		t.Error("changing an audio fade should change the input hash")
+	}
+
+	zero := 0.0
+	clip.FadeInSeconds, clip.FadeOutSeconds = 1, 1
+	clip.AudioFadeIn, clip.AudioFadeOut = &zero, &zero
+	if got := AudioFadeFilters(clip); got != "" {
+		t.Errorf("zero audio fades: AudioFadeFilters = %q, want none", got)
+	}
+}
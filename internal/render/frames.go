@@ -0,0 +1,157 @@
+package render
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/config"
+)
+
+// FrameExportOptions controls an image-sequence export of a single clip.
+type FrameExportOptions struct {
+	// Every is the sampling interval in seconds.
+	Every float64
+	// OverlaysOnly renders the overlay layer on a transparent canvas and the
+	// untouched video as separate background frames instead of compositing.
+	OverlaysOnly bool
+	// OutputDir receives the numbered PNGs.
+	OutputDir string
+}
+
+// FrameExportPass is one ffmpeg invocation of a frame export.
+type FrameExportPass struct {
+	Layer   string   // "frame", "overlay" or "background"
+	Pattern string   // printf-style output path
+	Args    []string // ffmpeg arguments
+}
+
+// BuildFrameExportPasses assembles the ffmpeg invocations for a frame export.
+// Composited exports take a single pass; overlays-only exports render the
+// overlay layer from a transparent lavfi canvas plus a background pass over
+// the source, sampled at the same instants so files pair up by number.
+func BuildFrameExportPasses(seg Segment, cfg config.Config, opts FrameExportOptions) ([]FrameExportPass, error) {
+	if opts.Every <= 0 {
+		return nil, errors.New("frame interval must be positive")
+	}
+	if strings.TrimSpace(opts.OutputDir) == "" {
+		return nil, errors.New("output directory is empty")
+	}
+	inputArgs, err := sourceInputArgs(seg, cfg)
+	if err != nil {
+		return nil, err
+	}
+	clip := seg.Clip
+	clipDuration := float64(clip.DurationSeconds)
+	if clipDuration <= 0 {
+		return nil, fmt.Errorf("clip %s#%d missing duration", clip.ClipType, clip.TypeIndex)
+	}
+
+	sampler := fmt.Sprintf("fps=fps=1/%s", formatFloat(opts.Every))
+
+	sourceArgs := func(filters string, pattern string) []string {
+		args := append([]string{"-hide_banner", "-y"}, inputArgs...)
+		return append(args,
+			"-t", formatFloat(clipDuration),
+			"-vf", filters+","+sampler,
+			"-an",
+			pattern,
+		)
+	}
+
+	if !opts.OverlaysOnly {
+		graph, err := BuildFilterGraph(seg, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("build filter graph: %w", err)
+		}
+		pattern := filepath.Join(opts.OutputDir, "frame_%04d.png")
+		return []FrameExportPass{{Layer: "frame", Pattern: pattern, Args: sourceArgs(graph, pattern)}}, nil
+	}
+
+	// Background: the same scale/pad/fade chain as a render, minus overlays.
+	bare := seg
+	bare.Overlays = nil
+	background, err := BuildFilterGraph(bare, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("build filter graph: %w", err)
+	}
+	backgroundPattern := filepath.Join(opts.OutputDir, "background_%04d.png")
+
+	overlayPattern := filepath.Join(opts.OutputDir, "overlay_%04d.png")
+	canvas := fmt.Sprintf("color=c=black@0.0:s=%dx%d:r=%d:d=%s",
+		cfg.Video.Width, cfg.Video.Height, cfg.Video.FPS, formatFloat(clipDuration))
+	overlayFilters := append([]string{"format=rgba"}, ExpandOverlays(seg.Overlays, clip.Row, clipDuration)...)
+	overlayFilters = append(overlayFilters, sampler)
+	overlayArgs := []string{
+		"-hide_banner", "-y",
+		"-f", "lavfi",
+		"-i", canvas,
+		"-vf", strings.Join(overlayFilters, ","),
+		"-pix_fmt", "rgba",
+		overlayPattern,
+	}
+
+	return []FrameExportPass{
+		{Layer: "overlay", Pattern: overlayPattern, Args: overlayArgs},
+		{Layer: "background", Pattern: backgroundPattern, Args: sourceArgs(background, backgroundPattern)},
+	}, nil
+}
+
+// FrameCount is the number of images a clip yields at the given interval.
+func FrameCount(clipDuration, every float64) int {
+	if clipDuration <= 0 || every <= 0 {
+		return 0
+	}
+	return int(math.Ceil(clipDuration / every))
+}
+
+// RenderFrames writes a PNG image sequence for a single segment and returns
+// the passes it ran.
+func (s *Service) RenderFrames(ctx context.Context, seg Segment, opts FrameExportOptions) ([]FrameExportPass, error) {
+	if s == nil {
+		return nil, errors.New("render service is nil")
+	}
+	passes, err := BuildFrameExportPasses(seg, s.Config, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(opts.OutputDir, 0o755); err != nil {
+		return nil, fmt.Errorf("create frames directory: %w", err)
+	}
+
+	logPath := filepath.Join(opts.OutputDir, "frames.log")
+	logFile, err := os.Create(logPath)
+	if err != nil {
+		s.printf("warning: could not create log file: %v\n", err)
+		logFile = nil
+	}
+	if logFile != nil {
+		defer logFile.Close()
+	}
+
+	for _, pass := range passes {
+		s.printf("Exporting %s frames every %ss\n", pass.Layer, formatFloat(opts.Every))
+		runOpts := cache.RunOptions{Dir: s.Paths.Root}
+		if logFile != nil {
+			runOpts.Stderr = logFile
+			if s.stderr != nil {
+				runOpts.Stderr = io.MultiWriter(logFile, s.stderr)
+			}
+		} else if s.stderr != nil {
+			runOpts.Stderr = s.stderr
+		}
+		if _, err := s.Runner.Run(ctx, s.ffmpegPath, pass.Args, runOpts); err != nil {
+			if logFile != nil {
+				return nil, fmt.Errorf("ffmpeg %s pass failed: %w (see %s)", pass.Layer, err, logPath)
+			}
+			return nil, fmt.Errorf("ffmpeg %s pass failed: %w", pass.Layer, err)
+		}
+	}
+	return passes, nil
+}
@@ -0,0 +1,75 @@
+package render
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"powerhour/internal/config"
+	"powerhour/pkg/csvplan"
+)
+
+func TestBuildFrameExportPasses(t *testing.T) {
+	cfg := config.Default()
+	row := csvplan.Row{Index: 3, Title: "Song", Artist: "Band", Start: 30 * time.Second, DurationSeconds: 60}
+	seg := newTestSegment(cfg, row)
+
+	composite, err := BuildFrameExportPasses(seg, cfg, FrameExportOptions{Every: 1, OutputDir: "/tmp/frames"})
+	if err != nil {
+		t.Fatalf("composite: %v", err)
+	}
+	if len(composite) != 1 || composite[0].Layer != "frame" {
+		t.Fatalf("composite passes = %+v", composite)
+	}
+	args := strings.Join(composite[0].Args, " ")
+	for _, want := range []string{"-ss 0:30.000", "-i /tmp/source.mp4", "drawtext=", "fps=fps=1/1", "/tmp/frames/frame_%04d.png"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("composite args missing %q\nargs: %s", want, args)
+		}
+	}
+
+	layered, err := BuildFrameExportPasses(seg, cfg, FrameExportOptions{Every: 0.5, OverlaysOnly: true, OutputDir: "/tmp/frames"})
+	if err != nil {
+		t.Fatalf("overlays only: %v", err)
+	}
+	if len(layered) != 2 || layered[0].Layer != "overlay" || layered[1].Layer != "background" {
+		t.Fatalf("layered passes = %+v", layered)
+	}
+	overlay := strings.Join(layered[0].Args, " ")
+	for _, want := range []string{"-f lavfi", "color=c=black@0.0:s=1920x1080", "format=rgba,drawtext=", "fps=fps=1/0.5", "-pix_fmt rgba"} {
+		if !strings.Contains(overlay, want) {
+			t.Errorf("overlay args missing %q\nargs: %s", want, overlay)
+		}
+	}
+	if strings.Contains(overlay, "/tmp/source.mp4") {
+		t.Errorf("overlay pass should not read the source: %s", overlay)
+	}
+	background := strings.Join(layered[1].Args, " ")
+	if strings.Contains(background, "drawtext") {
+		t.Errorf("background pass should not draw overlays: %s", background)
+	}
+	if !strings.Contains(background, "background_%04d.png") {
+		t.Errorf("background pattern missing: %s", background)
+	}
+
+	if _, err := BuildFrameExportPasses(seg, cfg, FrameExportOptions{OutputDir: "/tmp/frames"}); err == nil {
+		t.Error("expected error for zero interval")
+	}
+}
+
+func TestFrameCount(t *testing.T) {
+	tests := []struct {
+		duration, every float64
+		want            int
+	}{
+		{60, 1, 60},
+		{60, 7, 9},
+		{0, 1, 0},
+		{10, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := FrameCount(tt.duration, tt.every); got != tt.want {
+			t.Errorf("FrameCount(%v, %v) = %d, want %d", tt.duration, tt.every, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+// Package golden renders synthetic test patterns through the segment video
+// filter graph and compares per-frame hashes against recorded goldens, so
+// overlay and filter changes can be checked without real media.
+//
+// Hashes depend on the ffmpeg build, the platform and the fonts overlays
+// resolve to, so each golden records that environment and is only compared
+// where it matches. Record goldens with:
+//
+//	go test ./internal/render/golden -update
+package golden
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"powerhour/internal/config"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+	"powerhour/pkg/csvplan"
+)
+
+// Pattern is an ffmpeg lavfi test source.
+type Pattern string
+
+const (
+	PatternBars    Pattern = "smptebars"
+	PatternTestsrc Pattern = "testsrc"
+)
+
+// Defaults keep golden renders small and quick.
+const (
+	DefaultSourceWidth  = 640
+	DefaultSourceHeight = 360
+	DefaultSeconds      = 2
+	outputWidth         = 320
+	outputHeight        = 180
+	outputFPS           = 10
+)
+
+// Case is one golden render: a synthetic source of Width x Height playing
+// for Seconds, rendered as a song clip through the config and segment that
+// Config and Segment adjust.
+type Case struct {
+	Name    string
+	Pattern Pattern
+	Width   int
+	Height  int
+	Seconds int
+
+	// Config adjusts the config, which starts from config.Default() at
+	// 320x180 and 10fps.
+	Config func(*config.Config)
+	// Segment adjusts the segment, which starts without fades or overlays.
+	Segment func(*render.Segment)
+}
+
+// Build returns the case's config and segment.
+func (c Case) Build() (config.Config, render.Segment) {
+	cfg := config.Default()
+	cfg.Video.Width, cfg.Video.Height, cfg.Video.FPS = outputWidth, outputHeight, outputFPS
+	if c.Config != nil {
+		c.Config(&cfg)
+	}
+
+	seconds := c.seconds()
+	row := csvplan.Row{Index: 1, Title: "Golden Song", Artist: "Test Pattern", DurationSeconds: seconds}
+	seg := render.Segment{
+		Clip: project.Clip{
+			Sequence:        1,
+			ClipType:        project.ClipTypeSong,
+			TypeIndex:       1,
+			Row:             row,
+			SourceKind:      project.SourceKindPlan,
+			DurationSeconds: seconds,
+		},
+	}
+	if c.Segment != nil {
+		c.Segment(&seg)
+	}
+	return cfg, seg
+}
+
+func (c Case) seconds() int {
+	if c.Seconds > 0 {
+		return c.Seconds
+	}
+	return DefaultSeconds
+}
+
+// Args returns the ffmpeg arguments that run the case's source through its
+// filter graph and print an MD5 of every output frame. Scaling is pinned to
+// bit-exact results so the hashes don't follow the CPU's SIMD paths.
+func (c Case) Args() ([]string, error) {
+	cfg, seg := c.Build()
+	graph, err := render.BuildFilterGraph(seg, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("golden %s: %w", c.Name, err)
+	}
+	width, height := c.Width, c.Height
+	if width <= 0 || height <= 0 {
+		width, height = DefaultSourceWidth, DefaultSourceHeight
+	}
+	pattern := c.Pattern
+	if pattern == "" {
+		pattern = PatternBars
+	}
+	source := fmt.Sprintf("%s=size=%dx%d:rate=25:duration=%d", pattern, width, height, c.seconds())
+	return []string{
+		"-hide_banner",
+		"-nostdin",
+		"-fflags", "+bitexact",
+		"-f", "lavfi",
+		"-i", source,
+		"-sws_flags", "+bitexact+accurate_rnd+full_chroma_int",
+		"-vf", graph,
+		"-flags:v", "+bitexact",
+		"-an",
+		"-f", "framemd5",
+		"-",
+	}, nil
+}
+
+// Render runs the case and returns its frame hashes in order.
+func Render(ctx context.Context, ffmpegPath string, c Case) ([]string, error) {
+	args, err := c.Args()
+	if err != nil {
+		return nil, err
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, ffmpegPath, args...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if i := strings.LastIndex(msg, "\n"); i >= 0 {
+			msg = msg[i+1:]
+		}
+		return nil, fmt.Errorf("golden %s: ffmpeg: %w: %s", c.Name, err, msg)
+	}
+	return ParseFrameMD5(stdout.String()), nil
+}
+
+// ParseFrameMD5 returns the hashes from ffmpeg's framemd5 output, the last
+// column of each non-comment line.
+func ParseFrameMD5(output string) []string {
+	var hashes []string
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		hashes = append(hashes, strings.TrimSpace(fields[len(fields)-1]))
+	}
+	return hashes
+}
+
+// Environment describes what frame hashes depend on besides the filter
+// graph: the ffmpeg build, the platform, and the font files of the
+// families overlays fall back between.
+type Environment struct {
+	FFmpeg   string
+	Platform string
+	Fonts    string
+}
+
+// DetectEnvironment reads the environment of ffmpegPath on this machine.
+func DetectEnvironment(ctx context.Context, ffmpegPath string) (Environment, error) {
+	out, err := exec.CommandContext(ctx, ffmpegPath, "-version").Output()
+	if err != nil {
+		return Environment{}, fmt.Errorf("ffmpeg -version: %w", err)
+	}
+	version, _, _ := strings.Cut(string(out), "\n")
+	var fonts []string
+	for _, family := range []string{"Oswald", "Futura"} {
+		file, err := exec.CommandContext(ctx, "fc-match", "--format=%{file}", family).Output()
+		if err != nil {
+			file = []byte("none")
+		}
+		fonts = append(fonts, family+"="+strings.TrimSpace(string(file)))
+	}
+	return Environment{
+		FFmpeg:   strings.TrimSpace(version),
+		Platform: runtime.GOOS + "/" + runtime.GOARCH,
+		Fonts:    strings.Join(fonts, " "),
+	}, nil
+}
+
+// Golden is a recorded render: the environment it was made in and the
+// hash of each frame.
+type Golden struct {
+	Environment Environment
+	Frames      []string
+}
+
+// Read loads a golden file written by Write.
+func Read(path string) (Golden, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Golden{}, err
+	}
+	var g Golden
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(line, "#"); ok {
+			key, value, _ := strings.Cut(strings.TrimSpace(rest), ":")
+			value = strings.TrimSpace(value)
+			switch strings.TrimSpace(key) {
+			case "ffmpeg":
+				g.Environment.FFmpeg = value
+			case "platform":
+				g.Environment.Platform = value
+			case "fonts":
+				g.Environment.Fonts = value
+			}
+			continue
+		}
+		g.Frames = append(g.Frames, line)
+	}
+	if len(g.Frames) == 0 {
+		return Golden{}, fmt.Errorf("%s: no frames", path)
+	}
+	return g, nil
+}
+
+// Write saves g to path, its environment as comments and then one frame
+// hash per line.
+func Write(path string, g Golden) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# ffmpeg: %s\n", g.Environment.FFmpeg)
+	fmt.Fprintf(&b, "# platform: %s\n", g.Environment.Platform)
+	fmt.Fprintf(&b, "# fonts: %s\n", g.Environment.Fonts)
+	for _, frame := range g.Frames {
+		b.WriteString(frame + "\n")
+	}
+	return os.WriteFile(path, []byte(b.String()), 0o644)
+}
+
+// Compare returns an error naming the frames of got that differ from want,
+// or nil when they match.
+func Compare(want, got []string) error {
+	if len(want) != len(got) {
+		return fmt.Errorf("rendered %d frames, golden has %d", len(got), len(want))
+	}
+	var diff []string
+	for i := range want {
+		if want[i] != got[i] {
+			diff = append(diff, strconv.Itoa(i))
+		}
+	}
+	if len(diff) == 0 {
+		return nil
+	}
+	const shown = 10
+	if len(diff) > shown {
+		diff = append(diff[:shown], "...")
+	}
+	return errors.New("frames differ from golden: " + strings.Join(diff, ", "))
+}
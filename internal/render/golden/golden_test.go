@@ -0,0 +1,167 @@
+package golden
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"powerhour/internal/config"
+	"powerhour/internal/render"
+)
+
+var update = flag.Bool("update", false, "record golden frame hashes in testdata/")
+
+// cases cover the stages of the segment filter graph. Add one when a
+// filter or overlay changes shape, then record it with -update.
+var cases = []Case{
+	{Name: "bars", Pattern: PatternBars},
+	{Name: "portrait-letterbox", Pattern: PatternTestsrc, Width: 360, Height: 640},
+	{
+		Name:    "fades",
+		Pattern: PatternTestsrc,
+		Segment: func(seg *render.Segment) {
+			seg.Clip.FadeInSeconds, seg.Clip.FadeOutSeconds = 0.5, 0.5
+		},
+	},
+	{
+		Name:    "crop",
+		Pattern: PatternBars,
+		Segment: func(seg *render.Segment) { seg.Crop = "crop=w=480:h=270:x=80:y=45" },
+	},
+	{
+		Name:    "song-info",
+		Pattern: PatternTestsrc,
+		Seconds: 6,
+		Segment: func(seg *render.Segment) {
+			seg.Overlays = []config.OverlayEntry{{Type: "song-info"}}
+		},
+	},
+	{
+		Name:    "drink",
+		Pattern: PatternBars,
+		Segment: func(seg *render.Segment) {
+			seg.Overlays = []config.OverlayEntry{{Type: "drink"}}
+		},
+	},
+}
+
+func TestGoldenFrames(t *testing.T) {
+	ffmpeg, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		t.Skip("ffmpeg not installed")
+	}
+	ctx := context.Background()
+	env, err := DetectEnvironment(ctx, ffmpeg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			path := filepath.Join("testdata", c.Name+".framemd5")
+			got, err := Render(ctx, ffmpeg, c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *update {
+				if err := Write(path, Golden{Environment: env, Frames: got}); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			want, err := Read(path)
+			if errors.Is(err, fs.ErrNotExist) {
+				t.Skipf("no golden at %s; record it with -update", path)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want.Environment != env {
+				t.Skipf("golden recorded with %+v, running %+v; re-record with -update", want.Environment, env)
+			}
+			if err := Compare(want.Frames, got); err != nil {
+				t.Errorf("%v (re-record with -update if the change is intended)", err)
+			}
+		})
+	}
+}
+
+func TestCaseArgs(t *testing.T) {
+	args, err := (Case{Name: "bars"}).Args()
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := slices.Index(args, "-i")
+	if i < 0 || args[i+1] != "smptebars=size=640x360:rate=25:duration=2" {
+		t.Fatalf("unexpected source in %v", args)
+	}
+	if args[len(args)-2] != "framemd5" {
+		t.Fatalf("expected framemd5 output, got %v", args)
+	}
+	for _, c := range cases {
+		if _, err := c.Args(); err != nil {
+			t.Errorf("case %s: %v", c.Name, err)
+		}
+	}
+
+	if _, err := (Case{Name: "long-freeze", Segment: func(seg *render.Segment) {
+		seg.Clip.Row.CustomFields = map[string]string{render.FreezeField: "5"}
+	}}).Args(); err == nil {
+		t.Fatal("expected the filter graph error for a freeze longer than the clip")
+	}
+}
+
+func TestParseFrameMD5(t *testing.T) {
+	output := `#format: frame checksums
+#version: 2
+#hash: MD5
+#stream#, dts,        pts, duration,     size, hash
+0,          0,          0,        1,    86400, 5f2c1d3e
+0,          1,          1,        1,    86400, 9a0b7c44
+`
+	if got, want := ParseFrameMD5(output), []string{"5f2c1d3e", "9a0b7c44"}; !slices.Equal(got, want) {
+		t.Fatalf("ParseFrameMD5 = %v, want %v", got, want)
+	}
+}
+
+func TestGoldenRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "case.framemd5")
+	g := Golden{
+		Environment: Environment{FFmpeg: "ffmpeg version 7.1", Platform: "linux/amd64", Fonts: "Oswald=none Futura=/f.ttf"},
+		Frames:      []string{"aa", "bb", "cc"},
+	}
+	if err := Write(path, g); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Environment != g.Environment || !slices.Equal(got.Frames, g.Frames) {
+		t.Fatalf("round trip = %+v, want %+v", got, g)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	if err := Compare([]string{"a", "b"}, []string{"a", "b"}); err != nil {
+		t.Fatalf("equal frames: %v", err)
+	}
+	if err := Compare([]string{"a", "b", "c"}, []string{"a", "x", "y"}); err == nil || err.Error() != "frames differ from golden: 1, 2" {
+		t.Fatalf("Compare = %v", err)
+	}
+	if err := Compare([]string{"a"}, []string{"a", "b"}); err == nil {
+		t.Fatal("expected a frame count mismatch")
+	}
+}
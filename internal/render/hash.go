@@ -17,17 +17,30 @@ type fieldEntry struct {
 
 // segmentInput is the canonical structure hashed for per-segment changes.
 type segmentInput struct {
-	Link            string                `json:"link"`
-	StartRaw        string                `json:"start_raw"`
-	DurationSeconds int                   `json:"duration_seconds"`
-	Title           string                `json:"title"`
-	Artist          string                `json:"artist"`
-	Name            string                `json:"name"`
-	CustomFields    []fieldEntry          `json:"custom_fields"`
-	FadeInSeconds   float64               `json:"fade_in_seconds"`
-	FadeOutSeconds  float64               `json:"fade_out_seconds"`
-	Overlays        []config.OverlayEntry `json:"overlays"`
-	Template        string                `json:"template"`
+	Link            string                 `json:"link"`
+	StartRaw        string                 `json:"start_raw"`
+	DurationSeconds int                    `json:"duration_seconds"`
+	Title           string                 `json:"title"`
+	Artist          string                 `json:"artist"`
+	Name            string                 `json:"name"`
+	CustomFields    []fieldEntry           `json:"custom_fields"`
+	FadeInSeconds   float64                `json:"fade_in_seconds"`
+	FadeOutSeconds  float64                `json:"fade_out_seconds"`
+	FadeCurve       string                 `json:"fade_curve,omitempty"`
+	AudioFadeIn     *float64               `json:"audio_fade_in_seconds,omitempty"`
+	AudioFadeOut    *float64               `json:"audio_fade_out_seconds,omitempty"`
+	Overlays        []config.OverlayEntry  `json:"overlays"`
+	Template        string                 `json:"template"`
+	Crop            string                 `json:"crop,omitempty"`
+	Color           string                 `json:"color,omitempty"`
+	Downmix         string                 `json:"downmix,omitempty"`
+	PrerollSeconds  float64                `json:"preroll_seconds,omitempty"`
+	PostrollSeconds float64                `json:"postroll_seconds,omitempty"`
+	PadMode         string                 `json:"pad_mode,omitempty"`
+	AudioCue        *config.AudioCueConfig `json:"audio_cue,omitempty"`
+	Loop            bool                   `json:"loop,omitempty"`
+	PadShort        string                 `json:"pad_short,omitempty"`
+	SampleSeconds   float64                `json:"sample_seconds,omitempty"`
 }
 
 // SegmentInputHash returns a deterministic hash of all render-relevant inputs
@@ -51,12 +64,137 @@ func SegmentInputHash(seg Segment, filenameTemplate string) string {
 		CustomFields:    fields,
 		FadeInSeconds:   seg.Clip.FadeInSeconds,
 		FadeOutSeconds:  seg.Clip.FadeOutSeconds,
+		FadeCurve:       audioFadeCurve(seg.Clip),
+		AudioFadeIn:     seg.Clip.AudioFadeIn,
+		AudioFadeOut:    seg.Clip.AudioFadeOut,
 		Overlays:        seg.Overlays,
 		Template:        filenameTemplate,
+		Crop:            seg.Crop,
+		Color:           seg.Color,
+		Downmix:         seg.Downmix,
+		PrerollSeconds:  seg.Clip.PrerollSeconds,
+		PostrollSeconds: seg.Clip.PostrollSeconds,
+		AudioCue:        seg.Clip.AudioCue,
+		Loop:            seg.Clip.Loop,
+		PadShort:        seg.Clip.PadShort,
+		SampleSeconds:   seg.SampleSeconds,
+	}
+	if input.PrerollSeconds > 0 || input.PostrollSeconds > 0 {
+		input.PadMode = seg.Clip.PadMode
 	}
 	return HashJSON(input)
 }
 
+// Segment input parts, the groups of SegmentInputHash inputs hashed
+// separately by SegmentInputParts so a changed hash can be explained.
+const (
+	InputPartSource   = "source"
+	InputPartTiming   = "timing"
+	InputPartText     = "text"
+	InputPartOverlays = "overlays"
+	InputPartFades    = "fades"
+	InputPartCrop     = "crop"
+	InputPartColor    = "color"
+	InputPartDownmix  = "downmix"
+	InputPartTemplate = "template"
+)
+
+// InputPartNames lists the InputPart* names in display order.
+var InputPartNames = []string{
+	InputPartSource, InputPartTiming, InputPartText, InputPartOverlays,
+	InputPartFades, InputPartCrop, InputPartColor, InputPartDownmix,
+	InputPartTemplate,
+}
+
+// InputField is one input of SegmentInputHash and the part it belongs to.
+type InputField struct {
+	Part  string `json:"part"`
+	Name  string `json:"name"`
+	Value any    `json:"value"`
+}
+
+// SegmentInputFields returns the inputs of SegmentInputHash with their
+// names, grouped by part in InputPartNames order.
+func SegmentInputFields(seg Segment, filenameTemplate string) []InputField {
+	var fields []fieldEntry
+	for k, v := range seg.Clip.Row.CustomFields {
+		fields = append(fields, fieldEntry{Key: k, Value: v})
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Key < fields[j].Key
+	})
+	padMode := ""
+	if seg.Clip.PrerollSeconds > 0 || seg.Clip.PostrollSeconds > 0 {
+		padMode = seg.Clip.PadMode
+	}
+
+	inputs := []InputField{
+		{InputPartSource, "link", seg.Clip.Row.Link},
+		{InputPartTiming, "start_raw", seg.Clip.Row.StartRaw},
+		{InputPartTiming, "duration_seconds", seg.Clip.DurationSeconds},
+		{InputPartTiming, "preroll_seconds", seg.Clip.PrerollSeconds},
+		{InputPartTiming, "postroll_seconds", seg.Clip.PostrollSeconds},
+		{InputPartTiming, "pad_mode", padMode},
+		{InputPartTiming, "loop", seg.Clip.Loop},
+		{InputPartTiming, "pad_short", seg.Clip.PadShort},
+	}
+	// Only sample renders, kept in their own state, carry a sample length.
+	if seg.SampleSeconds > 0 {
+		inputs = append(inputs, InputField{InputPartTiming, "sample_seconds", seg.SampleSeconds})
+	}
+	inputs = append(inputs, []InputField{
+		{InputPartText, "title", seg.Clip.Row.Title},
+		{InputPartText, "artist", seg.Clip.Row.Artist},
+		{InputPartText, "name", seg.Clip.Row.Name},
+		{InputPartText, "custom_fields", fields},
+		{InputPartOverlays, "overlays", seg.Overlays},
+		{InputPartFades, "fade_in_seconds", seg.Clip.FadeInSeconds},
+		{InputPartFades, "fade_out_seconds", seg.Clip.FadeOutSeconds},
+		{InputPartFades, "audio_cue", seg.Clip.AudioCue},
+	}...)
+	// Audio fades came after the video ones, so only clips that fade carry
+	// their curve and re-render to pick them up; the rest keep their hashes.
+	if curve := audioFadeCurve(seg.Clip); curve != "" {
+		inputs = append(inputs, InputField{InputPartFades, "fade_curve", curve})
+	}
+	if seg.Clip.AudioFadeIn != nil {
+		inputs = append(inputs, InputField{InputPartFades, "audio_fade_in_seconds", *seg.Clip.AudioFadeIn})
+	}
+	if seg.Clip.AudioFadeOut != nil {
+		inputs = append(inputs, InputField{InputPartFades, "audio_fade_out_seconds", *seg.Clip.AudioFadeOut})
+	}
+	inputs = append(inputs, InputField{InputPartCrop, "crop", seg.Crop})
+	// Like the hash, color only counts once a source needs converting, so
+	// segments recorded before it don't show a changed part.
+	if seg.Color != "" {
+		inputs = append(inputs, InputField{InputPartColor, "color", seg.Color})
+	}
+	if seg.Downmix != "" {
+		inputs = append(inputs, InputField{InputPartDownmix, "downmix", seg.Downmix})
+	}
+	return append(inputs, InputField{InputPartTemplate, "template", filenameTemplate})
+}
+
+// SegmentInputParts hashes the inputs of SegmentInputHash in groups, keyed
+// by the InputPart* names. Every input belongs to exactly one group. A
+// part with one input hashes the value itself, otherwise the list of its
+// values in SegmentInputFields order.
+func SegmentInputParts(seg Segment, filenameTemplate string) map[string]string {
+	values := make(map[string][]any, len(InputPartNames))
+	for _, f := range SegmentInputFields(seg, filenameTemplate) {
+		values[f.Part] = append(values[f.Part], f.Value)
+	}
+	parts := make(map[string]string, len(values))
+	for part, v := range values {
+		if len(v) == 1 {
+			parts[part] = HashJSON(v[0])
+		} else {
+			parts[part] = HashJSON(v)
+		}
+	}
+	return parts
+}
+
 // HashJSON returns a deterministic SHA256 hash of the JSON encoding of v.
 func HashJSON(v any) string {
 	data, err := json.Marshal(v)
@@ -1,9 +1,61 @@
 package render
 
-import "strings"
+import (
+	"strings"
+	"unicode"
+)
+
+// latinFold maps accented and ligature Latin letters to ASCII so filename
+// tokens keep "Café" and "Straße" readable on FAT/exFAT drives instead of
+// dropping the letters. Upper-case forms are added from the lower-case ones.
+var latinFold = func() map[rune]string {
+	lower := map[string]string{
+		"àáâãäåāăą": "a", "æ": "ae", "çćĉċč": "c", "ďđð": "d",
+		"èéêëēĕėęě": "e", "ĝğġģ": "g", "ĥħ": "h", "ìíîïĩīĭįı": "i",
+		"ĳ": "ij", "ĵ": "j", "ķ": "k", "ĺļľŀł": "l", "ñńņňŉ": "n",
+		"òóôõöøōŏő": "o", "œ": "oe", "ŕŗř": "r", "śŝşšș": "s", "ß": "ss",
+		"ţťŧț": "t", "þ": "th", "ùúûüũūŭůűų": "u", "ŵ": "w", "ýÿŷ": "y",
+		"źżž": "z",
+	}
+	fold := map[rune]string{'ẞ': "SS"}
+	for letters, ascii := range lower {
+		for _, r := range letters {
+			fold[r] = ascii
+			if up := unicode.ToUpper(r); up != r && up >= 0x80 {
+				fold[up] = strings.ToUpper(ascii)
+			}
+		}
+	}
+	return fold
+}()
+
+// transliterate replaces the letters in latinFold with their ASCII forms and
+// leaves everything else alone.
+func transliterate(value string) string {
+	ascii := true
+	for i := 0; i < len(value); i++ {
+		if value[i] >= 0x80 {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return value
+	}
+	var builder strings.Builder
+	builder.Grow(len(value))
+	for _, r := range value {
+		if repl, ok := latinFold[r]; ok {
+			builder.WriteString(repl)
+		} else {
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
 
 func safeFileSlug(value string) string {
-	value = strings.ToLower(strings.TrimSpace(value))
+	value = strings.ToLower(transliterate(strings.TrimSpace(value)))
 	if value == "" {
 		return ""
 	}
@@ -0,0 +1,95 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Loudness is an EBU R128 measurement of a file's audio.
+type Loudness struct {
+	Integrated float64 `json:"integrated_lufs"`
+	TruePeak   float64 `json:"true_peak_db"`
+	LRA        float64 `json:"lra"`
+	// Silent is set when the file has no measurable audio (integrated
+	// loudness of -inf), such as a generated slate; the values are zero.
+	Silent bool `json:"silent,omitempty"`
+}
+
+// BuildLoudnessArgs returns the ffmpeg arguments for an analysis-only
+// loudnorm pass over the first audio stream; the measurement is printed as
+// JSON on stderr.
+func BuildLoudnessArgs(path string) []string {
+	return []string{
+		"-hide_banner",
+		"-nostats",
+		"-i", path,
+		"-map", "0:a:0",
+		"-af", "loudnorm=print_format=json",
+		"-f", "null",
+		"-",
+	}
+}
+
+// MeasureLoudness runs a loudnorm analysis pass over path.
+func MeasureLoudness(ctx context.Context, ffmpegPath, path string) (Loudness, error) {
+	var stderr bytes.Buffer
+	if err := runFFmpeg(ctx, ffmpegPath, BuildLoudnessArgs(path), nil, &stderr); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if i := strings.LastIndex(msg, "\n"); i >= 0 {
+			msg = msg[i+1:]
+		}
+		if msg != "" {
+			return Loudness{}, fmt.Errorf("measure loudness: %w: %s", err, msg)
+		}
+		return Loudness{}, fmt.Errorf("measure loudness: %w", err)
+	}
+	return ParseLoudnormOutput(stderr.String())
+}
+
+// ParseLoudnormOutput extracts the measurement from the JSON block loudnorm
+// prints at the end of an analysis pass.
+func ParseLoudnormOutput(output string) (Loudness, error) {
+	end := strings.LastIndex(output, "}")
+	if end < 0 {
+		return Loudness{}, errors.New("no loudnorm measurement in ffmpeg output")
+	}
+	start := strings.LastIndex(output[:end], "{")
+	if start < 0 {
+		return Loudness{}, errors.New("no loudnorm measurement in ffmpeg output")
+	}
+	var raw struct {
+		InputI   string `json:"input_i"`
+		InputTP  string `json:"input_tp"`
+		InputLRA string `json:"input_lra"`
+	}
+	if err := json.Unmarshal([]byte(output[start:end+1]), &raw); err != nil {
+		return Loudness{}, fmt.Errorf("parse loudnorm measurement: %w", err)
+	}
+
+	var l Loudness
+	values := []struct {
+		raw string
+		dst *float64
+	}{
+		{raw.InputI, &l.Integrated},
+		{raw.InputTP, &l.TruePeak},
+		{raw.InputLRA, &l.LRA},
+	}
+	for _, v := range values {
+		f, err := strconv.ParseFloat(strings.TrimSpace(v.raw), 64)
+		if err != nil {
+			return Loudness{}, fmt.Errorf("parse loudnorm measurement: %q: %w", v.raw, err)
+		}
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return Loudness{Silent: true}, nil
+		}
+		*v.dst = f
+	}
+	return l, nil
+}
@@ -0,0 +1,142 @@
+package render
+
+import "testing"
+
+func TestParseLoudnormOutput(t *testing.T) {
+	output := `[Parsed_loudnorm_0 @ 0x1] 
+{
+	"input_i" : "-16.42",
+	"input_tp" : "-0.87",
+	"input_lra" : "7.10",
+	"input_thresh" : "-26.61",
+	"output_i" : "-14.02",
+	"target_offset" : "0.02"
+}
+`
+	got, err := ParseLoudnormOutput(output)
+	if err != nil {
+		t.Fatalf("ParseLoudnormOutput: %v", err)
+	}
+	want := Loudness{Integrated: -16.42, TruePeak: -0.87, LRA: 7.1}
+	if got != want {
+		t.Errorf("ParseLoudnormOutput = %+v, want %+v", got, want)
+	}
+
+	silent, err := ParseLoudnormOutput(`{"input_i" : "-inf", "input_tp" : "-inf", "input_lra" : "0.00"}`)
+	if err != nil {
+		t.Fatalf("silent: %v", err)
+	}
+	if !silent.Silent {
+		t.Errorf("silent = %+v, want Silent", silent)
+	}
+
+	if _, err := ParseLoudnormOutput("Output file is empty, nothing was encoded"); err == nil {
+		t.Error("expected error when no measurement is printed")
+	}
+}
+
+func TestPickOnset(t *testing.T) {
+	output := `frame:0    pts:0       pts_time:0
+lavfi.r128.M=-120.691
+frame:1    pts:4800    pts_time:0.1
+lavfi.r128.M=-30.5
+frame:2    pts:9600    pts_time:0.2
+lavfi.r128.M=-29.8
+lavfi.r128.M=garbage
+frame:3    pts:14400   pts_time:0.3
+lavfi.r128.M=-26.0
+frame:4    pts:19200   pts_time:0.4
+lavfi.r128.M=-12.0
+frame:5    pts:24000   pts_time:0.5
+lavfi.r128.M=-11.8
+`
+	samples := ParseMomentaryLoudness(output)
+	if len(samples) != 6 {
+		t.Fatalf("ParseMomentaryLoudness returned %d samples, want 6: %+v", len(samples), samples)
+	}
+	if samples[4] != (LoudnessSample{Time: 0.4, LUFS: -12}) {
+		t.Errorf("samples[4] = %+v", samples[4])
+	}
+
+	tests := []struct {
+		name    string
+		samples []LoudnessSample
+		target  float64
+		want    float64
+		wantOK  bool
+	}{
+		{
+			name:    "strong rises beat a closer small one",
+			samples: samples,
+			target:  0.3,
+			want:    0,
+			wantOK:  true,
+		},
+		{
+			name: "nearest of equally strong rises",
+			samples: []LoudnessSample{
+				{0, -40}, {0.1, -20}, {0.2, -40}, {0.3, -40}, {0.4, -20},
+			},
+			target: 0.35,
+			want:   0.3,
+			wantOK: true,
+		},
+		{
+			name: "no rise",
+			samples: []LoudnessSample{
+				{0, -20}, {0.1, -19}, {0.2, -21},
+			},
+			target: 0.1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := PickOnset(tt.samples, tt.target)
+			if ok != tt.wantOK || (ok && got != tt.want) {
+				t.Errorf("PickOnset = %v, %t, want %v, %t", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestFindChorusCandidates(t *testing.T) {
+	// verse, chorus, verse, chorus, outro; the chorus is louder and its
+	// loudness follows the same shape both times.
+	var samples []LoudnessSample
+	add := func(seconds int, level func(i int) float64) {
+		start := 0
+		if len(samples) > 0 {
+			start = int(samples[len(samples)-1].Time) + 1
+		}
+		for i := 0; i < seconds; i++ {
+			for tenth := 0; tenth < 10; tenth++ {
+				samples = append(samples, LoudnessSample{Time: float64(start+i) + float64(tenth)/10, LUFS: level(i)})
+			}
+		}
+	}
+	verse := func(i int) float64 { return -24 + float64(i%3) }
+	chorus := func(i int) float64 { return -14 + float64(i%5) }
+	add(20, verse)
+	add(15, chorus)
+	add(20, verse)
+	add(15, chorus)
+	add(10, func(int) float64 { return -30 })
+
+	got := FindChorusCandidates(samples, 30, 3)
+	if len(got) != 3 {
+		t.Fatalf("FindChorusCandidates returned %d candidates, want 3: %+v", len(got), got)
+	}
+	if got[0].Start != 20 && got[0].Start != 55 {
+		t.Errorf("best candidate starts at %v, want a chorus at 20 or 55: %+v", got[0].Start, got)
+	}
+	if got[1].Start != 20 && got[1].Start != 55 {
+		t.Errorf("second candidate starts at %v, want the other chorus: %+v", got[1].Start, got)
+	}
+	if got[0].Confidence < got[2].Confidence || got[0].Confidence > 1 {
+		t.Errorf("confidences out of order or range: %+v", got)
+	}
+
+	if got := FindChorusCandidates(samples[:30], 30, 3); got != nil {
+		t.Errorf("short source = %+v, want nil", got)
+	}
+}
@@ -0,0 +1,126 @@
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// LoudnessSample is one momentary (400ms) EBU R128 loudness reading.
+type LoudnessSample struct {
+	Time float64 // seconds from the start of the analysed window
+	LUFS float64
+}
+
+// MinOnsetRiseLU is the smallest jump in momentary loudness between two
+// readings that counts as an onset.
+const MinOnsetRiseLU = 3.0
+
+// BuildOnsetArgs returns the ffmpeg arguments for an ebur128 pass over
+// length seconds of path's first audio stream starting at from, or the rest
+// of the file when length is zero. Each momentary reading is printed to
+// stdout every 100ms.
+func BuildOnsetArgs(path string, from, length float64) []string {
+	args := []string{
+		"-hide_banner",
+		"-nostats",
+		"-ss", strconv.FormatFloat(from, 'f', 3, 64),
+	}
+	if length > 0 {
+		args = append(args, "-t", strconv.FormatFloat(length, 'f', 3, 64))
+	}
+	return append(args,
+		"-i", path,
+		"-map", "0:a:0",
+		"-af", "ebur128=metadata=1,ametadata=mode=print:key=lavfi.r128.M:file=-",
+		"-f", "null",
+		"-",
+	)
+}
+
+// MeasureMomentaryLoudness returns the momentary loudness of length seconds
+// of path starting at from, or of the rest of the file when length is zero.
+func MeasureMomentaryLoudness(ctx context.Context, ffmpegPath, path string, from, length float64) ([]LoudnessSample, error) {
+	var stdout, stderr bytes.Buffer
+	if err := runFFmpeg(ctx, ffmpegPath, BuildOnsetArgs(path, from, length), &stdout, &stderr); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if i := strings.LastIndex(msg, "\n"); i >= 0 {
+			msg = msg[i+1:]
+		}
+		if msg != "" {
+			return nil, fmt.Errorf("measure onsets: %w: %s", err, msg)
+		}
+		return nil, fmt.Errorf("measure onsets: %w", err)
+	}
+	return ParseMomentaryLoudness(stdout.String()), nil
+}
+
+// ParseMomentaryLoudness reads the frame and lavfi.r128.M lines ametadata
+// prints. Readings without a preceding frame time are dropped.
+func ParseMomentaryLoudness(output string) []LoudnessSample {
+	var (
+		samples []LoudnessSample
+		at      = math.NaN()
+	)
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "frame:") {
+			at = math.NaN()
+			for _, field := range strings.Fields(line) {
+				if v, ok := strings.CutPrefix(field, "pts_time:"); ok {
+					if f, err := strconv.ParseFloat(v, 64); err == nil {
+						at = f
+					}
+				}
+			}
+			continue
+		}
+		v, ok := strings.CutPrefix(line, "lavfi.r128.M=")
+		if !ok || math.IsNaN(at) {
+			continue
+		}
+		lufs, err := strconv.ParseFloat(v, 64)
+		if err != nil || math.IsNaN(lufs) {
+			continue
+		}
+		samples = append(samples, LoudnessSample{Time: at, LUFS: lufs})
+	}
+	return samples
+}
+
+// PickOnset returns the onset in samples nearest to target: the start of a
+// reading interval where momentary loudness rises by at least
+// MinOnsetRiseLU. Only rises at least half as strong as the strongest are
+// considered, so a clear beat wins over a small bump that happens to be
+// closer. The bool is false when no reading rises enough.
+func PickOnset(samples []LoudnessSample, target float64) (float64, bool) {
+	type onset struct{ at, rise float64 }
+	var (
+		onsets    []onset
+		strongest float64
+	)
+	for i := 1; i < len(samples); i++ {
+		prev := math.Max(samples[i-1].LUFS, -70)
+		rise := math.Max(samples[i].LUFS, -70) - prev
+		if rise < MinOnsetRiseLU {
+			continue
+		}
+		onsets = append(onsets, onset{at: samples[i-1].Time, rise: rise})
+		strongest = math.Max(strongest, rise)
+	}
+	best, found := 0.0, false
+	for _, o := range onsets {
+		if o.rise < strongest/2 {
+			continue
+		}
+		if !found || math.Abs(o.at-target) < math.Abs(best-target) {
+			best, found = o.at, true
+		}
+	}
+	return best, found
+}
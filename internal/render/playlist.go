@@ -0,0 +1,104 @@
+package render
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"strings"
+)
+
+// PlaylistEntry is one segment in a playlist, in playing order.
+type PlaylistEntry struct {
+	Location string // path relative to the playlist, with forward slashes
+	Title    string
+	Artist   string
+	Seconds  float64 // 0 when unknown
+}
+
+// PlaylistFormats lists the supported playlist formats.
+var PlaylistFormats = []string{"m3u", "xspf"}
+
+// PlaylistExtension returns the file extension for a playlist format.
+// M3U playlists are written as UTF-8, so they get .m3u8.
+func PlaylistExtension(format string) string {
+	if format == "xspf" {
+		return ".xspf"
+	}
+	return ".m3u8"
+}
+
+// WriteM3U writes entries as an extended M3U playlist.
+func WriteM3U(w io.Writer, entries []PlaylistEntry) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "#EXTM3U")
+	for _, e := range entries {
+		seconds := -1
+		if e.Seconds > 0 {
+			seconds = int(math.Round(e.Seconds))
+		}
+		fmt.Fprintf(bw, "#EXTINF:%d,%s\n%s\n", seconds, m3uLabel(e), e.Location)
+	}
+	return bw.Flush()
+}
+
+// m3uLabel is "Artist - Title", as players split it, without line breaks.
+func m3uLabel(e PlaylistEntry) string {
+	var parts []string
+	for _, s := range []string{e.Artist, e.Title} {
+		if s = strings.Join(strings.Fields(s), " "); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, " - ")
+}
+
+type xspfPlaylist struct {
+	XMLName xml.Name    `xml:"playlist"`
+	Version string      `xml:"version,attr"`
+	NS      string      `xml:"xmlns,attr"`
+	Title   string      `xml:"title,omitempty"`
+	Tracks  []xspfTrack `xml:"trackList>track"`
+}
+
+type xspfTrack struct {
+	Location string `xml:"location"`
+	Title    string `xml:"title,omitempty"`
+	Creator  string `xml:"creator,omitempty"`
+	Duration int64  `xml:"duration,omitempty"` // milliseconds
+}
+
+// WriteXSPF writes entries as an XSPF playlist named title. Locations are
+// written as relative URIs.
+func WriteXSPF(w io.Writer, title string, entries []PlaylistEntry) error {
+	playlist := xspfPlaylist{Version: "1", NS: "http://xspf.org/ns/0/", Title: title}
+	for _, e := range entries {
+		playlist.Tracks = append(playlist.Tracks, xspfTrack{
+			Location: playlistURI(e.Location),
+			Title:    strings.TrimSpace(e.Title),
+			Creator:  strings.TrimSpace(e.Artist),
+			Duration: int64(math.Round(e.Seconds * 1000)),
+		})
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(playlist); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// playlistURI escapes each component of a slash-separated relative path.
+func playlistURI(location string) string {
+	parts := strings.Split(location, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
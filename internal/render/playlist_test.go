@@ -0,0 +1,45 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteM3U(t *testing.T) {
+	var buf bytes.Buffer
+	entries := []PlaylistEntry{
+		{Location: "songs/001_teenagers.mp4", Title: "Teenagers", Artist: "My Chemical Romance", Seconds: 60.4},
+		{Location: "__inline__/002-intermission.mp4", Title: "intermission"},
+	}
+	if err := WriteM3U(&buf, entries); err != nil {
+		t.Fatal(err)
+	}
+	want := "#EXTM3U\n" +
+		"#EXTINF:60,My Chemical Romance - Teenagers\nsongs/001_teenagers.mp4\n" +
+		"#EXTINF:-1,intermission\n__inline__/002-intermission.mp4\n"
+	if buf.String() != want {
+		t.Errorf("WriteM3U =\n%q\nwant\n%q", buf.String(), want)
+	}
+}
+
+func TestWriteXSPF(t *testing.T) {
+	var buf bytes.Buffer
+	entries := []PlaylistEntry{{Location: "songs/001 rock & roll.mp4", Title: "Rock & Roll", Artist: "Led Zeppelin", Seconds: 61.25}}
+	if err := WriteXSPF(&buf, "party", entries); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`<playlist version="1" xmlns="http://xspf.org/ns/0/">`,
+		"<title>party</title>",
+		"<location>songs/001%20rock%20&amp;%20roll.mp4</location>",
+		"<title>Rock &amp; Roll</title>",
+		"<creator>Led Zeppelin</creator>",
+		"<duration>61250</duration>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("WriteXSPF output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -2,7 +2,9 @@ package render
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
@@ -372,7 +374,15 @@ func optBool(opts map[string]string, key string, fallback bool) bool {
 }
 
 // fontFilePath resolves a fontconfig pattern to an absolute font file path.
+// A pattern that is already a font file is returned as is, which is the way
+// to pick a font where fc-match isn't installed, as on most Windows systems.
 func fontFilePath(pattern string) string {
+	switch strings.ToLower(filepath.Ext(pattern)) {
+	case ".ttf", ".otf", ".ttc":
+		if info, err := os.Stat(pattern); err == nil && !info.IsDir() {
+			return pattern
+		}
+	}
 	out, err := exec.Command("fc-match", "--format=%{file}", pattern).Output()
 	if err != nil {
 		return ""
@@ -0,0 +1,50 @@
+package render
+
+import (
+	"errors"
+	"os/exec"
+	"regexp"
+	"syscall"
+	"time"
+)
+
+// retryBackoff is how long Render waits before retry pass attempt+1.
+var retryBackoff = func(attempt int) time.Duration {
+	return time.Duration(1<<(attempt-1)) * 5 * time.Second
+}
+
+// transientFFmpegPattern matches ffmpeg output for failures that may not
+// recur: I/O hiccups on network or USB storage, and memory or file handle
+// pressure from parallel encodes.
+var transientFFmpegPattern = regexp.MustCompile(`(?i)input/output error|resource temporarily unavailable|cannot allocate memory|out of memory|too many open files|connection (?:reset|timed out|refused)|stale file handle|device or resource busy`)
+
+// fatalFFmpegPattern matches output that means a retry would fail the same
+// way, even when a transient error shows up alongside it.
+var fatalFFmpegPattern = regexp.MustCompile(`(?i)no space left on device|disk quota exceeded|no such filter|unknown encoder|invalid data found|no such file or directory`)
+
+// stderrTailBytes is how much of ffmpeg's output the classifier reads; the
+// cause is at the end.
+const stderrTailBytes = 8 << 10
+
+// IsTransientFFmpegError reports whether an ffmpeg failure is worth
+// retrying: the process was SIGKILLed (usually the OOM killer), or its output
+// ends in a temporary I/O or resource error. Everything else — bad input,
+// missing filters or encoders, a full disk — is fatal.
+func IsTransientFFmpegError(err error, stderr []byte) bool {
+	if err == nil {
+		return false
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if ws, ok := exitErr.Sys().(syscall.WaitStatus); ok && ws.Signaled() && ws.Signal() == syscall.SIGKILL {
+			return true
+		}
+		if exitErr.ExitCode() == 137 { // 128+SIGKILL, from a wrapper shell
+			return true
+		}
+	}
+	if len(stderr) > stderrTailBytes {
+		stderr = stderr[len(stderr)-stderrTailBytes:]
+	}
+	return !fatalFFmpegPattern.Match(stderr) && transientFFmpegPattern.Match(stderr)
+}
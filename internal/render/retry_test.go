@@ -0,0 +1,135 @@
+package render
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/config"
+	"powerhour/internal/paths"
+	"powerhour/pkg/csvplan"
+)
+
+func TestIsTransientFFmpegError(t *testing.T) {
+	exitErr := errors.New("exit status 1")
+	tests := []struct {
+		name   string
+		err    error
+		stderr string
+		want   bool
+	}{
+		{name: "no error", stderr: "Input/output error"},
+		{name: "io error", err: exitErr, stderr: "frame= 900\nav_interleaved_write_frame(): Input/output error\n", want: true},
+		{name: "memory", err: exitErr, stderr: "Error while filtering: Cannot allocate memory", want: true},
+		{name: "file handles", err: exitErr, stderr: "Too many open files", want: true},
+		{name: "corrupt input", err: exitErr, stderr: "Invalid data found when processing input"},
+		{name: "disk full wins", err: exitErr, stderr: "Input/output error\nNo space left on device"},
+		{name: "missing filter", err: exitErr, stderr: "No such filter: 'drawtext'"},
+		{name: "unclassified", err: exitErr, stderr: "Conversion failed!"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransientFFmpegError(tt.err, []byte(tt.stderr)); got != tt.want {
+				t.Fatalf("IsTransientFFmpegError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsTransientFFmpegErrorKilled(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a unix shell")
+	}
+	cmd := exec.Command("sh", "-c", "kill -9 $$")
+	err := cmd.Run()
+	if err == nil {
+		t.Skip("sh did not report the kill")
+	}
+	if !IsTransientFFmpegError(err, nil) {
+		t.Fatalf("SIGKILL should be transient: %v", err)
+	}
+}
+
+// flakyRunner fails the first failures runs per output with stderr.
+type flakyRunner struct {
+	mu       sync.Mutex
+	failures int
+	stderr   string
+	calls    map[string]int
+}
+
+func (r *flakyRunner) Run(_ context.Context, _ string, args []string, _ cache.RunOptions) (cache.RunResult, error) {
+	out := args[len(args)-1]
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls[out]++
+	if r.calls[out] <= r.failures {
+		return cache.RunResult{Stderr: []byte(r.stderr)}, errors.New("exit status 1")
+	}
+	return cache.RunResult{}, os.WriteFile(out, nil, 0o644)
+}
+
+func TestRenderRetriesTransientFailures(t *testing.T) {
+	orig := retryBackoff
+	retryBackoff = func(int) time.Duration { return time.Millisecond }
+	t.Cleanup(func() { retryBackoff = orig })
+
+	root := t.TempDir()
+	pp := paths.ProjectPaths{Root: root, SegmentsDir: filepath.Join(root, "segments"), LogsDir: root}
+	newSegment := func(cfg config.Config) Segment {
+		seg := newTestSegment(cfg, csvplan.Row{Index: 1, Title: "Song", DurationSeconds: 60})
+		seg.Entry.Probe = &cache.ProbeMetadata{DurationSeconds: 300}
+		seg.OutputPath = filepath.Join(pp.SegmentsDir, "001.mp4")
+		return seg
+	}
+	tests := []struct {
+		name     string
+		retries  int
+		failures int
+		stderr   string
+		attempts int
+		wantErr  bool
+	}{
+		{name: "recovers", retries: 2, failures: 2, stderr: "Input/output error", attempts: 3},
+		{name: "gives up", retries: 1, failures: 5, stderr: "Input/output error", attempts: 2, wantErr: true},
+		{name: "fatal not retried", retries: 2, failures: 1, stderr: "Invalid data found when processing input", attempts: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Render.Retries = &tt.retries
+			runner := &flakyRunner{failures: tt.failures, stderr: tt.stderr, calls: map[string]int{}}
+			svc := &Service{Paths: pp, Config: cfg, Runner: runner, ffmpegPath: "ffmpeg"}
+			reporter := &recordingReporter{}
+
+			results := svc.Render(context.Background(), []Segment{newSegment(cfg)}, Options{Concurrency: 2, Force: true, Reporter: reporter})
+			res := results[0]
+			if res.Attempts != tt.attempts || (res.Err != nil) != tt.wantErr {
+				t.Fatalf("attempts %d err %v, want %d attempts, err %v", res.Attempts, res.Err, tt.attempts, tt.wantErr)
+			}
+			if reporter.completed != 1 {
+				t.Fatalf("Complete called %d times, want once", reporter.completed)
+			}
+		})
+	}
+}
+
+type recordingReporter struct {
+	mu        sync.Mutex
+	completed int
+}
+
+func (r *recordingReporter) Start(Segment)             {}
+func (r *recordingReporter) Progress(Segment, float64) {}
+func (r *recordingReporter) Complete(Result) {
+	r.mu.Lock()
+	r.completed++
+	r.mu.Unlock()
+}
@@ -7,14 +7,19 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"powerhour/internal/cache"
 	"powerhour/internal/config"
 	"powerhour/internal/paths"
 	"powerhour/internal/project"
+	"powerhour/internal/telemetry"
 	"powerhour/internal/tools"
 )
 
@@ -45,6 +50,31 @@ type Segment struct {
 	Entry       cache.Entry
 	OutputPath  string // Optional: if set, overrides default path calculation
 	StoredHash  string // Hash from render state; if set, used for change detection
+	Crop        string // Optional crop filter applied before scaling (see ResolveCrop)
+	Color       string // Optional BT.709 or video.hdr conversion applied to the source first (see ResolveColor)
+	Downmix     string // Optional surround-to-stereo pan applied to the source audio first (see ResolveDownmix)
+	Tags        SegmentTags // Container tags; not part of the input hash
+
+	// SampleSeconds, when set, renders only the first this many seconds
+	// of the segment (render --sample-duration). Fades and overlays keep
+	// their places in the full clip.
+	SampleSeconds float64
+}
+
+// OutputSeconds is the length of the rendered file: the clip's output
+// length, cut short to SampleSeconds for a sample render.
+func (s Segment) OutputSeconds() float64 {
+	seconds := s.Clip.OutputSeconds()
+	if s.SampleSeconds > 0 && s.SampleSeconds < seconds {
+		return s.SampleSeconds
+	}
+	return seconds
+}
+
+// TrimsSilence reports whether audio.trim_silence applies to the segment:
+// plan clips that don't loop.
+func (s Segment) TrimsSilence() bool {
+	return s.Clip.SourceKind == project.SourceKindPlan && !s.Clip.Loop
 }
 
 // Result captures the outcome of a render attempt.
@@ -58,6 +88,17 @@ type Result struct {
 	Skipped    bool
 	Reason     string // Why the segment was rendered or skipped (from state.Reason* constants)
 	Err        error
+	Attempts   int  // ffmpeg runs; more than one when transient failures were retried
+	transient  bool // Err is worth retrying (see IsTransientFFmpegError)
+	// Interrupted is set when the render was canceled before the segment
+	// finished. No output was written and the segment must render again.
+	Interrupted bool
+
+	// Metrics of a successful render.
+	Elapsed       time.Duration // ffmpeg wall time
+	OutputSeconds float64       // length of the segment
+	SizeBytes     int64         // size of the segment file
+	FPS           float64       // frames encoded per second of wall time
 }
 
 // ProgressReporter receives notifications as segments move through the render pipeline.
@@ -100,6 +141,9 @@ func NewService(ctx context.Context, pp paths.ProjectPaths, cfg config.Config, r
 		msg += "\nRun 'powerhour doctor' for full diagnostics."
 		return nil, errors.New(msg)
 	}
+	if err := checkEncoder(ctx, ffmpegPath, cfg); err != nil {
+		return nil, err
+	}
 
 	return &Service{
 		Paths:      pp,
@@ -109,6 +153,35 @@ func NewService(ctx context.Context, pp paths.ProjectPaths, cfg config.Config, r
 	}, nil
 }
 
+// checkEncoder makes sure the ffmpeg build can encode the configured
+// output: a video.pix_fmt the encoder lists, and for video.hdr zscale for
+// the conversions plus a 10-bit test encode, which 8-bit-only libx265
+// builds and older GPUs fail.
+func checkEncoder(ctx context.Context, ffmpegPath string, cfg config.Config) error {
+	codec := strings.TrimSpace(cfg.Video.Codec)
+	pixFmt := OutputPixFmt(cfg)
+	if strings.TrimSpace(cfg.Video.PixFmt) != "" {
+		formats, err := tools.EncoderPixFmts(ctx, ffmpegPath, codec)
+		if err != nil {
+			return fmt.Errorf("video.pix_fmt: %w", err)
+		}
+		// Encoders that list nothing take whatever the filter graph gives.
+		if len(formats) > 0 && !slices.Contains(formats, pixFmt) {
+			return fmt.Errorf("video.pix_fmt: %s doesn't support %s (supported: %s)", codec, pixFmt, strings.Join(formats, ", "))
+		}
+	}
+	if cfg.Video.HDRMode() == "" {
+		return nil
+	}
+	if _, missing := tools.ProbeFilters(ctx, ffmpegPath, []string{"zscale"}); len(missing) > 0 {
+		return errors.New("video.hdr needs ffmpeg's zscale filter (an ffmpeg built with libzimg)")
+	}
+	if !tools.EncoderSupportsPixFmt(ctx, ffmpegPath, codec, pixFmt) {
+		return fmt.Errorf("video.hdr: encoder %s can't encode 10-bit %s on this machine; pick another HEVC encoder in video.codec", codec, pixFmt)
+	}
+	return nil
+}
+
 // SetWriters configures optional stdout/stderr writers for progress messages.
 func (s *Service) SetWriters(stdout, stderr io.Writer) {
 	if s == nil {
@@ -136,77 +209,106 @@ func (s *Service) Render(ctx context.Context, segments []Segment, opts Options)
 		concurrency = 1
 	}
 
-	var (
-		wg  sync.WaitGroup
-		sem = make(chan struct{}, concurrency)
-	)
+	retries := s.Config.Render.RetryCount()
+	pending := make([]int, len(segments))
+	for i := range segments {
+		pending[i] = i
+	}
+
+	for attempt := 1; ; attempt++ {
+		var (
+			wg    sync.WaitGroup
+			mu    sync.Mutex
+			retry []int
+			sem   = make(chan struct{}, concurrency)
+		)
+		for _, i := range pending {
+			seg := segments[i]
+			if opts.Reporter != nil {
+				opts.Reporter.Start(seg)
+			}
+			sem <- struct{}{}
+			// Don't start segments once the render is canceled.
+			if ctx.Err() != nil {
+				<-sem
+				results[i] = s.interruptedResult(seg, ctx.Err())
+				if opts.Reporter != nil {
+					opts.Reporter.Complete(results[i])
+				}
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				res := s.renderOne(ctx, seg, opts.Force, opts.Reporter)
+				res.Attempts = attempt
+				results[i] = res
+				if res.transient && attempt <= retries && ctx.Err() == nil {
+					mu.Lock()
+					retry = append(retry, i)
+					mu.Unlock()
+					return
+				}
+				if opts.Reporter != nil {
+					opts.Reporter.Complete(res)
+				}
+			}()
+		}
+		wg.Wait()
 
-	for i, seg := range segments {
-		if opts.Reporter != nil {
-			opts.Reporter.Start(seg)
+		if len(retry) == 0 {
+			return results
 		}
-		sem <- struct{}{}
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			defer func() { <-sem }()
-			res := s.renderOne(ctx, seg, opts.Force, opts.Reporter)
-			results[i] = res
+		sort.Ints(retry)
+		// Transient failures are often memory or I/O pressure, so retries
+		// wait and run with half the parallelism.
+		concurrency = max(concurrency/2, 1)
+		wait := retryBackoff(attempt)
+		s.printf("retrying %d segment(s) after transient ffmpeg failures in %s (attempt %d of %d)\n", len(retry), wait, attempt+1, retries+1)
+		select {
+		case <-ctx.Done():
 			if opts.Reporter != nil {
-				opts.Reporter.Complete(res)
+				for _, i := range retry {
+					opts.Reporter.Complete(results[i])
+				}
 			}
-		}()
+			return results
+		case <-time.After(wait):
+		}
+		pending = retry
 	}
-
-	wg.Wait()
-	return results
 }
 
-func (s *Service) renderOne(ctx context.Context, seg Segment, force bool, reporter ProgressReporter) Result {
+func (s *Service) renderOne(ctx context.Context, seg Segment, force bool, reporter ProgressReporter) (result Result) {
 	clip := seg.Clip
-	row := clip.Row
-	result := Result{
+	result = Result{
 		Index:     clip.Sequence,
 		ClipType:  clip.ClipType,
 		TypeIndex: clip.TypeIndex,
 		Title:     clipTitle(clip),
 	}
 
-	source := strings.TrimSpace(seg.SourcePath)
-	if source == "" {
-		source = strings.TrimSpace(seg.CachedPath)
-	}
-	if source == "" {
-		result.Err = fmt.Errorf("clip %s#%03d missing source path", clip.ClipType, clip.TypeIndex)
-		return result
-	}
-
-	// Validate start time and duration against source video duration
-	if err := s.validateSegmentTiming(ctx, seg, source); err != nil {
+	ctx, span := telemetry.Start(ctx, "render segment",
+		attribute.Int("powerhour.segment.index", clip.Row.Index),
+		attribute.String("powerhour.clip.type", string(clip.ClipType)),
+		attribute.Bool("powerhour.render.force", force),
+	)
+	defer func() {
+		span.SetAttributes(
+			attribute.Bool("powerhour.render.skipped", result.Skipped),
+			attribute.Float64("powerhour.render.output_seconds", result.OutputSeconds),
+			attribute.Int64("powerhour.render.size_bytes", result.SizeBytes),
+		)
+		span.End(result.Err)
+	}()
+
+	seg, source, err := s.resolveSegment(ctx, seg)
+	if err != nil {
 		result.Err = err
 		return result
 	}
 
-	// Resolve zero duration (full video) by probing actual length
-	if clip.DurationSeconds <= 0 {
-		videoDur, err := s.probeVideoDuration(ctx, source)
-		if err != nil {
-			result.Err = fmt.Errorf("probe video duration for full-length clip: %w", err)
-			return result
-		}
-		startSec := clip.Row.Start.Seconds()
-		resolved := int(videoDur - startSec)
-		if resolved <= 0 {
-			result.Err = fmt.Errorf("start_time %s exceeds video length %s",
-				formatDuration(clip.Row.Start), formatSeconds(videoDur))
-			return result
-		}
-		seg.Clip.DurationSeconds = resolved
-		seg.Clip.Row.DurationSeconds = resolved
-		clip = seg.Clip
-		row = clip.Row
-	}
-
 	outputPath, logPath := s.segmentPaths(seg)
 	result.OutputPath = outputPath
 
@@ -215,7 +317,7 @@ func (s *Service) renderOne(ctx context.Context, seg Segment, force bool, report
 		if seg.StoredHash != "" && seg.StoredHash == currentHash {
 			if exists, err := paths.FileExists(outputPath); err == nil && exists {
 				result.Skipped = true
-				s.printf("segment %03d up to date, skipping: %s\n", row.Index, outputPath)
+				s.printf("segment %03d up to date, skipping: %s\n", seg.Clip.Row.Index, outputPath)
 				return result
 			}
 		}
@@ -226,15 +328,11 @@ func (s *Service) renderOne(ctx context.Context, seg Segment, force bool, report
 		return result
 	}
 
-	filterGraph, err := BuildFilterGraph(seg, s.Config)
-	if err != nil {
-		result.Err = fmt.Errorf("build filter graph: %w", err)
-		return result
-	}
-
-	audioFilters := BuildAudioFilters(s.Config)
-
-	args, err := BuildFFmpegCmd(seg, outputPath, filterGraph, audioFilters, s.Config)
+	// ffmpeg writes to a partial file that replaces the output only once
+	// it is complete, so a canceled or crashed render never leaves a
+	// truncated segment that looks rendered.
+	partial := PartialPath(outputPath)
+	seg, args, err := s.prepareCommand(ctx, seg, source, partial)
 	if err != nil {
 		result.Err = err
 		return result
@@ -256,6 +354,7 @@ func (s *Service) renderOne(ctx context.Context, seg Segment, force bool, report
 	runOpts := cache.RunOptions{
 		Dir:    s.Paths.Root,
 		Stderr: logFile,
+		Nice:   s.Config.Render.Nice,
 	}
 	if s.stderr != nil {
 		runOpts.Stderr = io.MultiWriter(logFile, s.stderr)
@@ -263,22 +362,207 @@ func (s *Service) renderOne(ctx context.Context, seg Segment, force bool, report
 
 	// Wire up progress parsing if reporter is available.
 	if reporter != nil {
-		clipDur := float64(clip.DurationSeconds)
-		pw := newProgressWriter(clipDur, func(pct float64) {
+		pw := newProgressWriter(seg.OutputSeconds(), func(pct float64) {
 			reporter.Progress(seg, pct)
 		})
 		runOpts.Stdout = pw
 	}
 
-	if _, err := s.Runner.Run(ctx, s.ffmpegPath, args, runOpts); err != nil {
+	started := time.Now()
+	if run, err := s.Runner.Run(ctx, s.ffmpegPath, args, runOpts); err != nil {
+		_ = os.Remove(partial)
+		if ctx.Err() != nil {
+			result.Err = fmt.Errorf("render interrupted: %w", ctx.Err())
+			result.Interrupted = true
+			return result
+		}
 		result.Err = fmt.Errorf("ffmpeg failed: %w (see %s)", err, logPath)
-		_ = os.Remove(outputPath)
+		result.transient = IsTransientFFmpegError(err, run.Stderr)
+		// The previous output, if any, is left in place; the log records
+		// that this render failed.
+		fmt.Fprintf(logFile, "\n%s %v\n", renderFailedMarker, err)
+		return result
+	}
+	if err := os.Rename(partial, outputPath); err != nil {
+		_ = os.Remove(partial)
+		result.Err = fmt.Errorf("move segment into place: %w", err)
 		return result
 	}
+	result.Elapsed = time.Since(started)
+	result.OutputSeconds = seg.OutputSeconds()
+	if info, err := os.Stat(outputPath); err == nil {
+		result.SizeBytes = info.Size()
+	}
+	if fps := s.Config.Video.FPS; fps > 0 && result.Elapsed > 0 {
+		result.FPS = result.OutputSeconds * float64(fps) / result.Elapsed.Seconds()
+	}
 
 	return result
 }
 
+// renderFailedMarker starts the line renderOne appends to a segment's log
+// when ffmpeg fails.
+const renderFailedMarker = "powerhour: render failed:"
+
+// LogShowsFailure reports whether a segment log records a failed render.
+// Each render rewrites the log, so the marker means the last one failed,
+// even though an earlier output may still be on disk.
+func LogShowsFailure(log string) bool {
+	return strings.Contains(log, renderFailedMarker)
+}
+
+// PartialPath returns the file ffmpeg writes a segment to before it is
+// renamed to outputPath: a hidden file next to it with the same extension,
+// so ffmpeg still picks the right container.
+func PartialPath(outputPath string) string {
+	dir, name := filepath.Split(outputPath)
+	ext := filepath.Ext(name)
+	return filepath.Join(dir, "."+strings.TrimSuffix(name, ext)+".partial"+ext)
+}
+
+// interruptedResult is the result of a segment that was never started
+// because the render was canceled.
+func (s *Service) interruptedResult(seg Segment, cause error) Result {
+	outputPath, _ := s.segmentPaths(seg)
+	return Result{
+		Index:       seg.Clip.Sequence,
+		ClipType:    seg.Clip.ClipType,
+		TypeIndex:   seg.Clip.TypeIndex,
+		Title:       clipTitle(seg.Clip),
+		OutputPath:  outputPath,
+		Err:         fmt.Errorf("render interrupted: %w", cause),
+		Interrupted: true,
+	}
+}
+
+// resolveSegment checks seg's source and timing and fills in the length of
+// a full-length clip, returning the path it renders from.
+func (s *Service) resolveSegment(ctx context.Context, seg Segment) (Segment, string, error) {
+	clip := seg.Clip
+	source := strings.TrimSpace(seg.SourcePath)
+	if source == "" {
+		source = strings.TrimSpace(seg.CachedPath)
+	}
+	generated := clip.SourceKind == project.SourceKindGenerator
+	if source == "" && !generated {
+		return seg, "", fmt.Errorf("clip %s#%03d missing source path", clip.ClipType, clip.TypeIndex)
+	}
+
+	// Validate start time and duration against source video duration
+	if !generated {
+		if err := s.validateSegmentTiming(ctx, seg, source); err != nil {
+			return seg, "", err
+		}
+	}
+
+	// Resolve zero duration (full video) by probing actual length
+	if clip.DurationSeconds <= 0 {
+		videoDur, err := s.probeVideoDuration(ctx, source)
+		if err != nil {
+			return seg, "", fmt.Errorf("probe video duration for full-length clip: %w", err)
+		}
+		startSec := clip.Row.Start.Seconds()
+		resolved := int(videoDur - startSec)
+		if resolved <= 0 {
+			return seg, "", fmt.Errorf("start_time %s exceeds video length %s",
+				formatDuration(clip.Row.Start), formatSeconds(videoDur))
+		}
+		seg.Clip.DurationSeconds = resolved
+		seg.Clip.Row.DurationSeconds = resolved
+	}
+	return seg, source, nil
+}
+
+// prepareCommand settles what a render decides when it runs, the silence
+// trim and the audio cue's path, and returns the segment with them applied
+// and the ffmpeg arguments that write it to outputPath.
+func (s *Service) prepareCommand(ctx context.Context, seg Segment, source, outputPath string) (Segment, []string, error) {
+	if trim := s.Config.Audio.TrimSilence; trim != nil && seg.TrimsSilence() {
+		seg = s.trimLeadingSilence(ctx, seg, source, *trim)
+	}
+
+	if cue := seg.Clip.AudioCue; cue != nil {
+		resolved := *cue
+		resolved.File = strings.TrimSpace(resolved.File)
+		if !filepath.IsAbs(resolved.File) {
+			resolved.File = filepath.Join(s.Paths.Root, resolved.File)
+		}
+		if _, err := os.Stat(resolved.File); err != nil {
+			return seg, nil, fmt.Errorf("audio cue: %w", err)
+		}
+		seg.Clip.AudioCue = &resolved
+	}
+
+	filterGraph, err := BuildFilterGraph(seg, s.Config)
+	if err != nil {
+		return seg, nil, fmt.Errorf("build filter graph: %w", err)
+	}
+
+	audioFilters := BuildAudioFilters(s.Config)
+
+	args, err := BuildFFmpegCmd(seg, outputPath, filterGraph, audioFilters, s.Config)
+	if err != nil {
+		return seg, nil, err
+	}
+	return seg, args, nil
+}
+
+// Command returns the ffmpeg command line Render would run for seg, the
+// ffmpeg path followed by its arguments, without rendering or writing
+// anything (render --print-cmd). With audio.trim_silence the source is
+// still measured, so the start matches a real render.
+func (s *Service) Command(ctx context.Context, seg Segment) ([]string, error) {
+	seg, source, err := s.resolveSegment(ctx, seg)
+	if err != nil {
+		return nil, err
+	}
+	outputPath, _ := s.segmentPaths(seg)
+	_, args, err := s.prepareCommand(ctx, seg, source, outputPath)
+	if err != nil {
+		return nil, err
+	}
+	return append([]string{s.ffmpegPath}, args...), nil
+}
+
+// trimLeadingSilence moves a clip's start past the silence it opens with,
+// by at most audio.trim_silence.max_seconds and never so far that the clip
+// outruns its source. The clip keeps its length; when detection fails the
+// start stays where the plan put it.
+func (s *Service) trimLeadingSilence(ctx context.Context, seg Segment, source string, trim config.TrimSilenceConfig) Segment {
+	row := seg.Clip.Row
+	window := trim.MaxSecondsValue()
+	if probe := seg.Entry.Probe; probe != nil {
+		if channels, _ := probe.AudioLayout(); channels == 0 && len(probe.Streams) > 0 {
+			return seg
+		}
+		if probe.DurationSeconds > 0 {
+			window = min(window, probe.DurationSeconds-row.Start.Seconds()-float64(seg.Clip.DurationSeconds))
+		}
+	}
+	if window < minSilenceSeconds {
+		return seg
+	}
+
+	silence, err := MeasureLeadingSilence(ctx, s.ffmpegPath, source, row.Start.Seconds(), window, trim.ThresholdDBValue())
+	if err != nil {
+		s.printf("warning: segment %03d: %v\n", row.Index, err)
+		return seg
+	}
+	if silence <= 0 {
+		return seg
+	}
+	silence = min(silence, window)
+	seg.Clip.Row.Start += time.Duration(silence * float64(time.Second))
+	s.printf("segment %03d: skipped %.2fs of silence at the start\n", row.Index, silence)
+	return seg
+}
+
+// LogPath returns where the ffmpeg log of seg is written.
+func (s *Service) LogPath(seg Segment) string {
+	_, log := s.segmentPaths(seg)
+	return log
+}
+
 func (s *Service) segmentPaths(seg Segment) (string, string) {
 	// Use explicit OutputPath if provided (e.g., for collections with subdirectories)
 	if seg.OutputPath != "" {
@@ -461,11 +745,8 @@ func (s *Service) RenderSample(ctx context.Context, seg Segment, sampleTime floa
 		return errors.New("render service is nil")
 	}
 
-	source := strings.TrimSpace(seg.SourcePath)
-	if source == "" {
-		source = strings.TrimSpace(seg.CachedPath)
-	}
-	if source == "" {
+	inputArgs, err := sourceInputArgs(seg, s.Config)
+	if err != nil {
 		return fmt.Errorf("segment missing source path")
 	}
 
@@ -489,11 +770,8 @@ func (s *Service) RenderSample(ctx context.Context, seg Segment, sampleTime floa
 		"-hide_banner",
 		"-y",
 	}
-	if seg.Clip.SourceKind == project.SourceKindPlan {
-		args = append(args, "-ss", fmt.Sprintf("%.3f", seg.Clip.Row.Start.Seconds()))
-	}
+	args = append(args, inputArgs...)
 	args = append(args,
-		"-i", source,
 		"-vf", filterGraph,
 		"-ss", fmt.Sprintf("%.3f", sampleTime),
 		"-frames:v", "1",
@@ -512,7 +790,11 @@ func (s *Service) RenderSample(ctx context.Context, seg Segment, sampleTime floa
 		defer logFile.Close()
 	}
 
-	s.printf("Extracting frame at %.2fs from %s\n", sampleTime, filepath.Base(source))
+	from := segmentLabel(seg)
+	if source := firstNonEmpty(seg.SourcePath, seg.CachedPath); source != "" {
+		from = filepath.Base(source)
+	}
+	s.printf("Extracting frame at %.2fs from %s\n", sampleTime, from)
 
 	runOpts := cache.RunOptions{
 		Dir: s.Paths.Root,
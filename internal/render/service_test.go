@@ -0,0 +1,143 @@
+package render
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/config"
+	"powerhour/internal/paths"
+	"powerhour/pkg/csvplan"
+)
+
+// writingRunner writes the output file ffmpeg was given, then runs after.
+type writingRunner struct {
+	outputs []string
+	after   func() error
+}
+
+func (r *writingRunner) Run(_ context.Context, _ string, args []string, _ cache.RunOptions) (cache.RunResult, error) {
+	out := args[len(args)-1]
+	r.outputs = append(r.outputs, out)
+	if err := os.WriteFile(out, []byte("partial"), 0o644); err != nil {
+		return cache.RunResult{}, err
+	}
+	if r.after != nil {
+		return cache.RunResult{}, r.after()
+	}
+	return cache.RunResult{}, nil
+}
+
+func newServiceTestSegment(pp paths.ProjectPaths, index int) Segment {
+	cfg := config.Default()
+	seg := newTestSegment(cfg, csvplan.Row{Index: index, Title: "Song", DurationSeconds: 60})
+	seg.Entry.Probe = &cache.ProbeMetadata{DurationSeconds: 300}
+	seg.OutputPath = filepath.Join(pp.SegmentsDir, fmt.Sprintf("%03d.mp4", index))
+	return seg
+}
+
+func TestRenderWritesThroughPartialFile(t *testing.T) {
+	root := t.TempDir()
+	pp := paths.ProjectPaths{Root: root, SegmentsDir: filepath.Join(root, "segments"), LogsDir: root}
+	runner := &writingRunner{}
+	svc := &Service{Paths: pp, Config: config.Default(), Runner: runner, ffmpegPath: "ffmpeg"}
+	seg := newServiceTestSegment(pp, 1)
+
+	res := svc.Render(context.Background(), []Segment{seg}, Options{Force: true})[0]
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+	if len(runner.outputs) != 1 || runner.outputs[0] != PartialPath(seg.OutputPath) {
+		t.Fatalf("ffmpeg wrote %v, want the partial file", runner.outputs)
+	}
+	if _, err := os.Stat(seg.OutputPath); err != nil {
+		t.Fatalf("segment not moved into place: %v", err)
+	}
+	if _, err := os.Stat(PartialPath(seg.OutputPath)); !os.IsNotExist(err) {
+		t.Fatalf("partial file left behind: %v", err)
+	}
+}
+
+func TestRenderCanceledCleansPartialOutput(t *testing.T) {
+	root := t.TempDir()
+	pp := paths.ProjectPaths{Root: root, SegmentsDir: filepath.Join(root, "segments"), LogsDir: root}
+	ctx, cancel := context.WithCancel(context.Background())
+	runner := &writingRunner{after: func() error {
+		cancel()
+		return ctx.Err()
+	}}
+	svc := &Service{Paths: pp, Config: config.Default(), Runner: runner, ffmpegPath: "ffmpeg"}
+	first, second := newServiceTestSegment(pp, 1), newServiceTestSegment(pp, 2)
+
+	// An earlier render of the first segment must survive the interruption.
+	if err := os.MkdirAll(pp.SegmentsDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(first.OutputPath, []byte("complete"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	results := svc.Render(ctx, []Segment{first, second}, Options{Concurrency: 1, Force: true})
+	for i, res := range results {
+		if !res.Interrupted || res.Err == nil {
+			t.Errorf("result %d = %+v, want interrupted", i, res)
+		}
+	}
+	if len(runner.outputs) != 1 {
+		t.Errorf("ffmpeg ran %d times, want the second segment not started", len(runner.outputs))
+	}
+	if results[1].OutputPath != second.OutputPath {
+		t.Errorf("unstarted result OutputPath = %q, want %q", results[1].OutputPath, second.OutputPath)
+	}
+	if data, err := os.ReadFile(first.OutputPath); err != nil || string(data) != "complete" {
+		t.Errorf("earlier render = %q, %v; want it untouched", data, err)
+	}
+	for _, seg := range []Segment{first, second} {
+		if _, err := os.Stat(PartialPath(seg.OutputPath)); !os.IsNotExist(err) {
+			t.Errorf("partial file of %s left behind: %v", filepath.Base(seg.OutputPath), err)
+		}
+	}
+}
+
+func TestRenderFailureKeepsPreviousOutput(t *testing.T) {
+	root := t.TempDir()
+	pp := paths.ProjectPaths{Root: root, SegmentsDir: filepath.Join(root, "segments"), LogsDir: root}
+	runner := &writingRunner{after: func() error { return errors.New("exit status 1") }}
+	svc := &Service{Paths: pp, Config: config.Default(), Runner: runner, ffmpegPath: "ffmpeg"}
+	seg := newServiceTestSegment(pp, 1)
+	if err := os.MkdirAll(pp.SegmentsDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(seg.OutputPath, []byte("complete"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	res := svc.Render(context.Background(), []Segment{seg}, Options{Force: true})[0]
+	if res.Err == nil || res.Interrupted {
+		t.Fatalf("result = %+v, want a failure", res)
+	}
+	if data, err := os.ReadFile(seg.OutputPath); err != nil || string(data) != "complete" {
+		t.Errorf("previous output = %q, %v; want it kept", data, err)
+	}
+	if _, err := os.Stat(PartialPath(seg.OutputPath)); !os.IsNotExist(err) {
+		t.Errorf("partial file left behind: %v", err)
+	}
+	log, err := os.ReadFile(res.LogPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !LogShowsFailure(string(log)) {
+		t.Errorf("log does not record the failure: %q", log)
+	}
+}
+
+func TestPartialPath(t *testing.T) {
+	got := PartialPath(filepath.Join("segments", "songs", "001_song.mp4"))
+	if want := filepath.Join("segments", "songs", ".001_song.partial.mp4"); got != want {
+		t.Fatalf("PartialPath = %q, want %q", got, want)
+	}
+}
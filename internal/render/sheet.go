@@ -0,0 +1,140 @@
+package render
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"powerhour/internal/cache"
+)
+
+// ContactSheetTile is one cell of a contact sheet: a frame grabbed from a
+// rendered segment, or a placeholder when Path is empty.
+type ContactSheetTile struct {
+	Path    string  // rendered segment; empty draws a "not rendered" tile
+	At      float64 // seconds into the segment to grab
+	Caption string
+}
+
+// ContactSheetOptions controls the contact sheet layout.
+type ContactSheetOptions struct {
+	Columns     int
+	TileWidth   int
+	TileHeight  int
+	CaptionSize int // font size; the caption band is twice this tall
+	OutputPath  string
+}
+
+// BuildContactSheetArgs assembles a single ffmpeg invocation that grabs one
+// frame per tile, letterboxes it to the tile size, draws its caption in a
+// band underneath, and stacks the tiles into a grid, row by row.
+func BuildContactSheetArgs(tiles []ContactSheetTile, opts ContactSheetOptions) ([]string, error) {
+	if len(tiles) == 0 {
+		return nil, errors.New("no tiles")
+	}
+	if opts.Columns <= 0 || opts.TileWidth <= 0 || opts.TileHeight <= 0 {
+		return nil, errors.New("tile size and columns must be positive")
+	}
+	if strings.TrimSpace(opts.OutputPath) == "" {
+		return nil, errors.New("output path is empty")
+	}
+	captionSize := max(opts.CaptionSize, 12)
+	band := captionSize * 2
+	w, h := opts.TileWidth, opts.TileHeight
+	font := fontFilePath(defaultFont())
+
+	args := []string{"-hide_banner", "-y"}
+	var graph []string
+	var layout []string
+	columns := min(opts.Columns, len(tiles))
+	for i, tile := range tiles {
+		if tile.Path == "" {
+			args = append(args, "-f", "lavfi", "-i", fmt.Sprintf("color=c=0x222222:s=%dx%d:d=1", w, h))
+		} else {
+			args = append(args, "-ss", formatFloat(math.Max(tile.At, 0)), "-i", tile.Path)
+		}
+
+		caption := buildDrawText(drawTextOptions{
+			Text:       tile.Caption,
+			End:        1,
+			FontSize:   captionSize,
+			FontFile:   font,
+			XExpr:      "10",
+			YExpr:      fmt.Sprintf("%d+(%d-text_h)/2", h, band),
+			Persistent: true,
+		})
+		graph = append(graph, fmt.Sprintf(
+			"[%d:v]scale=%d:%d:force_original_aspect_ratio=decrease,pad=%d:%d:(ow-iw)/2:(oh-ih)/2:color=black,pad=%d:%d:0:0:color=0x111111,%s,setsar=1,format=rgb24[t%d]",
+			i, w, h, w, h, w, h+band, caption, i))
+		layout = append(layout, fmt.Sprintf("%d_%d", (i%columns)*w, (i/columns)*(h+band)))
+	}
+
+	out := "[t0]"
+	if len(tiles) > 1 {
+		var inputs strings.Builder
+		for i := range tiles {
+			fmt.Fprintf(&inputs, "[t%d]", i)
+		}
+		graph = append(graph, fmt.Sprintf("%sxstack=inputs=%d:layout=%s:fill=black[sheet]",
+			inputs.String(), len(tiles), strings.Join(layout, "|")))
+		out = "[sheet]"
+	}
+
+	return append(args,
+		"-filter_complex", strings.Join(graph, ";"),
+		"-map", out,
+		"-frames:v", "1",
+		"-update", "1",
+		opts.OutputPath,
+	), nil
+}
+
+// ContactSheetCaption shortens a caption to roughly fit a tile of the given
+// width at the given font size.
+func ContactSheetCaption(text string, tileWidth, fontSize int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	limit := (tileWidth - 20) * 2 / max(fontSize, 1)
+	runes := []rune(text)
+	if limit <= 1 || len(runes) <= limit {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:limit-1])) + "…"
+}
+
+// RenderContactSheet writes a contact sheet image from the given tiles.
+func (s *Service) RenderContactSheet(ctx context.Context, tiles []ContactSheetTile, opts ContactSheetOptions) error {
+	if s == nil {
+		return errors.New("render service is nil")
+	}
+	args, err := BuildContactSheetArgs(tiles, opts)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(opts.OutputPath), 0o755); err != nil {
+		return fmt.Errorf("create output directory: %w", err)
+	}
+
+	runOpts := cache.RunOptions{Dir: s.Paths.Root}
+	logPath := filepath.Join(s.Paths.LogsDir, "contact-sheet.log")
+	_ = os.MkdirAll(s.Paths.LogsDir, 0o755)
+	logFile, err := os.Create(logPath)
+	if err != nil {
+		s.printf("warning: could not create log file: %v\n", err)
+		logPath = ""
+	} else {
+		defer logFile.Close()
+		runOpts.Stderr = logFile
+	}
+
+	if _, err := s.Runner.Run(ctx, s.ffmpegPath, args, runOpts); err != nil {
+		if logPath != "" {
+			return fmt.Errorf("ffmpeg contact sheet failed: %w (see %s)", err, logPath)
+		}
+		return fmt.Errorf("ffmpeg contact sheet failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package render
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildContactSheetArgs(t *testing.T) {
+	tiles := []ContactSheetTile{
+		{Path: "/seg/001.mp4", At: 30, Caption: "1. Song - Band"},
+		{Caption: "2. not rendered"},
+		{Path: "/seg/003.mp4", At: 12.5, Caption: "3. Other"},
+	}
+	opts := ContactSheetOptions{Columns: 2, TileWidth: 320, TileHeight: 180, CaptionSize: 16, OutputPath: "/out/sheet.png"}
+	args, err := BuildContactSheetArgs(tiles, opts)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	joined := strings.Join(args, " ")
+	for _, want := range []string{
+		"-ss 30 -i /seg/001.mp4",
+		"-f lavfi -i color=c=0x222222:s=320x180",
+		"-ss 12.5 -i /seg/003.mp4",
+		"pad=320:212:0:0",
+		"text='1. Song - Band'",
+		"xstack=inputs=3:layout=0_0|320_0|0_212:fill=black[sheet]",
+		"-map [sheet]",
+		"-frames:v 1",
+		"/out/sheet.png",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("args missing %q\nargs: %s", want, joined)
+		}
+	}
+
+	single, err := BuildContactSheetArgs(tiles[:1], opts)
+	if err != nil {
+		t.Fatalf("single: %v", err)
+	}
+	if joined := strings.Join(single, " "); strings.Contains(joined, "xstack") || !strings.Contains(joined, "-map [t0]") {
+		t.Errorf("single tile should map the tile directly: %s", joined)
+	}
+
+	if _, err := BuildContactSheetArgs(nil, opts); err == nil {
+		t.Error("expected error for no tiles")
+	}
+	if _, err := BuildContactSheetArgs(tiles, ContactSheetOptions{OutputPath: "/out/sheet.png"}); err == nil {
+		t.Error("expected error for zero layout")
+	}
+}
+
+func TestContactSheetCaption(t *testing.T) {
+	if got := ContactSheetCaption("1.  Short   title", 320, 16); got != "1. Short title" {
+		t.Errorf("short caption = %q", got)
+	}
+	got := ContactSheetCaption("12. A Very Long Song Title That Will Not Fit - Some Band", 200, 20)
+	if len([]rune(got)) != 18 || !strings.HasSuffix(got, "…") {
+		t.Errorf("long caption = %q, want 18 runes ending in an ellipsis", got)
+	}
+}
@@ -0,0 +1,80 @@
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// minSilenceSeconds is the shortest quiet stretch silencedetect reports;
+// anything shorter is a gap between notes, not a quiet bar.
+const minSilenceSeconds = 0.1
+
+// BuildSilenceArgs returns the ffmpeg arguments for a silencedetect pass over
+// length seconds of path's first audio stream starting at from, counting
+// audio below thresholdDB as silence.
+func BuildSilenceArgs(path string, from, length, thresholdDB float64) []string {
+	return []string{
+		"-hide_banner",
+		"-nostats",
+		"-ss", strconv.FormatFloat(from, 'f', 3, 64),
+		"-t", strconv.FormatFloat(length, 'f', 3, 64),
+		"-i", path,
+		"-map", "0:a:0",
+		"-af", fmt.Sprintf("silencedetect=noise=%sdB:duration=%s", formatFloat(thresholdDB), formatFloat(minSilenceSeconds)),
+		"-f", "null",
+		"-",
+	}
+}
+
+// MeasureLeadingSilence returns how many seconds of silence path has from
+// from onward, looking at most length seconds ahead. It is zero when the
+// music is already playing, and also when the whole window is silent,
+// since skipping part of a longer quiet stretch wouldn't bring the music
+// in any sooner.
+func MeasureLeadingSilence(ctx context.Context, ffmpegPath, path string, from, length, thresholdDB float64) (float64, error) {
+	var stderr bytes.Buffer
+	if err := runFFmpeg(ctx, ffmpegPath, BuildSilenceArgs(path, from, length, thresholdDB), nil, &stderr); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if i := strings.LastIndex(msg, "\n"); i >= 0 {
+			msg = msg[i+1:]
+		}
+		if msg != "" {
+			return 0, fmt.Errorf("detect silence: %w: %s", err, msg)
+		}
+		return 0, fmt.Errorf("detect silence: %w", err)
+	}
+	return ParseLeadingSilence(stderr.String()), nil
+}
+
+// ParseLeadingSilence reads silencedetect's log and returns the end of a
+// silence that starts at the beginning of the window, or zero when the
+// window opens with sound or never leaves silence.
+func ParseLeadingSilence(output string) float64 {
+	leading := false
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if _, v, ok := strings.Cut(line, "silence_start: "); ok {
+			start, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			// Only a silence from the first few frames counts as leading.
+			leading = err == nil && start <= minSilenceSeconds
+			if !leading {
+				return 0
+			}
+			continue
+		}
+		if _, v, ok := strings.Cut(line, "silence_end: "); ok && leading {
+			v, _, _ = strings.Cut(v, " ")
+			end, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return 0
+			}
+			return end
+		}
+	}
+	return 0
+}
@@ -0,0 +1,36 @@
+package render
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseLeadingSilence(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   float64
+	}{
+		{"opens with silence",
+			"[silencedetect @ 0x1] silence_start: 0\n[silencedetect @ 0x1] silence_end: 1.2345 | silence_duration: 1.2345\n", 1.2345},
+		{"silence after the music starts",
+			"[silencedetect @ 0x1] silence_start: 0.8\n[silencedetect @ 0x1] silence_end: 1.5 | silence_duration: 0.7\n", 0},
+		{"silent throughout", "[silencedetect @ 0x1] silence_start: 0\n", 0},
+		{"no silence", "size=N/A time=00:00:02.00 bitrate=N/A speed= 200x\n", 0},
+	}
+	for _, tt := range tests {
+		if got := ParseLeadingSilence(tt.output); got != tt.want {
+			t.Errorf("%s: ParseLeadingSilence = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBuildSilenceArgs(t *testing.T) {
+	args := BuildSilenceArgs("/tmp/song.mp4", 42.5, 2, -45)
+	if i := slices.Index(args, "-ss"); i < 0 || args[i+1] != "42.500" {
+		t.Errorf("expected -ss 42.500, got %v", args)
+	}
+	if i := slices.Index(args, "-af"); i < 0 || args[i+1] != "silencedetect=noise=-45dB:duration=0.1" {
+		t.Errorf("unexpected filter in %v", args)
+	}
+}
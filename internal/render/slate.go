@@ -0,0 +1,225 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"powerhour/internal/config"
+	"powerhour/internal/project"
+	"powerhour/pkg/csvplan"
+)
+
+// slateCreditsShare is the part of a slate given to scrolling credits when
+// it has any; the title card holds the rest.
+const slateCreditsShare = 0.6
+
+// sourceInputArgs returns the ffmpeg input arguments for a segment: the
+// source file (seeked to the row start for plan clips), or a color canvas
+// and silence for generated clips.
+func sourceInputArgs(seg Segment, cfg config.Config) ([]string, error) {
+	clip := seg.Clip
+	if clip.SourceKind == project.SourceKindGenerator {
+		return slateInputArgs(clip.Row, cfg), nil
+	}
+	source := strings.TrimSpace(seg.SourcePath)
+	if source == "" {
+		source = strings.TrimSpace(seg.CachedPath)
+	}
+	if source == "" {
+		return nil, errors.New("source path is empty")
+	}
+	var args []string
+	if clip.Loop {
+		// Loops restart from the top of the file, video and audio together;
+		// -t on the output stops them at the clip length.
+		args = append(args, "-stream_loop", "-1")
+	}
+	if clip.SourceKind == project.SourceKindPlan {
+		args = append(args, "-ss", formatTimecode(clip.Row.Start))
+	}
+	return append(args, "-i", source), nil
+}
+
+// slateInputArgs generates the slate background at the output size and
+// frame rate, plus a silent audio track so the segment concatenates with
+// the others.
+func slateInputArgs(row csvplan.Row, cfg config.Config) []string {
+	background := fallback(row.CustomFields[project.SlateBackgroundField], "black")
+	sampleRate := cfg.Audio.SampleRate
+	if sampleRate <= 0 {
+		sampleRate = 48000
+	}
+	channels := "stereo"
+	if cfg.Audio.Channels == 1 {
+		channels = "mono"
+	}
+	return []string{
+		"-f", "lavfi",
+		"-i", fmt.Sprintf("color=c=%s:s=%dx%d:r=%d", escapeFilterValueNoQuotes(background), cfg.Video.Width, cfg.Video.Height, cfg.Video.FPS),
+		"-f", "lavfi",
+		"-i", fmt.Sprintf("anullsrc=r=%d:cl=%s", sampleRate, channels),
+	}
+}
+
+// SlateFilters draws a generated slate: the title, date and numbered rules
+// as a title card that fades in and out, then the credits scrolling up from
+// the bottom for the rest of the clip.
+func SlateFilters(row csvplan.Row, duration float64, width, height int) []string {
+	fields := row.CustomFields
+	font := fallback(fields[project.SlateFontField], defaultFont())
+	color := fallback(fields[project.SlateFontColorField], "white")
+	credits := strings.TrimSpace(fields[project.SlateCreditsField])
+
+	cardEnd := duration
+	if credits != "" {
+		cardEnd = duration * (1 - slateCreditsShare)
+	}
+	fade := math.Min(1, cardEnd/4)
+
+	title := fallback(row.Title, "Power Hour")
+	titleSize := height / 10
+	bodySize := height / 24
+	margin := height / 12
+
+	var filters []string
+	add := func(opts drawTextOptions) {
+		opts.Font = font
+		opts.FontColor = color
+		opts.OutlineWidth = max(height/360, 1)
+		if f := buildDrawText(opts); f != "" {
+			filters = append(filters, f)
+		}
+	}
+
+	add(drawTextOptions{
+		Text:     title,
+		End:      cardEnd,
+		FadeIn:   fade,
+		FadeOut:  fade,
+		FontSize: titleSize,
+		XExpr:    "(w-text_w)/2",
+		YExpr:    strconv.Itoa(margin),
+	})
+	y := margin + titleSize*3/2
+	if date := strings.TrimSpace(fields[project.SlateDateField]); date != "" {
+		add(drawTextOptions{
+			Text:     date,
+			End:      cardEnd,
+			FadeIn:   fade,
+			FadeOut:  fade,
+			FontSize: bodySize,
+			XExpr:    "(w-text_w)/2",
+			YExpr:    strconv.Itoa(y),
+		})
+		y += bodySize * 2
+	}
+	if rules := strings.TrimSpace(fields[project.SlateRulesField]); rules != "" {
+		var numbered []string
+		for i, rule := range strings.Split(rules, "\n") {
+			numbered = append(numbered, fmt.Sprintf("%d. %s", i+1, rule))
+		}
+		add(drawTextOptions{
+			Text:        strings.Join(numbered, "\n"),
+			End:         cardEnd,
+			FadeIn:      fade,
+			FadeOut:     fade,
+			FontSize:    bodySize,
+			LineSpacing: bodySize / 2,
+			XExpr:       "(w-text_w)/2",
+			YExpr:       strconv.Itoa(y + bodySize),
+		})
+	}
+
+	if credits != "" {
+		scroll := duration - cardEnd
+		text := "Songs picked by\n\n" + credits
+		add(drawTextOptions{
+			Text:        text,
+			Start:       cardEnd,
+			End:         duration,
+			FontSize:    bodySize,
+			LineSpacing: bodySize / 2,
+			XExpr:       "(w-text_w)/2",
+			// Enter at the bottom edge and leave past the top by the end.
+			YExpr: fmt.Sprintf("h-(t-%s)*(h+text_h)/%s", formatFloat(cardEnd), formatFloat(scroll)),
+		})
+	}
+	return filters
+}
+
+// GeneratorFilters draws a generated clip: a drink card when the row has a
+// rule, otherwise a slate.
+func GeneratorFilters(row csvplan.Row, duration float64, width, height int) []string {
+	if strings.TrimSpace(row.CustomFields[project.CardRuleField]) != "" {
+		return CardFilters(row, duration, width, height)
+	}
+	return SlateFilters(row, duration, width, height)
+}
+
+// cardLineChars is roughly how many characters of rule text fit on one
+// line of a drink card.
+const cardLineChars = 28
+
+// CardFilters draws a drink card: the title near the top and the rule,
+// word-wrapped, large in the middle, both fading in and out.
+func CardFilters(row csvplan.Row, duration float64, width, height int) []string {
+	fields := row.CustomFields
+	font := fallback(fields[project.SlateFontField], defaultFont())
+	color := fallback(fields[project.SlateFontColorField], "white")
+	fade := math.Min(0.5, duration/4)
+	titleSize := height / 14
+	ruleSize := height / 10
+
+	var filters []string
+	add := func(opts drawTextOptions) {
+		opts.End = duration
+		opts.FadeIn = fade
+		opts.FadeOut = fade
+		opts.Font = font
+		opts.FontColor = color
+		opts.OutlineWidth = max(height/360, 1)
+		opts.XExpr = "(w-text_w)/2"
+		if f := buildDrawText(opts); f != "" {
+			filters = append(filters, f)
+		}
+	}
+	add(drawTextOptions{
+		Text:     fallback(row.Title, project.DefaultCardTitle),
+		FontSize: titleSize,
+		YExpr:    strconv.Itoa(height / 6),
+	})
+	add(drawTextOptions{
+		Text:        wrapWords(fields[project.CardRuleField], cardLineChars),
+		FontSize:    ruleSize,
+		LineSpacing: ruleSize / 4,
+		YExpr:       "(h-text_h)/2",
+	})
+	return filters
+}
+
+// wrapWords breaks text into lines of at most width characters at spaces;
+// longer words get a line of their own.
+func wrapWords(text string, width int) string {
+	var (
+		lines []string
+		line  string
+	)
+	for _, word := range strings.Fields(text) {
+		switch {
+		case line == "":
+			line = word
+		case len([]rune(line))+1+len([]rune(word)) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,119 @@
+package render
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"powerhour/internal/config"
+	"powerhour/internal/project"
+	"powerhour/pkg/csvplan"
+)
+
+func newSlateSegment(fields map[string]string) Segment {
+	row := csvplan.Row{Index: 1, Title: fields["title"], DurationSeconds: 20, CustomFields: fields}
+	return Segment{
+		Clip: project.Clip{
+			Sequence:        1,
+			Row:             row,
+			SourceKind:      project.SourceKindGenerator,
+			DurationSeconds: row.DurationSeconds,
+		},
+	}
+}
+
+func TestBuildFFmpegCmdSlateUsesLavfi(t *testing.T) {
+	cfg := config.Default()
+	seg := newSlateSegment(map[string]string{"title": "Power Hour", project.SlateBackgroundField: "navy"})
+
+	cmd, err := BuildFFmpegCmd(seg, "/tmp/out.mp4", "null", "anull", cfg)
+	if err != nil {
+		t.Fatalf("BuildFFmpegCmd error: %v", err)
+	}
+	joined := strings.Join(cmd, " ")
+	wantColor := "-f lavfi -i color=c=navy:s=1920x1080:r=30"
+	if !strings.Contains(joined, wantColor) {
+		t.Errorf("command missing %q:\n%s", wantColor, joined)
+	}
+	if !strings.Contains(joined, "-f lavfi -i anullsrc=r=48000:cl=stereo") {
+		t.Errorf("command missing silent audio input:\n%s", joined)
+	}
+	if slices.Contains(cmd, "-ss") {
+		t.Errorf("generated slate should not seek:\n%s", joined)
+	}
+}
+
+func TestSourceInputArgsRequiresSource(t *testing.T) {
+	seg := newTestSegment(config.Default(), csvplan.Row{Index: 1})
+	seg.SourcePath = ""
+	if _, err := sourceInputArgs(seg, config.Default()); err == nil {
+		t.Fatal("expected error for empty source path")
+	}
+}
+
+func TestSlateFilters(t *testing.T) {
+	fields := map[string]string{
+		project.SlateDateField:  "Oct 17",
+		project.SlateRulesField: "Drink on the horn\nNo skipping",
+	}
+	row := csvplan.Row{Title: "Power Hour", CustomFields: fields}
+
+	filters := SlateFilters(row, 20, 1920, 1080)
+	if len(filters) != 3 {
+		t.Fatalf("got %d filters without credits, want 3: %v", len(filters), filters)
+	}
+	joined := strings.Join(filters, ",")
+	for _, want := range []string{"Power Hour", "Oct 17", "1. Drink on the horn", "2. No skipping"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("filters missing %q:\n%s", want, joined)
+		}
+	}
+
+	fields[project.SlateCreditsField] = "Song One - Alice"
+	filters = SlateFilters(row, 20, 1920, 1080)
+	if len(filters) != 4 {
+		t.Fatalf("got %d filters with credits, want 4: %v", len(filters), filters)
+	}
+	credits := filters[3]
+	if !strings.Contains(credits, "Song One - Alice") {
+		t.Errorf("credits filter missing credit line: %s", credits)
+	}
+	// Credits take the last 60% of the clip and scroll from the bottom.
+	if !strings.Contains(credits, "h-(t-8)*(h+text_h)/12") {
+		t.Errorf("credits filter missing scroll expression: %s", credits)
+	}
+}
+
+func TestCardFilters(t *testing.T) {
+	row := csvplan.Row{Title: "Drink card", CustomFields: map[string]string{
+		project.CardRuleField: "Everyone wearing a hat drinks twice, then passes the hat",
+	}}
+	filters := GeneratorFilters(row, 10, 1920, 1080)
+	if len(filters) != 2 {
+		t.Fatalf("got %d filters, want 2: %v", len(filters), filters)
+	}
+	if !strings.Contains(filters[0], "Drink card") {
+		t.Errorf("title filter missing title: %s", filters[0])
+	}
+	if strings.Contains(strings.Join(filters, ","), "Power Hour") {
+		t.Errorf("card drew the slate: %v", filters)
+	}
+}
+
+func TestWrapWords(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		want  string
+	}{
+		{"Waterfall", 28, "Waterfall"},
+		{"Everyone wearing a hat drinks twice", 20, "Everyone wearing a\nhat drinks twice"},
+		{"  spaced   out  ", 28, "spaced out"},
+		{"Supercalifragilistic go", 5, "Supercalifragilistic\ngo"},
+	}
+	for _, tt := range tests {
+		if got := wrapWords(tt.text, tt.width); got != tt.want {
+			t.Errorf("wrapWords(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/config"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/pkg/csvplan"
+)
+
+// ErrSourceMissing marks a segment whose source hasn't been fetched or whose
+// local file doesn't exist.
+var ErrSourceMissing = errors.New("missing cached source")
+
+type sourceMissingError struct {
+	msg string
+}
+
+func (e sourceMissingError) Error() string { return e.msg }
+
+func (e sourceMissingError) Is(target error) bool { return target == ErrSourceMissing }
+
+// BuildCollectionSegment resolves a collection clip to the segment render
+// takes: its output path, and for a sourced clip the cached or local file
+// with the crop, color and downmix its probe calls for. The segment is
+// returned even on error so callers can report against its output path; a
+// source that isn't available yet wraps ErrSourceMissing.
+func BuildCollectionSegment(pp paths.ProjectPaths, cfg config.Config, idx *cache.Index, collClip project.CollectionClip) (Segment, error) {
+	clip := collClip.Clip
+	clip.Row.DurationSeconds = clip.DurationSeconds
+	if clip.Row.Index <= 0 {
+		clip.Row.Index = clip.TypeIndex
+		if clip.Row.Index <= 0 {
+			clip.Row.Index = clip.Sequence
+		}
+	}
+
+	segment := Segment{
+		Clip:     clip,
+		Overlays: collClip.Overlays,
+	}
+	segment.OutputPath = CollectionSegmentPath(cfg, pp.SegmentsDir, collClip.CollectionName, collClip.OutputDir, segment)
+
+	// Generated clips (slates) have no source; render builds them.
+	if clip.SourceKind == project.SourceKindGenerator {
+		return segment, nil
+	}
+
+	if !IsRemoteLink(clip.Row.Link) {
+		sourcePath := LocalSourcePath(pp.Root, clip.Row.Link)
+		if _, err := os.Stat(sourcePath); err != nil {
+			if os.IsNotExist(err) {
+				return segment, sourceMissingError{msg: fmt.Sprintf("local file not found: %s", sourcePath)}
+			}
+			return segment, fmt.Errorf("collection %q row %03d: stat local file: %w", collClip.CollectionName, clip.Row.Index, err)
+		}
+		segment.SourcePath = sourcePath
+		segment.CachedPath = sourcePath
+		// Local sources are indexed when fetched; use their probe for auto-crop.
+		if entry, ok, _ := ResolveEntry(pp, idx, clip.Row); ok {
+			segment.Crop = ResolveCrop(cfg, clip.Row, entry)
+			segment.Color = ResolveColor(cfg, entry)
+			segment.Downmix = ResolveDownmix(cfg, entry)
+		}
+		return segment, nil
+	}
+
+	entry, ok, err := ResolveEntry(pp, idx, clip.Row)
+	if err != nil {
+		return segment, err
+	}
+	if !ok {
+		return segment, sourceMissingError{msg: "video not downloaded; may be unavailable or region-locked"}
+	}
+	segment.Entry = entry
+	segment.SourcePath = entry.CachedPath
+	segment.CachedPath = entry.CachedPath
+	segment.Crop = ResolveCrop(cfg, clip.Row, entry)
+	segment.Color = ResolveColor(cfg, entry)
+	segment.Downmix = ResolveDownmix(cfg, entry)
+	return segment, nil
+}
+
+// ResolveEntry returns the cache entry for row's link: the fetched download
+// for a URL, or the indexed probe of a local file. ok is false when nothing
+// usable is cached.
+func ResolveEntry(pp paths.ProjectPaths, idx *cache.Index, row csvplan.Row) (cache.Entry, bool, error) {
+	if idx == nil {
+		return cache.Entry{}, false, fmt.Errorf("row %03d %q: cache index is nil", row.Index, row.Title)
+	}
+
+	link := strings.TrimSpace(row.Link)
+	if link == "" {
+		return cache.Entry{}, false, fmt.Errorf("row %03d missing link; update the plan and re-run", row.Index)
+	}
+
+	identifier := link
+	if IsRemoteLink(link) {
+		key, exists := idx.LookupLink(link)
+		if !exists {
+			return cache.Entry{}, false, nil
+		}
+		identifier = key
+	} else {
+		abs, err := filepath.Abs(LocalSourcePath(pp.Root, link))
+		if err != nil {
+			return cache.Entry{}, false, fmt.Errorf("row %03d %q: resolve source path: %w", row.Index, row.Title, err)
+		}
+		identifier = abs
+	}
+
+	entry, ok := idx.GetByIdentifier(identifier)
+	if !ok || strings.TrimSpace(entry.CachedPath) == "" {
+		return cache.Entry{}, false, nil
+	}
+	return entry, true, nil
+}
+
+// IsRemoteLink reports whether link is fetched with yt-dlp rather than read
+// from disk.
+func IsRemoteLink(link string) bool {
+	link = strings.TrimSpace(link)
+	return strings.HasPrefix(link, "http://") || strings.HasPrefix(link, "https://") || strings.HasPrefix(link, "youtu")
+}
+
+// LocalSourcePath resolves a local link against the project root. Quotes
+// left over from a pasted path are stripped, and an absolute path that
+// doesn't exist is tried under the root, so "/songs/a.mp4" finds
+// <root>/songs/a.mp4.
+func LocalSourcePath(root, link string) string {
+	link = strings.Trim(strings.TrimSpace(link), "'\"")
+	if !filepath.IsAbs(link) {
+		return filepath.Join(root, link)
+	}
+	if _, err := os.Stat(link); err == nil {
+		return link
+	}
+	return filepath.Join(root, strings.TrimPrefix(link, string(filepath.Separator)))
+}
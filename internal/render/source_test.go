@@ -0,0 +1,84 @@
+package render
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/config"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/pkg/csvplan"
+)
+
+func TestBuildCollectionSegment(t *testing.T) {
+	root := t.TempDir()
+	pp := paths.ProjectPaths{Root: root, SegmentsDir: filepath.Join(root, "segments")}
+	cfg := config.Default()
+	if err := os.MkdirAll(filepath.Join(root, "songs"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	local := filepath.Join(root, "songs", "a.mp4")
+	if err := os.WriteFile(local, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	idx := &cache.Index{}
+	idx.SetEntry(cache.Entry{Identifier: "youtube:abc", CachedPath: "/cache/abc.mp4"})
+	idx.SetLink("https://youtube.com/watch?v=abc", "youtube:abc")
+	idx.SetEntry(cache.Entry{Identifier: local, CachedPath: local})
+
+	clip := func(link string, kind project.ClipSourceKind) project.CollectionClip {
+		return project.CollectionClip{
+			CollectionName: "songs",
+			Clip: project.Clip{
+				Sequence:        1,
+				TypeIndex:       1,
+				SourceKind:      kind,
+				DurationSeconds: 60,
+				Row:             csvplan.Row{Index: 1, Title: "Song", Link: link},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		clip    project.CollectionClip
+		source  string
+		entry   bool
+		missing bool
+	}{
+		{name: "url", clip: clip("https://youtube.com/watch?v=abc", project.SourceKindPlan), source: "/cache/abc.mp4", entry: true},
+		{name: "url not fetched", clip: clip("https://youtube.com/watch?v=zzz", project.SourceKindPlan), missing: true},
+		{name: "relative path", clip: clip("songs/a.mp4", project.SourceKindPlan), source: local},
+		{name: "quoted path", clip: clip(`"songs/a.mp4"`, project.SourceKindPlan), source: local},
+		{name: "absolute path under root", clip: clip("/songs/a.mp4", project.SourceKindPlan), source: local},
+		{name: "missing file", clip: clip("songs/b.mp4", project.SourceKindPlan), missing: true},
+		{name: "generator", clip: clip("", project.SourceKindGenerator)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seg, err := BuildCollectionSegment(pp, cfg, idx, tt.clip)
+			if seg.OutputPath == "" {
+				t.Error("output path not set")
+			}
+			if tt.missing {
+				if !errors.Is(err, ErrSourceMissing) {
+					t.Fatalf("err = %v, want ErrSourceMissing", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BuildCollectionSegment: %v", err)
+			}
+			if seg.SourcePath != tt.source {
+				t.Errorf("SourcePath = %q, want %q", seg.SourcePath, tt.source)
+			}
+			if got := seg.Entry.Identifier != ""; got != tt.entry {
+				t.Errorf("entry set = %v, want %v", got, tt.entry)
+			}
+		})
+	}
+}
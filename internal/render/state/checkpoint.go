@@ -0,0 +1,134 @@
+package state
+
+import (
+	"sync"
+	"time"
+
+	"powerhour/internal/config"
+	"powerhour/internal/render"
+)
+
+// NewSegmentState is the state entry for seg after res rendered it with cfg.
+func NewSegmentState(seg render.Segment, res render.Result, cfg config.Config) SegmentState {
+	filenameTemplate := cfg.SegmentFilenameTemplate()
+	return SegmentState{
+		InputHash:    SegmentInputHash(seg, filenameTemplate),
+		SettingsHash: SettingsHash(seg, cfg),
+		InputParts:   SegmentInputParts(seg, filenameTemplate),
+		RenderedAt:   time.Now(),
+		SourcePath:   seg.CachedPath,
+		DurationS:    float64(seg.Clip.DurationSeconds),
+		EncodeS:      res.Elapsed.Seconds(),
+		FPS:          res.FPS,
+		SizeBytes:    res.SizeBytes,
+	}
+}
+
+// Checkpoint saves render state after every segment that renders, so a run
+// that crashes or is killed keeps the skip information of the segments it
+// finished.
+type Checkpoint struct {
+	path     string
+	cfg      config.Config
+	hash     string
+	segments map[string]render.Segment // by output path
+
+	mu  sync.Mutex
+	rs  *RenderState
+	err error
+}
+
+// NewCheckpoint prepares to record segments into rs and save it to path.
+func NewCheckpoint(rs *RenderState, path string, cfg config.Config, segments []render.Segment) *Checkpoint {
+	bySeg := make(map[string]render.Segment, len(segments))
+	for _, seg := range segments {
+		bySeg[seg.OutputPath] = seg
+	}
+	return &Checkpoint{
+		path:     path,
+		cfg:      cfg,
+		hash:     GlobalConfigHash(cfg),
+		segments: bySeg,
+		rs:       rs,
+	}
+}
+
+// Record adds res to the state and saves it. An interrupted segment loses
+// its entry so the next run renders it again; other skipped and failed
+// results are ignored. The first record under a different global config
+// stores the new hash, which drops the entries that predate settings hashes
+// (see SetGlobalConfig); the others keep their own SettingsHash.
+func (c *Checkpoint) Record(res render.Result) {
+	if res.Interrupted {
+		c.forget(res.OutputPath)
+		return
+	}
+	if res.Skipped || res.Err != nil {
+		return
+	}
+	seg, ok := c.segments[res.OutputPath]
+	if !ok {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.rs.GlobalConfigHash != c.hash {
+		c.rs.SetGlobalConfig(c.cfg)
+	}
+	c.rs.Segments[res.OutputPath] = NewSegmentState(seg, res, c.cfg)
+	c.save()
+}
+
+func (c *Checkpoint) forget(outputPath string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.rs.Segments[outputPath]; !ok {
+		return
+	}
+	delete(c.rs.Segments, outputPath)
+	c.save()
+}
+
+// save writes the state, keeping the first failure. c.mu must be held.
+func (c *Checkpoint) save() {
+	if err := c.rs.Save(c.path); err != nil && c.err == nil {
+		c.err = err
+	}
+}
+
+// Err returns the first save failure.
+func (c *Checkpoint) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// Reporter returns a render.ProgressReporter that records each completed
+// segment before passing events on to inner, which may be nil.
+func (c *Checkpoint) Reporter(inner render.ProgressReporter) render.ProgressReporter {
+	return checkpointReporter{c: c, inner: inner}
+}
+
+type checkpointReporter struct {
+	c     *Checkpoint
+	inner render.ProgressReporter
+}
+
+func (r checkpointReporter) Start(seg render.Segment) {
+	if r.inner != nil {
+		r.inner.Start(seg)
+	}
+}
+
+func (r checkpointReporter) Progress(seg render.Segment, pct float64) {
+	if r.inner != nil {
+		r.inner.Progress(seg, pct)
+	}
+}
+
+func (r checkpointReporter) Complete(res render.Result) {
+	r.c.Record(res)
+	if r.inner != nil {
+		r.inner.Complete(res)
+	}
+}
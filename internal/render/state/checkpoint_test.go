@@ -0,0 +1,70 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"powerhour/internal/config"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+	"powerhour/pkg/csvplan"
+)
+
+type countingReporter struct{ completed int }
+
+func (r *countingReporter) Start(render.Segment)             {}
+func (r *countingReporter) Progress(render.Segment, float64) {}
+func (r *countingReporter) Complete(render.Result)           { r.completed++ }
+
+func TestCheckpointSavesEachSegment(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "render-state.json")
+	cfg := config.Default()
+	seg := func(index int) render.Segment {
+		return render.Segment{
+			Clip:       project.Clip{Row: csvplan.Row{Index: index, Link: "https://example.com/v"}, DurationSeconds: 60},
+			OutputPath: filepath.Join("segments", string(rune('a'+index))+".mp4"),
+		}
+	}
+	segs := []render.Segment{seg(1), seg(2), seg(3)}
+
+	rs := &RenderState{
+		GlobalConfigHash: "old settings",
+		Segments:         map[string]SegmentState{"segments/z.mp4": {InputHash: "stale"}},
+	}
+	inner := &countingReporter{}
+	cp := NewCheckpoint(rs, path, cfg, segs)
+	reporter := cp.Reporter(inner)
+
+	reporter.Complete(render.Result{OutputPath: segs[0].OutputPath})
+	saved, _ := Load(path)
+	if saved.GlobalConfigHash != GlobalConfigHash(cfg) || len(saved.Segments) != 1 {
+		t.Fatalf("after first segment: hash %q, %d segments", saved.GlobalConfigHash, len(saved.Segments))
+	}
+	if saved.Segments[segs[0].OutputPath].InputHash != SegmentInputHash(segs[0], cfg.SegmentFilenameTemplate()) {
+		t.Fatal("checkpointed entry has the wrong input hash")
+	}
+
+	reporter.Complete(render.Result{OutputPath: segs[1].OutputPath, Err: errors.New("boom")})
+	reporter.Complete(render.Result{OutputPath: segs[2].OutputPath, Skipped: true})
+	reporter.Complete(render.Result{OutputPath: segs[2].OutputPath})
+	saved, _ = Load(path)
+	if len(saved.Segments) != 2 {
+		t.Fatalf("expected 2 checkpointed segments, got %d", len(saved.Segments))
+	}
+	if _, ok := saved.Segments[segs[1].OutputPath]; ok {
+		t.Fatal("failed segment should not be checkpointed")
+	}
+
+	// A segment canceled mid-render must render again, even though an
+	// earlier run left an entry for it.
+	reporter.Complete(render.Result{OutputPath: segs[0].OutputPath, Err: context.Canceled, Interrupted: true})
+	saved, _ = Load(path)
+	if _, ok := saved.Segments[segs[0].OutputPath]; ok || len(saved.Segments) != 1 {
+		t.Fatalf("interrupted segment still checkpointed: %v", saved.Segments)
+	}
+	if inner.completed != 5 || cp.Err() != nil {
+		t.Fatalf("inner saw %d completions, err %v", inner.completed, cp.Err())
+	}
+}
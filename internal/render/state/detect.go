@@ -27,8 +27,17 @@ type SegmentAction struct {
 }
 
 // DetectChanges determines which segments need re-rendering by comparing
-// current inputs against the stored render state.
+// current inputs against the stored render state. Render settings are
+// compared per segment (see SettingsCurrent), so a segment rendered after
+// a settings change stays up to date while older ones re-render.
 func DetectChanges(rs *RenderState, segments []render.Segment, cfg config.Config, filenameTemplate string, force bool) []SegmentAction {
+	return DetectChangesProgress(rs, segments, cfg, filenameTemplate, force, nil)
+}
+
+// DetectChangesProgress is DetectChanges reporting to progress as segments
+// are checked. Segments are hashed and their outputs checked in parallel;
+// the actions keep the order of segments.
+func DetectChangesProgress(rs *RenderState, segments []render.Segment, cfg config.Config, filenameTemplate string, force bool, progress ScanProgress) []SegmentAction {
 	actions := make([]SegmentAction, len(segments))
 
 	if force {
@@ -38,35 +47,27 @@ func DetectChanges(rs *RenderState, segments []render.Segment, cfg config.Config
 		return actions
 	}
 
-	currentGlobalHash := GlobalConfigHash(cfg)
-	if currentGlobalHash != rs.GlobalConfigHash {
-		for i, seg := range segments {
-			actions[i] = SegmentAction{Segment: seg, Action: ActionRender, Reason: ReasonConfigChanged}
-		}
-		return actions
-	}
-
-	for i, seg := range segments {
+	settings := rs.settingsCheck(cfg)
+	scanSegments(len(segments), progress, func(i int) {
+		seg := segments[i]
+		actions[i] = SegmentAction{Segment: seg, Action: ActionRender}
 		key := seg.OutputPath
 		prior, exists := rs.Segments[key]
-		if !exists {
-			actions[i] = SegmentAction{Segment: seg, Action: ActionRender, Reason: ReasonNew}
-			continue
+		switch {
+		case !exists:
+			actions[i].Reason = ReasonNew
+		case !settings(seg, prior):
+			actions[i].Reason = ReasonConfigChanged
+		case SegmentInputHash(seg, filenameTemplate) != prior.InputHash:
+			actions[i].Reason = ReasonInputChanged
+		default:
+			if _, err := os.Stat(key); os.IsNotExist(err) {
+				actions[i].Reason = ReasonOutputMissing
+			} else {
+				actions[i].Action, actions[i].Reason = ActionSkip, ReasonUpToDate
+			}
 		}
-
-		currentHash := SegmentInputHash(seg, filenameTemplate)
-		if currentHash != prior.InputHash {
-			actions[i] = SegmentAction{Segment: seg, Action: ActionRender, Reason: ReasonInputChanged}
-			continue
-		}
-
-		if _, err := os.Stat(key); os.IsNotExist(err) {
-			actions[i] = SegmentAction{Segment: seg, Action: ActionRender, Reason: ReasonOutputMissing}
-			continue
-		}
-
-		actions[i] = SegmentAction{Segment: seg, Action: ActionSkip, Reason: ReasonUpToDate}
-	}
+	})
 
 	return actions
 }
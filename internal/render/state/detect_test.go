@@ -1,6 +1,7 @@
 package state
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -51,12 +52,15 @@ func TestDetectChangesNewSegment(t *testing.T) {
 }
 
 func TestDetectChangesConfigChanged(t *testing.T) {
+	cfg := testConfig()
+	seg := detectTestSegment("/output/seg001.mp4")
 	rs := &RenderState{
 		GlobalConfigHash: "sha256:oldconfighash",
-		Segments:         map[string]SegmentState{},
+		Segments: map[string]SegmentState{
+			seg.OutputPath: {InputHash: SegmentInputHash(seg, "$INDEX")},
+		},
 	}
-	seg := detectTestSegment("/output/seg001.mp4")
-	actions := DetectChanges(rs, []render.Segment{seg}, testConfig(), "$INDEX", false)
+	actions := DetectChanges(rs, []render.Segment{seg}, cfg, "$INDEX", false)
 
 	if len(actions) != 1 {
 		t.Fatalf("expected 1 action, got %d", len(actions))
@@ -184,3 +188,74 @@ func TestPruneRemovesOldEntries(t *testing.T) {
 		t.Error("seg003 should still exist")
 	}
 }
+
+func TestDetectChangesSettingsPerSegment(t *testing.T) {
+	cfg := testConfig()
+	template := "$INDEX"
+	dir := t.TempDir()
+	older := detectTestSegment(filepath.Join(dir, "seg001.mp4"))
+	newer := detectTestSegment(filepath.Join(dir, "seg002.mp4"))
+	for _, seg := range []render.Segment{older, newer} {
+		if err := os.WriteFile(seg.OutputPath, []byte("fake"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A partial render after a settings change: only newer was redone.
+	previous := cfg
+	previous.Video.Preset = "slower"
+	rs := &RenderState{
+		GlobalConfigHash: GlobalConfigHash(cfg),
+		Segments: map[string]SegmentState{
+			older.OutputPath: {InputHash: SegmentInputHash(older, template), SettingsHash: SettingsHash(older, previous)},
+			newer.OutputPath: {InputHash: SegmentInputHash(newer, template), SettingsHash: SettingsHash(newer, cfg)},
+		},
+	}
+
+	actions := DetectChanges(rs, []render.Segment{older, newer}, cfg, template, false)
+	if actions[0].Action != ActionRender || actions[0].Reason != ReasonConfigChanged {
+		t.Errorf("older: got %s (%s), want render (config changed)", actions[0].Action, actions[0].Reason)
+	}
+	if actions[1].Action != ActionSkip {
+		t.Errorf("newer: got %s (%s), want skip", actions[1].Action, actions[1].Reason)
+	}
+	if got := rs.StoredHash(older, cfg); got != "" {
+		t.Errorf("StoredHash(older) = %q, want empty so the service renders it", got)
+	}
+	if got := rs.StoredHash(newer, cfg); got != rs.Segments[newer.OutputPath].InputHash {
+		t.Errorf("StoredHash(newer) = %q, want the stored input hash", got)
+	}
+}
+
+func TestDetectChangesProgressKeepsOrder(t *testing.T) {
+	cfg := testConfig()
+	rs := &RenderState{GlobalConfigHash: GlobalConfigHash(cfg), Segments: map[string]SegmentState{}}
+	segments := make([]render.Segment, 64)
+	for i := range segments {
+		segments[i] = detectTestSegment(filepath.Join("/output", fmt.Sprintf("seg%03d.mp4", i)))
+		if i%2 == 0 {
+			rs.Segments[segments[i].OutputPath] = SegmentState{InputHash: "sha256:old", SettingsHash: SettingsHash(segments[i], cfg)}
+		}
+	}
+
+	calls, last := 0, 0
+	actions := DetectChangesProgress(rs, segments, cfg, "$INDEX", false, func(done, total int) {
+		calls++
+		if done <= last || total != len(segments) {
+			t.Errorf("progress(%d, %d) after %d", done, total, last)
+		}
+		last = done
+	})
+	if calls != len(segments) || last != len(segments) {
+		t.Errorf("progress called %d times ending at %d, want %d", calls, last, len(segments))
+	}
+	for i, a := range actions {
+		want := ReasonNew
+		if i%2 == 0 {
+			want = ReasonInputChanged
+		}
+		if a.Segment.OutputPath != segments[i].OutputPath || a.Reason != want {
+			t.Errorf("action %d = %s (%s), want %s (%s)", i, a.Segment.OutputPath, a.Reason, segments[i].OutputPath, want)
+		}
+	}
+}
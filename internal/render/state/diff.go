@@ -0,0 +1,130 @@
+package state
+
+import (
+	"os"
+	"sort"
+
+	"powerhour/internal/config"
+	"powerhour/internal/render"
+)
+
+// Changes reported by ExplainChanges when the state can't be compared part
+// by part.
+const (
+	// ChangeUnrecorded means the stored hash differs but the state predates
+	// per-part hashes, so which inputs changed is unknown.
+	ChangeUnrecorded = "unrecorded"
+	// ChangeMarkedStale means nudge, pick or review cleared the stored hash.
+	ChangeMarkedStale = "marked stale"
+	// ChangeSettings means the segment was rendered with other render
+	// settings; see SettingsCurrent.
+	ChangeSettings = "settings"
+)
+
+// partLabels describes each input and global config part for people.
+var partLabels = map[string]string{
+	render.InputPartSource:   "source link",
+	render.InputPartTiming:   "start time, duration or padding",
+	render.InputPartText:     "title, artist or plan fields",
+	render.InputPartOverlays: "overlays",
+	render.InputPartFades:    "fades or audio cue",
+	render.InputPartCrop:     "crop",
+	render.InputPartTemplate: "segment filename template",
+	GlobalPartVideo:          "video settings",
+	GlobalPartAudio:          "audio settings",
+	GlobalPartEncoding:       "encoding settings",
+	ChangeUnrecorded:         "inputs (not recorded in detail by the last render)",
+	ChangeMarkedStale:        "marked stale by nudge, pick or review",
+	ChangeSettings:           "render settings",
+}
+
+// DescribeChange returns a readable description of a change name from
+// ExplainChanges.
+func DescribeChange(name string) string {
+	if label, ok := partLabels[name]; ok {
+		return label
+	}
+	return name
+}
+
+// SegmentChange is a SegmentAction with the inputs behind it.
+type SegmentChange struct {
+	SegmentAction
+	// Changes names the segment's changed input parts (render.InputPart*),
+	// or ChangeUnrecorded or ChangeMarkedStale, plus ChangeSettings, sorted.
+	Changes []string
+}
+
+// ChangedGlobalParts returns the GlobalPart* names whose hash differs from
+// the stored state, sorted. It is ChangeUnrecorded alone when the global
+// hash differs but the state has no parts to compare, and nil when nothing
+// changed or nothing has rendered yet.
+func ChangedGlobalParts(rs *RenderState, cfg config.Config) []string {
+	if rs.GlobalConfigHash == "" || rs.GlobalConfigHash == GlobalConfigHash(cfg) {
+		return nil
+	}
+	if len(rs.GlobalConfigParts) == 0 {
+		return []string{ChangeUnrecorded}
+	}
+	return changedParts(rs.GlobalConfigParts, GlobalConfigParts(cfg))
+}
+
+// ExplainChanges is DetectChanges with the reasons spelled out: each
+// segment that would re-render lists which of its inputs changed since it
+// was last rendered. A segment rendered with other render settings
+// re-renders for that, as in DetectChanges, and its own changes are still
+// listed.
+func ExplainChanges(rs *RenderState, segments []render.Segment, cfg config.Config, filenameTemplate string) []SegmentChange {
+	changes := make([]SegmentChange, len(segments))
+	settings := rs.settingsCheck(cfg)
+	scanSegments(len(segments), nil, func(i int) {
+		seg := segments[i]
+		c := SegmentChange{SegmentAction: SegmentAction{Segment: seg, Action: ActionRender}}
+		prior, exists := rs.Segments[seg.OutputPath]
+		switch {
+		case !exists:
+			c.Reason = ReasonNew
+		case prior.InputHash == "":
+			c.Reason = ReasonInputChanged
+			c.Changes = []string{ChangeMarkedStale}
+		case prior.InputHash != SegmentInputHash(seg, filenameTemplate):
+			c.Reason = ReasonInputChanged
+			if len(prior.InputParts) == 0 {
+				c.Changes = []string{ChangeUnrecorded}
+			} else {
+				c.Changes = changedParts(prior.InputParts, SegmentInputParts(seg, filenameTemplate))
+			}
+		default:
+			if _, err := os.Stat(seg.OutputPath); os.IsNotExist(err) {
+				c.Reason = ReasonOutputMissing
+			} else {
+				c.Action, c.Reason = ActionSkip, ReasonUpToDate
+			}
+		}
+		if exists && !settings(seg, prior) {
+			c.Action, c.Reason = ActionRender, ReasonConfigChanged
+			c.Changes = append(c.Changes, ChangeSettings)
+			sort.Strings(c.Changes)
+		}
+		changes[i] = c
+	})
+	return changes
+}
+
+// changedParts returns the names whose hash differs between before and
+// after, including names present in only one of them, sorted.
+func changedParts(before, after map[string]string) []string {
+	var names []string
+	for name, hash := range after {
+		if before[name] != hash {
+			names = append(names, name)
+		}
+	}
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
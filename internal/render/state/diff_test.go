@@ -0,0 +1,76 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"powerhour/internal/config"
+	"powerhour/internal/render"
+)
+
+func TestExplainChanges(t *testing.T) {
+	cfg := testConfig()
+	template := cfg.SegmentFilenameTemplate()
+	dir := t.TempDir()
+	rendered := func(name string) (render.Segment, SegmentState) {
+		seg := detectTestSegment(filepath.Join(dir, name))
+		if err := os.WriteFile(seg.OutputPath, []byte("fake"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return seg, NewSegmentState(seg, render.Result{}, cfg)
+	}
+
+	upToDate, upToDateState := rendered("up.mp4")
+	retimed, retimedState := rendered("retimed.mp4")
+	retimed.Clip.Row.StartRaw = "1:45"
+	retimed.Overlays = []config.OverlayEntry{{Type: "song-info"}, {Type: "drink"}}
+	legacy, legacyState := rendered("legacy.mp4")
+	legacyState.InputParts = nil
+	legacy.Clip.DurationSeconds = 45
+	stale, staleState := rendered("stale.mp4")
+	staleState.InputHash = ""
+	fresh := detectTestSegment(filepath.Join(dir, "new.mp4"))
+
+	rs := &RenderState{Segments: map[string]SegmentState{
+		upToDate.OutputPath: upToDateState,
+		retimed.OutputPath:  retimedState,
+		legacy.OutputPath:   legacyState,
+		stale.OutputPath:    staleState,
+	}}
+	rs.SetGlobalConfig(cfg)
+
+	got := ExplainChanges(rs, []render.Segment{upToDate, retimed, legacy, stale, fresh}, cfg, template)
+	want := []struct {
+		action, reason string
+		changes        []string
+	}{
+		{ActionSkip, ReasonUpToDate, nil},
+		{ActionRender, ReasonInputChanged, []string{render.InputPartOverlays, render.InputPartTiming}},
+		{ActionRender, ReasonInputChanged, []string{ChangeUnrecorded}},
+		{ActionRender, ReasonInputChanged, []string{ChangeMarkedStale}},
+		{ActionRender, ReasonNew, nil},
+	}
+	for i, w := range want {
+		c := got[i]
+		if c.Action != w.action || c.Reason != w.reason || !reflect.DeepEqual(c.Changes, w.changes) {
+			t.Errorf("segment %d = %s/%s %v, want %s/%s %v", i, c.Action, c.Reason, c.Changes, w.action, w.reason, w.changes)
+		}
+	}
+
+	changed := cfg
+	changed.Video.Preset = "slower"
+	if parts := ChangedGlobalParts(rs, changed); !reflect.DeepEqual(parts, []string{GlobalPartVideo}) {
+		t.Errorf("ChangedGlobalParts = %v, want [video]", parts)
+	}
+	got = ExplainChanges(rs, []render.Segment{upToDate}, changed, template)
+	if got[0].Action != ActionRender || got[0].Reason != ReasonConfigChanged || !reflect.DeepEqual(got[0].Changes, []string{ChangeSettings}) {
+		t.Errorf("settings change = %+v, want config changed with only the settings changed", got[0])
+	}
+
+	rs.GlobalConfigParts = nil
+	if parts := ChangedGlobalParts(rs, changed); !reflect.DeepEqual(parts, []string{ChangeUnrecorded}) {
+		t.Errorf("ChangedGlobalParts without parts = %v, want [unrecorded]", parts)
+	}
+}
@@ -2,6 +2,7 @@ package state
 
 import (
 	"powerhour/internal/config"
+	"powerhour/internal/project"
 	"powerhour/internal/render"
 )
 
@@ -23,7 +24,121 @@ func GlobalConfigHash(cfg config.Config) string {
 	return render.HashJSON(input)
 }
 
+// settingsInput is the canonical structure hashed for a segment's render
+// settings. The encoding section is left out: it configures concat only.
+type settingsInput struct {
+	Video config.VideoConfig `json:"video"`
+	Audio config.AudioConfig `json:"audio"`
+}
+
+// SettingsHash returns a hash of the render settings seg's output depends
+// on, stored with each segment so a settings change only counts against
+// the segments it affects that were rendered before it. It covers the video
+// and audio sections minus what can't change this segment on its own:
+// video.auto_crop and audio.downmix only reach a segment through its crop
+// and pan filters, which SegmentInputHash covers, video.color set to auto is
+// the default, the loudnorm targets don't apply while loudnorm is off, and
+// audio.trim_silence only applies to plan clips that don't loop.
+func SettingsHash(seg render.Segment, cfg config.Config) string {
+	return settingsHash(cfg, seg.TrimsSilence())
+}
+
+func settingsHash(cfg config.Config, trimsSilence bool) string {
+	input := settingsInput{Video: cfg.Video, Audio: cfg.Audio}
+	input.Video.AutoCrop = false
+	if input.Video.ColorManaged() {
+		input.Video.Color = ""
+	}
+	input.Audio.Downmix = nil
+	if !cfg.Audio.Loudnorm.EnabledValue() {
+		input.Audio.Loudnorm = config.LoudnormConfig{}
+	}
+	if !trimsSilence {
+		input.Audio.TrimSilence = nil
+	}
+	return render.HashJSON(input)
+}
+
+// SettingsCurrent reports whether prior, the state entry of seg, was
+// rendered with cfg's render settings. Entries saved before settings hashes
+// were stored fall back to the state's global config hash.
+func (rs *RenderState) SettingsCurrent(seg render.Segment, prior SegmentState, cfg config.Config) bool {
+	return rs.settingsCheck(cfg)(seg, prior)
+}
+
+// SettingsMatch is SettingsCurrent for callers without the segment at
+// hand, such as timing history: prior matches when it was rendered with
+// cfg's settings as they apply to any segment.
+func (rs *RenderState) SettingsMatch(prior SegmentState, cfg config.Config) bool {
+	current := rs.settingsCheck(cfg)
+	return current(render.Segment{}, prior) ||
+		current(render.Segment{Clip: project.Clip{SourceKind: project.SourceKindPlan}}, prior)
+}
+
+// settingsCheck is SettingsCurrent with cfg's hashes computed once, for
+// checking many segments.
+func (rs *RenderState) settingsCheck(cfg config.Config) func(seg render.Segment, prior SegmentState) bool {
+	settings := map[bool]string{false: settingsHash(cfg, false), true: settingsHash(cfg, true)}
+	globalCurrent := rs.GlobalConfigHash == GlobalConfigHash(cfg)
+	return func(seg render.Segment, prior SegmentState) bool {
+		if prior.SettingsHash != "" {
+			return prior.SettingsHash == settings[seg.TrimsSilence()]
+		}
+		return globalCurrent
+	}
+}
+
+// StoredHash returns the value for render.Segment.StoredHash: the stored
+// input hash of seg, or "" when there is none or it was rendered with other
+// settings, so the render service doesn't skip it.
+func (rs *RenderState) StoredHash(seg render.Segment, cfg config.Config) string {
+	prior, ok := rs.Segments[seg.OutputPath]
+	if !ok || !rs.SettingsCurrent(seg, prior, cfg) {
+		return ""
+	}
+	return prior.InputHash
+}
+
+// Global config parts, the sections hashed separately by GlobalConfigParts.
+const (
+	GlobalPartVideo    = "video"
+	GlobalPartAudio    = "audio"
+	GlobalPartEncoding = "encoding"
+)
+
+// GlobalConfigParts hashes each section of GlobalConfigHash on its own,
+// keyed by the GlobalPart* names.
+func GlobalConfigParts(cfg config.Config) map[string]string {
+	return map[string]string{
+		GlobalPartVideo:    render.HashJSON(cfg.Video),
+		GlobalPartAudio:    render.HashJSON(cfg.Audio),
+		GlobalPartEncoding: render.HashJSON(cfg.Encoding),
+	}
+}
+
+// SetGlobalConfig records the global config hash, and its parts, of cfg.
+// When the hash changes, entries without a settings hash are dropped:
+// SettingsCurrent would judge them against the new hash, though they were
+// rendered under the old one.
+func (rs *RenderState) SetGlobalConfig(cfg config.Config) {
+	hash := GlobalConfigHash(cfg)
+	if rs.GlobalConfigHash != "" && rs.GlobalConfigHash != hash {
+		for key, seg := range rs.Segments {
+			if seg.SettingsHash == "" {
+				delete(rs.Segments, key)
+			}
+		}
+	}
+	rs.GlobalConfigHash = hash
+	rs.GlobalConfigParts = GlobalConfigParts(cfg)
+}
+
 // SegmentInputHash delegates to render.SegmentInputHash.
 func SegmentInputHash(seg render.Segment, filenameTemplate string) string {
 	return render.SegmentInputHash(seg, filenameTemplate)
 }
+
+// SegmentInputParts delegates to render.SegmentInputParts.
+func SegmentInputParts(seg render.Segment, filenameTemplate string) map[string]string {
+	return render.SegmentInputParts(seg, filenameTemplate)
+}
@@ -157,3 +157,123 @@ func TestSegmentInputHashChangesOnTemplate(t *testing.T) {
 		t.Error("different template should produce different hash")
 	}
 }
+
+func TestSegmentInputHashChangesOnPadding(t *testing.T) {
+	seg1 := testSegment()
+	seg2 := testSegment()
+	seg2.Clip.PadMode = "freeze"
+
+	if SegmentInputHash(seg1, "$INDEX") != SegmentInputHash(seg2, "$INDEX") {
+		t.Error("pad mode without padding should not change the hash")
+	}
+
+	seg2.Clip.PrerollSeconds = 0.5
+	if SegmentInputHash(seg1, "$INDEX") == SegmentInputHash(seg2, "$INDEX") {
+		t.Error("adding preroll should produce different hash")
+	}
+}
+
+func TestSegmentInputHashChangesOnAudioCue(t *testing.T) {
+	seg1 := testSegment()
+	seg2 := testSegment()
+	seg2.Clip.AudioCue = &config.AudioCueConfig{File: "horn.wav"}
+
+	hash2 := SegmentInputHash(seg2, "$INDEX")
+	if SegmentInputHash(seg1, "$INDEX") == hash2 {
+		t.Error("adding an audio cue should produce different hash")
+	}
+
+	seg2.Clip.AudioCue = &config.AudioCueConfig{File: "horn.wav", Offset: 1}
+	if SegmentInputHash(seg2, "$INDEX") == hash2 {
+		t.Error("changing the cue offset should produce different hash")
+	}
+}
+
+func TestSettingsHashIgnoresSettingsWithoutEffect(t *testing.T) {
+	seg := testSegment()
+	cfg := testConfig()
+	base := SettingsHash(seg, cfg)
+
+	cropped := testConfig()
+	cropped.Video.AutoCrop = true
+	if SettingsHash(seg, cropped) != base {
+		t.Error("auto_crop changed the settings hash; the crop input covers it")
+	}
+
+	off, lufs := false, -16.0
+	cfg.Audio.Loudnorm.Enabled = &off
+	base = SettingsHash(seg, cfg)
+	targets := cfg
+	targets.Audio.Loudnorm.IntegratedLUFS = &lufs
+	if SettingsHash(seg, targets) != base {
+		t.Error("loudnorm targets changed the settings hash while loudnorm is off")
+	}
+	on := true
+	targets.Audio.Loudnorm.Enabled = &on
+	if SettingsHash(seg, targets) == base {
+		t.Error("enabling loudnorm did not change the settings hash")
+	}
+}
+
+func TestSettingsHashScopedToSegment(t *testing.T) {
+	song := testSegment()
+	looped := testSegment()
+	looped.Clip.Loop = true
+	cfg := testConfig()
+	songHash, loopedHash := SettingsHash(song, cfg), SettingsHash(looped, cfg)
+
+	// The encoding section configures concat, not segments.
+	concat := testConfig()
+	concat.Encoding.VideoCodec = "libx265"
+	concat.Encoding.AudioBitrate = "320k"
+	if SettingsHash(song, concat) != songHash {
+		t.Error("the concat encoding changed a segment's settings hash")
+	}
+
+	// Downmix levels reach a segment only through its pan filter.
+	downmix := testConfig()
+	downmix.Audio.Downmix = &config.DownmixConfig{}
+	if SettingsHash(song, downmix) != songHash {
+		t.Error("audio.downmix changed the settings hash; the downmix input covers it")
+	}
+
+	// Silence trimming applies to plan clips that don't loop.
+	trim := testConfig()
+	trim.Audio.TrimSilence = &config.TrimSilenceConfig{MaxSeconds: 3}
+	if SettingsHash(looped, trim) != loopedHash {
+		t.Error("audio.trim_silence changed the settings hash of a looping clip")
+	}
+	if SettingsHash(song, trim) == songHash {
+		t.Error("audio.trim_silence did not change the settings hash of a plan clip")
+	}
+
+	rs := &RenderState{Segments: map[string]SegmentState{}}
+	if !rs.SettingsCurrent(looped, SegmentState{SettingsHash: loopedHash}, trim) {
+		t.Error("a looping clip went stale after audio.trim_silence changed")
+	}
+	if rs.SettingsCurrent(song, SegmentState{SettingsHash: songHash}, trim) {
+		t.Error("a plan clip stayed current after audio.trim_silence changed")
+	}
+}
+
+func TestSetGlobalConfigDropsEntriesWithoutSettings(t *testing.T) {
+	cfg := testConfig()
+	rs := &RenderState{Segments: map[string]SegmentState{
+		"/output/legacy.mp4":  {InputHash: "sha256:aaa"},
+		"/output/tracked.mp4": {InputHash: "sha256:bbb", SettingsHash: SettingsHash(testSegment(), cfg)},
+	}}
+	rs.SetGlobalConfig(cfg)
+	if len(rs.Segments) != 2 {
+		t.Fatalf("first SetGlobalConfig dropped entries: %v", rs.Segments)
+	}
+
+	changed := testConfig()
+	changed.Encoding.VideoCodec = "libx265"
+	rs.SetGlobalConfig(changed)
+	if _, ok := rs.Segments["/output/legacy.mp4"]; ok {
+		t.Error("entry without a settings hash survived a settings change")
+	}
+	if _, ok := rs.Segments["/output/tracked.mp4"]; !ok {
+		t.Error("entry with a settings hash was dropped")
+	}
+}
@@ -0,0 +1,87 @@
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"powerhour/internal/config"
+	"powerhour/internal/render"
+)
+
+// MaxRuns is how many render runs RecordRun keeps.
+const MaxRuns = 100
+
+// RunStats summarizes one render run, so timings survive the segments they
+// describe being re-rendered and can be compared across settings.
+type RunStats struct {
+	StartedAt   time.Time `json:"started_at"`
+	WallS       float64   `json:"wall_s"`
+	ConfigHash  string    `json:"config_hash"`
+	Codec       string    `json:"codec"`
+	Preset      string    `json:"preset,omitempty"`
+	CRF         int       `json:"crf"`
+	Width       int       `json:"width"`
+	Height      int       `json:"height"`
+	FPS         int       `json:"fps"`
+	Concurrency int       `json:"concurrency"`
+	Rendered    int       `json:"rendered"`
+	Failed      int       `json:"failed"`
+	VideoS      float64   `json:"video_s"`  // length of the rendered segments
+	EncodeS     float64   `json:"encode_s"` // summed ffmpeg wall time
+	Bytes       int64     `json:"bytes"`
+}
+
+// Settings names the encode settings of a run, e.g. "libx264 medium crf 20
+// 1920x1080@30".
+func (r RunStats) Settings() string {
+	s := r.Codec
+	if r.Preset != "" {
+		s += " " + r.Preset
+	}
+	return fmt.Sprintf("%s crf %d %dx%d@%d", s, r.CRF, r.Width, r.Height, r.FPS)
+}
+
+// SummarizeRun builds the RunStats of a render that started at started.
+// Skipped segments don't count.
+func SummarizeRun(cfg config.Config, started time.Time, concurrency int, results []render.Result) RunStats {
+	codec := cfg.Video.Codec
+	if codec == "" {
+		codec = "libx264"
+	}
+	run := RunStats{
+		StartedAt:   started,
+		WallS:       time.Since(started).Seconds(),
+		ConfigHash:  GlobalConfigHash(cfg),
+		Codec:       codec,
+		Preset:      cfg.Video.Preset,
+		CRF:         cfg.Video.CRF,
+		Width:       cfg.Video.Width,
+		Height:      cfg.Video.Height,
+		FPS:         cfg.Video.FPS,
+		Concurrency: max(concurrency, 1),
+	}
+	for _, res := range results {
+		switch {
+		case res.Err != nil:
+			run.Failed++
+		case !res.Skipped:
+			run.Rendered++
+			run.VideoS += res.OutputSeconds
+			run.EncodeS += res.Elapsed.Seconds()
+			run.Bytes += res.SizeBytes
+		}
+	}
+	return run
+}
+
+// RecordRun appends run to rs, dropping the oldest beyond MaxRuns. Runs that
+// rendered and failed nothing aren't worth keeping.
+func RecordRun(rs *RenderState, run RunStats) {
+	if run.Rendered == 0 && run.Failed == 0 {
+		return
+	}
+	rs.Runs = append(rs.Runs, run)
+	if len(rs.Runs) > MaxRuns {
+		rs.Runs = append([]RunStats(nil), rs.Runs[len(rs.Runs)-MaxRuns:]...)
+	}
+}
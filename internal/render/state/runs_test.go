@@ -0,0 +1,46 @@
+package state
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"powerhour/internal/config"
+	"powerhour/internal/render"
+)
+
+func TestSummarizeRun(t *testing.T) {
+	cfg := config.Default()
+	started := time.Now().Add(-time.Minute)
+	results := []render.Result{
+		{Elapsed: 20 * time.Second, OutputSeconds: 60, SizeBytes: 1000},
+		{Elapsed: 10 * time.Second, OutputSeconds: 30, SizeBytes: 500},
+		{Skipped: true},
+		{Err: errors.New("boom")},
+	}
+
+	run := SummarizeRun(cfg, started, 0, results)
+	if run.Rendered != 2 || run.Failed != 1 || run.VideoS != 90 || run.EncodeS != 30 || run.Bytes != 1500 {
+		t.Fatalf("run = %+v", run)
+	}
+	if run.Concurrency != 1 || run.ConfigHash != GlobalConfigHash(cfg) || run.WallS < 60 {
+		t.Fatalf("run = %+v", run)
+	}
+	if got, want := run.Settings(), "libx264 medium crf 20 1920x1080@30"; got != want {
+		t.Fatalf("Settings() = %q, want %q", got, want)
+	}
+}
+
+func TestRecordRunKeepsNewest(t *testing.T) {
+	rs := &RenderState{}
+	RecordRun(rs, RunStats{})
+	if len(rs.Runs) != 0 {
+		t.Fatal("empty run should not be recorded")
+	}
+	for i := range MaxRuns + 5 {
+		RecordRun(rs, RunStats{Rendered: i + 1})
+	}
+	if len(rs.Runs) != MaxRuns || rs.Runs[0].Rendered != 6 || rs.Runs[MaxRuns-1].Rendered != MaxRuns+5 {
+		t.Fatalf("kept %d runs, first %d", len(rs.Runs), rs.Runs[0].Rendered)
+	}
+}
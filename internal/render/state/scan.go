@@ -0,0 +1,52 @@
+package state
+
+import (
+	"runtime"
+	"sync"
+)
+
+// ScanProgress is told how many of total segments have been checked. It is
+// called from the scanning goroutines, one call at a time.
+type ScanProgress func(done, total int)
+
+// scanSegments calls fn for every index below n on a pool of one worker per
+// CPU. fn must only write to its own index of any shared slice.
+func scanSegments(n int, progress ScanProgress, fn func(i int)) {
+	workers := min(runtime.NumCPU(), n)
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
+			if progress != nil {
+				progress(i+1, n)
+			}
+		}
+		return
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		done int
+		next = make(chan int)
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				fn(i)
+				if progress != nil {
+					mu.Lock()
+					done++
+					progress(done, n)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+}
@@ -13,12 +13,25 @@ type SegmentState struct {
 	RenderedAt time.Time `json:"rendered_at"`
 	SourcePath string    `json:"source_path"`
 	DurationS  float64   `json:"duration_s"`
+	EncodeS    float64   `json:"encode_s,omitempty"`   // ffmpeg wall time
+	FPS        float64   `json:"fps,omitempty"`        // frames encoded per second
+	SizeBytes  int64     `json:"size_bytes,omitempty"` // output size
+	// SettingsHash is the SettingsHash of the config the segment was
+	// rendered with. Older state files lack it; see SettingsCurrent.
+	SettingsHash string `json:"settings_hash,omitempty"`
+	// InputParts are the SegmentInputParts hashes behind InputHash, so
+	// `diff` can say which inputs changed. Older state files lack them.
+	InputParts map[string]string `json:"input_parts,omitempty"`
 }
 
 // RenderState tracks render state across all segments for change detection.
 type RenderState struct {
-	GlobalConfigHash string                  `json:"global_config_hash"`
-	Segments         map[string]SegmentState `json:"segments"`
+	GlobalConfigHash string `json:"global_config_hash"`
+	// GlobalConfigParts are the GlobalConfigParts hashes behind
+	// GlobalConfigHash; see SetGlobalConfig.
+	GlobalConfigParts map[string]string       `json:"global_config_parts,omitempty"`
+	Segments          map[string]SegmentState `json:"segments"`
+	Runs              []RunStats              `json:"runs,omitempty"` // oldest first, see RecordRun
 }
 
 // Load reads render state from the given path. A missing or corrupt file
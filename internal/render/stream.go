@@ -0,0 +1,212 @@
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Stream target kinds.
+const (
+	StreamRTMP = "rtmp" // Twitch, YouTube Live and other RTMP ingests
+	StreamSRT  = "srt"  // SRT caller or listener, e.g. an OBS Media Source
+	StreamNDI  = "ndi"  // NDI source on the local network
+)
+
+// ndiMuxer is the ffmpeg output for NDI. Upstream ffmpeg dropped it, so
+// only some builds have it.
+const ndiMuxer = "libndi_newtek"
+
+// StreamTarget is where a stream goes.
+type StreamTarget struct {
+	Kind string
+	URL  string // for NDI, the source name
+}
+
+// ParseStreamTarget reads rtmp(s)://…, srt://… or ndi:<source name>.
+func ParseStreamTarget(s string) (StreamTarget, error) {
+	s = strings.TrimSpace(s)
+	if name, ok := strings.CutPrefix(s, "ndi:"); ok {
+		name = strings.TrimPrefix(name, "//")
+		if name == "" {
+			return StreamTarget{}, fmt.Errorf("ndi target needs a source name, e.g. ndi:Power Hour")
+		}
+		return StreamTarget{Kind: StreamNDI, URL: name}, nil
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return StreamTarget{}, fmt.Errorf("invalid stream target %q (use rtmp://, rtmps://, srt:// or ndi:<name>)", RedactStreamURL(s))
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "rtmp", "rtmps":
+		return StreamTarget{Kind: StreamRTMP, URL: s}, nil
+	case "srt":
+		return StreamTarget{Kind: StreamSRT, URL: s}, nil
+	}
+	return StreamTarget{}, fmt.Errorf("unsupported stream scheme %q (use rtmp://, rtmps://, srt:// or ndi:<name>)", u.Scheme)
+}
+
+// RedactStreamURL hides the stream key in an ingest URL: the last path
+// element and any query (SRT passphrases, streamid) become ****.
+func RedactStreamURL(target string) string {
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" {
+		return target
+	}
+	if u.RawQuery != "" {
+		u.RawQuery = "****"
+	}
+	if i := strings.LastIndex(u.Path, "/"); i >= 0 && i < len(u.Path)-1 && strings.Count(u.Path, "/") > 1 {
+		u.Path = u.Path[:i+1] + "****"
+		u.RawPath = u.Path
+	}
+	if u.User != nil {
+		u.User = url.User(u.User.Username())
+	}
+	return u.String()
+}
+
+// StreamOptions describes one ffmpeg streaming run.
+type StreamOptions struct {
+	Input        string  // a video file, or a concat list with ConcatList set
+	ConcatList   bool    // Input is an ffconcat list of segments
+	Start        float64 // seconds into the input to start from
+	VideoBitrate string  // e.g. 4500k
+	AudioBitrate string  // e.g. 160k
+}
+
+// BuildStreamArgs returns the ffmpeg arguments that play opts.Input in
+// real time to target. RTMP and SRT get H.264 and AAC with a keyframe every
+// two seconds, which ingests require; NDI gets uncompressed UYVY video and
+// PCM audio. Progress is written to stdout in ffmpeg's -progress format.
+func BuildStreamArgs(target StreamTarget, opts StreamOptions) []string {
+	args := []string{"-hide_banner", "-nostdin", "-loglevel", "error", "-re"}
+	if opts.Start > 0 {
+		args = append(args, "-ss", strconv.FormatFloat(opts.Start, 'f', 3, 64))
+	}
+	if opts.ConcatList {
+		args = append(args, "-f", "concat", "-safe", "0")
+	}
+	args = append(args, "-i", opts.Input, "-map", "0:v:0", "-map", "0:a:0?")
+
+	if target.Kind == StreamNDI {
+		args = append(args, "-c:v", "rawvideo", "-pix_fmt", "uyvy422", "-c:a", "pcm_s16le")
+		return append(args, "-progress", "pipe:1", "-f", ndiMuxer, target.URL)
+	}
+
+	vb := opts.VideoBitrate
+	if vb == "" {
+		vb = "4500k"
+	}
+	ab := opts.AudioBitrate
+	if ab == "" {
+		ab = "160k"
+	}
+	args = append(args,
+		"-c:v", "libx264", "-preset", "veryfast", "-tune", "zerolatency",
+		"-b:v", vb, "-maxrate", vb, "-bufsize", doubleBitrate(vb),
+		"-pix_fmt", "yuv420p",
+		"-force_key_frames", "expr:gte(t,n_forced*2)",
+		"-c:a", "aac", "-b:a", ab, "-ar", "48000", "-ac", "2",
+		"-progress", "pipe:1",
+	)
+	if target.Kind == StreamRTMP {
+		return append(args, "-f", "flv", "-flvflags", "no_duration_filesize", target.URL)
+	}
+	return append(args, "-f", "mpegts", target.URL)
+}
+
+// doubleBitrate returns twice a bitrate like 4500k, for the rate control
+// buffer; values it can't read are returned as is.
+func doubleBitrate(rate string) string {
+	num := strings.TrimRight(rate, "kKmM")
+	n, err := strconv.Atoi(num)
+	if err != nil {
+		return rate
+	}
+	return strconv.Itoa(n*2) + rate[len(num):]
+}
+
+// HasMuxer reports whether ffmpeg can write the named format.
+func HasMuxer(ctx context.Context, ffmpegPath, name string) bool {
+	out, err := exec.CommandContext(ctx, ffmpegPath, "-hide_banner", "-muxers").Output()
+	if err != nil {
+		return false
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && strings.Contains(fields[0], "E") && fields[1] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// NDIAvailable reports whether ffmpeg was built with NDI output.
+func NDIAvailable(ctx context.Context, ffmpegPath string) bool {
+	return HasMuxer(ctx, ffmpegPath, ndiMuxer)
+}
+
+// RunStream runs one ffmpeg streaming pass and calls onTime with the
+// output position, in seconds from opts.Start, as ffmpeg reports it. The
+// returned position is the last one reported. ffmpeg's error output is
+// kept in the error, with the stream key redacted.
+func RunStream(ctx context.Context, ffmpegPath string, target StreamTarget, opts StreamOptions, onTime func(float64)) (float64, error) {
+	cmd := exec.CommandContext(ctx, ffmpegPath, BuildStreamArgs(target, opts)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return 0, err
+	}
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("start ffmpeg: %w", err)
+	}
+	var last float64
+	readStreamProgress(stdout, func(t float64) {
+		last = t
+		if onTime != nil {
+			onTime(t)
+		}
+	})
+	if err := cmd.Wait(); err != nil {
+		msg := strings.ReplaceAll(lastLines(stderr.String(), 3), target.URL, RedactStreamURL(target.URL))
+		if msg = strings.TrimSpace(msg); msg != "" {
+			return last, fmt.Errorf("ffmpeg: %w: %s", err, msg)
+		}
+		return last, fmt.Errorf("ffmpeg: %w", err)
+	}
+	return last, nil
+}
+
+// readStreamProgress reads ffmpeg -progress output until EOF and reports
+// each out_time_us in seconds.
+func readStreamProgress(r io.Reader, onTime func(float64)) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		key, val, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok || key != "out_time_us" {
+			continue
+		}
+		us, err := strconv.ParseInt(val, 10, 64)
+		if err != nil || us < 0 {
+			continue
+		}
+		onTime(float64(us) / 1e6)
+	}
+}
+
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "; ")
+}
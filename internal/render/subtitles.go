@@ -0,0 +1,111 @@
+package render
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"powerhour/internal/tools"
+)
+
+// SubtitleCue is one caption on the final video's timeline. Text may span
+// several lines separated by "\n".
+type SubtitleCue struct {
+	Start float64
+	End   float64
+	Text  string
+}
+
+// SubtitleFormats lists the supported subtitle formats.
+var SubtitleFormats = []string{"srt", "ass"}
+
+// WriteSRT writes cues as a SubRip file.
+func WriteSRT(w io.Writer, cues []SubtitleCue) error {
+	bw := bufio.NewWriter(w)
+	for i, cue := range cues {
+		fmt.Fprintf(bw, "%d\n%s --> %s\n%s\n\n", i+1, srtTime(cue.Start), srtTime(cue.End), strings.TrimSpace(cue.Text))
+	}
+	return bw.Flush()
+}
+
+// WriteASS writes cues as an Advanced SubStation Alpha file with a single
+// bottom-left style sized for a width x height video.
+func WriteASS(w io.Writer, cues []SubtitleCue, width, height int) error {
+	if width <= 0 || height <= 0 {
+		width, height = 1920, 1080
+	}
+	fontSize := height / 18
+	margin := height / 20
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "[Script Info]\nScriptType: v4.00+\nPlayResX: %d\nPlayResY: %d\nScaledBorderAndShadow: yes\n\n", width, height)
+	fmt.Fprintf(bw, "[V4+ Styles]\n")
+	fmt.Fprintf(bw, "Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding\n")
+	fmt.Fprintf(bw, "Style: Default,Arial,%d,&H00FFFFFF,&H00FFFFFF,&H00000000,&H80000000,-1,0,0,0,100,100,0,0,1,3,1,1,%d,%d,%d,1\n\n", fontSize, margin, margin, margin)
+	fmt.Fprintf(bw, "[Events]\nFormat: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\n")
+	for _, cue := range cues {
+		fmt.Fprintf(bw, "Dialogue: 0,%s,%s,Default,,0,0,0,,%s\n", assTime(cue.Start), assTime(cue.End), assText(cue.Text))
+	}
+	return bw.Flush()
+}
+
+// MuxSubtitles copies videoPath's streams plus the subtitle file into
+// outputPath. MP4 and MOV outputs carry the track as mov_text, which keeps
+// the text but drops ASS styling; MKV keeps the subtitle file's format.
+func MuxSubtitles(ctx context.Context, videoPath, subsPath, outputPath string, stdout, stderr io.Writer) error {
+	ffmpegPath, err := tools.Lookup("ffmpeg")
+	if err != nil {
+		return fmt.Errorf("locate ffmpeg: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
+		return fmt.Errorf("prepare output dir: %w", err)
+	}
+	if err := runFFmpeg(ctx, ffmpegPath, BuildSubtitleMuxArgs(videoPath, subsPath, outputPath), stdout, stderr); err != nil {
+		return fmt.Errorf("mux subtitles: %w", err)
+	}
+	return nil
+}
+
+// BuildSubtitleMuxArgs returns the ffmpeg arguments for MuxSubtitles. Any
+// existing subtitle streams in the video are replaced.
+func BuildSubtitleMuxArgs(videoPath, subsPath, outputPath string) []string {
+	codec := "copy"
+	switch strings.ToLower(filepath.Ext(outputPath)) {
+	case ".mp4", ".m4v", ".mov":
+		codec = "mov_text"
+	}
+	return []string{
+		"-y",
+		"-i", videoPath,
+		"-i", subsPath,
+		"-map", "0:v", "-map", "0:a?", "-map", "1:0",
+		"-c", "copy",
+		"-c:s", codec,
+		"-metadata:s:s:0", "language=eng",
+		"-disposition:s:0", "default",
+		outputPath,
+	}
+}
+
+// srtTime formats seconds as HH:MM:SS,mmm.
+func srtTime(seconds float64) string {
+	ms := int64(math.Round(math.Max(seconds, 0) * 1000))
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+// assTime formats seconds as H:MM:SS.cc.
+func assTime(seconds float64) string {
+	cs := int64(math.Round(math.Max(seconds, 0) * 100))
+	return fmt.Sprintf("%d:%02d:%02d.%02d", cs/360000, cs/6000%60, cs/100%60, cs%100)
+}
+
+// assText escapes override braces and turns newlines into ASS line breaks.
+func assText(s string) string {
+	s = strings.TrimSpace(s)
+	s = strings.NewReplacer("{", "(", "}", ")", "\r\n", `\N`, "\n", `\N`).Replace(s)
+	return s
+}
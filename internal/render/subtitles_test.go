@@ -0,0 +1,62 @@
+package render
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWriteSRT(t *testing.T) {
+	var buf bytes.Buffer
+	cues := []SubtitleCue{
+		{Start: 0, End: 8, Text: "Song\nBand"},
+		{Start: 3661.5, End: 3669.25, Text: "Other"},
+	}
+	if err := WriteSRT(&buf, cues); err != nil {
+		t.Fatal(err)
+	}
+	want := "1\n00:00:00,000 --> 00:00:08,000\nSong\nBand\n\n2\n01:01:01,500 --> 01:01:09,250\nOther\n\n"
+	if buf.String() != want {
+		t.Errorf("WriteSRT =\n%q\nwant\n%q", buf.String(), want)
+	}
+}
+
+func TestWriteASS(t *testing.T) {
+	var buf bytes.Buffer
+	cues := []SubtitleCue{{Start: 61.5, End: 69.5, Text: "Song {live}\nBand"}}
+	if err := WriteASS(&buf, cues, 1280, 720); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"PlayResX: 1280\nPlayResY: 720\n",
+		"Style: Default,Arial,40,",
+		`Dialogue: 0,0:01:01.50,0:01:09.50,Default,,0,0,0,,Song (live)\NBand`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("ASS missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestBuildSubtitleMuxArgs(t *testing.T) {
+	tests := []struct {
+		output string
+		codec  string
+	}{
+		{"/p/powerhour.mp4", "mov_text"},
+		{"/p/powerhour.MOV", "mov_text"},
+		{"/p/powerhour.mkv", "copy"},
+	}
+	for _, tt := range tests {
+		args := BuildSubtitleMuxArgs("/p/in", "/p/powerhour.srt", tt.output)
+		i := slices.Index(args, "-c:s")
+		if i < 0 || args[i+1] != tt.codec {
+			t.Errorf("%s: -c:s = %v, want %s", tt.output, args, tt.codec)
+		}
+		if args[len(args)-1] != tt.output {
+			t.Errorf("%s: output not last: %v", tt.output, args)
+		}
+	}
+}
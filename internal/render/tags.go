@@ -0,0 +1,69 @@
+package render
+
+import (
+	"strconv"
+	"strings"
+
+	"powerhour/internal/config"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+)
+
+// SegmentTags are the container tags written to a segment, so a player
+// opening the segments directory lists it like an album. Empty tags are
+// left out.
+type SegmentTags struct {
+	Title  string
+	Artist string
+	Album  string // the project name
+	Track  int    // position in the timeline; 0 when the segment isn't on it
+}
+
+// NewSegmentTags tags clip with its row's title (or name) and artist.
+func NewSegmentTags(clip project.Clip, album string, track int) SegmentTags {
+	title := strings.TrimSpace(clip.Row.Title)
+	if title == "" {
+		title = strings.TrimSpace(clip.Row.Name)
+	}
+	return SegmentTags{
+		Title:  title,
+		Artist: strings.TrimSpace(clip.Row.Artist),
+		Album:  strings.TrimSpace(album),
+		Track:  track,
+	}
+}
+
+// Args returns the ffmpeg -metadata options for the tags.
+func (t SegmentTags) Args() []string {
+	var args []string
+	for _, tag := range []struct{ key, value string }{
+		{"title", t.Title},
+		{"artist", t.Artist},
+		{"album", t.Album},
+	} {
+		if tag.value != "" {
+			args = append(args, "-metadata", tag.key+"="+tag.value)
+		}
+	}
+	if t.Track > 0 {
+		args = append(args, "-metadata", "track="+strconv.Itoa(t.Track))
+	}
+	return args
+}
+
+// TimelineTracks numbers each segment path by where it first plays in the
+// timeline, from 1, following ResolveTimelineSegments. Interleaved clips
+// that repeat keep their first number.
+func TimelineTracks(pp paths.ProjectPaths, cfg config.Config, collections map[string]project.Collection) (map[string]int, error) {
+	ordered, err := ResolveTimelineSegments(pp, cfg, collections)
+	if err != nil {
+		return nil, err
+	}
+	tracks := make(map[string]int, len(ordered))
+	for i, seg := range ordered {
+		if _, ok := tracks[seg.Path]; !ok {
+			tracks[seg.Path] = i + 1
+		}
+	}
+	return tracks, nil
+}
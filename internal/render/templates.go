@@ -2,10 +2,14 @@ package render
 
 import (
 	"fmt"
+	"maps"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
+	"powerhour/internal/config"
+	"powerhour/internal/paths"
 	"powerhour/internal/project"
 )
 
@@ -20,7 +24,62 @@ func SegmentBaseName(template string, seg Segment) string {
 	if base == "" {
 		return sanitizeSegment(fallbackSegmentBase(seg.Clip))
 	}
-	return base
+	return paths.PortableName(base)
+}
+
+// CollectionSegmentPath returns where a collection's segment is rendered:
+// the collection's output_template under segmentsDir when set, otherwise
+// outputDir (relative to segmentsDir unless absolute) and the global segment
+// template. Window clips of one row get a _w<n> suffix.
+func CollectionSegmentPath(cfg config.Config, segmentsDir, collection, outputDir string, seg Segment) string {
+	suffix := ".mp4"
+	if seg.Clip.Window > 0 {
+		suffix = fmt.Sprintf("_w%d.mp4", seg.Clip.Window)
+	}
+	coll := cfg.Collections[collection]
+	if tmpl := strings.TrimSpace(coll.OutputTemplate); tmpl != "" {
+		return filepath.Join(segmentsDir, SegmentRelPath(tmpl, seg, collectionTemplateValues(collection, coll))+suffix)
+	}
+	if !filepath.IsAbs(outputDir) {
+		outputDir = filepath.Join(segmentsDir, outputDir)
+	}
+	return filepath.Join(outputDir, SegmentBaseName(cfg.SegmentFilenameTemplate(), seg)+suffix)
+}
+
+// SegmentRelPath renders a template that may contain "/" into a relative
+// path. Each directory and the file name are sanitized like SegmentBaseName;
+// directories that render empty are dropped, and an empty file name falls
+// back to the default base name. extra adds or overrides tokens.
+func SegmentRelPath(template string, seg Segment, extra map[string]string) string {
+	values := segmentTemplateValues(seg)
+	maps.Copy(values, extra)
+	parts := strings.Split(filepath.ToSlash(strings.TrimSpace(template)), "/")
+	var out []string
+	for i, part := range parts {
+		name := sanitizeSegment(applySegmentTemplate(part, values))
+		if i == len(parts)-1 && name == "" {
+			name = sanitizeSegment(fallbackSegmentBase(seg.Clip))
+		}
+		// sanitizeSegment trims leading dots, so ".." can't survive.
+		if name != "" {
+			out = append(out, paths.PortableName(name))
+		}
+	}
+	return filepath.Join(out...)
+}
+
+func collectionTemplateValues(collection string, coll config.CollectionConfig) map[string]string {
+	profile := "none"
+	if len(coll.Overlays) > 0 {
+		if t := strings.TrimSpace(coll.Overlays[0].Type); t != "" {
+			profile = t
+		}
+	}
+	return map[string]string{
+		"COLLECTION":      sanitizeSegment(collection),
+		"SAFE_COLLECTION": safeFileSlug(collection),
+		"PROFILE":         sanitizeSegment(profile),
+	}
 }
 
 func fallbackSegmentBase(clip project.Clip) string {
@@ -118,6 +177,10 @@ func segmentTemplateValues(seg Segment) map[string]string {
 		values["SOURCE"] = sanitizeSegment(entry.Source)
 	}
 
+	if date := uploadDate(entry.UploadDate); date != "" {
+		values["DATE"] = date
+	}
+
 	if entry.CachedPath != "" {
 		base := strings.TrimSuffix(filepath.Base(entry.CachedPath), filepath.Ext(entry.CachedPath))
 		values["SOURCE_BASENAME"] = sanitizeSegment(base)
@@ -143,6 +206,16 @@ func segmentTemplateValues(seg Segment) map[string]string {
 	return values
 }
 
+// uploadDate formats yt-dlp's YYYYMMDD upload_date as YYYY-MM-DD; anything
+// else is dropped.
+func uploadDate(raw string) string {
+	t, err := time.Parse("20060102", strings.TrimSpace(raw))
+	if err != nil {
+		return ""
+	}
+	return t.Format(time.DateOnly)
+}
+
 func applySegmentTemplate(template string, values map[string]string) string {
 	var builder strings.Builder
 	for i := 0; i < len(template); {
@@ -209,14 +282,14 @@ func ValidSegmentTokens() []string {
 		"SEQUENCE", "SEQUENCE_RAW",
 		"SOURCE_KIND", "SOURCE_PATH", "SAFE_SOURCE_PATH",
 		"ID", "SAFE_ID",
-		"SOURCE",
+		"SOURCE", "DATE",
 		"SOURCE_BASENAME", "SAFE_SOURCE_BASENAME",
 		"CACHE_BASENAME", "SAFE_CACHE_BASENAME",
 	}
 }
 
 func sanitizeSegment(value string) string {
-	value = strings.TrimSpace(value)
+	value = transliterate(strings.TrimSpace(value))
 	if value == "" {
 		return ""
 	}
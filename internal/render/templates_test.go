@@ -1,6 +1,8 @@
 package render
 
 import (
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -50,3 +52,79 @@ func TestSegmentBaseNameFallback(t *testing.T) {
 		t.Fatalf("unexpected fallback base: %q", base)
 	}
 }
+
+func TestCollectionSegmentPath(t *testing.T) {
+	cfg := config.Default()
+	cfg.Collections = map[string]config.CollectionConfig{
+		"Big Songs": {
+			OutputTemplate: "$COLLECTION/$PROFILE/$INDEX_PAD3_$SAFE_TITLE",
+			Overlays:       []config.OverlayEntry{{Type: "song-info"}},
+		},
+		"empty-dir": {OutputTemplate: "$ARTIST/$INDEX_PAD3"},
+		"no-name":   {OutputTemplate: "clips/$ARTIST"},
+		"legacy":    {},
+	}
+	seg := newTestSegment(cfg, csvplan.Row{Index: 7, Title: "Fellow Feeling", DurationSeconds: 60})
+
+	tests := []struct {
+		collection string
+		outputDir  string
+		want       string
+	}{
+		{"Big Songs", "ignored", "/segs/Big_Songs/song-info/007_fellow-feeling.mp4"},
+		{"empty-dir", "", "/segs/007.mp4"},
+		{"no-name", "", "/segs/clips/song_007_fellow-feeling.mp4"},
+		{"legacy", "songs", "/segs/songs/" + SegmentBaseName(cfg.SegmentFilenameTemplate(), seg) + ".mp4"},
+		{"legacy", "/abs/out", "/abs/out/" + SegmentBaseName(cfg.SegmentFilenameTemplate(), seg) + ".mp4"},
+	}
+	for _, tt := range tests {
+		got := CollectionSegmentPath(cfg, "/segs", tt.collection, tt.outputDir, seg)
+		if filepath.ToSlash(got) != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.collection, got, tt.want)
+		}
+	}
+
+	// Window clips of one row get their own file.
+	seg.Clip.Window = 2
+	if got := filepath.ToSlash(CollectionSegmentPath(cfg, "/segs", "Big Songs", "", seg)); got != "/segs/Big_Songs/song-info/007_fellow-feeling_w2.mp4" {
+		t.Errorf("window clip: got %q", got)
+	}
+}
+
+func TestSegmentRelPathCannotEscape(t *testing.T) {
+	cfg := config.Default()
+	seg := newTestSegment(cfg, csvplan.Row{Index: 1, Title: "..", DurationSeconds: 60})
+	got := filepath.ToSlash(SegmentRelPath("$TITLE/../$INDEX", seg, nil))
+	if strings.Contains(got, "..") || strings.HasPrefix(got, "/") {
+		t.Fatalf("path escaped the segments dir: %q", got)
+	}
+}
+
+func TestSegmentBaseNameTransliterates(t *testing.T) {
+	cfg := config.Default()
+	row := csvplan.Row{Index: 3, Title: "Straße Café", Artist: "Sigur Rós", Name: "Æsa Łukasz"}
+	seg := newTestSegment(cfg, row)
+	seg.Entry = cache.Entry{UploadDate: "20190412"}
+
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"$SAFE_TITLE", "strasse-cafe"},
+		{"$TITLE", "Strasse_Cafe"},
+		{"$SAFE_ARTIST", "sigur-ros"},
+		{"$SAFE_NAME", "aesa-lukasz"},
+		{"$NAME", "AEsa_Lukasz"},
+		{"$DATE_$INDEX", "2019-04-12_003"},
+	}
+	for _, tt := range tests {
+		if got := SegmentBaseName(tt.template, seg); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.template, got, tt.want)
+		}
+	}
+
+	seg.Entry.UploadDate = "NA"
+	if got := SegmentBaseName("$DATE_$INDEX", seg); got != "003" {
+		t.Errorf("unparseable upload date: got %q", got)
+	}
+}
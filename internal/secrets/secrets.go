@@ -0,0 +1,164 @@
+// Package secrets resolves ${NAME} references in configuration values from
+// the environment or the OS keychain, and keeps resolved values out of logs
+// and other persisted output.
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// KeychainService is the service name secrets are stored under in the OS
+// keychain (macOS Keychain, libsecret on Linux).
+const KeychainService = "powerhour"
+
+// Placeholder replaces secret values in redacted output.
+const Placeholder = "[REDACTED]"
+
+// ErrNotFound is returned when a referenced secret is neither set in the
+// environment nor present in the OS keychain.
+var ErrNotFound = errors.New("secret not found")
+
+var refPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// lookupKeychain is a variable so tests can stub the OS keychain.
+var lookupKeychain = keychainLookup
+
+var (
+	mu       sync.RWMutex
+	resolved = map[string]struct{}{}
+)
+
+// References returns the secret names referenced by value, in order of first
+// appearance.
+func References(value string) []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, m := range refPattern.FindAllStringSubmatch(value, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
+
+// Lookup resolves a single secret by name. The environment takes precedence
+// over the OS keychain. Resolved values are registered for redaction.
+func Lookup(name string) (string, error) {
+	if v, ok := os.LookupEnv(name); ok && v != "" {
+		register(v)
+		return v, nil
+	}
+	v, err := lookupKeychain(name)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	register(v)
+	return v, nil
+}
+
+// Expand replaces every ${NAME} reference in value with the resolved secret.
+// Values without references are returned unchanged.
+func Expand(value string) (string, error) {
+	if !strings.Contains(value, "${") {
+		return value, nil
+	}
+	var firstErr error
+	out := refPattern.ReplaceAllStringFunc(value, func(ref string) string {
+		name := refPattern.FindStringSubmatch(ref)[1]
+		v, err := Lookup(name)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			return ref
+		}
+		return v
+	})
+	if firstErr != nil {
+		return "", firstErr
+	}
+	return out, nil
+}
+
+// Redact replaces every secret value resolved during this process with
+// Placeholder.
+func Redact(s string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	if len(resolved) == 0 || s == "" {
+		return s
+	}
+	// Replace longer values first so a secret containing another secret is
+	// never partially revealed.
+	values := make([]string, 0, len(resolved))
+	for v := range resolved {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+	for _, v := range values {
+		s = strings.ReplaceAll(s, v, Placeholder)
+	}
+	return s
+}
+
+// NewRedactingWriter wraps w so resolved secret values are scrubbed from
+// everything written through it.
+func NewRedactingWriter(w io.Writer) io.Writer {
+	return redactingWriter{w: w}
+}
+
+type redactingWriter struct {
+	w io.Writer
+}
+
+func (r redactingWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(r.w, Redact(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func register(value string) {
+	// Very short values would redact unrelated text; they are not plausible
+	// credentials anyway.
+	if len(value) < 4 {
+		return
+	}
+	mu.Lock()
+	resolved[value] = struct{}{}
+	mu.Unlock()
+}
+
+func keychainLookup(name string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", KeychainService, "-a", name, "-w")
+	case "linux":
+		if _, err := exec.LookPath("secret-tool"); err != nil {
+			return "", ErrNotFound
+		}
+		cmd = exec.Command("secret-tool", "lookup", "service", KeychainService, "account", name)
+	default:
+		return "", ErrNotFound
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return "", ErrNotFound
+	}
+	v := strings.TrimRight(string(out), "\r\n")
+	if v == "" {
+		return "", ErrNotFound
+	}
+	return v, nil
+}
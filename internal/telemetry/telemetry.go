@@ -0,0 +1,176 @@
+// Package telemetry exports OpenTelemetry traces and metrics over OTLP/HTTP
+// (telemetry: in powerhour.yaml, or the standard OTEL_EXPORTER_OTLP_*
+// variables) so long fetch, render and concat batches can be followed in
+// Jaeger, Tempo or Grafana. Until Setup installs an exporter, spans and
+// measurements go to OpenTelemetry's no-op providers and cost nothing.
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"powerhour/internal/config"
+	"powerhour/internal/secrets"
+)
+
+// instrumentationName names the tracer and meter.
+const instrumentationName = "powerhour"
+
+// DurationMetric is the histogram of every span's duration in seconds, by
+// operation (the span name) and outcome (ok or error).
+const DurationMetric = "powerhour.operation.duration"
+
+// Enabled reports whether runs should export: an endpoint is configured,
+// here or in OTEL_EXPORTER_OTLP_ENDPOINT, and OTEL_SDK_DISABLED isn't set.
+func Enabled(cfg config.TelemetryConfig) bool {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return false
+	}
+	return strings.TrimSpace(cfg.Endpoint) != "" || os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != ""
+}
+
+// Setup installs OTLP/HTTP trace and metric exporters as the global
+// providers when cfg is Enabled. The returned shutdown flushes and stops
+// them; it is a no-op when nothing was installed. Export errors, such as an
+// unreachable collector, go to logf instead of interrupting the run.
+func Setup(ctx context.Context, cfg config.TelemetryConfig, logf func(string, ...any)) (shutdown func(context.Context) error, err error) {
+	noop := func(context.Context) error { return nil }
+	if !Enabled(cfg) {
+		return noop, nil
+	}
+
+	var traceOpts []otlptracehttp.Option
+	var metricOpts []otlpmetrichttp.Option
+	if endpoint := strings.TrimSpace(cfg.Endpoint); endpoint != "" {
+		endpoint, err := secrets.Expand(endpoint)
+		if err != nil {
+			return noop, fmt.Errorf("telemetry.endpoint: %w", err)
+		}
+		// Like OTEL_EXPORTER_OTLP_ENDPOINT, the endpoint is the collector's
+		// base URL and each signal has its own path below it.
+		base := strings.TrimRight(endpoint, "/")
+		traceOpts = append(traceOpts, otlptracehttp.WithEndpointURL(base+"/v1/traces"))
+		metricOpts = append(metricOpts, otlpmetrichttp.WithEndpointURL(base+"/v1/metrics"))
+	}
+	if len(cfg.Headers) > 0 {
+		headers := make(map[string]string, len(cfg.Headers))
+		for key, value := range cfg.Headers {
+			expanded, err := secrets.Expand(value)
+			if err != nil {
+				return noop, fmt.Errorf("telemetry.headers.%s: %w", key, err)
+			}
+			headers[key] = expanded
+		}
+		traceOpts = append(traceOpts, otlptracehttp.WithHeaders(headers))
+		metricOpts = append(metricOpts, otlpmetrichttp.WithHeaders(headers))
+	}
+
+	res, err := newResource(cfg)
+	if err != nil {
+		return noop, err
+	}
+	traceExporter, err := otlptracehttp.New(ctx, traceOpts...)
+	if err != nil {
+		return noop, fmt.Errorf("create trace exporter: %w", err)
+	}
+	metricExporter, err := otlpmetrichttp.New(ctx, metricOpts...)
+	if err != nil {
+		_ = traceExporter.Shutdown(ctx)
+		return noop, fmt.Errorf("create metric exporter: %w", err)
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(traceExporter),
+		sdktrace.WithResource(res),
+	)
+	meterProvider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter)),
+		sdkmetric.WithResource(res),
+	)
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetMeterProvider(meterProvider)
+	if logf != nil {
+		otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+			logf("telemetry: %v", err)
+		}))
+	}
+
+	return func(ctx context.Context) error {
+		return errors.Join(tracerProvider.Shutdown(ctx), meterProvider.Shutdown(ctx))
+	}, nil
+}
+
+// newResource describes this process. OTEL_SERVICE_NAME and
+// OTEL_RESOURCE_ATTRIBUTES apply unless service_name is configured.
+func newResource(cfg config.TelemetryConfig) (*resource.Resource, error) {
+	name := strings.TrimSpace(cfg.ServiceName)
+	if name == "" && os.Getenv("OTEL_SERVICE_NAME") == "" {
+		name = "powerhour"
+	}
+	if name == "" {
+		return resource.Default(), nil
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(attribute.String("service.name", name)))
+	if err != nil {
+		return nil, fmt.Errorf("telemetry resource: %w", err)
+	}
+	return res, nil
+}
+
+// Span is a running operation. End it exactly once.
+type Span struct {
+	span  trace.Span
+	name  string
+	start time.Time
+}
+
+// Start begins a span named name as a child of any span in ctx, returning
+// the context to pass to the work it covers.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, *Span) {
+	ctx, span := otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+	return ctx, &Span{span: span, name: name, start: time.Now()}
+}
+
+// SetAttributes adds attributes learned while the operation ran.
+func (s *Span) SetAttributes(attrs ...attribute.KeyValue) {
+	s.span.SetAttributes(attrs...)
+}
+
+// End finishes the span, marking it failed when err is non-nil, and
+// records its duration in DurationMetric.
+func (s *Span) End(err error) {
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+
+	histogram, herr := otel.Meter(instrumentationName).Float64Histogram(DurationMetric,
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of powerhour operations: commands, source resolution, tool runs and segment renders"),
+	)
+	if herr != nil {
+		return
+	}
+	histogram.Record(context.Background(), time.Since(s.start).Seconds(), metric.WithAttributes(
+		attribute.String("operation", s.name),
+		attribute.String("outcome", outcome),
+	))
+}
@@ -0,0 +1,107 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"powerhour/internal/config"
+)
+
+func TestEnabled(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_SDK_DISABLED", "")
+	if Enabled(config.TelemetryConfig{}) {
+		t.Error("enabled without an endpoint")
+	}
+	if !Enabled(config.TelemetryConfig{Endpoint: "http://localhost:4318"}) {
+		t.Error("not enabled with telemetry.endpoint")
+	}
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://collector:4318")
+	if !Enabled(config.TelemetryConfig{}) {
+		t.Error("not enabled with OTEL_EXPORTER_OTLP_ENDPOINT")
+	}
+
+	t.Setenv("OTEL_SDK_DISABLED", "true")
+	if Enabled(config.TelemetryConfig{Endpoint: "http://localhost:4318"}) {
+		t.Error("enabled with OTEL_SDK_DISABLED=true")
+	}
+}
+
+func TestSetupDisabledInstallsNothing(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	before := otel.GetTracerProvider()
+	shutdown, err := Setup(context.Background(), config.TelemetryConfig{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if otel.GetTracerProvider() != before {
+		t.Error("Setup replaced the tracer provider without an endpoint")
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown: %v", err)
+	}
+}
+
+func TestSpanRecordsTraceAndDuration(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	prevTracer, prevMeter := otel.GetTracerProvider(), otel.GetMeterProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevTracer)
+		otel.SetMeterProvider(prevMeter)
+	})
+
+	ctx, parent := Start(context.Background(), "powerhour render")
+	_, child := Start(ctx, "exec ffmpeg", attribute.String("process.executable.name", "ffmpeg"))
+	child.SetAttributes(attribute.Int("process.exit.code", 1))
+	child.End(errors.New("exit status 1"))
+	parent.End(nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	exec, render := spans[0], spans[1]
+	if exec.Name() != "exec ffmpeg" || exec.Parent().SpanID() != render.SpanContext().SpanID() {
+		t.Errorf("exec span %q isn't a child of %q", exec.Name(), render.Name())
+	}
+	if exec.Status().Code != codes.Error || render.Status().Code == codes.Error {
+		t.Errorf("statuses = %v, %v", exec.Status(), render.Status())
+	}
+	if len(exec.Attributes()) != 2 {
+		t.Errorf("exec attributes = %v", exec.Attributes())
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	outcomes := map[string]string{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != DurationMetric {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Histogram[float64]).DataPoints {
+				op, _ := dp.Attributes.Value("operation")
+				outcome, _ := dp.Attributes.Value("outcome")
+				outcomes[op.AsString()] = outcome.AsString()
+			}
+		}
+	}
+	if outcomes["exec ffmpeg"] != "error" || outcomes["powerhour render"] != "ok" {
+		t.Errorf("duration outcomes = %v", outcomes)
+	}
+}
@@ -0,0 +1,170 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const autoUpdateStateFile = "auto_update.json"
+
+// autoUpdateChannels maps tools.<name>.auto_update values to check intervals.
+var autoUpdateChannels = map[string]time.Duration{
+	"daily":  24 * time.Hour,
+	"weekly": 7 * 24 * time.Hour,
+}
+
+// AutoUpdateChannels returns the accepted auto_update values (excluding "off").
+func AutoUpdateChannels() []string {
+	return []string{"daily", "weekly"}
+}
+
+// AutoUpdateInterval returns the check interval for channel. ok is false for
+// empty, "off", or unknown channels.
+func AutoUpdateInterval(channel string) (time.Duration, bool) {
+	d, ok := autoUpdateChannels[strings.ToLower(strings.TrimSpace(channel))]
+	return d, ok
+}
+
+// AutoUpdateResult describes the outcome of an auto-update check.
+type AutoUpdateResult struct {
+	Tool    string
+	Checked bool   // false when the channel interval has not elapsed yet
+	From    string // version before the update
+	To      string // installed version; empty when already current
+}
+
+// Updated reports whether a newer version was installed.
+func (r AutoUpdateResult) Updated() bool {
+	return r.To != "" && r.To != r.From
+}
+
+type autoUpdateState struct {
+	LastChecked map[string]time.Time `json:"last_checked"`
+}
+
+// AutoUpdate checks for a newer release of a powerhour-managed tool once per
+// channel interval and installs it into the tool cache. Tools installed by a
+// package manager or found on PATH are left alone — their owner updates them.
+func AutoUpdate(ctx context.Context, toolName, channel string) (AutoUpdateResult, error) {
+	result := AutoUpdateResult{Tool: toolName}
+	interval, ok := AutoUpdateInterval(channel)
+	if !ok {
+		return result, nil
+	}
+
+	state := loadAutoUpdateState()
+	if last, ok := state.LastChecked[toolName]; ok && time.Since(last) < interval {
+		return result, nil
+	}
+
+	current, err := currentStatus(ctx, toolName)
+	if err != nil {
+		return result, err
+	}
+	if current.Source != SourceCache {
+		return result, nil
+	}
+	result.Checked = true
+	result.From = current.Version
+
+	// Bypass the 1h release cache: this check runs at most once per interval.
+	spec, err := fetchDynamicRelease(ctx, toolName, "")
+	if err != nil {
+		return result, fmt.Errorf("check latest %s: %w", toolName, err)
+	}
+	cacheLatestRelease(toolName, spec)
+
+	state.LastChecked[toolName] = time.Now()
+	saveAutoUpdateState(state)
+
+	if !versionNewer(spec.Version, current.Version) {
+		return result, nil
+	}
+
+	installed, err := Install(ctx, toolName, spec.Version, InstallOptions{Force: true, Version: spec.Version})
+	if err != nil {
+		return result, fmt.Errorf("update %s to %s: %w", toolName, spec.Version, err)
+	}
+	ClearUpdateNotice(toolName)
+	result.To = installed.Version
+	return result, nil
+}
+
+func autoUpdateStatePath() (string, error) {
+	root, err := cacheRoot()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, autoUpdateStateFile), nil
+}
+
+func loadAutoUpdateState() autoUpdateState {
+	state := autoUpdateState{LastChecked: map[string]time.Time{}}
+	path, err := autoUpdateStatePath()
+	if err != nil {
+		return state
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return state
+	}
+	if err := json.Unmarshal(data, &state); err != nil || state.LastChecked == nil {
+		return autoUpdateState{LastChecked: map[string]time.Time{}}
+	}
+	return state
+}
+
+func saveAutoUpdateState(state autoUpdateState) {
+	path, err := autoUpdateStatePath()
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0o644)
+}
+
+type contextKeyProxy struct{}
+
+// WithProxy annotates the context with a proxy URL used for release lookups
+// and downloads, so tool updates follow the same network path as yt-dlp.
+func WithProxy(ctx context.Context, proxy string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	proxy = strings.TrimSpace(proxy)
+	if proxy == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKeyProxy{}, proxy)
+}
+
+// httpClient returns a client honoring any proxy set via WithProxy.
+func httpClient(ctx context.Context, timeout time.Duration) *http.Client {
+	client := &http.Client{Timeout: timeout}
+	if ctx == nil {
+		return client
+	}
+	proxy, _ := ctx.Value(contextKeyProxy{}).(string)
+	if proxy == "" {
+		return client
+	}
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return client
+	}
+	client.Transport = &http.Transport{Proxy: http.ProxyURL(proxyURL)}
+	return client
+}
@@ -0,0 +1,44 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAutoUpdateInterval(t *testing.T) {
+	if d, ok := AutoUpdateInterval("Weekly"); !ok || d != 7*24*time.Hour {
+		t.Fatalf("weekly = %v, %v", d, ok)
+	}
+	if _, ok := AutoUpdateInterval("off"); ok {
+		t.Fatal("off should not be a channel")
+	}
+	if _, ok := AutoUpdateInterval(""); ok {
+		t.Fatal("empty should not be a channel")
+	}
+}
+
+func TestAutoUpdateSkipsWithinInterval(t *testing.T) {
+	t.Setenv("POWERHOUR_TOOLS_DIR", t.TempDir())
+	saveAutoUpdateState(autoUpdateState{LastChecked: map[string]time.Time{
+		"yt-dlp": time.Now().Add(-time.Hour),
+	}})
+
+	result, err := AutoUpdate(context.Background(), "yt-dlp", "daily")
+	if err != nil {
+		t.Fatalf("AutoUpdate: %v", err)
+	}
+	if result.Checked || result.Updated() {
+		t.Fatalf("expected no check within interval, got %+v", result)
+	}
+}
+
+func TestHTTPClientUsesContextProxy(t *testing.T) {
+	ctx := WithProxy(context.Background(), "http://127.0.0.1:3128")
+	if httpClient(ctx, time.Second).Transport == nil {
+		t.Fatal("expected proxy transport")
+	}
+	if httpClient(context.Background(), time.Second).Transport != nil {
+		t.Fatal("expected default transport without proxy")
+	}
+}
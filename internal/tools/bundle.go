@@ -0,0 +1,285 @@
+package tools
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	bundleManifestName = "bundle.json"
+	bundleFormat       = 1
+)
+
+// BundleManifest describes the contents of an offline tool bundle created by
+// CreateBundle. It is stored as bundle.json at the root of the archive.
+type BundleManifest struct {
+	Format    int          `json:"format"`
+	Platform  string       `json:"platform"`
+	CreatedAt time.Time    `json:"created_at"`
+	Tools     []BundleTool `json:"tools"`
+}
+
+// BundleTool records one tool's binaries inside a bundle. Files and SHA256
+// are keyed by binary ID (e.g. "ffmpeg", "ffprobe").
+type BundleTool struct {
+	Tool    string            `json:"tool"`
+	Version string            `json:"version"`
+	Files   map[string]string `json:"files"`
+	SHA256  map[string]string `json:"sha256"`
+}
+
+// CreateBundle writes a tar.gz containing the currently resolved binaries for
+// names plus a bundle.json with per-file SHA256 checksums. The archive can be
+// carried to an offline machine of the same platform and installed with
+// InstallFromBundle.
+func CreateBundle(ctx context.Context, dest string, names []string) (BundleManifest, error) {
+	statuses, err := Detect(ctx)
+	if err != nil {
+		return BundleManifest{}, err
+	}
+	byName := make(map[string]Status, len(statuses))
+	for _, st := range statuses {
+		byName[st.Tool] = st
+	}
+
+	manifest := BundleManifest{
+		Format:    bundleFormat,
+		Platform:  currentPlatformKey(),
+		CreatedAt: time.Now().UTC(),
+	}
+
+	type bundleFile struct {
+		src, name string
+	}
+	var files []bundleFile
+
+	for _, name := range names {
+		def, ok := Definition(name)
+		if !ok {
+			return BundleManifest{}, fmt.Errorf("unknown tool: %s", name)
+		}
+		st, ok := byName[name]
+		if !ok || st.Version == "" || len(st.Paths) == 0 {
+			return BundleManifest{}, fmt.Errorf("%s is not installed; run `powerhour tools install %s` first", name, name)
+		}
+		entry := BundleTool{
+			Tool:    name,
+			Version: st.Version,
+			Files:   map[string]string{},
+			SHA256:  map[string]string{},
+		}
+		for _, bin := range def.Binaries {
+			src, ok := st.Paths[bin.ID]
+			if !ok {
+				return BundleManifest{}, fmt.Errorf("%s: binary %s not resolved", name, bin.ID)
+			}
+			sum, err := computeChecksum(src)
+			if err != nil {
+				return BundleManifest{}, fmt.Errorf("%s: %w", name, err)
+			}
+			archiveName := path.Join(name, st.Version, bin.Executable)
+			entry.Files[bin.ID] = archiveName
+			entry.SHA256[bin.ID] = sum
+			files = append(files, bundleFile{src: src, name: archiveName})
+		}
+		manifest.Tools = append(manifest.Tools, entry)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return BundleManifest{}, fmt.Errorf("prepare bundle dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dest), ".bundle-*.tmp")
+	if err != nil {
+		return BundleManifest{}, fmt.Errorf("create bundle: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	gz := gzip.NewWriter(tmp)
+	tw := tar.NewWriter(gz)
+
+	meta, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		tmp.Close()
+		return BundleManifest{}, fmt.Errorf("marshal bundle manifest: %w", err)
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: bundleManifestName, Mode: 0o644, Size: int64(len(meta)), ModTime: manifest.CreatedAt}); err != nil {
+		tmp.Close()
+		return BundleManifest{}, fmt.Errorf("write bundle manifest: %w", err)
+	}
+	if _, err := tw.Write(meta); err != nil {
+		tmp.Close()
+		return BundleManifest{}, fmt.Errorf("write bundle manifest: %w", err)
+	}
+	for _, f := range files {
+		if err := addFileToTar(tw, f.src, f.name); err != nil {
+			tmp.Close()
+			return BundleManifest{}, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		tmp.Close()
+		return BundleManifest{}, fmt.Errorf("finalize bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		tmp.Close()
+		return BundleManifest{}, fmt.Errorf("finalize bundle: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return BundleManifest{}, fmt.Errorf("close bundle: %w", err)
+	}
+	if err := os.Rename(tmpPath, dest); err != nil {
+		return BundleManifest{}, fmt.Errorf("write bundle: %w", err)
+	}
+	return manifest, nil
+}
+
+func addFileToTar(tw *tar.Writer, src, name string) error {
+	file, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", src, err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("stat %s: %w", src, err)
+	}
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0o755,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	if _, err := io.Copy(tw, file); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+// InstallFromBundle installs names from a bundle created by CreateBundle
+// without touching the network. Every binary is verified against the
+// bundle's SHA256 before it is copied into the tool cache. Tools missing from
+// the bundle are reported as errors.
+func InstallFromBundle(ctx context.Context, bundlePath string, names []string, opts InstallOptions) ([]Status, error) {
+	downloads, err := downloadsDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(downloads, 0o755); err != nil {
+		return nil, fmt.Errorf("prepare downloads dir: %w", err)
+	}
+	extractDir, err := os.MkdirTemp(downloads, "bundle-extract-")
+	if err != nil {
+		return nil, fmt.Errorf("create extract dir: %w", err)
+	}
+	defer os.RemoveAll(extractDir)
+
+	if err := extractTarGz(ctx, bundlePath, extractDir); err != nil {
+		return nil, fmt.Errorf("extract bundle: %w", err)
+	}
+
+	manifest, err := readBundleManifest(filepath.Join(extractDir, bundleManifestName))
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Platform != currentPlatformKey() {
+		return nil, fmt.Errorf("bundle built for %s, this machine is %s", manifest.Platform, currentPlatformKey())
+	}
+
+	entries := make(map[string]BundleTool, len(manifest.Tools))
+	for _, t := range manifest.Tools {
+		entries[t.Tool] = t
+	}
+
+	var (
+		statuses []Status
+		errs     []error
+	)
+	for _, name := range names {
+		entry, ok := entries[name]
+		if !ok {
+			err := fmt.Errorf("%s: not present in bundle", name)
+			statuses = append(statuses, Status{Tool: name, Error: err.Error()})
+			errs = append(errs, err)
+			continue
+		}
+		status, err := installBundleTool(ctx, extractDir, entry, filepath.Base(bundlePath), opts)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, errors.Join(errs...)
+}
+
+func installBundleTool(ctx context.Context, extractDir string, entry BundleTool, bundleName string, opts InstallOptions) (Status, error) {
+	def, ok := Definition(entry.Tool)
+	if !ok {
+		return Status{Tool: entry.Tool}, fmt.Errorf("unknown tool: %s", entry.Tool)
+	}
+
+	unlock, err := acquireInstallLock(ctx, def.Name)
+	if err != nil {
+		return Status{Tool: def.Name, Error: err.Error()}, err
+	}
+	defer unlock()
+
+	sources := make(map[string]string, len(def.Binaries))
+	for _, bin := range def.Binaries {
+		rel, ok := entry.Files[bin.ID]
+		if !ok {
+			err := fmt.Errorf("bundle missing binary %s", bin.ID)
+			return Status{Tool: def.Name, Error: err.Error()}, err
+		}
+		src, err := archiveTarget(extractDir, rel)
+		if err != nil {
+			return Status{Tool: def.Name, Error: err.Error()}, err
+		}
+		match, err := verifyChecksum(src, entry.SHA256[bin.ID])
+		if err != nil {
+			return Status{Tool: def.Name, Error: err.Error()}, err
+		}
+		if !match || entry.SHA256[bin.ID] == "" {
+			err := fmt.Errorf("checksum mismatch for %s", rel)
+			return Status{Tool: def.Name, Error: err.Error()}, err
+		}
+		sources[bin.ID] = src
+	}
+
+	destPaths, checksum, err := cacheBinaries(def, entry.Version, sources, opts.Force)
+	if err != nil {
+		return Status{Tool: def.Name, Error: err.Error()}, err
+	}
+	notes := []string{fmt.Sprintf("installed %s from bundle %s", entry.Version, bundleName)}
+	return saveCacheInstall(def, entry.Version, destPaths, checksum, notes)
+}
+
+func readBundleManifest(path string) (BundleManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return BundleManifest{}, fmt.Errorf("bundle is missing %s: %w", bundleManifestName, err)
+	}
+	var manifest BundleManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return BundleManifest{}, fmt.Errorf("parse %s: %w", bundleManifestName, err)
+	}
+	if manifest.Format != bundleFormat {
+		return BundleManifest{}, fmt.Errorf("unsupported bundle format %d", manifest.Format)
+	}
+	sort.Slice(manifest.Tools, func(i, j int) bool { return manifest.Tools[i].Tool < manifest.Tools[j].Tool })
+	return manifest, nil
+}
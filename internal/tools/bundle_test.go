@@ -0,0 +1,251 @@
+package tools
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func writeFakeYTDLP(t *testing.T, root, version string) {
+	t.Helper()
+	dir := filepath.Join(root, "yt-dlp", version)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	script := "#!/bin/sh\necho " + version + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "yt-dlp"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake binaries are shell scripts")
+	}
+	ctx := context.Background()
+
+	source := t.TempDir()
+	t.Setenv("POWERHOUR_TOOLS_DIR", source)
+	writeFakeYTDLP(t, source, "2024.07.16")
+
+	bundlePath := filepath.Join(t.TempDir(), "tools.tar.gz")
+	manifest, err := CreateBundle(ctx, bundlePath, []string{"yt-dlp"})
+	if err != nil {
+		t.Fatalf("CreateBundle: %v", err)
+	}
+	if len(manifest.Tools) != 1 || manifest.Tools[0].Version != "2024.07.16" {
+		t.Fatalf("unexpected manifest: %+v", manifest)
+	}
+
+	target := t.TempDir()
+	t.Setenv("POWERHOUR_TOOLS_DIR", target)
+	statuses, err := InstallFromBundle(ctx, bundlePath, []string{"yt-dlp"}, InstallOptions{})
+	if err != nil {
+		t.Fatalf("InstallFromBundle: %v", err)
+	}
+	if len(statuses) != 1 || statuses[0].Version != "2024.07.16" || statuses[0].Source != SourceCache {
+		t.Fatalf("unexpected statuses: %+v", statuses)
+	}
+	if !strings.HasPrefix(statuses[0].Path, target) {
+		t.Fatalf("binary installed outside tool cache: %s", statuses[0].Path)
+	}
+	if _, err := os.Stat(statuses[0].Path); err != nil {
+		t.Fatalf("installed binary missing: %v", err)
+	}
+}
+
+func TestInstallFromBundleReportsMissingTool(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake binaries are shell scripts")
+	}
+	ctx := context.Background()
+
+	source := t.TempDir()
+	t.Setenv("POWERHOUR_TOOLS_DIR", source)
+	writeFakeYTDLP(t, source, "2024.07.16")
+	bundlePath := filepath.Join(t.TempDir(), "tools.tar.gz")
+	if _, err := CreateBundle(ctx, bundlePath, []string{"yt-dlp"}); err != nil {
+		t.Fatalf("CreateBundle: %v", err)
+	}
+
+	t.Setenv("POWERHOUR_TOOLS_DIR", t.TempDir())
+	_, err := InstallFromBundle(ctx, bundlePath, []string{"ffmpeg"}, InstallOptions{})
+	if err == nil || !strings.Contains(err.Error(), "not present in bundle") {
+		t.Fatalf("expected missing tool error, got %v", err)
+	}
+}
+
+func TestInstallFromBundleRejectsEscapingMembers(t *testing.T) {
+	tests := []struct {
+		name   string
+		header tar.Header
+	}{
+		{"parent", tar.Header{Name: "../evil", Typeflag: tar.TypeReg, Mode: 0o644}},
+		{"nested parent", tar.Header{Name: "tools/../../evil", Typeflag: tar.TypeReg, Mode: 0o644}},
+		{"absolute", tar.Header{Name: "/tmp/evil", Typeflag: tar.TypeReg, Mode: 0o644}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			t.Setenv("POWERHOUR_TOOLS_DIR", filepath.Join(root, "tools"))
+			bundlePath := filepath.Join(root, "evil.tar.gz")
+			writeTarGz(t, bundlePath, tt.header, "pwned")
+
+			_, err := InstallFromBundle(context.Background(), bundlePath, []string{"yt-dlp"}, InstallOptions{})
+			if err == nil || !strings.Contains(err.Error(), "outside the extract dir") {
+				t.Fatalf("expected containment error, got %v", err)
+			}
+			// ../evil would land next to the extract dir in downloads/.
+			if _, err := os.Stat(filepath.Join(root, "tools", "downloads", "evil")); !os.IsNotExist(err) {
+				t.Fatalf("member escaped the extract dir: %v", err)
+			}
+		})
+	}
+}
+
+func TestInstallFromBundleRejectsUnsafeManifest(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake binaries are shell scripts")
+	}
+	body := "#!/bin/sh\necho ok\n"
+	sum := sha256.Sum256([]byte(body))
+	checksum := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name    string
+		version string
+		file    string
+		wantErr string
+	}{
+		{"parent version", "../../..", "yt-dlp", "invalid version"},
+		{"empty version", "", "yt-dlp", "invalid version"},
+		{"dot version", ".", "yt-dlp", "invalid version"},
+		{"nested version", "1/2", "yt-dlp", "invalid version"},
+		{"escaping file", "2024.07.16", "../secret", "outside the extract dir"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			toolsDir := filepath.Join(root, "home", "tools")
+			t.Setenv("POWERHOUR_TOOLS_DIR", toolsDir)
+			writeFakeYTDLP(t, toolsDir, "2024.01.01")
+			keep := filepath.Join(root, "home", "keep")
+			if err := os.MkdirAll(keep, 0o755); err != nil {
+				t.Fatal(err)
+			}
+
+			manifest := BundleManifest{
+				Format:   bundleFormat,
+				Platform: currentPlatformKey(),
+				Tools: []BundleTool{{
+					Tool:    "yt-dlp",
+					Version: tt.version,
+					Files:   map[string]string{"yt-dlp": tt.file},
+					SHA256:  map[string]string{"yt-dlp": checksum},
+				}},
+			}
+			data, err := json.Marshal(manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bundlePath := filepath.Join(root, "evil.tar.gz")
+			writeTarGzFiles(t, bundlePath, map[string]string{
+				bundleManifestName: string(data),
+				"yt-dlp":           body,
+			})
+			// The escaping file case points at this, next to the extract dir.
+			if err := os.MkdirAll(filepath.Join(toolsDir, "downloads"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(toolsDir, "downloads", "secret"), []byte(body), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = InstallFromBundle(context.Background(), bundlePath, []string{"yt-dlp"}, InstallOptions{})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected %q error, got %v", tt.wantErr, err)
+			}
+			if _, err := os.Stat(keep); err != nil {
+				t.Fatalf("directory outside the tool cache was removed: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(toolsDir, "yt-dlp", "2024.01.01", "yt-dlp")); err != nil {
+				t.Fatalf("cached version was removed: %v", err)
+			}
+		})
+	}
+}
+
+func TestUntarStreamSkipsLinks(t *testing.T) {
+	dest := t.TempDir()
+	archive := filepath.Join(t.TempDir(), "links.tar.gz")
+	writeTarGz(t, archive, tar.Header{Name: "escape", Typeflag: tar.TypeSymlink, Linkname: "/etc"}, "")
+	if err := extractTarGz(context.Background(), archive, dest); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Lstat(filepath.Join(dest, "escape")); !os.IsNotExist(err) {
+		t.Fatalf("symlink was extracted: %v", err)
+	}
+}
+
+// writeTarGz writes a one-member tar.gz. body is the content of a regular
+// file member.
+func writeTarGz(t *testing.T, path string, header tar.Header, body string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	if header.Typeflag == tar.TypeReg {
+		header.Size = int64(len(body))
+	}
+	if err := tw.WriteHeader(&header); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write([]byte(body)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeTarGzFiles writes a tar.gz of regular files keyed by member name.
+func writeTarGzFiles(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for name, body := range files {
+		header := tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0o755, Size: int64(len(body))}
+		if err := tw.WriteHeader(&header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -37,6 +37,19 @@ type GlobalConfig struct {
 	EncodingDefaults `yaml:",inline"`
 	Downloads        GlobalDownloads             `yaml:"downloads,omitempty"`
 	Metadata         MetadataNormalizationConfig `yaml:"metadata_normalization,omitempty"`
+	Tools            GlobalToolsConfig           `yaml:"tools,omitempty"`
+}
+
+// GlobalToolsConfig controls how tool release downloads are verified.
+type GlobalToolsConfig struct {
+	// RequireVerified refuses any download whose SHA256 cannot be established.
+	RequireVerified bool `yaml:"require_verified_downloads,omitempty"`
+	// Signature enables detached-signature checks on checksum manifests:
+	// "gpg" or "minisign". Empty disables signature verification.
+	Signature string `yaml:"signature,omitempty"`
+	// SignatureKey is a keyring (gpg) or public key file (minisign). When
+	// empty, gpg uses the default keyring; minisign requires a key.
+	SignatureKey string `yaml:"signature_key,omitempty"`
 }
 
 // CodecFamily groups related encoders by technology.
@@ -88,6 +101,10 @@ type EncodingProfile struct {
 	Hostname          string              `json:"hostname"`
 	GOOS              string              `json:"goos"`
 	ProbedAt          time.Time           `json:"probed_at"`
+	FFmpegPath        string              `json:"ffmpeg_path,omitempty"`
+	FFmpegChecksum    string              `json:"ffmpeg_checksum,omitempty"`
+	FFmpegSize        int64               `json:"ffmpeg_size,omitempty"`
+	FFmpegModTime     time.Time           `json:"ffmpeg_mod_time,omitzero"`
 }
 
 // AvailableAll returns all available codecs ordered by family priority.
@@ -127,8 +144,34 @@ func machineFingerprint() string {
 }
 
 // LoadEncodingProfile loads the cached encoding profile if valid.
-// Returns nil if missing, expired, wrong machine, or uses old schema.
+// Returns nil if missing, expired, wrong machine, uses old schema, or was
+// probed against a different ffmpeg binary than the one currently resolved.
 func LoadEncodingProfile() *EncodingProfile {
+	profile := LastEncodingProfile()
+	if profile == nil {
+		return nil
+	}
+	if time.Since(profile.ProbedAt) > encodingProfileTTL {
+		return nil
+	}
+	hostname, _ := os.Hostname()
+	if profile.GOOS != runtime.GOOS || profile.Hostname != hostname {
+		return nil
+	}
+	// Profile predates the multi-family schema — needs a fresh probe.
+	if len(profile.AvailableByFamily) == 0 {
+		return nil
+	}
+	// ffmpeg was swapped out (e.g. brew upgrade) — encoders may have changed.
+	if !profile.matchesCurrentFFmpeg() {
+		return nil
+	}
+	return profile
+}
+
+// LastEncodingProfile loads the cached encoding profile without any validity
+// checks. Used to diff a fresh probe against what was previously recorded.
+func LastEncodingProfile() *EncodingProfile {
 	path, err := encodingProfilePath()
 	if err != nil {
 		return nil
@@ -141,18 +184,70 @@ func LoadEncodingProfile() *EncodingProfile {
 	if err := json.Unmarshal(data, &profile); err != nil {
 		return nil
 	}
-	if time.Since(profile.ProbedAt) > encodingProfileTTL {
-		return nil
+	return &profile
+}
+
+// matchesCurrentFFmpeg reports whether the profile was probed against the
+// ffmpeg binary currently recorded in the tool manifest. The binary's size
+// and modification time stand in for its checksum, so loading the profile
+// doesn't hash ffmpeg every time. A profile saved before they were recorded
+// is compared by checksum once and saved with them.
+func (p *EncodingProfile) matchesCurrentFFmpeg() bool {
+	if p.FFmpegChecksum == "" {
+		return false
 	}
-	hostname, _ := os.Hostname()
-	if profile.GOOS != runtime.GOOS || profile.Hostname != hostname {
-		return nil
+	ffmpegPath, err := Lookup("ffmpeg")
+	if err != nil {
+		// Nothing to compare against; let the caller decide how to probe.
+		return true
 	}
-	// Profile predates the multi-family schema — needs a fresh probe.
-	if len(profile.AvailableByFamily) == 0 {
-		return nil
+	if ffmpegPath != p.FFmpegPath {
+		return false
 	}
-	return &profile
+	info, err := os.Stat(ffmpegPath)
+	if err != nil {
+		return false
+	}
+	if !p.FFmpegModTime.IsZero() {
+		return info.Size() == p.FFmpegSize && info.ModTime().Equal(p.FFmpegModTime)
+	}
+	checksum, err := computeChecksum(ffmpegPath)
+	if err != nil || checksum != p.FFmpegChecksum {
+		return false
+	}
+	p.FFmpegSize, p.FFmpegModTime = info.Size(), info.ModTime()
+	_ = SaveEncodingProfile(*p)
+	return true
+}
+
+// DiffEncoders compares the available codecs of two profiles and returns the
+// encoders present only in next (gained) and only in prev (lost).
+func DiffEncoders(prev, next *EncodingProfile) (gained, lost []string) {
+	before := map[string]bool{}
+	after := map[string]bool{}
+	if prev != nil {
+		for _, codec := range prev.AvailableCodecs {
+			before[codec] = true
+		}
+	}
+	if next != nil {
+		for _, codec := range next.AvailableCodecs {
+			after[codec] = true
+		}
+		for _, codec := range next.AvailableAll() {
+			if !before[codec] {
+				gained = append(gained, codec)
+			}
+		}
+	}
+	if prev != nil {
+		for _, codec := range prev.AvailableAll() {
+			if !after[codec] {
+				lost = append(lost, codec)
+			}
+		}
+	}
+	return gained, lost
 }
 
 // SaveEncodingProfile persists the encoding profile to disk.
@@ -191,6 +286,13 @@ func ProbeEncoders(ctx context.Context, ffmpegPath string) (EncodingProfile, err
 		GOOS:              runtime.GOOS,
 		ProbedAt:          time.Now(),
 		AvailableByFamily: make(map[string][]string),
+		FFmpegPath:        ffmpegPath,
+	}
+	if checksum, err := computeChecksum(ffmpegPath); err == nil {
+		profile.FFmpegChecksum = checksum
+	}
+	if info, err := os.Stat(ffmpegPath); err == nil {
+		profile.FFmpegSize, profile.FFmpegModTime = info.Size(), info.ModTime()
 	}
 
 	for _, family := range CodecFamilies {
@@ -254,14 +356,53 @@ func ProbeFilters(ctx context.Context, ffmpegPath string, required []string) (av
 }
 
 func testEncoder(ctx context.Context, ffmpegPath, codec string) bool {
+	return EncoderSupportsPixFmt(ctx, ffmpegPath, codec, "")
+}
+
+// EncoderPixFmts returns the pixel formats an encoder lists in
+// `ffmpeg -h encoder=<codec>`, or nil when it lists none.
+func EncoderPixFmts(ctx context.Context, ffmpegPath, codec string) ([]string, error) {
+	out, err := exec.CommandContext(ctx, ffmpegPath, "-hide_banner", "-h", "encoder="+codec).Output()
+	if err != nil {
+		return nil, fmt.Errorf("query encoder %s: %w", codec, err)
+	}
+	if strings.Contains(string(out), "is not recognized") {
+		return nil, fmt.Errorf("ffmpeg has no encoder %s", codec)
+	}
+	return parseEncoderPixFmts(string(out)), nil
+}
+
+// parseEncoderPixFmts reads the "Supported pixel formats:" line of an
+// encoder's help output.
+func parseEncoderPixFmts(help string) []string {
+	scanner := bufio.NewScanner(strings.NewReader(help))
+	for scanner.Scan() {
+		_, formats, ok := strings.Cut(scanner.Text(), "Supported pixel formats:")
+		if ok {
+			return strings.Fields(formats)
+		}
+	}
+	return nil
+}
+
+// EncoderSupportsPixFmt encodes a single test frame in pixFmt (the encoder's
+// default when empty) and reports whether it succeeded. Hardware encoders
+// are listed even where the GPU can't do the format, so only a real encode
+// tells.
+func EncoderSupportsPixFmt(ctx context.Context, ffmpegPath, codec, pixFmt string) bool {
 	args := []string{
 		"-f", "lavfi",
 		"-i", "color=black:s=64x64:d=1:r=1",
 		"-c:v", codec,
+	}
+	if pixFmt != "" {
+		args = append(args, "-pix_fmt", pixFmt)
+	}
+	args = append(args,
 		"-frames:v", "1",
 		"-f", "null",
 		"-",
-	}
+	)
 	cmd := exec.CommandContext(ctx, ffmpegPath, args...)
 	return cmd.Run() == nil
 }
@@ -339,6 +480,9 @@ type ResolvedEncoding struct {
 	Preset       string
 	VideoBitrate string
 	Container    string
+	// VideoArgs are extra encoder arguments for re-encodes, such as the
+	// pixel format and color tags of video.hdr output.
+	VideoArgs []string
 
 	// Audio
 	AudioCodec   string
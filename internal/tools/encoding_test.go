@@ -0,0 +1,136 @@
+package tools
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiffEncoders(t *testing.T) {
+	prev := &EncodingProfile{
+		AvailableCodecs: []string{"h264_videotoolbox", "libx264", "libx265"},
+		AvailableByFamily: map[string][]string{
+			"H.264":        {"h264_videotoolbox", "libx264"},
+			"H.265 (HEVC)": {"libx265"},
+		},
+	}
+	next := &EncodingProfile{
+		AvailableCodecs: []string{"h264_videotoolbox", "libx264", "libsvtav1"},
+		AvailableByFamily: map[string][]string{
+			"H.264": {"h264_videotoolbox", "libx264"},
+			"AV1":   {"libsvtav1"},
+		},
+	}
+
+	gained, lost := DiffEncoders(prev, next)
+	if !reflect.DeepEqual(gained, []string{"libsvtav1"}) {
+		t.Errorf("gained = %v, want [libsvtav1]", gained)
+	}
+	if !reflect.DeepEqual(lost, []string{"libx265"}) {
+		t.Errorf("lost = %v, want [libx265]", lost)
+	}
+}
+
+func TestDiffEncodersNoPrevious(t *testing.T) {
+	next := &EncodingProfile{
+		AvailableCodecs:   []string{"libx264"},
+		AvailableByFamily: map[string][]string{"H.264": {"libx264"}},
+	}
+	gained, lost := DiffEncoders(nil, next)
+	if !reflect.DeepEqual(gained, []string{"libx264"}) {
+		t.Errorf("gained = %v, want [libx264]", gained)
+	}
+	if len(lost) != 0 {
+		t.Errorf("lost = %v, want none", lost)
+	}
+}
+
+func TestEncodingProfileWithoutChecksumIsStale(t *testing.T) {
+	t.Setenv("POWERHOUR_TOOLS_DIR", t.TempDir())
+	profile := EncodingProfile{FFmpegPath: "/usr/bin/ffmpeg"}
+	if profile.matchesCurrentFFmpeg() {
+		t.Fatal("expected profile without checksum to be treated as stale")
+	}
+}
+
+func TestEncodingProfileMatchesFFmpegByStat(t *testing.T) {
+	t.Setenv("POWERHOUR_TOOLS_DIR", t.TempDir())
+	ffmpeg := filepath.Join(t.TempDir(), "ffmpeg")
+	if err := os.WriteFile(ffmpeg, []byte("ffmpeg-1"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := saveManifest(Manifest{Entries: map[string]ManifestEntry{
+		"ffmpeg": {Tool: "ffmpeg", Source: SourceSystem, Paths: map[string]string{"ffmpeg": ffmpeg}},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	checksum, err := computeChecksum(ffmpeg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(ffmpeg, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	// A profile from before size and mtime were recorded is checked by
+	// checksum and then saved with them.
+	legacy := EncodingProfile{FFmpegPath: ffmpeg, FFmpegChecksum: checksum}
+	if !legacy.matchesCurrentFFmpeg() {
+		t.Fatal("legacy profile with matching checksum should match")
+	}
+	saved := LastEncodingProfile()
+	if saved == nil || saved.FFmpegSize != 8 || !saved.FFmpegModTime.Equal(mtime) {
+		t.Fatalf("legacy profile not upgraded: %+v", saved)
+	}
+
+	// Same size and mtime match without hashing the binary again.
+	if err := os.WriteFile(ffmpeg, []byte("ffmpeg-2"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(ffmpeg, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	if !saved.matchesCurrentFFmpeg() {
+		t.Error("unchanged size and mtime should match")
+	}
+
+	later := mtime.Add(time.Minute)
+	if err := os.Chtimes(ffmpeg, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if saved.matchesCurrentFFmpeg() {
+		t.Error("a replaced binary should not match")
+	}
+}
+
+func TestParseEncoderPixFmts(t *testing.T) {
+	help := `Encoder libx265 [libx265 H.265 / HEVC]:
+    General capabilities: dr1 delay threads
+    Threading capabilities: other
+    Supported pixel formats: yuv420p yuvj420p yuv422p yuv420p10le gray
+libx265 AVOptions:
+  -crf               <float>      E..V....... set the x265 crf (from -1 to FLT_MAX) (default -1)
+`
+	want := []string{"yuv420p", "yuvj420p", "yuv422p", "yuv420p10le", "gray"}
+	if got := parseEncoderPixFmts(help); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseEncoderPixFmts = %v, want %v", got, want)
+	}
+	if got := parseEncoderPixFmts("Encoder rawvideo [raw video]:\n"); got != nil {
+		t.Errorf("no formats line: got %v, want nil", got)
+	}
+}
+
+func TestEncodingProfileOmitsUnsetFFmpegStat(t *testing.T) {
+	data, err := json.Marshal(EncodingProfile{FFmpegPath: "/usr/bin/ffmpeg"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "ffmpeg_mod_time") || strings.Contains(string(data), "ffmpeg_size") {
+		t.Errorf("unset stat written: %s", data)
+	}
+}
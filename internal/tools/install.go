@@ -3,6 +3,7 @@ package tools
 import (
 	"archive/tar"
 	"archive/zip"
+	"bufio"
 	"compress/gzip"
 	"context"
 	"crypto/sha256"
@@ -13,12 +14,14 @@ import (
 	"net/http"
 	"net/url"
 	"os"
-	"os/exec"
 	"path"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"strings"
 	"time"
+
+	"github.com/ulikunitz/xz"
 )
 
 // InstallOptions configures install behaviour.
@@ -69,6 +72,7 @@ func Install(ctx context.Context, toolName string, version string, opts InstallO
 		return Status{Tool: toolName, Error: err.Error()}, err
 	}
 	defer unlock()
+	CleanStaleInstallArtifacts()
 
 	current, err = currentStatus(ctx, toolName)
 	if err != nil {
@@ -157,6 +161,12 @@ func installFromRelease(ctx context.Context, def ToolDefinition, spec releaseSpe
 		archivePath = archivePath + "." + spec.Version
 	}
 
+	spec, verifyNotes, err := verifyRelease(ctx, spec, downloads, LoadGlobalConfig().Tools)
+	notes = append(notes, verifyNotes...)
+	if err != nil {
+		return Status{Tool: def.Name, Notes: notes}, err
+	}
+
 	if err := ensureDownload(ctx, archivePath, spec.URL, spec.Checksum, opts.Force); err != nil {
 		return Status{Tool: def.Name, Notes: notes}, err
 	}
@@ -263,6 +273,11 @@ func installFromSystem(ctx context.Context, def ToolDefinition, requestedVersion
 }
 
 func cacheBinaries(def ToolDefinition, version string, sources map[string]string, force bool) (map[string]string, string, error) {
+	// version names the cache directory that is replaced below, so it must
+	// stay a single path element.
+	if !validCacheVersion(version) {
+		return nil, "", fmt.Errorf("invalid version %q", version)
+	}
 	root, err := cacheRoot()
 	if err != nil {
 		return nil, "", err
@@ -428,7 +443,7 @@ func downloadArtifact(ctx context.Context, dest, downloadURL, checksum string) e
 	}
 	req.Header.Set("User-Agent", "powerhour/1.0")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpClient(ctx, 0).Do(req)
 	if err != nil {
 		return fmt.Errorf("download %s: %w", downloadURL, err)
 	}
@@ -492,9 +507,9 @@ func resolveArchivePath(downloadsDir, downloadURL string) (string, error) {
 func extractArchive(ctx context.Context, format archiveFormat, archivePath, dest string) error {
 	switch format {
 	case archiveFormatZip:
-		return extractZip(archivePath, dest)
+		return extractZip(ctx, archivePath, dest)
 	case archiveFormatTarGz:
-		return extractTarGz(archivePath, dest)
+		return extractTarGz(ctx, archivePath, dest)
 	case archiveFormatTarXz:
 		return extractTarXz(ctx, archivePath, dest)
 	default:
@@ -502,7 +517,7 @@ func extractArchive(ctx context.Context, format archiveFormat, archivePath, dest
 	}
 }
 
-func extractZip(archivePath, dest string) error {
+func extractZip(ctx context.Context, archivePath, dest string) error {
 	reader, err := zip.OpenReader(archivePath)
 	if err != nil {
 		return fmt.Errorf("open zip: %w", err)
@@ -510,7 +525,16 @@ func extractZip(archivePath, dest string) error {
 	defer reader.Close()
 
 	for _, file := range reader.File {
-		target := filepath.Join(dest, filepath.FromSlash(file.Name))
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		target, err := archiveTarget(dest, file.Name)
+		if err != nil {
+			return err
+		}
+		if file.Mode()&os.ModeSymlink != 0 {
+			continue
+		}
 		if file.FileInfo().IsDir() {
 			if err := os.MkdirAll(target, file.Mode()); err != nil {
 				return fmt.Errorf("create dir %s: %w", target, err)
@@ -542,7 +566,7 @@ func extractZip(archivePath, dest string) error {
 	return nil
 }
 
-func extractTarGz(archivePath, dest string) error {
+func extractTarGz(ctx context.Context, archivePath, dest string) error {
 	file, err := os.Open(archivePath)
 	if err != nil {
 		return fmt.Errorf("open archive: %w", err)
@@ -555,23 +579,34 @@ func extractTarGz(archivePath, dest string) error {
 	}
 	defer gz.Close()
 
-	return untarStream(gz, dest)
+	return untarStream(ctx, gz, dest)
 }
 
 func extractTarXz(ctx context.Context, archivePath, dest string) error {
-	if err := os.MkdirAll(dest, 0o755); err != nil {
-		return fmt.Errorf("prepare extract dir: %w", err)
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("open archive: %w", err)
 	}
-	cmd := exec.CommandContext(ctx, "tar", "-xJf", archivePath, "-C", dest)
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("tar extract: %v: %s", err, strings.TrimSpace(string(output)))
+	defer file.Close()
+
+	xzr, err := xz.NewReader(bufio.NewReader(file))
+	if err != nil {
+		return fmt.Errorf("xz reader: %w", err)
 	}
-	return nil
+
+	return untarStream(ctx, xzr, dest)
 }
 
-func untarStream(r io.Reader, dest string) error {
+// untarStream extracts a tar stream into dest. Archives come from downloads
+// and user-supplied bundles, so members that would land outside dest are
+// rejected, and links are skipped: only regular files and directories are
+// ever needed.
+func untarStream(ctx context.Context, r io.Reader, dest string) error {
 	tr := tar.NewReader(r)
 	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		header, err := tr.Next()
 		if errors.Is(err, io.EOF) {
 			break
@@ -579,7 +614,10 @@ func untarStream(r io.Reader, dest string) error {
 		if err != nil {
 			return fmt.Errorf("read tar header: %w", err)
 		}
-		target := filepath.Join(dest, filepath.FromSlash(header.Name))
+		target, err := archiveTarget(dest, header.Name)
+		if err != nil {
+			return err
+		}
 		switch header.Typeflag {
 		case tar.TypeDir:
 			if err := os.MkdirAll(target, os.FileMode(header.Mode)); err != nil {
@@ -601,12 +639,32 @@ func untarStream(r io.Reader, dest string) error {
 				return fmt.Errorf("close file %s: %w", target, err)
 			}
 		default:
-			// Ignore other entry types.
+			// Ignore symlinks, hard links and other entry types: a link
+			// could point outside dest for later members to write through.
 		}
 	}
 	return nil
 }
 
+// archiveTarget returns where the archive member name extracts to under
+// dest, or an error when it is absolute or climbs out of dest.
+var cacheVersionRegex = regexp.MustCompile(`^[A-Za-z0-9._+~-]+$`)
+
+// validCacheVersion reports whether version can name a directory of the tool
+// cache: letters, digits and ._+~- only, and not . or ..
+func validCacheVersion(version string) bool {
+	return cacheVersionRegex.MatchString(version) && version != "." && version != ".."
+}
+
+func archiveTarget(dest, name string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(name))
+	if filepath.IsAbs(clean) || filepath.VolumeName(clean) != "" || strings.HasPrefix(name, "/") ||
+		clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("archive member %q is outside the extract dir", name)
+	}
+	return filepath.Join(dest, clean), nil
+}
+
 func findExecutable(root, name string) (string, error) {
 	var match string
 	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
@@ -0,0 +1,156 @@
+package tools
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ulikunitz/xz"
+)
+
+func TestExtractTarXz(t *testing.T) {
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, "ffmpeg.tar.xz")
+
+	f, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xzw, err := xz.NewWriter(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tw := tar.NewWriter(xzw)
+	entries := []struct {
+		name string
+		body string
+	}{
+		{"ffmpeg-7.0-static/ffmpeg", "#!/bin/sh\necho ffmpeg\n"},
+		{"ffmpeg-7.0-static/ffprobe", "#!/bin/sh\necho ffprobe\n"},
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: "ffmpeg-7.0-static/", Typeflag: tar.TypeDir, Mode: 0o755}); err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if err := tw.WriteHeader(&tar.Header{Name: e.name, Typeflag: tar.TypeReg, Mode: 0o755, Size: int64(len(e.body))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(e.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := xzw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := filepath.Join(dir, "out")
+	if err := os.MkdirAll(dest, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := extractArchive(t.Context(), archiveFormatTarXz, archivePath, dest); err != nil {
+		t.Fatalf("extractArchive: %v", err)
+	}
+
+	for _, e := range entries {
+		got, err := os.ReadFile(filepath.Join(dest, filepath.FromSlash(e.name)))
+		if err != nil {
+			t.Fatalf("read %s: %v", e.name, err)
+		}
+		if string(got) != e.body {
+			t.Errorf("%s = %q, want %q", e.name, got, e.body)
+		}
+	}
+	if path, err := findExecutable(dest, "ffprobe"); err != nil || path == "" {
+		t.Errorf("findExecutable(ffprobe) = %q, %v", path, err)
+	}
+}
+
+func TestExtractTarXzRejectsCorruptArchive(t *testing.T) {
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, "broken.tar.xz")
+	if err := os.WriteFile(archivePath, []byte("not xz data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := extractArchive(t.Context(), archiveFormatTarXz, archivePath, dir); err == nil {
+		t.Fatal("expected error for corrupt archive")
+	}
+}
+
+func TestExtractArchiveRejectsEscapingMembers(t *testing.T) {
+	for _, name := range []string{"../evil", "ffmpeg-7.0-static/../../evil", "/tmp/evil"} {
+		t.Run("tar.xz "+name, func(t *testing.T) {
+			dir := t.TempDir()
+			archivePath := filepath.Join(dir, "ffmpeg.tar.xz")
+			f, err := os.Create(archivePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			xzw, err := xz.NewWriter(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tw := tar.NewWriter(xzw)
+			if err := tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0o755, Size: 5}); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := tw.Write([]byte("pwned")); err != nil {
+				t.Fatal(err)
+			}
+			for _, c := range []io.Closer{tw, xzw, f} {
+				if err := c.Close(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			checkEscapeRejected(t, dir, archiveFormatTarXz, archivePath)
+		})
+		t.Run("zip "+name, func(t *testing.T) {
+			dir := t.TempDir()
+			archivePath := filepath.Join(dir, "ffmpeg.zip")
+			f, err := os.Create(archivePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			zw := zip.NewWriter(f)
+			w, err := zw.CreateHeader(&zip.FileHeader{Name: name})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := w.Write([]byte("pwned")); err != nil {
+				t.Fatal(err)
+			}
+			for _, c := range []io.Closer{zw, f} {
+				if err := c.Close(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			checkEscapeRejected(t, dir, archiveFormatZip, archivePath)
+		})
+	}
+}
+
+// checkEscapeRejected extracts archivePath into dir/out and expects the
+// escaping member to be refused before anything lands in dir.
+func checkEscapeRejected(t *testing.T, dir string, format archiveFormat, archivePath string) {
+	t.Helper()
+	dest := filepath.Join(dir, "out")
+	if err := os.MkdirAll(dest, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	err := extractArchive(t.Context(), format, archivePath, dest)
+	if err == nil || !strings.Contains(err.Error(), "outside the extract dir") {
+		t.Fatalf("expected containment error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "evil")); !os.IsNotExist(err) {
+		t.Fatalf("member escaped the extract dir: %v", err)
+	}
+}
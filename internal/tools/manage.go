@@ -0,0 +1,121 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// UninstallResult reports what Uninstall removed.
+type UninstallResult struct {
+	Tool       string   `json:"tool"`
+	Removed    []string `json:"removed"`
+	FreedBytes int64    `json:"freed_bytes"`
+	// WasCurrent is set when the manifest's active version was removed; the
+	// next Detect falls back to a system binary (or reports the tool missing).
+	WasCurrent bool `json:"was_current"`
+}
+
+// Uninstall removes cached versions of tool. An empty version removes every
+// cached version. Removing the manifest's current version also drops its
+// manifest entry so nothing keeps pointing at the deleted binary.
+func Uninstall(ctx context.Context, tool, version string) (UninstallResult, error) {
+	def, ok := Definition(tool)
+	if !ok {
+		return UninstallResult{}, fmt.Errorf("unknown tool: %s", tool)
+	}
+
+	unlock, err := acquireInstallLock(ctx, def.Name)
+	if err != nil {
+		return UninstallResult{}, err
+	}
+	defer unlock()
+
+	usage, err := CacheStats(nil)
+	if err != nil {
+		return UninstallResult{}, err
+	}
+
+	result := UninstallResult{Tool: def.Name}
+	for _, v := range usage.Versions {
+		if v.Tool != def.Name || (version != "" && v.Version != version) {
+			continue
+		}
+		if err := os.RemoveAll(v.Path); err != nil {
+			return result, fmt.Errorf("remove %s: %w", v.Path, err)
+		}
+		result.Removed = append(result.Removed, v.Path)
+		result.FreedBytes += v.Bytes
+		if v.Current {
+			result.WasCurrent = true
+		}
+	}
+	if len(result.Removed) == 0 {
+		if version == "" {
+			return result, fmt.Errorf("no cached versions of %s", def.Name)
+		}
+		return result, fmt.Errorf("%s %s is not in the tool cache", def.Name, version)
+	}
+
+	if result.WasCurrent {
+		manifest, err := loadManifest()
+		if err != nil {
+			return result, err
+		}
+		delete(manifest.Entries, def.Name)
+		if err := saveManifest(manifest); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// SystemPath returns the tool's main binary as found on PATH, ignoring the
+// managed cache. Empty when the tool is not installed system-wide.
+func SystemPath(tool string) string {
+	def, ok := Definition(tool)
+	if !ok || len(def.Binaries) == 0 {
+		return ""
+	}
+	path, err := exec.LookPath(def.Binaries[0].Executable)
+	if err != nil || isUnderCacheRoot(path) {
+		return ""
+	}
+	return path
+}
+
+// Activate makes an already-cached version the manifest's current install
+// without downloading anything. It fails when the version is not cached.
+func Activate(ctx context.Context, tool, version string) (Status, error) {
+	def, ok := Definition(tool)
+	if !ok {
+		return Status{}, fmt.Errorf("unknown tool: %s", tool)
+	}
+	root, err := cacheRoot()
+	if err != nil {
+		return Status{Tool: def.Name}, err
+	}
+
+	unlock, err := acquireInstallLock(ctx, def.Name)
+	if err != nil {
+		return Status{Tool: def.Name}, err
+	}
+	defer unlock()
+
+	dir := filepath.Join(root, def.Name, version)
+	destPaths := make(map[string]string, len(def.Binaries))
+	for _, bin := range def.Binaries {
+		path := filepath.Join(dir, bin.Executable)
+		if _, err := os.Stat(path); err != nil {
+			return Status{Tool: def.Name}, fmt.Errorf("%s %s is not in the tool cache", def.Name, version)
+		}
+		destPaths[bin.ID] = path
+	}
+	checksum, err := computeChecksum(destPaths[def.Binaries[0].ID])
+	if err != nil {
+		return Status{Tool: def.Name}, fmt.Errorf("checksum: %w", err)
+	}
+	return saveCacheInstall(def, version, destPaths, checksum, []string{"activated cached version"})
+}
@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func seedCachedYTDLP(t *testing.T, root string, versions ...string) {
+	t.Helper()
+	for _, v := range versions {
+		dir := filepath.Join(root, "yt-dlp", v)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "yt-dlp"), []byte("bin "+v), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestActivateSwitchesManifestToCachedVersion(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("POWERHOUR_TOOLS_DIR", root)
+	seedCachedYTDLP(t, root, "2024.07.16", "2025.01.01")
+
+	st, err := Activate(context.Background(), "yt-dlp", "2024.07.16")
+	if err != nil {
+		t.Fatalf("Activate: %v", err)
+	}
+	if st.Version != "2024.07.16" || st.Source != SourceCache {
+		t.Fatalf("status = %+v", st)
+	}
+	manifest, err := loadManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := manifest.Entries["yt-dlp"].Version; got != "2024.07.16" {
+		t.Fatalf("manifest version = %q", got)
+	}
+
+	if _, err := Activate(context.Background(), "yt-dlp", "1999.01.01"); err == nil {
+		t.Fatal("expected error activating an uncached version")
+	}
+}
+
+func TestUninstall(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("POWERHOUR_TOOLS_DIR", root)
+	seedCachedYTDLP(t, root, "2024.07.16", "2025.01.01")
+	if _, err := Activate(context.Background(), "yt-dlp", "2025.01.01"); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Uninstall(context.Background(), "yt-dlp", "2024.07.16")
+	if err != nil {
+		t.Fatalf("Uninstall old version: %v", err)
+	}
+	if len(res.Removed) != 1 || res.WasCurrent {
+		t.Fatalf("result = %+v", res)
+	}
+	if _, err := os.Stat(filepath.Join(root, "yt-dlp", "2025.01.01")); err != nil {
+		t.Fatal("current version was removed")
+	}
+
+	if _, err := Uninstall(context.Background(), "yt-dlp", "2024.07.16"); err == nil {
+		t.Fatal("expected error for a version that is no longer cached")
+	}
+
+	res, err = Uninstall(context.Background(), "yt-dlp", "")
+	if err != nil {
+		t.Fatalf("Uninstall all: %v", err)
+	}
+	if !res.WasCurrent {
+		t.Fatal("expected current version to be reported as removed")
+	}
+	manifest, err := loadManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := manifest.Entries["yt-dlp"]; ok {
+		t.Fatal("manifest still references the removed install")
+	}
+}
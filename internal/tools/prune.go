@@ -0,0 +1,258 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CachedVersion is one installed version directory in the tool cache.
+type CachedVersion struct {
+	Tool    string `json:"tool"`
+	Version string `json:"version"`
+	Path    string `json:"path"`
+	Bytes   int64  `json:"bytes"`
+	Current bool   `json:"current"`
+	Pinned  bool   `json:"pinned"`
+}
+
+// CacheUsage summarizes disk usage of the per-user tool cache.
+type CacheUsage struct {
+	Root           string          `json:"root"`
+	Versions       []CachedVersion `json:"versions"`
+	DownloadsBytes int64           `json:"downloads_bytes"`
+	StaleBytes     int64           `json:"stale_bytes"`
+	TotalBytes     int64           `json:"total_bytes"`
+}
+
+// PruneResult lists what PruneCache removed (or would remove on a dry run).
+type PruneResult struct {
+	Removed    []string `json:"removed"`
+	FreedBytes int64    `json:"freed_bytes"`
+}
+
+// CacheStats reports every cached tool version plus download and stale
+// install artifact usage. pinned maps tool name to a pinned version.
+func CacheStats(pinned map[string]string) (CacheUsage, error) {
+	root, err := cacheRoot()
+	if err != nil {
+		return CacheUsage{}, err
+	}
+	usage := CacheUsage{Root: root}
+
+	manifest, err := loadManifest()
+	if err != nil {
+		return CacheUsage{}, err
+	}
+
+	for _, name := range InstallableTools() {
+		entries, err := os.ReadDir(filepath.Join(root, name))
+		if err != nil {
+			continue
+		}
+		current := manifest.Entries[name]
+		for _, e := range entries {
+			if !e.IsDir() {
+				continue
+			}
+			dir := filepath.Join(root, name, e.Name())
+			v := CachedVersion{
+				Tool:    name,
+				Version: e.Name(),
+				Path:    dir,
+				Bytes:   dirSize(dir),
+				Current: current.Source == SourceCache && current.Version == e.Name(),
+				Pinned:  pinned[name] != "" && pinned[name] == e.Name(),
+			}
+			usage.Versions = append(usage.Versions, v)
+			usage.TotalBytes += v.Bytes
+		}
+	}
+	sort.Slice(usage.Versions, func(i, j int) bool {
+		if usage.Versions[i].Tool != usage.Versions[j].Tool {
+			return usage.Versions[i].Tool < usage.Versions[j].Tool
+		}
+		return usage.Versions[i].Version > usage.Versions[j].Version
+	})
+
+	if downloads, err := downloadsDir(); err == nil {
+		usage.DownloadsBytes = dirSize(downloads)
+		usage.TotalBytes += usage.DownloadsBytes
+	}
+	for _, path := range staleInstallArtifacts(root, staleLockAge) {
+		size := dirSize(path)
+		usage.StaleBytes += size
+		// Extract dirs live inside downloads and are already counted there.
+		if !strings.HasPrefix(path, filepath.Join(root, "downloads")) {
+			usage.TotalBytes += size
+		}
+	}
+	return usage, nil
+}
+
+// PruneCache removes cached tool versions that are neither the manifest's
+// current version nor in pinned (tool name to every version some project
+// pins), download archives for removed versions, and leftover install temp
+// dirs older than the stale lock age. It holds every tool's install lock so
+// no install is disturbed. With dryRun nothing is deleted or locked. Paths
+// that could not be removed are reported in the error and not counted as
+// freed.
+func PruneCache(ctx context.Context, pinned map[string][]string, dryRun bool) (PruneResult, error) {
+	if !dryRun {
+		for _, name := range InstallableTools() {
+			unlock, err := acquireInstallLock(ctx, name)
+			if err != nil {
+				return PruneResult{}, err
+			}
+			defer unlock()
+		}
+	}
+
+	usage, err := CacheStats(nil)
+	if err != nil {
+		return PruneResult{}, err
+	}
+
+	var result PruneResult
+	var errs []error
+	remove := func(path string, size int64) {
+		if !dryRun {
+			if err := os.RemoveAll(path); err != nil {
+				errs = append(errs, fmt.Errorf("remove %s: %w", path, err))
+				return
+			}
+		}
+		result.Removed = append(result.Removed, path)
+		result.FreedBytes += size
+	}
+
+	kept := map[toolVersion]bool{}
+	for _, v := range usage.Versions {
+		if v.Current || slices.Contains(pinned[v.Tool], v.Version) {
+			kept[toolVersion{v.Tool, v.Version}] = true
+			continue
+		}
+		remove(v.Path, v.Bytes)
+	}
+
+	if downloads, err := downloadsDir(); err == nil {
+		entries, _ := os.ReadDir(downloads)
+		for _, e := range entries {
+			if e.IsDir() {
+				continue
+			}
+			// Archives are version-qualified (<asset>.<version>); keep the
+			// ones backing a kept install so a forced reinstall stays offline.
+			// Partial downloads are handled with the other install artifacts.
+			if keptArchive(e.Name(), kept) || isPartialDownload(e.Name()) {
+				continue
+			}
+			path := filepath.Join(downloads, e.Name())
+			remove(path, fileSize(path))
+		}
+	}
+
+	// Bundle installs extract before taking any install lock, so young
+	// artifacts may still be in use even with every lock held.
+	for _, path := range staleInstallArtifacts(usage.Root, staleLockAge) {
+		remove(path, dirSize(path))
+	}
+	return result, errors.Join(errs...)
+}
+
+// CleanStaleInstallArtifacts removes temp and extract dirs left behind by
+// installs that crashed or were killed. Only artifacts older than the stale
+// lock age are touched so concurrent installs are never disturbed.
+func CleanStaleInstallArtifacts() {
+	root, err := cacheRoot()
+	if err != nil {
+		return
+	}
+	for _, path := range staleInstallArtifacts(root, staleLockAge) {
+		_ = os.RemoveAll(path)
+	}
+}
+
+// staleInstallArtifacts lists <tool>-tmp-* dirs in the cache root plus
+// *-extract-* dirs and download-*.tmp files in downloads/ older than minAge.
+func staleInstallArtifacts(root string, minAge time.Duration) []string {
+	var out []string
+	collect := func(dir string, match func(name string) bool) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return
+		}
+		for _, e := range entries {
+			if !match(e.Name()) {
+				continue
+			}
+			if minAge > 0 {
+				info, err := e.Info()
+				if err != nil || time.Since(info.ModTime()) < minAge {
+					continue
+				}
+			}
+			out = append(out, filepath.Join(dir, e.Name()))
+		}
+	}
+	collect(root, func(name string) bool { return strings.Contains(name, "-tmp-") })
+	collect(filepath.Join(root, "downloads"), func(name string) bool {
+		return strings.Contains(name, "-extract-") || isPartialDownload(name)
+	})
+	return out
+}
+
+func isPartialDownload(name string) bool {
+	return strings.HasPrefix(name, "download-") && strings.HasSuffix(name, ".tmp")
+}
+
+type toolVersion struct {
+	tool, version string
+}
+
+// keptArchive reports whether the download archive name backs a kept
+// install. Asset names start with the tool name (yt-dlp_linux, ...); an
+// archive that names no known tool is kept if any tool keeps its version.
+func keptArchive(name string, kept map[toolVersion]bool) bool {
+	owner := ""
+	for _, tool := range InstallableTools() {
+		if strings.HasPrefix(name, tool) && len(tool) > len(owner) {
+			owner = tool
+		}
+	}
+	for tv := range kept {
+		if (owner == "" || tv.tool == owner) && strings.HasSuffix(name, "."+tv.version) {
+			return true
+		}
+	}
+	return false
+}
+
+func dirSize(path string) int64 {
+	var total int64
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total
+}
+
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
@@ -0,0 +1,126 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPruneCacheKeepsCurrentAndPinned(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("POWERHOUR_TOOLS_DIR", root)
+
+	for _, v := range []string{"2024.01.01", "2024.07.16", "2025.01.01"} {
+		dir := filepath.Join(root, "yt-dlp", v)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "yt-dlp"), []byte("bin"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	downloads := filepath.Join(root, "downloads")
+	for _, dir := range []string{"ffmpeg-extract-123", "bundle-extract-fresh"} {
+		if err := os.MkdirAll(filepath.Join(downloads, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-2 * staleLockAge)
+	if err := os.Chtimes(filepath.Join(downloads, "ffmpeg-extract-123"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	// The ffmpeg archive shares a version with a kept yt-dlp install only.
+	for _, name := range []string{"yt-dlp_linux.2025.01.01", "yt-dlp_linux.2024.01.01", "ffmpeg-linux64.tar.xz.2025.01.01"} {
+		if err := os.WriteFile(filepath.Join(downloads, name), []byte("archive"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := saveManifest(Manifest{Entries: map[string]ManifestEntry{
+		"yt-dlp": {Tool: "yt-dlp", Version: "2025.01.01", Source: SourceCache},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	pinned := map[string][]string{"yt-dlp": {"2024.07.16"}}
+	dry, err := PruneCache(context.Background(), pinned, true)
+	if err != nil {
+		t.Fatalf("PruneCache dry run: %v", err)
+	}
+	if len(dry.Removed) != 4 {
+		t.Fatalf("dry run removed %v, want 4 items", dry.Removed)
+	}
+	if _, err := os.Stat(filepath.Join(root, "yt-dlp", "2024.01.01")); err != nil {
+		t.Fatal("dry run deleted files")
+	}
+
+	if _, err := PruneCache(context.Background(), pinned, false); err != nil {
+		t.Fatalf("PruneCache: %v", err)
+	}
+	for path, want := range map[string]bool{
+		filepath.Join(root, "yt-dlp", "2024.01.01"):                  false,
+		filepath.Join(root, "yt-dlp", "2024.07.16"):                  true,
+		filepath.Join(root, "yt-dlp", "2025.01.01"):                  true,
+		filepath.Join(downloads, "yt-dlp_linux.2025.01.01"):          true,
+		filepath.Join(downloads, "yt-dlp_linux.2024.01.01"):          false,
+		filepath.Join(downloads, "ffmpeg-linux64.tar.xz.2025.01.01"): false,
+		filepath.Join(downloads, "ffmpeg-extract-123"):               false,
+		filepath.Join(downloads, "bundle-extract-fresh"):             true,
+	} {
+		_, err := os.Stat(path)
+		if exists := err == nil; exists != want {
+			t.Errorf("%s exists=%v, want %v", path, exists, want)
+		}
+	}
+}
+
+func TestCleanStaleInstallArtifactsSkipsFresh(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("POWERHOUR_TOOLS_DIR", root)
+
+	fresh := filepath.Join(root, "ffmpeg-tmp-fresh")
+	stale := filepath.Join(root, "ffmpeg-tmp-stale")
+	for _, dir := range []string{fresh, stale} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-2 * staleLockAge)
+	if err := os.Chtimes(stale, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	CleanStaleInstallArtifacts()
+
+	if _, err := os.Stat(fresh); err != nil {
+		t.Errorf("fresh temp dir removed: %v", err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("stale temp dir not removed: %v", err)
+	}
+}
+
+func TestPruneCacheWaitsForInstallLock(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("POWERHOUR_TOOLS_DIR", root)
+
+	tmp := filepath.Join(root, "ffmpeg-tmp-installing")
+	if err := os.MkdirAll(tmp, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	unlock, err := acquireInstallLock(context.Background(), "ffmpeg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if _, err := PruneCache(ctx, nil, false); err == nil {
+		t.Fatal("PruneCache ran while an install held the lock")
+	}
+	if _, err := os.Stat(tmp); err != nil {
+		t.Errorf("in-progress install dir removed: %v", err)
+	}
+}
@@ -19,6 +19,9 @@ type releaseCacheEntry struct {
 	Checksum  string    `json:"checksum"`
 	Archive   string    `json:"archive"`
 	FetchedAt time.Time `json:"fetched_at"`
+
+	ChecksumURL  string `json:"checksum_url,omitempty"`
+	SignatureURL string `json:"signature_url,omitempty"`
 }
 
 type releaseCache struct {
@@ -78,10 +81,12 @@ func cachedLatestRelease(tool string) (releaseSpec, bool) {
 		return releaseSpec{}, false
 	}
 	return releaseSpec{
-		Version:  entry.Version,
-		URL:      entry.URL,
-		Checksum: entry.Checksum,
-		Archive:  archiveFormat(entry.Archive),
+		Version:      entry.Version,
+		URL:          entry.URL,
+		Checksum:     entry.Checksum,
+		Archive:      archiveFormat(entry.Archive),
+		ChecksumURL:  entry.ChecksumURL,
+		SignatureURL: entry.SignatureURL,
 	}, true
 }
 
@@ -106,6 +111,9 @@ func cacheLatestRelease(tool string, spec releaseSpec) {
 		Checksum:  spec.Checksum,
 		Archive:   string(spec.Archive),
 		FetchedAt: time.Now(),
+
+		ChecksumURL:  spec.ChecksumURL,
+		SignatureURL: spec.SignatureURL,
 	}
 	saveReleaseCache(rc)
 }
@@ -72,7 +72,7 @@ func fetchYTDLPRelease(ctx context.Context, version string) (releaseSpec, error)
 	}
 
 	endpoints := ytDlpReleaseEndpoints(version)
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := httpClient(ctx, 30*time.Second)
 
 	var lastErr error
 	for _, endpoint := range endpoints {
@@ -121,10 +121,12 @@ func fetchYTDLPRelease(ctx context.Context, version string) (releaseSpec, error)
 		}
 
 		return releaseSpec{
-			Version:  versionTag,
-			URL:      assetURL,
-			Archive:  archiveFormatNone,
-			Checksum: "",
+			Version:      versionTag,
+			URL:          assetURL,
+			Archive:      archiveFormatNone,
+			Checksum:     "",
+			ChecksumURL:  assetURLByName(release.Assets, "SHA2-256SUMS"),
+			SignatureURL: assetURLByName(release.Assets, "SHA2-256SUMS.sig"),
 		}, nil
 	}
 
@@ -177,3 +179,12 @@ func selectYtDlpAsset(assets []githubReleaseAsset, candidates []string) (string,
 	}
 	return "", fmt.Errorf("no yt-dlp asset available for platform")
 }
+
+func assetURLByName(assets []githubReleaseAsset, name string) string {
+	for _, asset := range assets {
+		if asset.Name == name {
+			return asset.BrowserDownloadURL
+		}
+	}
+	return ""
+}
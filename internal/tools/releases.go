@@ -21,51 +21,70 @@ type releaseSpec struct {
 	Archive         archiveFormat
 	StripComponents int
 	Files           map[string]string
+	// ChecksumURL points at a SHA256 manifest ("<hex>  <filename>" lines)
+	// published alongside the release. Used when Checksum is empty.
+	ChecksumURL string
+	// SignatureURL points at a detached GPG signature over the manifest.
+	SignatureURL string
 }
 
-// releaseIndex captures known download artefacts per tool/OS/arch. Checksums are
-// currently left blank; populate them as part of the release process when the
-// authoritative SHA256 values are available.
+// ytDlpSums is the release download prefix for yt-dlp's SHA2-256SUMS manifests.
+const ytDlpSums = "https://github.com/yt-dlp/yt-dlp/releases/download/"
+
+// releaseIndex captures known download artefacts per tool/OS/arch. Every
+// pinned release must carry either a Checksum or a ChecksumURL so downloads
+// are always verified; the upstream SHA2-256SUMS manifest is preferred over
+// hardcoded digests.
 var releaseIndex = map[string]map[string]map[string]releaseSpec{
 	"yt-dlp": {
 		"darwin-amd64": {
 			"2024.07.16": {
-				Version:  "2024.07.16",
-				URL:      "https://github.com/yt-dlp/yt-dlp/releases/download/2024.07.16/yt-dlp_macos",
-				Checksum: "",
-				Archive:  archiveFormatNone,
+				Version:      "2024.07.16",
+				URL:          "https://github.com/yt-dlp/yt-dlp/releases/download/2024.07.16/yt-dlp_macos",
+				Checksum:     "",
+				Archive:      archiveFormatNone,
+				ChecksumURL:  ytDlpSums + "2024.07.16/SHA2-256SUMS",
+				SignatureURL: ytDlpSums + "2024.07.16/SHA2-256SUMS.sig",
 			},
 		},
 		"darwin-arm64": {
 			"2024.07.16": {
-				Version:  "2024.07.16",
-				URL:      "https://github.com/yt-dlp/yt-dlp/releases/download/2024.07.16/yt-dlp_macos",
-				Checksum: "",
-				Archive:  archiveFormatNone,
+				Version:      "2024.07.16",
+				URL:          "https://github.com/yt-dlp/yt-dlp/releases/download/2024.07.16/yt-dlp_macos",
+				Checksum:     "",
+				Archive:      archiveFormatNone,
+				ChecksumURL:  ytDlpSums + "2024.07.16/SHA2-256SUMS",
+				SignatureURL: ytDlpSums + "2024.07.16/SHA2-256SUMS.sig",
 			},
 		},
 		"linux-amd64": {
 			"2024.07.16": {
-				Version:  "2024.07.16",
-				URL:      "https://github.com/yt-dlp/yt-dlp/releases/download/2024.07.16/yt-dlp_linux",
-				Checksum: "",
-				Archive:  archiveFormatNone,
+				Version:      "2024.07.16",
+				URL:          "https://github.com/yt-dlp/yt-dlp/releases/download/2024.07.16/yt-dlp_linux",
+				Checksum:     "",
+				Archive:      archiveFormatNone,
+				ChecksumURL:  ytDlpSums + "2024.07.16/SHA2-256SUMS",
+				SignatureURL: ytDlpSums + "2024.07.16/SHA2-256SUMS.sig",
 			},
 		},
 		"linux-arm64": {
 			"2024.07.16": {
-				Version:  "2024.07.16",
-				URL:      "https://github.com/yt-dlp/yt-dlp/releases/download/2024.07.16/yt-dlp_linux_aarch64",
-				Checksum: "",
-				Archive:  archiveFormatNone,
+				Version:      "2024.07.16",
+				URL:          "https://github.com/yt-dlp/yt-dlp/releases/download/2024.07.16/yt-dlp_linux_aarch64",
+				Checksum:     "",
+				Archive:      archiveFormatNone,
+				ChecksumURL:  ytDlpSums + "2024.07.16/SHA2-256SUMS",
+				SignatureURL: ytDlpSums + "2024.07.16/SHA2-256SUMS.sig",
 			},
 		},
 		"windows-amd64": {
 			"2024.07.16": {
-				Version:  "2024.07.16",
-				URL:      "https://github.com/yt-dlp/yt-dlp/releases/download/2024.07.16/yt-dlp.exe",
-				Checksum: "",
-				Archive:  archiveFormatNone,
+				Version:      "2024.07.16",
+				URL:          "https://github.com/yt-dlp/yt-dlp/releases/download/2024.07.16/yt-dlp.exe",
+				Checksum:     "",
+				Archive:      archiveFormatNone,
+				ChecksumURL:  ytDlpSums + "2024.07.16/SHA2-256SUMS",
+				SignatureURL: ytDlpSums + "2024.07.16/SHA2-256SUMS.sig",
 			},
 		},
 	},
@@ -0,0 +1,142 @@
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Signature verifiers accepted by tools.signature in the global config.
+const (
+	SignatureGPG      = "gpg"
+	SignatureMinisign = "minisign"
+)
+
+// verifyRelease resolves the expected SHA256 for spec before its artefact is
+// downloaded. When the spec only carries a ChecksumURL the manifest is fetched
+// into downloads and, if a signature verifier is configured, its detached
+// signature is checked first. The returned spec has Checksum populated when
+// verification succeeded; notes describe what was (or was not) verified.
+//
+// With tools.require_verified_downloads enabled any failure to establish a
+// checksum is fatal. Otherwise unverifiable downloads proceed with a note.
+func verifyRelease(ctx context.Context, spec releaseSpec, downloads string, policy GlobalToolsConfig) (releaseSpec, []string, error) {
+	if spec.Checksum != "" {
+		return spec, []string{"sha256 pinned"}, nil
+	}
+
+	fail := func(err error) (releaseSpec, []string, error) {
+		if policy.RequireVerified {
+			return spec, nil, fmt.Errorf("refusing unverified download of %s: %w", spec.URL, err)
+		}
+		return spec, []string{fmt.Sprintf("unverified download: %v", err)}, nil
+	}
+
+	if spec.ChecksumURL == "" {
+		return fail(fmt.Errorf("no sha256 manifest published"))
+	}
+
+	manifestPath := filepath.Join(downloads, sumsFileName(spec, path.Base(spec.ChecksumURL)))
+	if err := downloadArtifact(ctx, manifestPath, spec.ChecksumURL, ""); err != nil {
+		return fail(fmt.Errorf("fetch sha256 manifest: %w", err))
+	}
+
+	var notes []string
+	if policy.Signature != "" {
+		if err := verifyManifestSignature(ctx, spec, manifestPath, downloads, policy); err != nil {
+			// A bad signature is never downgraded to a warning: it means the
+			// manifest itself cannot be trusted.
+			return spec, nil, err
+		}
+		notes = append(notes, fmt.Sprintf("%s signature verified", policy.Signature))
+	}
+
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return fail(fmt.Errorf("read sha256 manifest: %w", err))
+	}
+	sum, ok := lookupManifestChecksum(data, path.Base(spec.URL))
+	if !ok {
+		return fail(fmt.Errorf("%s not listed in sha256 manifest", path.Base(spec.URL)))
+	}
+
+	spec.Checksum = sum
+	notes = append(notes, "sha256 verified against release manifest")
+	return spec, notes, nil
+}
+
+// lookupManifestChecksum finds name in a sha256sum-style manifest. Both the
+// text ("<hex>  name") and binary ("<hex> *name") forms are accepted.
+func lookupManifestChecksum(manifest []byte, name string) (string, bool) {
+	scanner := bufio.NewScanner(bytes.NewReader(manifest))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		sum, file := fields[0], strings.TrimPrefix(fields[1], "*")
+		if file != name || len(sum) != 64 {
+			continue
+		}
+		return strings.ToLower(sum), true
+	}
+	return "", false
+}
+
+func verifyManifestSignature(ctx context.Context, spec releaseSpec, manifestPath, downloads string, policy GlobalToolsConfig) error {
+	switch policy.Signature {
+	case SignatureGPG:
+		if spec.SignatureURL == "" {
+			return fmt.Errorf("gpg verification requested but release publishes no signature")
+		}
+		sigPath := filepath.Join(downloads, sumsFileName(spec, path.Base(spec.SignatureURL)))
+		if err := downloadArtifact(ctx, sigPath, spec.SignatureURL, ""); err != nil {
+			return fmt.Errorf("fetch manifest signature: %w", err)
+		}
+		args := []string{"--batch", "--verify"}
+		if policy.SignatureKey != "" {
+			args = []string{"--batch", "--no-default-keyring", "--keyring", policy.SignatureKey, "--verify"}
+		}
+		args = append(args, sigPath, manifestPath)
+		return runVerifier(ctx, "gpg", args...)
+	case SignatureMinisign:
+		if policy.SignatureKey == "" {
+			return fmt.Errorf("minisign verification requires tools.signature_key")
+		}
+		sigURL := spec.ChecksumURL + ".minisig"
+		sigPath := filepath.Join(downloads, sumsFileName(spec, path.Base(sigURL)))
+		if err := downloadArtifact(ctx, sigPath, sigURL, ""); err != nil {
+			return fmt.Errorf("fetch manifest signature: %w", err)
+		}
+		return runVerifier(ctx, "minisign", "-V", "-m", manifestPath, "-x", sigPath, "-p", policy.SignatureKey)
+	default:
+		return fmt.Errorf("unknown signature verifier %q (want gpg or minisign)", policy.Signature)
+	}
+}
+
+func runVerifier(ctx context.Context, name string, args ...string) error {
+	bin, err := exec.LookPath(name)
+	if err != nil {
+		return fmt.Errorf("%s not found on PATH: %w", name, err)
+	}
+	out, err := exec.CommandContext(ctx, bin, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s signature verification failed: %w: %s", name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// sumsFileName version-qualifies manifest downloads so releases don't
+// overwrite each other's SHA2-256SUMS in the shared downloads dir.
+func sumsFileName(spec releaseSpec, base string) string {
+	if spec.Version == "" {
+		return base
+	}
+	return base + "." + spec.Version
+}
@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStaticReleasesPublishChecksums(t *testing.T) {
+	for tool, platforms := range releaseIndex {
+		for platform, versions := range platforms {
+			for version, spec := range versions {
+				if spec.Checksum == "" && spec.ChecksumURL == "" {
+					t.Errorf("%s %s %s: release has neither Checksum nor ChecksumURL", tool, platform, version)
+				}
+			}
+		}
+	}
+}
+
+func TestLookupManifestChecksum(t *testing.T) {
+	sum := strings.Repeat("ab", 32)
+	manifest := []byte(strings.Join([]string{
+		strings.Repeat("0", 64) + "  yt-dlp.exe",
+		strings.ToUpper(sum) + "  yt-dlp_linux",
+		strings.Repeat("1", 64) + " *yt-dlp_macos",
+		"garbage line",
+	}, "\n"))
+
+	tests := []struct {
+		name   string
+		file   string
+		want   string
+		wantOK bool
+	}{
+		{name: "text mode", file: "yt-dlp_linux", want: sum, wantOK: true},
+		{name: "binary mode", file: "yt-dlp_macos", want: strings.Repeat("1", 64), wantOK: true},
+		{name: "missing", file: "yt-dlp_linux_aarch64"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := lookupManifestChecksum(manifest, tt.file)
+			if ok != tt.wantOK || got != tt.want {
+				t.Fatalf("lookupManifestChecksum(%q) = %q, %v; want %q, %v", tt.file, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestVerifyRelease(t *testing.T) {
+	sum := strings.Repeat("cd", 32)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/SHA2-256SUMS":
+			_, _ = w.Write([]byte(sum + "  yt-dlp_linux\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name     string
+		spec     releaseSpec
+		policy   GlobalToolsConfig
+		want     string
+		wantErr  bool
+		wantNote string
+	}{
+		{
+			name:     "manifest resolves checksum",
+			spec:     releaseSpec{Version: "1", URL: srv.URL + "/yt-dlp_linux", ChecksumURL: srv.URL + "/SHA2-256SUMS"},
+			want:     sum,
+			wantNote: "release manifest",
+		},
+		{
+			name:     "missing manifest is a warning by default",
+			spec:     releaseSpec{Version: "1", URL: srv.URL + "/yt-dlp_linux"},
+			wantNote: "unverified download",
+		},
+		{
+			name:    "missing manifest is fatal when required",
+			spec:    releaseSpec{Version: "1", URL: srv.URL + "/yt-dlp_linux"},
+			policy:  GlobalToolsConfig{RequireVerified: true},
+			wantErr: true,
+		},
+		{
+			name:    "asset absent from manifest is fatal when required",
+			spec:    releaseSpec{Version: "1", URL: srv.URL + "/yt-dlp.exe", ChecksumURL: srv.URL + "/SHA2-256SUMS"},
+			policy:  GlobalToolsConfig{RequireVerified: true},
+			wantErr: true,
+		},
+		{
+			name:    "minisign without key is fatal",
+			spec:    releaseSpec{Version: "1", URL: srv.URL + "/yt-dlp_linux", ChecksumURL: srv.URL + "/SHA2-256SUMS"},
+			policy:  GlobalToolsConfig{Signature: SignatureMinisign},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, notes, err := verifyRelease(context.Background(), tt.spec, t.TempDir(), tt.policy)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("verifyRelease: %v", err)
+			}
+			if got.Checksum != tt.want {
+				t.Fatalf("Checksum = %q, want %q", got.Checksum, tt.want)
+			}
+			if !strings.Contains(strings.Join(notes, "; "), tt.wantNote) {
+				t.Fatalf("notes %v missing %q", notes, tt.wantNote)
+			}
+		})
+	}
+}
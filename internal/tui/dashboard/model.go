@@ -2274,11 +2274,11 @@ func (m Model) startCollectionFetchJob(cvIdx int, rows []csvplan.CollectionRow,
 	m.statusMsg = label + "..."
 	events := make(chan dashboardJobEvent, max(16, len(rows)*4))
 	m.job = dashboardJobState{active: true, label: label, events: events}
-	go runDashboardFetchJob(m.pp, cvIdx, rows, all, events)
+	go runDashboardFetchJob(m.pp, cvIdx, collName, rows, all, events)
 	return m
 }
 
-func runDashboardFetchJob(pp paths.ProjectPaths, cvIdx int, rows []csvplan.CollectionRow, all bool, events chan<- dashboardJobEvent) {
+func runDashboardFetchJob(pp paths.ProjectPaths, cvIdx int, collName string, rows []csvplan.CollectionRow, all bool, events chan<- dashboardJobEvent) {
 	defer close(events)
 	ctx := context.Background()
 	logger := log.New(io.Discard, "", 0)
@@ -2301,7 +2301,7 @@ func runDashboardFetchJob(pp paths.ProjectPaths, cvIdx int, rows []csvplan.Colle
 	}
 	for _, row := range rows {
 		events <- jobRowStatusEvent{collectionIdx: cvIdx, rowIndex: row.Index, status: "fetching"}
-		result, err := svc.Resolve(ctx, idx, row.ToRow(), cache.ResolveOptions{})
+		result, err := svc.Resolve(ctx, idx, row.ToRow(), cache.ResolveOptions{Collection: collName})
 		if err != nil {
 			events <- jobRowStatusEvent{collectionIdx: cvIdx, rowIndex: row.Index, status: "error"}
 			events <- jobCompletedEvent{label: "Fetch", err: err}
@@ -2382,17 +2382,21 @@ func runDashboardRenderJob(pp paths.ProjectPaths, cfg config.Config, collName st
 		events <- jobCompletedEvent{label: "Render", err: err}
 		return
 	}
+	// Track numbers follow the whole timeline, not just this collection.
+	var tracks map[string]int
+	if all, err := resolver.LoadCollections(); err == nil {
+		tracks, _ = render.TimelineTracks(pp, cfg, all)
+	}
 	filenameTemplate := cfg.SegmentFilenameTemplate()
 	segments := make([]render.Segment, 0, len(collectionClips))
 	for _, cc := range collectionClips {
-		seg, err := buildCollectionRenderSegmentLocal(pp, cfg, idx, cc)
+		seg, err := render.BuildCollectionSegment(pp, cfg, idx, cc)
 		if err != nil {
 			events <- jobCompletedEvent{label: "Render", err: err}
 			return
 		}
-		if prior, ok := rs.Segments[seg.OutputPath]; ok {
-			seg.StoredHash = prior.InputHash
-		}
+		seg.Tags = render.NewSegmentTags(cc.Clip, filepath.Base(pp.Root), tracks[seg.OutputPath])
+		seg.StoredHash = rs.StoredHash(seg, cfg)
 		segments = append(segments, seg)
 	}
 	actions := renderstate.DetectChanges(rs, segments, cfg, filenameTemplate, false)
@@ -2428,10 +2432,12 @@ func runDashboardRenderJob(pp paths.ProjectPaths, cfg config.Config, collName st
 		if !res.Skipped && res.OutputPath != "" {
 			if seg, ok := segByPath[res.OutputPath]; ok {
 				rs.Segments[res.OutputPath] = renderstate.SegmentState{
-					InputHash:  renderstate.SegmentInputHash(seg, filenameTemplate),
-					RenderedAt: time.Now(),
-					SourcePath: seg.CachedPath,
-					DurationS:  float64(seg.Clip.DurationSeconds),
+					InputHash:    renderstate.SegmentInputHash(seg, filenameTemplate),
+					InputParts:   renderstate.SegmentInputParts(seg, filenameTemplate),
+					SettingsHash: renderstate.SettingsHash(seg, cfg),
+					RenderedAt:   time.Now(),
+					SourcePath:   seg.CachedPath,
+					DurationS:    float64(seg.Clip.DurationSeconds),
 				}
 			}
 		}
@@ -2472,96 +2478,8 @@ func (r *dashboardRenderReporter) Complete(res render.Result) {
 	r.events <- jobRowStatusEvent{collectionIdx: r.collectionIdx, rowIndex: res.TypeIndex, status: status}
 }
 
-func buildCollectionRenderSegmentLocal(pp paths.ProjectPaths, cfg config.Config, idx *cache.Index, collClip project.CollectionClip) (render.Segment, error) {
-	clip := collClip.Clip
-	clip.Row.DurationSeconds = clip.DurationSeconds
-	if clip.Row.Index <= 0 {
-		clip.Row.Index = clip.TypeIndex
-		if clip.Row.Index <= 0 {
-			clip.Row.Index = clip.Sequence
-		}
-	}
-
-	segment := render.Segment{
-		Clip:     clip,
-		Overlays: collClip.Overlays,
-	}
-
-	outputDir := collClip.OutputDir
-	if !filepath.IsAbs(outputDir) {
-		outputDir = filepath.Join(pp.SegmentsDir, outputDir)
-	}
-	baseName := render.SegmentBaseName(cfg.SegmentFilenameTemplate(), segment)
-	segment.OutputPath = filepath.Join(outputDir, baseName+".mp4")
-
-	link := clip.Row.Link
-	isURL := strings.HasPrefix(link, "http://") || strings.HasPrefix(link, "https://") || strings.HasPrefix(link, "youtu")
-	if !isURL {
-		link = strings.Trim(link, "'\"")
-		sourcePath := link
-		if !filepath.IsAbs(sourcePath) {
-			sourcePath = filepath.Join(pp.Root, link)
-		}
-		if _, err := os.Stat(sourcePath); err != nil {
-			return segment, err
-		}
-		segment.SourcePath = sourcePath
-		segment.CachedPath = sourcePath
-		return segment, nil
-	}
-
-	entry, ok, err := resolveDashboardEntryForRow(pp, idx, clip.Row)
-	if err != nil {
-		return segment, err
-	}
-	if !ok {
-		return segment, fmt.Errorf("video not downloaded; may be unavailable or region-locked")
-	}
-	segment.Entry = entry
-	segment.SourcePath = entry.CachedPath
-	segment.CachedPath = entry.CachedPath
-	return segment, nil
-}
-
 func applySequenceEntryFadesLocal(cfg config.Config, clips []project.CollectionClip) {
-	project.ApplySequenceEntryFades(cfg, clips)
-}
-
-func resolveDashboardEntryForRow(pp paths.ProjectPaths, idx *cache.Index, row csvplan.Row) (cache.Entry, bool, error) {
-	if idx == nil {
-		return cache.Entry{}, false, fmt.Errorf("row %03d: cache index is nil", row.Index)
-	}
-
-	link := strings.TrimSpace(row.Link)
-	if link == "" {
-		return cache.Entry{}, false, fmt.Errorf("row %03d missing link", row.Index)
-	}
-
-	if isURL(link) {
-		key, exists := idx.LookupLink(link)
-		if !exists {
-			return cache.Entry{}, false, nil
-		}
-		entry, ok := idx.GetByIdentifier(key)
-		if !ok || strings.TrimSpace(entry.CachedPath) == "" {
-			return cache.Entry{}, false, nil
-		}
-		return entry, true, nil
-	}
-
-	path := link
-	if !filepath.IsAbs(path) {
-		path = filepath.Join(pp.Root, link)
-	}
-	abs, err := filepath.Abs(path)
-	if err != nil {
-		return cache.Entry{}, false, err
-	}
-	entry, ok := idx.GetByIdentifier(abs)
-	if !ok || strings.TrimSpace(entry.CachedPath) == "" {
-		return cache.Entry{}, false, nil
-	}
-	return entry, true, nil
+	project.ApplySequenceEntryOverrides(cfg, clips)
 }
 
 // processAddTimelineEntry adds a new sequence entry to the timeline.
@@ -2952,7 +2870,7 @@ func writeCollection(m Model, cvIdx int) Model {
 	oldAddSelected := v.addSelected
 
 	coll.Rows = v.rows
-	if coll.PlanFormat != "yaml" {
+	if coll.PlanFormat != csvplan.PlanFormatYAML && coll.PlanFormat != csvplan.PlanFormatJSON {
 		coll.Headers = csvplan.MergeHeaders(coll.Headers, v.rows)
 	}
 	err := project.WriteCollectionPlan(coll)
@@ -3162,8 +3080,8 @@ func reloadCollection(m Model, cvIdx int) Model {
 
 	var rows []csvplan.CollectionRow
 	var err error
-	if coll.PlanFormat == "yaml" {
-		result, yamlErr := csvplan.LoadCollectionYAML(coll.Plan, opts)
+	if coll.PlanFormat == csvplan.PlanFormatYAML || coll.PlanFormat == csvplan.PlanFormatJSON {
+		result, yamlErr := csvplan.LoadCollectionStructured(coll.Plan, opts)
 		rows = result.Rows
 		coll.Headers = result.Columns
 		coll.Defaults = result.Defaults
@@ -11,10 +11,12 @@ import (
 )
 
 type songSuggestion struct {
-	Title  string
-	Artist string
-	Link   string
-	score  int
+	Title       string
+	Artist      string
+	Link        string
+	License     string
+	Attribution string
+	score       int
 }
 
 func cacheFieldValues(entry cache.Entry, field string) []string {
@@ -35,6 +37,8 @@ func cacheFieldValues(entry cache.Entry, field string) []string {
 		return []string{entry.UploadDate}
 	case "description":
 		return []string{entry.Description}
+	case "license":
+		return []string{entry.License}
 	case "source":
 		return []string{entry.Source}
 	case "links":
@@ -74,6 +78,8 @@ func setCacheEntryField(entry *cache.Entry, field, value string) bool {
 		entry.UploadDate = value
 	case "description":
 		entry.Description = value
+	case "license":
+		entry.License = value
 	default:
 		return false
 	}
@@ -128,6 +134,12 @@ type cacheLookup struct {
 func (l cacheLookup) titleFields() []string  { return l.resolve("title") }
 func (l cacheLookup) artistFields() []string { return l.resolve("artist") }
 func (l cacheLookup) linkFields() []string   { return l.resolve("link") }
+func (l cacheLookup) licenseFields() []string {
+	return l.resolve("license")
+}
+func (l cacheLookup) attributionFields() []string {
+	return l.resolve("attribution")
+}
 
 func (l cacheLookup) resolve(key string) []string {
 	if fields, ok := l.fieldMap[key]; ok && len(fields) > 0 {
@@ -140,6 +152,10 @@ func (l cacheLookup) resolve(key string) []string {
 		return []string{"artist", "uploader", "channel"}
 	case "link":
 		return []string{"source", "links"}
+	case "license":
+		return []string{"license"}
+	case "attribution":
+		return []string{"uploader", "channel"}
 	default:
 		return nil
 	}
@@ -203,9 +219,11 @@ func suggestionFromEntry(entry cache.Entry, lookup cacheLookup) (songSuggestion,
 		return songSuggestion{}, false
 	}
 	return songSuggestion{
-		Title:  strings.TrimSpace(title),
-		Artist: strings.TrimSpace(artist),
-		Link:   strings.TrimSpace(link),
+		Title:       strings.TrimSpace(title),
+		Artist:      strings.TrimSpace(artist),
+		Link:        strings.TrimSpace(link),
+		License:     firstConfiguredCacheValue(entry, lookup.licenseFields()),
+		Attribution: firstConfiguredCacheValue(entry, lookup.attributionFields()),
 	}, true
 }
 
@@ -315,6 +333,14 @@ func applySuggestionToRow(coll project.Collection, row *csvplan.CollectionRow, s
 	if suggestion.Artist != "" && collectionHasField(coll, "artist") {
 		row.CustomFields["artist"] = suggestion.Artist
 	}
+	// license/attribution are optional columns; only fill them when the
+	// collection declares them and the user hasn't typed a value already.
+	if suggestion.License != "" && collectionHasField(coll, "license") && row.CustomFields["license"] == "" {
+		row.CustomFields["license"] = suggestion.License
+	}
+	if suggestion.Attribution != "" && collectionHasField(coll, "attribution") && row.CustomFields["attribution"] == "" {
+		row.CustomFields["attribution"] = suggestion.Attribution
+	}
 	if suggestion.Link != "" {
 		row.Link = suggestion.Link
 		row.CustomFields[collectionLinkHeader(coll)] = suggestion.Link
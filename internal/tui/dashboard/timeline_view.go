@@ -192,6 +192,12 @@ func (v timelineView) view(cacheStatus map[string]string) string {
 			if entry.Interleave != nil {
 				b.WriteString(fadeDim.Render(fmt.Sprintf(" · interleave: %s every %d", entry.Interleave.Collection, entry.Interleave.Every)))
 			}
+			if entry.Duration > 0 {
+				b.WriteString(fadeDim.Render(fmt.Sprintf(" · %ds", entry.Duration)))
+			}
+			if len(entry.Overlays) > 0 {
+				b.WriteString(fadeDim.Render(" · overlays"))
+			}
 		}
 
 		// Fade info, right side.
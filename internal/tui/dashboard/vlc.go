@@ -128,7 +128,8 @@ func vlcRunning() bool {
 	}
 }
 
-// resolveRenderedSegmentPath returns the rendered segment output path for a collection row.
+// resolveRenderedSegmentPath returns the rendered segment output path for a
+// collection row; for a row with windows, the first window's segment.
 func resolveRenderedSegmentPath(pp paths.ProjectPaths, cfg config.Config, collName string, coll project.Collection, row csvplan.CollectionRow) string {
 	collCfg := cfg.Collections[collName]
 	fadeIn, fadeOut := config.ResolveFade(collCfg.Fade, collCfg.FadeIn, collCfg.FadeOut)
@@ -143,6 +144,7 @@ func resolveRenderedSegmentPath(pp paths.ProjectPaths, cfg config.Config, collNa
 		FadeInSeconds:   fadeIn,
 		FadeOutSeconds:  fadeOut,
 	}
+	clip = project.WindowClips(clip)[0]
 	clip.Row.DurationSeconds = clip.DurationSeconds
 	if clip.Row.Index <= 0 {
 		clip.Row.Index = clip.TypeIndex
@@ -153,12 +155,7 @@ func resolveRenderedSegmentPath(pp paths.ProjectPaths, cfg config.Config, collNa
 		Overlays: collCfg.Overlays,
 	}
 
-	tmpl := cfg.SegmentFilenameTemplate()
-	outputDir := coll.OutputDir
-	if !filepath.IsAbs(outputDir) {
-		outputDir = filepath.Join(pp.SegmentsDir, outputDir)
-	}
-	return filepath.Join(outputDir, render.SegmentBaseName(tmpl, seg)+".mp4")
+	return render.CollectionSegmentPath(cfg, pp.SegmentsDir, collName, coll.OutputDir, seg)
 }
 
 // resolveAllTimelineSegmentPaths returns all rendered segment paths in timeline order.
@@ -213,7 +210,7 @@ func resolveSequenceEntrySegmentPaths(pp paths.ProjectPaths, cfg config.Config,
 // as ResolveTimeline.
 func assignTimelineToSequenceEntries(timeline config.TimelineConfig, collections map[string]project.Collection, totalEntries int) []int {
 	result := make([]int, 0, totalEntries)
-	placements, err := project.BuildTimelinePlacements(timeline, collections)
+	placements, err := project.BuildTimelinePlacements(timeline, project.WithoutSkippedRows(collections))
 	if err != nil {
 		return result
 	}
@@ -0,0 +1,225 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// logTailBytes is how much of the end of a log the pane reads.
+	logTailBytes = 16 << 10
+	// logTailEvery re-reads the log every this many ticks (~600ms).
+	logTailEvery = 4
+	// minPaneWidth and minTableWidth decide whether the pane fits beside
+	// the table; otherwise it goes below.
+	minPaneWidth  = 32
+	minTableWidth = 40
+	// paneHeightUnknown is the pane height when the terminal's is unknown.
+	paneHeightUnknown = 16
+)
+
+// DetailParam is one "name  value" line of the detail pane.
+type DetailParam struct {
+	Name  string
+	Value string
+}
+
+// Detail is what the detail pane shows for a row: its resolved parameters
+// and the log to tail. LogPath may not exist yet; the pane waits for it.
+type Detail struct {
+	Params  []DetailParam
+	LogPath string
+}
+
+// DetailFunc returns the detail of the row with key.
+type DetailFunc func(key string) Detail
+
+// logTailMsg carries the end of a row's log, read off the event loop.
+type logTailMsg struct {
+	key   string
+	lines []string
+	err   error
+}
+
+// SetDetail makes rows selectable: the arrow keys move a cursor and enter
+// opens a pane beside the table with fn's parameters and a live tail of the
+// row's log. Call this before the program starts.
+func (m *ProgressModel) SetDetail(fn DetailFunc) {
+	m.detail = fn
+}
+
+// moveCursor moves the selection by delta filtered rows, scrolling to keep
+// it in view, and stops following. With nothing selected yet it selects the
+// top visible row.
+func (m *ProgressModel) moveCursor(delta int) tea.Cmd {
+	shown := m.shownRows()
+	if len(shown) == 0 {
+		return nil
+	}
+	pos := -1
+	for i, idx := range shown {
+		if idx == m.cursor {
+			pos = i
+			break
+		}
+	}
+	if pos < 0 {
+		pos = min(m.scrollTop, len(shown)-1)
+	} else {
+		pos = max(0, min(pos+delta, len(shown)-1))
+	}
+	m.follow = false
+	m.cursor = shown[pos]
+	m.autoScroll(m.cursor)
+	return m.refreshDetail()
+}
+
+// toggleDetail opens the pane on the selected row, or closes it.
+func (m *ProgressModel) toggleDetail() tea.Cmd {
+	if m.detailOpen {
+		m.detailOpen = false
+		return nil
+	}
+	if m.cursor < 0 {
+		if shown := m.shownRows(); len(shown) > 0 {
+			m.cursor = shown[min(m.scrollTop, len(shown)-1)]
+		}
+	}
+	if m.cursor < 0 {
+		return nil
+	}
+	m.detailOpen = true
+	return m.refreshDetail()
+}
+
+// refreshDetail points the open pane at the selected row, reloading its
+// parameters when the row changed, and returns the command that reads the
+// log tail.
+func (m *ProgressModel) refreshDetail() tea.Cmd {
+	if !m.detailOpen || m.detail == nil || m.cursor < 0 || m.cursor >= len(m.rows) {
+		return nil
+	}
+	key := m.rows[m.cursor].Key
+	if key != m.detailKey {
+		m.detailKey = key
+		m.detailInfo = m.detail(key)
+		m.logTail, m.logErr = nil, nil
+	}
+	return readLogTail(key, m.detailInfo.LogPath)
+}
+
+func readLogTail(key, path string) tea.Cmd {
+	if path == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		lines, err := tailLines(path, logTailBytes)
+		return logTailMsg{key: key, lines: lines, err: err}
+	}
+}
+
+// tailLines returns the non-empty lines in the last limit bytes of path.
+// Carriage returns split lines too, so each ffmpeg or yt-dlp progress
+// update is its own line.
+func tailLines(path string, limit int64) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	cut := info.Size() > limit
+	if cut {
+		if _, err := f.Seek(-limit, io.SeekEnd); err != nil {
+			return nil, err
+		}
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.FieldsFunc(string(data), func(r rune) bool { return r == '\n' || r == '\r' })
+	if cut && len(fields) > 0 {
+		fields = fields[1:] // probably starts mid-line
+	}
+	lines := fields[:0]
+	for _, line := range fields {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+// paneLayout returns the pane's outer width and whether it sits beside the
+// table.
+func (m ProgressModel) paneLayout() (int, bool) {
+	if m.termWidth <= 0 {
+		return minPaneWidth * 2, false
+	}
+	width := max(m.termWidth*2/5, minPaneWidth)
+	if m.termWidth-width-1 < minTableWidth {
+		return m.termWidth, false
+	}
+	return width, true
+}
+
+// detailView renders the pane: the row key, its parameters and as much of
+// the log tail as fits in height lines.
+func (m ProgressModel) detailView(width, height int) string {
+	inner := max(width-4, 10) // border and padding
+	faint := lipgloss.NewStyle().Faint(true)
+
+	lines := []string{HeaderStyle.Render(TruncateWithEllipsis(m.detailKey, inner))}
+	nameWidth := 0
+	for _, p := range m.detailInfo.Params {
+		nameWidth = max(nameWidth, len(p.Name))
+	}
+	for _, p := range m.detailInfo.Params {
+		value := TruncateWithEllipsis(NonEmptyOrDash(p.Value), max(inner-nameWidth-2, 1))
+		lines = append(lines, faint.Render(pad(p.Name, nameWidth))+"  "+value)
+	}
+
+	logName := "log"
+	if m.detailInfo.LogPath != "" {
+		logName = filepath.Base(m.detailInfo.LogPath)
+	}
+	lines = append(lines, "", faint.Render(TruncateWithEllipsis("── "+logName+" ──", inner)))
+	var tail []string
+	switch {
+	case m.detailInfo.LogPath == "":
+		tail = []string{faint.Render("no log for this row")}
+	case m.logErr != nil && os.IsNotExist(m.logErr):
+		tail = []string{faint.Render("no log yet")}
+	case m.logErr != nil:
+		tail = []string{fmt.Sprintf("read log: %v", m.logErr)}
+	default:
+		room := max(height-2-len(lines), 1)
+		shown := m.logTail
+		if len(shown) > room {
+			shown = shown[len(shown)-room:]
+		}
+		for _, line := range shown {
+			tail = append(tail, TruncateWithEllipsis(line, inner))
+		}
+	}
+	lines = append(lines, tail...)
+
+	style := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		Padding(0, 1).
+		Width(width - 2)
+	if height > 2 {
+		style = style.Height(height - 2)
+	}
+	return style.Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,294 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"powerhour/pkg/csvplan"
+)
+
+// PlanEditorRow is one plan row plus the read-only status shown beside it.
+type PlanEditorRow struct {
+	Row     csvplan.CollectionRow
+	Skipped bool
+	Cache   string // "cached" or "missing"
+	Render  string // "rendered", "stale" or "missing"
+}
+
+// PlanEditorOptions describes the plan being edited.
+type PlanEditorOptions struct {
+	Title       string // shown in the header, e.g. "songs (songs.csv)"
+	StartHeader string // column holding the start time
+}
+
+// PlanEditorResult reports what the user chose when the editor closed.
+type PlanEditorResult struct {
+	Saved bool
+	Rows  []PlanEditorRow
+}
+
+type planEditorModel struct {
+	opts    PlanEditorOptions
+	rows    []PlanEditorRow
+	cursor  int
+	offset  int
+	height  int
+	dirty   bool
+	saved   bool
+	quit    bool
+	confirm bool // quit requested with unsaved changes
+
+	editing   bool
+	editValue string
+	message   string
+}
+
+func newPlanEditorModel(opts PlanEditorOptions, rows []PlanEditorRow) planEditorModel {
+	if opts.StartHeader == "" {
+		opts.StartHeader = "start_time"
+	}
+	copied := make([]PlanEditorRow, len(rows))
+	copy(copied, rows)
+	return planEditorModel{opts: opts, rows: copied, height: 20}
+}
+
+func (m planEditorModel) Init() tea.Cmd { return nil }
+
+func (m planEditorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// Header, column titles, blank line, status and help lines.
+		m.height = max(msg.Height-6, 3)
+		m.scroll()
+		return m, nil
+	case tea.KeyMsg:
+		if m.editing {
+			return m.updateEdit(msg)
+		}
+		return m.updateNormal(msg)
+	}
+	return m, nil
+}
+
+func (m planEditorModel) updateNormal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	if key != "q" && key != "esc" {
+		m.confirm = false
+	}
+	switch key {
+	case "ctrl+c":
+		m.quit = true
+		return m, tea.Quit
+	case "q", "esc":
+		if m.dirty && !m.confirm {
+			m.confirm = true
+			m.message = "Unsaved changes — press q again to discard, w to save"
+			return m, nil
+		}
+		m.quit = true
+		return m, tea.Quit
+	case "w", "ctrl+s":
+		m.saved = true
+		return m, tea.Quit
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.rows)-1 {
+			m.cursor++
+		}
+	case "home", "g":
+		m.cursor = 0
+	case "end", "G":
+		m.cursor = max(len(m.rows)-1, 0)
+	case "K", "shift+up":
+		if m.cursor > 0 {
+			m.rows[m.cursor], m.rows[m.cursor-1] = m.rows[m.cursor-1], m.rows[m.cursor]
+			m.cursor--
+			m.dirty = true
+		}
+	case "J", "shift+down":
+		if m.cursor < len(m.rows)-1 {
+			m.rows[m.cursor], m.rows[m.cursor+1] = m.rows[m.cursor+1], m.rows[m.cursor]
+			m.cursor++
+			m.dirty = true
+		}
+	case "s", " ":
+		if len(m.rows) > 0 {
+			m.rows[m.cursor].Skipped = !m.rows[m.cursor].Skipped
+			m.dirty = true
+		}
+	case "e", "enter":
+		if len(m.rows) > 0 {
+			m.editing = true
+			m.editValue = m.rows[m.cursor].Row.StartRaw
+			m.message = ""
+		}
+	}
+	m.scroll()
+	return m, nil
+}
+
+func (m planEditorModel) updateEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.quit = true
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.editing = false
+		m.message = ""
+	case tea.KeyEnter:
+		value := strings.TrimSpace(m.editValue)
+		row := &m.rows[m.cursor].Row
+		// A range replaces any end_time; a plain start keeps it.
+		_, _, isRange := csvplan.SplitTimeRange(value)
+		endRaw := row.CustomFields[csvplan.EndTimeField]
+		if isRange {
+			endRaw = ""
+		}
+		window, err := csvplan.ParseTimeWindow(value, endRaw)
+		if err != nil {
+			m.message = fmt.Sprintf("invalid start time %q: %v", value, err)
+			return m, nil
+		}
+		if value != row.StartRaw {
+			row.StartRaw = window.StartText
+			row.Start = window.Start
+			if window.HasEnd {
+				row.DurationSeconds = window.Seconds()
+			}
+			fields := make(map[string]string, len(row.CustomFields)+1)
+			for k, v := range row.CustomFields {
+				fields[k] = v
+			}
+			fields[m.opts.StartHeader] = value
+			if isRange {
+				delete(fields, csvplan.EndTimeField)
+			}
+			row.CustomFields = fields
+			m.dirty = true
+		}
+		m.editing = false
+		m.message = ""
+	case tea.KeyBackspace:
+		if len(m.editValue) > 0 {
+			m.editValue = m.editValue[:len(m.editValue)-1]
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.editValue += string(msg.Runes)
+	}
+	return m, nil
+}
+
+func (m *planEditorModel) scroll() {
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.height {
+		m.offset = m.cursor - m.height + 1
+	}
+}
+
+func (m planEditorModel) View() string {
+	if m.saved || m.quit {
+		return ""
+	}
+	var b strings.Builder
+
+	skipped := 0
+	for _, r := range m.rows {
+		if r.Skipped {
+			skipped++
+		}
+	}
+	dirty := ""
+	if m.dirty {
+		dirty = " [modified]"
+	}
+	fmt.Fprintf(&b, "%s  %d rows, %d skipped%s\n",
+		lipgloss.NewStyle().Bold(true).Render("Plan: "+m.opts.Title), len(m.rows), skipped, dirty)
+	b.WriteString(HeaderStyle.Render(fmt.Sprintf("  %4s  %-4s  %-9s  %4s  %-28s  %-20s  %-8s  %s",
+		"#", "SKIP", "START", "DUR", "TITLE", "ARTIST", "CACHE", "RENDER")))
+	b.WriteString("\n")
+
+	faint := lipgloss.NewStyle().Faint(true)
+	end := min(m.offset+m.height, len(m.rows))
+	for i := m.offset; i < end; i++ {
+		r := m.rows[i]
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+		skip := ""
+		if r.Skipped {
+			skip = "skip"
+		}
+		start := r.Row.StartRaw
+		if m.editing && i == m.cursor {
+			start = m.editValue + "_"
+		}
+		line := fmt.Sprintf("%4d  %-4s  %-9s  %4d  %-28s  %-20s  ",
+			i+1, skip, start, r.Row.DurationSeconds,
+			truncate(r.Row.CustomFields["title"], 28),
+			truncate(r.Row.CustomFields["artist"], 20))
+		cache := StatusStyle(r.Cache).Render(fmt.Sprintf("%-8s", r.Cache))
+		render := StatusStyle(r.Render).Render(r.Render)
+		if r.Skipped {
+			line = faint.Render(line)
+		}
+		if i == m.cursor {
+			line = lipgloss.NewStyle().Reverse(true).Render(line)
+		}
+		b.WriteString(cursor + line + cache + "  " + render + "\n")
+	}
+
+	b.WriteString("\n")
+	if m.message != "" {
+		b.WriteString(m.message + "\n")
+	}
+	if m.editing {
+		b.WriteString(faint.Render("enter apply · esc cancel"))
+	} else {
+		b.WriteString(faint.Render("↑/↓ move · J/K reorder · s skip · e edit start · w save · q quit"))
+	}
+	return b.String()
+}
+
+// result returns the edited rows reindexed in display order.
+func (m planEditorModel) result() PlanEditorResult {
+	rows := make([]PlanEditorRow, len(m.rows))
+	copy(rows, m.rows)
+	for i := range rows {
+		rows[i].Row.Index = i + 1
+	}
+	return PlanEditorResult{Saved: m.saved, Rows: rows}
+}
+
+func truncate(s string, width int) string {
+	s = strings.TrimSpace(s)
+	r := []rune(s)
+	if len(r) <= width {
+		return s
+	}
+	if width <= 1 {
+		return string(r[:width])
+	}
+	return string(r[:width-1]) + "…"
+}
+
+// RunPlanEditor runs the interactive plan editor on the alternate screen and
+// returns the edited rows. Nothing is written; the caller persists the rows
+// when Saved is true.
+func RunPlanEditor(w io.Writer, opts PlanEditorOptions, rows []PlanEditorRow) (PlanEditorResult, error) {
+	p := tea.NewProgram(newPlanEditorModel(opts, rows), tea.WithOutput(w), tea.WithAltScreen())
+	finalModel, err := p.Run()
+	if err != nil {
+		return PlanEditorResult{}, err
+	}
+	return finalModel.(planEditorModel).result(), nil
+}
@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"powerhour/pkg/csvplan"
+)
+
+func planEditorTestRows() []PlanEditorRow {
+	mk := func(i int, title string) PlanEditorRow {
+		return PlanEditorRow{Row: csvplan.CollectionRow{
+			Index:        i,
+			StartRaw:     "0:30",
+			CustomFields: map[string]string{"title": title, "start_time": "0:30", "notes": "keep"},
+		}}
+	}
+	return []PlanEditorRow{mk(1, "A"), mk(2, "B"), mk(3, "C")}
+}
+
+func sendKeys(m planEditorModel, keys ...tea.KeyMsg) planEditorModel {
+	for _, k := range keys {
+		updated, _ := m.Update(k)
+		m = updated.(planEditorModel)
+	}
+	return m
+}
+
+func runes(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+func TestPlanEditorReorderSkipAndRetime(t *testing.T) {
+	m := newPlanEditorModel(PlanEditorOptions{Title: "songs"}, planEditorTestRows())
+
+	// Move A down below B, skip it, then retime it.
+	m = sendKeys(m, runes("J"), runes("s"), runes("e"),
+		tea.KeyMsg{Type: tea.KeyBackspace}, tea.KeyMsg{Type: tea.KeyBackspace},
+		runes("45"), tea.KeyMsg{Type: tea.KeyEnter}, runes("w"))
+
+	res := m.result()
+	if !res.Saved {
+		t.Fatal("expected saved result")
+	}
+	var titles []string
+	for _, r := range res.Rows {
+		titles = append(titles, r.Row.CustomFields["title"])
+	}
+	if got := titles[0] + titles[1] + titles[2]; got != "BAC" {
+		t.Fatalf("order = %s, want BAC", got)
+	}
+	moved := res.Rows[1]
+	if moved.Row.Index != 2 || !moved.Skipped {
+		t.Errorf("moved row = index %d skipped %v", moved.Row.Index, moved.Skipped)
+	}
+	if moved.Row.StartRaw != "0:45" || moved.Row.CustomFields["start_time"] != "0:45" {
+		t.Errorf("start = %q / %q, want 0:45", moved.Row.StartRaw, moved.Row.CustomFields["start_time"])
+	}
+	if moved.Row.CustomFields["notes"] != "keep" {
+		t.Error("unknown columns should be preserved")
+	}
+}
+
+func TestPlanEditorRejectsInvalidStart(t *testing.T) {
+	m := newPlanEditorModel(PlanEditorOptions{}, planEditorTestRows())
+	m = sendKeys(m, runes("e"), runes("x"), tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.editing || m.message == "" {
+		t.Fatal("invalid start time should keep the editor open with a message")
+	}
+	if m.dirty {
+		t.Error("invalid edit should not mark the plan modified")
+	}
+}
+
+func TestPlanEditorQuitConfirmsUnsaved(t *testing.T) {
+	m := newPlanEditorModel(PlanEditorOptions{}, planEditorTestRows())
+	m = sendKeys(m, runes("s"), runes("q"))
+	if m.quit || !m.confirm {
+		t.Fatal("first q with unsaved changes should ask for confirmation")
+	}
+	m = sendKeys(m, runes("q"))
+	if !m.quit || m.result().Saved {
+		t.Fatal("second q should quit without saving")
+	}
+}
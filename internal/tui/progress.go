@@ -6,6 +6,7 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 const (
@@ -49,9 +50,41 @@ type ProgressModel struct {
 	tick int
 
 	// Viewport state for scrolling when rows exceed terminal height.
+	// scrollTop indexes the filtered rows.
 	termHeight int
 	termWidth  int
 	scrollTop  int
+
+	// follow keeps the last updated row in view; scrolling by hand turns
+	// it off until f is pressed.
+	follow     bool
+	lastUpdate int
+	filter     RowFilter
+
+	// Row selection and the detail pane (see SetDetail). cursor is a row
+	// index, or -1 before a row is selected.
+	detail     DetailFunc
+	cursor     int
+	detailOpen bool
+	detailKey  string
+	detailInfo Detail
+	logTail    []string
+	logErr     error
+}
+
+// RowFilter selects which rows the table shows, by STATUS.
+type RowFilter int
+
+const (
+	FilterAll    RowFilter = iota // every row
+	FilterActive                  // rows being worked on
+	FilterErrors                  // rows whose STATUS is "error"
+)
+
+var filterNames = map[RowFilter]string{
+	FilterAll:    "all",
+	FilterActive: "active",
+	FilterErrors: "errors",
 }
 
 // NewProgressModel creates a progress model with the given title and columns.
@@ -64,11 +97,14 @@ func NewProgressModel(title string, columns []Column) ProgressModel {
 		}
 	}
 	return ProgressModel{
-		columns:   columns,
-		rows:      nil,
-		rowIndex:  make(map[string]int),
-		title:     title,
-		statusCol: statusCol,
+		columns:    columns,
+		rows:       nil,
+		rowIndex:   make(map[string]int),
+		title:      title,
+		statusCol:  statusCol,
+		follow:     true,
+		lastUpdate: -1,
+		cursor:     -1,
 	}
 }
 
@@ -97,6 +133,7 @@ func (m ProgressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.termHeight = msg.Height
 		m.termWidth = msg.Width
+		m.clampScroll()
 		return m, nil
 
 	case tickMsg:
@@ -104,14 +141,32 @@ func (m ProgressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.done {
 			return m, nil
 		}
+		if m.detailOpen && m.tick%logTailEvery == 0 {
+			return m, tea.Batch(scheduleTick(), readLogTail(m.detailKey, m.detailInfo.LogPath))
+		}
 		return m, scheduleTick()
 
+	case logTailMsg:
+		if msg.key == m.detailKey {
+			m.logTail, m.logErr = msg.lines, msg.err
+		}
+		return m, nil
+
 	case RowUpdateMsg:
 		m.applyRowUpdate(msg)
+		var cmd tea.Cmd
 		if idx, ok := m.rowIndex[msg.Key]; ok {
-			m.autoScroll(idx)
+			m.lastUpdate = idx
+			if m.follow {
+				m.autoScroll(idx)
+				if m.detail != nil && m.cursor != idx {
+					m.cursor = idx
+					cmd = m.refreshDetail()
+				}
+			}
 		}
-		return m, nil
+		m.clampScroll()
+		return m, cmd
 
 	case WorkDoneMsg:
 		m.done = true
@@ -127,11 +182,125 @@ func (m ProgressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "ctrl+c", "q":
 			m.done = true
 			return m, tea.Quit
+		case "up", "k":
+			return m, m.scrollBy(-1)
+		case "down", "j":
+			return m, m.scrollBy(1)
+		case "pgup", "b":
+			return m, m.scrollBy(-m.pageSize())
+		case "pgdown", " ":
+			return m, m.scrollBy(m.pageSize())
+		case "home", "g":
+			return m, m.scrollBy(-len(m.rows))
+		case "end", "G":
+			return m, m.scrollBy(len(m.rows))
+		case "enter":
+			if m.detail != nil {
+				return m, m.toggleDetail()
+			}
+		case "esc":
+			m.detailOpen = false
+		case "f":
+			m.follow = true
+			m.autoScroll(m.lastUpdate)
+			if m.detail != nil && m.lastUpdate >= 0 {
+				m.cursor = m.lastUpdate
+				return m, m.refreshDetail()
+			}
+		case "tab":
+			m.SetFilter((m.filter + 1) % RowFilter(len(filterNames)))
+		case "e":
+			if m.filter == FilterErrors {
+				m.SetFilter(FilterAll)
+			} else {
+				m.SetFilter(FilterErrors)
+			}
 		}
 	}
 	return m, nil
 }
 
+// SetFilter shows only the rows f selects, starting from the top, or at the
+// last updated row when following.
+func (m *ProgressModel) SetFilter(f RowFilter) {
+	m.filter = f
+	m.scrollTop = 0
+	if m.follow {
+		m.autoScroll(m.lastUpdate)
+	}
+}
+
+// scrollBy moves the viewport by delta rows and stops following. With
+// selectable rows it moves the cursor instead.
+func (m *ProgressModel) scrollBy(delta int) tea.Cmd {
+	if m.detail != nil {
+		return m.moveCursor(delta)
+	}
+	m.follow = false
+	m.scrollTop += delta
+	m.clampScroll()
+	return nil
+}
+
+// pageSize is how far page up and page down move.
+func (m ProgressModel) pageSize() int {
+	if visible := m.visibleRowCount(); visible > 1 {
+		return visible - 1
+	}
+	return 1
+}
+
+// clampScroll keeps scrollTop inside the filtered rows.
+func (m *ProgressModel) clampScroll() {
+	maxTop := len(m.shownRows()) - m.visibleRowCount()
+	if m.visibleRowCount() <= 0 || maxTop < 0 {
+		maxTop = 0
+	}
+	m.scrollTop = max(0, min(m.scrollTop, maxTop))
+}
+
+// shownRows returns the indexes of the rows that pass the filter.
+func (m ProgressModel) shownRows() []int {
+	shown := make([]int, 0, len(m.rows))
+	for i, row := range m.rows {
+		if m.passesFilter(row) {
+			shown = append(shown, i)
+		}
+	}
+	return shown
+}
+
+func (m ProgressModel) passesFilter(row Row) bool {
+	if m.filter == FilterAll || m.statusCol < 0 || m.statusCol >= len(row.Fields) {
+		return true
+	}
+	status := strings.TrimSpace(row.Fields[m.statusCol])
+	switch m.filter {
+	case FilterActive:
+		return isActiveStatus(status)
+	case FilterErrors:
+		return status == "error"
+	}
+	return true
+}
+
+// activeStatuses are the STATUS values of rows being worked on.
+var activeStatuses = map[string]bool{
+	"resolving":     true,
+	"downloading":   true,
+	"matching":      true,
+	"copying":       true,
+	"fetching":      true,
+	"rendering":     true,
+	"concatenating": true,
+}
+
+// isActiveStatus reports whether status is an active state or a render
+// progress bar ("===-- 60%").
+func isActiveStatus(status string) bool {
+	return activeStatuses[status] || strings.HasSuffix(status, "%")
+}
+
 // applyRowUpdate updates a row's fields from a RowUpdateMsg.
 func (m *ProgressModel) applyRowUpdate(msg RowUpdateMsg) {
 	idx, ok := m.rowIndex[msg.Key]
@@ -151,6 +320,29 @@ func (m ProgressModel) View() string {
 	if m.done && m.err != nil {
 		return fmt.Sprintf("Error: %v\n", m.err)
 	}
+	if !m.detailOpen || m.done {
+		return m.tableView(m.termWidth)
+	}
+	paneWidth, beside := m.paneLayout()
+	if !beside {
+		return m.tableView(m.termWidth) + m.detailView(paneWidth, paneHeightUnknown) + "\n"
+	}
+	paneHeight := paneHeightUnknown
+	if m.termHeight > 0 {
+		paneHeight = m.termHeight - 1
+	}
+	table := m.tableView(m.termWidth - paneWidth - 1)
+	return lipgloss.JoinHorizontal(lipgloss.Top, table, " ", m.detailView(paneWidth, paneHeight)) + "\n"
+}
+
+// tableView renders the table in width columns (0 when unknown).
+func (m ProgressModel) tableView(width int) string {
+	// Selectable rows get a two-column gutter for the cursor.
+	gutter := ""
+	if m.detail != nil {
+		gutter = "  "
+		width -= len(gutter)
+	}
 
 	// Calculate column widths. Non-flex columns use their specified width (at
 	// least as wide as the header). Flex columns split any remaining terminal
@@ -170,9 +362,9 @@ func (m ProgressModel) View() string {
 			fixedTotal += w
 		}
 	}
-	if flexCount > 0 && m.termWidth > 0 {
+	if flexCount > 0 && width > 0 {
 		separators := (len(m.columns) - 1) * 2
-		remaining := m.termWidth - fixedTotal - separators
+		remaining := width - fixedTotal - separators
 		if remaining > flexCount {
 			perFlex := remaining / flexCount
 			for i, col := range m.columns {
@@ -185,24 +377,29 @@ func (m ProgressModel) View() string {
 
 	var b strings.Builder
 
+	// Collapsed summary of the whole table once it no longer fits, or
+	// some rows are filtered out.
+	shown := m.shownRows()
+	visibleRows := m.visibleRowCount()
+	if visibleRows > 0 || m.filter != FilterAll {
+		b.WriteString(m.summaryLine(len(shown)))
+		b.WriteByte('\n')
+	}
+
 	// Header
 	headerParts := make([]string, len(m.columns))
 	for i, col := range m.columns {
 		headerParts[i] = HeaderStyle.Render(pad(col.Header, widths[i]))
 	}
-	b.WriteString(strings.Join(headerParts, "  "))
+	b.WriteString(gutter + strings.Join(headerParts, "  "))
 	b.WriteByte('\n')
 
 	// Determine visible row range (viewport).
-	visibleRows := m.visibleRowCount()
 	startRow := 0
-	endRow := len(m.rows)
-	if visibleRows > 0 && len(m.rows) > visibleRows {
-		startRow = m.scrollTop
+	endRow := len(shown)
+	if visibleRows > 0 && len(shown) > visibleRows {
+		startRow = min(m.scrollTop, len(shown)-visibleRows)
 		endRow = startRow + visibleRows
-		if endRow > len(m.rows) {
-			endRow = len(m.rows)
-		}
 	}
 
 	// Scroll-up indicator.
@@ -210,8 +407,13 @@ func (m ProgressModel) View() string {
 		fmt.Fprintf(&b, "  ↑ %d more above\n", startRow)
 	}
 
+	if len(shown) == 0 && len(m.rows) > 0 {
+		fmt.Fprintf(&b, "  no %s rows (tab to change filter)\n", filterNames[m.filter])
+	}
+
 	// Rows
-	for _, row := range m.rows[startRow:endRow] {
+	for _, idx := range shown[startRow:endRow] {
+		row := m.rows[idx]
 		parts := make([]string, len(m.columns))
 		for i := range m.columns {
 			val := ""
@@ -229,20 +431,36 @@ func (m ProgressModel) View() string {
 				parts[i] = pad(val, widths[i])
 			}
 		}
+		if gutter != "" {
+			if idx == m.cursor {
+				b.WriteString(HeaderStyle.Render("▸ "))
+			} else {
+				b.WriteString(gutter)
+			}
+		}
 		b.WriteString(strings.Join(parts, "  "))
 		b.WriteByte('\n')
 	}
 
 	// Scroll-down indicator.
-	if endRow < len(m.rows) {
-		fmt.Fprintf(&b, "  ↓ %d more below\n", len(m.rows)-endRow)
+	if endRow < len(shown) {
+		fmt.Fprintf(&b, "  ↓ %d more below\n", len(shown)-endRow)
 	}
 
-	// Footer: spinner + progress counter while work is in progress.
+	// Footer: spinner + progress counter while work is in progress, with
+	// the keys once there is something to scroll or filter.
 	if !m.done {
 		processed, total := m.progressCounts()
 		spinner := spinnerFrames[m.tick%len(spinnerFrames)]
-		fmt.Fprintf(&b, "\n%s Processing %d/%d...\n", spinner, processed, total)
+		fmt.Fprintf(&b, "\n%s Processing %d/%d...", spinner, processed, total)
+		faint := lipgloss.NewStyle().Faint(true)
+		switch {
+		case m.detail != nil:
+			b.WriteString(faint.Render("  ↑/↓ select · enter details · f follow · tab filter · e errors"))
+		case visibleRows > 0 || m.filter != FilterAll:
+			b.WriteString(faint.Render("  ↑/↓ scroll · f follow · tab filter · e errors"))
+		}
+		b.WriteByte('\n')
 	}
 
 	return b.String()
@@ -266,6 +484,49 @@ func (m ProgressModel) progressCounts() (int, int) {
 	return processed, total
 }
 
+// summaryLine counts the rows by state, e.g. "render: 120 rows · 80 done ·
+// 3 active · 2 errors · 35 waiting", followed by the filter and whether the
+// table follows the work.
+func (m ProgressModel) summaryLine(shown int) string {
+	var done, active, errs, waiting int
+	for _, row := range m.rows {
+		status := ""
+		if m.statusCol >= 0 && m.statusCol < len(row.Fields) {
+			status = strings.TrimSpace(row.Fields[m.statusCol])
+		}
+		switch {
+		case status == "error":
+			errs++
+		case isActiveStatus(status):
+			active++
+		case status == "" || status == "pending" || status == "queued":
+			waiting++
+		default:
+			done++
+		}
+	}
+	parts := []string{fmt.Sprintf("%d rows", len(m.rows))}
+	if m.statusCol >= 0 {
+		parts = append(parts,
+			fmt.Sprintf("%d done", done),
+			fmt.Sprintf("%d active", active),
+			StatusStyle("error").Render(fmt.Sprintf("%d errors", errs)),
+			fmt.Sprintf("%d waiting", waiting),
+		)
+	}
+	line := strings.Join(parts, " · ")
+	if m.title != "" {
+		line = HeaderStyle.Render(m.title) + ": " + line
+	}
+	if m.filter != FilterAll {
+		line += fmt.Sprintf("  [%s: %d shown]", filterNames[m.filter], shown)
+	}
+	if m.follow {
+		line += "  [following]"
+	}
+	return line
+}
+
 // Done returns whether the model has finished (work done or error).
 func (m ProgressModel) Done() bool {
 	return m.done
@@ -277,14 +538,16 @@ func (m ProgressModel) Err() error {
 }
 
 // visibleRowCount returns how many data rows fit in the terminal, accounting
-// for the header line, footer (spinner + blank line), and possible scroll
-// indicators. Returns 0 if the terminal height is unknown or all rows fit.
+// for the summary and header lines, footer (spinner + blank line), and
+// possible scroll indicators. Returns 0 if the terminal height is unknown or
+// all rows fit.
 func (m ProgressModel) visibleRowCount() int {
 	if m.termHeight <= 0 {
 		return 0
 	}
-	// Reserve: 1 header + 2 footer (blank + spinner) + 2 scroll indicators.
-	available := m.termHeight - 5
+	// Reserve: 1 summary + 1 header + 2 footer (blank + spinner) + 2 scroll
+	// indicators.
+	available := m.termHeight - 6
 	if available <= 0 {
 		available = 1
 	}
@@ -294,16 +557,27 @@ func (m ProgressModel) visibleRowCount() int {
 	return available
 }
 
-// autoScroll adjusts scrollTop so that the given row index is visible.
+// autoScroll adjusts scrollTop so that the given row index is visible, if
+// the filter shows it.
 func (m *ProgressModel) autoScroll(idx int) {
 	visible := m.visibleRowCount()
-	if visible <= 0 {
+	if visible <= 0 || idx < 0 {
+		return
+	}
+	pos := -1
+	for i, shownIdx := range m.shownRows() {
+		if shownIdx == idx {
+			pos = i
+			break
+		}
+	}
+	if pos < 0 {
 		return
 	}
-	if idx < m.scrollTop {
-		m.scrollTop = idx
-	} else if idx >= m.scrollTop+visible {
-		m.scrollTop = idx - visible + 1
+	if pos < m.scrollTop {
+		m.scrollTop = pos
+	} else if pos >= m.scrollTop+visible {
+		m.scrollTop = pos - visible + 1
 	}
 }
 
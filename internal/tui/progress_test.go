@@ -1,6 +1,11 @@
 package tui
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
@@ -281,3 +286,203 @@ func TestCtrlC(t *testing.T) {
 		t.Error("expected tea.Quit command")
 	}
 }
+
+func TestPlainFallback(t *testing.T) {
+	m := NewProgressModel("test", []Column{
+		{Header: "INDEX", Width: 5},
+		{Header: "STATUS", Width: 10},
+	})
+	m.AddRow("row:001", []string{"001", "pending"})
+	m.AddRow("row:002", []string{"002", "pending"})
+
+	var out strings.Builder
+	f := &plainFallback{out: &out, model: m.clone()}
+
+	// Before activation messages are mirrored but still go to the program.
+	if f.handle(RowUpdateMsg{Key: "row:001", Fields: map[string]string{"STATUS": "downloading"}}) {
+		t.Fatal("inactive fallback should not consume messages")
+	}
+	if m.rows[0].Fields[1] != "pending" {
+		t.Fatalf("clone shares fields with original: %q", m.rows[0].Fields[1])
+	}
+
+	f.activate(errors.New("no tty"))
+	msgs := []tea.Msg{
+		RowUpdateMsg{Key: "row:001", Fields: map[string]string{"STATUS": "===-- 60%"}},
+		RowUpdateMsg{Key: "row:001", Fields: map[string]string{"STATUS": "downloaded"}},
+		RowUpdateMsg{Key: "row:002", Fields: map[string]string{"STATUS": "pending"}},
+		WorkDoneMsg{},
+	}
+	for _, msg := range msgs {
+		if !f.handle(msg) {
+			t.Fatalf("active fallback should consume %T", msg)
+		}
+	}
+
+	got := out.String()
+	if !strings.Contains(got, "no tty") {
+		t.Errorf("expected failure reason in output, got %q", got)
+	}
+	if !strings.Contains(got, "row:001  downloaded") {
+		t.Errorf("expected status transition line, got %q", got)
+	}
+	if strings.Contains(got, "60%") || strings.Contains(got, "row:002") {
+		t.Errorf("progress ticks and unchanged statuses should be silent, got %q", got)
+	}
+	if snap := f.snapshot(); !snap.Done() || snap.rows[0].Fields[1] != "downloaded" {
+		t.Errorf("snapshot not up to date: done=%v status=%q", snap.Done(), snap.rows[0].Fields[1])
+	}
+}
+
+func TestFailedToStart(t *testing.T) {
+	if !failedToStart(errors.New("could not open a new TTY")) {
+		t.Error("setup error should fall back to plain output")
+	}
+	for _, err := range []error{
+		tea.ErrProgramKilled,
+		fmt.Errorf("%w: %w", tea.ErrProgramKilled, tea.ErrProgramPanic),
+		fmt.Errorf("%w: %w", tea.ErrProgramKilled, tea.ErrInterrupted),
+	} {
+		if failedToStart(err) {
+			t.Errorf("%v: error from a running program should be returned", err)
+		}
+	}
+}
+
+// bigModel returns a 30-row model in a terminal that shows 10 of them.
+func bigModel() ProgressModel {
+	m := NewProgressModel("render", []Column{
+		{Header: "INDEX", Width: 5},
+		{Header: "STATUS", Width: 10},
+	})
+	for i := 1; i <= 30; i++ {
+		key := fmt.Sprintf("row:%03d", i)
+		m.AddRow(key, []string{fmt.Sprintf("%03d", i), "queued"})
+	}
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 16})
+	return updated.(ProgressModel)
+}
+
+func update(t *testing.T, m ProgressModel, msg tea.Msg) ProgressModel {
+	t.Helper()
+	updated, _ := m.Update(msg)
+	return updated.(ProgressModel)
+}
+
+func TestFollowAndManualScroll(t *testing.T) {
+	m := bigModel()
+	m = update(t, m, RowUpdateMsg{Key: "row:025", Fields: map[string]string{"STATUS": "rendering"}})
+	if m.scrollTop != 15 {
+		t.Fatalf("following: scrollTop = %d, want 15 so row 25 is the last shown", m.scrollTop)
+	}
+
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyHome})
+	if m.scrollTop != 0 || m.follow {
+		t.Fatalf("home: scrollTop = %d, follow = %v", m.scrollTop, m.follow)
+	}
+	m = update(t, m, RowUpdateMsg{Key: "row:028", Fields: map[string]string{"STATUS": "rendering"}})
+	if m.scrollTop != 0 {
+		t.Errorf("updates moved the viewport after scrolling by hand: scrollTop = %d", m.scrollTop)
+	}
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyDown})
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyEnd})
+	if m.scrollTop != 20 {
+		t.Errorf("end: scrollTop = %d, want 20", m.scrollTop)
+	}
+
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyHome})
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	if !m.follow || m.scrollTop != 18 {
+		t.Errorf("f: follow = %v, scrollTop = %d, want back on row 28", m.follow, m.scrollTop)
+	}
+}
+
+func TestErrorFilter(t *testing.T) {
+	m := bigModel()
+	m = update(t, m, RowUpdateMsg{Key: "row:004", Fields: map[string]string{"STATUS": "error"}})
+	m = update(t, m, RowUpdateMsg{Key: "row:017", Fields: map[string]string{"STATUS": "rendered"}})
+	m = update(t, m, RowUpdateMsg{Key: "row:022", Fields: map[string]string{"STATUS": "error"}})
+	m = update(t, m, RowUpdateMsg{Key: "row:023", Fields: map[string]string{"STATUS": "==--- 40%"}})
+
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	view := m.View()
+	for _, want := range []string{"004", "022", "[errors: 2 shown]", "30 rows", "1 done", "1 active", "2 errors", "26 waiting"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("errors view is missing %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "017") || strings.Contains(view, "more below") {
+		t.Errorf("errors view shows other rows:\n%s", view)
+	}
+
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyTab})
+	if m.filter != FilterAll {
+		t.Fatalf("tab after errors: filter = %v, want all", m.filter)
+	}
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyTab})
+	if shown := m.shownRows(); len(shown) != 1 || shown[0] != 22 {
+		t.Errorf("active filter shows %v, want only row 23", shown)
+	}
+}
+
+func TestSummaryLineOnlyWhenScrolling(t *testing.T) {
+	m := NewProgressModel("fetch", []Column{{Header: "STATUS", Width: 10}})
+	m.AddRow("row:001", []string{"pending"})
+	m = update(t, m, tea.WindowSizeMsg{Width: 80, Height: 40})
+	if view := m.View(); strings.Contains(view, "rows ·") || strings.Contains(view, "scroll") {
+		t.Errorf("small table shows the summary or key hints:\n%s", view)
+	}
+}
+
+func TestDetailPane(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "render.log")
+	if err := os.WriteFile(logPath, []byte("ffmpeg -i in.mp4\nframe=  10\rframe=  20\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m := bigModel()
+	m.SetDetail(func(key string) Detail {
+		return Detail{Params: []DetailParam{{Name: "start", Value: "1:05"}}, LogPath: logPath}
+	})
+
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyDown})
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyDown})
+	if m.rows[m.cursor].Key != "row:002" {
+		t.Fatalf("cursor on %s, want row:002", m.rows[m.cursor].Key)
+	}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(ProgressModel)
+	if !m.detailOpen || cmd == nil {
+		t.Fatalf("enter: open=%v cmd=%v", m.detailOpen, cmd)
+	}
+	m = update(t, m, cmd())
+	view := m.View()
+	for _, want := range []string{"row:002", "start", "1:05", "render.log", "frame=  20"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view is missing %q:\n%s", want, view)
+		}
+	}
+
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.detailOpen || strings.Contains(m.View(), "render.log") {
+		t.Error("esc left the pane open")
+	}
+}
+
+func TestTailLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fetch.log")
+	if err := os.WriteFile(path, []byte("first line\nsecond\n\n[download]  50%\r[download] 100%\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	lines, err := tailLines(path, 40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"[download]  50%", "[download] 100%"}
+	if !slices.Equal(lines, want) {
+		t.Fatalf("tailLines = %q, want %q", lines, want)
+	}
+	if _, err := tailLines(filepath.Join(t.TempDir(), "missing.log"), 30); !os.IsNotExist(err) {
+		t.Fatalf("missing log err = %v", err)
+	}
+}
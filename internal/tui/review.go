@@ -0,0 +1,321 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"powerhour/pkg/csvplan"
+)
+
+// ReviewDecision is what the reviewer chose for a segment.
+type ReviewDecision string
+
+const (
+	ReviewUndecided ReviewDecision = ""
+	ReviewAccept    ReviewDecision = "accept"
+	ReviewReject    ReviewDecision = "reject"
+	ReviewRefetch   ReviewDecision = "refetch"
+	ReviewRetrim    ReviewDecision = "retrim"
+)
+
+// ReviewItem is one rendered segment in timeline order.
+type ReviewItem struct {
+	Slot       int
+	Collection string
+	Index      int
+	Title      string
+	Artist     string
+	Start      string // the row's current start time
+	Path       string
+
+	Decision ReviewDecision
+	NewStart string // set when Decision is ReviewRetrim
+}
+
+// ReviewOptions describes the review session.
+type ReviewOptions struct {
+	Title string
+	// Play returns the command that plays an item; the review screen is
+	// suspended while it runs. Nil disables playback.
+	Play func(ReviewItem) *exec.Cmd
+	// AutoPlay plays the first item on start and each next item after a
+	// decision.
+	AutoPlay bool
+}
+
+// ReviewResult reports the decisions when the review closed.
+type ReviewResult struct {
+	Done  bool // false when the review was abandoned
+	Items []ReviewItem
+}
+
+type reviewPlayedMsg struct{ err error }
+
+type reviewModel struct {
+	opts    ReviewOptions
+	items   []ReviewItem
+	cursor  int
+	offset  int
+	height  int
+	done    bool
+	quit    bool
+	confirm bool // quit requested with decisions made
+
+	editing   bool
+	editValue string
+	message   string
+}
+
+func newReviewModel(opts ReviewOptions, items []ReviewItem) reviewModel {
+	copied := make([]ReviewItem, len(items))
+	copy(copied, items)
+	return reviewModel{opts: opts, items: copied, height: 20}
+}
+
+func (m reviewModel) Init() tea.Cmd {
+	if m.opts.AutoPlay {
+		return m.play()
+	}
+	return nil
+}
+
+func (m reviewModel) play() tea.Cmd {
+	if m.opts.Play == nil || len(m.items) == 0 {
+		return nil
+	}
+	c := m.opts.Play(m.items[m.cursor])
+	if c == nil {
+		return nil
+	}
+	return tea.ExecProcess(c, func(err error) tea.Msg { return reviewPlayedMsg{err: err} })
+}
+
+func (m reviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// Header, column titles, blank line, status and help lines.
+		m.height = max(msg.Height-6, 3)
+		m.scroll()
+		return m, nil
+	case reviewPlayedMsg:
+		if msg.err != nil {
+			m.message = fmt.Sprintf("player: %v", msg.err)
+		}
+		return m, nil
+	case tea.KeyMsg:
+		if m.editing {
+			return m.updateEdit(msg)
+		}
+		return m.updateNormal(msg)
+	}
+	return m, nil
+}
+
+func (m reviewModel) updateNormal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	if key != "q" && key != "esc" {
+		m.confirm = false
+	}
+	switch key {
+	case "ctrl+c":
+		m.quit = true
+		return m, tea.Quit
+	case "q", "esc":
+		if m.decided() > 0 && !m.confirm {
+			m.confirm = true
+			m.message = "Decisions not applied — press q again to discard, w to finish"
+			return m, nil
+		}
+		m.quit = true
+		return m, tea.Quit
+	case "w", "ctrl+s":
+		m.done = true
+		return m, tea.Quit
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.items)-1 {
+			m.cursor++
+		}
+	case "home", "g":
+		m.cursor = 0
+	case "end", "G":
+		m.cursor = max(len(m.items)-1, 0)
+	case "enter", "p":
+		m.message = ""
+		return m, m.play()
+	case "a":
+		return m.decide(ReviewAccept, "")
+	case "r":
+		return m.decide(ReviewReject, "")
+	case "f":
+		return m.decide(ReviewRefetch, "")
+	case "u":
+		if len(m.items) > 0 {
+			m.items[m.cursor].Decision = ReviewUndecided
+			m.items[m.cursor].NewStart = ""
+		}
+	case "t":
+		if len(m.items) > 0 {
+			m.editing = true
+			m.editValue = m.items[m.cursor].Start
+			if m.items[m.cursor].NewStart != "" {
+				m.editValue = m.items[m.cursor].NewStart
+			}
+			m.message = ""
+		}
+	}
+	m.scroll()
+	return m, nil
+}
+
+func (m reviewModel) updateEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.quit = true
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.editing = false
+		m.message = ""
+	case tea.KeyEnter:
+		value := strings.TrimSpace(m.editValue)
+		if _, err := csvplan.ParseStartTime(value); err != nil {
+			m.message = fmt.Sprintf("invalid start time %q: %v", value, err)
+			return m, nil
+		}
+		m.editing = false
+		if value == m.items[m.cursor].Start {
+			m.message = "start time unchanged"
+			return m, nil
+		}
+		return m.decide(ReviewRetrim, value)
+	case tea.KeyBackspace:
+		if len(m.editValue) > 0 {
+			m.editValue = m.editValue[:len(m.editValue)-1]
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.editValue += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// decide records a decision for the current item and moves to the next,
+// playing it when auto-play is on.
+func (m reviewModel) decide(decision ReviewDecision, newStart string) (tea.Model, tea.Cmd) {
+	if len(m.items) == 0 {
+		return m, nil
+	}
+	m.items[m.cursor].Decision = decision
+	m.items[m.cursor].NewStart = newStart
+	m.message = ""
+	if m.cursor == len(m.items)-1 {
+		m.message = "Last segment reviewed — press w to finish"
+		return m, nil
+	}
+	m.cursor++
+	m.scroll()
+	if m.opts.AutoPlay {
+		return m, m.play()
+	}
+	return m, nil
+}
+
+func (m reviewModel) decided() int {
+	n := 0
+	for _, item := range m.items {
+		if item.Decision != ReviewUndecided {
+			n++
+		}
+	}
+	return n
+}
+
+func (m *reviewModel) scroll() {
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.height {
+		m.offset = m.cursor - m.height + 1
+	}
+}
+
+var reviewDecisionStatus = map[ReviewDecision]string{
+	ReviewAccept:  "rendered",
+	ReviewReject:  "error",
+	ReviewRefetch: "queued",
+	ReviewRetrim:  "queued",
+}
+
+func (m reviewModel) View() string {
+	if m.done || m.quit {
+		return ""
+	}
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s  %d segments, %d decided\n",
+		lipgloss.NewStyle().Bold(true).Render("Review: "+m.opts.Title), len(m.items), m.decided())
+	b.WriteString(HeaderStyle.Render(fmt.Sprintf("  %4s  %-16s  %-9s  %-28s  %-20s  %s",
+		"SLOT", "ROW", "START", "TITLE", "ARTIST", "DECISION")))
+	b.WriteString("\n")
+
+	faint := lipgloss.NewStyle().Faint(true)
+	end := min(m.offset+m.height, len(m.items))
+	for i := m.offset; i < end; i++ {
+		item := m.items[i]
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+		start := item.Start
+		if m.editing && i == m.cursor {
+			start = m.editValue + "_"
+		}
+		line := fmt.Sprintf("%4d  %-16s  %-9s  %-28s  %-20s  ",
+			item.Slot, truncate(fmt.Sprintf("%s #%d", item.Collection, item.Index), 16), start,
+			truncate(item.Title, 28), truncate(item.Artist, 20))
+		if i == m.cursor {
+			line = lipgloss.NewStyle().Reverse(true).Render(line)
+		}
+		decision := string(item.Decision)
+		if item.Decision == ReviewRetrim {
+			decision += " → " + item.NewStart
+		}
+		b.WriteString(cursor + line + StatusStyle(reviewDecisionStatus[item.Decision]).Render(decision) + "\n")
+	}
+
+	b.WriteString("\n")
+	if m.message != "" {
+		b.WriteString(m.message + "\n")
+	}
+	if m.editing {
+		b.WriteString(faint.Render("new start time · enter apply · esc cancel"))
+	} else {
+		b.WriteString(faint.Render("enter play · a accept · r reject · f re-fetch · t re-trim · u undo · w finish · q quit"))
+	}
+	return b.String()
+}
+
+func (m reviewModel) result() ReviewResult {
+	items := make([]ReviewItem, len(m.items))
+	copy(items, m.items)
+	return ReviewResult{Done: m.done, Items: items}
+}
+
+// RunReview runs the segment review on the alternate screen and returns the
+// decisions. Nothing is changed; the caller applies the decisions when Done
+// is true.
+func RunReview(w io.Writer, opts ReviewOptions, items []ReviewItem) (ReviewResult, error) {
+	p := tea.NewProgram(newReviewModel(opts, items), tea.WithOutput(w), tea.WithAltScreen())
+	finalModel, err := p.Run()
+	if err != nil {
+		return ReviewResult{}, err
+	}
+	return finalModel.(reviewModel).result(), nil
+}
@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func sendReviewKeys(m reviewModel, keys ...tea.KeyMsg) reviewModel {
+	for _, k := range keys {
+		updated, _ := m.Update(k)
+		m = updated.(reviewModel)
+	}
+	return m
+}
+
+func TestReviewDecisions(t *testing.T) {
+	items := []ReviewItem{
+		{Slot: 1, Collection: "songs", Index: 1, Start: "0:30"},
+		{Slot: 2, Collection: "songs", Index: 2, Start: "1:00"},
+		{Slot: 3, Collection: "songs", Index: 3, Start: "0:45"},
+		{Slot: 4, Collection: "songs", Index: 4, Start: "2:00"},
+	}
+	m := newReviewModel(ReviewOptions{Title: "party"}, items)
+
+	// Accept, reject, re-trim the third to 0:50, then re-fetch the last.
+	m = sendReviewKeys(m, runes("a"), runes("r"), runes("t"),
+		tea.KeyMsg{Type: tea.KeyBackspace}, tea.KeyMsg{Type: tea.KeyBackspace},
+		runes("50"), tea.KeyMsg{Type: tea.KeyEnter}, runes("f"))
+	if m.cursor != 3 {
+		t.Fatalf("cursor = %d, want to stay on the last item", m.cursor)
+	}
+
+	// Quitting with decisions asks first; w finishes.
+	m = sendReviewKeys(m, runes("q"))
+	if m.quit || !m.confirm {
+		t.Fatal("first q with decisions should ask for confirmation")
+	}
+	m = sendReviewKeys(m, runes("w"))
+
+	res := m.result()
+	if !res.Done {
+		t.Fatal("expected done result")
+	}
+	want := []ReviewDecision{ReviewAccept, ReviewReject, ReviewRetrim, ReviewRefetch}
+	for i, item := range res.Items {
+		if item.Decision != want[i] {
+			t.Errorf("item %d decision = %q, want %q", i+1, item.Decision, want[i])
+		}
+	}
+	if res.Items[2].NewStart != "0:50" {
+		t.Errorf("re-trim start = %q, want 0:50", res.Items[2].NewStart)
+	}
+	if items[0].Decision != ReviewUndecided {
+		t.Error("input items should not be mutated")
+	}
+}
+
+func TestReviewRetrimValidatesAndUndo(t *testing.T) {
+	m := newReviewModel(ReviewOptions{}, []ReviewItem{{Slot: 1, Start: "0:30"}, {Slot: 2, Start: "0:30"}})
+
+	m = sendReviewKeys(m, runes("t"), runes("x"), tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.editing || m.message == "" {
+		t.Fatal("invalid start should keep editing with a message")
+	}
+	m = sendReviewKeys(m, tea.KeyMsg{Type: tea.KeyEsc}, runes("t"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.items[0].Decision != ReviewUndecided {
+		t.Fatal("an unchanged start should not record a re-trim")
+	}
+
+	m = sendReviewKeys(m, runes("r"), runes("k"), runes("u"))
+	if m.items[0].Decision != ReviewUndecided {
+		t.Errorf("undo left decision %q", m.items[0].Decision)
+	}
+	if m = sendReviewKeys(m, runes("q")); !m.quit {
+		t.Error("q with no decisions should quit at once")
+	}
+}
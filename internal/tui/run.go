@@ -1,8 +1,11 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"strings"
+	"sync"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -12,14 +15,26 @@ import (
 // and blocks until the program exits. workFn receives a send callback that
 // wraps tea.Program.Send with a small yield to give the renderer time to
 // draw between updates.
-func RunWithWork(out io.Writer, model ProgressModel, workFn func(send func(tea.Msg))) error {
+//
+// If the program fails to start (no usable TTY, unsupported terminal), the
+// work is not aborted: RunWithWork logs the reason via logf, switches to
+// plain line-per-status output mid-command, waits for workFn to finish, and
+// prints the final table as usual. logf may be nil. Once the program is
+// running, an error (a panic in the model, an interrupt) is returned as is.
+func RunWithWork(out io.Writer, model ProgressModel, logf func(string, ...any), workFn func(send func(tea.Msg))) error {
 	p := tea.NewProgram(model, tea.WithOutput(out), tea.WithAltScreen())
+	sink := &plainFallback{out: out, model: model.clone()}
+	workDone := make(chan struct{})
 
 	go func() {
+		defer close(workDone)
 		// Let bubbletea start its event loop and render the initial frame.
 		time.Sleep(50 * time.Millisecond)
 
 		workFn(func(msg tea.Msg) {
+			if sink.handle(msg) {
+				return
+			}
 			p.Send(msg)
 			// Small yield between sends so the renderer can draw frames.
 			// For 60 cached rows (~120 messages) this adds ~600ms total,
@@ -28,13 +43,23 @@ func RunWithWork(out io.Writer, model ProgressModel, workFn func(send func(tea.M
 			time.Sleep(5 * time.Millisecond)
 		})
 
-		p.Send(WorkDoneMsg{})
+		if !sink.handle(WorkDoneMsg{}) {
+			p.Send(WorkDoneMsg{})
+		}
 	}()
 
 	finalModel, err := p.Run()
-	if err != nil {
+	if err != nil && !failedToStart(err) {
 		return err
 	}
+	if err != nil {
+		if logf != nil {
+			logf("TUI failed, falling back to plain output: %v", err)
+		}
+		sink.activate(err)
+		<-workDone
+		finalModel = sink.snapshot()
+	}
 	if m, ok := finalModel.(ProgressModel); ok {
 		// Print the full static table so all rows appear in terminal scrollback.
 		fmt.Fprint(out, m.RenderFinalTable())
@@ -44,3 +69,82 @@ func RunWithWork(out io.Writer, model ProgressModel, workFn func(send func(tea.M
 	}
 	return nil
 }
+
+// failedToStart reports whether err came from setting up the terminal rather
+// than from a program that was already running. bubbletea wraps every error
+// raised after its event loop starts in tea.ErrProgramKilled.
+func failedToStart(err error) bool {
+	return !errors.Is(err, tea.ErrProgramKilled)
+}
+
+// plainFallback mirrors every message into a shadow model so that, once the
+// TUI has failed, progress can continue as plain status lines without losing
+// updates that were sent before the failure was noticed.
+type plainFallback struct {
+	mu     sync.Mutex
+	out    io.Writer
+	model  ProgressModel
+	active bool
+}
+
+// handle records msg and reports whether the fallback consumed it. Before
+// activation messages are only mirrored and should still go to the program.
+func (f *plainFallback) handle(msg tea.Msg) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	before := f.status(msg)
+	next, _ := f.model.Update(msg)
+	f.model = next.(ProgressModel)
+	if !f.active {
+		return false
+	}
+
+	if row, ok := msg.(RowUpdateMsg); ok {
+		after := f.status(row)
+		// Progress bars ("===-- 60%") would print a line per tick; only
+		// report transitions between named states.
+		if after != "" && after != before && !strings.HasSuffix(after, "%") {
+			fmt.Fprintf(f.out, "%s  %s\n", row.Key, after)
+		}
+	}
+	return true
+}
+
+func (f *plainFallback) activate(reason error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.active = true
+	fmt.Fprintf(f.out, "Interactive progress unavailable (%v); continuing with plain output.\n", reason)
+}
+
+func (f *plainFallback) snapshot() ProgressModel {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.model
+}
+
+// clone copies row fields so the shadow model can be updated from the work
+// goroutine without racing the program's copy, which shares the backing
+// arrays. rowIndex is read-only once rows are added and can be shared.
+func (m ProgressModel) clone() ProgressModel {
+	rows := make([]Row, len(m.rows))
+	for i, r := range m.rows {
+		rows[i] = Row{Key: r.Key, Fields: append([]string(nil), r.Fields...)}
+	}
+	m.rows = rows
+	return m
+}
+
+// status returns the current STATUS value for the row msg targets.
+func (f *plainFallback) status(msg tea.Msg) string {
+	row, ok := msg.(RowUpdateMsg)
+	if !ok || f.model.statusCol < 0 {
+		return ""
+	}
+	idx, ok := f.model.rowIndex[row.Key]
+	if !ok {
+		return ""
+	}
+	return f.model.rows[idx].Fields[f.model.statusCol]
+}
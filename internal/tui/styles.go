@@ -20,19 +20,21 @@ var (
 		"complete":   lipgloss.NewStyle().Foreground(lipgloss.Color("2")),
 
 		// Active states
-		"resolving":   lipgloss.NewStyle().Foreground(lipgloss.Color("4")),
-		"downloading": lipgloss.NewStyle().Foreground(lipgloss.Color("4")),
-		"matching":    lipgloss.NewStyle().Foreground(lipgloss.Color("4")),
-		"copying":     lipgloss.NewStyle().Foreground(lipgloss.Color("4")),
-		"fetching":    lipgloss.NewStyle().Foreground(lipgloss.Color("4")),
-		"rendering":   lipgloss.NewStyle().Foreground(lipgloss.Color("4")),
+		"resolving":     lipgloss.NewStyle().Foreground(lipgloss.Color("4")),
+		"downloading":   lipgloss.NewStyle().Foreground(lipgloss.Color("4")),
+		"matching":      lipgloss.NewStyle().Foreground(lipgloss.Color("4")),
+		"copying":       lipgloss.NewStyle().Foreground(lipgloss.Color("4")),
+		"fetching":      lipgloss.NewStyle().Foreground(lipgloss.Color("4")),
+		"rendering":     lipgloss.NewStyle().Foreground(lipgloss.Color("4")),
+		"concatenating": lipgloss.NewStyle().Foreground(lipgloss.Color("4")),
 
 		// Transitional
 		"fetched": lipgloss.NewStyle().Foreground(lipgloss.Color("6")),
 
 		// Skipped / warning
-		"skipped": lipgloss.NewStyle().Foreground(lipgloss.Color("3")),
-		"missing": lipgloss.NewStyle().Foreground(lipgloss.Color("3")),
+		"skipped":  lipgloss.NewStyle().Foreground(lipgloss.Color("3")),
+		"missing":  lipgloss.NewStyle().Foreground(lipgloss.Color("3")),
+		"canceled": lipgloss.NewStyle().Foreground(lipgloss.Color("3")),
 
 		// Error
 		"error": lipgloss.NewStyle().Foreground(lipgloss.Color("1")),
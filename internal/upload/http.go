@@ -0,0 +1,89 @@
+package upload
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"powerhour/internal/config"
+	"powerhour/internal/secrets"
+)
+
+// httpPut PUTs the file to a configured URL, for presigned URLs, WebDAV and
+// home-grown upload endpoints.
+type httpPut struct {
+	cfg    config.HTTPUploadConfig
+	client *http.Client
+}
+
+func newHTTPPut(cfg config.HTTPUploadConfig) *httpPut {
+	return &httpPut{cfg: cfg, client: http.DefaultClient}
+}
+
+func (h *httpPut) Name() string { return "http" }
+
+func (h *httpPut) Upload(ctx context.Context, file string, meta Metadata, progress Progress) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	target, err := secrets.Expand(strings.TrimSpace(h.cfg.URL))
+	if err != nil {
+		return "", fmt.Errorf("http upload url: %w", err)
+	}
+	target = strings.ReplaceAll(target, "{file}", url.PathEscape(filepath.Base(file)))
+
+	body := &progressReader{r: f, total: info.Size(), progress: progress}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target, body)
+	if err != nil {
+		// The URL may carry a token; don't echo it.
+		return "", fmt.Errorf("http upload: invalid url")
+	}
+	req.ContentLength = info.Size()
+	req.Header.Set("Content-Type", meta.ContentType)
+	for name, value := range h.cfg.Headers {
+		expanded, err := secrets.Expand(value)
+		if err != nil {
+			return "", fmt.Errorf("http upload header %s: %w", name, err)
+		}
+		req.Header.Set(name, expanded)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("http upload: %w", unwrapURLError(err))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return "", fmt.Errorf("http upload: %w", httpError(resp))
+	}
+	if location := resp.Header.Get("Location"); location != "" {
+		if u, err := req.URL.Parse(location); err == nil {
+			return u.String(), nil
+		}
+	}
+	// The query string of a presigned URL is a credential; keep it out of
+	// the uploads record.
+	recorded := *req.URL
+	recorded.RawQuery, recorded.User = "", nil
+	return recorded.String(), nil
+}
+
+// unwrapURLError drops the *url.Error wrapper, whose message repeats the
+// URL and any token in it.
+func unwrapURLError(err error) error {
+	if urlErr, ok := err.(*url.Error); ok {
+		return urlErr.Err
+	}
+	return err
+}
@@ -0,0 +1,203 @@
+package upload
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"powerhour/internal/config"
+	"powerhour/internal/secrets"
+)
+
+// s3MaxPutSize is the largest object a single PUT may create.
+const s3MaxPutSize = 5 << 30
+
+// unsignedPayload skips hashing a multi-gigabyte body before sending it;
+// S3 accepts it over HTTPS.
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// s3 PUTs the file as one object, signed with AWS Signature Version 4.
+type s3 struct {
+	cfg    config.S3UploadConfig
+	client *http.Client
+	now    func() time.Time
+}
+
+func newS3(cfg config.S3UploadConfig) *s3 {
+	return &s3{cfg: cfg, client: http.DefaultClient, now: time.Now}
+}
+
+func (s *s3) Name() string { return "s3" }
+
+func (s *s3) region() string {
+	if r := strings.TrimSpace(s.cfg.Region); r != "" {
+		return r
+	}
+	return "us-east-1"
+}
+
+// objectURL is the bucket URL of key: virtual-hosted on AWS, path-style on
+// a custom endpoint.
+func (s *s3) objectURL(key string) string {
+	escaped := escapePath(key)
+	if endpoint := strings.TrimRight(strings.TrimSpace(s.cfg.Endpoint), "/"); endpoint != "" {
+		return endpoint + "/" + escapePath(s.cfg.Bucket) + "/" + escaped
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.cfg.Bucket, s.region(), escaped)
+}
+
+func (s *s3) Upload(ctx context.Context, file string, meta Metadata, progress Progress) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	if info.Size() > s3MaxPutSize {
+		return "", fmt.Errorf("s3: %s is over the 5 GiB single-upload limit", filepath.Base(file))
+	}
+
+	creds := map[string]string{"access key": s.cfg.AccessKeyID, "secret key": s.cfg.SecretAccessKey, "session token": s.cfg.SessionToken}
+	for name, value := range creds {
+		expanded, err := secrets.Expand(value)
+		if err != nil {
+			return "", fmt.Errorf("s3 %s: %w", name, err)
+		}
+		creds[name] = expanded
+	}
+
+	key := strings.TrimLeft(s.cfg.Prefix, "/") + filepath.Base(file)
+	target := s.objectURL(key)
+	body := &progressReader{r: f, total: info.Size(), progress: progress}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target, body)
+	if err != nil {
+		return "", fmt.Errorf("s3: %w", err)
+	}
+	req.ContentLength = info.Size()
+	req.Header.Set("Content-Type", meta.ContentType)
+	if token := creds["session token"]; token != "" {
+		req.Header.Set("X-Amz-Security-Token", token)
+	}
+	signV4(req, creds["access key"], creds["secret key"], s.region(), "s3", unsignedPayload, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("s3: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return "", fmt.Errorf("s3: %w", httpError(resp))
+	}
+	if base := strings.TrimRight(strings.TrimSpace(s.cfg.PublicURL), "/"); base != "" {
+		return base + "/" + escapePath(key), nil
+	}
+	return target, nil
+}
+
+// signV4 adds the X-Amz-Date, X-Amz-Content-Sha256 and Authorization
+// headers for AWS Signature Version 4. Every header already on req is
+// signed, along with Host.
+func signV4(req *http.Request, accessKey, secretKey, region, service, payloadHash string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := day + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex(canonicalRequest)
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), day)
+	for _, part := range []string{region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+func canonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		values := append([]string(nil), q[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			parts = append(parts, escapeComponent(k)+"="+escapeComponent(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// escapePath percent-encodes an object key as S3 expects, leaving slashes.
+func escapePath(key string) string {
+	parts := strings.Split(key, "/")
+	for i, p := range parts {
+		parts[i] = escapeComponent(p)
+	}
+	return strings.Join(parts, "/")
+}
+
+// escapeComponent is RFC 3986 encoding: only A-Z a-z 0-9 - _ . ~ stay.
+func escapeComponent(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
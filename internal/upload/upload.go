@@ -0,0 +1,176 @@
+// Package upload publishes a finished power hour video (upload: in
+// powerhour.yaml). Each backend — YouTube, S3 or a plain HTTP PUT — sends
+// one file and returns the URL it can be found at; Record keeps that URL in
+// the project's .powerhour/uploads.json.
+package upload
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"powerhour/internal/config"
+)
+
+// Backend sends a file somewhere and returns its URL.
+type Backend interface {
+	Name() string
+	Upload(ctx context.Context, file string, meta Metadata, progress Progress) (string, error)
+}
+
+// Progress is told how many bytes of the file have been sent. It may be nil.
+type Progress func(sent, total int64)
+
+// Metadata describes the video. Backends that only store files ignore all
+// but ContentType.
+type Metadata struct {
+	Title       string
+	Description string
+	Tags        []string
+	Chapters    []Chapter
+	ContentType string
+}
+
+// Chapter is a named start time in the video.
+type Chapter struct {
+	Start float64 // seconds
+	Title string
+}
+
+// New returns the named backend from cfg.
+func New(cfg config.UploadConfig, name string) (Backend, error) {
+	switch name {
+	case "youtube":
+		if cfg.YouTube != nil {
+			return newYouTube(*cfg.YouTube), nil
+		}
+	case "s3":
+		if cfg.S3 != nil {
+			return newS3(*cfg.S3), nil
+		}
+	case "http":
+		if cfg.HTTP != nil {
+			return newHTTPPut(*cfg.HTTP), nil
+		}
+	default:
+		return nil, fmt.Errorf("unknown upload backend %q (choose from %s)", name, strings.Join(config.UploadBackends, ", "))
+	}
+	return nil, fmt.Errorf("upload.%s is not configured in powerhour.yaml", name)
+}
+
+// ContentType guesses the MIME type of a concat output from its extension.
+func ContentType(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".mp4":
+		return "video/mp4"
+	case ".mkv":
+		return "video/x-matroska"
+	case ".mov":
+		return "video/quicktime"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// ChapterList formats chapters as YouTube reads them from a description:
+// one "m:ss Title" line each, the first at 0:00. YouTube ignores lists of
+// fewer than three chapters, so those return "".
+func ChapterList(chapters []Chapter) string {
+	if len(chapters) < 3 {
+		return ""
+	}
+	lines := make([]string, len(chapters))
+	for i, ch := range chapters {
+		start := ch.Start
+		if i == 0 {
+			start = 0
+		}
+		lines[i] = formatTimestamp(start) + " " + ch.Title
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatTimestamp(seconds float64) string {
+	total := int(seconds)
+	h, m, s := total/3600, (total%3600)/60, total%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%d:%02d", m, s)
+}
+
+// progressReader counts bytes read through it.
+type progressReader struct {
+	r        io.Reader
+	sent     int64
+	total    int64
+	progress Progress
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.sent += int64(n)
+	if p.progress != nil && n > 0 {
+		p.progress(p.sent, p.total)
+	}
+	return n, err
+}
+
+// httpError describes a non-2xx response, with the start of its body.
+func httpError(resp *http.Response) error {
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 300))
+	msg := strings.TrimSpace(string(snippet))
+	if msg == "" {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return fmt.Errorf("HTTP %d: %s", resp.StatusCode, msg)
+}
+
+// Entry is one upload recorded in the project.
+type Entry struct {
+	Backend    string    `json:"backend"`
+	File       string    `json:"file"`
+	URL        string    `json:"url"`
+	SizeBytes  int64     `json:"size_bytes"`
+	UploadedAt time.Time `json:"uploaded_at"`
+}
+
+// Load reads the recorded uploads, oldest first. A missing file is empty.
+func Load(path string) ([]Entry, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// Record appends entry to the uploads file at path.
+func Record(path string, entry Entry) error {
+	entries, err := Load(path)
+	if err != nil {
+		return err
+	}
+	entries = append(entries, entry)
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
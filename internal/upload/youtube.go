@@ -0,0 +1,288 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"powerhour/internal/config"
+	"powerhour/internal/secrets"
+)
+
+const (
+	youTubeTokenURL  = "https://oauth2.googleapis.com/token"
+	youTubeUploadURL = "https://www.googleapis.com/upload/youtube/v3/videos?uploadType=resumable&part=snippet,status"
+	youTubeWatchURL  = "https://youtu.be/"
+
+	youTubeTitleMax       = 100
+	youTubeDescriptionMax = 5000
+	youTubeMaxRetries     = 5
+)
+
+// retryBackoff is how long to wait before resuming after the attempt'th
+// failure. Tests shorten it.
+var retryBackoff = func(attempt int) time.Duration {
+	return time.Duration(1<<attempt) * time.Second
+}
+
+// youTube uploads through the YouTube Data API's resumable upload protocol,
+// so a dropped connection partway through an hour-long video picks up where
+// it stopped instead of starting over.
+type youTube struct {
+	cfg       config.YouTubeUploadConfig
+	client    *http.Client
+	tokenURL  string
+	uploadURL string
+}
+
+func newYouTube(cfg config.YouTubeUploadConfig) *youTube {
+	return &youTube{cfg: cfg, client: http.DefaultClient, tokenURL: youTubeTokenURL, uploadURL: youTubeUploadURL}
+}
+
+func (y *youTube) Name() string { return "youtube" }
+
+func (y *youTube) Upload(ctx context.Context, file string, meta Metadata, progress Progress) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	size := info.Size()
+
+	token, err := y.accessToken(ctx)
+	if err != nil {
+		return "", fmt.Errorf("youtube auth: %w", err)
+	}
+	session, err := y.startSession(ctx, token, meta, size)
+	if err != nil {
+		return "", fmt.Errorf("youtube: start upload: %w", err)
+	}
+
+	var offset int64
+	for attempt := 0; ; attempt++ {
+		id, err := y.send(ctx, token, session, f, offset, size, meta.ContentType, progress)
+		if err == nil {
+			return youTubeWatchURL + id, nil
+		}
+		var retry retryableError
+		if !errors.As(err, &retry) || attempt >= youTubeMaxRetries || ctx.Err() != nil {
+			return "", fmt.Errorf("youtube: %w", err)
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(retryBackoff(attempt)):
+		}
+		var done bool
+		offset, id, done, err = y.queryOffset(ctx, token, session, size)
+		if err != nil {
+			return "", fmt.Errorf("youtube: resume upload: %w", err)
+		}
+		if done {
+			return youTubeWatchURL + id, nil
+		}
+	}
+}
+
+// retryableError marks failures worth resuming after: network errors, 5xx
+// responses, and an incomplete upload.
+type retryableError struct{ err error }
+
+func (e retryableError) Error() string { return e.err.Error() }
+func (e retryableError) Unwrap() error { return e.err }
+
+func (y *youTube) accessToken(ctx context.Context) (string, error) {
+	form := url.Values{"grant_type": {"refresh_token"}}
+	for key, value := range map[string]string{
+		"client_id":     y.cfg.ClientID,
+		"client_secret": y.cfg.ClientSecret,
+		"refresh_token": y.cfg.RefreshToken,
+	} {
+		expanded, err := secrets.Expand(value)
+		if err != nil {
+			return "", err
+		}
+		form.Set(key, expanded)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, y.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := y.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var body struct {
+		AccessToken      string `json:"access_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("token response: HTTP %d: %w", resp.StatusCode, err)
+	}
+	if body.Error != "" {
+		return "", fmt.Errorf("%s: %s", body.Error, body.ErrorDescription)
+	}
+	if body.AccessToken == "" {
+		return "", fmt.Errorf("token response: HTTP %d without access_token", resp.StatusCode)
+	}
+	return body.AccessToken, nil
+}
+
+type youTubeVideo struct {
+	Snippet struct {
+		Title       string   `json:"title"`
+		Description string   `json:"description"`
+		Tags        []string `json:"tags,omitempty"`
+		CategoryID  string   `json:"categoryId"`
+	} `json:"snippet"`
+	Status struct {
+		PrivacyStatus string `json:"privacyStatus"`
+	} `json:"status"`
+}
+
+// videoResource builds the snippet and status YouTube stores with the
+// video. Chapters are appended to the description, and angle brackets,
+// which YouTube rejects, are dropped.
+func (y *youTube) videoResource(meta Metadata) youTubeVideo {
+	clean := strings.NewReplacer("<", "", ">", "")
+	var v youTubeVideo
+	v.Snippet.Title = truncateRunes(clean.Replace(meta.Title), youTubeTitleMax)
+	description := strings.TrimSpace(meta.Description)
+	if chapters := ChapterList(meta.Chapters); chapters != "" {
+		description = strings.TrimSpace(description + "\n\n" + chapters)
+	}
+	v.Snippet.Description = truncateRunes(clean.Replace(description), youTubeDescriptionMax)
+	v.Snippet.Tags = meta.Tags
+	v.Snippet.CategoryID = y.cfg.CategoryID
+	if v.Snippet.CategoryID == "" {
+		v.Snippet.CategoryID = "10" // Music
+	}
+	v.Status.PrivacyStatus = strings.ToLower(strings.TrimSpace(y.cfg.Privacy))
+	if v.Status.PrivacyStatus == "" {
+		v.Status.PrivacyStatus = "unlisted"
+	}
+	return v
+}
+
+func (y *youTube) startSession(ctx context.Context, token string, meta Metadata, size int64) (string, error) {
+	body, err := json.Marshal(y.videoResource(meta))
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, y.uploadURL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	req.Header.Set("X-Upload-Content-Length", strconv.FormatInt(size, 10))
+	req.Header.Set("X-Upload-Content-Type", meta.ContentType)
+	resp, err := y.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", httpError(resp)
+	}
+	session := resp.Header.Get("Location")
+	if session == "" {
+		return "", errors.New("no upload session URL in response")
+	}
+	return session, nil
+}
+
+// send uploads the file from offset to the end and returns the video ID.
+func (y *youTube) send(ctx context.Context, token, session string, f *os.File, offset, size int64, contentType string, progress Progress) (string, error) {
+	body := &progressReader{r: io.NewSectionReader(f, offset, size-offset), sent: offset, total: size, progress: progress}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, session, body)
+	if err != nil {
+		return "", err
+	}
+	req.ContentLength = size - offset
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", contentType)
+	if offset > 0 {
+		req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, size-1, size))
+	}
+	resp, err := y.client.Do(req)
+	if err != nil {
+		return "", retryableError{err}
+	}
+	defer resp.Body.Close()
+	return videoID(resp)
+}
+
+// queryOffset asks how much of the file YouTube has, after an interrupted
+// send. done is set when the upload turns out to be complete.
+func (y *youTube) queryOffset(ctx context.Context, token, session string, size int64) (offset int64, id string, done bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, session, nil)
+	if err != nil {
+		return 0, "", false, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Range", fmt.Sprintf("bytes */%d", size))
+	resp, err := y.client.Do(req)
+	if err != nil {
+		return 0, "", false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusPermanentRedirect {
+		// "Range: bytes=0-N" covers what arrived; no header means nothing did.
+		r := resp.Header.Get("Range")
+		if _, last, ok := strings.Cut(r, "-"); ok {
+			n, err := strconv.ParseInt(last, 10, 64)
+			if err != nil {
+				return 0, "", false, fmt.Errorf("bad Range header %q", r)
+			}
+			return n + 1, "", false, nil
+		}
+		return 0, "", false, nil
+	}
+	id, err = videoID(resp)
+	return 0, id, err == nil, err
+}
+
+// videoID reads the video resource from a finished upload's response.
+func videoID(resp *http.Response) (string, error) {
+	switch {
+	case resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated:
+		var video struct {
+			ID string `json:"id"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&video); err != nil || video.ID == "" {
+			return "", fmt.Errorf("upload finished without a video id")
+		}
+		return video.ID, nil
+	case resp.StatusCode == http.StatusPermanentRedirect:
+		return "", retryableError{errors.New("upload incomplete")}
+	case resp.StatusCode >= 500:
+		return "", retryableError{httpError(resp)}
+	default:
+		return "", httpError(resp)
+	}
+}
+
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
@@ -0,0 +1,162 @@
+package webui
+
+import (
+	"embed"
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+//go:embed party
+var partyFiles embed.FS
+
+// PartyOptions describes what a party server plays.
+type PartyOptions struct {
+	// Token, when set, is required as for the dashboard.
+	Token string
+	// Hosts are host names, besides localhost and IP addresses, accepted in
+	// the Host header.
+	Hosts []string
+	// Title is shown on the pages.
+	Title string
+	// Media lists the files played one after another: the concat output
+	// alone, or every segment in timeline order.
+	Media []string
+	// Tracks lists the clips in playing order.
+	Tracks []PartyTrack
+	// Logf receives server activity; nil discards it.
+	Logf func(string, ...any)
+}
+
+// PartyTrack is one clip, placed within one of the media files.
+type PartyTrack struct {
+	Title     string  `json:"title"`
+	Artist    string  `json:"artist,omitempty"`
+	Submitter string  `json:"submitter,omitempty"`
+	Trivia    string  `json:"trivia,omitempty"`
+	Dare      string  `json:"dare,omitempty"`
+	Media     int     `json:"media"` // index into PartyOptions.Media
+	Start     float64 `json:"start"` // seconds into the media file
+	Seconds   float64 `json:"seconds"`
+	// Drink marks plan clips, which end with a drink; bumpers and other
+	// inline files don't.
+	Drink bool `json:"drink"`
+}
+
+// PartyPosition is where the playing page is. Time is seconds into the
+// media file at index Media.
+type PartyPosition struct {
+	Media  int     `json:"media"`
+	Time   float64 `json:"time"`
+	Paused bool    `json:"paused"`
+}
+
+// Party serves the party pages: the player at / and the companion at
+// /companion.html, which follows the player's position as it reports it.
+type Party struct {
+	opts PartyOptions
+
+	mu  sync.Mutex
+	pos *PartyPosition
+	hub wsHub
+}
+
+// NewParty returns a party server for opts.
+func NewParty(opts PartyOptions) *Party {
+	if opts.Logf == nil {
+		opts.Logf = func(string, ...any) {}
+	}
+	return &Party{opts: opts}
+}
+
+// Handler returns the pages, the media under /media/{n}, and the party API
+// under /api/party.
+func (p *Party) Handler() http.Handler {
+	static, err := fs.Sub(partyFiles, "party")
+	if err != nil {
+		panic(err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("GET /", http.FileServerFS(static))
+	mux.HandleFunc("GET /media/{n}/{name}", p.handleMedia)
+	mux.HandleFunc("GET /api/party", p.handleParty)
+	mux.HandleFunc("GET /api/party/position", p.handlePosition)
+	mux.HandleFunc("POST /api/party/position", p.handleReport)
+	mux.HandleFunc("GET /api/party/events", p.hub.serve)
+	return requireHost(p.opts.Hosts, requireToken(p.opts.Token, mux))
+}
+
+// Close disconnects every companion page.
+func (p *Party) Close() {
+	p.hub.closeAll()
+}
+
+func (p *Party) mediaURL(i int) string {
+	return "/media/" + strconv.Itoa(i) + "/" + url.PathEscape(filepath.Base(p.opts.Media[i]))
+}
+
+func (p *Party) handleMedia(w http.ResponseWriter, r *http.Request) {
+	i, err := strconv.Atoi(r.PathValue("n"))
+	if err != nil || i < 0 || i >= len(p.opts.Media) {
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeFile(w, r, p.opts.Media[i])
+}
+
+func (p *Party) handleParty(w http.ResponseWriter, _ *http.Request) {
+	media := make([]string, len(p.opts.Media))
+	for i := range media {
+		media[i] = p.mediaURL(i)
+	}
+	tracks := p.opts.Tracks
+	if tracks == nil {
+		tracks = []PartyTrack{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"title":  p.opts.Title,
+		"media":  media,
+		"tracks": tracks,
+	})
+}
+
+func (p *Party) handlePosition(w http.ResponseWriter, _ *http.Request) {
+	p.mu.Lock()
+	pos := p.pos
+	p.mu.Unlock()
+	if pos == nil {
+		writeError(w, http.StatusNotFound, errors.New("nothing is playing yet"))
+		return
+	}
+	writeJSON(w, http.StatusOK, pos)
+}
+
+// handleReport takes the player's position and passes it on to every
+// companion page.
+func (p *Party) handleReport(w http.ResponseWriter, r *http.Request) {
+	var pos PartyPosition
+	if err := decodeJSON(r, &pos); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if pos.Media < 0 || pos.Media >= len(p.opts.Media) || pos.Time < 0 {
+		writeError(w, http.StatusBadRequest, errors.New("position out of range"))
+		return
+	}
+	p.mu.Lock()
+	prev := p.pos
+	p.pos = &pos
+	p.mu.Unlock()
+	if prev == nil || prev.Media != pos.Media {
+		p.opts.Logf("party playing media %d", pos.Media)
+	}
+	p.hub.broadcast(struct {
+		Type string `json:"type"`
+		PartyPosition
+	}{"position", pos})
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,228 @@
+"use strict";
+
+// One script for both pages: the player (index.html) plays the media and
+// reports its position; the companion (companion.html) follows it over the
+// WebSocket and guesses the time in between from the clock.
+
+const $ = (sel) => document.querySelector(sel);
+const view = document.body.dataset.view;
+let party = { title: "", media: [], tracks: [] };
+let lastTrack = null;
+let lastRemaining = Infinity;
+
+function showError(message) {
+  const el = $("#error");
+  el.textContent = message;
+  el.hidden = false;
+  clearTimeout(showError.timer);
+  showError.timer = setTimeout(() => { el.hidden = true; }, 6000);
+}
+
+async function api(method, path, body) {
+  const opts = { method, headers: {} };
+  if (body !== undefined) {
+    opts.headers["Content-Type"] = "application/json";
+    opts.body = JSON.stringify(body);
+  }
+  const res = await fetch(path, opts);
+  if (!res.ok) {
+    let message = res.statusText;
+    try {
+      message = (await res.json()).error || message;
+    } catch (_) { /* not JSON */ }
+    throw new Error(message);
+  }
+  return res.status === 204 ? null : res.json();
+}
+
+// trackIndexAt returns the index of the track playing at time seconds
+// into media, or -1 between tracks.
+function trackIndexAt(media, time) {
+  return party.tracks.findIndex((t) => t.media === media && time >= t.start && time < t.start + t.seconds);
+}
+
+function formatCountdown(seconds) {
+  const s = Math.max(0, Math.ceil(seconds));
+  if (s < 60) return String(s);
+  return `${Math.floor(s / 60)}:${String(s % 60).padStart(2, "0")}`;
+}
+
+function flashDrink() {
+  const el = $("#drink");
+  el.hidden = false;
+  clearTimeout(flashDrink.timer);
+  flashDrink.timer = setTimeout(() => { el.hidden = true; }, 3000);
+}
+
+// showText sets an optional line and hides it when empty.
+function showText(sel, text) {
+  const el = $(sel);
+  el.textContent = text || "";
+  el.hidden = !text;
+}
+
+// show updates the song, submitter, trivia, dare and countdown for a
+// position.
+function show(media, time) {
+  const i = trackIndexAt(media, time);
+  const track = i >= 0 ? party.tracks[i] : null;
+  // A drink clip that ran out rather than being skipped: drink!
+  if (lastTrack && lastTrack !== track && lastTrack.drink && lastRemaining < 1.5) {
+    flashDrink();
+  }
+  lastTrack = track;
+
+  $("#title").textContent = track ? track.title : "";
+  $("#artist").textContent = track ? track.artist || "" : "";
+  $("#submitter").textContent = track && track.submitter ? `picked by ${track.submitter}` : "";
+  showText("#trivia", track && track.trivia);
+  showText("#dare", track && track.dare ? `Dare: ${track.dare}` : "");
+  const countdown = $("#countdown");
+  if (!track) {
+    lastRemaining = Infinity;
+    countdown.hidden = true;
+    return;
+  }
+  const remaining = track.start + track.seconds - time;
+  lastRemaining = remaining;
+  countdown.hidden = false;
+  countdown.classList.toggle("soon", track.drink && remaining <= 10);
+  $("#countdown-label").textContent = track.drink ? "Drink in" : "Up next in";
+  $("#countdown-value").textContent = formatCountdown(remaining);
+
+  const progress = $("#progress");
+  if (progress) {
+    const drinks = party.tracks.filter((t) => t.drink);
+    const n = drinks.indexOf(track) + 1;
+    progress.textContent = n > 0 ? `Song ${n} of ${drinks.length}` : "";
+  }
+}
+
+// Player
+
+function startPlayer() {
+  const video = $("#video");
+  let current = 0;
+
+  const report = () => {
+    api("POST", "/api/party/position", { media: current, time: video.currentTime, paused: video.paused })
+      .catch(() => { /* the next report will do */ });
+  };
+
+  const load = (media, time) => {
+    if (media !== current || !video.src) {
+      current = media;
+      video.src = party.media[media];
+    }
+    video.currentTime = time;
+    video.play().catch((err) => showError(err.message));
+  };
+
+  // skip moves to the track step places from the current one.
+  const skip = (step) => {
+    let i = trackIndexAt(current, video.currentTime);
+    if (i < 0) {
+      // Between tracks: count from the one before the gap.
+      const after = party.tracks.findIndex((t) => t.media > current || (t.media === current && t.start > video.currentTime));
+      i = (after < 0 ? party.tracks.length : after) - 1;
+    }
+    // Going back more than a few seconds in restarts the current track.
+    if (step < 0 && i >= 0 && video.currentTime - party.tracks[i].start > 3) step = 0;
+    const next = party.tracks[Math.min(Math.max(i + step, 0), party.tracks.length - 1)];
+    if (next) {
+      lastTrack = null;
+      load(next.media, next.start);
+    }
+  };
+
+  video.addEventListener("ended", () => {
+    if (current + 1 < party.media.length) load(current + 1, 0);
+    else report();
+  });
+  video.addEventListener("error", () => showError("can't play " + party.media[current]));
+  for (const type of ["play", "pause", "seeked"]) video.addEventListener(type, report);
+  setInterval(report, 1000);
+  setInterval(() => show(current, video.currentTime), 200);
+
+  document.addEventListener("keydown", (e) => {
+    switch (e.key) {
+      case " ":
+        if (video.paused) video.play();
+        else video.pause();
+        break;
+      case "n":
+      case "ArrowRight":
+        skip(1);
+        break;
+      case "p":
+      case "ArrowLeft":
+        skip(-1);
+        break;
+      case "f":
+        if (document.fullscreenElement) document.exitFullscreen();
+        else document.documentElement.requestFullscreen();
+        break;
+      default:
+        return;
+    }
+    e.preventDefault();
+  });
+
+  // Browsers only play sound after a click; resume where a reloaded page
+  // left off.
+  $("#start").addEventListener("click", async () => {
+    $("#start").hidden = true;
+    let pos = { media: 0, time: 0 };
+    try {
+      pos = await api("GET", "/api/party/position");
+    } catch (_) { /* nothing played yet */ }
+    load(pos.media, pos.time);
+  });
+}
+
+// Companion
+
+function startCompanion() {
+  let pos = null;
+  let receivedAt = 0;
+
+  const follow = (p) => {
+    pos = p;
+    receivedAt = performance.now();
+  };
+
+  const connect = () => {
+    const scheme = location.protocol === "https:" ? "wss:" : "ws:";
+    const ws = new WebSocket(`${scheme}//${location.host}/api/party/events`);
+    ws.addEventListener("open", () => {
+      $("#connection").textContent = "live";
+      api("GET", "/api/party/position").then(follow).catch(() => { /* not started */ });
+    });
+    ws.addEventListener("close", () => {
+      $("#connection").textContent = "disconnected, retrying…";
+      setTimeout(connect, 2000);
+    });
+    ws.addEventListener("message", (msg) => {
+      const ev = JSON.parse(msg.data);
+      if (ev.type === "position") follow(ev);
+    });
+  };
+
+  setInterval(() => {
+    if (!pos) return;
+    const elapsed = pos.paused ? 0 : (performance.now() - receivedAt) / 1000;
+    show(pos.media, pos.time + elapsed);
+  }, 200);
+  connect();
+}
+
+api("GET", "/api/party")
+  .then((p) => {
+    party = p;
+    if (p.title) document.title = `${p.title} · powerhour party`;
+    const title = $("#party-title");
+    if (title && p.title) title.textContent = p.title;
+    if (view === "player") startPlayer();
+    else startCompanion();
+  })
+  .catch((err) => showError(err.message));
@@ -0,0 +1,93 @@
+package webui
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPartyPositionAndMedia(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "power hour.mp4")
+	if err := os.WriteFile(file, []byte("video"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p := NewParty(PartyOptions{
+		Title:  "party",
+		Media:  []string{file},
+		Tracks: []PartyTrack{{Title: "Song", Seconds: 60, Drink: true}},
+	})
+	h := p.Handler()
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Host = "192.168.1.20:8788"
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	var info struct {
+		Media  []string     `json:"media"`
+		Tracks []PartyTrack `json:"tracks"`
+	}
+	rec := do(http.MethodGet, "/api/party", "")
+	if err := json.Unmarshal(rec.Body.Bytes(), &info); err != nil || len(info.Media) != 1 || len(info.Tracks) != 1 {
+		t.Fatalf("party = %s (%v)", rec.Body, err)
+	}
+	if info.Media[0] != "/media/0/power%20hour.mp4" {
+		t.Errorf("media url = %q", info.Media[0])
+	}
+	if rec := do(http.MethodGet, info.Media[0], ""); rec.Code != http.StatusOK || rec.Body.String() != "video" {
+		t.Errorf("media = %d %q", rec.Code, rec.Body)
+	}
+	if rec := do(http.MethodGet, "/media/1/x.mp4", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown media code = %d, want 404", rec.Code)
+	}
+
+	if rec := do(http.MethodGet, "/api/party/position", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("position before play = %d, want 404", rec.Code)
+	}
+	if rec := do(http.MethodPost, "/api/party/position", `{"media":0,"time":12.5}`); rec.Code != http.StatusNoContent {
+		t.Fatalf("report = %d %s", rec.Code, rec.Body)
+	}
+	if rec := do(http.MethodPost, "/api/party/position", `{"media":3,"time":1}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("out of range report = %d, want 400", rec.Code)
+	}
+	var pos PartyPosition
+	rec = do(http.MethodGet, "/api/party/position", "")
+	if err := json.Unmarshal(rec.Body.Bytes(), &pos); err != nil || pos.Time != 12.5 || pos.Paused {
+		t.Errorf("position = %s (%v)", rec.Body, err)
+	}
+
+	if rec := do(http.MethodGet, "/companion.html", ""); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `data-view="companion"`) {
+		t.Errorf("companion page = %d", rec.Code)
+	}
+}
+
+func TestPartyRequiresTokenAndHost(t *testing.T) {
+	h := NewParty(PartyOptions{Token: "cheers", Hosts: []string{"party-box.local"}}).Handler()
+	tests := []struct {
+		host, path string
+		code       int
+	}{
+		{"192.168.1.20:8788", "/api/party?token=cheers", http.StatusOK},
+		{"party-box.local:8788", "/api/party?token=cheers", http.StatusOK},
+		{"192.168.1.20:8788", "/api/party", http.StatusUnauthorized},
+		{"attacker.example:8788", "/api/party?token=cheers", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.Host = tt.host
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tt.code {
+			t.Errorf("%s %s code = %d, want %d", tt.host, tt.path, rec.Code, tt.code)
+		}
+	}
+}
@@ -0,0 +1,493 @@
+// Package webui serves a browser dashboard for a project: plan rows with
+// their cache and render state, inline edits of start times and skips, and
+// fetch/render jobs whose output streams to every open page over a
+// WebSocket. The project logic lives with the caller; the server only
+// routes requests to the callbacks in Options and runs jobs as powerhour
+// subprocesses. The same JSON API, described by the embedded OpenAPI spec
+// at /api/openapi.json, lets scripts drive a project without scraping CLI
+// output.
+package webui
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net"
+	"net/http"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:embed static
+var staticFiles embed.FS
+
+//go:embed openapi.json
+var openAPISpec []byte
+
+// ErrNotFound marks callback errors for things that don't exist; the API
+// answers them with 404.
+var ErrNotFound = errors.New("not found")
+
+// maxJobLines is how much job output is kept for pages opened mid-job.
+const maxJobLines = 500
+
+// tokenCookie carries the access token once a page has been opened with
+// ?token=.
+const tokenCookie = "powerhour_token"
+
+// Options wires the server to a project.
+type Options struct {
+	// Token, when set, is required on every request as a bearer token, a
+	// ?token= query parameter, or the cookie set after the first page load.
+	Token string
+	// Hosts are host names, besides localhost and IP addresses, accepted in
+	// the Host header.
+	Hosts []string
+	// Status returns the project snapshot served at /api/status.
+	Status func() (any, error)
+	// Config returns the loaded project config.
+	Config func() (any, error)
+	// Timeline returns the resolved playback order.
+	Timeline func() (any, error)
+	// Row returns one plan row with its cache and render state.
+	Row func(collection string, index int) (any, error)
+	// UpdateRow applies an edit to a plan row.
+	UpdateRow func(RowUpdate) error
+	// JobArgs validates a job request and returns the powerhour arguments
+	// that run it.
+	JobArgs func(JobRequest) ([]string, error)
+	// Executable is the powerhour binary jobs run.
+	Executable string
+	// Logf receives server activity; nil discards it.
+	Logf func(string, ...any)
+	// APIOnly leaves out the browser dashboard.
+	APIOnly bool
+}
+
+// RowUpdate is an edit to one plan row; nil fields are left alone.
+type RowUpdate struct {
+	Collection string  `json:"-"`
+	Index      int     `json:"-"`
+	StartTime  *string `json:"start_time,omitempty"`
+	Skip       *bool   `json:"skip,omitempty"`
+}
+
+// JobRequest asks for a fetch, render, or concat run.
+type JobRequest struct {
+	Command    string `json:"command"`
+	Collection string `json:"collection,omitempty"`
+	Indexes    []int  `json:"indexes,omitempty"`
+}
+
+// Job is the current or last job.
+type Job struct {
+	Command    string    `json:"command"`
+	Args       []string  `json:"args"`
+	Running    bool      `json:"running"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at,omitzero"`
+	Error      string    `json:"error,omitempty"`
+	Lines      []string  `json:"lines"`
+}
+
+// Event is pushed to every WebSocket client.
+type Event struct {
+	Type  string `json:"type"` // job_started, log, job_finished, plan_changed
+	Job   string `json:"job,omitempty"`
+	Line  string `json:"line,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// Server is the dashboard's HTTP handler and job runner.
+type Server struct {
+	opts Options
+	ctx  context.Context
+
+	mu        sync.Mutex
+	hub       wsHub
+	job       *Job
+	cancelJob context.CancelFunc
+}
+
+// New returns a server whose jobs are cancelled when ctx is done.
+func New(ctx context.Context, opts Options) *Server {
+	if opts.Logf == nil {
+		opts.Logf = func(string, ...any) {}
+	}
+	return &Server{opts: opts, ctx: ctx}
+}
+
+// Handler returns the routes: the embedded frontend at / (unless APIOnly)
+// and the JSON API under /api.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	if !s.opts.APIOnly {
+		static, err := fs.Sub(staticFiles, "static")
+		if err != nil {
+			panic(err)
+		}
+		mux.Handle("GET /", http.FileServerFS(static))
+	}
+	mux.HandleFunc("GET /api/openapi.json", handleOpenAPI)
+	mux.HandleFunc("GET /api/status", s.handleStatus)
+	mux.HandleFunc("GET /api/config", s.handleGet(s.opts.Config))
+	mux.HandleFunc("GET /api/timeline", s.handleGet(s.opts.Timeline))
+	mux.HandleFunc("GET /api/collections/{collection}/rows/{index}", s.handleRow)
+	mux.HandleFunc("PATCH /api/collections/{collection}/rows/{index}", s.handleUpdateRow)
+	mux.HandleFunc("GET /api/jobs", s.handleJob)
+	mux.HandleFunc("POST /api/jobs", s.handleStartJob)
+	mux.HandleFunc("DELETE /api/jobs", s.handleCancelJob)
+	mux.HandleFunc("GET /api/events", s.handleEvents)
+	return s.authorize(mux)
+}
+
+// authorize enforces Options.Token and Options.Hosts. A valid ?token= sets
+// a cookie so the page's own API calls and WebSocket are let through.
+func (s *Server) authorize(next http.Handler) http.Handler {
+	return requireHost(s.opts.Hosts, requireToken(s.opts.Token, next))
+}
+
+// requireHost rejects requests whose Host header isn't localhost, an IP
+// address or one of hosts. A DNS-rebound page reaches the server under its
+// own domain name, so this keeps other sites from calling the API through
+// the visitor's browser; their requests would otherwise pass as same-origin.
+func requireHost(hosts []string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !allowedHost(r.Host, hosts) {
+			http.Error(w, "unexpected Host header", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func allowedHost(hostport string, hosts []string) bool {
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.Trim(host, "[]"), ".")
+	if strings.EqualFold(host, "localhost") || net.ParseIP(host) != nil {
+		return true
+	}
+	for _, h := range hosts {
+		if strings.EqualFold(host, h) {
+			return true
+		}
+	}
+	return false
+}
+
+// requireToken lets through requests carrying token; an empty token lets
+// everything through.
+func requireToken(token string, next http.Handler) http.Handler {
+	if token == "" {
+		return next
+	}
+	want := []byte(token)
+	valid := func(got string) bool {
+		return got != "" && subtle.ConstantTimeCompare([]byte(got), want) == 1
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token := r.URL.Query().Get("token"); valid(token) {
+			http.SetCookie(w, &http.Cookie{Name: tokenCookie, Value: token, Path: "/", HttpOnly: true, SameSite: http.SameSiteStrictMode})
+			next.ServeHTTP(w, r)
+			return
+		}
+		if c, err := r.Cookie(tokenCookie); err == nil && valid(c.Value) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if auth := r.Header.Get("Authorization"); len(auth) > 7 && auth[:7] == "Bearer " && valid(auth[7:]) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		http.Error(w, "missing or invalid token", http.StatusUnauthorized)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// decodeJSON reads a JSON request body. Requiring the JSON content type
+// keeps other sites from posting forms here: a cross-origin JSON request
+// needs a preflight this server never answers.
+func decodeJSON(r *http.Request, v any) error {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/json" {
+		return errors.New("content type must be application/json")
+	}
+	dec := json.NewDecoder(io.LimitReader(r.Body, 1<<20))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("decode request: %w", err)
+	}
+	return nil
+}
+
+// errorStatus maps a callback error to a response code.
+func errorStatus(err error, fallback int) int {
+	if errors.Is(err, ErrNotFound) {
+		return http.StatusNotFound
+	}
+	return fallback
+}
+
+// rowIndex reads the {index} path value.
+func rowIndex(r *http.Request) (int, error) {
+	index, err := strconv.Atoi(r.PathValue("index"))
+	if err != nil || index < 1 {
+		return 0, fmt.Errorf("invalid row index %q", r.PathValue("index"))
+	}
+	return index, nil
+}
+
+func handleOpenAPI(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(openAPISpec)
+}
+
+// handleGet serves the result of a read-only callback.
+func (s *Server) handleGet(get func() (any, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		if get == nil {
+			writeError(w, http.StatusNotImplemented, errors.New("not available"))
+			return
+		}
+		v, err := get()
+		if err != nil {
+			writeError(w, errorStatus(err, http.StatusInternalServerError), err)
+			return
+		}
+		writeJSON(w, http.StatusOK, v)
+	}
+}
+
+func (s *Server) handleRow(w http.ResponseWriter, r *http.Request) {
+	index, err := rowIndex(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if s.opts.Row == nil {
+		writeError(w, http.StatusNotImplemented, errors.New("not available"))
+		return
+	}
+	row, err := s.opts.Row(r.PathValue("collection"), index)
+	if err != nil {
+		writeError(w, errorStatus(err, http.StatusInternalServerError), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, row)
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, _ *http.Request) {
+	status, err := s.opts.Status()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"project": status, "job": s.snapshotJob()})
+}
+
+func (s *Server) handleUpdateRow(w http.ResponseWriter, r *http.Request) {
+	var update RowUpdate
+	if err := decodeJSON(r, &update); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	index, err := rowIndex(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	update.Collection, update.Index = r.PathValue("collection"), index
+	if err := s.opts.UpdateRow(update); err != nil {
+		writeError(w, errorStatus(err, http.StatusUnprocessableEntity), err)
+		return
+	}
+	s.opts.Logf("row updated: %s #%d", update.Collection, update.Index)
+	s.broadcast(Event{Type: "plan_changed"})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleStartJob(w http.ResponseWriter, r *http.Request) {
+	var req JobRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	args, err := s.opts.JobArgs(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	done, err := s.startJob(req.Command, args)
+	if err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	// ?wait=true holds the response until the job finishes, so a script
+	// can run one step after another without polling.
+	if wait, _ := strconv.ParseBool(r.URL.Query().Get("wait")); wait {
+		select {
+		case <-done:
+			writeJSON(w, http.StatusOK, s.snapshotJob())
+		case <-r.Context().Done():
+		}
+		return
+	}
+	writeJSON(w, http.StatusAccepted, s.snapshotJob())
+}
+
+func (s *Server) handleJob(w http.ResponseWriter, _ *http.Request) {
+	job := s.snapshotJob()
+	if job == nil {
+		writeError(w, http.StatusNotFound, errors.New("no job has run"))
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+func (s *Server) handleCancelJob(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	cancel := s.cancelJob
+	running := s.job != nil && s.job.Running
+	s.mu.Unlock()
+	if !running || cancel == nil {
+		writeError(w, http.StatusConflict, errors.New("no job is running"))
+		return
+	}
+	cancel()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	s.hub.serve(w, r)
+}
+
+// broadcast sends ev to every client.
+func (s *Server) broadcast(ev Event) {
+	s.hub.broadcast(ev)
+}
+
+func (s *Server) snapshotJob() *Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.job == nil {
+		return nil
+	}
+	job := *s.job
+	job.Lines = append([]string(nil), s.job.Lines...)
+	return &job
+}
+
+// startJob runs powerhour with args in the background, one job at a time,
+// streaming its combined output line by line. The returned channel is
+// closed when the job finishes.
+func (s *Server) startJob(command string, args []string) (<-chan struct{}, error) {
+	s.mu.Lock()
+	if s.job != nil && s.job.Running {
+		running := s.job.Command
+		s.mu.Unlock()
+		return nil, fmt.Errorf("a %s job is already running", running)
+	}
+	ctx, cancel := context.WithCancel(s.ctx)
+	proc := exec.CommandContext(ctx, s.opts.Executable, args...)
+	pr, pw := io.Pipe()
+	proc.Stdout, proc.Stderr = pw, pw
+	if err := proc.Start(); err != nil {
+		s.mu.Unlock()
+		cancel()
+		return nil, fmt.Errorf("start %s: %w", command, err)
+	}
+	s.job = &Job{Command: command, Args: args, Running: true, StartedAt: time.Now()}
+	s.cancelJob = cancel
+	jobDone := make(chan struct{})
+	s.mu.Unlock()
+
+	s.opts.Logf("job started: %s %v", command, args)
+	s.broadcast(Event{Type: "job_started", Job: command})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scanner := bufio.NewScanner(pr)
+		scanner.Split(scanOutputLines)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if line == "" {
+				continue
+			}
+			s.mu.Lock()
+			s.job.Lines = append(s.job.Lines, line)
+			if len(s.job.Lines) > maxJobLines {
+				s.job.Lines = s.job.Lines[len(s.job.Lines)-maxJobLines:]
+			}
+			s.mu.Unlock()
+			s.broadcast(Event{Type: "log", Job: command, Line: line})
+		}
+		// Drain anything left so the process never blocks on a full pipe.
+		_, _ = io.Copy(io.Discard, pr)
+	}()
+	go func() {
+		err := proc.Wait()
+		pw.Close()
+		<-done
+		cancel()
+
+		ev := Event{Type: "job_finished", Job: command}
+		s.mu.Lock()
+		s.job.Running = false
+		s.job.FinishedAt = time.Now()
+		if err != nil {
+			s.job.Error = err.Error()
+			ev.Error = err.Error()
+		}
+		s.mu.Unlock()
+		close(jobDone)
+		s.opts.Logf("job finished: %s err=%v", command, err)
+		s.broadcast(ev)
+	}()
+	return jobDone, nil
+}
+
+// scanOutputLines splits job output on newlines and on the carriage returns
+// progress lines are redrawn with.
+func scanOutputLines(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	for i, b := range data {
+		if b == '\n' || b == '\r' {
+			return i + 1, data[:i], nil
+		}
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// Close disconnects every WebSocket client; http.Server.Shutdown doesn't
+// track hijacked connections.
+func (s *Server) Close() {
+	s.hub.closeAll()
+}
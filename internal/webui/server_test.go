@@ -0,0 +1,241 @@
+package webui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServerStatusAndRowUpdate(t *testing.T) {
+	var got RowUpdate
+	s := New(t.Context(), Options{
+		Status: func() (any, error) { return map[string]string{"name": "party"}, nil },
+		UpdateRow: func(u RowUpdate) error {
+			if u.Index == 99 {
+				return errors.New("no row 99")
+			}
+			got = u
+			return nil
+		},
+	})
+	h := s.Handler()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, newLocalRequest(http.MethodGet, "/api/status", nil))
+	var status struct {
+		Project map[string]string `json:"project"`
+		Job     *Job              `json:"job"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil || status.Project["name"] != "party" || status.Job != nil {
+		t.Fatalf("status = %s (%v)", rec.Body, err)
+	}
+
+	patch := func(path, contentType, body string) int {
+		req := newLocalRequest(http.MethodPatch, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	if code := patch("/api/collections/songs/rows/3", "application/json", `{"start_time":"1:27","skip":true}`); code != http.StatusNoContent {
+		t.Fatalf("patch code = %d", code)
+	}
+	if got.Collection != "songs" || got.Index != 3 || got.StartTime == nil || *got.StartTime != "1:27" || got.Skip == nil || !*got.Skip {
+		t.Fatalf("update = %+v", got)
+	}
+	if code := patch("/api/collections/songs/rows/3", "text/plain", `{"skip":true}`); code != http.StatusBadRequest {
+		t.Errorf("non-JSON patch code = %d, want 400", code)
+	}
+	if code := patch("/api/collections/songs/rows/99", "application/json", `{"skip":true}`); code != http.StatusUnprocessableEntity {
+		t.Errorf("failed update code = %d, want 422", code)
+	}
+}
+
+func TestServerToken(t *testing.T) {
+	s := New(t.Context(), Options{Token: "secret", Status: func() (any, error) { return nil, nil }})
+	h := s.Handler()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, newLocalRequest(http.MethodGet, "/api/status", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("no token code = %d, want 401", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, newLocalRequest(http.MethodGet, "/?token=secret", nil))
+	if rec.Code != http.StatusOK || len(rec.Result().Cookies()) != 1 {
+		t.Fatalf("token query code = %d cookies = %v", rec.Code, rec.Result().Cookies())
+	}
+
+	req := newLocalRequest(http.MethodGet, "/api/status", nil)
+	req.AddCookie(rec.Result().Cookies()[0])
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("cookie code = %d, want 200", rec.Code)
+	}
+}
+
+func TestServerJob(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	s := New(t.Context(), Options{
+		Executable: sh,
+		JobArgs: func(req JobRequest) ([]string, error) {
+			if req.Command != "fetch" {
+				return nil, errors.New("unsupported command")
+			}
+			return []string{"-c", "echo one; printf 'two\\rthree\\n' >&2; sleep 0.2"}, nil
+		},
+	})
+	h := s.Handler()
+	post := func(body string) int {
+		req := newLocalRequest(http.MethodPost, "/api/jobs", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := post(`{"command":"wipe"}`); code != http.StatusBadRequest {
+		t.Fatalf("bad command code = %d, want 400", code)
+	}
+	if code := post(`{"command":"fetch"}`); code != http.StatusAccepted {
+		t.Fatalf("start code = %d, want 202", code)
+	}
+	if code := post(`{"command":"fetch"}`); code != http.StatusConflict {
+		t.Fatalf("second start code = %d, want 409", code)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job := s.snapshotJob()
+		if !job.Running {
+			if job.Error != "" || strings.Join(job.Lines, "|") != "one|two|three" {
+				t.Fatalf("job = %+v", job)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("job did not finish")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	req := newLocalRequest(http.MethodPost, "/api/jobs?wait=true", strings.NewReader(`{"command":"fetch"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var job Job
+	if err := json.Unmarshal(rec.Body.Bytes(), &job); err != nil || rec.Code != http.StatusOK || job.Running || job.FinishedAt.IsZero() {
+		t.Fatalf("wait = %d %s (%v)", rec.Code, rec.Body, err)
+	}
+}
+
+func TestServerReadAPI(t *testing.T) {
+	s := New(t.Context(), Options{
+		APIOnly:  true,
+		Config:   func() (any, error) { return map[string]string{"file": "powerhour.yaml"}, nil },
+		Timeline: func() (any, error) { return []int{1, 2}, nil },
+		Row: func(collection string, index int) (any, error) {
+			if collection != "songs" {
+				return nil, fmt.Errorf("collection %q: %w", collection, ErrNotFound)
+			}
+			return map[string]int{"index": index}, nil
+		},
+	})
+	h := s.Handler()
+
+	tests := []struct {
+		path string
+		code int
+		body string
+	}{
+		{"/api/config", http.StatusOK, `{"file":"powerhour.yaml"}`},
+		{"/api/timeline", http.StatusOK, `[1,2]`},
+		{"/api/collections/songs/rows/4", http.StatusOK, `{"index":4}`},
+		{"/api/collections/intros/rows/4", http.StatusNotFound, `{"error":"collection \"intros\": not found"}`},
+		{"/api/collections/songs/rows/x", http.StatusBadRequest, `{"error":"invalid row index \"x\""}`},
+		{"/api/jobs", http.StatusNotFound, `{"error":"no job has run"}`},
+		{"/", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, newLocalRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != tt.code {
+			t.Errorf("GET %s code = %d, want %d", tt.path, rec.Code, tt.code)
+		}
+		if tt.body != "" && strings.TrimSpace(rec.Body.String()) != tt.body {
+			t.Errorf("GET %s body = %s, want %s", tt.path, rec.Body, tt.body)
+		}
+	}
+}
+
+func TestOpenAPISpecCoversRoutes(t *testing.T) {
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(openAPISpec, &spec); err != nil {
+		t.Fatalf("parse spec: %v", err)
+	}
+	routes := []string{
+		"GET /api/openapi.json",
+		"GET /api/status",
+		"GET /api/config",
+		"GET /api/timeline",
+		"GET /api/collections/{collection}/rows/{index}",
+		"PATCH /api/collections/{collection}/rows/{index}",
+		"GET /api/jobs",
+		"POST /api/jobs",
+		"DELETE /api/jobs",
+		"GET /api/events",
+	}
+	for _, route := range routes {
+		method, path, _ := strings.Cut(route, " ")
+		if _, ok := spec.Paths[path][strings.ToLower(method)]; !ok {
+			t.Errorf("spec is missing %s", route)
+		}
+	}
+}
+
+// newLocalRequest is a request addressed to the server on localhost.
+func newLocalRequest(method, target string, body io.Reader) *http.Request {
+	req := httptest.NewRequest(method, target, body)
+	req.Host = "127.0.0.1:8787"
+	return req
+}
+
+func TestServerRejectsForeignHost(t *testing.T) {
+	s := New(t.Context(), Options{Hosts: []string{"party-box.local"}, Status: func() (any, error) { return nil, nil }})
+	h := s.Handler()
+	tests := []struct {
+		host string
+		code int
+	}{
+		{"127.0.0.1:8787", http.StatusOK},
+		{"localhost:8787", http.StatusOK},
+		{"[::1]:8787", http.StatusOK},
+		{"192.168.1.20:8787", http.StatusOK},
+		{"Party-Box.local:8787", http.StatusOK},
+		{"attacker.example:8787", http.StatusForbidden},
+		{"localhost.attacker.example", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/status", nil)
+		req.Host = tt.host
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tt.code {
+			t.Errorf("Host %s code = %d, want %d", tt.host, rec.Code, tt.code)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+"use strict";
+
+const $ = (sel) => document.querySelector(sel);
+const selected = new Map(); // collection -> Set of row indexes
+let refreshPending = false;
+
+function showError(message) {
+  const el = $("#error");
+  el.textContent = message;
+  el.hidden = false;
+  clearTimeout(showError.timer);
+  showError.timer = setTimeout(() => { el.hidden = true; }, 6000);
+}
+
+async function api(method, path, body) {
+  const opts = { method, headers: {} };
+  if (body !== undefined) {
+    opts.headers["Content-Type"] = "application/json";
+    opts.body = JSON.stringify(body);
+  }
+  const res = await fetch(path, opts);
+  if (!res.ok) {
+    let message = res.statusText;
+    try {
+      message = (await res.json()).error || message;
+    } catch (_) { /* not JSON */ }
+    throw new Error(message);
+  }
+  return res.status === 204 ? null : res.json();
+}
+
+function cell(row, content, className) {
+  const td = document.createElement("td");
+  if (className) td.className = className;
+  if (content instanceof Node) td.append(content);
+  else td.textContent = content ?? "";
+  row.append(td);
+  return td;
+}
+
+function statusCell(row, status) {
+  cell(row, status, "status-" + status);
+}
+
+function renderCollection(coll) {
+  const section = document.createElement("div");
+  const title = document.createElement("h2");
+  title.textContent = coll.name;
+  if (coll.plan) {
+    const plan = document.createElement("span");
+    plan.className = "muted";
+    plan.textContent = " " + coll.plan;
+    title.append(plan);
+  }
+  section.append(title);
+
+  const table = document.createElement("table");
+  table.innerHTML = "<thead><tr><th></th><th>#</th><th>Skip</th><th>Start</th><th>Dur</th>" +
+    "<th>Title</th><th>Artist</th><th>Cache</th><th>Render</th></tr></thead>";
+  const tbody = document.createElement("tbody");
+  const picked = selected.get(coll.name) || new Set();
+
+  for (const r of coll.rows) {
+    const tr = document.createElement("tr");
+    if (r.skipped) tr.className = "skipped";
+
+    const pick = document.createElement("input");
+    pick.type = "checkbox";
+    pick.checked = picked.has(r.index);
+    pick.addEventListener("change", () => {
+      if (!selected.has(coll.name)) selected.set(coll.name, new Set());
+      if (pick.checked) selected.get(coll.name).add(r.index);
+      else selected.get(coll.name).delete(r.index);
+    });
+    cell(tr, pick);
+    cell(tr, r.index);
+
+    const skip = document.createElement("input");
+    skip.type = "checkbox";
+    skip.checked = r.skipped;
+    skip.disabled = !coll.editable;
+    skip.addEventListener("change", () => updateRow(coll.name, r.index, { skip: skip.checked }));
+    cell(tr, skip);
+
+    const start = document.createElement("input");
+    start.className = "start";
+    start.value = r.start || "";
+    start.disabled = !coll.editable || r.start_overridden;
+    if (r.start_overridden) start.title = "Set by the overrides file";
+    start.addEventListener("input", () => start.classList.toggle("dirty", start.value !== r.start));
+    start.addEventListener("keydown", (e) => {
+      if (e.key === "Enter") start.blur();
+      if (e.key === "Escape") { start.value = r.start; start.blur(); }
+    });
+    start.addEventListener("blur", () => {
+      if (start.value.trim() !== r.start) updateRow(coll.name, r.index, { start_time: start.value.trim() });
+      else if (refreshPending) refresh();
+    });
+    cell(tr, start);
+
+    cell(tr, r.duration ? r.duration + "s" : "");
+    const titleCell = cell(tr, r.title, "title");
+    titleCell.title = r.title || "";
+    cell(tr, r.artist);
+    statusCell(tr, r.cache);
+    statusCell(tr, r.render);
+    tbody.append(tr);
+  }
+  table.append(tbody);
+  section.append(table);
+  return section;
+}
+
+function renderJob(job) {
+  const state = $("#job-state");
+  const running = Boolean(job && job.running);
+  $("#cancel").hidden = !running;
+  for (const b of document.querySelectorAll("button[data-job]")) b.disabled = running;
+  if (!job) {
+    state.textContent = "idle";
+    return;
+  }
+  state.textContent = job.command + (running ? " running…" : job.error ? " failed: " + job.error : " finished");
+}
+
+async function refresh() {
+  if (document.activeElement && document.activeElement.classList.contains("start")) {
+    refreshPending = true;
+    return;
+  }
+  refreshPending = false;
+  try {
+    const data = await api("GET", "/api/status");
+    $("#project").textContent = data.project.name || "";
+    document.title = "powerhour · " + (data.project.name || "");
+    const container = $("#collections");
+    container.replaceChildren(...data.project.collections.map(renderCollection));
+    renderJob(data.job);
+    if (data.job && !$("#log").textContent) {
+      $("#log").textContent = data.job.lines.join("\n") + "\n";
+    }
+  } catch (err) {
+    showError(err.message);
+  }
+}
+
+async function updateRow(collection, index, change) {
+  try {
+    await api("PATCH", `/api/collections/${encodeURIComponent(collection)}/rows/${index}`, change);
+  } catch (err) {
+    showError(err.message);
+  }
+  refresh();
+}
+
+async function startJob(command) {
+  const req = { command };
+  if (command !== "concat") {
+    const withRows = [...selected].filter(([, rows]) => rows.size > 0);
+    if (withRows.length > 1) {
+      showError("Select rows from one collection at a time");
+      return;
+    }
+    if (withRows.length === 1) {
+      req.collection = withRows[0][0];
+      req.indexes = [...withRows[0][1]].sort((a, b) => a - b);
+    }
+  }
+  try {
+    renderJob(await api("POST", "/api/jobs", req));
+  } catch (err) {
+    showError(err.message);
+  }
+}
+
+function appendLog(line) {
+  const log = $("#log");
+  const atBottom = log.scrollTop + log.clientHeight >= log.scrollHeight - 4;
+  log.textContent += line + "\n";
+  if (atBottom) log.scrollTop = log.scrollHeight;
+}
+
+function connect() {
+  const scheme = location.protocol === "https:" ? "wss:" : "ws:";
+  const ws = new WebSocket(`${scheme}//${location.host}/api/events`);
+  ws.addEventListener("open", () => {
+    $("#connection").textContent = "live";
+    refresh();
+  });
+  ws.addEventListener("close", () => {
+    $("#connection").textContent = "disconnected, retrying…";
+    setTimeout(connect, 2000);
+  });
+  ws.addEventListener("message", (msg) => {
+    const ev = JSON.parse(msg.data);
+    switch (ev.type) {
+      case "job_started":
+        $("#log").textContent = "";
+        renderJob({ command: ev.job, running: true });
+        break;
+      case "log":
+        appendLog(ev.line);
+        break;
+      case "job_finished":
+        if (ev.error) appendLog("error: " + ev.error);
+        refresh();
+        break;
+      case "plan_changed":
+        refresh();
+        break;
+    }
+  });
+}
+
+for (const b of document.querySelectorAll("button[data-job]")) {
+  b.addEventListener("click", () => startJob(b.dataset.job));
+}
+$("#cancel").addEventListener("click", () => api("DELETE", "/api/jobs").catch((err) => showError(err.message)));
+
+refresh();
+connect();
@@ -0,0 +1,138 @@
+package webui
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAcceptKey(t *testing.T) {
+	// The example handshake from RFC 6455 section 1.3.
+	if got := acceptKey("dGhlIHNhbXBsZSBub25jZQ=="); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("acceptKey = %q", got)
+	}
+}
+
+func TestFrameHeader(t *testing.T) {
+	tests := []struct {
+		n    int
+		want []byte
+	}{
+		{5, []byte{0x81, 5}},
+		{126, []byte{0x81, 126, 0, 126}},
+		{70000, []byte{0x81, 127, 0, 0, 0, 0, 0, 1, 0x11, 0x70}},
+	}
+	for _, tt := range tests {
+		if got := frameHeader(opText, tt.n); !bytes.Equal(got, tt.want) {
+			t.Errorf("frameHeader(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestSameOrigin(t *testing.T) {
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"", true},
+		{"http://127.0.0.1:8787", true},
+		{"http://evil.example", false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "http://127.0.0.1:8787/api/events", nil)
+		if tt.origin != "" {
+			r.Header.Set("Origin", tt.origin)
+		}
+		if got := sameOrigin(r); got != tt.want {
+			t.Errorf("sameOrigin(%q) = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+}
+
+// dialEvents opens a raw WebSocket to the events endpoint.
+func dialEvents(t *testing.T, srv *httptest.Server) (net.Conn, *bufio.Reader) {
+	t.Helper()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+	req := "GET /api/events HTTP/1.1\r\nHost: " + strings.TrimPrefix(srv.URL, "http://") + "\r\n" +
+		"Upgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Version: 13\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n"
+	if _, err := conn.Write([]byte(req)); err != nil {
+		t.Fatal(err)
+	}
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("handshake: %s %v", resp.Status, resp.Header)
+	}
+	return conn, br
+}
+
+// readServerFrame reads one unmasked frame with a short payload.
+func readServerFrame(t *testing.T, br *bufio.Reader) (byte, string) {
+	t.Helper()
+	head := make([]byte, 2)
+	if _, err := br.Read(head[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := br.Read(head[1:]); err != nil {
+		t.Fatal(err)
+	}
+	payload := make([]byte, head[1]&0x7F)
+	for read := 0; read < len(payload); {
+		n, err := br.Read(payload[read:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		read += n
+	}
+	return head[0] & 0x0F, string(payload)
+}
+
+func maskedFrame(opcode byte, payload string) []byte {
+	mask := []byte{1, 2, 3, 4}
+	frame := []byte{0x80 | opcode, 0x80 | byte(len(payload))}
+	frame = append(frame, mask...)
+	for i := range len(payload) {
+		frame = append(frame, payload[i]^mask[i%4])
+	}
+	return frame
+}
+
+func TestEventsWebSocket(t *testing.T) {
+	s := New(t.Context(), Options{})
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+	conn, br := dialEvents(t, srv)
+
+	if _, err := conn.Write(maskedFrame(opPing, "hi")); err != nil {
+		t.Fatal(err)
+	}
+	if op, payload := readServerFrame(t, br); op != opPong || payload != "hi" {
+		t.Fatalf("got opcode %x %q, want pong hi", op, payload)
+	}
+
+	s.broadcast(Event{Type: "plan_changed"})
+	if op, payload := readServerFrame(t, br); op != opText || payload != `{"type":"plan_changed"}` {
+		t.Fatalf("got opcode %x %q, want plan_changed event", op, payload)
+	}
+
+	if _, err := conn.Write(maskedFrame(opClose, "")); err != nil {
+		t.Fatal(err)
+	}
+	if op, _ := readServerFrame(t, br); op != opClose {
+		t.Fatalf("got opcode %x, want close", op)
+	}
+}
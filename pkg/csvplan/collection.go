@@ -18,6 +18,7 @@ type CollectionOptions struct {
 	StartHeader     string // CSV column name for start time
 	DurationHeader  string // CSV column name for duration (optional)
 	DefaultDuration int    // Fallback duration if not specified
+	Sheet           string // Worksheet to read from an .xlsx plan (empty = first)
 }
 
 // CollectionRow represents a single clip from a collection plan with dynamic fields.
@@ -30,11 +31,12 @@ type CollectionRow struct {
 	CustomFields    map[string]string // All CSV columns as key-value pairs
 }
 
-// LoadCollection reads a CSV with configurable headers for a collection.
+// LoadCollection reads a CSV/TSV file, or one sheet of an .xlsx workbook,
+// with configurable headers for a collection.
 func LoadCollection(path string, opts CollectionOptions) ([]CollectionRow, error) {
-	data, err := os.ReadFile(path)
+	data, err := readPlanData(path, opts.Sheet)
 	if err != nil {
-		return nil, fmt.Errorf("read file: %w", err)
+		return nil, err
 	}
 	return loadCollectionData(data, opts)
 }
@@ -198,16 +200,10 @@ func parseCollectionRecord(record []string, header map[string]int, index, line i
 	}
 
 	startRaw := get(opts.StartHeader)
-	var startDur time.Duration
-	if startRaw == "" {
-		errs = append(errs, ValidationError{Line: line, Field: opts.StartHeader, Message: fmt.Sprintf("%s is required", opts.StartHeader)})
-	} else {
-		d, err := parseStartTime(startRaw)
-		if err != nil {
-			errs = append(errs, ValidationError{Line: line, Field: opts.StartHeader, Message: err.Error()})
-		} else {
-			startDur = d
-		}
+	timing, timingErrs := parseRowTiming(startRaw, get(EndTimeField), get(WindowsField), opts.StartHeader, line)
+	errs = append(errs, timingErrs...)
+	if timing.window.StartText != "" {
+		startRaw = timing.window.StartText
 	}
 
 	// Get duration (optional with default)
@@ -228,6 +224,11 @@ func parseCollectionRecord(record []string, header map[string]int, index, line i
 		}
 	}
 
+	// An end time (end_time or a start range) or windows set the duration.
+	if seconds := timing.seconds(); seconds > 0 {
+		durationSeconds = seconds
+	}
+
 	if durationSeconds <= 0 {
 		errs = append(errs, ValidationError{Line: line, Field: "duration", Message: "duration must be greater than 0"})
 	}
@@ -247,7 +248,7 @@ func parseCollectionRecord(record []string, header map[string]int, index, line i
 		Index:           index,
 		Link:            link,
 		StartRaw:        startRaw,
-		Start:           startDur,
+		Start:           timing.window.Start,
 		DurationSeconds: durationSeconds,
 		CustomFields:    customFields,
 	}
@@ -292,6 +293,37 @@ func ReadHeaders(path string) (headers []string, delimiter rune, err error) {
 	return headers, delimiter, nil
 }
 
+// ReadHeaderRow returns every column of the header row, normalized, in file
+// order. Unlike ReadHeaders it keeps blank names, which LoadCollection
+// ignores, so positions line up with the data. sheet picks the worksheet of
+// an .xlsx plan.
+func ReadHeaderRow(path, sheet string) ([]string, error) {
+	data, err := readPlanData(path, sheet)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("plan file is empty")
+	}
+	delimiter, err := detectDelimiter(data)
+	if err != nil {
+		return nil, err
+	}
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	record, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	record = trimTrailingFields(record)
+	headers := make([]string, len(record))
+	for i, raw := range record {
+		headers[i] = normalizeHeader(raw)
+	}
+	return headers, nil
+}
+
 // ToRow converts a CollectionRow to a standard Row for compatibility with existing systems.
 func (cr CollectionRow) ToRow() Row {
 	return Row{
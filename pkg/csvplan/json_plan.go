@@ -0,0 +1,110 @@
+package csvplan
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Plan storage formats, as reported by DetectPlanFormat.
+const (
+	PlanFormatCSV  = "csv" // CSV or TSV
+	PlanFormatYAML = "yaml"
+	PlanFormatJSON = "json"
+	PlanFormatXLSX = "xlsx"
+)
+
+// DetectPlanFormat returns the storage format of a plan file from its
+// extension. Anything that isn't YAML, JSON or a workbook is read as
+// CSV/TSV.
+func DetectPlanFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return PlanFormatYAML
+	case ".json":
+		return PlanFormatJSON
+	case ".xlsx":
+		return PlanFormatXLSX
+	default:
+		return PlanFormatCSV
+	}
+}
+
+// IsStructuredPlan reports whether path holds a YAML or JSON plan, which
+// load through LoadCollectionStructured rather than LoadCollection.
+func IsStructuredPlan(path string) bool {
+	format := DetectPlanFormat(path)
+	return format == PlanFormatYAML || format == PlanFormatJSON
+}
+
+// LoadCollectionStructured loads a YAML or JSON plan, picking the parser by
+// extension.
+func LoadCollectionStructured(path string, opts CollectionOptions) (YAMLResult, error) {
+	if DetectPlanFormat(path) == PlanFormatJSON {
+		return LoadCollectionJSON(path, opts)
+	}
+	return LoadCollectionYAML(path, opts)
+}
+
+// LoadCollectionJSON reads a JSON plan file. Like a YAML plan it is either
+// an object with "columns", optional "defaults" and "rows", or a bare list
+// of row objects. Rows go through the same validation as YAML rows.
+func LoadCollectionJSON(path string, opts CollectionOptions) (YAMLResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return YAMLResult{}, fmt.Errorf("read file: %w", err)
+	}
+	data = bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\ufeff")))
+	if len(data) == 0 {
+		return YAMLResult{}, errors.New("plan file is empty")
+	}
+	return loadCollectionJSONData(data, opts)
+}
+
+func loadCollectionJSONData(data []byte, opts CollectionOptions) (YAMLResult, error) {
+	opts = normalizeYAMLOpts(opts)
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	if data[0] == '[' {
+		var rawRows []map[string]interface{}
+		if err := dec.Decode(&rawRows); err != nil {
+			return YAMLResult{}, fmt.Errorf("parse JSON: %w", err)
+		}
+		if len(rawRows) == 0 {
+			return YAMLResult{}, errors.New("no data rows found")
+		}
+		rows, errs := parseYAMLRows(rawRows, nil, opts)
+		if len(errs) > 0 {
+			return YAMLResult{Rows: rows}, errs
+		}
+		return YAMLResult{Rows: rows}, nil
+	}
+
+	var plan struct {
+		Columns  []string                 `json:"columns"`
+		Defaults map[string]interface{}   `json:"defaults"`
+		Rows     []map[string]interface{} `json:"rows"`
+	}
+	if err := dec.Decode(&plan); err != nil {
+		return YAMLResult{}, fmt.Errorf("parse JSON: %w", err)
+	}
+	if plan.Columns == nil && plan.Rows == nil {
+		return YAMLResult{}, errors.New(`JSON plan must be a list of rows or an object with "columns" and "rows"`)
+	}
+	for i, c := range plan.Columns {
+		plan.Columns[i] = normalizeHeader(c)
+	}
+	defaults := normalizeYAMLDefaults(plan.Defaults)
+	rows, errs := parseYAMLRows(plan.Rows, defaults, opts)
+	result := YAMLResult{Columns: plan.Columns, Defaults: defaults, Rows: rows}
+	if len(errs) > 0 {
+		return result, errs
+	}
+	return result, nil
+}
@@ -0,0 +1,141 @@
+package csvplan
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadCollectionJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		opts      CollectionOptions
+		wantRows  int
+		wantCols  []string
+		wantErr   string
+		checkRow1 func(t *testing.T, row CollectionRow)
+	}{
+		{
+			name: "structured with defaults and numbers",
+			input: `{
+  "columns": ["title", "link", "start_time", "duration"],
+  "defaults": {"start_time": "0:00", "duration": 60},
+  "rows": [
+    {"title": "Song, with \"quotes\"", "link": "https://youtu.be/a", "start_time": "1:30", "duration": 45},
+    {"title": "Defaults", "link": "https://youtu.be/b"}
+  ]
+}`,
+			wantRows: 2,
+			wantCols: []string{"title", "link", "start_time", "duration"},
+			checkRow1: func(t *testing.T, row CollectionRow) {
+				if row.Start != 90*time.Second || row.DurationSeconds != 45 {
+					t.Errorf("row 1 start = %v, duration = %d", row.Start, row.DurationSeconds)
+				}
+				if got := row.CustomFields["title"]; got != `Song, with "quotes"` {
+					t.Errorf("title = %q", got)
+				}
+			},
+		},
+		{
+			name:     "bare list with renamed headers",
+			input:    `[{"URL": "https://youtu.be/a", "from": "0:10", "extra": true}]`,
+			opts:     CollectionOptions{LinkHeader: "url", StartHeader: "from"},
+			wantRows: 1,
+			checkRow1: func(t *testing.T, row CollectionRow) {
+				if row.Link != "https://youtu.be/a" || row.StartRaw != "0:10" || row.CustomFields["extra"] != "true" {
+					t.Errorf("row = %+v", row)
+				}
+			},
+		},
+		{
+			name:     "row errors match YAML validation",
+			input:    `[{"link": "https://youtu.be/a", "start_time": "0:10", "duration": 0}, {"start_time": "0:10"}]`,
+			wantRows: 2,
+			wantErr:  "link is required",
+		},
+		{
+			name:    "syntax error",
+			input:   `{"rows": [}`,
+			wantErr: "parse JSON",
+		},
+		{
+			name:    "object without rows",
+			input:   `{"songs": []}`,
+			wantErr: `"columns" and "rows"`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "plan.json")
+			if err := os.WriteFile(path, []byte(tc.input), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			result, err := LoadCollectionStructured(path, tc.opts)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tc.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("LoadCollectionStructured: %v", err)
+			}
+			if len(result.Rows) != tc.wantRows {
+				t.Fatalf("got %d rows, want %d", len(result.Rows), tc.wantRows)
+			}
+			if tc.wantCols != nil && strings.Join(result.Columns, ",") != strings.Join(tc.wantCols, ",") {
+				t.Errorf("columns = %v, want %v", result.Columns, tc.wantCols)
+			}
+			if tc.checkRow1 != nil {
+				tc.checkRow1(t, result.Rows[0])
+			}
+		})
+	}
+}
+
+func TestWriteJSON_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "songs.json")
+	rows := []CollectionRow{{
+		Index: 1, Link: "https://youtu.be/a", StartRaw: "2:00", DurationSeconds: 60,
+		CustomFields: map[string]string{"title": "Tab\there", "link": "https://youtu.be/a", "start_time": "2:00", "duration": "60"},
+	}}
+	defaults := map[string]string{"duration": "60"}
+	if err := WriteJSON(path, []string{"title", "link", "start_time", "duration"}, defaults, rows); err != nil {
+		t.Fatalf("WriteJSON: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), `"duration": "60"`); n != 1 {
+		t.Errorf("duration matching the default written %d times, want once in defaults:\n%s", n, data)
+	}
+
+	result, err := LoadCollectionJSON(path, CollectionOptions{})
+	if err != nil {
+		t.Fatalf("LoadCollectionJSON: %v\n%s", err, data)
+	}
+	if len(result.Rows) != 1 || result.Rows[0].CustomFields["title"] != "Tab\there" || result.Rows[0].DurationSeconds != 60 {
+		t.Errorf("rows = %+v", result.Rows)
+	}
+	if result.Defaults["duration"] != "60" {
+		t.Errorf("defaults = %v", result.Defaults)
+	}
+}
+
+func TestDetectPlanFormat(t *testing.T) {
+	tests := map[string]string{
+		"songs.yaml": PlanFormatYAML,
+		"songs.YML":  PlanFormatYAML,
+		"songs.json": PlanFormatJSON,
+		"songs.xlsx": PlanFormatXLSX,
+		"songs.tsv":  PlanFormatCSV,
+		"songs":      PlanFormatCSV,
+	}
+	for path, want := range tests {
+		if got := DetectPlanFormat(path); got != want {
+			t.Errorf("DetectPlanFormat(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
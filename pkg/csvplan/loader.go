@@ -249,6 +249,12 @@ func buildHeaderMap(header []string, resolver headerResolver) (map[string]int, e
 	return headerMap, nil
 }
 
+// NormalizeHeader returns the form plan loaders compare header names in:
+// lower case, with spaces, dashes, dots and slashes as single underscores.
+func NormalizeHeader(value string) string {
+	return normalizeHeader(value)
+}
+
 func normalizeHeader(value string) string {
 	value = strings.TrimSpace(value)
 	if strings.HasPrefix(value, "\ufeff") {
@@ -4,19 +4,24 @@ import (
 	"encoding/csv"
 	"errors"
 	"fmt"
-	"os"
 	"regexp"
 	"strconv"
 	"strings"
-	"time"
 )
 
-// ImportOptions controls how ImportFromCSV parses a CSV/TSV file.
+// ImportOptions controls how ImportFromCSV parses a CSV/TSV file or an
+// .xlsx sheet.
 type ImportOptions struct {
 	LinkHeader      string // Override column name for the URL field (empty = auto-detect)
 	StartHeader     string // Override column name for the start time field (empty = auto-detect)
 	DurationHeader  string // Override column name for the duration field (empty = auto-detect)
 	DefaultDuration int    // Fallback duration in seconds (default: 60)
+	// StrictHeaders disables the heuristics: a header row is required, the
+	// link and start columns must be present under their exact (normalized)
+	// names, and missing or ambiguous columns fail instead of being guessed.
+	StrictHeaders bool
+	// Sheet picks the worksheet of an .xlsx file (empty = first).
+	Sheet string
 }
 
 var (
@@ -31,24 +36,115 @@ var (
 //   - Heuristic column override: if the mapped link column doesn't contain URLs
 //     but another column does, that column is used for link instead.
 //   - NoHeader mode: column roles are detected entirely from data patterns.
+//
+// With opts.StrictHeaders the last two are disabled; see ImportOptions.
 func ImportFromCSV(path string, opts ImportOptions) ([]CollectionRow, error) {
 	if opts.DefaultDuration <= 0 {
 		opts.DefaultDuration = 60
 	}
 
-	raw, err := os.ReadFile(path)
+	headerLine, rawRecords, err := readImportRecords(path, opts)
 	if err != nil {
-		return nil, fmt.Errorf("read file: %w", err)
+		return nil, err
+	}
+
+	// Determine column roles (link, start, duration) and output key names.
+	// Empty headerLine means the file had no header row; use pure heuristics.
+	linkCol, startCol, durationCol, colNames, err := resolveColumnRoles(headerLine, rawRecords, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build CollectionRows.
+	var (
+		rows []CollectionRow
+		errs ValidationErrors
+	)
+	for ri, rec := range rawRecords {
+		row, rowErrs := buildImportRow(rec, ri+1, linkCol, startCol, durationCol, colNames, opts.DefaultDuration)
+		errs = append(errs, rowErrs...)
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("no data rows found")
+	}
+	if len(errs) > 0 {
+		return rows, errs
+	}
+	return rows, nil
+}
+
+// ImportColumn describes how ImportFromCSV reads one column of a file.
+type ImportColumn struct {
+	Position int    `json:"position"` // 0-based
+	Header   string `json:"header"`   // normalized header; empty without a header row
+	Field    string `json:"field"`    // key the value is stored under
+	Role     string `json:"role,omitempty"`
+	// Guessed is set when the role came from the column's values rather
+	// than its header.
+	Guessed bool `json:"guessed,omitempty"`
+}
+
+// Column roles reported by ExplainImport.
+const (
+	RoleLink     = "link"
+	RoleStart    = "start"
+	RoleDuration = "duration"
+)
+
+// ExplainImport reports the column mapping ImportFromCSV would use for the
+// file, including the columns its heuristics picked.
+func ExplainImport(path string, opts ImportOptions) ([]ImportColumn, error) {
+	headerLine, records, err := readImportRecords(path, opts)
+	if err != nil {
+		return nil, err
+	}
+	linkCol, startCol, durationCol, colNames, err := resolveColumnRoles(headerLine, records, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var headers []string
+	if headerLine != "" {
+		headers = splitLine(headerLine, lineDelim(headerLine))
+	}
+	n := max(len(headers), maxCols(records))
+	columns := make([]ImportColumn, n)
+	for i := range columns {
+		col := ImportColumn{Position: i, Field: colNames[i]}
+		if i < len(headers) {
+			col.Header = normalizeHeader(headers[i])
+		}
+		switch i {
+		case linkCol:
+			col.Role = RoleLink
+		case startCol:
+			col.Role = RoleStart
+		case durationCol:
+			col.Role = RoleDuration
+		}
+		col.Guessed = col.Role != "" && col.Header != col.Field
+		columns[i] = col
+	}
+	return columns, nil
+}
+
+// readImportRecords splits a file into its header line (empty when the
+// first line looks like data) and the parsed data records.
+func readImportRecords(path string, opts ImportOptions) (string, [][]string, error) {
+	raw, err := readPlanData(path, opts.Sheet)
+	if err != nil {
+		return "", nil, err
 	}
 	if len(raw) == 0 {
-		return nil, errors.New("plan file is empty")
+		return "", nil, errors.New("plan file is empty")
 	}
 
 	content := strings.TrimPrefix(string(raw), "\ufeff") // strip UTF-8 BOM
 
 	allLines := nonEmptyLines(content)
 	if len(allLines) == 0 {
-		return nil, errors.New("plan file is empty")
+		return "", nil, errors.New("plan file is empty")
 	}
 
 	var headerLine string
@@ -59,12 +155,14 @@ func ImportFromCSV(path string, opts ImportOptions) ([]CollectionRow, error) {
 	if looksLikeHeader(allLines[0]) {
 		headerLine = allLines[0]
 		dataLines = allLines[1:]
+	} else if opts.StrictHeaders {
+		return "", nil, fmt.Errorf("strict headers: first line looks like data, not a header row: %s", strings.TrimSpace(allLines[0]))
 	} else {
 		dataLines = allLines
 	}
 
 	if len(dataLines) == 0 {
-		return nil, errors.New("no data rows found")
+		return "", nil, errors.New("no data rows found")
 	}
 
 	// Use majority vote among data lines to choose the data delimiter.
@@ -82,35 +180,16 @@ func ImportFromCSV(path string, opts ImportOptions) ([]CollectionRow, error) {
 		}
 	}
 	if len(rawRecords) == 0 {
-		return nil, errors.New("no data rows found")
+		return "", nil, errors.New("no data rows found")
 	}
 
-	// Determine column roles (link, start, duration) and output key names.
-	// Empty headerLine means the file had no header row; use pure heuristics.
-	linkCol, startCol, durationCol, colNames := resolveColumnRoles(headerLine, rawRecords, opts)
-
-	// Build CollectionRows.
-	var (
-		rows []CollectionRow
-		errs ValidationErrors
-	)
-	for ri, rec := range rawRecords {
-		row, rowErrs := buildImportRow(rec, ri+1, linkCol, startCol, durationCol, colNames, opts.DefaultDuration)
-		errs = append(errs, rowErrs...)
-		rows = append(rows, row)
-	}
-	if len(rows) == 0 {
-		return nil, errors.New("no data rows found")
-	}
-	if len(errs) > 0 {
-		return rows, errs
-	}
-	return rows, nil
+	return headerLine, rawRecords, nil
 }
 
 // resolveColumnRoles returns the column indices for link, start, and duration,
-// plus a map of col index → output field name for all other columns.
-func resolveColumnRoles(headerLine string, records [][]string, opts ImportOptions) (linkCol, startCol, durationCol int, colNames map[int]string) {
+// plus a map of col index → output field name for all other columns. It only
+// fails in strict mode.
+func resolveColumnRoles(headerLine string, records [][]string, opts ImportOptions) (linkCol, startCol, durationCol int, colNames map[int]string, err error) {
 	linkCol, startCol, durationCol = -1, -1, -1
 
 	if headerLine == "" {
@@ -152,6 +231,17 @@ func resolveColumnRoles(headerLine string, records [][]string, opts ImportOption
 		}
 	}
 
+	if opts.StrictHeaders {
+		if err = strictHeaderCheck(normHeaders, records, wantLink, wantStart, wantDuration); err != nil {
+			return
+		}
+		colNames = make(map[int]string, len(normHeaders))
+		for i, h := range normHeaders {
+			colNames[i] = h
+		}
+		return
+	}
+
 	// Heuristic override: if the mapped link col doesn't actually have URLs,
 	// scan all columns for URLs. We intentionally do not exclude durationCol
 	// here — the header column named "duration" may actually contain URLs when
@@ -200,6 +290,42 @@ func resolveColumnRoles(headerLine string, records [][]string, opts ImportOption
 	return
 }
 
+// strictHeaderCheck reports every problem with the header row at once:
+// duplicated role columns and missing link/start columns. For a missing
+// column it names the column the heuristics would have picked, so the fix
+// (rename the column or set link_header/start_header) is obvious.
+func strictHeaderCheck(headers []string, records [][]string, wantLink, wantStart, wantDuration string) error {
+	var problems []string
+	positions := make(map[string][]int, len(headers))
+	for i, h := range headers {
+		positions[h] = append(positions[h], i)
+	}
+	for _, want := range []string{wantLink, wantStart, wantDuration} {
+		if cols := positions[want]; len(cols) > 1 {
+			problems = append(problems, fmt.Sprintf("column %q appears %d times", want, len(cols)))
+		}
+	}
+	missing := func(role, want string, re *regexp.Regexp) {
+		if len(positions[want]) > 0 {
+			return
+		}
+		msg := fmt.Sprintf("missing %s column %q", role, want)
+		for i, h := range headers {
+			if h != "" && colMatchesMajority(records, i, re) {
+				msg += fmt.Sprintf(" (column %q looks like %s values)", h, role)
+				break
+			}
+		}
+		problems = append(problems, msg)
+	}
+	missing("link", wantLink, reURL)
+	missing("start", wantStart, reTimePat)
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("strict headers: %s; header row: %s", strings.Join(problems, "; "), strings.Join(headers, ", "))
+}
+
 // buildImportRow constructs a CollectionRow from a raw record given the column
 // role indices and output key names.
 func buildImportRow(rec []string, index, linkCol, startCol, durationCol int, colNames map[int]string, defaultDuration int) (CollectionRow, []ValidationError) {
@@ -217,18 +343,18 @@ func buildImportRow(rec []string, index, linkCol, startCol, durationCol int, col
 		errs = append(errs, ValidationError{Line: index, Field: "link", Message: "link is required"})
 	}
 
-	startRaw := get(startCol)
-	var startDur time.Duration
-	if startRaw == "" {
-		errs = append(errs, ValidationError{Line: index, Field: "start_time", Message: "start_time is required"})
-	} else {
-		d, err := parseStartTime(startRaw)
-		if err != nil {
-			errs = append(errs, ValidationError{Line: index, Field: "start_time", Message: err.Error()})
-		} else {
-			startDur = d
+	endRaw, windowsRaw := "", ""
+	for col, name := range colNames {
+		switch name {
+		case EndTimeField:
+			endRaw = get(col)
+		case WindowsField:
+			windowsRaw = get(col)
 		}
 	}
+	startRaw := get(startCol)
+	timing, timingErrs := parseRowTiming(startRaw, endRaw, windowsRaw, "start_time", index)
+	errs = append(errs, timingErrs...)
 
 	durationSeconds := defaultDuration
 	if durationCol >= 0 {
@@ -254,12 +380,19 @@ func buildImportRow(rec []string, index, linkCol, startCol, durationCol int, col
 	if startRaw != "" {
 		customFields["start_time"] = startRaw
 	}
+	if timing.window.StartText != "" {
+		startRaw = timing.window.StartText
+	}
+	// An end time (end_time or a start range) or windows set the duration.
+	if seconds := timing.seconds(); seconds > 0 {
+		durationSeconds = seconds
+	}
 
 	return CollectionRow{
 		Index:           index,
 		Link:            link,
 		StartRaw:        startRaw,
-		Start:           startDur,
+		Start:           timing.window.Start,
 		DurationSeconds: durationSeconds,
 		CustomFields:    customFields,
 	}, errs
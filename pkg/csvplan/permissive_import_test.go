@@ -0,0 +1,101 @@
+package csvplan
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestImportFromCSVStrictHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		opts    ImportOptions
+		wantErr string // substring; empty means success
+		link    string
+	}{
+		{
+			name:  "permissive remaps mislabeled link column",
+			input: "title,url,start_time\nSong,https://youtu.be/a,0:10\n",
+			link:  "https://youtu.be/a",
+		},
+		{
+			name:    "strict reports missing link with hint",
+			input:   "title,url,start_time\nSong,https://youtu.be/a,0:10\n",
+			opts:    ImportOptions{StrictHeaders: true},
+			wantErr: `missing link column "link" (column "url" looks like link values)`,
+		},
+		{
+			name:  "strict accepts configured headers",
+			input: "title,url,start\nSong,https://youtu.be/a,0:10\n",
+			opts:  ImportOptions{StrictHeaders: true, LinkHeader: "URL", StartHeader: "start"},
+			link:  "https://youtu.be/a",
+		},
+		{
+			name:  "strict keeps the named column even when another looks like links",
+			input: "link,source,start_time\nabc123,https://youtu.be/a,0:10\n",
+			opts:  ImportOptions{StrictHeaders: true},
+			link:  "abc123",
+		},
+		{
+			name:    "strict requires a header row",
+			input:   "Song,https://youtu.be/a,0:10\n",
+			opts:    ImportOptions{StrictHeaders: true},
+			wantErr: "not a header row",
+		},
+		{
+			name:    "strict rejects duplicate role columns",
+			input:   "link,link,start_time\nhttps://youtu.be/a,https://youtu.be/b,0:10\n",
+			opts:    ImportOptions{StrictHeaders: true},
+			wantErr: `column "link" appears 2 times`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "plan.csv")
+			if err := os.WriteFile(path, []byte(tc.input), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			rows, err := ImportFromCSV(path, tc.opts)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("err = %v, want containing %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ImportFromCSV: %v", err)
+			}
+			if len(rows) != 1 || rows[0].Link != tc.link {
+				t.Fatalf("rows = %+v, want link %q", rows, tc.link)
+			}
+		})
+	}
+}
+
+func TestExplainImport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.csv")
+	input := "title,url,begin,duration\nSong,https://youtu.be/a,0:10,45\n"
+	if err := os.WriteFile(path, []byte(input), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cols, err := ExplainImport(path, ImportOptions{})
+	if err != nil {
+		t.Fatalf("ExplainImport: %v", err)
+	}
+	want := []ImportColumn{
+		{Position: 0, Header: "title", Field: "title"},
+		{Position: 1, Header: "url", Field: "link", Role: RoleLink, Guessed: true},
+		{Position: 2, Header: "begin", Field: "start_time", Role: RoleStart, Guessed: true},
+		{Position: 3, Header: "duration", Field: "duration", Role: RoleDuration},
+	}
+	if len(cols) != len(want) {
+		t.Fatalf("got %d columns, want %d: %+v", len(cols), len(want), cols)
+	}
+	for i := range want {
+		if cols[i] != want[i] {
+			t.Errorf("column %d = %+v, want %+v", i, cols[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package csvplan
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// EndTimeField is the optional plan column holding the time a clip ends.
+// With it, or with a start value written as a range ("1:23-2:23"), the
+// clip's duration is the length of the window instead of the duration
+// column or the collection default.
+const EndTimeField = "end_time"
+
+// rangeSeparators split a start value written as a range. The dashes are
+// tried in order so a typographic dash pasted from a document works too.
+var rangeSeparators = []string{"–", "—", "-"}
+
+// SplitTimeRange splits a start value written as a range, such as
+// "1:23-2:23" or "1:23 – 2:23", into its two ends. ok is false for a plain
+// time.
+func SplitTimeRange(value string) (start, end string, ok bool) {
+	for _, sep := range rangeSeparators {
+		if s, e, found := strings.Cut(value, sep); found {
+			return strings.TrimSpace(s), strings.TrimSpace(e), true
+		}
+	}
+	return strings.TrimSpace(value), "", false
+}
+
+// TimeWindow is a row's parsed start and optional end.
+type TimeWindow struct {
+	Start     time.Duration
+	StartText string // the start without any range suffix
+	End       time.Duration
+	HasEnd    bool
+}
+
+// Seconds returns the window length in whole seconds, or 0 without an end.
+func (w TimeWindow) Seconds() int {
+	if !w.HasEnd {
+		return 0
+	}
+	return int(math.Round((w.End - w.Start).Seconds()))
+}
+
+// ParseTimeWindow parses a start value that may be a range, and the value
+// of the end_time column (empty when absent). Setting both is an error, as
+// is an end that is not at least a second after the start.
+func ParseTimeWindow(startRaw, endRaw string) (TimeWindow, error) {
+	startText, rangeEnd, isRange := SplitTimeRange(startRaw)
+	start, err := parseStartTime(startText)
+	if err != nil {
+		return TimeWindow{}, err
+	}
+	w := TimeWindow{Start: start, StartText: startText}
+
+	endText := strings.TrimSpace(endRaw)
+	switch {
+	case isRange && endText != "":
+		return w, fmt.Errorf("start time %q is a range but %s is also set; use one or the other", startRaw, EndTimeField)
+	case isRange:
+		endText = rangeEnd
+	case endText == "":
+		return w, nil
+	}
+	end, err := parseStartTime(endText)
+	if err != nil {
+		return w, fmt.Errorf("end time: %w", err)
+	}
+	w.End, w.HasEnd = end, true
+	if w.Seconds() < 1 {
+		return w, fmt.Errorf("end time %s must be at least a second after start time %s", endText, startText)
+	}
+	return w, nil
+}
+
+// parseRowWindow parses a row's start and end for the plan loaders,
+// reporting problems against the column that holds the bad value.
+func parseRowWindow(startRaw, endRaw, startField string, line int) (TimeWindow, []ValidationError) {
+	w, err := ParseTimeWindow(startRaw, endRaw)
+	if err == nil {
+		return w, nil
+	}
+	// A zero StartText means the start itself didn't parse.
+	field := startField
+	if w.StartText != "" && strings.TrimSpace(endRaw) != "" {
+		if _, _, isRange := SplitTimeRange(startRaw); !isRange {
+			field = EndTimeField
+		}
+	}
+	return w, []ValidationError{{Line: line, Field: field, Message: err.Error()}}
+}
+
+// WindowsField is the optional plan column listing several clips from one
+// source, e.g. "0:30-1:00; 2:10-2:40". Each window renders as its own
+// segment and the source is fetched once. In YAML and JSON plans it may
+// also be a list.
+const WindowsField = "windows"
+
+// ParseWindows parses a windows value: time ranges separated by ";", ",",
+// "|" or line breaks. Every window needs an end.
+func ParseWindows(value string) ([]TimeWindow, error) {
+	parts := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ';' || r == ',' || r == '|' || r == '\n'
+	})
+	var windows []TimeWindow
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if _, _, isRange := SplitTimeRange(part); !isRange {
+			return nil, fmt.Errorf("%q needs an end, e.g. 0:30-1:00", part)
+		}
+		w, err := ParseTimeWindow(part, "")
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", part, err)
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+// RowWindows returns the windows listed in a row's fields, or nil when the
+// row has none or they don't parse (the loaders report that).
+func RowWindows(fields map[string]string) []TimeWindow {
+	raw := strings.TrimSpace(fields[WindowsField])
+	if raw == "" {
+		return nil
+	}
+	windows, err := ParseWindows(raw)
+	if err != nil {
+		return nil
+	}
+	return windows
+}
+
+// rowTiming is a row's parsed start, end and windows.
+type rowTiming struct {
+	window  TimeWindow
+	windows []TimeWindow
+}
+
+// seconds returns the clip length the timing sets, or 0 to keep the
+// duration column: the windows' total, or the start-to-end window.
+func (t rowTiming) seconds() int {
+	if len(t.windows) == 0 {
+		return t.window.Seconds()
+	}
+	total := 0
+	for _, w := range t.windows {
+		total += w.Seconds()
+	}
+	return total
+}
+
+// parseRowTiming parses a row's start, end_time and windows for the plan
+// loaders. With windows the start column is optional and the first window
+// sets the row's start.
+func parseRowTiming(startRaw, endRaw, windowsRaw, startField string, line int) (rowTiming, []ValidationError) {
+	var (
+		t    rowTiming
+		errs []ValidationError
+	)
+	if strings.TrimSpace(windowsRaw) != "" {
+		windows, err := ParseWindows(windowsRaw)
+		if err != nil {
+			errs = append(errs, ValidationError{Line: line, Field: WindowsField, Message: err.Error()})
+		} else if len(windows) > 0 {
+			t.windows = windows
+			t.window = windows[0]
+			return t, errs
+		}
+	}
+	if startRaw == "" {
+		if len(errs) == 0 {
+			errs = append(errs, ValidationError{Line: line, Field: startField, Message: fmt.Sprintf("%s is required", startField)})
+		}
+		return t, errs
+	}
+	window, windowErrs := parseRowWindow(startRaw, endRaw, startField, line)
+	t.window = window
+	return t, append(errs, windowErrs...)
+}
@@ -0,0 +1,155 @@
+package csvplan
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTimeWindow(t *testing.T) {
+	tests := []struct {
+		start, end string
+		wantStart  time.Duration
+		wantText   string
+		wantSecs   int
+		wantErr    string
+	}{
+		{start: "1:23", wantStart: 83 * time.Second, wantText: "1:23"},
+		{start: "1:23-2:23", wantStart: 83 * time.Second, wantText: "1:23", wantSecs: 60},
+		{start: "1:23 – 1:53", wantStart: 83 * time.Second, wantText: "1:23", wantSecs: 30},
+		{start: "1:23", end: "1:38", wantStart: 83 * time.Second, wantText: "1:23", wantSecs: 15},
+		{start: "1:23", end: "1:23", wantErr: "at least a second after"},
+		{start: "2:00-1:00", wantErr: "at least a second after"},
+		{start: "1:00-2:00", end: "2:00", wantErr: "also set"},
+		{start: "1:00", end: "soon", wantErr: "end time"},
+		{start: "bad-2:00", wantErr: "invalid"},
+	}
+	for _, tc := range tests {
+		w, err := ParseTimeWindow(tc.start, tc.end)
+		if tc.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("ParseTimeWindow(%q, %q) err = %v, want %q", tc.start, tc.end, err, tc.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseTimeWindow(%q, %q): %v", tc.start, tc.end, err)
+			continue
+		}
+		if w.Start != tc.wantStart || w.StartText != tc.wantText || w.Seconds() != tc.wantSecs {
+			t.Errorf("ParseTimeWindow(%q, %q) = %+v (%ds), want start %s %q, %ds", tc.start, tc.end, w, w.Seconds(), tc.wantStart, tc.wantText, tc.wantSecs)
+		}
+	}
+}
+
+func TestLoadCollectionTimeWindows(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "songs.csv")
+	csvData := "link,start_time,end_time,duration\n" +
+		"https://youtu.be/a,1:23-2:23,,\n" +
+		"https://youtu.be/b,0:30,0:45,90\n" +
+		"https://youtu.be/c,0:30,,40\n"
+	if err := os.WriteFile(csvPath, []byte(csvData), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := LoadCollection(csvPath, CollectionOptions{DurationHeader: "duration"})
+	if err != nil {
+		t.Fatalf("LoadCollection: %v", err)
+	}
+	wantSecs := []int{60, 15, 40}
+	for i, row := range rows {
+		if row.DurationSeconds != wantSecs[i] {
+			t.Errorf("row %d duration = %d, want %d", i+1, row.DurationSeconds, wantSecs[i])
+		}
+	}
+	if rows[0].StartRaw != "1:23" || rows[0].Start != 83*time.Second || rows[0].CustomFields["start_time"] != "1:23-2:23" {
+		t.Errorf("row 1 = %+v, want start 1:23 with the range kept in the plan", rows[0])
+	}
+
+	yamlPath := filepath.Join(dir, "songs.yaml")
+	yamlData := "- link: https://youtu.be/a\n  start_time: \"1:00\"\n  end_time: \"1:20\"\n" +
+		"- link: https://youtu.be/b\n  start_time: \"1:00\"\n  end_time: \"0:50\"\n"
+	if err := os.WriteFile(yamlPath, []byte(yamlData), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	res, err := LoadCollectionYAML(yamlPath, CollectionOptions{})
+	errs, ok := err.(ValidationErrors)
+	if !ok || len(errs) != 1 || errs[0].Field != EndTimeField {
+		t.Fatalf("YAML err = %v, want one end_time error", err)
+	}
+	if res.Rows[0].DurationSeconds != 20 {
+		t.Errorf("YAML row 1 duration = %d, want 20", res.Rows[0].DurationSeconds)
+	}
+}
+
+func TestParseWindows(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []int // window lengths
+		wantErr string
+	}{
+		{value: "0:30-1:00; 2:10-2:40", want: []int{30, 30}},
+		{value: "0:30-1:00, 2:10-2:20 | 3:00-3:05", want: []int{30, 10, 5}},
+		{value: " ; ", want: nil},
+		{value: "0:30-1:00; 2:10", wantErr: "needs an end"},
+		{value: "1:00-0:30", wantErr: "at least a second after"},
+	}
+	for _, tc := range tests {
+		windows, err := ParseWindows(tc.value)
+		if tc.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("ParseWindows(%q) err = %v, want %q", tc.value, err, tc.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseWindows(%q): %v", tc.value, err)
+			continue
+		}
+		var got []int
+		for _, w := range windows {
+			got = append(got, w.Seconds())
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("ParseWindows(%q) lengths = %v, want %v", tc.value, got, tc.want)
+		}
+	}
+}
+
+func TestLoadCollectionWindows(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "songs.csv")
+	csvData := "link,start_time,windows\n" +
+		"https://youtu.be/a,,0:30-1:00; 2:10-2:40\n" +
+		"https://youtu.be/b,0:10,0:30\n"
+	if err := os.WriteFile(csvPath, []byte(csvData), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := LoadCollection(csvPath, CollectionOptions{})
+	errs, ok := err.(ValidationErrors)
+	if !ok || len(errs) != 1 || errs[0].Line != 3 || errs[0].Field != WindowsField {
+		t.Fatalf("err = %v, want one windows error on line 3", err)
+	}
+	if rows[0].StartRaw != "0:30" || rows[0].DurationSeconds != 60 {
+		t.Errorf("row 1 = %+v, want start 0:30 and the windows' 60s total", rows[0])
+	}
+
+	yamlPath := filepath.Join(dir, "songs.yaml")
+	yamlData := "- link: https://youtu.be/a\n  windows: [\"0:30-1:00\", \"2:10-2:20\"]\n"
+	if err := os.WriteFile(yamlPath, []byte(yamlData), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	res, err := LoadCollectionYAML(yamlPath, CollectionOptions{})
+	if err != nil {
+		t.Fatalf("LoadCollectionYAML: %v", err)
+	}
+	if got := res.Rows[0].CustomFields[WindowsField]; got != "0:30-1:00; 2:10-2:20" {
+		t.Errorf("windows field = %q", got)
+	}
+	if res.Rows[0].DurationSeconds != 40 || len(RowWindows(res.Rows[0].CustomFields)) != 2 {
+		t.Errorf("YAML row = %+v, want two windows totalling 40s", res.Rows[0])
+	}
+}
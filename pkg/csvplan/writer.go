@@ -2,6 +2,7 @@ package csvplan
 
 import (
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -72,6 +73,34 @@ func WriteCSV(path string, headers []string, rows []CollectionRow, delimiter run
 // defaults, and rows. Columns are merged with any new fields discovered in the
 // row data or defaults.
 func WriteYAML(path string, columns []string, defaults map[string]string, rows []CollectionRow) error {
+	data, err := yaml.Marshal(newStructuredPlan(columns, defaults, rows))
+	if err != nil {
+		return fmt.Errorf("marshal yaml: %w", err)
+	}
+	return writePlanAtomic(path, ".yamlplan-*.tmp", data)
+}
+
+// WriteJSON writes collection rows back to a JSON plan file using atomic
+// write. The document has the same columns/defaults/rows shape as WriteYAML.
+func WriteJSON(path string, columns []string, defaults map[string]string, rows []CollectionRow) error {
+	data, err := json.MarshalIndent(newStructuredPlan(columns, defaults, rows), "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal json: %w", err)
+	}
+	return writePlanAtomic(path, ".jsonplan-*.tmp", append(data, '\n'))
+}
+
+// structuredPlan is the columns/defaults/rows document written for YAML and
+// JSON plans.
+type structuredPlan struct {
+	Columns  []string                 `yaml:"columns" json:"columns"`
+	Defaults map[string]string        `yaml:"defaults,omitempty" json:"defaults,omitempty"`
+	Rows     []map[string]interface{} `yaml:"rows" json:"rows"`
+}
+
+// newStructuredPlan merges new fields into columns and drops row values
+// that are empty or equal to the schema default.
+func newStructuredPlan(columns []string, defaults map[string]string, rows []CollectionRow) structuredPlan {
 	columns = mergeYAMLHeaders(columns, defaults, rows)
 
 	entries := make([]map[string]interface{}, 0, len(rows))
@@ -88,24 +117,12 @@ func WriteYAML(path string, columns []string, defaults map[string]string, rows [
 		}
 		entries = append(entries, entry)
 	}
+	return structuredPlan{Columns: columns, Defaults: defaults, Rows: entries}
+}
 
-	plan := struct {
-		Columns  []string                 `yaml:"columns"`
-		Defaults map[string]string        `yaml:"defaults,omitempty"`
-		Rows     []map[string]interface{} `yaml:"rows"`
-	}{
-		Columns:  columns,
-		Defaults: defaults,
-		Rows:     entries,
-	}
-
-	data, err := yaml.Marshal(plan)
-	if err != nil {
-		return fmt.Errorf("marshal yaml: %w", err)
-	}
-
+func writePlanAtomic(path, pattern string, data []byte) error {
 	dir := filepath.Dir(path)
-	tmp, err := os.CreateTemp(dir, ".yamlplan-*.tmp")
+	tmp, err := os.CreateTemp(dir, pattern)
 	if err != nil {
 		return fmt.Errorf("create temp file: %w", err)
 	}
@@ -114,7 +131,7 @@ func WriteYAML(path string, columns []string, defaults map[string]string, rows [
 	if _, err := tmp.Write(data); err != nil {
 		tmp.Close()
 		os.Remove(tmpPath)
-		return fmt.Errorf("write yaml: %w", err)
+		return fmt.Errorf("write plan: %w", err)
 	}
 
 	if err := tmp.Close(); err != nil {
@@ -0,0 +1,445 @@
+package csvplan
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IsSpreadsheet reports whether path names an Excel workbook (.xlsx), which
+// the plan loaders read one sheet of instead of parsing as text.
+func IsSpreadsheet(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".xlsx")
+}
+
+// readPlanData returns a plan file's contents as delimited text. Workbooks
+// are converted to TSV, one line per sheet row, so the CSV loaders and the
+// permissive importer read them unchanged.
+func readPlanData(planPath, sheet string) ([]byte, error) {
+	switch strings.ToLower(filepath.Ext(planPath)) {
+	case ".xlsx":
+		records, err := ReadXLSX(planPath, sheet)
+		if err != nil {
+			return nil, err
+		}
+		return recordsToTSV(records)
+	case ".numbers":
+		return nil, errors.New("can't read Apple Numbers files; export the sheet as .xlsx or .csv (File > Export To)")
+	case ".xls":
+		return nil, errors.New("legacy .xls workbooks aren't supported; save the sheet as .xlsx or .csv")
+	}
+	data, err := os.ReadFile(planPath)
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+	return data, nil
+}
+
+// recordsToTSV writes records as TSV. Tabs and line breaks inside cells
+// become spaces so every record stays on one line.
+func recordsToTSV(records [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = '\t'
+	clean := strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ", "\t", " ")
+	for _, record := range records {
+		out := make([]string, len(record))
+		for i, v := range record {
+			out[i] = clean.Replace(v)
+		}
+		if err := w.Write(out); err != nil {
+			return nil, fmt.Errorf("convert sheet: %w", err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("convert sheet: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ReadXLSX returns the cells of one worksheet as text, row by row. sheet
+// picks the worksheet by name (case-insensitive); empty means the first.
+// Empty rows up to the last used row are kept, so record i is sheet row
+// i+1. Cells are rendered the way the spreadsheet shows them where that
+// matters for plans: times formatted h:mm or m:ss come back as "1:30", not
+// as fractions of a day.
+func ReadXLSX(xlsxPath, sheet string) ([][]string, error) {
+	zr, err := zip.OpenReader(xlsxPath)
+	if err != nil {
+		return nil, fmt.Errorf("open workbook: %w", err)
+	}
+	defer zr.Close()
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[strings.TrimPrefix(f.Name, "/")] = f
+	}
+
+	sheetPath, err := findXLSXSheet(files, sheet)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := readXLSXSharedStrings(files)
+	if err != nil {
+		return nil, err
+	}
+	formats, err := readXLSXStyles(files)
+	if err != nil {
+		return nil, err
+	}
+
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("workbook is missing %s", sheetPath)
+	}
+	var ws xlsxWorksheet
+	if err := decodeXLSXPart(f, &ws); err != nil {
+		return nil, err
+	}
+
+	var records [][]string
+	next := 1
+	for _, row := range ws.Rows {
+		rowNum := row.R
+		if rowNum <= 0 {
+			rowNum = next
+		}
+		next = rowNum + 1
+
+		var record []string
+		col := 0
+		for _, c := range row.Cells {
+			if idx, ok := xlsxColumnIndex(c.R); ok {
+				col = idx
+			}
+			value := c.text(shared, formats)
+			if value != "" {
+				for len(record) < col {
+					record = append(record, "")
+				}
+				record = append(record, value)
+			}
+			col++
+		}
+		if len(record) == 0 {
+			continue
+		}
+		for len(records) < rowNum-1 {
+			records = append(records, nil)
+		}
+		records = append(records, record)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("plan file is empty")
+	}
+	return records, nil
+}
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxRichText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (r xlsxRichText) String() string {
+	if len(r.Runs) == 0 {
+		return r.T
+	}
+	var b strings.Builder
+	for _, run := range r.Runs {
+		b.WriteString(run.T)
+	}
+	return b.String()
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		R     int        `xml:"r,attr"`
+		Cells []xlsxCell `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+type xlsxCell struct {
+	R      string        `xml:"r,attr"`
+	T      string        `xml:"t,attr"`
+	S      int           `xml:"s,attr"`
+	V      string        `xml:"v"`
+	Inline *xlsxRichText `xml:"is"`
+}
+
+// text renders the cell: shared and inline strings as stored, booleans as
+// TRUE/FALSE, and numbers through their number format.
+func (c xlsxCell) text(shared []string, formats []string) string {
+	switch c.T {
+	case "s":
+		i, err := strconv.Atoi(strings.TrimSpace(c.V))
+		if err != nil || i < 0 || i >= len(shared) {
+			return ""
+		}
+		return shared[i]
+	case "inlineStr":
+		if c.Inline == nil {
+			return ""
+		}
+		return c.Inline.String()
+	case "b":
+		if c.V == "1" {
+			return "TRUE"
+		}
+		return "FALSE"
+	case "str", "e", "d":
+		return c.V
+	}
+	v := strings.TrimSpace(c.V)
+	if v == "" {
+		return ""
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return v
+	}
+	format := ""
+	if c.S >= 0 && c.S < len(formats) {
+		format = formats[c.S]
+	}
+	return formatXLSXNumber(f, format)
+}
+
+// findXLSXSheet resolves the worksheet part for sheet through the workbook
+// relationships.
+func findXLSXSheet(files map[string]*zip.File, sheet string) (string, error) {
+	var wb xlsxWorkbook
+	if err := decodeXLSXPart(files["xl/workbook.xml"], &wb); err != nil {
+		return "", err
+	}
+	if len(wb.Sheets) == 0 {
+		return "", errors.New("workbook has no sheets")
+	}
+	chosen := -1
+	if strings.TrimSpace(sheet) == "" {
+		chosen = 0
+	} else {
+		for i, s := range wb.Sheets {
+			if strings.EqualFold(strings.TrimSpace(s.Name), strings.TrimSpace(sheet)) {
+				chosen = i
+				break
+			}
+		}
+	}
+	if chosen < 0 {
+		names := make([]string, len(wb.Sheets))
+		for i, s := range wb.Sheets {
+			names[i] = fmt.Sprintf("%q", s.Name)
+		}
+		return "", fmt.Errorf("workbook has no sheet %q (sheets: %s)", sheet, strings.Join(names, ", "))
+	}
+
+	var rels xlsxRelationships
+	if err := decodeXLSXPart(files["xl/_rels/workbook.xml.rels"], &rels); err != nil {
+		return "", err
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID != wb.Sheets[chosen].RID {
+			continue
+		}
+		if target, ok := strings.CutPrefix(rel.Target, "/"); ok {
+			return target, nil
+		}
+		return path.Clean(path.Join("xl", rel.Target)), nil
+	}
+	return "", fmt.Errorf("workbook sheet %q has no worksheet part", wb.Sheets[chosen].Name)
+}
+
+func readXLSXSharedStrings(files map[string]*zip.File) ([]string, error) {
+	f, ok := files["xl/sharedStrings.xml"]
+	if !ok {
+		return nil, nil
+	}
+	var sst struct {
+		Items []xlsxRichText `xml:"si"`
+	}
+	if err := decodeXLSXPart(f, &sst); err != nil {
+		return nil, err
+	}
+	out := make([]string, len(sst.Items))
+	for i, item := range sst.Items {
+		out[i] = item.String()
+	}
+	return out, nil
+}
+
+// readXLSXStyles returns the number format code of each cell style, indexed
+// like a cell's s attribute. Built-in formats map to their codes.
+func readXLSXStyles(files map[string]*zip.File) ([]string, error) {
+	f, ok := files["xl/styles.xml"]
+	if !ok {
+		return nil, nil
+	}
+	var styles struct {
+		NumFmts []struct {
+			ID   int    `xml:"numFmtId,attr"`
+			Code string `xml:"formatCode,attr"`
+		} `xml:"numFmts>numFmt"`
+		CellXfs []struct {
+			NumFmtID int `xml:"numFmtId,attr"`
+		} `xml:"cellXfs>xf"`
+	}
+	if err := decodeXLSXPart(f, &styles); err != nil {
+		return nil, err
+	}
+	custom := make(map[int]string, len(styles.NumFmts))
+	for _, nf := range styles.NumFmts {
+		custom[nf.ID] = nf.Code
+	}
+	out := make([]string, len(styles.CellXfs))
+	for i, xf := range styles.CellXfs {
+		if code, ok := custom[xf.NumFmtID]; ok {
+			out[i] = code
+		} else {
+			out[i] = builtinXLSXFormats[xf.NumFmtID]
+		}
+	}
+	return out, nil
+}
+
+// builtinXLSXFormats holds the built-in date and time formats; the other
+// built-ins render like plain numbers.
+var builtinXLSXFormats = map[int]string{
+	14: "mm-dd-yy",
+	15: "d-mmm-yy",
+	16: "d-mmm",
+	17: "mmm-yy",
+	18: "h:mm AM/PM",
+	19: "h:mm:ss AM/PM",
+	20: "h:mm",
+	21: "h:mm:ss",
+	22: "m/d/yy h:mm",
+	45: "mm:ss",
+	46: "[h]:mm:ss",
+	47: "mmss.0",
+}
+
+// formatXLSXNumber renders a numeric cell. Date formats become ISO dates;
+// time formats keep the fields the format shows, so a start time typed as
+// 1:30 (which Excel stores as 1 h 30 min) reads back as "1:30".
+func formatXLSXNumber(v float64, format string) string {
+	code := strings.ToLower(stripXLSXFormatLiterals(format))
+	hasHour := strings.Contains(code, "h")
+	hasSecond := strings.Contains(code, "s")
+	hasDate := strings.ContainsAny(code, "yd") || (strings.Contains(code, "m") && !hasHour && !hasSecond)
+	switch {
+	case hasDate:
+		days := math.Floor(v)
+		date := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC).AddDate(0, 0, int(days))
+		if !hasHour {
+			return date.Format("2006-01-02")
+		}
+		return date.Format("2006-01-02") + " " + formatXLSXClock(v-days, true, hasSecond)
+	case hasHour || hasSecond:
+		return formatXLSXClock(v, hasHour, hasSecond)
+	}
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(v, 'g', 15, 64), 64)
+	return strconv.FormatFloat(rounded, 'f', -1, 64)
+}
+
+// formatXLSXClock renders a fraction of a day as h:mm[:ss], or m:ss when
+// the format has no hours.
+func formatXLSXClock(days float64, hasHour, hasSecond bool) string {
+	total := int(math.Round(days * 86400))
+	h, m, s := total/3600, total%3600/60, total%60
+	switch {
+	case hasHour && hasSecond:
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	case hasHour:
+		return fmt.Sprintf("%d:%02d", h, m)
+	default:
+		return fmt.Sprintf("%d:%02d", total/60, s)
+	}
+}
+
+// stripXLSXFormatLiterals drops quoted text, escaped characters and
+// bracketed sections other than elapsed-time markers from a format code.
+func stripXLSXFormatLiterals(code string) string {
+	var b strings.Builder
+	for i := 0; i < len(code); i++ {
+		switch ch := code[i]; ch {
+		case '"':
+			for i++; i < len(code) && code[i] != '"'; i++ {
+			}
+		case '\\', '_', '*':
+			i++
+		case '[':
+			end := strings.IndexByte(code[i:], ']')
+			if end < 0 {
+				return b.String()
+			}
+			inner := strings.ToLower(code[i+1 : i+end])
+			if strings.Trim(inner, "hms") == "" {
+				b.WriteString(inner)
+			}
+			i += end
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return b.String()
+}
+
+// xlsxColumnIndex returns the 0-based column of a cell reference like "C5".
+func xlsxColumnIndex(ref string) (int, bool) {
+	col := 0
+	n := 0
+	for _, r := range strings.ToUpper(ref) {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+		n++
+	}
+	if n == 0 {
+		return 0, false
+	}
+	return col - 1, true
+}
+
+func decodeXLSXPart(f *zip.File, v any) error {
+	if f == nil {
+		return errors.New("not an .xlsx workbook (missing xl/workbook.xml)")
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("read %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(io.LimitReader(rc, 256<<20)).Decode(v); err != nil {
+		return fmt.Errorf("parse %s: %w", f.Name, err)
+	}
+	return nil
+}
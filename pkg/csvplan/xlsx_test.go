@@ -0,0 +1,162 @@
+package csvplan
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeTestWorkbook writes a minimal .xlsx with two sheets: "Songs" (shared
+// and inline strings, an h:mm start time, a gap column and a blank row) and
+// "Extras".
+func writeTestWorkbook(t *testing.T) string {
+	t.Helper()
+	parts := map[string]string{
+		"xl/workbook.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Songs" sheetId="1" r:id="rId1"/><sheet name="Extras" sheetId="2" r:id="rId2"/></sheets>
+</workbook>`,
+		"xl/_rels/workbook.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="/xl/worksheets/sheet2.xml"/>
+</Relationships>`,
+		"xl/sharedStrings.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<si><t>Title</t></si><si><t>Link</t></si><si><t>Start Time</t></si><si><t>Duration</t></si>
+<si><r><t>Hey </t></r><r><t>Ya</t></r></si><si><t>https://youtu.be/a</t></si>
+</sst>`,
+		"xl/styles.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<numFmts><numFmt numFmtId="164" formatCode="m:ss"/></numFmts>
+<cellXfs><xf numFmtId="0"/><xf numFmtId="20"/><xf numFmtId="164"/></cellXfs>
+</styleSheet>`,
+		"xl/worksheets/sheet1.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="s"><v>2</v></c><c r="E1" t="s"><v>3</v></c></row>
+<row r="2"><c r="A2" t="s"><v>4</v></c><c r="B2" t="s"><v>5</v></c><c r="C2" s="1"><v>0.0625</v></c><c r="E2"><v>45</v></c></row>
+<row r="4"><c r="A4" t="inlineStr"><is><t>Second</t></is></c><c r="B4" t="inlineStr"><is><t>https://youtu.be/b</t></is></c><c r="C4" s="2"><v>0.000694444444444444</v></c></row>
+</sheetData></worksheet>`,
+		"xl/worksheets/sheet2.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="inlineStr"><is><t>link</t></is></c><c r="B1" t="inlineStr"><is><t>start_time</t></is></c></row>
+<row r="2"><c r="A2" t="inlineStr"><is><t>https://youtu.be/c</t></is></c><c r="B2" t="inlineStr"><is><t>0:10</t></is></c></row>
+</sheetData></worksheet>`,
+	}
+	path := filepath.Join(t.TempDir(), "plan.xlsx")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for name, body := range parts {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadXLSX(t *testing.T) {
+	path := writeTestWorkbook(t)
+
+	records, err := ReadXLSX(path, "")
+	if err != nil {
+		t.Fatalf("ReadXLSX: %v", err)
+	}
+	want := [][]string{
+		{"Title", "Link", "Start Time", "", "Duration"},
+		{"Hey Ya", "https://youtu.be/a", "1:30", "", "45"},
+		nil,
+		{"Second", "https://youtu.be/b", "1:00"},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("records = %q\nwant %q", records, want)
+	}
+
+	records, err = ReadXLSX(path, "extras")
+	if err != nil {
+		t.Fatalf("ReadXLSX extras: %v", err)
+	}
+	if len(records) != 2 || records[1][0] != "https://youtu.be/c" {
+		t.Errorf("extras records = %q", records)
+	}
+
+	if _, err := ReadXLSX(path, "Missing"); err == nil || !strings.Contains(err.Error(), `"Songs", "Extras"`) {
+		t.Errorf("missing sheet error = %v, want the sheet names", err)
+	}
+}
+
+func TestLoadCollectionXLSX(t *testing.T) {
+	path := writeTestWorkbook(t)
+
+	rows, err := LoadCollection(path, CollectionOptions{DurationHeader: "duration"})
+	if err != nil {
+		t.Fatalf("LoadCollection: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(rows))
+	}
+	if rows[0].Link != "https://youtu.be/a" || rows[0].StartRaw != "1:30" || rows[0].DurationSeconds != 45 {
+		t.Errorf("row 1 = %+v", rows[0])
+	}
+	if rows[0].CustomFields["title"] != "Hey Ya" {
+		t.Errorf("title = %q", rows[0].CustomFields["title"])
+	}
+	if rows[1].DurationSeconds != 60 {
+		t.Errorf("row 2 duration = %d, want the default", rows[1].DurationSeconds)
+	}
+
+	imported, err := ImportFromCSV(path, ImportOptions{Sheet: "Extras"})
+	if err != nil {
+		t.Fatalf("ImportFromCSV: %v", err)
+	}
+	if len(imported) != 1 || imported[0].Link != "https://youtu.be/c" {
+		t.Errorf("imported = %+v", imported)
+	}
+}
+
+func TestFormatXLSXNumber(t *testing.T) {
+	tests := []struct {
+		value  float64
+		format string
+		want   string
+	}{
+		{45, "", "45"},
+		{0.1 + 0.2, "General", "0.3"},
+		{0.0625, "h:mm", "1:30"},
+		{0.0625, "[h]:mm:ss", "1:30:00"},
+		{0.00104166666666667, "mm:ss", "1:30"},
+		{0.00104166666666667, `[$-409]m:ss;@`, "1:30"},
+		{45292, "yyyy-mm-dd", "2024-01-01"},
+		{12, `0 "sec"`, "12"},
+	}
+	for _, tc := range tests {
+		if got := formatXLSXNumber(tc.value, tc.format); got != tc.want {
+			t.Errorf("formatXLSXNumber(%v, %q) = %q, want %q", tc.value, tc.format, got, tc.want)
+		}
+	}
+}
+
+func TestReadPlanDataRejectsNumbers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.numbers")
+	if err := os.WriteFile(path, []byte("PK"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCollection(path, CollectionOptions{}); err == nil || !strings.Contains(err.Error(), "export") {
+		t.Errorf("err = %v, want an export hint", err)
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"os"
 	"strconv"
 	"strings"
-	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -184,20 +183,10 @@ func parseYAMLRow(raw map[string]interface{}, defaults map[string]string, index
 	}
 
 	startRaw := strings.TrimSpace(fields[opts.StartHeader])
-	var startDur time.Duration
-	if startRaw == "" {
-		errs = append(errs, ValidationError{
-			Line:    index,
-			Field:   opts.StartHeader,
-			Message: fmt.Sprintf("%s is required", opts.StartHeader),
-		})
-	} else {
-		d, err := parseStartTime(startRaw)
-		if err != nil {
-			errs = append(errs, ValidationError{Line: index, Field: opts.StartHeader, Message: err.Error()})
-		} else {
-			startDur = d
-		}
+	timing, timingErrs := parseRowTiming(startRaw, fields[EndTimeField], fields[WindowsField], opts.StartHeader, index)
+	errs = append(errs, timingErrs...)
+	if timing.window.StartText != "" {
+		startRaw = timing.window.StartText
 	}
 
 	durationSeconds := opts.DefaultDuration
@@ -220,6 +209,11 @@ func parseYAMLRow(raw map[string]interface{}, defaults map[string]string, index
 		}
 	}
 
+	// An end time (end_time or a start range) or windows set the duration.
+	if seconds := timing.seconds(); seconds > 0 {
+		durationSeconds = seconds
+	}
+
 	if durationSeconds <= 0 {
 		errs = append(errs, ValidationError{
 			Line:    index,
@@ -240,7 +234,7 @@ func parseYAMLRow(raw map[string]interface{}, defaults map[string]string, index
 		Index:           index,
 		Link:            link,
 		StartRaw:        startRaw,
-		Start:           startDur,
+		Start:           timing.window.Start,
 		DurationSeconds: durationSeconds,
 		CustomFields:    customFields,
 	}, errs
@@ -267,6 +261,13 @@ func yamlScalarToString(v interface{}) string {
 			return "true"
 		}
 		return "false"
+	case []interface{}:
+		// A list, such as windows: [0:30-1:00, 2:10-2:40], joins with "; ".
+		items := make([]string, len(val))
+		for i, item := range val {
+			items[i] = yamlScalarToString(item)
+		}
+		return strings.Join(items, "; ")
 	default:
 		return fmt.Sprintf("%v", val)
 	}
@@ -0,0 +1,101 @@
+package powerhour
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/logx"
+	"powerhour/internal/project"
+)
+
+// FetchOptions tune Fetch.
+type FetchOptions struct {
+	// Force re-downloads sources that are already cached.
+	Force bool
+	// NoDownload only matches existing files; nothing is downloaded.
+	NoDownload bool
+	// Reprobe refreshes the stored ffprobe metadata.
+	Reprobe bool
+	// Log, when set, also receives yt-dlp and ffprobe output. Everything is
+	// written to the project's logs either way.
+	Log io.Writer
+}
+
+// FetchResult is the outcome for one plan row.
+type FetchResult struct {
+	Collection string
+	Index      int
+	Title      string
+	Link       string
+	Status     string // cached, downloaded, matched, missing, or error
+	CachedPath string
+	Err        error
+}
+
+// Fetch downloads or matches the sources of the selected rows, skipping
+// rows marked skip, and saves the cache index. A row that fails is reported
+// in its result; the returned error is for failures that stop the whole run.
+// yt-dlp and ffmpeg are located or installed first, as the CLI does.
+func (p *Project) Fetch(ctx context.Context, sel Selection, opts FetchOptions) ([]FetchResult, error) {
+	collections, err := p.selected(sel)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.paths.EnsureMetaDirs(); err != nil {
+		return nil, err
+	}
+	if err := p.paths.EnsureCollectionDirs(p.cfg); err != nil {
+		return nil, err
+	}
+	idx, err := cache.Load(p.paths)
+	if err != nil {
+		return nil, err
+	}
+
+	logger, closer, err := logx.Open(p.paths)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	svc, err := cache.NewService(ctx, p.paths, logx.PrintLogger(logger), nil)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Log != nil {
+		svc.SetLogOutput(opts.Log)
+	}
+
+	resolveOpts := cache.ResolveOptions{Force: opts.Force, Reprobe: opts.Reprobe, NoDownload: opts.NoDownload}
+	rows := project.FlattenCollections(collections)
+	results := make([]FetchResult, 0, len(rows))
+	dirty := false
+	for _, collRow := range rows {
+		if ctx.Err() != nil {
+			break
+		}
+		row := collRow.Row
+		res := FetchResult{Collection: collRow.CollectionName, Index: row.Index, Title: row.Title, Link: row.Link}
+		rowOpts := resolveOpts
+		rowOpts.Collection = collRow.CollectionName
+		resolved, err := svc.Resolve(ctx, idx, row, rowOpts)
+		if err != nil {
+			logger.Error("fetch row failed", "collection", collRow.CollectionName, "row", row.Index, "error", err)
+			res.Status, res.Err = "error", err
+			results = append(results, res)
+			continue
+		}
+		res.Status = string(resolved.Status)
+		res.CachedPath = resolved.Entry.CachedPath
+		dirty = dirty || resolved.Updated
+		results = append(results, res)
+	}
+
+	if dirty {
+		if err := cache.Save(p.paths, idx); err != nil {
+			return results, fmt.Errorf("save cache index: %w", err)
+		}
+	}
+	return results, ctx.Err()
+}
@@ -0,0 +1,196 @@
+// Package powerhour is the supported Go API for embedding the generator.
+// It wraps the same config loading, plan parsing, timeline resolution,
+// fetch and render steps the powerhour CLI runs, without its terminal
+// output, so other Go programs can drive a project directly.
+//
+//	p, err := powerhour.Open("party")
+//	if err != nil {
+//		return err
+//	}
+//	if _, err := p.Fetch(ctx, powerhour.Selection{}, powerhour.FetchOptions{}); err != nil {
+//		return err
+//	}
+//	results, err := p.Render(ctx, powerhour.Selection{}, powerhour.RenderOptions{})
+//
+// Types are aliases of the internal ones, so values pass straight through
+// and fields read the same as in the rest of the code base.
+package powerhour
+
+import (
+	"fmt"
+	"slices"
+
+	"powerhour/internal/config"
+	"powerhour/internal/paths"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+	"powerhour/pkg/csvplan"
+)
+
+type (
+	// Config is a parsed powerhour.yaml.
+	Config = config.Config
+	// Paths are a project's resolved directories and files.
+	Paths = paths.ProjectPaths
+	// Collection is a loaded collection: its config, plan rows and
+	// overrides.
+	Collection = project.Collection
+	// Row is one plan row.
+	Row = csvplan.CollectionRow
+	// Segment is a clip ready to render.
+	Segment = render.Segment
+	// RenderResult is the outcome for one segment.
+	RenderResult = render.Result
+	// ProgressReporter receives per-segment render progress.
+	ProgressReporter = render.ProgressReporter
+)
+
+// Project is an opened powerhour project. Its collections are loaded once
+// by Open; call Reload after changing plan or config files.
+type Project struct {
+	paths       Paths
+	cfg         Config
+	resolver    *project.CollectionResolver
+	collections map[string]Collection
+}
+
+// Open loads the project in dir ("" for the nearest project at or above the
+// working directory): its config, library paths and collection plans.
+func Open(dir string) (*Project, error) {
+	pp, err := paths.Resolve(dir)
+	if err != nil {
+		return nil, err
+	}
+	p := &Project{paths: pp}
+	if err := p.Reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// LoadConfig parses a powerhour.yaml without opening a project.
+func LoadConfig(path string) (Config, error) {
+	return config.Load(path)
+}
+
+// LoadPlan parses a CSV/TSV, YAML, JSON or .xlsx plan file with the default
+// column names.
+func LoadPlan(path string) ([]Row, error) {
+	opts := project.CollectionOptionsForConfig(Collection{})
+	if csvplan.IsStructuredPlan(path) {
+		result, err := csvplan.LoadCollectionStructured(path, opts)
+		return result.Rows, err
+	}
+	return csvplan.LoadCollection(path, opts)
+}
+
+// Reload re-reads the config and every collection plan.
+func (p *Project) Reload() error {
+	cfg, err := config.Load(p.paths.ConfigFile)
+	if err != nil {
+		return err
+	}
+	pp := paths.ApplyConfig(p.paths, cfg)
+	pp = paths.ApplyLibrary(pp, cfg.LibraryShared(), cfg.LibraryPath())
+	resolver, err := project.NewCollectionResolver(cfg, pp)
+	if err != nil {
+		return err
+	}
+	collections, err := resolver.LoadCollections()
+	if err != nil {
+		return err
+	}
+	p.paths, p.cfg, p.resolver, p.collections = pp, cfg, resolver, collections
+	return nil
+}
+
+// Paths returns the project's resolved paths.
+func (p *Project) Paths() Paths { return p.paths }
+
+// Config returns the loaded config.
+func (p *Project) Config() Config { return p.cfg }
+
+// CollectionNames returns the configured collections, sorted.
+func (p *Project) CollectionNames() []string {
+	names := make([]string, 0, len(p.collections))
+	for name := range p.collections {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Collection returns a loaded collection as written in its plan.
+func (p *Project) Collection(name string) (Collection, bool) {
+	coll, ok := p.collections[name]
+	return coll, ok
+}
+
+// Rows returns a collection's rows with the overrides file applied, the
+// values render uses.
+func (p *Project) Rows(collection string) ([]Row, error) {
+	if _, ok := p.collections[collection]; !ok {
+		return nil, fmt.Errorf("collection %q not found in configuration", collection)
+	}
+	effective := project.WithRowOverrides(map[string]Collection{collection: p.collections[collection]})
+	return effective[collection].Rows, nil
+}
+
+// TimelineSlot is one position in playback order.
+type TimelineSlot struct {
+	Slot       int
+	Collection string
+	Index      int
+	Segment    string // Rendered segment path
+}
+
+// Timeline resolves the timeline sequence into playback order. Skipped rows
+// are left out; inline files appear with their normalized segment path.
+func (p *Project) Timeline() ([]TimelineSlot, error) {
+	segments, err := render.ResolveTimelineSegments(p.paths, p.cfg, p.collections)
+	if err != nil {
+		return nil, fmt.Errorf("resolve timeline: %w", err)
+	}
+	slots := make([]TimelineSlot, len(segments))
+	for i, seg := range segments {
+		slots[i] = TimelineSlot{Slot: i + 1, Collection: seg.CollectionName, Index: seg.Index, Segment: seg.Path}
+	}
+	return slots, nil
+}
+
+// Selection narrows Fetch and Render to one collection, and optionally to
+// some of its rows. The zero value selects the whole project.
+type Selection struct {
+	Collection string
+	Indexes    []int
+}
+
+func (s Selection) all() bool {
+	return s.Collection == "" && len(s.Indexes) == 0
+}
+
+// selected returns the collections and rows in sel.
+func (p *Project) selected(sel Selection) (map[string]Collection, error) {
+	if sel.Collection == "" {
+		if len(sel.Indexes) > 0 {
+			return nil, fmt.Errorf("row indexes need a collection")
+		}
+		return p.collections, nil
+	}
+	coll, ok := p.collections[sel.Collection]
+	if !ok {
+		return nil, fmt.Errorf("collection %q not found in configuration", sel.Collection)
+	}
+	if len(sel.Indexes) > 0 {
+		rows := make([]Row, 0, len(sel.Indexes))
+		for _, index := range sel.Indexes {
+			i := slices.IndexFunc(coll.Rows, func(r Row) bool { return r.Index == index })
+			if i < 0 {
+				return nil, fmt.Errorf("collection %q has no row %d", sel.Collection, index)
+			}
+			rows = append(rows, coll.Rows[i])
+		}
+		coll.Rows = rows
+	}
+	return map[string]Collection{sel.Collection: coll}, nil
+}
@@ -0,0 +1,118 @@
+package powerhour
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testConfig = `collections:
+  songs:
+    plan: songs.csv
+timeline:
+  sequence:
+    - collection: songs
+`
+
+const testPlan = `title,artist,start_time,duration,link,skip
+One,A,0:30,60,https://www.youtube.com/watch?v=aaaaaaaaaaa,
+Two,B,1:00,60,https://www.youtube.com/watch?v=bbbbbbbbbbb,yes
+Three,C,0:45,60,https://www.youtube.com/watch?v=ccccccccccc,
+`
+
+func openTestProject(t *testing.T) *Project {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "powerhour.yaml"), []byte(testConfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "songs.csv"), []byte(testPlan), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// The default config also declares an interstitials collection.
+	if err := os.WriteFile(filepath.Join(dir, "interstitials.yaml"), []byte("[]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	return p
+}
+
+func TestOpenLoadsPlansAndTimeline(t *testing.T) {
+	p := openTestProject(t)
+
+	if names := p.CollectionNames(); len(names) != 2 || names[1] != "songs" {
+		t.Fatalf("CollectionNames = %v", names)
+	}
+	rows, err := p.Rows("songs")
+	if err != nil || len(rows) != 3 || rows[2].CustomFields["title"] != "Three" {
+		t.Fatalf("Rows = %+v, %v", rows, err)
+	}
+	if _, err := p.Rows("intros"); err == nil {
+		t.Error("Rows(intros) succeeded, want error")
+	}
+
+	slots, err := p.Timeline()
+	if err != nil {
+		t.Fatalf("Timeline: %v", err)
+	}
+	var indexes []int
+	for _, s := range slots {
+		indexes = append(indexes, s.Index)
+	}
+	if len(indexes) != 2 || indexes[0] != 1 || indexes[1] != 3 {
+		t.Errorf("timeline rows = %v, want [1 3] (row 2 is skipped)", indexes)
+	}
+
+	planRows, err := LoadPlan(filepath.Join(p.Paths().Root, "songs.csv"))
+	if err != nil || len(planRows) != 3 {
+		t.Errorf("LoadPlan = %d rows, %v", len(planRows), err)
+	}
+}
+
+func TestSelected(t *testing.T) {
+	p := openTestProject(t)
+	tests := []struct {
+		sel     Selection
+		rows    int
+		wantErr bool
+	}{
+		{sel: Selection{}, rows: 3},
+		{sel: Selection{Collection: "songs", Indexes: []int{3, 1}}, rows: 2},
+		{sel: Selection{Indexes: []int{1}}, wantErr: true},
+		{sel: Selection{Collection: "intros"}, wantErr: true},
+		{sel: Selection{Collection: "songs", Indexes: []int{9}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := p.selected(tt.sel)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("selected(%+v) succeeded, want error", tt.sel)
+			}
+			continue
+		}
+		if err != nil || len(got["songs"].Rows) != tt.rows {
+			t.Errorf("selected(%+v) = %d rows, %v; want %d", tt.sel, len(got["songs"].Rows), err, tt.rows)
+		}
+	}
+}
+
+func TestRenderReportsUncachedSources(t *testing.T) {
+	p := openTestProject(t)
+	results, err := p.Render(t.Context(), Selection{Collection: "songs", Indexes: []int{1, 3}}, RenderOptions{})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	for _, res := range results {
+		if !errors.Is(res.Err, ErrSourceNotCached) {
+			t.Errorf("result %q err = %v, want ErrSourceNotCached", res.Title, res.Err)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package powerhour
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"powerhour/internal/cache"
+	"powerhour/internal/project"
+	"powerhour/internal/render"
+	"powerhour/internal/render/state"
+)
+
+// ErrSourceNotCached marks render results whose source hasn't been fetched.
+var ErrSourceNotCached = render.ErrSourceMissing
+
+// RenderOptions tune Render.
+type RenderOptions struct {
+	// Force re-renders segments whose inputs haven't changed.
+	Force bool
+	// Concurrency is the number of ffmpeg processes; 0 uses the number of
+	// CPUs.
+	Concurrency int
+	// Reporter, when set, is told as each segment starts, progresses and
+	// finishes.
+	Reporter ProgressReporter
+	// Output, when set, receives render status lines.
+	Output io.Writer
+}
+
+// Render renders the selected rows to segment files, skipping segments
+// whose inputs are unchanged since the last render, and records the result
+// in the project's render state. Rows whose source isn't cached get a result
+// wrapping ErrSourceNotCached; fetch them first. Inline timeline files are
+// left to the CLI's render and concat.
+func (p *Project) Render(ctx context.Context, sel Selection, opts RenderOptions) ([]RenderResult, error) {
+	collections, err := p.selected(sel)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkOutputCollisions(); err != nil {
+		return nil, err
+	}
+	if err := p.paths.EnsureMetaDirs(); err != nil {
+		return nil, err
+	}
+	if err := p.paths.EnsureCollectionDirs(p.cfg); err != nil {
+		return nil, err
+	}
+	idx, err := cache.Load(p.paths)
+	if err != nil {
+		return nil, err
+	}
+	clips, err := p.resolver.BuildCollectionClips(collections)
+	if err != nil {
+		return nil, err
+	}
+	if len(clips) == 0 {
+		return nil, fmt.Errorf("no clips to render")
+	}
+	project.ApplySequenceEntryOverrides(p.cfg, clips)
+
+	rs, err := state.Load(p.paths.RenderStateFile)
+	if err != nil {
+		return nil, fmt.Errorf("load render state: %w", err)
+	}
+	// Without a resolvable timeline, segments are tagged without a track.
+	tracks, _ := render.TimelineTracks(p.paths, p.cfg, p.collections)
+	var results []RenderResult
+	var segments []Segment
+	for _, cc := range clips {
+		seg, err := render.BuildCollectionSegment(p.paths, p.cfg, idx, cc)
+		seg.Tags = render.NewSegmentTags(cc.Clip, filepath.Base(p.paths.Root), tracks[seg.OutputPath])
+		if err != nil {
+			results = append(results, RenderResult{
+				Index:      cc.Clip.Sequence,
+				ClipType:   cc.Clip.ClipType,
+				TypeIndex:  cc.Clip.TypeIndex,
+				Title:      clipTitle(cc.Clip),
+				OutputPath: seg.OutputPath,
+				Err:        err,
+			})
+			continue
+		}
+		seg.StoredHash = rs.StoredHash(seg, p.cfg)
+		segments = append(segments, seg)
+	}
+
+	filenameTemplate := p.cfg.SegmentFilenameTemplate()
+	var toRender []Segment
+	for _, action := range state.DetectChanges(rs, segments, p.cfg, filenameTemplate, opts.Force) {
+		seg := action.Segment
+		if action.Action != state.ActionSkip {
+			toRender = append(toRender, seg)
+			continue
+		}
+		results = append(results, RenderResult{
+			Index:      seg.Clip.Sequence,
+			ClipType:   seg.Clip.ClipType,
+			TypeIndex:  seg.Clip.TypeIndex,
+			Title:      clipTitle(seg.Clip),
+			OutputPath: seg.OutputPath,
+			Skipped:    true,
+			Reason:     action.Reason,
+		})
+	}
+
+	if len(toRender) > 0 {
+		svc, err := render.NewService(ctx, p.paths, p.cfg, nil)
+		if err != nil {
+			return nil, err
+		}
+		svc.SetWriters(opts.Output, nil)
+		concurrency := opts.Concurrency
+		if concurrency <= 0 {
+			concurrency = runtime.NumCPU()
+		}
+		started := time.Now()
+		// Each rendered segment is saved as it finishes, so an interrupted
+		// render resumes where it stopped.
+		checkpoint := state.NewCheckpoint(rs, p.paths.RenderStateFile, p.cfg, toRender)
+		rendered := svc.Render(ctx, toRender, render.Options{
+			Concurrency: concurrency,
+			Force:       opts.Force,
+			Reporter:    checkpoint.Reporter(opts.Reporter),
+		})
+		state.RecordRun(rs, state.SummarizeRun(p.cfg, started, concurrency, rendered))
+		results = append(results, rendered...)
+	}
+
+	rs.SetGlobalConfig(p.cfg)
+	// A partial render only knows its own segments, so only a full one
+	// forgets the rest.
+	if sel.all() {
+		current := make(map[string]bool, len(segments))
+		for _, seg := range segments {
+			current[seg.OutputPath] = true
+		}
+		state.Prune(rs, current)
+	}
+	if err := rs.Save(p.paths.RenderStateFile); err != nil {
+		return results, fmt.Errorf("save render state: %w", err)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Index < results[j].Index })
+	return results, ctx.Err()
+}
+
+// checkOutputCollisions fails with a *render.CollisionError when two rows in
+// any collection would render to the same segment file.
+func (p *Project) checkOutputCollisions() error {
+	clips, err := p.resolver.BuildCollectionClips(p.collections)
+	if err != nil {
+		return err
+	}
+	project.ApplySequenceEntryOverrides(p.cfg, clips)
+	return render.CheckOutputCollisions(p.cfg, p.paths.SegmentsDir, clips)
+}
+
+func clipTitle(clip project.Clip) string {
+	if title := strings.TrimSpace(clip.Row.Title); title != "" {
+		return title
+	}
+	if name := strings.TrimSpace(clip.Row.Name); name != "" {
+		return name
+	}
+	return string(clip.ClipType)
+}